@@ -77,6 +77,7 @@ var httpTestcases = map[string]testutils.TestCase{
 	"http/cl-body-without-header.yaml":              &httpCLBodyWithoutHeader{},
 	"http/cl-body-with-header.yaml":                 &httpCLBodyWithHeader{},
 	"http/save-extractor-values-to-file.yaml":       &httpSaveExtractorValuesToFile{},
+	"http/request-dependencies.yaml":                &httpRequestDependencies{},
 }
 
 type httpInteractshRequest struct{}
@@ -1403,3 +1404,37 @@ func (h *httpSaveExtractorValuesToFile) Execute(filePath string) error {
 	}
 	return expectResultsCount(results, 1)
 }
+
+type httpRequestDependencies struct{}
+
+// Execute executes a test case and returns an error if occurred
+func (h *httpRequestDependencies) Execute(filePath string) error {
+	router := httprouter.New()
+	router.POST("/login", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		fmt.Fprintf(w, "token=s3cr3t")
+	})
+	router.GET("/profile", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		if r.URL.Query().Get("token") == "s3cr3t" {
+			fmt.Fprintf(w, "user=admin")
+		}
+	})
+	router.GET("/settings", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		if r.URL.Query().Get("token") == "s3cr3t" {
+			fmt.Fprintf(w, "role=owner")
+		}
+	})
+	router.GET("/admin", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		query := r.URL.Query()
+		if query.Get("token") == "s3cr3t" && query.Get("user") == "admin" && query.Get("role") == "owner" {
+			fmt.Fprintf(w, "request dependencies resolved")
+		}
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	results, err := testutils.RunNucleiTemplateAndGetResults(filePath, ts.URL, debug)
+	if err != nil {
+		return err
+	}
+	return expectResultsCount(results, 1)
+}
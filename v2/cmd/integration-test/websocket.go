@@ -10,10 +10,11 @@ import (
 )
 
 var websocketTestCases = map[string]testutils.TestCase{
-	"websocket/basic.yaml":    &websocketBasic{},
-	"websocket/cswsh.yaml":    &websocketCswsh{},
-	"websocket/no-cswsh.yaml": &websocketNoCswsh{},
-	"websocket/path.yaml":     &websocketWithPath{},
+	"websocket/basic.yaml":       &websocketBasic{},
+	"websocket/cswsh.yaml":       &websocketCswsh{},
+	"websocket/no-cswsh.yaml":    &websocketNoCswsh{},
+	"websocket/path.yaml":        &websocketWithPath{},
+	"websocket/read-frames.yaml": &websocketReadFrames{},
 }
 
 type websocketBasic struct{}
@@ -105,3 +106,30 @@ func (h *websocketWithPath) Execute(filePath string) error {
 
 	return expectResultsCount(results, 0)
 }
+
+type websocketReadFrames struct{}
+
+// Execute executes a test case and returns an error if occurred
+func (h *websocketReadFrames) Execute(filePath string) error {
+	connHandler := func(conn net.Conn) {
+		msg, op, _ := wsutil.ReadClientData(conn)
+		if string(msg) != "hello" {
+			return
+		}
+		for _, frame := range []string{"first", "second", "third"} {
+			_ = wsutil.WriteServerMessage(conn, op, []byte(frame))
+		}
+	}
+	originValidate := func(origin string) bool {
+		return true
+	}
+	ts := testutils.NewWebsocketServer("", connHandler, originValidate)
+	defer ts.Close()
+
+	results, err := testutils.RunNucleiTemplateAndGetResults(filePath, strings.ReplaceAll(ts.URL, "http", "ws"), debug)
+	if err != nil {
+		return err
+	}
+
+	return expectResultsCount(results, 1)
+}
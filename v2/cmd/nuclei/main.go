@@ -80,7 +80,7 @@ func main() {
 	}
 
 	// Setup graceful exits
-	resumeFileName := types.DefaultResumeFilePath()
+	resumeFileName := nucleiRunner.ResumeFile()
 	c := make(chan os.Signal, 1)
 	defer close(c)
 	signal.Notify(c, os.Interrupt)
@@ -125,7 +125,11 @@ on extensive configurability, massive extensibility and ease of use.`)
 	flagSet.CreateGroup("input", "Target",
 		flagSet.StringSliceVarP(&options.Targets, "target", "u", nil, "target URLs/hosts to scan", goflags.StringSliceOptions),
 		flagSet.StringVarP(&options.TargetsFilePath, "list", "l", "", "path to file containing a list of target URLs/hosts to scan (one per line)"),
+		flagSet.StringVarP(&options.OpenAPISpecFile, "openapi", "oa", "", "path to OpenAPI 3.x spec file to generate target requests from (yaml/json)"),
 		flagSet.StringVar(&options.Resume, "resume", "", "resume scan using resume.cfg (clustering will be disabled)"),
+		flagSet.DurationVarP(&options.ResumeInterval, "resume-interval", "ri", time.Minute, "interval between checkpoints of the scan progression to the resume file (0 to disable)"),
+		flagSet.StringVarP(&options.Incremental, "incremental", "inc", "", "endpoint inventory of the previous crawl to scan only the new and changed endpoints (updated after the scan)"),
+		flagSet.StringSliceVarP(&options.IncrementalFields, "incremental-fields", "incf", nil, "endpoint fields compared to detect changed endpoints (status,hash,headers) - (default status,hash)", goflags.CommaSeparatedStringSliceOptions),
 		flagSet.BoolVarP(&options.ScanAllIPs, "scan-all-ips", "sa", false, "scan all the IP's associated with dns record"),
 		flagSet.StringSliceVarP(&options.IPVersion, "ip-version", "iv", nil, "IP version to scan of hostname (4,6) - (default 4)", goflags.CommaSeparatedStringSliceOptions),
 	)
@@ -139,6 +143,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringSliceVarP(&options.Workflows, "workflows", "w", nil, "list of workflow or workflow directory to run (comma-separated, file)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.StringSliceVarP(&options.WorkflowURLs, "workflow-url", "wu", nil, "list of workflow urls to run (comma-separated, file)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.BoolVar(&options.Validate, "validate", false, "validate the passed templates to nuclei"),
+		flagSet.StringVarP(&options.ResponseFixture, "response-fixture", "rfx", "", "run the http templates against a raw http response file instead of targets, showing the matchers fired and values extracted"),
 		flagSet.BoolVarP(&options.NoStrictSyntax, "no-strict-syntax", "nss", false, "disable strict syntax check on templates"),
 		flagSet.BoolVarP(&options.TemplateDisplay, "template-display", "td", false, "displays the templates content"),
 		flagSet.BoolVar(&options.TemplateList, "tl", false, "list all available templates"),
@@ -170,10 +175,24 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.NoColor, "no-color", "nc", false, "disable output content coloring (ANSI escape codes)"),
 		flagSet.BoolVarP(&options.JSONL, "jsonl", "j", false, "write output in JSONL(ines) format"),
 		flagSet.BoolVarP(&options.JSONRequests, "include-rr", "irr", false, "include request/response pairs in the JSONL output (for findings only)"),
+		flagSet.StringVarP(&options.OutputTemplate, "output-template", "otp", "", "go template to format each finding on a single line (eg. '{{.Timestamp}} {{.TemplateID}} {{.Severity}} {{.Matched}}')"),
 		flagSet.BoolVarP(&options.NoMeta, "no-meta", "nm", false, "disable printing result metadata in cli output"),
 		flagSet.BoolVarP(&options.Timestamp, "timestamp", "ts", false, "enables printing timestamp in cli output"),
 		flagSet.StringVarP(&options.ReportingDB, "report-db", "rdb", "", "nuclei reporting database (always use this to persist report data)"),
 		flagSet.BoolVarP(&options.MatcherStatus, "matcher-status", "ms", false, "display match failure status"),
+		flagSet.BoolVarP(&options.DedupeFindings, "dedupe-findings", "dfi", false, "dedupe identical findings across targets and display their count"),
+		flagSet.BoolVarP(&options.DedupeIncludeHost, "dedupe-include-host", "dih", false, "include host in the key used to dedupe identical findings"),
+		flagSet.StringVarP(&options.DedupeFullOutput, "dedupe-full-output", "dfo", "", "file to write all findings in JSONL(ines) format when deduping findings"),
+		flagSet.StringSliceVarP(&options.DiffResults, "diff", "dif", nil, "output the findings added and removed between two result files in JSON or JSONL(ines) format (previous,current)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringVarP(&options.WebhookURL, "webhook-url", "whu", "", "webhook url to post findings to as they are found"),
+		flagSet.StringSliceVarP(&options.WebhookHeaders, "webhook-header", "whh", nil, "custom header to include in webhook requests in header:value format (cli, file)", goflags.FileStringSliceOptions),
+		flagSet.StringVarP(&options.WebhookTemplate, "webhook-template", "wht", "", "go template to format the findings posted to the webhook (eg. '{\"text\":{{json .Matched}}}')"),
+		flagSet.StringVarP(&options.SignKey, "sign-key", "sk", "", "hmac secret or ed25519 key file to write a detached signature of the output file (<output>.sig)"),
+		flagSet.StringVarP(&options.VerifyOutput, "verify-output", "vo", "", "output file to verify against its detached signature with the sign key"),
+		flagSet.StringVarP(&options.Inventory, "inventory", "inv", "", "file to write the inventory of the technologies, headers and ports detected per host in JSONL(ines) format"),
+		flagSet.StringSliceVarP(&options.InventoryCategories, "inventory-category", "invc", nil, "categories of findings to include in the inventory (tech,cms,favicon,header,port)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.IntVarP(&options.OutputBatchSize, "output-batch-size", "obs", 0, "max number of findings sent to the webhook per flush interval, buffering up to 10 batches and dropping the overflow (webhook only, 0 to disable)"),
+		flagSet.DurationVarP(&options.OutputFlushInterval, "output-flush-interval", "ofi", 0, "interval to send the batches of findings to the webhook at when it is throttled (webhook only, 0 = 1s)"),
 		flagSet.StringVarP(&options.MarkdownExportDirectory, "markdown-export", "me", "", "directory to export results in markdown format"),
 		flagSet.StringVarP(&options.SarifExport, "sarif-export", "se", "", "file to export results in SARIF format"),
 		flagSet.StringVarP(&options.JSONExport, "json-export", "je", "", "file to export results in JSON format"),
@@ -188,11 +207,13 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.DisableRedirects, "disable-redirects", "dr", false, "disable redirects for http templates"),
 		flagSet.StringVarP(&options.ReportingConfig, "report-config", "rc", "", "nuclei reporting module configuration file"), // TODO merge into the config file or rename to issue-tracking
 		flagSet.StringSliceVarP(&options.CustomHeaders, "header", "H", nil, "custom header/cookie to include in all http request in header:value format (cli, file)", goflags.FileStringSliceOptions),
+		flagSet.StringSliceVarP(&options.UserAgents, "user-agent-list", "ual", nil, "list of user-agents to rotate per http request and headless page (cli, file)", goflags.FileStringSliceOptions),
+		flagSet.BoolVarP(&options.RotateUserAgent, "rotate-user-agent", "rua", false, "rotate the user-agent per headless page from the built-in or given user-agent list"),
 		flagSet.RuntimeMapVarP(&options.Vars, "var", "V", nil, "custom vars in key=value format"),
 		flagSet.StringVarP(&options.ResolversFile, "resolvers", "r", "", "file containing resolver list for nuclei"),
 		flagSet.BoolVarP(&options.SystemResolvers, "system-resolvers", "sr", false, "use system DNS resolving as error fallback"),
 		flagSet.BoolVarP(&options.DisableClustering, "disable-clustering", "dc", false, "disable clustering of requests"),
-		flagSet.BoolVar(&options.OfflineHTTP, "passive", false, "enable passive HTTP response processing mode"),
+		flagSet.BoolVar(&options.OfflineHTTP, "passive", false, "enable passive HTTP response processing mode (raw responses and har files)"),
 		flagSet.BoolVarP(&options.ForceAttemptHTTP2, "force-http2", "fh2", false, "force http2 connection on requests"),
 		flagSet.BoolVarP(&options.EnvironmentVariables, "env-vars", "ev", false, "enable environment variables to be used in template"),
 		flagSet.StringVarP(&options.ClientCertFile, "client-cert", "cc", "", "client certificate file (PEM-encoded) used for authenticating against scanned hosts"),
@@ -201,6 +222,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.ShowMatchLine, "show-match-line", "sml", false, "show match lines for file templates, works with extractors only"),
 		flagSet.BoolVar(&options.ZTLS, "ztls", false, "use ztls library with autofallback to standard one for tls13"),
 		flagSet.StringVar(&options.SNI, "sni", "", "tls sni hostname to use (default: input domain name)"),
+		flagSet.StringVar(&options.JA3, "ja3", "", "ja3 fingerprint to use for the tls client hello of http, network and ssl requests"),
 		flagSet.BoolVar(&options.Sandbox, "sandbox", false, "sandbox nuclei for safe templates execution"),
 		flagSet.StringVarP(&options.Interface, "interface", "i", "", "network interface to use for network scan"),
 		flagSet.StringVarP(&options.AttackType, "attack-type", "at", "", "type of payload combinations to perform (batteringram,pitchfork,clusterbomb)"),
@@ -208,6 +230,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringVar(&options.CustomConfigDir, "config-directory", "", "override the default config path ($home/.config)"),
 		flagSet.IntVarP(&options.ResponseReadSize, "response-size-read", "rsr", 10*1024*1024, "max response size to read in bytes"),
 		flagSet.IntVarP(&options.ResponseSaveSize, "response-size-save", "rss", 1*1024*1024, "max response size to read in bytes"),
+		flagSet.IntVarP(&options.MaxBodySize, "max-body-size", "mbs", 10*1024*1024, "max response body size kept for matching after decompression in bytes (0 = unlimited)"),
+		flagSet.BoolVarP(&options.TranscodeResponse, "transcode-response", "tr", false, "transcode response bodies in legacy charsets (shift_jis, iso-8859-*, etc) to utf-8 before matching"),
 		flagSet.CallbackVar(resetCallback, "reset", "reset removes all nuclei configuration and data files (including nuclei-templates)"),
 	)
 
@@ -218,6 +242,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.IntVar(&options.InteractionsEviction, "interactions-eviction", 60, "number of seconds to wait before evicting requests from cache"),
 		flagSet.IntVar(&options.InteractionsPollDuration, "interactions-poll-duration", 5, "number of seconds to wait before each interaction poll request"),
 		flagSet.IntVar(&options.InteractionsCoolDownPeriod, "interactions-cooldown-period", 5, "extra time for interaction polling before exiting"),
+		flagSet.BoolVar(&options.InteractionsOutput, "interactions-output", false, "include all the interactions correlated with a matched request in the json output"),
 		flagSet.BoolVarP(&options.NoInteractsh, "no-interactsh", "ni", false, "disable interactsh server for OAST testing, exclude OAST based templates"),
 	)
 
@@ -242,14 +267,24 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.IntVarP(&options.TemplateThreads, "concurrency", "c", 25, "maximum number of templates to be executed in parallel"),
 		flagSet.IntVarP(&options.HeadlessBulkSize, "headless-bulk-size", "hbs", 10, "maximum number of headless hosts to be analyzed in parallel per template"),
 		flagSet.IntVarP(&options.HeadlessTemplateThreads, "headless-concurrency", "headc", 10, "maximum number of headless templates to be executed in parallel"),
+		flagSet.StringSliceVarP(&options.ProtocolConcurrency, "protocol-concurrency", "pcc", nil, "maximum number of parallel executions per protocol in protocol=limit format (0 = unlimited, default headless=5,http=50,dns=200)", goflags.CommaSeparatedStringSliceOptions),
 	)
 	flagSet.CreateGroup("optimization", "Optimizations",
 		flagSet.IntVar(&options.Timeout, "timeout", 10, "time to wait in seconds before timeout"),
+		flagSet.DurationVarP(&options.DialTimeout, "dial-timeout", "dto", 0, "time to wait for a connection to be established (0 = timeout)"),
+		flagSet.DurationVarP(&options.TLSHandshakeTimeout, "tls-handshake-timeout", "tht", 0, "time to wait for a tls handshake to complete (0 = timeout)"),
+		flagSet.DurationVarP(&options.ResponseReadTimeout, "response-read-timeout", "rrt", 0, "time to wait for a response after sending a request (0 = timeout)"),
 		flagSet.IntVar(&options.Retries, "retries", 1, "number of times to retry a failed request"),
 		flagSet.BoolVarP(&options.LeaveDefaultPorts, "leave-default-ports", "ldp", false, "leave default HTTP/HTTPS ports (eg. host:80,host:443)"),
 		flagSet.IntVarP(&options.MaxHostError, "max-host-error", "mhe", 30, "max errors for a host before skipping from scan"),
 		flagSet.StringSliceVarP(&options.TrackError, "track-error", "te", nil, "adds given error to max-host-error watchlist (standard, file)", goflags.FileStringSliceOptions),
 		flagSet.BoolVarP(&options.NoHostErrors, "no-mhe", "nmhe", false, "disable skipping host from scan based on errors"),
+		flagSet.IntVarP(&options.MaxRequestsPerTemplate, "max-requests", "mreq", 0, "max number of requests a template can send to a single host (0 = unlimited)"),
+		flagSet.IntVarP(&options.RetryBackoff, "retry-backoff", "rb", 0, "number of times to retry http requests throttled with 429/503 using exponential backoff (0 = disabled)"),
+		flagSet.DurationVarP(&options.RetryBackoffMax, "retry-backoff-max", "rbm", 30*time.Second, "max time to wait before retrying a throttled http request, including retry-after"),
+		flagSet.BoolVarP(&options.RespectRateHeaders, "respect-rate-headers", "rrh", false, "delay the requests to a host as advertised by its retry-after and x-ratelimit-reset headers (capped by retry-backoff-max)"),
+		flagSet.IntVarP(&options.BreakerThreshold, "breaker-threshold", "cbt", 0, "consecutive http failures (errors, 429/503) before temporarily skipping a host (0 = disabled)"),
+		flagSet.DurationVarP(&options.BreakerCooldown, "breaker-cooldown", "cbc", time.Minute, "time to skip a host for once the breaker threshold is reached"),
 		flagSet.BoolVar(&options.Project, "project", false, "use a project folder to avoid sending same request multiple times"),
 		flagSet.StringVar(&options.ProjectPath, "project-path", os.TempDir(), "set a specific project path"),
 		flagSet.BoolVarP(&options.StopAtFirstMatch, "stop-at-first-match", "spm", false, "stop processing HTTP requests after the first match (may break template/workflow logic)"),
@@ -259,6 +294,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 			scanstrategy.HostSpray.String():     goflags.EnumVariable(1),
 			scanstrategy.TemplateSpray.String(): goflags.EnumVariable(2),
 		}),
+		flagSet.IntVar(&options.Seed, "seed", 0, "seed making the order of templates and targets and the random dsl helper values reproducible (0 = disabled)"),
 		flagSet.DurationVarP(&options.InputReadTimeout, "input-read-timeout", "irt", time.Duration(3*time.Minute), "timeout on input read"),
 		flagSet.BoolVarP(&options.DisableHTTPProbe, "no-httpx", "nh", false, "disable httpx probing for non-url input"),
 		flagSet.BoolVar(&options.DisableStdin, "no-stdin", false, "disable stdin processing"),
@@ -267,6 +303,12 @@ on extensive configurability, massive extensibility and ease of use.`)
 	flagSet.CreateGroup("headless", "Headless",
 		flagSet.BoolVar(&options.Headless, "headless", false, "enable templates that require headless browser support (root user on Linux will disable sandbox)"),
 		flagSet.IntVar(&options.PageTimeout, "page-timeout", 20, "seconds to wait for each page in headless mode"),
+		flagSet.IntVarP(&options.HeadlessMaxPages, "headless-max-pages", "hmp", 0, "maximum number of pages open concurrently in the headless browser (0 = unlimited)"),
+		flagSet.IntVarP(&options.HeadlessRecyclePages, "headless-recycle-pages", "hrp", 0, "relaunch the headless browser after given number of pages to reclaim memory (0 = disabled)"),
+		flagSet.IntVarP(&options.HeadlessRequestBodySize, "headless-request-body-size", "hrbs", 10*1024, "maximum size of request bodies recorded from headless pages (0 = unlimited)"),
+		flagSet.StringVarP(&options.HeadlessRecord, "headless-record", "hrec", "", "directory to record headless sessions (network events, history, console, screenshots) to"),
+		flagSet.StringVarP(&options.HeadlessReplay, "headless-replay", "hrep", "", "directory to replay recorded headless sessions from without launching a browser"),
+		flagSet.BoolVarP(&options.HeadlessClustering, "headless-clustering", "hcl", false, "share a single page between the headless templates with identical steps which only load or observe it"),
 		flagSet.BoolVarP(&options.ShowBrowser, "show-browser", "sb", false, "show the browser on the screen when running templates with headless mode"),
 		flagSet.BoolVarP(&options.UseInstalledChrome, "system-chrome", "sc", false, "use local installed Chrome browser instead of nuclei installed"),
 		flagSet.BoolVarP(&options.ShowActions, "list-headless-action", "lha", false, "list available headless actions"),
@@ -278,6 +320,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.DebugResponse, "debug-resp", "dresp", false, "show all received responses"),
 		flagSet.StringSliceVarP(&options.Proxy, "proxy", "p", nil, "list of http/socks5 proxy to use (comma separated or file input)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.BoolVarP(&options.ProxyInternal, "proxy-internal", "pi", false, "proxy all internal requests"),
+		flagSet.BoolVarP(&options.ProxyPool, "proxy-pool", "ppool", false, "distribute requests across all healthy proxies with periodic health checks"),
+		flagSet.DurationVarP(&options.ProxyCheckInterval, "proxy-check-interval", "pci", 30*time.Second, "interval between health checks of the proxy pool"),
 		flagSet.BoolVarP(&options.ListDslSignatures, "list-dsl-function", "ldf", false, "list all supported DSL function signatures"),
 		flagSet.StringVarP(&options.TraceLogFile, "trace-log", "tlog", "", "file to write sent requests trace log"),
 		flagSet.StringVarP(&options.ErrorLogFile, "error-log", "elog", "", "file to write sent requests error log"),
@@ -305,6 +349,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.IntVarP(&options.StatsInterval, "stats-interval", "si", 5, "number of seconds to wait between showing a statistics update"),
 		flagSet.BoolVarP(&options.Metrics, "metrics", "m", false, "expose nuclei metrics on a port"),
 		flagSet.IntVarP(&options.MetricsPort, "metrics-port", "mp", 9092, "port to expose nuclei metrics on"),
+		flagSet.BoolVarP(&options.Prometheus, "prometheus", "pm", false, "expose nuclei metrics in prometheus format"),
+		flagSet.StringVarP(&options.PrometheusAddress, "prometheus-address", "pma", "127.0.0.1:9093", "address to expose prometheus metrics on"),
 	)
 
 	flagSet.CreateGroup("cloud", "Cloud",
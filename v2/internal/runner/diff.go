@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// DiffResults writes the findings added and removed between the previous
+// and current result files with the configured output format.
+func DiffResults(options *types.Options) error {
+	previous, err := output.ReadResults(options.DiffResults[0])
+	if err != nil {
+		return errors.Wrap(err, "could not read previous results")
+	}
+	current, err := output.ReadResults(options.DiffResults[1])
+	if err != nil {
+		return errors.Wrap(err, "could not read current results")
+	}
+	diff := output.DiffResults(previous, current)
+
+	writer, err := output.NewStandardWriter(options)
+	if err != nil {
+		return errors.Wrap(err, "could not create output file")
+	}
+	defer writer.Close()
+
+	for _, results := range [][]*output.ResultEvent{diff.Added, diff.Removed} {
+		for _, result := range results {
+			if err := writer.Write(result); err != nil {
+				return errors.Wrap(err, "could not write diff result")
+			}
+		}
+	}
+	gologger.Info().Msgf("Found %d added and %d removed findings", len(diff.Added), len(diff.Removed))
+	return nil
+}
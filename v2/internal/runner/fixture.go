@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/catalog/loader"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+)
+
+// runResponseFixture runs the matchers and extractors of the loaded http
+// templates against the raw http response of the fixture file instead of
+// the targets, writing the results and showing the matchers which fired and
+// the values extracted for each template.
+func (r *Runner) runResponseFixture(store *loader.Store) error {
+	fixture, err := os.ReadFile(r.options.ResponseFixture)
+	if err != nil {
+		return errors.Wrap(err, "could not read response fixture")
+	}
+
+	var executed int
+	for _, template := range store.Templates() {
+		if len(template.RequestsHTTP) == 0 {
+			continue
+		}
+		executed++
+
+		matchers := make(map[string]struct{})
+		var extracted []string
+		for _, request := range template.RequestsHTTP {
+			err := request.ExecuteWithFixture(r.options.ResponseFixture, fixture, func(event *output.InternalWrappedEvent) {
+				if event.OperatorsResult == nil {
+					return
+				}
+				for name := range event.OperatorsResult.Matches {
+					matchers[name] = struct{}{}
+				}
+				extracted = append(extracted, event.OperatorsResult.OutputExtracts...)
+				for _, result := range event.Results {
+					if err := r.output.Write(result); err != nil {
+						gologger.Warning().Msgf("[%s] Could not write result: %s\n", template.ID, err)
+					}
+				}
+			})
+			if err != nil {
+				return errors.Wrapf(err, "could not run %s against response fixture", template.ID)
+			}
+		}
+
+		if len(matchers) == 0 && len(extracted) == 0 {
+			gologger.Info().Msgf("[%s] No matchers fired and no values extracted", template.ID)
+			continue
+		}
+		names := make([]string, 0, len(matchers))
+		for name := range matchers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		gologger.Info().Msgf("[%s] Matchers fired: [%s] Extracted values: [%s]", template.ID, strings.Join(names, ","), strings.Join(extracted, ","))
+	}
+	if executed == 0 {
+		return errors.New("no http templates to run against the response fixture")
+	}
+	return nil
+}
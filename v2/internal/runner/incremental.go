@@ -0,0 +1,311 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corpix/uarand"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/hmap/store/hybrid"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/remeh/sizedwaitgroup"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// Fields of the endpoints compared to detect the endpoints changed since the previous crawl
+const (
+	// incrementalStatus is the status code of the endpoint
+	incrementalStatus = "status"
+	// incrementalHash is the sha256 hash of the body of the endpoint
+	incrementalHash = "hash"
+	// incrementalHeaders are the values of the stable headers of the endpoint
+	incrementalHeaders = "headers"
+)
+
+// maxEndpointBodySize is the maximum size of the hashed body of the endpoints
+// when the response read size isn't specified
+const maxEndpointBodySize = 10 * 1024 * 1024
+
+// incrementalFields are all the fields compared by incremental scans
+var incrementalFields = []string{incrementalStatus, incrementalHash, incrementalHeaders}
+
+// defaultIncrementalFields are the fields compared when not specified
+var defaultIncrementalFields = []string{incrementalStatus, incrementalHash}
+
+// endpointHeaders are the headers compared by the headers field, leaving out
+// the headers such as Date or Set-Cookie which change on every response
+var endpointHeaders = []string{"Content-Type", "Location", "Server", "X-Powered-By", "ETag", "Last-Modified", "Content-Security-Policy"}
+
+// endpointRecord is the state of an endpoint recorded in the endpoint inventory
+type endpointRecord struct {
+	// URL is the url of the endpoint
+	URL string `json:"url"`
+	// Status is the status code of the response of the endpoint
+	Status int `json:"status"`
+	// Hash is the sha256 hash of the body of the response of the endpoint
+	Hash string `json:"hash"`
+	// Headers are the values of the stable headers of the response of the endpoint
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// changed returns true if any of the fields of the record differs from the previous record
+func (record *endpointRecord) changed(previous *endpointRecord, fields []string) bool {
+	for _, field := range fields {
+		switch field {
+		case incrementalStatus:
+			if record.Status != previous.Status {
+				return true
+			}
+		case incrementalHash:
+			if record.Hash != previous.Hash {
+				return true
+			}
+		case incrementalHeaders:
+			if len(record.Headers) != len(previous.Headers) {
+				return true
+			}
+			for name, value := range record.Headers {
+				if previousValue, ok := previous.Headers[name]; !ok || previousValue != value {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// incrementalStats are the numbers of endpoints scanned and skipped by an incremental scan
+type incrementalStats struct {
+	New        int
+	Changed    int
+	Unprobed   int
+	Unchanged  int
+	Unrecorded int
+}
+
+// incrementalScan skips the endpoints unchanged since the previous crawl
+// recorded in the endpoint inventory, which is updated once the scan is done.
+type incrementalScan struct {
+	path     string
+	fields   []string
+	previous map[string]*endpointRecord
+	current  map[string]*endpointRecord
+	mutex    sync.Mutex
+	stats    incrementalStats
+}
+
+// newIncrementalScan returns an incremental scan comparing the fields of the
+// endpoints with the endpoint inventory at path, which may not exist yet
+func newIncrementalScan(path string, fields []string) (*incrementalScan, error) {
+	if len(fields) == 0 {
+		fields = defaultIncrementalFields
+	}
+	scan := &incrementalScan{path: path, previous: make(map[string]*endpointRecord), current: make(map[string]*endpointRecord)}
+	for _, field := range fields {
+		scan.fields = append(scan.fields, strings.ToLower(strings.TrimSpace(field)))
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return scan, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open endpoint inventory")
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		record := &endpointRecord{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal endpoint record")
+		}
+		scan.previous[record.URL] = record
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read endpoint inventory")
+	}
+	return scan, nil
+}
+
+// record records the current state of an endpoint, returning true if the
+// endpoint is new or changed since the previous crawl
+func (scan *incrementalScan) record(record *endpointRecord) bool {
+	scan.mutex.Lock()
+	defer scan.mutex.Unlock()
+
+	scan.current[record.URL] = record
+	previous, ok := scan.previous[record.URL]
+	switch {
+	case !ok:
+		scan.stats.New++
+		return true
+	case record.changed(previous, scan.fields):
+		scan.stats.Changed++
+		return true
+	default:
+		scan.stats.Unchanged++
+		return false
+	}
+}
+
+// Write writes the endpoint inventory, with the current state of the endpoints
+// probed by the scan and the previous state of the other ones
+func (scan *incrementalScan) Write() error {
+	scan.mutex.Lock()
+	defer scan.mutex.Unlock()
+
+	records := make([]*endpointRecord, 0, len(scan.previous)+len(scan.current))
+	for url, record := range scan.previous {
+		if _, ok := scan.current[url]; !ok {
+			records = append(records, record)
+		}
+	}
+	for _, record := range scan.current {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].URL < records[j].URL
+	})
+
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return errors.Wrap(err, "could not marshal endpoint record")
+		}
+	}
+	if err := os.WriteFile(scan.path, buffer.Bytes(), 0644); err != nil {
+		return errors.Wrap(err, "could not write endpoint inventory")
+	}
+	return nil
+}
+
+// excludeUnchangedInputs probes the http endpoints of the inputs and excludes
+// the ones unchanged since the previous crawl from the scan.
+//
+// Inputs without an http endpoint, or with an endpoint which can't be probed,
+// are always scanned.
+func (r *Runner) excludeUnchangedInputs(scan *incrementalScan, inputsHTTP *hybrid.HybridMap) error {
+	gologger.Info().Msgf("Probing endpoints for changes since the previous crawl in %s", scan.path)
+
+	bulkSize := probeBulkSize
+	if r.options.BulkSize > probeBulkSize {
+		bulkSize = r.options.BulkSize
+	}
+	clientOptions := retryablehttp.DefaultOptionsSpraying
+	clientOptions.RetryMax = r.options.Retries
+	clientOptions.Timeout = time.Duration(r.options.Timeout) * time.Second
+	client := retryablehttp.NewClient(clientOptions)
+	readSize := int64(maxEndpointBodySize)
+	if r.options.ResponseReadSize > 0 {
+		readSize = int64(r.options.ResponseReadSize)
+	}
+
+	var mutex sync.Mutex
+	var unchanged []*contextargs.MetaInput
+	swg := sizedwaitgroup.New(bulkSize)
+	r.hmapInputProvider.Scan(func(value *contextargs.MetaInput) bool {
+		endpoint := value.Input
+		if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+			endpoint = ""
+			if inputsHTTP != nil {
+				if probed, ok := inputsHTTP.Get(value.Input); ok {
+					endpoint = string(probed)
+				}
+			}
+		}
+		if endpoint == "" {
+			scan.mutex.Lock()
+			scan.stats.Unrecorded++
+			scan.mutex.Unlock()
+			return true
+		}
+
+		swg.Add()
+		go func(input *contextargs.MetaInput, endpoint string) {
+			defer swg.Done()
+
+			record, err := probeEndpoint(client, endpoint, readSize)
+			if err != nil {
+				gologger.Verbose().Msgf("Could not probe endpoint %s: %s", endpoint, err)
+				scan.mutex.Lock()
+				scan.stats.Unprobed++
+				scan.mutex.Unlock()
+				return
+			}
+			if !scan.record(record) {
+				mutex.Lock()
+				unchanged = append(unchanged, input)
+				mutex.Unlock()
+			}
+		}(value, endpoint)
+		return true
+	})
+	swg.Wait()
+
+	for _, input := range unchanged {
+		r.hmapInputProvider.Exclude(input)
+	}
+	stats := scan.stats
+	gologger.Info().Msgf("Incremental scan: %d endpoints scanned (%d new, %d changed, %d unprobed, %d non-http), %d unchanged skipped", stats.New+stats.Changed+stats.Unprobed+stats.Unrecorded, stats.New, stats.Changed, stats.Unprobed, stats.Unrecorded, stats.Unchanged)
+	return nil
+}
+
+// probeEndpoint returns the current state of an http endpoint
+func probeEndpoint(client *retryablehttp.Client, endpoint string, readSize int64) (*endpointRecord, error) {
+	req, err := retryablehttp.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", uarand.GetRandom())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.LimitReader(resp.Body, readSize)); err != nil {
+		return nil, err
+	}
+	record := &endpointRecord{URL: endpoint, Status: resp.StatusCode, Hash: hex.EncodeToString(hash.Sum(nil))}
+	for _, name := range endpointHeaders {
+		if values := resp.Header.Values(name); len(values) > 0 {
+			if record.Headers == nil {
+				record.Headers = make(map[string]string)
+			}
+			record.Headers[strings.ToLower(name)] = strings.Join(values, ", ")
+		}
+	}
+	return record, nil
+}
+
+// validateIncrementalFields returns an error if a field isn't an incremental field
+func validateIncrementalFields(fields []string) error {
+	for _, field := range fields {
+		if !sliceutil.Contains(incrementalFields, strings.ToLower(strings.TrimSpace(field))) {
+			return errors.Errorf("invalid incremental field %s, expected one of %s", field, strings.Join(incrementalFields, ","))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/core/inputs/hybrid"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+func TestIncrementalScan(t *testing.T) {
+	version := "v1"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/changed" {
+			fmt.Fprintf(w, "changed %s", version)
+			return
+		}
+		fmt.Fprint(w, "static")
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "inventory.jsonl")
+	run := func(targets ...string) ([]string, incrementalStats) {
+		options := &types.Options{Targets: targets, IPVersion: []string{"4"}, Timeout: 5}
+		input, err := hybrid.New(&hybrid.Options{Options: options})
+		require.Nil(t, err, "could not create input provider")
+		defer input.Close()
+
+		scan, err := newIncrementalScan(path, nil)
+		require.Nil(t, err, "could not load endpoint inventory")
+		runner := &Runner{options: options, hmapInputProvider: input}
+		require.Nil(t, runner.excludeUnchangedInputs(scan, nil), "could not probe endpoints")
+		require.Nil(t, scan.Write(), "could not write endpoint inventory")
+
+		var scanned []string
+		input.Scan(func(value *contextargs.MetaInput) bool {
+			scanned = append(scanned, value.Input)
+			return true
+		})
+		return scanned, scan.stats
+	}
+
+	scanned, stats := run(ts.URL+"/static", ts.URL+"/changed")
+	require.ElementsMatch(t, []string{ts.URL + "/static", ts.URL + "/changed"}, scanned, "new endpoints not scanned")
+	require.Equal(t, incrementalStats{New: 2}, stats, "wrong stats for the first crawl")
+
+	version = "v2"
+	scanned, stats = run(ts.URL+"/static", ts.URL+"/changed", "example.com:22")
+	require.ElementsMatch(t, []string{ts.URL + "/changed", "example.com:22"}, scanned, "unchanged endpoint scanned")
+	require.Equal(t, incrementalStats{Changed: 1, Unchanged: 1, Unrecorded: 1}, stats, "wrong stats for the second crawl")
+
+	// endpoints not probed by a scan are kept in the inventory
+	scanned, stats = run(ts.URL + "/changed")
+	require.Empty(t, scanned, "unchanged endpoint scanned")
+	require.Equal(t, incrementalStats{Unchanged: 1}, stats, "wrong stats for the third crawl")
+	scan, err := newIncrementalScan(path, nil)
+	require.Nil(t, err, "could not load endpoint inventory")
+	require.Len(t, scan.previous, 2, "endpoints missing from the inventory")
+}
+
+func TestEndpointRecordChanged(t *testing.T) {
+	previous := &endpointRecord{URL: "https://example.com", Status: 200, Hash: "a", Headers: map[string]string{"server": "nginx"}}
+
+	headers := &endpointRecord{URL: "https://example.com", Status: 200, Hash: "a", Headers: map[string]string{"server": "apache"}}
+	require.False(t, headers.changed(previous, defaultIncrementalFields), "headers compared by default")
+	require.True(t, headers.changed(previous, incrementalFields), "changed headers not detected")
+
+	status := &endpointRecord{URL: "https://example.com", Status: 302, Hash: "a", Headers: previous.Headers}
+	require.True(t, status.changed(previous, defaultIncrementalFields), "changed status not detected")
+	require.False(t, status.changed(previous, []string{incrementalHash}), "status compared without the status field")
+
+	require.NotNil(t, validateIncrementalFields([]string{"body"}), "invalid field accepted")
+	require.Nil(t, validateIncrementalFields([]string{"Status", "headers"}), "valid fields rejected")
+}
@@ -17,12 +17,15 @@ import (
 	"github.com/projectdiscovery/gologger/formatter"
 	"github.com/projectdiscovery/gologger/levels"
 	"github.com/projectdiscovery/nuclei/v2/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v2/pkg/core"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/protocolinit"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/utils/vardump"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/headless/engine"
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
 	fileutil "github.com/projectdiscovery/utils/file"
 	logutil "github.com/projectdiscovery/utils/log"
+	sliceutil "github.com/projectdiscovery/utils/slice"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 )
 
@@ -115,6 +118,82 @@ func validateOptions(options *types.Options) error {
 	if options.ShouldFollowHTTPRedirects() && options.DisableRedirects {
 		return errors.New("both follow redirects and disable redirects specified")
 	}
+	if options.HeadlessRecord != "" && options.HeadlessReplay != "" {
+		return errors.New("both headless record and headless replay specified")
+	}
+	if !options.DedupeFindings && (options.DedupeIncludeHost || options.DedupeFullOutput != "") {
+		return errors.New("dedupe include host and dedupe full output require dedupe findings")
+	}
+	if len(options.InventoryCategories) > 0 {
+		if options.Inventory == "" {
+			return errors.New("inventory category requires an inventory file")
+		}
+		for _, category := range options.InventoryCategories {
+			if !sliceutil.Contains(output.InventoryCategories, strings.ToLower(strings.TrimSpace(category))) {
+				return fmt.Errorf("invalid inventory category %s, expected one of %s", category, strings.Join(output.InventoryCategories, ","))
+			}
+		}
+	}
+	if options.OutputBatchSize < 0 {
+		return errors.New("output batch size can't be negative")
+	}
+	if options.OutputFlushInterval < 0 {
+		return errors.New("output flush interval can't be negative")
+	}
+	if options.Seed != 0 && options.Stream {
+		return errors.New("seed can't be used with stream mode as the input isn't stored")
+	}
+	if err := validateIncrementalFields(options.IncrementalFields); err != nil {
+		return err
+	}
+	if len(options.DiffResults) > 0 && len(options.DiffResults) != 2 {
+		return errors.New("diff requires the previous and current result files")
+	}
+	if options.OutputTemplate != "" {
+		if options.JSONL {
+			return errors.New("both output template and jsonl output specified")
+		}
+		if _, err := output.ParseOutputTemplate(options.OutputTemplate); err != nil {
+			return errors.Wrap(err, "invalid output template")
+		}
+	}
+	if options.WebhookURL == "" && (len(options.WebhookHeaders) > 0 || options.WebhookTemplate != "") {
+		return errors.New("webhook headers and webhook template require a webhook url")
+	}
+	if options.WebhookURL == "" && (options.OutputBatchSize > 0 || options.OutputFlushInterval > 0) {
+		return errors.New("output batch size and output flush interval only apply to the webhook and require a webhook url")
+	}
+	if options.WebhookURL != "" {
+		if _, err := output.ParseWebhookHeaders(options.WebhookHeaders); err != nil {
+			return err
+		}
+		if options.WebhookTemplate != "" {
+			if _, err := output.ParseOutputTemplate(options.WebhookTemplate); err != nil {
+				return errors.Wrap(err, "invalid webhook template")
+			}
+		}
+	}
+	if options.VerifyOutput != "" && options.SignKey == "" {
+		return errors.New("verify output requires a sign key")
+	}
+	if options.SignKey != "" {
+		if options.Output == "" && options.VerifyOutput == "" {
+			return errors.New("sign key requires an output file")
+		}
+		signingKey, err := output.LoadSigningKey(options.SignKey)
+		if err != nil {
+			return err
+		}
+		if options.VerifyOutput == "" && !signingKey.CanSign() {
+			return errors.New("sign key is a public key which can only verify output files")
+		}
+	}
+	if options.DialTimeout < 0 || options.TLSHandshakeTimeout < 0 || options.ResponseReadTimeout < 0 {
+		return errors.New("dial, tls handshake and response read timeouts can't be negative")
+	}
+	if _, err := core.ParseProtocolConcurrency(options.ProtocolConcurrency); err != nil {
+		return err
+	}
 	// loading the proxy server list from file or cli and test the connectivity
 	if err := loadProxyServers(options); err != nil {
 		return err
@@ -6,8 +6,10 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/proxypool"
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
 	errorutil "github.com/projectdiscovery/utils/errors"
 	fileutil "github.com/projectdiscovery/utils/file"
@@ -39,6 +41,9 @@ func loadProxyServers(options *types.Options) error {
 			proxyList = append(proxyList, p)
 		}
 	}
+	if options.ProxyPool {
+		return loadProxyPool(options, proxyList)
+	}
 	aliveProxy, err := proxyutils.GetAnyAliveProxy(options.Timeout, proxyList...)
 	if err != nil {
 		return err
@@ -61,3 +66,22 @@ func loadProxyServers(options *types.Options) error {
 	}
 	return nil
 }
+
+// loadProxyPool creates the pool distributing requests across the proxy servers
+func loadProxyPool(options *types.Options, proxyList []string) error {
+	pool, err := proxypool.New(proxyList, time.Duration(options.Timeout)*time.Second)
+	if err != nil {
+		return errorutil.WrapfWithNil(err, "could not create proxy pool")
+	}
+	if options.ProxyInternal {
+		if proxy, err := pool.Next(); err == nil {
+			os.Setenv(types.HTTP_PROXY_ENV, proxy.URL.String())
+		}
+	}
+	types.ProxyURL = ""
+	types.ProxySocksURL = ""
+	types.ProxyPool = pool
+	pool.Start(options.ProxyCheckInterval)
+	gologger.Verbose().Msgf("Using a pool of %d proxy servers", pool.Len())
+	return nil
+}
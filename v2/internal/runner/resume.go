@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// resumeWriter is an output writer recording the findings in the resume
+// configuration and dropping the ones emitted before resuming the scan.
+// Only the sha1 keys of the findings are recorded.
+type resumeWriter struct {
+	output.Writer
+	resumeCfg *types.ResumeCfg
+}
+
+// newResumeWriter wraps an output writer to record the findings in the resume configuration
+func newResumeWriter(writer output.Writer, resumeCfg *types.ResumeCfg) *resumeWriter {
+	return &resumeWriter{Writer: writer, resumeCfg: resumeCfg}
+}
+
+// Write writes the event to the output unless it was emitted before resuming the scan
+func (w *resumeWriter) Write(event *output.ResultEvent) error {
+	key := output.FindingKey(event)
+	if w.resumeCfg.Emitted(key) {
+		gologger.Debug().Msgf("[%s] Skipping finding on %s: Resume - Finding already emitted\n", event.TemplateID, event.Matched)
+		return nil
+	}
+	if err := w.Writer.Write(event); err != nil {
+		return err
+	}
+	w.resumeCfg.AddFinding(key)
+	return nil
+}
+
+// ResumeFile returns the path of the file the scan progression is saved to
+func (r *Runner) ResumeFile() string {
+	return r.resumeFile
+}
+
+// startResumeCheckpoints saves the scan progression to the resume file every
+// resume interval until stopResumeCheckpoints is called, if an interval is set.
+func (r *Runner) startResumeCheckpoints() {
+	if r.options.ResumeInterval <= 0 || r.resumeWriter == nil {
+		return
+	}
+	gologger.Info().Msgf("Saving resume checkpoints every %s to: %s", r.options.ResumeInterval, r.resumeFile)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	var once sync.Once
+	r.stopCheckpoints = func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(r.options.ResumeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := r.SaveResumeConfig(r.resumeFile); err != nil {
+				gologger.Warning().Msgf("Could not save resume checkpoint: %s\n", err)
+			}
+		}
+	}()
+}
+
+// stopResumeCheckpoints stops saving the scan progression to the resume file
+func (r *Runner) stopResumeCheckpoints() {
+	if r.stopCheckpoints != nil {
+		r.stopCheckpoints()
+	}
+}
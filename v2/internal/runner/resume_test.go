@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// recordingWriter is an output writer recording the results written
+type recordingWriter struct {
+	output.Writer
+	events []*output.ResultEvent
+}
+
+func (w *recordingWriter) Write(event *output.ResultEvent) error {
+	w.events = append(w.events, event)
+	return nil
+}
+
+func TestResumeCheckpoint(t *testing.T) {
+	resumeCfg := types.NewResumeCfg()
+	resumeCfg.Current["completed"] = &types.ResumeInfo{Completed: true, InFlight: map[uint32]struct{}{}, Scanned: 10}
+	resumeCfg.Current["running"] = &types.ResumeInfo{InFlight: map[uint32]struct{}{4: {}, 6: {}}, Scanned: 8}
+	resumeCfg.Current["idle"] = &types.ResumeInfo{InFlight: map[uint32]struct{}{}, Scanned: 5}
+
+	found := &output.ResultEvent{TemplateID: "completed", Host: "https://example.com", Matched: "https://example.com/admin", MatcherStatus: true}
+	writer := newResumeWriter(&recordingWriter{}, resumeCfg)
+	require.Nil(t, writer.Write(found), "could not write finding")
+
+	runner := &Runner{resumeCfg: resumeCfg}
+	path := filepath.Join(t.TempDir(), "resume.cfg")
+	require.Nil(t, runner.SaveResumeConfig(path), "could not save resume file")
+
+	data, err := os.ReadFile(path)
+	require.Nil(t, err, "could not read resume file")
+	resumed := types.NewResumeCfg()
+	require.Nil(t, json.Unmarshal(data, &resumed), "could not unmarshal resume file")
+	resumed.Compile()
+
+	running := resumed.ResumeFrom["running"]
+	require.Equal(t, uint32(4), running.SkipUnder, "wrong skip under for template in progress")
+	require.Equal(t, uint32(8), running.Scanned, "wrong scanned targets")
+	idle := resumed.ResumeFrom["idle"]
+	require.Equal(t, uint32(5), idle.SkipUnder, "dispatched targets not skipped without targets in flight")
+	require.True(t, resumed.ResumeFrom["completed"].Completed, "template not completed")
+
+	recorder := &recordingWriter{}
+	writer = newResumeWriter(recorder, resumed)
+	require.Nil(t, writer.Write(found), "could not write emitted finding")
+	other := &output.ResultEvent{TemplateID: "completed", Host: "https://other.example.com", Matched: "https://other.example.com/admin", MatcherStatus: true}
+	require.Nil(t, writer.Write(other), "could not write new finding")
+	require.Equal(t, []*output.ResultEvent{other}, recorder.events, "emitted finding written again on resume")
+	require.Len(t, resumed.Findings, 2, "findings not recorded for the next checkpoint")
+}
@@ -3,6 +3,7 @@ package runner
 import (
 	"context"
 	"encoding/json"
+	"math/rand"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
@@ -30,6 +31,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/core/inputs/hybrid"
 	"github.com/projectdiscovery/nuclei/v2/pkg/external/customtemplates"
 	"github.com/projectdiscovery/nuclei/v2/pkg/input"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/dsl"
 	"github.com/projectdiscovery/nuclei/v2/pkg/output"
 	"github.com/projectdiscovery/nuclei/v2/pkg/parsers"
 	"github.com/projectdiscovery/nuclei/v2/pkg/progress"
@@ -37,9 +39,11 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/automaticscan"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/hostbreaker"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/hosterrorscache"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/protocolinit"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/requestbudget"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/uncover"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/utils/excludematchers"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/headless/engine"
@@ -72,6 +76,9 @@ type Runner struct {
 	ratelimiter       *ratelimit.Limiter
 	hostErrors        hosterrorscache.CacheInterface
 	resumeCfg         *types.ResumeCfg
+	resumeFile        string
+	resumeWriter      *resumeWriter
+	stopCheckpoints   func()
 	pprofServer       *http.Server
 	cloudClient       *nucleicloud.Client
 	cloudTargets      []string
@@ -90,10 +97,23 @@ func New(options *types.Options) (*Runner, error) {
 		os.Exit(0)
 	}
 
+	if len(options.DiffResults) > 0 {
+		return nil, DiffResults(options)
+	}
+
+	if options.VerifyOutput != "" {
+		return nil, VerifyOutput(options)
+	}
+
 	if options.Cloud {
 		runner.cloudClient = nucleicloud.New(options.CloudURL, options.CloudAPIKey)
 	}
 
+	// seed the random helpers before any template is compiled
+	if options.Seed != 0 {
+		dsl.SetRandomSeed(int64(options.Seed))
+	}
+
 	//  Version check by default
 	if config.DefaultConfig.CanCheckForUpdates() {
 		if err := installer.NucleiVersionCheck(); err != nil {
@@ -255,10 +275,15 @@ func New(options *types.Options) (*Runner, error) {
 		statsInterval = -1
 		options.EnableProgressBar = true
 	}
-	runner.progress, progressErr = progress.NewStatsTicker(statsInterval, options.EnableProgressBar, options.StatsJSON, options.Metrics, options.Cloud, options.MetricsPort)
+	var prometheusAddress string
+	if options.Prometheus {
+		prometheusAddress = options.PrometheusAddress
+	}
+	runner.progress, progressErr = progress.NewStatsTicker(statsInterval, options.EnableProgressBar, options.StatsJSON, options.Metrics, options.Cloud, options.MetricsPort, prometheusAddress)
 	if progressErr != nil {
 		return nil, progressErr
 	}
+	runner.output = progress.MetricsWriter(runner.output, runner.progress)
 
 	// create project file if requested or load the existing one
 	if options.Project {
@@ -284,6 +309,13 @@ func New(options *types.Options) (*Runner, error) {
 		resumeCfg.Compile()
 	}
 	runner.resumeCfg = resumeCfg
+	runner.resumeFile = options.Resume
+	if runner.resumeFile == "" {
+		runner.resumeFile = types.DefaultResumeFilePath()
+	}
+	// findings are always recorded as the resume file is also saved on interruption
+	runner.resumeWriter = newResumeWriter(runner.output, resumeCfg)
+	runner.output = runner.resumeWriter
 
 	opts := interactsh.DefaultOptions(runner.output, runner.issuesClient, runner.progress)
 	opts.Debug = runner.options.Debug
@@ -298,6 +330,7 @@ func New(options *types.Options) (*Runner, error) {
 	opts.PollDuration = time.Duration(options.InteractionsPollDuration) * time.Second
 	opts.NoInteractsh = runner.options.NoInteractsh
 	opts.StopAtFirstMatch = runner.options.StopAtFirstMatch
+	opts.ExportInteractions = runner.options.InteractionsOutput
 	opts.Debug = runner.options.Debug
 	opts.DebugRequest = runner.options.DebugRequests
 	opts.DebugResponse = runner.options.DebugResponse
@@ -376,6 +409,7 @@ func createReportingOptions(options *types.Options) (*reporting.Options, error)
 
 // Close releases all the resources and cleans up
 func (r *Runner) Close() {
+	r.stopResumeCheckpoints()
 	if r.output != nil {
 		r.output.Close()
 	}
@@ -384,6 +418,9 @@ func (r *Runner) Close() {
 	}
 	r.hmapInputProvider.Close()
 	protocolinit.Close()
+	if types.ProxyPool != nil {
+		types.ProxyPool.Close()
+	}
 	if r.pprofServer != nil {
 		_ = r.pprofServer.Shutdown(context.Background())
 	}
@@ -437,6 +474,14 @@ func (r *Runner) RunEnumeration() error {
 		r.hostErrors = cache
 		executerOpts.HostErrorsCache = cache
 	}
+	executerOpts.HostBreaker = hostbreaker.New(hostbreaker.Options{
+		MaxRetries:         r.options.RetryBackoff,
+		MaxDelay:           r.options.RetryBackoffMax,
+		Threshold:          r.options.BreakerThreshold,
+		Cooldown:           r.options.BreakerCooldown,
+		RespectRateHeaders: r.options.RespectRateHeaders,
+	})
+	executerOpts.RequestBudgets = requestbudget.NewTracker()
 
 	engine := core.New(r.options)
 	engine.SetExecuterOptions(executerOpts)
@@ -500,6 +545,11 @@ func (r *Runner) RunEnumeration() error {
 		os.Exit(0)
 	}
 
+	// run the templates against the response fixture instead of the targets
+	if r.options.ResponseFixture != "" {
+		return r.runResponseFixture(store)
+	}
+
 	// display execution info like version , templates used etc
 	r.displayExecutionInfo(store)
 
@@ -514,6 +564,18 @@ func (r *Runner) RunEnumeration() error {
 		executerOpts.InputHelper.InputsHTTP = inputHelpers
 	}
 
+	// skip the endpoints unchanged since the previous crawl
+	var incremental *incrementalScan
+	if r.options.Incremental != "" && !r.options.Cloud {
+		incremental, err = newIncrementalScan(r.options.Incremental, r.options.IncrementalFields)
+		if err != nil {
+			return errors.Wrap(err, "could not load endpoint inventory")
+		}
+		if err := r.excludeUnchangedInputs(incremental, executerOpts.InputHelper.InputsHTTP); err != nil {
+			return errors.Wrap(err, "could not probe endpoints for changes")
+		}
+	}
+
 	enumeration := false
 	var results *atomic.Bool
 	if r.options.Cloud {
@@ -562,8 +624,15 @@ func (r *Runner) RunEnumeration() error {
 			enumeration = true
 		}
 	} else {
+		r.startResumeCheckpoints()
 		results, err = r.runStandardEnumeration(executerOpts, store, engine)
+		r.stopResumeCheckpoints()
 		enumeration = true
+		if err == nil && incremental != nil {
+			if err := incremental.Write(); err != nil {
+				gologger.Warning().Msgf("Could not write endpoint inventory: %s", err)
+			}
+		}
 	}
 
 	if !enumeration {
@@ -577,6 +646,13 @@ func (r *Runner) RunEnumeration() error {
 		}
 	}
 	r.progress.Stop()
+	if r.options.EnableProgressBar {
+		displayProtocolStats(engine.ProtocolStats())
+	}
+	if executerOpts.HostBreaker != nil {
+		displayBreakerStats(executerOpts.HostBreaker.Stats())
+	}
+	displayBudgetStats(executerOpts.RequestBudgets.Stats())
 
 	if executerOpts.InputHelper != nil {
 		_ = executerOpts.InputHelper.Close()
@@ -599,6 +675,31 @@ func (r *Runner) RunEnumeration() error {
 	return err
 }
 
+// displayProtocolStats displays the utilization of the per-protocol concurrency limits
+func displayProtocolStats(stats []core.ProtocolPoolStats) {
+	for _, stat := range stats {
+		if stat.Executions == 0 {
+			continue
+		}
+		gologger.Info().Msgf("Protocol %s: %d executions, peak concurrency %d/%d (%.0f%%), %d waited for a free slot", stat.Protocol, stat.Executions, stat.Peak, stat.Limit, stat.Utilization(), stat.Saturated)
+	}
+}
+
+// displayBreakerStats displays the retries and trips of the per host breaker
+func displayBreakerStats(stats hostbreaker.Stats) {
+	if stats.Retries == 0 && stats.Trips == 0 {
+		return
+	}
+	gologger.Info().Msgf("Host breaker: %d throttled requests retried, %d trips, %d requests skipped", stats.Retries, stats.Trips, stats.Skipped)
+}
+
+// displayBudgetStats displays the requests skipped by the request budgets of the templates
+func displayBudgetStats(stats []requestbudget.Stat) {
+	for _, stat := range stats {
+		gologger.Info().Msgf("Request budget: [%s] %d requests skipped for %s after the max of %d", stat.TemplateID, stat.Skipped, stat.Host, stat.MaxRequests)
+	}
+}
+
 func (r *Runner) isInputNonHTTP() bool {
 	var nonURLInput bool
 	r.hmapInputProvider.Scan(func(value *contextargs.MetaInput) bool {
@@ -654,6 +755,12 @@ func (r *Runner) executeTemplatesInput(store *loader.Store, engine *core.Engine)
 	originalTemplatesCount := len(store.Templates())
 	finalTemplates, clusterCount := templates.ClusterTemplates(store.Templates(), engine.ExecuterOptions())
 	finalTemplates = append(finalTemplates, store.Workflows()...)
+	if r.options.Seed != 0 {
+		random := rand.New(rand.NewSource(int64(r.options.Seed)))
+		random.Shuffle(len(finalTemplates), func(i, j int) {
+			finalTemplates[i], finalTemplates[j] = finalTemplates[j], finalTemplates[i]
+		})
+	}
 
 	var totalRequests int64
 	for _, t := range finalTemplates {
@@ -710,7 +817,12 @@ func (r *Runner) SaveResumeConfig(path string) error {
 	resumeCfgClone.ResumeFrom = resumeCfgClone.Current
 	data, _ := json.MarshalIndent(resumeCfgClone, "", "\t")
 
-	return os.WriteFile(path, data, os.ModePerm)
+	// write to a temporary file first so an interrupted write doesn't corrupt the checkpoint
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, os.ModePerm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
 }
 
 type WalkFunc func(reflect.Value, reflect.StructField)
@@ -0,0 +1,24 @@
+package runner
+
+import (
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// VerifyOutput verifies the output file against its detached signature
+// with the sign key.
+func VerifyOutput(options *types.Options) error {
+	key, err := output.LoadSigningKey(options.SignKey)
+	if err != nil {
+		return err
+	}
+	manifest, err := output.VerifyOutput(options.VerifyOutput, key)
+	if err != nil {
+		return errors.Wrapf(err, "could not verify %s", options.VerifyOutput)
+	}
+	gologger.Info().Msgf("Verified %s signature of %s with %d findings", manifest.Algorithm, options.VerifyOutput, len(manifest.Findings))
+	return nil
+}
@@ -257,6 +257,9 @@ func tryCollectConditionsMatchinfo(template *templates.Template) map[string]inte
 	for _, req := range template.RequestsWebsocket {
 		matcherTypes = append(matcherTypes, collectMatcherTypes(req.Matchers)...)
 	}
+	for _, req := range template.RequestsICMP {
+		matcherTypes = append(matcherTypes, collectMatcherTypes(req.Matchers)...)
+	}
 	matcherTypes = sliceutil.Dedupe(sliceutil.PruneEmptyStrings(matcherTypes))
 	parameters["matcher_type"] = matcherTypes
 
@@ -286,6 +289,9 @@ func tryCollectConditionsMatchinfo(template *templates.Template) map[string]inte
 	for _, req := range template.RequestsWebsocket {
 		extractorTypes = append(extractorTypes, collectExtractorTypes(req.Extractors)...)
 	}
+	for _, req := range template.RequestsICMP {
+		extractorTypes = append(extractorTypes, collectExtractorTypes(req.Extractors)...)
+	}
 	extractorTypes = sliceutil.Dedupe(sliceutil.PruneEmptyStrings(extractorTypes))
 	parameters["extractor_type"] = extractorTypes
 
@@ -16,10 +16,11 @@ import (
 // templates to leading to the final execution by the work pool, it is
 // handled by the engine.
 type Engine struct {
-	workPool     *WorkPool
-	options      *types.Options
-	executerOpts protocols.ExecuterOptions
-	Callback     func(*output.ResultEvent) // Executed on results
+	workPool      *WorkPool
+	protocolPools *ProtocolPools
+	options       *types.Options
+	executerOpts  protocols.ExecuterOptions
+	Callback      func(*output.ResultEvent) // Executed on results
 }
 
 // InputProvider is an input providing interface for the nuclei execution
@@ -43,6 +44,14 @@ func New(options *types.Options) *Engine {
 		options: options,
 	}
 	engine.workPool = engine.GetWorkPool()
+
+	// protocol concurrency is validated with the options so the
+	// defaults are only used by callers providing invalid values
+	limits, err := ParseProtocolConcurrency(options.ProtocolConcurrency)
+	if err != nil {
+		limits = DefaultProtocolConcurrency
+	}
+	engine.protocolPools = NewProtocolPools(limits)
 	return engine
 }
 
@@ -71,3 +80,8 @@ func (e *Engine) ExecuterOptions() protocols.ExecuterOptions {
 func (e *Engine) WorkPool() *WorkPool {
 	return e.workPool
 }
+
+// ProtocolStats returns the utilization statistics of the per-protocol concurrency limits
+func (e *Engine) ProtocolStats() []ProtocolPoolStats {
+	return e.protocolPools.Stats()
+}
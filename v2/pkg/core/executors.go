@@ -78,15 +78,18 @@ func (e *Engine) executeTemplateWithTargets(template *templates.Template, target
 		// skips indexes lower than the minimum in-flight at interruption time
 		var skip bool
 		if resumeFromInfo.Completed { // the template was completed
-			gologger.Debug().Msgf("[%s] Skipping \"%s\": Resume - Template already completed\n", template.ID, scannedValue)
+			gologger.Debug().Msgf("[%s] Skipping \"%s\": Resume - Template already completed\n", template.ID, scannedValue.PrettyPrint())
 			skip = true
 		} else if index < resumeFromInfo.SkipUnder { // index lower than the sliding window (bulk-size)
-			gologger.Debug().Msgf("[%s] Skipping \"%s\": Resume - Target already processed\n", template.ID, scannedValue)
+			gologger.Debug().Msgf("[%s] Skipping \"%s\": Resume - Target already processed\n", template.ID, scannedValue.PrettyPrint())
 			skip = true
 		} else if _, isInFlight := resumeFromInfo.InFlight[index]; isInFlight { // the target wasn't completed successfully
-			gologger.Debug().Msgf("[%s] Repeating \"%s\": Resume - Target wasn't completed\n", template.ID, scannedValue)
+			gologger.Debug().Msgf("[%s] Repeating \"%s\": Resume - Target wasn't completed\n", template.ID, scannedValue.PrettyPrint())
 			// skip is already false, but leaving it here for clarity
 			skip = false
+		} else if index < resumeFromInfo.Scanned { // index dispatched and completed before the checkpoint
+			gologger.Debug().Msgf("[%s] Skipping \"%s\": Resume - Target already processed\n", template.ID, scannedValue.PrettyPrint())
+			skip = true
 		} else if index > resumeFromInfo.DoAbove { // index above the sliding window (bulk-size)
 			// skip is already false - but leaving it here for clarity
 			skip = false
@@ -94,10 +97,13 @@ func (e *Engine) executeTemplateWithTargets(template *templates.Template, target
 
 		currentInfo.Lock()
 		currentInfo.InFlight[index] = struct{}{}
+		currentInfo.Scanned = index + 1
 		currentInfo.Unlock()
 
 		// Skip if the host has had errors
 		if e.executerOpts.HostErrorsCache != nil && e.executerOpts.HostErrorsCache.Check(scannedValue.ID()) {
+			cleanupInFlight(index)
+			index++
 			return true
 		}
 
@@ -108,6 +114,7 @@ func (e *Engine) executeTemplateWithTargets(template *templates.Template, target
 			if skip {
 				return
 			}
+			defer e.protocolPools.Acquire(template.Type())()
 
 			var match bool
 			var err error
@@ -163,6 +170,7 @@ func (e *Engine) executeTemplatesOnTarget(alltemplates []*templates.Template, ta
 		sg.Add()
 		go func(template *templates.Template, value *contextargs.MetaInput, wg *sizedwaitgroup.SizedWaitGroup) {
 			defer wg.Done()
+			defer e.protocolPools.Acquire(template.Type())()
 
 			var match bool
 			var err error
@@ -218,6 +226,7 @@ func (e *ChildExecuter) Execute(template *templates.Template, value *contextargs
 	wg.Add()
 	go func(tpl *templates.Template) {
 		defer wg.Done()
+		defer e.e.protocolPools.Acquire(tpl.Type())()
 
 		ctxArgs := contextargs.New()
 		ctxArgs.MetaInput = value
@@ -6,7 +6,9 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -18,10 +20,12 @@ import (
 	"github.com/projectdiscovery/hmap/store/hybrid"
 	"github.com/projectdiscovery/mapcidr"
 	"github.com/projectdiscovery/mapcidr/asn"
+	"github.com/projectdiscovery/nuclei/v2/pkg/input/openapi"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/uncover"
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/nuclei/v2/pkg/utils"
 	fileutil "github.com/projectdiscovery/utils/file"
 	iputil "github.com/projectdiscovery/utils/ip"
 	readerutil "github.com/projectdiscovery/utils/reader"
@@ -39,6 +43,10 @@ type Input struct {
 	hostMap           *hybrid.HybridMap
 	hostMapStream     *filekv.FileDB
 	hostMapStreamOnce sync.Once
+	excluded          map[string]struct{}
+	excludedMutex     sync.RWMutex
+	// seed shuffles the iteration order of the input reproducibly if not 0
+	seed int64
 	sync.Once
 }
 
@@ -68,6 +76,7 @@ func New(opts *Options) (*Input, error) {
 			IPV4:       sliceutil.Contains(options.IPVersion, "4"),
 			IPV6:       sliceutil.Contains(options.IPVersion, "6"),
 		},
+		seed: int64(options.Seed),
 	}
 	if options.Stream {
 		fkvOptions := filekv.DefaultOptions
@@ -135,6 +144,11 @@ func (i *Input) initializeInputSources(opts *Options) error {
 			input.Close()
 		}
 	}
+	if options.OpenAPISpecFile != "" {
+		if err := i.scanInputFromOpenAPI(options.OpenAPISpecFile); err != nil {
+			return err
+		}
+	}
 	if options.Uncover && options.UncoverQuery != nil {
 		gologger.Info().Msgf("Running uncover query against: %s", strings.Join(options.UncoverEngine, ","))
 		ch, err := uncover.GetTargetsFromUncover(options.UncoverDelay, options.UncoverLimit, options.UncoverField, options.UncoverEngine, options.UncoverQuery)
@@ -164,12 +178,43 @@ func (i *Input) scanInputFromReader(reader io.Reader) {
 	}
 }
 
+// scanInputFromOpenAPI generates the requests for the operations of an OpenAPI spec
+func (i *Input) scanInputFromOpenAPI(path string) error {
+	spec, requests, err := openapi.ParseFile(path)
+	if err != nil {
+		return err
+	}
+	baseURLs := spec.BaseURLs()
+	if len(baseURLs) == 0 {
+		gologger.Warning().Msgf("No absolute server url found in openapi spec %s", path)
+		return nil
+	}
+	for _, baseURL := range baseURLs {
+		for _, request := range requests {
+			i.setWithRequest(request.URL(baseURL), &contextargs.HTTPRequest{
+				Method:  request.Method,
+				Headers: request.Headers,
+				Body:    request.Body,
+			})
+		}
+	}
+	return nil
+}
+
 // Set normalizes and stores passed input values
 func (i *Input) Set(value string) {
+	i.setWithRequest(value, nil)
+}
+
+// setWithRequest normalizes and stores passed input values
+// along with the http request imported for them if any
+func (i *Input) setWithRequest(value string, request *contextargs.HTTPRequest) {
 	URL := strings.TrimSpace(value)
 	if URL == "" {
 		return
 	}
+	// bare and scoped ipv6 addresses are normalized so they are parsed as hosts
+	URL = utils.NormalizeIPv6Input(URL)
 	// parse hostname if url is given
 	urlx, err := urlutil.Parse(URL)
 	if err != nil || (urlx != nil && urlx.Host == "") {
@@ -179,14 +224,14 @@ func (i *Input) Set(value string) {
 			}
 			return fmt.Sprintf("got empty hostname for %v skipping ip selection", URL)
 		})
-		metaInput := &contextargs.MetaInput{Input: URL}
+		metaInput := &contextargs.MetaInput{Input: URL, Request: request}
 		i.setItem(metaInput)
 		return
 	}
 
 	// Check if input is ip or hostname
-	if iputil.IsIP(urlx.Hostname()) {
-		metaInput := &contextargs.MetaInput{Input: URL}
+	if iputil.IsIP(urlx.Hostname()) || utils.IsIPv6(urlx.Hostname()) {
+		metaInput := &contextargs.MetaInput{Input: URL, Request: request}
 		i.setItem(metaInput)
 		return
 	}
@@ -207,7 +252,7 @@ func (i *Input) Set(value string) {
 					if ip == "" {
 						continue
 					}
-					metaInput := &contextargs.MetaInput{Input: value, CustomIP: ip}
+					metaInput := &contextargs.MetaInput{Input: value, CustomIP: ip, Request: request}
 					i.setItem(metaInput)
 				}
 				return
@@ -238,10 +283,10 @@ func (i *Input) Set(value string) {
 
 	for _, ip := range ips {
 		if ip != "" {
-			metaInput := &contextargs.MetaInput{Input: URL, CustomIP: ip}
+			metaInput := &contextargs.MetaInput{Input: URL, CustomIP: ip, Request: request}
 			i.setItem(metaInput)
 		} else {
-			metaInput := &contextargs.MetaInput{Input: URL}
+			metaInput := &contextargs.MetaInput{Input: URL, Request: request}
 			i.setItem(metaInput)
 		}
 	}
@@ -279,6 +324,39 @@ func (i *Input) Count() int64 {
 	return i.inputCount
 }
 
+// Exclude excludes an input from the scan, so that it is skipped by Scan
+func (i *Input) Exclude(metaInput *contextargs.MetaInput) {
+	key, err := metaInput.MarshalString()
+	if err != nil {
+		gologger.Warning().Msgf("%s\n", err)
+		return
+	}
+	if _, ok := i.hostMap.Get(key); !ok {
+		return
+	}
+
+	i.excludedMutex.Lock()
+	defer i.excludedMutex.Unlock()
+
+	if i.excluded == nil {
+		i.excluded = make(map[string]struct{})
+	}
+	if _, ok := i.excluded[key]; ok {
+		return
+	}
+	i.excluded[key] = struct{}{}
+	i.inputCount--
+}
+
+// isExcluded returns true if the input of the key was excluded
+func (i *Input) isExcluded(key string) bool {
+	i.excludedMutex.RLock()
+	defer i.excludedMutex.RUnlock()
+
+	_, ok := i.excluded[key]
+	return ok
+}
+
 // Scan iterates the input and each found item is passed to the
 // callback consumer.
 func (i *Input) Scan(callback func(value *contextargs.MetaInput) bool) {
@@ -290,6 +368,9 @@ func (i *Input) Scan(callback func(value *contextargs.MetaInput) bool) {
 		})
 	}
 	callbackFunc := func(k, _ []byte) error {
+		if i.isExcluded(string(k)) {
+			return nil
+		}
 		metaInput := &contextargs.MetaInput{}
 		if err := metaInput.Unmarshal(string(k)); err != nil {
 			return err
@@ -301,11 +382,34 @@ func (i *Input) Scan(callback func(value *contextargs.MetaInput) bool) {
 	}
 	if i.hostMapStream != nil {
 		_ = i.hostMapStream.Scan(callbackFunc)
+	} else if i.seed != 0 {
+		i.scanShuffled(callbackFunc)
 	} else {
 		i.hostMap.Scan(callbackFunc)
 	}
 }
 
+// scanShuffled iterates the input in an order shuffled with the seed, the
+// same seed giving the same order for the same input
+func (i *Input) scanShuffled(callback func(k, v []byte) error) {
+	var keys []string
+	i.hostMap.Scan(func(k, _ []byte) error {
+		keys = append(keys, string(k))
+		return nil
+	})
+	// sort the keys first so the order only depends on the seed and the input
+	sort.Strings(keys)
+	random := rand.New(rand.NewSource(i.seed))
+	random.Shuffle(len(keys), func(a, b int) {
+		keys[a], keys[b] = keys[b], keys[a]
+	})
+	for _, key := range keys {
+		if err := callback([]byte(key), nil); err != nil {
+			return
+		}
+	}
+}
+
 // expandCIDRInputValue expands CIDR and stores expanded IPs
 func (i *Input) expandCIDRInputValue(value string) {
 	ips, _ := mapcidr.IPAddressesAsStream(value)
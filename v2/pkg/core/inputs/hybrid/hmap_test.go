@@ -1,8 +1,10 @@
 package hybrid
 
 import (
+	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -189,3 +191,101 @@ func Test_expandASNInputValue(t *testing.T) {
 		require.ElementsMatch(t, items, got, "could not get correct ips")
 	}
 }
+
+func Test_excludeInput(t *testing.T) {
+	hm, err := hybrid.New(hybrid.DefaultDiskOptions)
+	require.Nil(t, err, "could not create temporary input file")
+	input := &Input{hostMap: hm, ipOptions: &ipOptions{IPV4: true}}
+	defer input.Close()
+
+	input.Set("https://127.0.0.1/login")
+	input.Set("https://127.0.0.1/admin")
+	input.Exclude(&contextargs.MetaInput{Input: "https://127.0.0.1/login"})
+	input.Exclude(&contextargs.MetaInput{Input: "https://127.0.0.1/login"})
+	input.Exclude(&contextargs.MetaInput{Input: "https://127.0.0.1/unknown"})
+	require.Equal(t, int64(1), input.Count(), "wrong input count")
+
+	got := []string{}
+	input.Scan(func(value *contextargs.MetaInput) bool {
+		got = append(got, value.Input)
+		return true
+	})
+	require.Equal(t, []string{"https://127.0.0.1/admin"}, got, "excluded input scanned")
+}
+
+func Test_scanSeeded(t *testing.T) {
+	scan := func(seed int64) []string {
+		hm, err := hybrid.New(hybrid.DefaultDiskOptions)
+		require.Nil(t, err, "could not create temporary input file")
+		input := &Input{hostMap: hm, ipOptions: &ipOptions{IPV4: true}, seed: seed}
+		defer input.Close()
+
+		for i := 0; i < 20; i++ {
+			input.Set(fmt.Sprintf("https://127.0.0.%d", i))
+		}
+		input.Exclude(&contextargs.MetaInput{Input: "https://127.0.0.3"})
+
+		got := []string{}
+		input.Scan(func(value *contextargs.MetaInput) bool {
+			got = append(got, value.Input)
+			return true
+		})
+		return got
+	}
+
+	sorted := scan(0)
+	shuffled := scan(42)
+	require.Len(t, shuffled, 19, "excluded input scanned")
+	require.ElementsMatch(t, sorted, shuffled, "wrong shuffled inputs")
+	require.NotEqual(t, sorted, shuffled, "inputs not shuffled")
+	require.Equal(t, shuffled, scan(42), "shuffled order not reproducible")
+	require.NotEqual(t, shuffled, scan(7), "shuffled order independent of the seed")
+}
+
+func Test_scanInputFromOpenAPI(t *testing.T) {
+	spec := `openapi: 3.0.0
+servers:
+  - url: https://127.0.0.1/api
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+      x-example: secret-key
+security:
+  - apiKey: []
+paths:
+  /users:
+    get:
+      responses: {}
+    post:
+      requestBody:
+        content:
+          application/json:
+            example: {"name": "nuclei"}
+      responses: {}
+`
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.Nil(t, os.WriteFile(path, []byte(spec), 0600), "could not write spec")
+
+	hm, err := hybrid.New(hybrid.DefaultDiskOptions)
+	require.Nil(t, err, "could not create temporary input file")
+	input := &Input{hostMap: hm, ipOptions: &ipOptions{IPV4: true}}
+	defer input.Close()
+
+	require.Nil(t, input.scanInputFromOpenAPI(path), "could not scan openapi spec")
+	require.Equal(t, int64(2), input.Count(), "wrong input count")
+
+	got := make(map[string]*contextargs.MetaInput)
+	input.Scan(func(value *contextargs.MetaInput) bool {
+		require.NotNil(t, value.Request, "input without imported request")
+		got[value.Request.Method] = value
+		return true
+	})
+	require.Equal(t, "https://127.0.0.1/api/users", got["POST"].Input, "wrong input url")
+	require.JSONEq(t, `{"name":"nuclei"}`, got["POST"].Request.Body, "wrong imported body")
+	require.Equal(t, "application/json", got["POST"].Request.Headers["Content-Type"], "wrong imported content type")
+	require.Equal(t, "secret-key", got["GET"].Request.Headers["X-API-Key"], "wrong imported security header")
+	require.Empty(t, got["GET"].Request.Body, "wrong imported body")
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates/types"
+)
+
+// DefaultProtocolConcurrency is the default maximum number of templates of a
+// protocol executed in parallel on targets. The limits are applied under the
+// global concurrency of the engine, and protocols which are not limited are
+// only bound by it.
+var DefaultProtocolConcurrency = map[types.ProtocolType]int{
+	types.HeadlessProtocol: 5,
+	types.HTTPProtocol:     50,
+	types.DNSProtocol:      200,
+}
+
+// ParseProtocolConcurrency parses per-protocol concurrency values in
+// protocol=limit format and layers them over the default protocol concurrency.
+//
+// A limit of 0 removes the limit for the protocol.
+func ParseProtocolConcurrency(values []string) (map[types.ProtocolType]int, error) {
+	limits := make(map[types.ProtocolType]int, len(DefaultProtocolConcurrency))
+	for protocol, limit := range DefaultProtocolConcurrency {
+		limits[protocol] = limit
+	}
+	for _, value := range values {
+		name, limitValue, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid protocol concurrency %s, expected protocol=limit", value)
+		}
+		protocol, ok := protocolTypeFromString(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid protocol %s in protocol concurrency", name)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(limitValue))
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit %s for protocol %s", limitValue, name)
+		}
+		limits[protocol] = limit
+	}
+	return limits, nil
+}
+
+// protocolTypeFromString returns the protocol type of a protocol name
+func protocolTypeFromString(name string) (types.ProtocolType, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, protocol := range types.GetSupportedProtocolTypes() {
+		if protocol.String() == name {
+			return protocol, true
+		}
+	}
+	return 0, false
+}
+
+// ProtocolPools limits the number of templates executed in parallel for each
+// protocol on top of the global concurrency of the engine, so that heavy
+// protocols don't starve the lighter ones.
+type ProtocolPools struct {
+	pools map[types.ProtocolType]*protocolPool
+}
+
+// protocolPool is the pool of a single protocol
+type protocolPool struct {
+	protocol   types.ProtocolType
+	slots      chan struct{}
+	active     atomic.Int64
+	peak       atomic.Int64
+	executions atomic.Uint64
+	saturated  atomic.Uint64
+}
+
+// ProtocolPoolStats contains the utilization statistics of a protocol pool
+type ProtocolPoolStats struct {
+	// Protocol is the name of the protocol
+	Protocol string `json:"protocol"`
+	// Limit is the maximum number of parallel executions of the protocol
+	Limit int `json:"limit"`
+	// Executions is the number of executions of templates of the protocol
+	Executions uint64 `json:"executions"`
+	// Peak is the highest number of parallel executions reached
+	Peak int64 `json:"peak"`
+	// Saturated is the number of executions which waited for a free slot
+	Saturated uint64 `json:"saturated"`
+}
+
+// Utilization returns the peak utilization of the protocol pool in percent
+func (s ProtocolPoolStats) Utilization() float64 {
+	if s.Limit == 0 {
+		return 0
+	}
+	return float64(s.Peak) / float64(s.Limit) * 100
+}
+
+// NewProtocolPools returns protocol pools for the limits specified.
+// Protocols with a limit of 0 are not limited.
+func NewProtocolPools(limits map[types.ProtocolType]int) *ProtocolPools {
+	pools := &ProtocolPools{pools: make(map[types.ProtocolType]*protocolPool)}
+	for protocol, limit := range limits {
+		if limit <= 0 {
+			continue
+		}
+		pools.pools[protocol] = &protocolPool{protocol: protocol, slots: make(chan struct{}, limit)}
+	}
+	return pools
+}
+
+// Acquire waits for a free slot in the pool of a protocol and returns
+// a function releasing it. Protocols without a limit return immediately.
+func (p *ProtocolPools) Acquire(protocol types.ProtocolType) func() {
+	if p == nil {
+		return func() {}
+	}
+	pool, ok := p.pools[protocol]
+	if !ok {
+		return func() {}
+	}
+
+	select {
+	case pool.slots <- struct{}{}:
+	default:
+		pool.saturated.Add(1)
+		pool.slots <- struct{}{}
+	}
+	pool.executions.Add(1)
+	active := pool.active.Add(1)
+	for {
+		peak := pool.peak.Load()
+		if active <= peak || pool.peak.CompareAndSwap(peak, active) {
+			break
+		}
+	}
+	return func() {
+		pool.active.Add(-1)
+		<-pool.slots
+	}
+}
+
+// Stats returns the utilization statistics of the protocol pools sorted by protocol
+func (p *ProtocolPools) Stats() []ProtocolPoolStats {
+	if p == nil {
+		return nil
+	}
+	stats := make([]ProtocolPoolStats, 0, len(p.pools))
+	for _, pool := range p.pools {
+		stats = append(stats, ProtocolPoolStats{
+			Protocol:   pool.protocol.String(),
+			Limit:      cap(pool.slots),
+			Executions: pool.executions.Load(),
+			Peak:       pool.peak.Load(),
+			Saturated:  pool.saturated.Load(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Protocol < stats[j].Protocol
+	})
+	return stats
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates/types"
+)
+
+func TestParseProtocolConcurrency(t *testing.T) {
+	limits, err := ParseProtocolConcurrency(nil)
+	require.Nil(t, err, "could not parse default protocol concurrency")
+	require.Equal(t, DefaultProtocolConcurrency, limits, "could not get default protocol concurrency")
+
+	require.Equal(t, 5, limits[types.HeadlessProtocol], "could not get default headless limit")
+	require.Equal(t, 50, limits[types.HTTPProtocol], "could not get default http limit")
+	require.Equal(t, 200, limits[types.DNSProtocol], "could not get default dns limit")
+	require.Equal(t, 0, limits[types.NetworkProtocol], "network limited by default")
+
+	limits, err = ParseProtocolConcurrency([]string{"http=100", "tcp=20", "headless=0"})
+	require.Nil(t, err, "could not parse protocol concurrency")
+	require.Equal(t, 100, limits[types.HTTPProtocol], "could not set http limit")
+	require.Equal(t, 20, limits[types.NetworkProtocol], "could not set limit")
+	require.Equal(t, 0, limits[types.HeadlessProtocol], "could not remove limit")
+	require.Equal(t, 200, limits[types.DNSProtocol], "could not keep default dns limit")
+	require.Equal(t, 0, DefaultProtocolConcurrency[types.NetworkProtocol], "default protocol concurrency was modified")
+	require.Equal(t, 5, DefaultProtocolConcurrency[types.HeadlessProtocol], "default protocol concurrency was modified")
+
+	for _, value := range []string{"http", "unknown=5", "http=-1", "http=many"} {
+		_, err = ParseProtocolConcurrency([]string{value})
+		require.NotNil(t, err, "could not detect invalid protocol concurrency %s", value)
+	}
+}
+
+func TestProtocolPoolsAcquire(t *testing.T) {
+	pools := NewProtocolPools(map[types.ProtocolType]int{types.HTTPProtocol: 3, types.DNSProtocol: 0})
+
+	var active, peak atomic.Int64
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer pools.Acquire(types.HTTPProtocol)()
+
+			current := active.Add(1)
+			for {
+				value := peak.Load()
+				if current <= value || peak.CompareAndSwap(value, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			active.Add(-1)
+		}()
+	}
+	wg.Wait()
+	require.LessOrEqual(t, peak.Load(), int64(3), "could not limit protocol concurrency")
+
+	// unlimited protocols don't block
+	release := pools.Acquire(types.DNSProtocol)
+	pools.Acquire(types.DNSProtocol)()
+	release()
+
+	stats := pools.Stats()
+	require.Len(t, stats, 1, "could not skip unlimited protocols")
+	require.Equal(t, "http", stats[0].Protocol, "could not get protocol name")
+	require.Equal(t, 3, stats[0].Limit, "could not get protocol limit")
+	require.Equal(t, uint64(20), stats[0].Executions, "could not count executions")
+	require.Equal(t, int64(3), stats[0].Peak, "could not get peak concurrency")
+	require.Positive(t, stats[0].Saturated, "could not count saturated executions")
+	require.Equal(t, float64(100), stats[0].Utilization(), "could not get utilization")
+}
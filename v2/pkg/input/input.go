@@ -7,6 +7,7 @@ import (
 
 	"github.com/projectdiscovery/hmap/store/hybrid"
 	templateTypes "github.com/projectdiscovery/nuclei/v2/pkg/templates/types"
+	"github.com/projectdiscovery/nuclei/v2/pkg/utils"
 	fileutil "github.com/projectdiscovery/utils/file"
 	"github.com/projectdiscovery/utils/ports"
 	stringsutil "github.com/projectdiscovery/utils/strings"
@@ -37,7 +38,7 @@ func (h *Helper) Close() error {
 // appropriate input based on it.
 func (h *Helper) Transform(input string, protocol templateTypes.ProtocolType) string {
 	switch protocol {
-	case templateTypes.DNSProtocol, templateTypes.WHOISProtocol:
+	case templateTypes.DNSProtocol, templateTypes.WHOISProtocol, templateTypes.ICMPProtocol:
 		return h.convertInputToType(input, typeHostOnly, "")
 	case templateTypes.FileProtocol, templateTypes.OfflineHTTPProtocol:
 		return h.convertInputToType(input, typeFilepath, "")
@@ -70,9 +71,9 @@ func (h *Helper) convertInputToType(input string, inputType inputType, defaultPo
 
 	var host, port string
 	if isURL && uri != nil {
-		host, port, _ = net.SplitHostPort(uri.Host)
+		host, port = utils.SplitHostPort(uri.Host)
 	} else {
-		host, port, _ = net.SplitHostPort(input)
+		host, port = utils.SplitHostPort(input)
 	}
 
 	hasHost := host != ""
@@ -116,10 +117,10 @@ func (h *Helper) convertInputToType(input string, inputType inputType, defaultPo
 			return net.JoinHostPort(host, port)
 		}
 		if uri != nil && !hasPort && uri.Scheme == "https" {
-			return net.JoinHostPort(uri.Host, "443")
+			return net.JoinHostPort(uri.Hostname(), "443")
 		}
 		if hasDefaultPort {
-			return net.JoinHostPort(input, defaultPort)
+			return utils.JoinHostPort(input, defaultPort)
 		}
 		if inputType == typeHostWithOptionalPort {
 			return input
@@ -28,6 +28,10 @@ func TestConvertInputToType(t *testing.T) {
 		{"google.com:443", typeHostOnly, "google.com", ""},
 		{"https://google.com", typeHostOnly, "google.com", ""},
 		{"https://google.com:443", typeHostOnly, "google.com", ""},
+		{"[::1]:8443", typeHostOnly, "::1", ""},
+		{"[::1]", typeHostOnly, "::1", ""},
+		{"https://[::1]:8443", typeHostOnly, "::1", ""},
+		{"[fe80::1%25eth0]:8443", typeHostOnly, "fe80::1%eth0", ""},
 
 		// url
 		{"test.com", typeURL, "", ""},
@@ -47,14 +51,22 @@ func TestConvertInputToType(t *testing.T) {
 		{"google.com:443", typeHostWithPort, "google.com:443", ""},
 		{"https://google.com", typeHostWithPort, "google.com:443", ""},
 		{"https://google.com:443", typeHostWithPort, "google.com:443", ""},
+		{"[::1]:8443", typeHostWithPort, "[::1]:8443", ""},
+		{"https://[::1]", typeHostWithPort, "[::1]:443", ""},
+		{"https://[::1]:8443", typeHostWithPort, "[::1]:8443", ""},
 		// host-port with default port
 		{"google.com", typeHostWithPort, "google.com:443", "443"},
+		{"[::1]", typeHostWithPort, "[::1]:443", "443"},
+		{"[fe80::1%25eth0]", typeHostWithPort, "[fe80::1%eth0]:443", "443"},
 
 		// host with optional port
 		{"google.com", typeHostWithOptionalPort, "google.com", ""},
 		{"google.com:443", typeHostWithOptionalPort, "google.com:443", ""},
 		{"https://google.com", typeHostWithOptionalPort, "google.com:443", ""},
 		{"https://google.com:443", typeHostWithOptionalPort, "google.com:443", ""},
+		{"unix:///var/run/docker.sock", typeHostWithOptionalPort, "unix:///var/run/docker.sock", ""},
+		{"[::1]", typeHostWithOptionalPort, "[::1]", ""},
+		{"[::1]:8443", typeHostWithOptionalPort, "[::1]:8443", ""},
 		// host with optional port and default port
 		{"google.com", typeHostWithOptionalPort, "google.com:443", "443"},
 
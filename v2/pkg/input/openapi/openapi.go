@@ -0,0 +1,535 @@
+// Package openapi generates http requests from OpenAPI 3.x specifications.
+//
+// Each path operation of the specification is converted into a request
+// using example values for path, query, header and cookie parameters along
+// with a request body generated from the schema examples.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// maxSchemaDepth is the maximum depth for generating examples from nested schemas
+const maxSchemaDepth = 10
+
+// Spec is a minimal OpenAPI 3.x specification
+type Spec struct {
+	OpenAPI    string                `yaml:"openapi"`
+	Servers    []Server              `yaml:"servers"`
+	Paths      map[string]*PathItem  `yaml:"paths"`
+	Components Components            `yaml:"components"`
+	Security   []map[string][]string `yaml:"security"`
+}
+
+// Server is a server of the specification
+type Server struct {
+	URL string `yaml:"url"`
+}
+
+// Components contains the reusable objects of the specification
+type Components struct {
+	Schemas         map[string]*Schema         `yaml:"schemas"`
+	Parameters      map[string]*Parameter      `yaml:"parameters"`
+	RequestBodies   map[string]*RequestBody    `yaml:"requestBodies"`
+	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes"`
+}
+
+// PathItem contains the operations of a path
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Put        *Operation   `yaml:"put"`
+	Post       *Operation   `yaml:"post"`
+	Delete     *Operation   `yaml:"delete"`
+	Options    *Operation   `yaml:"options"`
+	Head       *Operation   `yaml:"head"`
+	Patch      *Operation   `yaml:"patch"`
+	Trace      *Operation   `yaml:"trace"`
+}
+
+// Operation is a single operation on a path
+type Operation struct {
+	OperationID string                 `yaml:"operationId"`
+	Parameters  []*Parameter           `yaml:"parameters"`
+	RequestBody *RequestBody           `yaml:"requestBody"`
+	Security    *[]map[string][]string `yaml:"security"`
+}
+
+// Parameter is an operation parameter
+type Parameter struct {
+	Ref      string              `yaml:"$ref"`
+	Name     string              `yaml:"name"`
+	In       string              `yaml:"in"`
+	Example  interface{}         `yaml:"example"`
+	Examples map[string]*Example `yaml:"examples"`
+	Schema   *Schema             `yaml:"schema"`
+}
+
+// RequestBody is the body of an operation
+type RequestBody struct {
+	Ref     string                `yaml:"$ref"`
+	Content map[string]*MediaType `yaml:"content"`
+}
+
+// MediaType is the content of a request body for a media type
+type MediaType struct {
+	Example  interface{}         `yaml:"example"`
+	Examples map[string]*Example `yaml:"examples"`
+	Schema   *Schema             `yaml:"schema"`
+}
+
+// Example is a named example value
+type Example struct {
+	Value interface{} `yaml:"value"`
+}
+
+// Schema is a json schema for a value
+type Schema struct {
+	Ref        string             `yaml:"$ref"`
+	Type       string             `yaml:"type"`
+	Format     string             `yaml:"format"`
+	Example    interface{}        `yaml:"example"`
+	Default    interface{}        `yaml:"default"`
+	Enum       []interface{}      `yaml:"enum"`
+	Properties map[string]*Schema `yaml:"properties"`
+	Items      *Schema            `yaml:"items"`
+	AllOf      []*Schema          `yaml:"allOf"`
+	OneOf      []*Schema          `yaml:"oneOf"`
+	AnyOf      []*Schema          `yaml:"anyOf"`
+}
+
+// SecurityScheme is a security scheme of the specification.
+//
+// Since specifications don't contain credentials, a scheme is only
+// applied to requests if it has an x-example value.
+type SecurityScheme struct {
+	Type     string `yaml:"type"`
+	Scheme   string `yaml:"scheme"`
+	Name     string `yaml:"name"`
+	In       string `yaml:"in"`
+	XExample string `yaml:"x-example"`
+}
+
+// Request is a http request generated for an operation
+type Request struct {
+	// Method is the method of the request
+	Method string
+	// Path is the path of the request including the query string
+	Path string
+	// Headers contains the headers of the request
+	Headers map[string]string
+	// Body is the body of the request
+	Body string
+}
+
+// ParseFile parses an OpenAPI specification file and returns the requests for it.
+func ParseFile(path string) (*Spec, []*Request, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not open openapi file")
+	}
+	defer file.Close()
+
+	return Parse(file)
+}
+
+// Parse parses an OpenAPI specification in yaml or json
+// format and returns the requests for its operations.
+func Parse(reader io.Reader) (*Spec, []*Request, error) {
+	spec := &Spec{}
+	if err := yaml.NewDecoder(reader).Decode(spec); err != nil {
+		return nil, nil, errors.Wrap(err, "could not decode openapi specification")
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, nil, fmt.Errorf("unsupported openapi version %q", spec.OpenAPI)
+	}
+
+	// sort paths for a deterministic order of requests
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var requests []*Request
+	for _, path := range paths {
+		item := spec.Paths[path]
+		if item == nil {
+			continue
+		}
+		for _, operation := range item.operations() {
+			request, err := spec.makeRequest(path, operation.method, item, operation.operation)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "could not make request for %s %s", operation.method, path)
+			}
+			requests = append(requests, request)
+		}
+	}
+	return spec, requests, nil
+}
+
+// BaseURLs returns the absolute server urls of the specification
+func (spec *Spec) BaseURLs() []string {
+	var urls []string
+	for _, server := range spec.Servers {
+		if parsed, err := url.Parse(server.URL); err == nil && parsed.IsAbs() {
+			urls = append(urls, strings.TrimSuffix(server.URL, "/"))
+		}
+	}
+	return urls
+}
+
+// URL returns the full url of the request for a base url
+func (r *Request) URL(baseURL string) string {
+	return strings.TrimSuffix(baseURL, "/") + r.Path
+}
+
+// Raw returns the request in raw format usable in http templates
+func (r *Request) Raw() string {
+	builder := &strings.Builder{}
+	builder.WriteString(r.Method + " " + r.Path + " HTTP/1.1\r\n")
+	builder.WriteString("Host: {{Hostname}}\r\n")
+
+	headers := make([]string, 0, len(r.Headers))
+	for key := range r.Headers {
+		headers = append(headers, key)
+	}
+	sort.Strings(headers)
+	for _, key := range headers {
+		builder.WriteString(key + ": " + r.Headers[key] + "\r\n")
+	}
+	builder.WriteString("\r\n")
+	builder.WriteString(r.Body)
+	return builder.String()
+}
+
+type methodOperation struct {
+	method    string
+	operation *Operation
+}
+
+func (item *PathItem) operations() []methodOperation {
+	var operations []methodOperation
+	for _, operation := range []methodOperation{
+		{"GET", item.Get}, {"PUT", item.Put}, {"POST", item.Post}, {"DELETE", item.Delete},
+		{"OPTIONS", item.Options}, {"HEAD", item.Head}, {"PATCH", item.Patch}, {"TRACE", item.Trace},
+	} {
+		if operation.operation != nil {
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
+
+func (spec *Spec) makeRequest(path, method string, item *PathItem, operation *Operation) (*Request, error) {
+	request := &Request{Method: method, Headers: make(map[string]string)}
+
+	query := url.Values{}
+	var cookies []string
+	// operation parameters override the path item ones with the same name and location
+	parameters := make(map[string]*Parameter)
+	var order []string
+	for _, parameter := range append(append([]*Parameter{}, item.Parameters...), operation.Parameters...) {
+		resolved, err := spec.resolveParameter(parameter)
+		if err != nil {
+			return nil, err
+		}
+		key := resolved.In + ":" + resolved.Name
+		if _, ok := parameters[key]; !ok {
+			order = append(order, key)
+		}
+		parameters[key] = resolved
+	}
+	for _, key := range order {
+		parameter := parameters[key]
+		value := toString(spec.parameterExample(parameter))
+
+		switch parameter.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+parameter.Name+"}", url.PathEscape(value))
+		case "query":
+			query.Add(parameter.Name, value)
+		case "header":
+			request.Headers[parameter.Name] = value
+		case "cookie":
+			cookies = append(cookies, parameter.Name+"="+value)
+		}
+	}
+
+	spec.applySecurity(operation, request, query, &cookies)
+
+	if len(cookies) > 0 {
+		request.Headers["Cookie"] = strings.Join(cookies, "; ")
+	}
+	request.Path = path
+	if len(query) > 0 {
+		request.Path += "?" + query.Encode()
+	}
+
+	if operation.RequestBody != nil {
+		body, contentType, err := spec.requestBody(operation.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		if contentType != "" {
+			request.Headers["Content-Type"] = contentType
+			request.Body = body
+		}
+	}
+	return request, nil
+}
+
+// applySecurity applies the first security requirement whose schemes all have example values
+func (spec *Spec) applySecurity(operation *Operation, request *Request, query url.Values, cookies *[]string) {
+	requirements := spec.Security
+	if operation.Security != nil {
+		requirements = *operation.Security
+	}
+
+	for _, requirement := range requirements {
+		var schemes []*SecurityScheme
+		for name := range requirement {
+			scheme, ok := spec.Components.SecuritySchemes[name]
+			if !ok || scheme == nil || scheme.XExample == "" {
+				schemes = nil
+				break
+			}
+			schemes = append(schemes, scheme)
+		}
+		if len(schemes) == 0 {
+			continue
+		}
+		for _, scheme := range schemes {
+			switch {
+			case scheme.Type == "apiKey" && scheme.In == "header":
+				request.Headers[scheme.Name] = scheme.XExample
+			case scheme.Type == "apiKey" && scheme.In == "query":
+				query.Set(scheme.Name, scheme.XExample)
+			case scheme.Type == "apiKey" && scheme.In == "cookie":
+				*cookies = append(*cookies, scheme.Name+"="+scheme.XExample)
+			case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"):
+				request.Headers["Authorization"] = "Bearer " + scheme.XExample
+			case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+				request.Headers["Authorization"] = "Basic " + scheme.XExample
+			}
+		}
+		return
+	}
+}
+
+// requestBody returns the example body and content type for a request body
+func (spec *Spec) requestBody(body *RequestBody) (string, string, error) {
+	if body.Ref != "" {
+		resolved, ok := spec.Components.RequestBodies[refName(body.Ref, "requestBodies")]
+		if !ok || resolved == nil {
+			return "", "", fmt.Errorf("could not resolve request body %s", body.Ref)
+		}
+		body = resolved
+	}
+
+	for _, contentType := range []string{"application/json", "application/x-www-form-urlencoded"} {
+		media, ok := body.Content[contentType]
+		if !ok || media == nil {
+			continue
+		}
+		value := spec.mediaExample(media)
+		if contentType == "application/json" {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return "", "", errors.Wrap(err, "could not marshal json body")
+			}
+			return string(data), contentType, nil
+		}
+		form := url.Values{}
+		if values, ok := value.(map[string]interface{}); ok {
+			for key, item := range values {
+				form.Set(key, toString(item))
+			}
+		}
+		return form.Encode(), contentType, nil
+	}
+
+	// fallback to any content type having an example
+	contentTypes := make([]string, 0, len(body.Content))
+	for contentType := range body.Content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+	for _, contentType := range contentTypes {
+		media := body.Content[contentType]
+		if media == nil {
+			continue
+		}
+		if value := spec.mediaExample(media); value != nil {
+			return toString(value), contentType, nil
+		}
+	}
+	return "", "", nil
+}
+
+func (spec *Spec) resolveParameter(parameter *Parameter) (*Parameter, error) {
+	if parameter.Ref == "" {
+		return parameter, nil
+	}
+	resolved, ok := spec.Components.Parameters[refName(parameter.Ref, "parameters")]
+	if !ok || resolved == nil {
+		return nil, fmt.Errorf("could not resolve parameter %s", parameter.Ref)
+	}
+	return resolved, nil
+}
+
+func (spec *Spec) parameterExample(parameter *Parameter) interface{} {
+	if parameter.Example != nil {
+		return parameter.Example
+	}
+	if value := firstExample(parameter.Examples); value != nil {
+		return value
+	}
+	return spec.schemaExample(parameter.Schema, 0)
+}
+
+func (spec *Spec) mediaExample(media *MediaType) interface{} {
+	if media.Example != nil {
+		return media.Example
+	}
+	if value := firstExample(media.Examples); value != nil {
+		return value
+	}
+	return spec.schemaExample(media.Schema, 0)
+}
+
+// schemaExample generates an example value for a schema, preferring
+// example, default and enum values over a placeholder based on the type.
+func (spec *Spec) schemaExample(schema *Schema, depth int) interface{} {
+	if schema == nil || depth > maxSchemaDepth {
+		return nil
+	}
+	if schema.Ref != "" {
+		resolved, ok := spec.Components.Schemas[refName(schema.Ref, "schemas")]
+		if !ok {
+			return nil
+		}
+		return spec.schemaExample(resolved, depth+1)
+	}
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := make(map[string]interface{})
+		for _, item := range schema.AllOf {
+			if values, ok := spec.schemaExample(item, depth+1).(map[string]interface{}); ok {
+				for key, value := range values {
+					merged[key] = value
+				}
+			}
+		}
+		return merged
+	}
+	if len(schema.OneOf) > 0 {
+		return spec.schemaExample(schema.OneOf[0], depth+1)
+	}
+	if len(schema.AnyOf) > 0 {
+		return spec.schemaExample(schema.AnyOf[0], depth+1)
+	}
+
+	switch schema.Type {
+	case "object", "":
+		if len(schema.Properties) == 0 {
+			if schema.Type == "" {
+				return nil
+			}
+			return map[string]interface{}{}
+		}
+		values := make(map[string]interface{}, len(schema.Properties))
+		for name, property := range schema.Properties {
+			values[name] = spec.schemaExample(property, depth+1)
+		}
+		return values
+	case "array":
+		item := spec.schemaExample(schema.Items, depth+1)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "integer":
+		return 1
+	case "number":
+		return 1.0
+	case "boolean":
+		return true
+	default:
+		return stringExample(schema.Format)
+	}
+}
+
+// stringExample returns a placeholder value for a string format
+func stringExample(format string) string {
+	switch format {
+	case "date":
+		return "2023-01-01"
+	case "date-time":
+		return "2023-01-01T00:00:00Z"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	case "ipv6":
+		return "::1"
+	default:
+		return "string"
+	}
+}
+
+// firstExample returns the value of the first example in name order
+func firstExample(examples map[string]*Example) interface{} {
+	names := make([]string, 0, len(examples))
+	for name := range examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if example := examples[name]; example != nil && example.Value != nil {
+			return example.Value
+		}
+	}
+	return nil
+}
+
+// refName returns the name of a local component reference
+func refName(ref, component string) string {
+	return strings.TrimPrefix(ref, "#/components/"+component+"/")
+}
+
+// toString converts an example value to its string representation
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,122 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `openapi: 3.0.0
+servers:
+  - url: https://api.example.com/v1/
+  - url: /relative
+security:
+  - apiKey: []
+paths:
+  /users/{id}:
+    parameters:
+      - $ref: '#/components/parameters/id'
+    get:
+      parameters:
+        - name: fields
+          in: query
+          schema:
+            type: string
+            enum: [name, email]
+        - name: X-Trace
+          in: header
+          example: trace
+    delete:
+      security: []
+  /users:
+    post:
+      security:
+        - bearer: []
+      requestBody:
+        $ref: '#/components/requestBodies/user'
+  /login:
+    post:
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              properties:
+                username:
+                  type: string
+                  default: admin
+components:
+  parameters:
+    id:
+      name: id
+      in: path
+      schema:
+        type: integer
+  requestBodies:
+    user:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/User'
+  schemas:
+    User:
+      type: object
+      properties:
+        name:
+          type: string
+          example: john
+        email:
+          type: string
+          format: email
+        tags:
+          type: array
+          items:
+            type: string
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+      x-example: secret
+    bearer:
+      type: http
+      scheme: bearer
+`
+
+func TestParse(t *testing.T) {
+	spec, requests, err := Parse(strings.NewReader(testSpec))
+	require.Nil(t, err, "could not parse spec")
+	require.Equal(t, []string{"https://api.example.com/v1"}, spec.BaseURLs())
+	require.Len(t, requests, 4, "could not get all requests")
+
+	login := requests[0]
+	require.Equal(t, "POST", login.Method)
+	require.Equal(t, "/login", login.Path)
+	require.Equal(t, "username=admin", login.Body)
+	require.Equal(t, "application/x-www-form-urlencoded", login.Headers["Content-Type"])
+	require.Equal(t, "secret", login.Headers["X-API-Key"], "global security was not applied")
+
+	// bearer scheme has no example value so no security is applied
+	users := requests[1]
+	require.Equal(t, "POST", users.Method)
+	require.Equal(t, `{"email":"user@example.com","name":"john","tags":["string"]}`, users.Body)
+	require.NotContains(t, users.Headers, "Authorization")
+	require.NotContains(t, users.Headers, "X-API-Key")
+
+	get := requests[2]
+	require.Equal(t, "GET", get.Method)
+	require.Equal(t, "/users/1?fields=name", get.Path)
+	require.Equal(t, "trace", get.Headers["X-Trace"])
+	require.Equal(t, "https://api.example.com/v1/users/1?fields=name", get.URL(spec.BaseURLs()[0]))
+	require.Equal(t, "GET /users/1?fields=name HTTP/1.1\r\nHost: {{Hostname}}\r\nX-API-Key: secret\r\nX-Trace: trace\r\n\r\n", get.Raw())
+
+	del := requests[3]
+	require.Equal(t, "DELETE", del.Method)
+	require.Empty(t, del.Headers, "security was not overridden by operation")
+}
+
+func TestParseInvalidVersion(t *testing.T) {
+	_, _, err := Parse(strings.NewReader("swagger: '2.0'\npaths: {}\n"))
+	require.NotNil(t, err, "swagger 2.0 spec was parsed")
+}
@@ -0,0 +1,103 @@
+package decode
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxCandidates is the maximum number of encoded substrings decoded in a corpus
+	maxCandidates = 100
+	// maxCandidateLength is the length above which encoded substrings are not decoded
+	maxCandidateLength = 64 * 1024
+	// minDecodedLength is the minimum length of a decoded region
+	minDecodedLength = 4
+	// minPrintableRatio is the minimum ratio of printable characters in a decoded region
+	minPrintableRatio = 0.9
+)
+
+var (
+	// hexRegex matches hex encoded substrings of at least 8 bytes
+	hexRegex = regexp.MustCompile(`\b(?:[0-9a-fA-F]{2}){8,}\b`)
+	// base64Regex matches standard and url-safe base64 encoded substrings of at least 12 bytes
+	base64Regex = regexp.MustCompile(`[A-Za-z0-9+/_-]{16,}={0,2}`)
+
+	base64Encodings = []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	}
+)
+
+// Regions returns the decoded content of the hex and base64 encoded
+// substrings of the corpus.
+//
+// Only substrings decoding to mostly printable utf-8 text are returned, which
+// excludes most words, hashes and identifiers incidentally being valid encodings.
+func Regions(corpus string) []string {
+	var regions []string
+	seen := make(map[string]struct{})
+	candidates := 0
+	addCandidates := func(regex *regexp.Regexp, decode func(string) (string, bool)) {
+		for _, candidate := range regex.FindAllString(corpus, -1) {
+			if candidates >= maxCandidates {
+				return
+			}
+			if len(candidate) > maxCandidateLength {
+				continue
+			}
+			if _, ok := seen[candidate]; ok {
+				continue
+			}
+			seen[candidate] = struct{}{}
+			candidates++
+
+			if decoded, ok := decode(candidate); ok {
+				regions = append(regions, decoded)
+			}
+		}
+	}
+	addCandidates(hexRegex, decodeHex)
+	addCandidates(base64Regex, decodeBase64)
+	return regions
+}
+
+// decodeHex decodes a hex encoded candidate
+func decodeHex(candidate string) (string, bool) {
+	decoded, err := hex.DecodeString(candidate)
+	if err != nil {
+		return "", false
+	}
+	return string(decoded), isText(decoded)
+}
+
+// decodeBase64 decodes a base64 encoded candidate with the first matching encoding
+func decodeBase64(candidate string) (string, bool) {
+	for _, encoding := range base64Encodings {
+		decoded, err := encoding.DecodeString(candidate)
+		if err != nil {
+			continue
+		}
+		return string(decoded), isText(decoded)
+	}
+	return "", false
+}
+
+// isText returns true if the data is utf-8 text made of mostly printable characters
+func isText(data []byte) bool {
+	if len(data) < minDecodedLength || !utf8.Valid(data) {
+		return false
+	}
+	var printable, total int
+	for _, r := range string(data) {
+		total++
+		if unicode.IsPrint(r) || unicode.IsSpace(r) {
+			printable++
+		}
+	}
+	return float64(printable)/float64(total) >= minPrintableRatio
+}
@@ -1,8 +1,16 @@
 package dsl
 
 import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"fmt"
+	"io"
+	"net/netip"
+	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Knetic/govaluate"
 	"github.com/miekg/dns"
@@ -96,6 +104,138 @@ func init() {
 		return "", fmt.Errorf("no records found")
 	})
 
+	_ = dsl.AddMultiSignatureHelperFunction("map_get", []string{
+		"(data map, key string) string",
+	}, func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		data, ok := args[0].(map[string]interface{})
+		if !ok {
+			return "", nil
+		}
+		key := types.ToString(args[1])
+		if value, ok := data[key]; ok {
+			return value, nil
+		}
+		// keys are looked up case-insensitively, with '_' matching '-'
+		normalized := strings.ReplaceAll(strings.ToLower(key), "_", "-")
+		for k, v := range data {
+			if strings.ReplaceAll(strings.ToLower(k), "_", "-") == normalized {
+				return v, nil
+			}
+		}
+		return "", nil
+	})
+
+	_ = dsl.AddMultiSignatureHelperFunction("cert_has_san", []string{
+		"(subject_an []string, name string) bool",
+	}, func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		name := strings.ToLower(types.ToString(args[1]))
+		for _, san := range types.ToStringSlice(args[0]) {
+			if matchCertificateName(strings.ToLower(san), name) {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+
+	_ = dsl.AddMultiSignatureHelperFunction("ip_in_cidr", []string{
+		"(ip string, cidr string) bool",
+	}, func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(types.ToString(args[1])))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr %s", types.ToString(args[1]))
+		}
+		// extracted values which are not ips are not part of any cidr
+		addr, err := netip.ParseAddr(strings.TrimSpace(types.ToString(args[0])))
+		if err != nil {
+			return false, nil
+		}
+		return prefix.Contains(addr.Unmap()), nil
+	})
+
+	_ = dsl.AddMultiSignatureHelperFunction("cidr_hosts", []string{
+		"(cidr string) []string",
+	}, func(args ...interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		return cidrHosts(strings.TrimSpace(types.ToString(args[0])))
+	})
+
+	_ = dsl.AddMultiSignatureHelperFunction("date_parse", []string{
+		"(date string) float64",
+		"(date string, layout string) float64",
+	}, func(args ...interface{}) (interface{}, error) {
+		if len(args) != 1 && len(args) != 2 {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		var layout string
+		if len(args) == 2 {
+			layout = types.ToString(args[1])
+		}
+		parsed, err := parseDate(strings.TrimSpace(types.ToString(args[0])), layout)
+		if err != nil {
+			return nil, err
+		}
+		return float64(parsed.Unix()), nil
+	})
+
+	_ = dsl.AddMultiSignatureHelperFunction("date_format", []string{
+		"(unixTime float64) string",
+		"(unixTime float64, layout string) string",
+	}, func(args ...interface{}) (interface{}, error) {
+		if len(args) != 1 && len(args) != 2 {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		seconds, err := strconv.ParseFloat(strings.TrimSpace(types.ToString(args[0])), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid unix time %s", types.ToString(args[0]))
+		}
+		layout := time.RFC3339
+		if len(args) == 2 {
+			layout = dateLayout(types.ToString(args[1]))
+		}
+		return time.Unix(int64(seconds), 0).UTC().Format(layout), nil
+	})
+
+	_ = dsl.AddMultiSignatureHelperFunction("now", []string{
+		"() float64",
+	}, func(args ...interface{}) (interface{}, error) {
+		if len(args) != 0 {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		return float64(time.Now().Unix()), nil
+	})
+
+	decompressors := map[string]func(io.Reader) (io.ReadCloser, error){
+		"gunzip": func(reader io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(reader)
+		},
+		"inflate": func(reader io.Reader) (io.ReadCloser, error) {
+			return flate.NewReader(reader), nil
+		},
+		"zlib_decompress": zlib.NewReader,
+	}
+	for name, newReader := range decompressors {
+		newReader := newReader
+		_ = dsl.AddMultiSignatureHelperFunction(name, []string{
+			"(data string) string",
+		}, func(args ...interface{}) (interface{}, error) {
+			if len(args) != 1 {
+				return nil, dsl.ErrInvalidDslFunction
+			}
+			return decompress(types.ToString(args[0]), newReader)
+		})
+	}
+
 	dsl.PrintDebugCallback = func(args ...interface{}) error {
 		gologger.Info().Msgf("print_debug value: %s", fmt.Sprint(args))
 		return nil
@@ -118,6 +258,203 @@ func (e *CompilationError) Unwrap() error {
 	return e.WrappedError
 }
 
+// ExpandMapIndexes rewrites map index expressions like headers["server"]
+// into map_get(headers, "server") calls, as govaluate doesn't support indexing.
+// String literals in the expression are left untouched.
+func ExpandMapIndexes(expression string) string {
+	if !strings.Contains(expression, "[") {
+		return expression
+	}
+	var builder strings.Builder
+	var identifier strings.Builder
+
+	flushIdentifier := func() {
+		builder.WriteString(identifier.String())
+		identifier.Reset()
+	}
+	for i := 0; i < len(expression); i++ {
+		ch := expression[i]
+		switch {
+		case ch == '"' || ch == '\'':
+			end := stringLiteralEnd(expression, i)
+			flushIdentifier()
+			builder.WriteString(expression[i:end])
+			i = end - 1
+		case isIdentifierChar(ch):
+			identifier.WriteByte(ch)
+		case ch == '[' && identifier.Len() > 0 && i+1 < len(expression) && (expression[i+1] == '"' || expression[i+1] == '\''):
+			end := stringLiteralEnd(expression, i+1)
+			if end >= len(expression) || expression[end] != ']' {
+				flushIdentifier()
+				builder.WriteByte(ch)
+				continue
+			}
+			builder.WriteString("map_get(" + identifier.String() + ", " + expression[i+1:end] + ")")
+			identifier.Reset()
+			i = end
+		default:
+			flushIdentifier()
+			builder.WriteByte(ch)
+		}
+	}
+	flushIdentifier()
+	return builder.String()
+}
+
+// stringLiteralEnd returns the index after the end of the string literal starting at start
+func stringLiteralEnd(expression string, start int) int {
+	quote := expression[start]
+	for i := start + 1; i < len(expression); i++ {
+		switch expression[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(expression)
+}
+
+func isIdentifierChar(ch byte) bool {
+	return ch == '_' || ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z') || ('0' <= ch && ch <= '9')
+}
+
 func GetPrintableDslFunctionSignatures(noColor bool) string {
 	return dsl.GetPrintableDslFunctionSignatures(noColor)
 }
+
+// matchCertificateName returns true if a certificate subject alternative name
+// matches a name. The name can be a glob pattern (e.g. *.example.com), and
+// wildcard names of the certificate match a single label of the name.
+func matchCertificateName(san, name string) bool {
+	if san == name {
+		return true
+	}
+	if matched, err := path.Match(name, san); err == nil && matched {
+		return true
+	}
+	if strings.HasPrefix(san, "*.") {
+		label, rest, found := strings.Cut(name, ".")
+		return found && label != "" && rest == san[2:]
+	}
+	return false
+}
+
+// maxCIDRHosts is the maximum number of addresses returned by cidr_hosts
+const maxCIDRHosts = 65536
+
+// cidrHosts returns the host addresses of a cidr. The network and broadcast
+// addresses of ipv4 networks larger than /31 are not included.
+func cidrHosts(cidr string) ([]string, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cidr %s", cidr)
+	}
+	prefix = prefix.Masked()
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	if hostBits > 16 {
+		return nil, fmt.Errorf("cidr %s has more than %d addresses", cidr, maxCIDRHosts)
+	}
+
+	hosts := make([]string, 0, 1<<hostBits)
+	for addr := prefix.Addr(); addr.IsValid() && prefix.Contains(addr); addr = addr.Next() {
+		hosts = append(hosts, addr.String())
+	}
+	if prefix.Addr().Is4() && hostBits > 1 {
+		hosts = hosts[1 : len(hosts)-1]
+	}
+	return hosts, nil
+}
+
+// maxDecompressedSize is the maximum size of the data decompressed by the dsl helpers
+const maxDecompressedSize = 10 * 1024 * 1024
+
+// decompress decompresses data with a reader of the compression format,
+// returning an error for malformed data or data over the maximum size.
+func decompress(data string, newReader func(io.Reader) (io.ReadCloser, error)) (string, error) {
+	reader, err := newReader(strings.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxDecompressedSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(decompressed) > maxDecompressedSize {
+		return "", fmt.Errorf("decompressed data is larger than %d bytes", maxDecompressedSize)
+	}
+	return string(decompressed), nil
+}
+
+// namedDateLayouts are the layouts which can be given by name to the date helpers
+var namedDateLayouts = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"rfc1123":     time.RFC1123,
+	"rfc1123z":    time.RFC1123Z,
+	"rfc850":      time.RFC850,
+	"rfc822":      time.RFC822,
+	"rfc822z":     time.RFC822Z,
+	"ansic":       time.ANSIC,
+	"unixdate":    time.UnixDate,
+	"rubydate":    time.RubyDate,
+	"http":        "Mon, 02 Jan 2006 15:04:05 GMT",
+	"date":        "2006-01-02",
+	"datetime":    "2006-01-02 15:04:05",
+}
+
+// commonDateLayouts are the layouts tried in order to parse dates without a layout
+var commonDateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC1123,
+	time.RFC1123Z,
+	time.RFC850,
+	time.RFC822,
+	time.RFC822Z,
+	time.ANSIC,
+	time.UnixDate,
+	time.RubyDate,
+	"Jan _2 15:04:05 2006 MST",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"20060102150405Z",
+	"2006-01-02",
+	"02 Jan 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
+
+// dateLayout returns the layout of a layout name, or the layout itself
+func dateLayout(layout string) string {
+	if named, ok := namedDateLayouts[strings.ToLower(layout)]; ok {
+		return named
+	}
+	return layout
+}
+
+// parseDate parses a date with a layout, which can be a go layout or a layout
+// name. Without a layout the date is parsed as a unix timestamp or with the
+// common layouts. Dates without a timezone are in UTC.
+func parseDate(date, layout string) (time.Time, error) {
+	if layout != "" {
+		parsed, err := time.Parse(dateLayout(layout), date)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("could not parse date %s with layout %s", date, layout)
+		}
+		return parsed, nil
+	}
+	if seconds, err := strconv.ParseInt(date, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	for _, layout := range commonDateLayouts {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("could not parse date %s", date)
+}
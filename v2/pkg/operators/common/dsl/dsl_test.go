@@ -1,8 +1,14 @@
 package dsl
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"fmt"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/Knetic/govaluate"
 	"github.com/stretchr/testify/assert"
@@ -48,3 +54,195 @@ func testDslExpressionScenarios(t *testing.T, dslExpressions map[string]interfac
 		})
 	}
 }
+
+func TestExpandMapIndexes(t *testing.T) {
+	expressions := map[string]string{
+		`headers["x-powered-by"] == "php"`:     `map_get(headers, "x-powered-by") == "php"`,
+		`contains(headers['server'], "nginx")`: `contains(map_get(headers, 'server'), "nginx")`,
+		`body == "headers[\"server\"]"`:        `body == "headers[\"server\"]"`,
+		`status_code == 200`:                   `status_code == 200`,
+		`a["b"] && c["d\"]"]`:                  `map_get(a, "b") && map_get(c, "d\"]")`,
+	}
+	for expression, expected := range expressions {
+		require.Equal(t, expected, ExpandMapIndexes(expression), "could not expand %q", expression)
+	}
+
+	compiled, err := govaluate.NewEvaluableExpressionWithFunctions(ExpandMapIndexes(`headers["X-Powered-By"] == "php" && headers["x_powered_by"] == "php" && headers["missing"] == ""`), HelperFunctions)
+	require.Nil(t, err, "could not compile expression")
+	result, err := compiled.Evaluate(map[string]interface{}{"headers": map[string]interface{}{"x-powered-by": "php"}})
+	require.Nil(t, err, "could not evaluate expression")
+	require.Equal(t, true, result, "could not get map value")
+}
+
+func TestCertHasSAN(t *testing.T) {
+	expressions := map[string]bool{
+		`cert_has_san(subject_an, "example.com")`:      true,
+		`cert_has_san(subject_an, "WWW.Example.com")`:  true,
+		`cert_has_san(subject_an, "api.internal.com")`: true,
+		`cert_has_san(subject_an, "a.b.internal.com")`: false,
+		`cert_has_san(subject_an, "*.example.com")`:    true,
+		`cert_has_san(subject_an, "*.example.org")`:    false,
+		`cert_has_san(subject_an, "internal.com")`:     false,
+		`cert_has_san(missing_an, "example.com")`:      false,
+	}
+	values := map[string]interface{}{
+		"subject_an": []string{"example.com", "www.example.com", "*.internal.com"},
+		"missing_an": "",
+	}
+	for expression, expected := range expressions {
+		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(expression, HelperFunctions)
+		require.Nil(t, err, "could not compile %q", expression)
+		result, err := compiled.Evaluate(values)
+		require.Nil(t, err, "could not evaluate %q", expression)
+		require.Equal(t, expected, result, "could not match san for %q", expression)
+	}
+}
+
+func TestIPInCIDR(t *testing.T) {
+	expressions := map[string]bool{
+		`ip_in_cidr("10.0.0.1", "10.0.0.0/8")`:        true,
+		`ip_in_cidr("192.168.1.1", "10.0.0.0/8")`:     false,
+		`ip_in_cidr("::ffff:10.1.2.3", "10.0.0.0/8")`: true,
+		`ip_in_cidr("2001:db8::1", "2001:db8::/32")`:  true,
+		`ip_in_cidr("not-an-ip", "10.0.0.0/8")`:       false,
+		`ip_in_cidr(" 172.16.5.4 ", "172.16.0.0/12")`: true,
+		`ip_in_cidr("172.32.0.1", "172.16.0.0/12")`:   false,
+	}
+	for expression, expected := range expressions {
+		require.Equal(t, expected, evaluateExpression(t, expression), "could not match cidr for %q", expression)
+	}
+
+	compiled, err := govaluate.NewEvaluableExpressionWithFunctions(`ip_in_cidr("10.0.0.1", "10.0.0.0")`, HelperFunctions)
+	require.Nil(t, err, "could not compile expression")
+	_, err = compiled.Evaluate(nil)
+	require.NotNil(t, err, "could not get error for invalid cidr")
+}
+
+func TestCIDRHosts(t *testing.T) {
+	hosts, err := cidrHosts("192.168.1.5/30")
+	require.Nil(t, err, "could not expand cidr")
+	require.Equal(t, []string{"192.168.1.5", "192.168.1.6"}, hosts, "could not get cidr hosts")
+
+	hosts, err = cidrHosts("10.0.0.0/31")
+	require.Nil(t, err, "could not expand cidr")
+	require.Equal(t, []string{"10.0.0.0", "10.0.0.1"}, hosts, "could not get point-to-point cidr hosts")
+
+	hosts, err = cidrHosts("10.0.0.1/32")
+	require.Nil(t, err, "could not expand cidr")
+	require.Equal(t, []string{"10.0.0.1"}, hosts, "could not get single host cidr")
+
+	hosts, err = cidrHosts("2001:db8::/126")
+	require.Nil(t, err, "could not expand cidr")
+	require.Equal(t, []string{"2001:db8::", "2001:db8::1", "2001:db8::2", "2001:db8::3"}, hosts, "could not get ipv6 cidr hosts")
+
+	hosts, err = cidrHosts("10.0.0.0/16")
+	require.Nil(t, err, "could not expand cidr")
+	require.Len(t, hosts, maxCIDRHosts-2, "could not get all cidr hosts")
+
+	_, err = cidrHosts("10.0.0.0/15")
+	require.NotNil(t, err, "could not get error for cidr over limit")
+	_, err = cidrHosts("2001:db8::/64")
+	require.NotNil(t, err, "could not get error for ipv6 cidr over limit")
+	_, err = cidrHosts("10.0.0.1")
+	require.NotNil(t, err, "could not get error for invalid cidr")
+
+	result := evaluateExpression(t, `cidr_hosts("10.0.0.0/29")`)
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.6"}, result, "could not expand cidr with dsl")
+}
+
+func TestDecompressHelpers(t *testing.T) {
+	compress := func(newWriter func(io.Writer) io.WriteCloser) string {
+		buffer := &bytes.Buffer{}
+		writer := newWriter(buffer)
+		_, _ = writer.Write([]byte("nested payload"))
+		_ = writer.Close()
+		return buffer.String()
+	}
+	values := map[string]interface{}{
+		"gzipped": []byte(compress(func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })),
+		"deflated": compress(func(w io.Writer) io.WriteCloser {
+			writer, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return writer
+		}),
+		"zlibbed": compress(func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }),
+	}
+	for expression, name := range map[string]string{
+		"gunzip(gzipped)":          "gunzip",
+		"inflate(deflated)":        "inflate",
+		"zlib_decompress(zlibbed)": "zlib_decompress",
+	} {
+		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(expression, HelperFunctions)
+		require.Nil(t, err, "could not compile %s", name)
+		result, err := compiled.Evaluate(values)
+		require.Nil(t, err, "could not evaluate %s", name)
+		require.Equal(t, "nested payload", result, "could not decompress with %s", name)
+	}
+
+	for _, expression := range []string{`gunzip("not compressed")`, `inflate("not compressed")`, `zlib_decompress("not compressed")`} {
+		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(expression, HelperFunctions)
+		require.Nil(t, err, "could not compile expression")
+		_, err = compiled.Evaluate(nil)
+		require.NotNil(t, err, "could not get error for malformed data with %s", expression)
+	}
+}
+
+func TestDateHelpers(t *testing.T) {
+	expressions := map[string]interface{}{
+		`date_parse("2023-01-02T15:04:05Z")`:                       float64(1672671845),
+		`date_parse("2023-01-02T17:04:05+02:00")`:                  float64(1672671845),
+		`date_parse("Mon, 02 Jan 2023 15:04:05 GMT")`:              float64(1672671845),
+		`date_parse("Jan  2 15:04:05 2023 GMT")`:                   float64(1672671845),
+		`date_parse("2023-01-02")`:                                 float64(1672617600),
+		`date_parse("1672671845")`:                                 float64(1672671845),
+		`date_parse("02/01/2023 15:04", "02/01/2006 15:04")`:       float64(1672671840),
+		`date_parse("Mon, 02 Jan 2023 15:04:05 GMT", "rfc1123")`:   float64(1672671845),
+		`date_format(1672671845)`:                                  "2023-01-02T15:04:05Z",
+		`date_format("1672671845", "http")`:                        "Mon, 02 Jan 2023 15:04:05 GMT",
+		`date_format(1672671845, "2006/01/02")`:                    "2023/01/02",
+		`date_format(date_parse("2023-01-02 15:04:05"), "date")`:   "2023-01-02",
+		`date_parse("2023-01-02T15:04:05Z") < now()`:               true,
+		`date_parse("2023-01-02T15:04:05Z") + 86400 > now() - 300`: false,
+	}
+	for expression, expected := range expressions {
+		require.Equal(t, expected, evaluateExpression(t, expression), "could not evaluate %q", expression)
+	}
+
+	current := evaluateExpression(t, "now()")
+	require.InDelta(t, float64(time.Now().Unix()), current, 5, "could not get current time")
+
+	for _, expression := range []string{`date_parse("not a date")`, `date_parse("2023-01-02", "rfc1123")`, `date_format("not a time")`} {
+		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(expression, HelperFunctions)
+		require.Nil(t, err, "could not compile expression")
+		_, err = compiled.Evaluate(nil)
+		require.NotNil(t, err, "could not get error for %s", expression)
+	}
+}
+
+func TestSetRandomSeed(t *testing.T) {
+	original := make(map[string]govaluate.ExpressionFunction)
+	for name, function := range HelperFunctions {
+		original[name] = function
+	}
+	defer func() {
+		HelperFunctions = original
+	}()
+
+	expressions := []string{`rand_int(10, 1000)`, `rand_base(8, "abc")`, `rand_text_alpha(6, "abcdefghijklmnopqrstuvwxyz")`, `randtextnumeric(4)`, `rand_char("xy")`}
+	generate := func(seed int64) []interface{} {
+		SetRandomSeed(seed)
+		var values []interface{}
+		for _, expression := range expressions {
+			values = append(values, evaluateExpression(t, expression))
+		}
+		return values
+	}
+
+	values := generate(42)
+	require.Equal(t, values, generate(42), "random values not reproducible")
+	require.NotEqual(t, values, generate(7), "random values independent of the seed")
+	require.GreaterOrEqual(t, values[0], 10, "random int out of range")
+	require.Regexp(t, "^[abc]{8}$", values[1], "wrong random base")
+	require.Regexp(t, "^[A-Z]{6}$", values[2], "bad characters not removed")
+	require.Regexp(t, "^[0-9]{4}$", values[3], "wrong random numeric text")
+	require.Regexp(t, "^[xy]$", values[4], "wrong random char")
+}
@@ -0,0 +1,133 @@
+package dsl
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+
+	"github.com/Knetic/govaluate"
+	"github.com/projectdiscovery/dsl"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+const (
+	randomLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	randomNumbers = "1234567890"
+)
+
+// seededRandom generates the values of the random helper functions from a seeded source
+type seededRandom struct {
+	mutex  sync.Mutex
+	source *rand.Rand
+}
+
+// SetRandomSeed makes the values of the rand_char, rand_base, rand_text_alpha,
+// rand_text_alphanumeric, rand_text_numeric and rand_int helper functions
+// reproducible, drawing them from a pseudo random source seeded with seed.
+//
+// Only the expressions compiled after the call use the seeded functions, and
+// the values only repeat across runs if the expressions are evaluated in the
+// same order.
+func SetRandomSeed(seed int64) {
+	random := &seededRandom{source: rand.New(rand.NewSource(seed))}
+	functions := map[string]govaluate.ExpressionFunction{
+		"rand_char":              random.randChar,
+		"rand_base":              random.randBase,
+		"rand_text_alphanumeric": random.randText(randomLetters + randomNumbers),
+		"rand_text_alpha":        random.randText(randomLetters),
+		"rand_text_numeric":      random.randText(randomNumbers),
+		"rand_int":               random.randInt,
+	}
+	for name, function := range functions {
+		HelperFunctions[name] = function
+		// for backwards compatibility
+		HelperFunctions[strings.ReplaceAll(name, "_", "")] = function
+	}
+}
+
+// intn returns a pseudo random number in [0,n)
+func (r *seededRandom) intn(n int) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.source.Intn(n)
+}
+
+// sequence returns a pseudo random sequence of length characters of charset
+func (r *seededRandom) sequence(charset string, length int) string {
+	if charset == "" || length <= 0 {
+		return ""
+	}
+	var builder strings.Builder
+	for i := 0; i < length; i++ {
+		builder.WriteByte(charset[r.intn(len(charset))])
+	}
+	return builder.String()
+}
+
+func (r *seededRandom) randChar(args ...interface{}) (interface{}, error) {
+	if len(args) > 1 {
+		return nil, dsl.ErrInvalidDslFunction
+	}
+	charset := randomLetters + randomNumbers
+	if len(args) == 1 && strings.TrimSpace(types.ToString(args[0])) != "" {
+		charset = types.ToString(args[0])
+	}
+	return r.sequence(charset, 1), nil
+}
+
+func (r *seededRandom) randBase(args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, dsl.ErrInvalidDslFunction
+	}
+	length, ok := args[0].(float64)
+	if !ok {
+		return nil, dsl.ErrInvalidDslFunction
+	}
+	charset := randomLetters + randomNumbers
+	if len(args) == 2 && strings.TrimSpace(types.ToString(args[1])) != "" {
+		charset = types.ToString(args[1])
+	}
+	return r.sequence(charset, int(length)), nil
+}
+
+// randText returns a helper generating a sequence of the charset without the bad characters
+func (r *seededRandom) randText(charset string) govaluate.ExpressionFunction {
+	return func(args ...interface{}) (interface{}, error) {
+		if len(args) < 1 || len(args) > 2 {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		length, ok := args[0].(float64)
+		if !ok {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		chars := charset
+		if len(args) == 2 {
+			chars = dsl.TrimAll(chars, types.ToString(args[1]))
+		}
+		return r.sequence(chars, int(length)), nil
+	}
+}
+
+func (r *seededRandom) randInt(args ...interface{}) (interface{}, error) {
+	if len(args) > 2 {
+		return nil, dsl.ErrInvalidDslFunction
+	}
+	min, max := 0, math.MaxInt32
+	for i, arg := range args {
+		value, ok := arg.(float64)
+		if !ok {
+			return nil, dsl.ErrInvalidDslFunction
+		}
+		if i == 0 {
+			min = int(value)
+		} else {
+			max = int(value)
+		}
+	}
+	if max <= min {
+		return nil, dsl.ErrInvalidDslFunction
+	}
+	return min + r.intn(max-min), nil
+}
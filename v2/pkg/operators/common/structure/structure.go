@@ -0,0 +1,141 @@
+package structure
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	// shingleSize is the number of consecutive tags of a shingle
+	shingleSize = 4
+	// permutations is the number of minhash values of a fingerprint
+	permutations = 64
+)
+
+// FingerprintLength is the length of an hex encoded fingerprint
+const FingerprintLength = permutations * 4
+
+// Fingerprint is the structural fingerprint of an html document, made of the
+// minhash values of the shingles of its tag tree.
+//
+// The proportion of values two fingerprints have in common estimates the
+// jaccard similarity of the shingles of their documents.
+type Fingerprint [permutations]uint16
+
+// New returns the structural fingerprint of an html document.
+//
+// The document is parsed as browsers do and its elements are serialized as
+// opening and closing tags in document order, ignoring text, attributes and
+// comments, the tag sequence being split in overlapping shingles of 4 tags.
+func New(document string) Fingerprint {
+	var fingerprint Fingerprint
+	shingles := shingleHashes(tags(document))
+	for i := range fingerprint {
+		seed := uint64(i+1) * 0x9e3779b97f4a7c15
+		lowest := ^uint64(0)
+		for shingle := range shingles {
+			if value := mix(shingle ^ seed); value < lowest {
+				lowest = value
+			}
+		}
+		fingerprint[i] = uint16(lowest >> 48)
+	}
+	return fingerprint
+}
+
+// Parse parses an hex encoded fingerprint
+func Parse(value string) (Fingerprint, error) {
+	var fingerprint Fingerprint
+	if len(value) != FingerprintLength {
+		return fingerprint, fmt.Errorf("fingerprint must be %d hex characters long", FingerprintLength)
+	}
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return fingerprint, fmt.Errorf("could not hex decode fingerprint: %s", err)
+	}
+	for i := range fingerprint {
+		fingerprint[i] = binary.BigEndian.Uint16(decoded[i*2:])
+	}
+	return fingerprint, nil
+}
+
+// String returns the hex encoded fingerprint
+func (f Fingerprint) String() string {
+	data := make([]byte, permutations*2)
+	for i, value := range f {
+		binary.BigEndian.PutUint16(data[i*2:], value)
+	}
+	return hex.EncodeToString(data)
+}
+
+// Similarity returns the similarity between two fingerprints from 0 for
+// unrelated structures to 1 for identical ones.
+func (f Fingerprint) Similarity(other Fingerprint) float64 {
+	var common int
+	for i := range f {
+		if f[i] == other[i] {
+			common++
+		}
+	}
+	return float64(common) / permutations
+}
+
+// tags returns the opening and closing tags of the elements of a document
+func tags(document string) []string {
+	root, err := html.Parse(strings.NewReader(document))
+	if err != nil {
+		return nil
+	}
+	var sequence []string
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode {
+			sequence = append(sequence, node.Data)
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if node.Type == html.ElementNode {
+			sequence = append(sequence, "/"+node.Data)
+		}
+	}
+	walk(root)
+	return sequence
+}
+
+// shingleHashes returns the unique hashes of the shingles of a tag sequence,
+// a sequence shorter than a shingle being a shingle itself
+func shingleHashes(sequence []string) map[uint64]struct{} {
+	hashes := make(map[uint64]struct{})
+	for start := 0; start == 0 || start+shingleSize <= len(sequence); start++ {
+		end := start + shingleSize
+		if end > len(sequence) {
+			end = len(sequence)
+		}
+		if end == start {
+			break
+		}
+		hasher := fnv.New64a()
+		for _, tag := range sequence[start:end] {
+			_, _ = hasher.Write([]byte(tag))
+			_, _ = hasher.Write([]byte{0})
+		}
+		hashes[hasher.Sum64()] = struct{}{}
+	}
+	return hashes
+}
+
+// mix is the splitmix64 finalizer, used to derive the permutations of the hashes
+func mix(value uint64) uint64 {
+	value ^= value >> 30
+	value *= 0xbf58476d1ce4e5b9
+	value ^= value >> 27
+	value *= 0x94d049bb133111eb
+	value ^= value >> 31
+	return value
+}
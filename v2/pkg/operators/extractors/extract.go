@@ -8,6 +8,8 @@ import (
 	"github.com/antchfx/htmlquery"
 	"github.com/antchfx/xmlquery"
 
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/decode"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/structure"
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
 )
 
@@ -15,23 +17,42 @@ import (
 func (e *Extractor) ExtractRegex(corpus string) map[string]struct{} {
 	results := make(map[string]struct{})
 
+	if e.AutoDecode {
+		for _, region := range decode.Regions(corpus) {
+			e.extractRegex(region, results)
+		}
+	} else {
+		e.extractRegex(corpus, results)
+	}
+	e.SaveToFile(results)
+	return results
+}
+
+// extractRegex adds the regex matches in the corpus to the results
+func (e *Extractor) extractRegex(corpus string, results map[string]struct{}) {
 	groupPlusOne := e.RegexGroup + 1
 	for _, regex := range e.regexCompiled {
-		matches := regex.FindAllStringSubmatch(corpus, -1)
+		matches := regex.FindAllStringSubmatchIndex(corpus, -1)
 
 		for _, match := range matches {
-			if len(match) < groupPlusOne {
+			if len(match) < groupPlusOne*2 {
 				continue
 			}
-			matchString := match[e.RegexGroup]
+			start, end := match[e.RegexGroup*2], match[e.RegexGroup*2+1]
+			// optional named groups not taking part in the match are skipped
+			if start < 0 && e.namedGroup {
+				continue
+			}
+			var matchString string
+			if start >= 0 {
+				matchString = corpus[start:end]
+			}
 
 			if _, ok := results[matchString]; !ok {
 				results[matchString] = struct{}{}
 			}
 		}
 	}
-	e.SaveToFile(results)
-	return results
 }
 
 // ExtractKval extracts key value pairs from a data map
@@ -51,7 +72,12 @@ func (e *Extractor) ExtractKval(data map[string]interface{}) map[string]struct{}
 	for _, k := range e.KVal {
 		item, ok := data[k]
 		if !ok {
-			continue
+			if item, ok = headerValue(data, k); !ok {
+				continue
+			}
+			if e.CaseInsensitive {
+				item = strings.ToLower(types.ToString(item))
+			}
 		}
 		itemString := types.ToString(item)
 		if _, ok := results[itemString]; !ok {
@@ -62,6 +88,17 @@ func (e *Extractor) ExtractKval(data map[string]interface{}) map[string]struct{}
 	return results
 }
 
+// headerValue returns the value of a header by name from the headers map
+// of a response, looked up case-insensitively with '_' matching '-'.
+func headerValue(data map[string]interface{}, name string) (interface{}, bool) {
+	headers, ok := data["headers"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	item, ok := headers[strings.ReplaceAll(strings.ToLower(name), "_", "-")]
+	return item, ok
+}
+
 // ExtractXPath extracts items from text using XPath selectors
 func (e *Extractor) ExtractXPath(corpus string) map[string]struct{} {
 	if strings.HasPrefix(corpus, "<?xml") {
@@ -190,3 +227,15 @@ func (e *Extractor) ExtractDSL(data map[string]interface{}) map[string]struct{}
 	e.SaveToFile(results)
 	return results
 }
+
+// ExtractStructure extracts the structural fingerprint of an html corpus,
+// to be compared against by structure matchers
+func (e *Extractor) ExtractStructure(corpus string) map[string]struct{} {
+	results := make(map[string]struct{})
+	if strings.TrimSpace(corpus) == "" {
+		return results
+	}
+	results[structure.New(corpus).String()] = struct{}{}
+	e.SaveToFile(results)
+	return results
+}
@@ -23,16 +23,19 @@ const (
 	JSONExtractor
 	// name:dsl
 	DSLExtractor
+	// name:structure
+	StructureExtractor
 	limit
 )
 
 // extractorMappings is a table for conversion of extractor type from string.
 var extractorMappings = map[ExtractorType]string{
-	RegexExtractor: "regex",
-	KValExtractor:  "kval",
-	XPathExtractor: "xpath",
-	JSONExtractor:  "json",
-	DSLExtractor:   "dsl",
+	RegexExtractor:     "regex",
+	KValExtractor:      "kval",
+	XPathExtractor:     "xpath",
+	JSONExtractor:      "json",
+	DSLExtractor:       "dsl",
+	StructureExtractor: "structure",
 }
 
 // GetType returns the type of the matcher
@@ -30,6 +30,9 @@ type Extractor struct {
 	//
 	//   Go regex engine does not support lookaheads or lookbehinds, so as a result
 	//   they are also not supported in nuclei.
+	//
+	//   Named capture groups (?P<name>...) are additionally extracted
+	//   into distinct variables having the name of the group.
 	// examples:
 	//   - name: Braintree Access Token Regex
 	//     value: >
@@ -46,6 +49,10 @@ type Extractor struct {
 	RegexGroup int `yaml:"group,omitempty" json:"group,omitempty" jsonschema:"title=group to extract from regex,description=Group to extract from regex"`
 	// regexCompiled is the compiled variant
 	regexCompiled []*regexp.Regexp
+	// namedGroupExtractors contains an extractor for each named group of the regexes
+	namedGroupExtractors []*Extractor
+	// namedGroup is true for the extractors of named groups
+	namedGroup bool
 
 	// description: |
 	//   kval contains the key-value pairs present in the HTTP response header.
@@ -118,10 +125,25 @@ type Extractor struct {
 	//   - true
 	CaseInsensitive bool `yaml:"case-insensitive,omitempty" json:"case-insensitive,omitempty" jsonschema:"title=use case insensitive extract,description=use case insensitive extract"`
 	// description: |
+	//   AutoDecode runs the regexes on the decoded content of the base64 and hex
+	//   encoded substrings of the part instead of on the part itself.
+	//
+	//   Each decoded region is searched separately, a `.+` regex extracting it whole.
+	// values:
+	//   - false
+	//   - true
+	AutoDecode bool `yaml:"auto-decode,omitempty" json:"auto-decode,omitempty" jsonschema:"title=extract from decoded base64 and hex data,description=AutoDecode runs the regexes on the decoded content of the base64 and hex encoded substrings of the part"`
+	// description: |
 	//  ToFile (to) saves extracted requests to file and if file is present values are appended to file.
 	ToFile string `yaml:"to,omitempty" json:"to,omitempty" jsonschema:"title=save extracted values to file,description=save extracted values to file"`
 }
 
+// NamedGroupExtractors returns the extractors for the named capture groups of
+// the regexes, each one extracting its group into a variable with the group name.
+func (e *Extractor) NamedGroupExtractors() []*Extractor {
+	return e.namedGroupExtractors
+}
+
 // SaveToFile saves extracted values to file if `to` is present and valid
 func (e *Extractor) SaveToFile(data map[string]struct{}) {
 	if e.ToFile == "" {
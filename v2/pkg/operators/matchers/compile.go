@@ -3,12 +3,16 @@ package matchers
 import (
 	"encoding/hex"
 	"fmt"
+	"io"
 	"regexp"
 	"strings"
 
 	"github.com/Knetic/govaluate"
+	"github.com/pkg/errors"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 
 	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/structure"
 )
 
 // CompileMatchers performs the initial setup operation on a matcher
@@ -62,13 +66,38 @@ func (matcher *Matcher) CompileMatchers() error {
 
 	// Compile the dsl expressions
 	for _, dslExpression := range matcher.DSL {
-		compiledExpression, err := govaluate.NewEvaluableExpressionWithFunctions(dslExpression, dsl.HelperFunctions)
+		compiledExpression, err := govaluate.NewEvaluableExpressionWithFunctions(dsl.ExpandMapIndexes(dslExpression), dsl.HelperFunctions)
 		if err != nil {
 			return &dsl.CompilationError{DslSignature: dslExpression, WrappedError: err}
 		}
 		matcher.dslCompiled = append(matcher.dslCompiled, compiledExpression)
 	}
 
+	// Compile the json schema
+	if matcher.Schema != "" {
+		compiled, err := compileJSONSchema(matcher.Schema)
+		if err != nil {
+			return errors.Wrap(err, "could not compile json schema")
+		}
+		matcher.jsonSchema = compiled
+	}
+
+	// Parse the structural fingerprints
+	for _, value := range matcher.Fingerprints {
+		fingerprint, err := structure.Parse(value)
+		if err != nil {
+			return errors.Wrapf(err, "could not parse fingerprint %s", value)
+		}
+		matcher.fingerprints = append(matcher.fingerprints, fingerprint)
+	}
+	if matcher.GetType() == StructureMatcher && matcher.MinSimilarity == 0 {
+		matcher.MinSimilarity = defaultMinSimilarity
+	}
+
+	if matcher.MinMatches < 0 || matcher.MinMatches > len(matcher.Words)+len(matcher.Regex) {
+		return fmt.Errorf("min-matches must be between 1 and the number of matcher values: %d", matcher.MinMatches)
+	}
+
 	// Set up the condition type, if any.
 	if matcher.Condition != "" {
 		matcher.condition, ok = ConditionTypes[matcher.Condition]
@@ -89,3 +118,22 @@ func (matcher *Matcher) CompileMatchers() error {
 	}
 	return nil
 }
+
+// defaultMinSimilarity is the minimum similarity of the structure matchers not specifying it
+const defaultMinSimilarity = 0.8
+
+// jsonSchemaURL is the url the schema of a matcher is compiled as
+const jsonSchemaURL = "matcher.schema.json"
+
+// compileJSONSchema compiles a json schema, not allowing references to
+// remote or local schemas.
+func compileJSONSchema(schema string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("external schema references are not supported: %s", url)
+	}
+	if err := compiler.AddResource(jsonSchemaURL, strings.NewReader(schema)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(jsonSchemaURL)
+}
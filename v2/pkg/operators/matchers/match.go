@@ -1,12 +1,18 @@
 package matchers
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/Knetic/govaluate"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/decode"
 	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/structure"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/expressions"
 )
 
@@ -44,10 +50,24 @@ func (matcher *Matcher) MatchSize(length int) bool {
 
 // MatchWords matches a word check against a corpus.
 func (matcher *Matcher) MatchWords(corpus string, data map[string]interface{}) (bool, []string) {
+	corpus = matcher.decodedCorpus(corpus)
 	if matcher.CaseInsensitive {
 		corpus = strings.ToLower(corpus)
 	}
 
+	if matcher.MinMatches > 0 {
+		return matcher.matchMinimum(len(matcher.Words), func(i int) []string {
+			word, err := expressions.Evaluate(matcher.Words[i], data)
+			if err != nil {
+				gologger.Warning().Msgf("Error while evaluating word matcher: %q", word)
+			}
+			if strings.Contains(corpus, word) {
+				return []string{word}
+			}
+			return nil
+		})
+	}
+
 	var matchedWords []string
 	// Iterate over all the words accepted as valid
 	for i, word := range matcher.Words {
@@ -92,8 +112,28 @@ func (matcher *Matcher) MatchWords(corpus string, data map[string]interface{}) (
 	return false, []string{}
 }
 
+// decodedCorpus appends the decoded base64 and hex encoded regions
+// of the corpus to it if auto decoding is enabled.
+func (matcher *Matcher) decodedCorpus(corpus string) string {
+	if !matcher.AutoDecode {
+		return corpus
+	}
+	regions := decode.Regions(corpus)
+	if len(regions) == 0 {
+		return corpus
+	}
+	return corpus + "\n" + strings.Join(regions, "\n")
+}
+
 // MatchRegex matches a regex check against a corpus
 func (matcher *Matcher) MatchRegex(corpus string) (bool, []string) {
+	corpus = matcher.decodedCorpus(corpus)
+	if matcher.MinMatches > 0 {
+		return matcher.matchMinimum(len(matcher.regexCompiled), func(i int) []string {
+			return matcher.regexCompiled[i].FindAllString(corpus, -1)
+		})
+	}
+
 	var matchedRegexes []string
 	// Iterate over all the regexes accepted as valid
 	for i, regex := range matcher.regexCompiled {
@@ -128,6 +168,23 @@ func (matcher *Matcher) MatchRegex(corpus string) (bool, []string) {
 	return false, []string{}
 }
 
+// matchMinimum returns true if at least MinMatches of the count matcher values
+// are matched by match, which returns the matches of the value at an index.
+func (matcher *Matcher) matchMinimum(count int, match func(i int) []string) (bool, []string) {
+	var hits int
+	var matched []string
+	for i := 0; i < count; i++ {
+		if values := match(i); len(values) > 0 {
+			hits++
+			matched = append(matched, values...)
+		}
+	}
+	if hits < matcher.MinMatches {
+		return false, []string{}
+	}
+	return true, matched
+}
+
 // MatchBinary matches a binary check against a corpus
 func (matcher *Matcher) MatchBinary(corpus string) (bool, []string) {
 	var matchedBinary []string
@@ -219,3 +276,137 @@ func (matcher *Matcher) MatchDSL(data map[string]interface{}) bool {
 	}
 	return false
 }
+
+// MatchJSONSchema validates a json corpus against the schema of the matcher.
+//
+// It returns true if the corpus conforms to the schema, or if it doesn't
+// conform and the matcher matches invalid json, along with the validation errors.
+func (matcher *Matcher) MatchJSONSchema(corpus string) (bool, []string) {
+	if matcher.jsonSchema == nil {
+		return false, []string{}
+	}
+	var validationErrors []string
+	decoder := json.NewDecoder(strings.NewReader(corpus))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		validationErrors = []string{"invalid json: " + err.Error()}
+	} else if err := matcher.jsonSchema.Validate(value); err != nil {
+		validationErrors = jsonSchemaErrors(err)
+	}
+	if matcher.Invalid {
+		return len(validationErrors) > 0, validationErrors
+	}
+	return len(validationErrors) == 0, []string{}
+}
+
+// MatchDifferential compares a corpus against its baseline, matching if their
+// similarity is below the maximum similarity or if their lengths differ by at
+// least the minimum length delta, combined with the condition of the matcher.
+//
+// The computed delta is returned along with the result.
+func (matcher *Matcher) MatchDifferential(corpus, baseline string) (bool, []string) {
+	similarity := Similarity(corpus, baseline)
+	lengthDelta := len(corpus) - len(baseline)
+
+	var results []bool
+	if matcher.MaxSimilarity > 0 {
+		results = append(results, similarity < matcher.MaxSimilarity)
+	}
+	if matcher.MinLengthDelta > 0 {
+		results = append(results, lengthDelta >= matcher.MinLengthDelta || -lengthDelta >= matcher.MinLengthDelta)
+	}
+	// all the results must be true with an and condition, any of them otherwise
+	matched := matcher.condition == ANDCondition
+	for _, result := range results {
+		if matcher.condition == ANDCondition {
+			matched = matched && result
+		} else {
+			matched = matched || result
+		}
+	}
+	if !matched {
+		return false, []string{}
+	}
+	return true, []string{fmt.Sprintf("%s/%s similarity:%.2f length-delta:%+d", matcher.Part, matcher.Baseline, similarity, lengthDelta)}
+}
+
+// MatchStructure compares the structure of an html corpus against the fingerprints,
+// matching if their similarity is at least the minimum similarity, with any of the
+// fingerprints or all of them depending on the condition of the matcher.
+//
+// The similarity with the matching fingerprints is returned along with the result.
+func (matcher *Matcher) MatchStructure(corpus string) (bool, []string) {
+	fingerprint := structure.New(corpus)
+
+	var matched []string
+	for i, other := range matcher.fingerprints {
+		similarity := fingerprint.Similarity(other)
+		if similarity < matcher.MinSimilarity {
+			if matcher.condition == ANDCondition {
+				return false, []string{}
+			}
+			continue
+		}
+		matched = append(matched, fmt.Sprintf("%s/fingerprint-%d similarity:%.2f", matcher.Part, i+1, similarity))
+	}
+	if len(matched) == 0 {
+		return false, []string{}
+	}
+	return true, matched
+}
+
+// Similarity returns the similarity between two contents from 0 to 1, computed
+// as the dice coefficient of their words: twice the number of words in common
+// divided by the total number of words. Empty contents are identical.
+func Similarity(a, b string) float64 {
+	wordsA, wordsB := similarityWords(a), similarityWords(b)
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1
+	}
+	counts := make(map[string]int, len(wordsA))
+	for _, word := range wordsA {
+		counts[word]++
+	}
+	var common int
+	for _, word := range wordsB {
+		if counts[word] > 0 {
+			counts[word]--
+			common++
+		}
+	}
+	return float64(2*common) / float64(len(wordsA)+len(wordsB))
+}
+
+// similarityWords splits a content into words made of letters and digits
+func similarityWords(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// jsonSchemaErrors returns the leaf errors of a json schema validation error
+// prefixed by the location of the invalid value.
+func jsonSchemaErrors(err error) []string {
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return []string{err.Error()}
+	}
+	var errs []string
+	var walk func(*jsonschema.ValidationError)
+	walk = func(validationErr *jsonschema.ValidationError) {
+		if len(validationErr.Causes) == 0 {
+			location := validationErr.InstanceLocation
+			if location == "" {
+				location = "/"
+			}
+			errs = append(errs, location+": "+validationErr.Message)
+			return
+		}
+		for _, cause := range validationErr.Causes {
+			walk(cause)
+		}
+	}
+	walk(validationErr)
+	return errs
+}
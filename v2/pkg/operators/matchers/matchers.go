@@ -4,13 +4,16 @@ import (
 	"regexp"
 
 	"github.com/Knetic/govaluate"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/structure"
 )
 
 // Matcher is used to match a part in the output from a protocol.
 type Matcher struct {
 	// description: |
 	//   Type is the type of the matcher.
-	Type MatcherTypeHolder `yaml:"type" json:"type" jsonschema:"title=type of matcher,description=Type of the matcher,enum=status,enum=size,enum=word,enum=regex,enum=binary,enum=dsl"`
+	Type MatcherTypeHolder `yaml:"type" json:"type" jsonschema:"title=type of matcher,description=Type of the matcher,enum=status,enum=size,enum=word,enum=regex,enum=binary,enum=dsl,enum=jsonschema,enum=differential,enum=structure"`
 	// description: |
 	//   Condition is the optional condition between two matcher variables. By default,
 	//   the condition is assumed to be OR.
@@ -94,6 +97,65 @@ type Matcher struct {
 	//       []string{"!contains(tolower(all_headers), ''strict-transport-security'')"}
 	DSL []string `yaml:"dsl,omitempty" json:"dsl,omitempty" jsonschema:"title=dsl expressions to match in response,description=DSL are the dsl expressions that will be evaluated as part of nuclei matching rules"`
 	// description: |
+	//   Schema is the JSON Schema the response part is validated against.
+	//
+	//   The part matches if it is valid json conforming to the schema, or if
+	//   it doesn't conform when invalid is set. References to other schemas
+	//   must be defined in the schema itself.
+	// examples:
+	//   - name: Match for a user object with a numeric id
+	//     value: >
+	//       "{\"type\": \"object\", \"required\": [\"id\"], \"properties\": {\"id\": {\"type\": \"integer\"}}}"
+	Schema string `yaml:"schema,omitempty" json:"schema,omitempty" jsonschema:"title=json schema to validate response,description=JSON Schema the response part is validated against"`
+	// description: |
+	//   Invalid matches the response part if it doesn't conform to the schema.
+	//
+	//   The validation errors are reported with the result.
+	Invalid bool `yaml:"invalid,omitempty" json:"invalid,omitempty" jsonschema:"title=match invalid json,description=Invalid matches the response part if it doesn't conform to the schema"`
+	// description: |
+	//   Baseline is the part the response part is compared against by differential matchers.
+	//
+	//   It usually is a part of the response of a previous request, referenced
+	//   either by its number with req-condition (e.g. body_1) or by its id (e.g. baseline_body).
+	// examples:
+	//   - name: Compare against the body of the first request
+	//     value: "\"body_1\""
+	Baseline string `yaml:"baseline,omitempty" json:"baseline,omitempty" jsonschema:"title=baseline part to compare against,description=Baseline is the part the response part is compared against by differential matchers"`
+	// description: |
+	//   MaxSimilarity matches if the similarity between the part and the baseline is below it.
+	//
+	//   The similarity goes from 0 for entirely different contents to 1 for identical ones,
+	//   and is computed as the proportion of words the part and the baseline have in common.
+	// examples:
+	//   - name: Match if less than 90% of the words are in common
+	//     value: 0.9
+	MaxSimilarity float64 `yaml:"max-similarity,omitempty" json:"max-similarity,omitempty" jsonschema:"title=maximum similarity with baseline,description=MaxSimilarity matches if the similarity between the part and the baseline is below it"`
+	// description: |
+	//   MinLengthDelta matches if the length of the part differs from the
+	//   length of the baseline by at least the specified number of bytes.
+	// examples:
+	//   - name: Match if the lengths differ by at least 100 bytes
+	//     value: 100
+	MinLengthDelta int `yaml:"min-length-delta,omitempty" json:"min-length-delta,omitempty" jsonschema:"title=minimum length difference with baseline,description=MinLengthDelta matches if the length of the part differs from the length of the baseline by at least the specified number of bytes"`
+	// description: |
+	//   Fingerprints are the structural fingerprints of html documents the part is compared against by structure matchers.
+	//
+	//   A fingerprint only depends on the tag tree of a document, ignoring its text and
+	//   attributes, and is obtained by running a structure extractor on the document.
+	// examples:
+	//   - name: Compare against the fingerprint of a login page
+	//     value: >
+	//       []string{"1a7b221c03a9054d021b0c6d07be282f062b02e708a52d671db50aea0e72017903a20e174df51d2a0477035a1814065c0ac814a41b12097a04150282123404f303d52a85233b31f711702d06085d1ae018b40250102c1f1b0aad491721060aa629b00bea05b6039b1a8731ad29103d181b1105f0000313211e941d9f16b51a12"}
+	Fingerprints []string `yaml:"fingerprints,omitempty" json:"fingerprints,omitempty" jsonschema:"title=structural fingerprints to compare against,description=Fingerprints are the structural fingerprints of html documents the part is compared against by structure matchers"`
+	// description: |
+	//   MinSimilarity matches if the structural similarity between the part and the fingerprints is at least it.
+	//
+	//   The similarity goes from 0 for unrelated structures to 1 for identical ones. Default is 0.8.
+	// examples:
+	//   - name: Match if the structures are at least 90% similar
+	//     value: 0.9
+	MinSimilarity float64 `yaml:"min-similarity,omitempty" json:"min-similarity,omitempty" jsonschema:"title=minimum structural similarity,description=MinSimilarity matches if the structural similarity between the part and the fingerprints is at least it"`
+	// description: |
 	//   Encoding specifies the encoding for the words field if any.
 	// values:
 	//   - "hex"
@@ -110,6 +172,22 @@ type Matcher struct {
 	//   - false
 	//   - true
 	MatchAll bool `yaml:"match-all,omitempty" json:"match-all,omitempty" jsonschema:"title=match all values,description=match all matcher values ignoring condition"`
+	// description: |
+	//   MinMatches is the minimum number of words or regexes which must match
+	//   for the matcher to match, ignoring condition.
+	// examples:
+	//   - name: Match if at least two of the words are present
+	//     value: 2
+	MinMatches int `yaml:"min-matches,omitempty" json:"min-matches,omitempty" jsonschema:"title=minimum number of values to match,description=Minimum number of matcher values which must match ignoring condition"`
+	// description: |
+	//   AutoDecode additionally matches the words or regexes against the decoded
+	//   content of the base64 and hex encoded substrings of the part.
+	//
+	//   Only substrings decoding to printable text are considered.
+	// values:
+	//   - false
+	//   - true
+	AutoDecode bool `yaml:"auto-decode,omitempty" json:"auto-decode,omitempty" jsonschema:"title=match decoded base64 and hex data,description=AutoDecode additionally matches the decoded content of the base64 and hex encoded substrings of the part"`
 
 	// cached data for the compiled matcher
 	condition     ConditionType
@@ -117,6 +195,8 @@ type Matcher struct {
 	binaryDecoded []string
 	regexCompiled []*regexp.Regexp
 	dslCompiled   []*govaluate.EvaluableExpression
+	jsonSchema    *jsonschema.Schema
+	fingerprints  []structure.Fingerprint
 }
 
 // ConditionType is the type of condition for matcher
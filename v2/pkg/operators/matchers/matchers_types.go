@@ -25,17 +25,26 @@ const (
 	SizeMatcher
 	// name:dsl
 	DSLMatcher
+	// name:jsonschema
+	JSONSchemaMatcher
+	// name:differential
+	DifferentialMatcher
+	// name:structure
+	StructureMatcher
 	limit
 )
 
 // MatcherTypes is a table for conversion of matcher type from string.
 var MatcherTypes = map[MatcherType]string{
-	StatusMatcher: "status",
-	SizeMatcher:   "size",
-	WordsMatcher:  "word",
-	RegexMatcher:  "regex",
-	BinaryMatcher: "binary",
-	DSLMatcher:    "dsl",
+	StatusMatcher:       "status",
+	SizeMatcher:         "size",
+	WordsMatcher:        "word",
+	RegexMatcher:        "regex",
+	BinaryMatcher:       "binary",
+	DSLMatcher:          "dsl",
+	JSONSchemaMatcher:   "jsonschema",
+	DifferentialMatcher: "differential",
+	StructureMatcher:    "structure",
 }
 
 // GetType returns the type of the matcher
@@ -33,11 +33,35 @@ func (matcher *Matcher) Validate() error {
 	case SizeMatcher:
 		expectedFields = append(commonExpectedFields, "Size", "Part")
 	case WordsMatcher:
-		expectedFields = append(commonExpectedFields, "Words", "Part", "Encoding", "CaseInsensitive")
+		expectedFields = append(commonExpectedFields, "Words", "Part", "Encoding", "CaseInsensitive", "MinMatches", "AutoDecode")
 	case BinaryMatcher:
 		expectedFields = append(commonExpectedFields, "Binary", "Part", "Encoding", "CaseInsensitive")
 	case RegexMatcher:
-		expectedFields = append(commonExpectedFields, "Regex", "Part", "Encoding", "CaseInsensitive")
+		expectedFields = append(commonExpectedFields, "Regex", "Part", "Encoding", "CaseInsensitive", "MinMatches", "AutoDecode")
+	case JSONSchemaMatcher:
+		if matcher.Schema == "" {
+			return errors.New("matcher jsonschema requires a schema")
+		}
+		expectedFields = append(commonExpectedFields, "Schema", "Invalid", "Part")
+	case DifferentialMatcher:
+		if matcher.Baseline == "" {
+			return errors.New("matcher differential requires a baseline")
+		}
+		if matcher.MaxSimilarity <= 0 && matcher.MinLengthDelta <= 0 {
+			return errors.New("matcher differential requires a max-similarity or a min-length-delta")
+		}
+		if matcher.MaxSimilarity > 1 {
+			return fmt.Errorf("max-similarity must be between 0 and 1: %v", matcher.MaxSimilarity)
+		}
+		expectedFields = append(commonExpectedFields, "Baseline", "MaxSimilarity", "MinLengthDelta", "Part")
+	case StructureMatcher:
+		if len(matcher.Fingerprints) == 0 {
+			return errors.New("matcher structure requires fingerprints")
+		}
+		if matcher.MinSimilarity < 0 || matcher.MinSimilarity > 1 {
+			return fmt.Errorf("min-similarity must be between 0 and 1: %v", matcher.MinSimilarity)
+		}
+		expectedFields = append(commonExpectedFields, "Fingerprints", "MinSimilarity", "Part")
 	}
 	return checkFields(matcher, matcherMap, expectedFields...)
 }
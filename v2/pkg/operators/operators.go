@@ -34,6 +34,16 @@ type Operators struct {
 	//   - "and"
 	//   - "or"
 	MatchersCondition string `yaml:"matchers-condition,omitempty" json:"matchers-condition,omitempty" jsonschema:"title=condition between the matchers,description=Conditions between the matchers,enum=and,enum=or"`
+	// description: |
+	//   MinMatches is the minimum number of matchers which must match,
+	//   taking precedence over the matchers condition.
+	//
+	//   A value of 1 is the same as the `or` condition while the number
+	//   of matchers is the same as the `and` condition.
+	// examples:
+	//   - name: Match if at least two of the matchers match
+	//     value: 2
+	MinMatches int `yaml:"min-matches,omitempty" json:"min-matches,omitempty" jsonschema:"title=minimum number of matchers to match,description=Minimum number of matchers which must match taking precedence over the matchers condition"`
 	// cached variables that may be used along with request.
 	matchersCondition matchers.ConditionType
 
@@ -50,6 +60,9 @@ func (operators *Operators) Compile() error {
 	} else {
 		operators.matchersCondition = matchers.ORCondition
 	}
+	if operators.MinMatches < 0 || operators.MinMatches > len(operators.Matchers) {
+		return fmt.Errorf("min-matches must be between 1 and the number of matchers: %d", operators.MinMatches)
+	}
 
 	for _, matcher := range operators.Matchers {
 		if err := matcher.CompileMatchers(); err != nil {
@@ -90,6 +103,11 @@ type Result struct {
 
 	// Optional lineCounts for file protocol
 	LineCount string
+	// ValidationErrors contains the errors of the jsonschema matchers matching invalid json
+	ValidationErrors []string
+	// Deltas contains the differences with the baseline computed by the differential
+	// matchers and the similarities computed by the structure matchers
+	Deltas []string
 }
 
 func (result *Result) HasMatch(name string) bool {
@@ -193,6 +211,8 @@ func (r *Result) Merge(result *Result) {
 			r.OutputExtracts = append(r.OutputExtracts, v)
 		}
 	}
+	r.ValidationErrors = sliceutil.Dedupe(append(r.ValidationErrors, result.ValidationErrors...))
+	r.Deltas = sliceutil.Dedupe(append(r.Deltas, result.Deltas...))
 	for k, v := range result.DynamicValues {
 		r.DynamicValues[k] = v
 	}
@@ -212,6 +232,7 @@ func (operators *Operators) Execute(data map[string]interface{}, match MatchFunc
 	matcherCondition := operators.GetMatchersCondition()
 
 	var matches bool
+	var hits int
 	result := &Result{
 		Matches:       make(map[string][]string),
 		Extracts:      make(map[string][]string),
@@ -241,6 +262,22 @@ func (operators *Operators) Execute(data map[string]interface{}, match MatchFunc
 		if len(extractorResults) > 0 && !extractor.Internal && extractor.Name != "" {
 			result.Extracts[extractor.Name] = extractorResults
 		}
+
+		// named capture groups are stored as distinct values by group name
+		for _, named := range extractor.NamedGroupExtractors() {
+			var namedResults []string
+			for match := range extract(data, named) {
+				namedResults = append(namedResults, match)
+			}
+			if len(namedResults) == 0 {
+				continue
+			}
+			if named.Internal {
+				result.DynamicValues[named.Name] = append(result.DynamicValues[named.Name], namedResults...)
+			} else {
+				result.Extracts[named.Name] = append(result.Extracts[named.Name], namedResults...)
+			}
+		}
 	}
 
 	// expose dynamic values to same request matchers
@@ -269,16 +306,23 @@ func (operators *Operators) Execute(data map[string]interface{}, match MatchFunc
 			}
 		}
 		if isMatch, matched := match(data, matcher); isMatch {
+			switch matcher.GetType() {
+			case matchers.JSONSchemaMatcher:
+				result.ValidationErrors = append(result.ValidationErrors, matched...)
+			case matchers.DifferentialMatcher, matchers.StructureMatcher:
+				result.Deltas = append(result.Deltas, matched...)
+			}
 			if isDebug { // matchers without an explicit name or with AND condition should only be made visible if debug is enabled
 				matcherName := getMatcherName(matcher, matcherIndex)
 				result.Matches[matcherName] = matched
 			} else { // if it's a "named" matcher with OR condition, then display it
-				if matcherCondition == matchers.ORCondition && matcher.Name != "" {
+				if (matcherCondition == matchers.ORCondition || operators.MinMatches > 0) && matcher.Name != "" {
 					result.Matches[matcher.Name] = matched
 				}
 			}
 			matches = true
-		} else if matcherCondition == matchers.ANDCondition {
+			hits++
+		} else if matcherCondition == matchers.ANDCondition && operators.MinMatches == 0 {
 			if len(result.DynamicValues) > 0 {
 				return result, true
 			}
@@ -286,6 +330,10 @@ func (operators *Operators) Execute(data map[string]interface{}, match MatchFunc
 		}
 	}
 
+	if operators.MinMatches > 0 {
+		matches = hits >= operators.MinMatches
+	}
+
 	result.Matched = matches
 	result.Extracted = len(result.OutputExtracts) > 0
 	if len(result.DynamicValues) > 0 {
@@ -326,6 +374,13 @@ func (operators *Operators) ExecuteInternalExtractors(data map[string]interface{
 				dynamicValues[extractor.Name] = match
 			}
 		}
+		for _, named := range extractor.NamedGroupExtractors() {
+			for match := range extract(data, named) {
+				if _, ok := dynamicValues[named.Name]; !ok {
+					dynamicValues[named.Name] = match
+				}
+			}
+		}
 	}
 	return dynamicValues
 }
@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
 )
 
 func TestMakeDynamicValuesCallback(t *testing.T) {
@@ -55,3 +58,68 @@ func TestMakeDynamicValuesCallback(t *testing.T) {
 		require.Equal(t, 1, count, "could not get correct result count")
 	})
 }
+
+func TestExecuteNamedGroupExtractors(t *testing.T) {
+	operators := &Operators{Extractors: []*extractors.Extractor{
+		{Name: "version", Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.RegexExtractor}, Regex: []string{`(?P<product>[a-z]+)/(?P<major>\d+)\.(?P<minor>\d+)`}},
+		{Name: "token", Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.RegexExtractor}, Regex: []string{`token=(?P<tokenvalue>[a-z0-9]+)`}, Internal: true},
+	}}
+	require.Nil(t, operators.Compile(), "could not compile operators")
+
+	extract := func(data map[string]interface{}, extractor *extractors.Extractor) map[string]struct{} {
+		return extractor.ExtractRegex(data["body"].(string))
+	}
+	data := map[string]interface{}{"body": "server: nginx/1.22 token=abc123"}
+
+	result, ok := operators.Execute(data, nil, extract, false)
+	require.True(t, ok, "could not execute operators")
+	require.Equal(t, []string{"nginx/1.22"}, result.Extracts["version"])
+	require.Equal(t, []string{"nginx"}, result.Extracts["product"])
+	require.Equal(t, []string{"1"}, result.Extracts["major"])
+	require.Equal(t, []string{"22"}, result.Extracts["minor"])
+	require.Equal(t, []string{"abc123"}, result.DynamicValues["tokenvalue"])
+	require.Equal(t, []string{"token=abc123"}, result.DynamicValues["token"])
+
+	dynamicValues := operators.ExecuteInternalExtractors(data, extract)
+	require.Equal(t, map[string]interface{}{"token": "token=abc123", "tokenvalue": "abc123"}, dynamicValues)
+}
+
+func TestExecuteMinMatches(t *testing.T) {
+	wordMatcher := func(name, word string) *matchers.Matcher {
+		return &matchers.Matcher{Name: name, Type: matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher}, Words: []string{word}}
+	}
+	match := func(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
+		return matcher.MatchWords(data["body"].(string), data)
+	}
+	data := map[string]interface{}{"body": "wp-content wp-includes"}
+
+	tests := []struct {
+		name       string
+		minMatches int
+		condition  string
+		matched    bool
+	}{
+		{name: "same-as-or", minMatches: 1, condition: "and", matched: true},
+		{name: "intermediate", minMatches: 2, matched: true},
+		{name: "same-as-and", minMatches: 3, condition: "or", matched: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			operators := &Operators{MinMatches: test.minMatches, MatchersCondition: test.condition, Matchers: []*matchers.Matcher{
+				wordMatcher("content", "wp-content"),
+				wordMatcher("includes", "wp-includes"),
+				wordMatcher("admin", "wp-admin"),
+			}}
+			require.Nil(t, operators.Compile(), "could not compile operators")
+
+			result, ok := operators.Execute(data, match, nil, false)
+			require.Equal(t, test.matched, ok, "could not get correct match result")
+			if test.matched {
+				require.Equal(t, map[string][]string{"content": {"wp-content"}, "includes": {"wp-includes"}}, result.Matches, "could not get named matches")
+			}
+		})
+	}
+
+	operators := &Operators{MinMatches: 2, Matchers: []*matchers.Matcher{wordMatcher("content", "wp-content")}}
+	require.NotNil(t, operators.Compile(), "could not detect min-matches above number of matchers")
+}
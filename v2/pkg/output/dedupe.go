@@ -0,0 +1,141 @@
+package output
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// findingsDeduper suppresses identical findings of a run and keeps
+// a count of the repeats for each of the unique findings.
+//
+// Findings are identical if they share the template id, the matched
+// signature (matcher, extractor and type) and the normalized evidence.
+// The host is only part of the key if includeHost is set.
+type findingsDeduper struct {
+	includeHost bool
+	mutex       *sync.Mutex
+	order       []string
+	findings    map[string]*dedupedFinding
+}
+
+// dedupedFinding is a unique finding with the number of times it was found
+type dedupedFinding struct {
+	event *ResultEvent
+	count int
+	hosts map[string]struct{}
+}
+
+func newFindingsDeduper(includeHost bool) *findingsDeduper {
+	return &findingsDeduper{
+		includeHost: includeHost,
+		mutex:       &sync.Mutex{},
+		findings:    make(map[string]*dedupedFinding),
+	}
+}
+
+// Index indexes a finding and returns true if the finding was not seen before
+func (d *findingsDeduper) Index(event *ResultEvent) bool {
+	key := d.key(event)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	finding, ok := d.findings[key]
+	if !ok {
+		finding = &dedupedFinding{event: event, hosts: make(map[string]struct{})}
+		d.findings[key] = finding
+		d.order = append(d.order, key)
+	}
+	finding.count++
+	finding.hosts[event.Host] = struct{}{}
+	return !ok
+}
+
+// Duplicated returns the findings that were found more than once in the order they were first found
+func (d *findingsDeduper) Duplicated() []*dedupedFinding {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var duplicated []*dedupedFinding
+	for _, key := range d.order {
+		if finding := d.findings[key]; finding.count > 1 {
+			duplicated = append(duplicated, finding)
+		}
+	}
+	return duplicated
+}
+
+// FindingKey returns a key identifying a finding of a host, independent of the time it was found
+func FindingKey(event *ResultEvent) string {
+	deduper := &findingsDeduper{includeHost: true}
+	return deduper.key(event)
+}
+
+// key returns the dedupe key for a finding
+func (d *findingsDeduper) key(event *ResultEvent) string {
+	hasher := sha1.New()
+	write := func(value string) {
+		_, _ = hasher.Write([]byte(value))
+		_, _ = hasher.Write([]byte{0})
+	}
+	write(event.TemplateID)
+	write(event.MatcherName)
+	write(event.ExtractorName)
+	write(event.Type)
+	if d.includeHost {
+		write(event.Host)
+	}
+	write(d.normalizeMatched(event))
+	for _, value := range d.normalizeExtracted(event) {
+		write(value)
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// normalizeMatched returns the matched location of a finding. The host is
+// stripped from it when the host is not part of the dedupe key.
+func (d *findingsDeduper) normalizeMatched(event *ResultEvent) string {
+	if d.includeHost {
+		return event.Matched
+	}
+	parsed, err := url.Parse(event.Matched)
+	if err != nil || parsed.Host == "" {
+		return d.stripHost(event, event.Matched)
+	}
+	parsed.Scheme = ""
+	parsed.Host = ""
+	parsed.User = nil
+	return parsed.String()
+}
+
+// normalizeExtracted returns the trimmed and sorted extracted results of a finding
+func (d *findingsDeduper) normalizeExtracted(event *ResultEvent) []string {
+	extracted := make([]string, 0, len(event.ExtractedResults))
+	for _, value := range event.ExtractedResults {
+		value = strings.TrimSpace(value)
+		if !d.includeHost {
+			value = d.stripHost(event, value)
+		}
+		extracted = append(extracted, value)
+	}
+	sort.Strings(extracted)
+	return extracted
+}
+
+// stripHost replaces the host and ip of a finding in a value
+func (d *findingsDeduper) stripHost(event *ResultEvent, value string) string {
+	items := []string{event.Host, event.IP}
+	if parsed, err := url.Parse(event.Host); err == nil && parsed.Host != "" {
+		items = append(items, parsed.Host, parsed.Hostname())
+	}
+	for _, item := range items {
+		if item != "" {
+			value = strings.ReplaceAll(value, item, "")
+		}
+	}
+	return value
+}
@@ -0,0 +1,103 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DiffAdded is the diff status of findings only found in the current results
+	DiffAdded = "added"
+	// DiffRemoved is the diff status of findings only found in the previous results
+	DiffRemoved = "removed"
+)
+
+// ResultsDiff contains the findings added and removed between two result sets
+type ResultsDiff struct {
+	// Added are the findings of the current results missing in the previous ones
+	Added []*ResultEvent
+	// Removed are the findings of the previous results missing in the current ones
+	Removed []*ResultEvent
+}
+
+// ReadResults reads the findings of a result file written in JSON
+// (array of results) or JSONL(ines) format.
+func ReadResults(path string) ([]*ResultEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read results")
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var results []*ResultEvent
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &results); err != nil {
+			return nil, errors.Wrapf(err, "could not unmarshal results from %s", path)
+		}
+		return results, nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data))
+	for line := 1; scanner.Scan(); line++ {
+		item := bytes.TrimSpace(scanner.Bytes())
+		if len(item) == 0 {
+			continue
+		}
+		result := &ResultEvent{}
+		if err := json.Unmarshal(item, result); err != nil {
+			return nil, errors.Wrapf(err, "could not unmarshal result at %s:%d", path, line)
+		}
+		results = append(results, result)
+	}
+	return results, scanner.Err()
+}
+
+// DiffResults returns the findings added and removed between the previous
+// and the current results, in the order they appear in their result set.
+//
+// Findings are keyed by their template id, host and signature (matcher,
+// extractor, type, matched location and extracted results), so repeated
+// scans of the same target only report the findings which changed.
+// Results of failed matches are ignored.
+func DiffResults(previous, current []*ResultEvent) *ResultsDiff {
+	keys := newFindingsDeduper(true)
+	index := func(results []*ResultEvent) map[string]struct{} {
+		indexed := make(map[string]struct{}, len(results))
+		for _, result := range results {
+			indexed[keys.key(result)] = struct{}{}
+		}
+		return indexed
+	}
+	changed := func(results []*ResultEvent, other map[string]struct{}, status string) []*ResultEvent {
+		var events []*ResultEvent
+		seen := make(map[string]struct{})
+		for _, result := range results {
+			if !result.MatcherStatus {
+				continue
+			}
+			key := keys.key(result)
+			if _, ok := other[key]; ok {
+				continue
+			}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			result.Diff = status
+			events = append(events, result)
+		}
+		return events
+	}
+
+	return &ResultsDiff{
+		Added:   changed(current, index(previous), DiffAdded),
+		Removed: changed(previous, index(current), DiffRemoved),
+	}
+}
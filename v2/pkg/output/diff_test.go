@@ -0,0 +1,83 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/model"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+)
+
+func TestDiffResults(t *testing.T) {
+	finding := func(templateID, host, matched string, extracted ...string) *ResultEvent {
+		return &ResultEvent{TemplateID: templateID, Type: "http", Host: host, Matched: matched, ExtractedResults: extracted, MatcherStatus: true}
+	}
+	previous := []*ResultEvent{
+		finding("tech-detect", "https://a.example.com", "https://a.example.com/", "nginx"),
+		finding("exposed-git", "https://a.example.com", "https://a.example.com/.git/config"),
+		finding("exposed-git", "https://b.example.com", "https://b.example.com/.git/config"),
+	}
+	current := []*ResultEvent{
+		finding("tech-detect", "https://a.example.com", "https://a.example.com/", "nginx"),
+		finding("exposed-git", "https://b.example.com", "https://b.example.com/.git/config"),
+		finding("tech-detect", "https://b.example.com", "https://b.example.com/", "apache"),
+		finding("tech-detect", "https://b.example.com", "https://b.example.com/", "apache"),
+		{TemplateID: "failed", Host: "https://b.example.com"},
+	}
+
+	diff := DiffResults(previous, current)
+	require.Len(t, diff.Added, 1, "could not get added findings")
+	require.Equal(t, "https://b.example.com", diff.Added[0].Host, "could not get added finding")
+	require.Equal(t, DiffAdded, diff.Added[0].Diff, "could not set added status")
+	require.Len(t, diff.Removed, 1, "could not get removed findings")
+	require.Equal(t, "exposed-git", diff.Removed[0].TemplateID, "could not get removed finding")
+	require.Equal(t, "https://a.example.com", diff.Removed[0].Host, "could not get removed finding")
+	require.Equal(t, DiffRemoved, diff.Removed[0].Diff, "could not set removed status")
+
+	// findings with changed extracted results are a new finding
+	changed := []*ResultEvent{finding("tech-detect", "https://a.example.com", "https://a.example.com/", "nginx/1.25")}
+	diff = DiffResults(previous[:1], changed)
+	require.Len(t, diff.Added, 1, "could not get changed finding")
+	require.Len(t, diff.Removed, 1, "could not get changed finding")
+}
+
+func TestReadResults(t *testing.T) {
+	tempDir := t.TempDir()
+	info := model.Info{SeverityHolder: severity.Holder{Severity: severity.High}}
+	results := []*ResultEvent{
+		{TemplateID: "first", Info: info, Host: "https://example.com", MatcherStatus: true},
+		{TemplateID: "second", Info: info, Host: "https://example.com", MatcherStatus: true},
+	}
+
+	jsonData, err := json.Marshal(results)
+	require.Nil(t, err, "could not marshal results")
+	jsonFile := filepath.Join(tempDir, "results.json")
+	require.Nil(t, os.WriteFile(jsonFile, jsonData, 0644), "could not write json results")
+
+	lines := make([]string, 0, len(results))
+	for _, result := range results {
+		line, err := json.Marshal(result)
+		require.Nil(t, err, "could not marshal result")
+		lines = append(lines, string(line))
+	}
+	jsonlFile := filepath.Join(tempDir, "results.jsonl")
+	require.Nil(t, os.WriteFile(jsonlFile, []byte(strings.Join(lines, "\n")+"\n\n"), 0644), "could not write jsonl results")
+
+	for _, file := range []string{jsonFile, jsonlFile} {
+		read, err := ReadResults(file)
+		require.Nil(t, err, "could not read results from %s", file)
+		require.Len(t, read, 2, "could not read all results from %s", file)
+		require.Equal(t, "second", read[1].TemplateID, "could not read result from %s", file)
+		require.Equal(t, severity.High, read[1].Info.SeverityHolder.Severity, "could not read result severity from %s", file)
+	}
+
+	invalidFile := filepath.Join(tempDir, "invalid.jsonl")
+	require.Nil(t, os.WriteFile(invalidFile, []byte(lines[0]+"\n{invalid\n"), 0644), "could not write invalid results")
+	_, err = ReadResults(invalidFile)
+	require.ErrorContains(t, err, "invalid.jsonl:2", "could not get line of invalid result")
+}
@@ -3,6 +3,7 @@ package output
 import (
 	"bytes"
 	"strconv"
+	"strings"
 
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
 )
@@ -17,6 +18,16 @@ func (w *StandardWriter) formatScreen(output *ResultEvent) []byte {
 			builder.WriteString(w.aurora.Cyan(output.Timestamp.Format("2006-01-02 15:04:05")).String())
 			builder.WriteString("] ")
 		}
+		switch output.Diff {
+		case DiffAdded:
+			builder.WriteString("[")
+			builder.WriteString(w.aurora.Green(output.Diff).String())
+			builder.WriteString("] ")
+		case DiffRemoved:
+			builder.WriteString("[")
+			builder.WriteString(w.aurora.Red(output.Diff).String())
+			builder.WriteString("] ")
+		}
 		builder.WriteRune('[')
 		builder.WriteString(w.aurora.BrightGreen(output.TemplateID).String())
 
@@ -65,6 +76,18 @@ func (w *StandardWriter) formatScreen(output *ResultEvent) []byte {
 		builder.WriteString("]")
 	}
 
+	if len(output.ValidationErrors) > 0 {
+		builder.WriteString(" [")
+		builder.WriteString(w.aurora.BrightYellow(strings.Join(output.ValidationErrors, ", ")).String())
+		builder.WriteString("]")
+	}
+
+	if len(output.Deltas) > 0 {
+		builder.WriteString(" [")
+		builder.WriteString(w.aurora.BrightYellow(strings.Join(output.Deltas, ", ")).String())
+		builder.WriteString("]")
+	}
+
 	if len(output.Lines) > 0 {
 		builder.WriteString(" [LN: ")
 
@@ -0,0 +1,86 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/model"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/stringslice"
+)
+
+// templateEvent is the data an output template is rendered with.
+//
+// Fields of the result event are available as is, along with shortcuts
+// for the commonly used template information (e.g. {{.TemplateID}} {{.Severity}} {{.Matched}}).
+type templateEvent struct {
+	*ResultEvent
+	// Name is the name of the template
+	Name string
+	// Severity is the severity of the template
+	Severity string
+	// Tags are the tags of the template
+	Tags []string
+}
+
+// outputTemplateFuncs are the functions available to output templates
+var outputTemplateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"json": func(value interface{}) (string, error) {
+		data, err := jsoniter.Marshal(value)
+		return string(data), err
+	},
+}
+
+// ParseOutputTemplate parses a go template used to format findings.
+//
+// The template is also rendered with a sample finding, so that references to
+// unknown fields are reported before the scan instead of for every finding.
+func ParseOutputTemplate(text string) (*template.Template, error) {
+	tpl, err := template.New("output").Funcs(outputTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse output template")
+	}
+	sample := &ResultEvent{
+		TemplateID:       "sample",
+		Info:             model.Info{Name: "sample", SeverityHolder: severity.Holder{Severity: severity.Info}, Tags: stringslice.StringSlice{Value: []string{"sample"}}},
+		Type:             "http",
+		Host:             "https://example.com",
+		Matched:          "https://example.com/",
+		ExtractedResults: []string{"sample"},
+		Metadata:         map[string]interface{}{},
+		Timestamp:        time.Now(),
+		MatcherStatus:    true,
+	}
+	if _, err := renderTemplate(tpl, sample); err != nil {
+		return nil, errors.Wrap(err, "could not render output template")
+	}
+	return tpl, nil
+}
+
+// formatTemplate formats the output using the user supplied output template
+func (w *StandardWriter) formatTemplate(output *ResultEvent) ([]byte, error) {
+	return renderTemplate(w.outputTemplate, output)
+}
+
+// renderTemplate renders a finding as a single line with an output template
+func renderTemplate(tpl *template.Template, output *ResultEvent) ([]byte, error) {
+	event := &templateEvent{
+		ResultEvent: output,
+		Name:        output.Info.Name,
+		Severity:    output.Info.SeverityHolder.Severity.String(),
+		Tags:        output.Info.Tags.ToSlice(),
+	}
+	buffer := &bytes.Buffer{}
+	if err := tpl.Execute(buffer, event); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buffer.Bytes(), "\r\n"), nil
+}
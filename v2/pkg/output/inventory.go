@@ -0,0 +1,235 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// Inventory categories of the findings aggregated in the asset inventory
+const (
+	// InventoryTech are the technologies detected by the templates tagged tech
+	InventoryTech = "tech"
+	// InventoryCMS are the cms detected by the templates tagged cms
+	InventoryCMS = "cms"
+	// InventoryFavicon are the favicons detected by the templates tagged favicon
+	InventoryFavicon = "favicon"
+	// InventoryHeader are the headers detected by the templates tagged headers or
+	// by matchers and extractors named after a header or the server
+	InventoryHeader = "header"
+	// InventoryPort are the open ports found by the network templates
+	InventoryPort = "port"
+)
+
+// InventoryCategories are all the inventory categories
+var InventoryCategories = []string{InventoryTech, InventoryCMS, InventoryFavicon, InventoryHeader, InventoryPort}
+
+// InventoryRecord is the inventory of the assets detected on a host
+type InventoryRecord struct {
+	// Host is the host the assets were detected on
+	Host string `json:"host"`
+	// Technologies are the technologies detected on the host
+	Technologies []string `json:"technologies,omitempty"`
+	// CMS are the cms detected on the host
+	CMS []string `json:"cms,omitempty"`
+	// Favicons are the favicons detected on the host
+	Favicons []string `json:"favicons,omitempty"`
+	// Headers are the headers detected on the host
+	Headers []string `json:"headers,omitempty"`
+	// Ports are the open ports of the host, with the detected service (port/service)
+	Ports []string `json:"ports,omitempty"`
+	// Templates are the ids of the templates the assets were detected by
+	Templates []string `json:"templates"`
+}
+
+// inventory aggregates the assets detected by the findings of a run per host
+type inventory struct {
+	path       string
+	categories map[string]struct{}
+	mutex      *sync.Mutex
+	records    map[string]*InventoryRecord
+}
+
+// newInventory returns an inventory of the given categories written to path
+func newInventory(path string, categories []string) *inventory {
+	if len(categories) == 0 {
+		categories = InventoryCategories
+	}
+	inv := &inventory{path: path, categories: make(map[string]struct{}), mutex: &sync.Mutex{}, records: make(map[string]*InventoryRecord)}
+	for _, category := range categories {
+		inv.categories[strings.ToLower(strings.TrimSpace(category))] = struct{}{}
+	}
+	return inv
+}
+
+// Index adds the assets detected by a finding to the inventory of its host
+func (inv *inventory) Index(event *ResultEvent) {
+	categories := inv.classify(event)
+	if len(categories) == 0 {
+		return
+	}
+	host := inventoryHost(event.Host)
+	if host == "" {
+		return
+	}
+
+	inv.mutex.Lock()
+	defer inv.mutex.Unlock()
+
+	record, ok := inv.records[host]
+	if !ok {
+		record = &InventoryRecord{Host: host}
+		inv.records[host] = record
+	}
+	record.Templates = append(record.Templates, event.TemplateID)
+	for _, category := range categories {
+		switch category {
+		case InventoryTech:
+			record.Technologies = append(record.Technologies, inventoryValues(event)...)
+		case InventoryCMS:
+			record.CMS = append(record.CMS, inventoryValues(event)...)
+		case InventoryFavicon:
+			record.Favicons = append(record.Favicons, inventoryValues(event)...)
+		case InventoryHeader:
+			record.Headers = append(record.Headers, inventoryValues(event)...)
+		case InventoryPort:
+			if port := inventoryPort(event); port != "" {
+				record.Ports = append(record.Ports, port)
+			}
+		}
+	}
+}
+
+// classify returns the enabled inventory categories of a finding
+func (inv *inventory) classify(event *ResultEvent) []string {
+	tags := make(map[string]struct{})
+	for _, tag := range event.Info.Tags.ToSlice() {
+		tags[strings.ToLower(tag)] = struct{}{}
+	}
+	hasTag := func(tag string) bool {
+		_, ok := tags[tag]
+		return ok
+	}
+	names := strings.ToLower(event.MatcherName + " " + event.ExtractorName)
+
+	var categories []string
+	add := func(category string, ok bool) {
+		if _, enabled := inv.categories[category]; enabled && ok {
+			categories = append(categories, category)
+		}
+	}
+	add(InventoryTech, hasTag("tech"))
+	add(InventoryCMS, hasTag("cms"))
+	add(InventoryFavicon, hasTag("favicon") || strings.Contains(strings.ToLower(event.TemplateID), "favicon"))
+	add(InventoryHeader, hasTag("headers") || strings.Contains(names, "header") || strings.Contains(names, "server"))
+	add(InventoryPort, event.Type == "tcp")
+	return categories
+}
+
+// Records returns the inventory records sorted by host, with their values deduplicated
+func (inv *inventory) Records() []*InventoryRecord {
+	inv.mutex.Lock()
+	defer inv.mutex.Unlock()
+
+	records := make([]*InventoryRecord, 0, len(inv.records))
+	for _, record := range inv.records {
+		records = append(records, &InventoryRecord{
+			Host:         record.Host,
+			Technologies: sortedUnique(record.Technologies),
+			CMS:          sortedUnique(record.CMS),
+			Favicons:     sortedUnique(record.Favicons),
+			Headers:      sortedUnique(record.Headers),
+			Ports:        sortedUnique(record.Ports),
+			Templates:    sortedUnique(record.Templates),
+		})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Host < records[j].Host
+	})
+	return records
+}
+
+// Write writes the inventory records in JSONL(ines) format to the inventory file
+func (inv *inventory) Write() error {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	for _, record := range inv.Records() {
+		if err := encoder.Encode(record); err != nil {
+			return errors.Wrap(err, "could not marshal inventory record")
+		}
+	}
+	if err := os.WriteFile(inv.path, buffer.Bytes(), 0644); err != nil {
+		return errors.Wrap(err, "could not write inventory")
+	}
+	return nil
+}
+
+// inventoryHost returns the hostname of the host of a finding
+func inventoryHost(host string) string {
+	if strings.Contains(host, "://") {
+		if parsed, err := url.Parse(host); err == nil {
+			return parsed.Hostname()
+		}
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return hostname
+	}
+	return host
+}
+
+// inventoryValues returns the assets detected by a finding: its extracted
+// results, or its matcher name, or the id of its template
+func inventoryValues(event *ResultEvent) []string {
+	var values []string
+	for _, value := range event.ExtractedResults {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) > 0 {
+		return values
+	}
+	if event.MatcherName != "" {
+		return []string{event.MatcherName}
+	}
+	return []string{event.TemplateID}
+}
+
+// inventoryPort returns the port of a network finding with the detected
+// service, named after its matcher or its template
+func inventoryPort(event *ResultEvent) string {
+	var port string
+	for _, address := range []string{event.Matched, event.Host} {
+		if _, value, err := net.SplitHostPort(address); err == nil && value != "" {
+			port = value
+			break
+		}
+	}
+	if port == "" {
+		return ""
+	}
+	service := event.MatcherName
+	if service == "" {
+		service = event.TemplateID
+	}
+	return port + "/" + service
+}
+
+// sortedUnique returns the sorted unique values
+func sortedUnique(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	values = sliceutil.Dedupe(values)
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/model"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/stringslice"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+func TestStandardWriterInventory(t *testing.T) {
+	inventoryFile := func(t *testing.T, categories []string, events ...*ResultEvent) []InventoryRecord {
+		path := filepath.Join(t.TempDir(), "inventory.jsonl")
+		w, err := NewStandardWriter(&types.Options{Inventory: path, InventoryCategories: categories, DedupeFindings: true})
+		require.Nil(t, err, "could not create writer")
+		for _, event := range events {
+			require.Nil(t, w.Write(event), "could not write event")
+		}
+		w.Close()
+
+		data, err := os.ReadFile(path)
+		require.Nil(t, err, "could not read inventory")
+		var records []InventoryRecord
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if line == "" {
+				continue
+			}
+			var record InventoryRecord
+			require.Nil(t, json.Unmarshal([]byte(line), &record), "could not unmarshal record")
+			records = append(records, record)
+		}
+		return records
+	}
+	tagged := func(tags string) model.Info {
+		return model.Info{Name: "detect", Tags: stringslice.StringSlice{Value: strings.Split(tags, ",")}}
+	}
+	events := []*ResultEvent{
+		{TemplateID: "tech-detect", Info: tagged("tech"), Type: "http", Host: "https://example.com", Matched: "https://example.com/", MatcherName: "nginx", MatcherStatus: true},
+		{TemplateID: "tech-detect", Info: tagged("tech"), Type: "http", Host: "https://example.com:8443", Matched: "https://example.com:8443/", MatcherName: "nginx", MatcherStatus: true},
+		{TemplateID: "wordpress-detect", Info: tagged("tech,cms"), Type: "http", Host: "https://example.com", ExtractedResults: []string{"6.1"}, MatcherStatus: true},
+		{TemplateID: "favicon-detect", Info: tagged("misc"), Type: "http", Host: "https://example.com", MatcherName: "jenkins", MatcherStatus: true},
+		{TemplateID: "http-missing-security-headers", Info: tagged("misc,generic"), Type: "http", Host: "https://example.com", MatcherName: "x-frame-options-header", MatcherStatus: true},
+		{TemplateID: "openssh-detect", Info: tagged("network"), Type: "tcp", Host: "other.example.com:22", Matched: "other.example.com:22", MatcherName: "ssh", MatcherStatus: true},
+		{TemplateID: "exposed-panel", Info: tagged("panel"), Type: "http", Host: "https://example.com", MatcherStatus: true},
+		{TemplateID: "tech-detect", Info: tagged("tech"), Type: "http", Host: "https://failed.example.com", MatcherName: "apache"},
+	}
+
+	t.Run("all", func(t *testing.T) {
+		records := inventoryFile(t, nil, events...)
+		require.Equal(t, []InventoryRecord{
+			{
+				Host:         "example.com",
+				Technologies: []string{"6.1", "nginx"},
+				CMS:          []string{"6.1"},
+				Favicons:     []string{"jenkins"},
+				Headers:      []string{"x-frame-options-header"},
+				Templates:    []string{"favicon-detect", "http-missing-security-headers", "tech-detect", "wordpress-detect"},
+			},
+			{Host: "other.example.com", Ports: []string{"22/ssh"}, Templates: []string{"openssh-detect"}},
+		}, records, "wrong inventory")
+	})
+	t.Run("categories", func(t *testing.T) {
+		records := inventoryFile(t, []string{"port", "cms"}, events...)
+		require.Equal(t, []InventoryRecord{
+			{Host: "example.com", CMS: []string{"6.1"}, Templates: []string{"wordpress-detect"}},
+			{Host: "other.example.com", Ports: []string{"22/ssh"}, Templates: []string{"openssh-detect"}},
+		}, records, "wrong inventory")
+	})
+	t.Run("empty", func(t *testing.T) {
+		require.Empty(t, inventoryFile(t, nil), "inventory not empty")
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/pkg/errors"
@@ -58,6 +59,14 @@ type StandardWriter struct {
 	severityColors   func(severity.Severity) string
 	storeResponse    bool
 	storeResponseDir string
+	deduper          *findingsDeduper
+	fullOutputFile   io.WriteCloser
+	outputTemplate   *template.Template
+	webhook          *webhookSender
+	outputPath       string
+	signingKey       *SigningKey
+	inventory        *inventory
+	throttler        *findingsThrottler
 }
 
 var decolorizerRegex = regexp.MustCompile(`\x1B\[[0-9;]*[a-zA-Z]`)
@@ -138,6 +147,9 @@ type ResultEvent struct {
 	Timestamp time.Time `json:"timestamp"`
 	// Interaction is the full details of interactsh interaction.
 	Interaction *server.Interaction `json:"interaction,omitempty"`
+	// Interactions are all the interactsh interactions correlated with the request
+	// of the result, only populated when the interactions output is enabled.
+	Interactions []*InteractionRecord `json:"interactions,omitempty"`
 	// CURLCommand is an optional curl command to reproduce the request
 	// Only applicable if the report is for HTTP.
 	CURLCommand string `json:"curl-command,omitempty"`
@@ -145,10 +157,27 @@ type ResultEvent struct {
 	MatcherStatus bool `json:"matcher-status"`
 	// Lines is the line count for the specified match
 	Lines []int `json:"matched-line"`
+	// ValidationErrors contains the json schema validation errors of the response
+	ValidationErrors []string `json:"validation-errors,omitempty"`
+	// Deltas contains the differences with the baseline computed by the differential
+	// matchers and the similarities computed by the structure matchers
+	Deltas []string `json:"deltas,omitempty"`
+	// Truncated is true if a response body was truncated to the maximum body size
+	Truncated bool `json:"truncated,omitempty"`
+	// Diff is the status of the result when diffing two result sets (added or removed)
+	Diff string `json:"diff,omitempty"`
 
 	FileToIndexPosition map[string]int `json:"-"`
 }
 
+// InteractionRecord is an interactsh interaction along with the
+// interactsh url of the request it was correlated with.
+type InteractionRecord struct {
+	// URL is the interactsh url the interaction was received for
+	URL string `json:"interactsh-url"`
+	*server.Interaction
+}
+
 // NewStandardWriter creates a new output writer based on user configurations
 func NewStandardWriter(options *types.Options) (*StandardWriter, error) {
 	resumeBool := false
@@ -181,6 +210,14 @@ func NewStandardWriter(options *types.Options) (*StandardWriter, error) {
 		}
 		errorOutput = output
 	}
+	var fullOutput io.WriteCloser
+	if options.DedupeFindings && options.DedupeFullOutput != "" {
+		output, err := newFileOutputWriter(options.DedupeFullOutput, resumeBool)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create full output file")
+		}
+		fullOutput = output
+	}
 	// Try to create output folder if it doesn't exist
 	if options.StoreResponse && !fileutil.FolderExists(options.StoreResponseDir) {
 		if err := fileutil.CreateFolder(options.StoreResponseDir); err != nil {
@@ -201,6 +238,43 @@ func NewStandardWriter(options *types.Options) (*StandardWriter, error) {
 		severityColors:   colorizer.New(auroraColorizer),
 		storeResponse:    options.StoreResponse,
 		storeResponseDir: options.StoreResponseDir,
+		fullOutputFile:   fullOutput,
+	}
+	if options.DedupeFindings {
+		writer.deduper = newFindingsDeduper(options.DedupeIncludeHost)
+	}
+	if options.OutputTemplate != "" {
+		outputTemplate, err := ParseOutputTemplate(options.OutputTemplate)
+		if err != nil {
+			return nil, err
+		}
+		writer.outputTemplate = outputTemplate
+	}
+	if options.Output != "" && options.SignKey != "" {
+		signingKey, err := LoadSigningKey(options.SignKey)
+		if err != nil {
+			return nil, err
+		}
+		writer.outputPath = options.Output
+		writer.signingKey = signingKey
+	}
+	if options.Inventory != "" {
+		writer.inventory = newInventory(options.Inventory, options.InventoryCategories)
+	}
+	if options.WebhookURL != "" {
+		webhook, err := newWebhookSender(options.WebhookURL, options.WebhookHeaders, options.WebhookTemplate)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create webhook")
+		}
+		writer.webhook = webhook
+	}
+	// only the webhook is throttled, the file and screen output getting every finding
+	if options.OutputBatchSize > 0 && writer.webhook != nil {
+		interval := options.OutputFlushInterval
+		if interval <= 0 {
+			interval = time.Second
+		}
+		writer.throttler = newFindingsThrottler(options.OutputBatchSize, interval, writer.sendWebhook)
 	}
 	return writer, nil
 }
@@ -213,11 +287,47 @@ func (w *StandardWriter) Write(event *ResultEvent) error {
 	}
 	event.Timestamp = time.Now()
 
+	if w.inventory != nil && event.MatcherStatus {
+		w.inventory.Index(event)
+	}
+	if w.deduper != nil && event.MatcherStatus {
+		if err := w.writeFullOutput(event); err != nil {
+			return err
+		}
+		if !w.deduper.Index(event) {
+			return nil
+		}
+	}
+	if w.throttler != nil {
+		w.throttler.Add(event)
+	} else {
+		w.sendWebhook(event)
+	}
+	return w.writeFinding(event)
+}
+
+// sendWebhook sends the event to the webhook if it is a finding
+func (w *StandardWriter) sendWebhook(event *ResultEvent) {
+	if w.webhook == nil || !event.MatcherStatus {
+		return
+	}
+	finding := *event
+	if !w.jsonReqResp {
+		finding.Request = ""
+		finding.Response = ""
+	}
+	w.webhook.Send(&finding)
+}
+
+// writeFinding writes the event to file and/or screen.
+func (w *StandardWriter) writeFinding(event *ResultEvent) error {
 	var data []byte
 	var err error
 
 	if w.json {
 		data, err = w.formatJSON(event)
+	} else if w.outputTemplate != nil {
+		data, err = w.formatTemplate(event)
 	} else {
 		data = w.formatScreen(event)
 	}
@@ -244,6 +354,37 @@ func (w *StandardWriter) Write(event *ResultEvent) error {
 	return nil
 }
 
+// writeFullOutput writes a finding to the full output file irrespective of deduplication
+func (w *StandardWriter) writeFullOutput(event *ResultEvent) error {
+	if w.fullOutputFile == nil {
+		return nil
+	}
+	data, err := w.formatJSON(event)
+	if err != nil {
+		return errors.Wrap(err, "could not format full output")
+	}
+	if _, err := w.fullOutputFile.Write(data); err != nil {
+		return errors.Wrap(err, "could not write to full output")
+	}
+	return nil
+}
+
+// writeDedupeSummary displays the number of times the deduped findings were found
+func (w *StandardWriter) writeDedupeSummary() {
+	if w.deduper == nil {
+		return
+	}
+	for _, finding := range w.deduper.Duplicated() {
+		name := finding.event.TemplateID
+		if finding.event.MatcherName != "" {
+			name = name + ":" + finding.event.MatcherName
+		} else if finding.event.ExtractorName != "" {
+			name = name + ":" + finding.event.ExtractorName
+		}
+		gologger.Info().Msgf("[%s] Found %d identical findings on %d hosts (first at %s)", name, finding.count, len(finding.hosts), finding.event.Matched)
+	}
+}
+
 // JSONLogRequest is a trace/error log request written to file
 type JSONLogRequest struct {
 	Template string `json:"template"`
@@ -289,8 +430,28 @@ func (w *StandardWriter) Colorizer() aurora.Aurora {
 
 // Close closes the output writing interface
 func (w *StandardWriter) Close() {
+	if w.throttler != nil {
+		w.throttler.Close()
+	}
+	w.writeDedupeSummary()
+	if w.inventory != nil {
+		if err := w.inventory.Write(); err != nil {
+			gologger.Error().Msgf("Could not write inventory: %s\n", err)
+		}
+	}
+	if w.webhook != nil {
+		w.webhook.Close()
+	}
+	if w.fullOutputFile != nil {
+		w.fullOutputFile.Close()
+	}
 	if w.outputFile != nil {
 		w.outputFile.Close()
+		if w.signingKey != nil {
+			if err := SignOutput(w.outputPath, w.signingKey); err != nil {
+				gologger.Error().Msgf("Could not sign output file: %s\n", err)
+			}
+		}
 	}
 	if w.traceFile != nil {
 		w.traceFile.Close()
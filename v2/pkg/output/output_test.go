@@ -6,6 +6,9 @@ import (
 	"testing"
 
 	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/stringslice"
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
 	"github.com/stretchr/testify/require"
 )
@@ -51,6 +54,98 @@ func TestStandardWriterRequest(t *testing.T) {
 	})
 }
 
+func TestStandardWriterDedupeFindings(t *testing.T) {
+	newEvent := func(host string) *ResultEvent {
+		return &ResultEvent{
+			TemplateID:       "exposed-panel",
+			MatcherName:      "title",
+			Type:             "http",
+			Host:             host,
+			Matched:          host + "/admin",
+			ExtractedResults: []string{" Admin Panel "},
+			MatcherStatus:    true,
+		}
+	}
+
+	t.Run("ExcludeHost", func(t *testing.T) {
+		outputWriter := &testWriteCloser{}
+		fullWriter := &testWriteCloser{}
+
+		w, err := NewStandardWriter(&types.Options{JSONL: true, DedupeFindings: true})
+		require.NoError(t, err)
+		w.outputFile = outputWriter
+		w.fullOutputFile = fullWriter
+
+		for _, host := range []string{"https://a.example.com", "https://b.example.com", "https://a.example.com"} {
+			require.NoError(t, w.Write(newEvent(host)))
+		}
+		require.Equal(t, 1, strings.Count(outputWriter.String(), "exposed-panel"), "could not dedupe findings")
+		require.Equal(t, 3, strings.Count(fullWriter.String(), "exposed-panel"), "could not write full output")
+
+		duplicated := w.deduper.Duplicated()
+		require.Len(t, duplicated, 1, "could not get deduped findings")
+		require.Equal(t, 3, duplicated[0].count, "could not get deduped findings count")
+		require.Len(t, duplicated[0].hosts, 2, "could not get deduped findings hosts")
+	})
+
+	t.Run("IncludeHost", func(t *testing.T) {
+		outputWriter := &testWriteCloser{}
+
+		w, err := NewStandardWriter(&types.Options{JSONL: true, DedupeFindings: true, DedupeIncludeHost: true})
+		require.NoError(t, err)
+		w.outputFile = outputWriter
+
+		for _, host := range []string{"https://a.example.com", "https://b.example.com", "https://a.example.com"} {
+			require.NoError(t, w.Write(newEvent(host)))
+		}
+		require.Equal(t, 2, strings.Count(outputWriter.String(), "exposed-panel"), "could not dedupe findings by host")
+	})
+
+	t.Run("DifferentEvidence", func(t *testing.T) {
+		outputWriter := &testWriteCloser{}
+
+		w, err := NewStandardWriter(&types.Options{JSONL: true, DedupeFindings: true})
+		require.NoError(t, err)
+		w.outputFile = outputWriter
+
+		first, second := newEvent("https://a.example.com"), newEvent("https://b.example.com")
+		second.ExtractedResults = []string{"Login"}
+		require.NoError(t, w.Write(first))
+		require.NoError(t, w.Write(second))
+		require.Equal(t, 2, strings.Count(outputWriter.String(), "exposed-panel"), "could dedupe different findings")
+	})
+}
+
+func TestStandardWriterOutputTemplate(t *testing.T) {
+	t.Run("Render", func(t *testing.T) {
+		outputWriter := &testWriteCloser{}
+
+		w, err := NewStandardWriter(&types.Options{OutputTemplate: "{{.TemplateID}}|{{.Severity}}|{{.Matched}}|{{join .ExtractedResults \",\"}}|{{upper .Name}}|{{json .Tags}}\n"})
+		require.NoError(t, err)
+		w.outputFile = outputWriter
+
+		event := &ResultEvent{
+			TemplateID:       "exposed-panel",
+			Info:             model.Info{Name: "Admin Panel", SeverityHolder: severity.Holder{Severity: severity.Medium}, Tags: stringslice.StringSlice{Value: []string{"panel", "admin"}}},
+			Type:             "http",
+			Matched:          "https://example.com/admin",
+			ExtractedResults: []string{"v1", "v2"},
+			MatcherStatus:    true,
+		}
+		require.NoError(t, w.Write(event))
+		require.Equal(t, `exposed-panel|medium|https://example.com/admin|v1,v2|ADMIN PANEL|["panel","admin"]`, outputWriter.String(), "could not render output template")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewStandardWriter(&types.Options{OutputTemplate: "{{.TemplateID"})
+		require.ErrorContains(t, err, "could not parse output template")
+
+		_, err = NewStandardWriter(&types.Options{OutputTemplate: "{{.Unknown}}"})
+		require.ErrorContains(t, err, "could not render output template")
+		require.ErrorContains(t, err, "can't evaluate field Unknown")
+	})
+}
+
 type testWriteCloser struct {
 	strings.Builder
 }
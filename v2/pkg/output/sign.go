@@ -0,0 +1,263 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SignatureFileSuffix is appended to the path of an output file to get the path of its signature
+	SignatureFileSuffix = ".sig"
+	// SignatureHMAC is the algorithm of the signatures made with hmac keys
+	SignatureHMAC = "hmac-sha256"
+	// SignatureEd25519 is the algorithm of the signatures made with ed25519 keys
+	SignatureEd25519 = "ed25519"
+
+	// manifestVersion is the version of the manifest format
+	manifestVersion = 1
+)
+
+// Manifest describes a signed output file.
+//
+// The canonical serialization of the manifest, which is signed, is its compact
+// json encoding with the fields in the order below and the findings sorted.
+type Manifest struct {
+	// Version is the version of the manifest format
+	Version int `json:"version"`
+	// Algorithm is the signature algorithm (hmac-sha256 or ed25519)
+	Algorithm string `json:"algorithm"`
+	// File is the base name of the output file
+	File string `json:"file"`
+	// SHA256 is the hex encoded sha256 hash of the output file
+	SHA256 string `json:"sha256"`
+	// Findings are the hex encoded sha256 hashes of the non-empty lines of the
+	// output file, excluding line endings, one for each finding, sorted.
+	Findings []string `json:"findings"`
+}
+
+// Signature is the detached signature of an output file
+type Signature struct {
+	Manifest
+	// Signature is the base64 encoded signature of the canonical serialization of the manifest
+	Signature string `json:"signature"`
+}
+
+// SigningKey is a key signing or verifying output files
+type SigningKey struct {
+	algorithm  string
+	hmacKey    []byte
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+// LoadSigningKey loads a signing key from a file containing either an ed25519
+// private or public key in PEM format (PKCS #8 or PKIX), or an hmac secret.
+// Public keys can only verify signatures.
+func LoadSigningKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read signing key")
+	}
+	return ParseSigningKey(data)
+}
+
+// ParseSigningKey parses a signing key, see LoadSigningKey
+func ParseSigningKey(data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		secret := bytes.TrimSpace(data)
+		if len(secret) == 0 {
+			return nil, errors.New("empty hmac signing key")
+		}
+		return &SigningKey{algorithm: SignatureHMAC, hmacKey: secret}, nil
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse private key")
+		}
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("private key is not an ed25519 key")
+		}
+		return &SigningKey{algorithm: SignatureEd25519, privateKey: privateKey, publicKey: privateKey.Public().(ed25519.PublicKey)}, nil
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse public key")
+		}
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New("public key is not an ed25519 key")
+		}
+		return &SigningKey{algorithm: SignatureEd25519, publicKey: publicKey}, nil
+	default:
+		return nil, errors.Errorf("unsupported signing key type %s", block.Type)
+	}
+}
+
+// CanSign returns true if the key can sign output files, which ed25519 public keys can't
+func (k *SigningKey) CanSign() bool {
+	return k.hmacKey != nil || k.privateKey != nil
+}
+
+// sign returns the signature of data
+func (k *SigningKey) sign(data []byte) ([]byte, error) {
+	switch {
+	case k.hmacKey != nil:
+		mac := hmac.New(sha256.New, k.hmacKey)
+		mac.Write(data)
+		return mac.Sum(nil), nil
+	case k.privateKey != nil:
+		return ed25519.Sign(k.privateKey, data), nil
+	default:
+		return nil, errors.New("ed25519 public keys can only verify signatures")
+	}
+}
+
+// verify returns true if signature is a valid signature of data
+func (k *SigningKey) verify(data, signature []byte) bool {
+	if k.hmacKey != nil {
+		expected, _ := k.sign(data)
+		return hmac.Equal(expected, signature)
+	}
+	return ed25519.Verify(k.publicKey, data, signature)
+}
+
+// NewManifest returns the manifest of an output file
+func NewManifest(path, algorithm string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read output file")
+	}
+	fileHash := sha256.Sum256(data)
+	manifest := &Manifest{
+		Version:   manifestVersion,
+		Algorithm: algorithm,
+		File:      filepath.Base(path),
+		SHA256:    hex.EncodeToString(fileHash[:]),
+		Findings:  []string{},
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSuffix(scanner.Bytes(), []byte("\r"))
+		if len(line) == 0 {
+			continue
+		}
+		lineHash := sha256.Sum256(line)
+		manifest.Findings = append(manifest.Findings, hex.EncodeToString(lineHash[:]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read output file")
+	}
+	sort.Strings(manifest.Findings)
+	return manifest, nil
+}
+
+// canonical returns the canonical serialization of the manifest
+func (m *Manifest) canonical() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// SignOutput writes the detached signature of an output file next to it
+func SignOutput(path string, key *SigningKey) error {
+	manifest, err := NewManifest(path, key.algorithm)
+	if err != nil {
+		return err
+	}
+	data, err := manifest.canonical()
+	if err != nil {
+		return errors.Wrap(err, "could not serialize manifest")
+	}
+	signature, err := key.sign(data)
+	if err != nil {
+		return err
+	}
+
+	signed, err := json.MarshalIndent(&Signature{Manifest: *manifest, Signature: base64.StdEncoding.EncodeToString(signature)}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal signature")
+	}
+	if err := os.WriteFile(path+SignatureFileSuffix, append(signed, '\n'), 0644); err != nil {
+		return errors.Wrap(err, "could not write signature")
+	}
+	return nil
+}
+
+// VerifyOutput verifies an output file against its detached signature,
+// returning the signed manifest if the file wasn't altered.
+func VerifyOutput(path string, key *SigningKey) (*Manifest, error) {
+	data, err := os.ReadFile(path + SignatureFileSuffix)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read signature")
+	}
+	signed := &Signature{}
+	if err := json.Unmarshal(data, signed); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal signature")
+	}
+	if signed.Version != manifestVersion {
+		return nil, errors.Errorf("unsupported manifest version %d", signed.Version)
+	}
+	if signed.Algorithm != key.algorithm {
+		return nil, errors.Errorf("output signed with %s but verified with a %s key", signed.Algorithm, key.algorithm)
+	}
+	signature, err := base64.StdEncoding.DecodeString(signed.Signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode signature")
+	}
+	canonical, err := signed.Manifest.canonical()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not serialize manifest")
+	}
+	if !key.verify(canonical, signature) {
+		return nil, errors.New("invalid signature")
+	}
+
+	manifest, err := NewManifest(path, key.algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.SHA256 != signed.SHA256 {
+		missing, added := diffFindings(signed.Findings, manifest.Findings)
+		return nil, errors.Errorf("output file was altered: %d signed findings missing, %d findings added", missing, added)
+	}
+	return &signed.Manifest, nil
+}
+
+// diffFindings returns the number of signed findings hashes missing from the current ones and of current ones added
+func diffFindings(signed, current []string) (int, int) {
+	counts := make(map[string]int, len(signed))
+	for _, finding := range signed {
+		counts[finding]++
+	}
+	added := 0
+	for _, finding := range current {
+		if counts[finding] > 0 {
+			counts[finding]--
+		} else {
+			added++
+		}
+	}
+	missing := 0
+	for _, count := range counts {
+		missing += count
+	}
+	return missing, added
+}
@@ -0,0 +1,67 @@
+package output
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignOutputHMAC(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.txt")
+	require.Nil(t, os.WriteFile(path, []byte("finding-a\r\nfinding-b\n\nfinding-c\n"), 0644), "could not write output")
+
+	key, err := ParseSigningKey([]byte("secret\n"))
+	require.Nil(t, err, "could not parse hmac key")
+	require.True(t, key.CanSign(), "hmac key can't sign")
+	require.Nil(t, SignOutput(path, key), "could not sign output")
+
+	manifest, err := VerifyOutput(path, key)
+	require.Nil(t, err, "could not verify output")
+	require.Equal(t, SignatureHMAC, manifest.Algorithm, "wrong algorithm")
+	require.Equal(t, "results.txt", manifest.File, "wrong file")
+	require.Len(t, manifest.Findings, 3, "wrong number of findings")
+
+	otherKey, err := ParseSigningKey([]byte("other"))
+	require.Nil(t, err, "could not parse hmac key")
+	_, err = VerifyOutput(path, otherKey)
+	require.NotNil(t, err, "output verified with wrong key")
+
+	require.Nil(t, os.WriteFile(path, []byte("finding-a\nfinding-c\nfinding-d\nfinding-e\n"), 0644), "could not alter output")
+	_, err = VerifyOutput(path, key)
+	require.EqualError(t, err, "output file was altered: 1 signed findings missing, 2 findings added")
+}
+
+func TestSignOutputEd25519(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err, "could not generate key")
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.Nil(t, err, "could not marshal private key")
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.Nil(t, err, "could not marshal public key")
+
+	signer, err := ParseSigningKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	require.Nil(t, err, "could not parse private key")
+	verifier, err := ParseSigningKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	require.Nil(t, err, "could not parse public key")
+	require.False(t, verifier.CanSign(), "public key can sign")
+
+	path := filepath.Join(t.TempDir(), "results.json")
+	require.Nil(t, os.WriteFile(path, []byte("{\"template-id\":\"a\"}\n"), 0644), "could not write output")
+	require.Nil(t, SignOutput(path, signer), "could not sign output")
+	require.NotNil(t, SignOutput(path, verifier), "output signed with public key")
+
+	manifest, err := VerifyOutput(path, verifier)
+	require.Nil(t, err, "could not verify output")
+	require.Equal(t, SignatureEd25519, manifest.Algorithm, "wrong algorithm")
+
+	hmacKey, err := ParseSigningKey([]byte("secret"))
+	require.Nil(t, err, "could not parse hmac key")
+	_, err = VerifyOutput(path, hmacKey)
+	require.NotNil(t, err, "ed25519 signature verified with hmac key")
+}
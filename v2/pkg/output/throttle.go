@@ -0,0 +1,110 @@
+package output
+
+import (
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// maxThrottledBatches is the number of batches of findings buffered by the
+// throttler after which new findings are dropped from the webhook and counted
+// as overflow. The file and screen output always get every finding.
+const maxThrottledBatches = 10
+
+// findingsThrottler buffers the findings and flushes them in batches of at
+// most batchSize findings every interval, capping the rate of the findings
+// sent to the downstream integrations such as the webhook.
+type findingsThrottler struct {
+	batchSize int
+	interval  time.Duration
+	flush     func(event *ResultEvent)
+
+	mutex    sync.Mutex
+	buffer   []*ResultEvent
+	overflow int
+	dropped  int
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newFindingsThrottler returns a throttler flushing the findings with flush
+func newFindingsThrottler(batchSize int, interval time.Duration, flush func(event *ResultEvent)) *findingsThrottler {
+	throttler := &findingsThrottler{
+		batchSize: batchSize,
+		interval:  interval,
+		flush:     flush,
+		done:      make(chan struct{}),
+	}
+	throttler.wg.Add(1)
+	go throttler.run()
+	return throttler
+}
+
+// Add buffers a finding until the next flush, dropping it if the buffer is full
+func (t *findingsThrottler) Add(event *ResultEvent) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.buffer) >= t.batchSize*maxThrottledBatches {
+		t.overflow++
+		t.dropped++
+		return
+	}
+	t.buffer = append(t.buffer, event)
+}
+
+// run flushes a batch of findings every interval until the throttler is closed
+func (t *findingsThrottler) run() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.flushBatch(t.batchSize)
+		case <-t.done:
+			t.flushBatch(-1)
+			return
+		}
+	}
+}
+
+// flushBatch flushes up to size buffered findings, all of them if size is negative
+func (t *findingsThrottler) flushBatch(size int) {
+	t.mutex.Lock()
+	if size < 0 || size > len(t.buffer) {
+		size = len(t.buffer)
+	}
+	batch := t.buffer[:size:size]
+	t.buffer = t.buffer[size:]
+	overflow := t.overflow
+	t.overflow = 0
+	t.mutex.Unlock()
+
+	if overflow > 0 {
+		gologger.Warning().Msgf("Dropped %d findings from the webhook exceeding the buffer of %d findings", overflow, t.batchSize*maxThrottledBatches)
+	}
+	for _, event := range batch {
+		t.flush(event)
+	}
+}
+
+// Dropped returns the number of findings dropped as the buffer was full
+func (t *findingsThrottler) Dropped() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.dropped
+}
+
+// Close flushes all the buffered findings and stops the throttler
+func (t *findingsThrottler) Close() {
+	close(t.done)
+	t.wg.Wait()
+	if dropped := t.Dropped(); dropped > 0 {
+		gologger.Warning().Msgf("Dropped %d findings in total from the webhook as it was throttled", dropped)
+	}
+}
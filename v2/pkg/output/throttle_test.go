@@ -0,0 +1,45 @@
+package output
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindingsThrottler(t *testing.T) {
+	var mutex sync.Mutex
+	var flushed []string
+	throttler := newFindingsThrottler(2, 50*time.Millisecond, func(event *ResultEvent) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		flushed = append(flushed, event.TemplateID)
+	})
+	flushedCount := func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(flushed)
+	}
+
+	// the buffer holds 10 batches of 2 findings, the 5 others overflow
+	for i := 0; i < 25; i++ {
+		throttler.Add(&ResultEvent{TemplateID: string(rune('a' + i))})
+	}
+	require.Equal(t, 5, throttler.Dropped(), "wrong number of dropped findings")
+	require.Equal(t, 0, flushedCount(), "findings flushed before the interval")
+
+	require.Eventually(t, func() bool { return flushedCount() >= 2 }, time.Second, 10*time.Millisecond, "findings not flushed")
+	require.Less(t, flushedCount(), 20, "findings flushed without throttling")
+	require.Zero(t, flushedCount()%2, "findings not flushed in batches")
+
+	throttler.Close()
+	require.Equal(t, 20, flushedCount(), "buffered findings not flushed on close")
+	require.Equal(t, "abcdefghijklmnopqrst", func() string {
+		var ids string
+		for _, id := range flushed {
+			ids += id
+		}
+		return ids
+	}(), "findings flushed out of order")
+}
@@ -0,0 +1,145 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+const (
+	// webhookQueueSize is the number of findings waiting to be sent after
+	// which new findings are dropped instead of blocking the scan.
+	webhookQueueSize = 1000
+	// webhookRetries is the number of retries of a failed webhook request
+	webhookRetries = 3
+)
+
+// webhookRetryWait is the wait before the first retry of a failed webhook
+// request, doubled for each following retry.
+var webhookRetryWait = time.Second
+
+// webhookSender posts the findings to a webhook as they are found
+type webhookSender struct {
+	url      string
+	headers  map[string]string
+	template *template.Template
+	client   *http.Client
+	queue    chan []byte
+	wg       sync.WaitGroup
+}
+
+// ParseWebhookHeaders parses the webhook headers in header:value format
+func ParseWebhookHeaders(values []string) (map[string]string, error) {
+	headers := make(map[string]string, len(values))
+	for _, value := range values {
+		name, headerValue, ok := strings.Cut(value, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, errors.Errorf("invalid webhook header %s", value)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+	}
+	return headers, nil
+}
+
+// newWebhookSender creates a webhook sender posting the findings to url,
+// rendered with the output template if any or as json otherwise.
+func newWebhookSender(url string, headers []string, outputTemplate string) (*webhookSender, error) {
+	parsedHeaders, err := ParseWebhookHeaders(headers)
+	if err != nil {
+		return nil, err
+	}
+	sender := &webhookSender{
+		url:     url,
+		headers: parsedHeaders,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		queue:   make(chan []byte, webhookQueueSize),
+	}
+	if outputTemplate != "" {
+		if sender.template, err = ParseOutputTemplate(outputTemplate); err != nil {
+			return nil, errors.Wrap(err, "invalid webhook template")
+		}
+	}
+
+	sender.wg.Add(1)
+	go sender.run()
+	return sender, nil
+}
+
+// Send queues a finding to be posted to the webhook, dropping it if the queue is full
+func (s *webhookSender) Send(event *ResultEvent) {
+	var body []byte
+	var err error
+	if s.template != nil {
+		body, err = renderTemplate(s.template, event)
+	} else {
+		body, err = jsoniter.Marshal(event)
+	}
+	if err != nil {
+		gologger.Warning().Msgf("Could not format finding for webhook: %s\n", err)
+		return
+	}
+
+	select {
+	case s.queue <- body:
+	default:
+		gologger.Warning().Msgf("[%s] Dropping finding on %s: Webhook queue is full\n", event.TemplateID, event.Matched)
+	}
+}
+
+// run posts the queued findings until the queue is closed
+func (s *webhookSender) run() {
+	defer s.wg.Done()
+
+	for body := range s.queue {
+		wait := webhookRetryWait
+		err := s.post(body)
+		for retry := 0; err != nil && retry < webhookRetries; retry++ {
+			time.Sleep(wait)
+			wait *= 2
+			err = s.post(body)
+		}
+		if err != nil {
+			gologger.Warning().Msgf("Could not send finding to webhook: %s\n", err)
+		}
+	}
+}
+
+// post posts a finding to the webhook
+func (s *webhookSender) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "could not make request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("webhook responded with status %d: %s", resp.StatusCode, bytes.TrimSpace(data))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Close sends the queued findings and stops the sender
+func (s *webhookSender) Close() {
+	close(s.queue)
+	s.wg.Wait()
+}
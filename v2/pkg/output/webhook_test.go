@@ -0,0 +1,91 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/model"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+func TestStandardWriterWebhook(t *testing.T) {
+	webhookRetryWait = time.Millisecond
+
+	var mutex sync.Mutex
+	var bodies []string
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"), "missing webhook header")
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"), "wrong content type")
+		// fail the first attempt to check the finding is retried
+		if attempts++; attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	writer, err := NewStandardWriter(&types.Options{
+		WebhookURL:      server.URL,
+		WebhookHeaders:  []string{"Authorization: Bearer token"},
+		WebhookTemplate: `{"text":{{json .Matched}},"severity":"{{.Severity}}"}`,
+	})
+	require.Nil(t, err, "could not create writer")
+
+	info := model.Info{Name: "Exposed Panel", SeverityHolder: severity.Holder{Severity: severity.High}}
+	require.Nil(t, writer.Write(&ResultEvent{TemplateID: "panel", Info: info, Matched: "https://example.com/admin", MatcherStatus: true}), "could not write finding")
+	require.Nil(t, writer.Write(&ResultEvent{TemplateID: "panel", Info: info, Host: "https://other.example.com"}), "could not write failure")
+	writer.Close()
+
+	require.Equal(t, []string{`{"text":"https://example.com/admin","severity":"high"}`}, bodies, "wrong findings posted")
+	require.Equal(t, 2, attempts, "failed request not retried")
+}
+
+func TestParseWebhookHeaders(t *testing.T) {
+	headers, err := ParseWebhookHeaders([]string{"Authorization: Bearer a:b", "X-Team:security"})
+	require.Nil(t, err, "could not parse headers")
+	require.Equal(t, map[string]string{"Authorization": "Bearer a:b", "X-Team": "security"}, headers, "wrong headers")
+
+	_, err = ParseWebhookHeaders([]string{"invalid"})
+	require.NotNil(t, err, "invalid header parsed")
+}
+
+func TestStandardWriterThrottledWebhook(t *testing.T) {
+	var mutex sync.Mutex
+	var posted int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		posted++
+	}))
+	defer server.Close()
+
+	// the buffer of the throttler holds 10 findings until the writer is closed
+	writer, err := NewStandardWriter(&types.Options{JSONL: true, WebhookURL: server.URL, OutputBatchSize: 1, OutputFlushInterval: time.Hour})
+	require.Nil(t, err, "could not create writer")
+	outputWriter := &testWriteCloser{}
+	writer.outputFile = outputWriter
+
+	for i := 0; i < 15; i++ {
+		require.Nil(t, writer.Write(&ResultEvent{TemplateID: "panel", Matched: fmt.Sprintf("https://example.com/%d", i), MatcherStatus: true}), "could not write finding")
+	}
+	require.Equal(t, 15, strings.Count(outputWriter.String(), `"template-id":"panel"`), "findings dropped from the output file")
+	writer.Close()
+
+	require.Equal(t, 10, posted, "wrong number of findings posted to the throttled webhook")
+	require.Equal(t, 5, writer.throttler.Dropped(), "wrong number of findings dropped from the webhook")
+}
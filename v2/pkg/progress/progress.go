@@ -35,22 +35,29 @@ type Progress interface {
 	// IncrementFailedRequestsBy increments the number of requests counter by count
 	// along with errors.
 	IncrementFailedRequestsBy(count int64)
+	// IncrementSkippedRequestsBy increments the number of requests counter by count
+	// along with skipped requests.
+	IncrementSkippedRequestsBy(count int64)
 }
 
 var _ Progress = &StatsTicker{}
 
 // StatsTicker is a progress instance for showing program stats
 type StatsTicker struct {
-	cloud        bool
-	active       bool
-	outputJSON   bool
-	server       *http.Server
-	stats        clistats.StatisticsClient
-	tickDuration time.Duration
+	cloud      bool
+	active     bool
+	outputJSON bool
+	server     *http.Server
+	// prometheusServer exposes the statistics as prometheus metrics, nil if disabled
+	prometheusServer *http.Server
+	stats            clistats.StatisticsClient
+	tickDuration     time.Duration
 }
 
 // NewStatsTicker creates and returns a new progress tracking object.
-func NewStatsTicker(duration int, active, outputJSON, metrics, cloud bool, port int) (Progress, error) {
+//
+// Prometheus metrics are exposed on prometheusAddress if it is not empty.
+func NewStatsTicker(duration int, active, outputJSON, metrics, cloud bool, port int, prometheusAddress string) (Progress, error) {
 	var tickDuration time.Duration
 	if active && duration != -1 {
 		tickDuration = time.Duration(duration) * time.Second
@@ -85,6 +92,15 @@ func NewStatsTicker(duration int, active, outputJSON, metrics, cloud bool, port
 			}
 		}()
 	}
+	if prometheusAddress != "" {
+		registerPrometheusCounters(stats)
+		progress.prometheusServer = newPrometheusServer(prometheusAddress, stats)
+		go func() {
+			if err := progress.prometheusServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				gologger.Warning().Msgf("Could not serve prometheus metrics: %s", err)
+			}
+		}()
+	}
 	return progress, nil
 }
 
@@ -95,6 +111,7 @@ func (p *StatsTicker) Init(hostCount int64, rulesCount int, requestCount int64)
 	p.stats.AddStatic("startedAt", time.Now())
 	p.stats.AddCounter("requests", uint64(0))
 	p.stats.AddCounter("errors", uint64(0))
+	p.stats.AddCounter("skipped", uint64(0))
 	p.stats.AddCounter("matched", uint64(0))
 	p.stats.AddCounter("total", uint64(requestCount))
 
@@ -145,6 +162,13 @@ func (p *StatsTicker) IncrementFailedRequestsBy(count int64) {
 	p.stats.IncrementCounter("errors", int(count))
 }
 
+// IncrementSkippedRequestsBy increments the number of requests counter by count along with skipped requests.
+func (p *StatsTicker) IncrementSkippedRequestsBy(count int64) {
+	// mimic dropping by incrementing the completed requests
+	p.stats.IncrementCounter("requests", int(count))
+	p.stats.IncrementCounter("skipped", int(count))
+}
+
 func (p *StatsTicker) makePrintCallback() func(stats clistats.StatisticsClient) {
 	return func(stats clistats.StatisticsClient) {
 		builder := &strings.Builder{}
@@ -190,6 +214,11 @@ func (p *StatsTicker) makePrintCallback() func(stats clistats.StatisticsClient)
 			builder.WriteString(clistats.String(errors))
 		}
 
+		if skipped, ok := stats.GetCounter("skipped"); ok && skipped > 0 && !p.cloud {
+			builder.WriteString(" | Skipped: ")
+			builder.WriteString(clistats.String(skipped))
+		}
+
 		if okRequests && okTotal {
 			if p.cloud {
 				builder.WriteString(" | Task: ")
@@ -247,6 +276,8 @@ func metricsMap(stats clistats.StatisticsClient) map[string]interface{} {
 	results["rps"] = clistats.String(uint64(float64(requests) / duration.Seconds()))
 	errors, _ := stats.GetCounter("errors")
 	results["errors"] = clistats.String(errors)
+	skipped, _ := stats.GetCounter("skipped")
+	results["skipped"] = clistats.String(skipped)
 
 	// nolint:gomnd // this is not a magic number
 	percentData := (float64(requests) * float64(100)) / float64(total)
@@ -287,4 +318,7 @@ func (p *StatsTicker) Stop() {
 	if p.server != nil {
 		_ = p.server.Shutdown(context.Background())
 	}
+	if p.prometheusServer != nil {
+		_ = p.prometheusServer.Shutdown(context.Background())
+	}
 }
@@ -0,0 +1,155 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/projectdiscovery/clistats"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	templateTypes "github.com/projectdiscovery/nuclei/v2/pkg/templates/types"
+)
+
+// prometheusContentType is the content type of the prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// registerPrometheusCounters adds the counters of the requests by protocol
+// and of the results by severity exposed in the prometheus metrics.
+func registerPrometheusCounters(stats clistats.StatisticsClient) {
+	for _, protocol := range prometheusProtocols() {
+		stats.AddCounter("requests_"+protocol, uint64(0))
+		stats.AddCounter("errors_"+protocol, uint64(0))
+	}
+	for _, item := range severity.GetSupportedSeverities() {
+		stats.AddCounter("matched_"+item.String(), uint64(0))
+	}
+}
+
+// prometheusProtocols returns the names of the protocols requests are counted for
+func prometheusProtocols() []string {
+	var protocols []string
+	for _, protocol := range templateTypes.GetSupportedProtocolTypes() {
+		if name := protocol.String(); name != "" {
+			protocols = append(protocols, name)
+		}
+	}
+	return protocols
+}
+
+// newPrometheusServer returns a server exposing the statistics as prometheus metrics on /metrics
+func newPrometheusServer(address string, stats clistats.StatisticsClient) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", prometheusContentType)
+		writePrometheusMetrics(w, stats)
+	})
+	return &http.Server{Addr: address, Handler: mux}
+}
+
+// writePrometheusMetrics writes the statistics in the prometheus text exposition format
+func writePrometheusMetrics(w io.Writer, stats clistats.StatisticsClient) {
+	var duration time.Duration
+	if startedAt, ok := stats.GetStatic("startedAt"); ok {
+		if startedAtTime, ok := startedAt.(time.Time); ok {
+			duration = time.Since(startedAtTime)
+			writeMetric(w, "nuclei_start_time_seconds", "gauge", "Unix time the scan started at", startedAtTime.Unix())
+		}
+	}
+	templates, _ := stats.GetStatic("templates")
+	writeMetric(w, "nuclei_templates", "gauge", "Number of templates loaded for the scan", clistats.String(templates))
+	hosts, _ := stats.GetStatic("hosts")
+	writeMetric(w, "nuclei_hosts", "gauge", "Number of hosts to scan", clistats.String(hosts))
+
+	requests, _ := stats.GetCounter("requests")
+	writeMetric(w, "nuclei_requests_total", "counter", "Number of requests sent, including the failed and skipped ones", requests)
+	total, _ := stats.GetCounter("total")
+	writeMetric(w, "nuclei_requests_expected", "gauge", "Number of requests expected to be sent by the scan", total)
+	writeMetric(w, "nuclei_requests_per_second", "gauge", "Average number of requests sent per second since the scan started", perSecond(requests, duration))
+	errors, _ := stats.GetCounter("errors")
+	writeMetric(w, "nuclei_errors_total", "counter", "Number of errors encountered", errors)
+	skipped, _ := stats.GetCounter("skipped")
+	writeMetric(w, "nuclei_skipped_requests_total", "counter", "Number of requests skipped", skipped)
+	matched, _ := stats.GetCounter("matched")
+	writeMetric(w, "nuclei_matched_total", "counter", "Number of results matched", matched)
+
+	writeHeader(w, "nuclei_findings_total", "counter", "Number of results matched by severity")
+	for _, item := range severity.GetSupportedSeverities() {
+		count, _ := stats.GetCounter("matched_" + item.String())
+		fmt.Fprintf(w, "nuclei_findings_total{severity=%q} %d\n", item.String(), count)
+	}
+
+	protocols := prometheusProtocols()
+	writeHeader(w, "nuclei_protocol_requests_total", "counter", "Number of requests sent by protocol")
+	for _, protocol := range protocols {
+		count, _ := stats.GetCounter("requests_" + protocol)
+		fmt.Fprintf(w, "nuclei_protocol_requests_total{protocol=%q} %d\n", protocol, count)
+	}
+	writeHeader(w, "nuclei_protocol_errors_total", "counter", "Number of failed requests by protocol")
+	for _, protocol := range protocols {
+		count, _ := stats.GetCounter("errors_" + protocol)
+		fmt.Fprintf(w, "nuclei_protocol_errors_total{protocol=%q} %d\n", protocol, count)
+	}
+	writeHeader(w, "nuclei_protocol_requests_per_second", "gauge", "Average number of requests sent per second by protocol since the scan started")
+	for _, protocol := range protocols {
+		count, _ := stats.GetCounter("requests_" + protocol)
+		fmt.Fprintf(w, "nuclei_protocol_requests_per_second{protocol=%q} %v\n", protocol, perSecond(count, duration))
+	}
+
+	writeMetric(w, "nuclei_goroutines", "gauge", "Number of active goroutines", runtime.NumGoroutine())
+}
+
+// writeHeader writes the help and type lines of a metric
+func writeHeader(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// writeMetric writes a metric without labels along with its help and type lines
+func writeMetric(w io.Writer, name, metricType, help string, value interface{}) {
+	writeHeader(w, name, metricType, help)
+	fmt.Fprintf(w, "%s %v\n", name, value)
+}
+
+// perSecond returns the rate of a count over a duration, 0 for an empty duration
+func perSecond(count uint64, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 0
+	}
+	return float64(count) / duration.Seconds()
+}
+
+// metricsWriter is an output writer counting the requests by protocol and
+// the results by severity for the prometheus metrics.
+type metricsWriter struct {
+	output.Writer
+	stats clistats.StatisticsClient
+}
+
+// MetricsWriter wraps an output writer to count the requests and results written
+// in the prometheus metrics of the progress, if they are enabled.
+func MetricsWriter(writer output.Writer, progress Progress) output.Writer {
+	ticker, ok := progress.(*StatsTicker)
+	if !ok || ticker.prometheusServer == nil {
+		return writer
+	}
+	return &metricsWriter{Writer: writer, stats: ticker.stats}
+}
+
+// Write counts the result by severity and writes it to the output
+func (w *metricsWriter) Write(event *output.ResultEvent) error {
+	if event.MatcherStatus {
+		w.stats.IncrementCounter("matched_"+event.Info.SeverityHolder.Severity.String(), 1)
+	}
+	return w.Writer.Write(event)
+}
+
+// Request counts the request by protocol and logs it in the trace log
+func (w *metricsWriter) Request(templatePath, input, requestType string, requestErr error) {
+	w.stats.IncrementCounter("requests_"+requestType, 1)
+	if requestErr != nil {
+		w.stats.IncrementCounter("errors_"+requestType, 1)
+	}
+	w.Writer.Request(templatePath, input, requestType, requestErr)
+}
@@ -0,0 +1,67 @@
+package progress
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/model"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+)
+
+// nopWriter is an output writer discarding the results and requests
+type nopWriter struct {
+	output.Writer
+}
+
+func (w *nopWriter) Write(event *output.ResultEvent) error { return nil }
+
+func (w *nopWriter) Request(templatePath, input, requestType string, requestErr error) {}
+
+func TestPrometheusMetrics(t *testing.T) {
+	progress, err := NewStatsTicker(0, false, false, false, false, 0, "127.0.0.1:0")
+	require.Nil(t, err, "could not create progress")
+	defer progress.Stop()
+	progress.Init(2, 3, 10)
+
+	writer := MetricsWriter(&nopWriter{}, progress)
+	writer.Request("template.yaml", "https://example.com", "http", nil)
+	writer.Request("template.yaml", "https://example.com", "http", errors.New("timeout"))
+	writer.Request("template.yaml", "example.com", "dns", nil)
+	high := model.Info{SeverityHolder: severity.Holder{Severity: severity.High}}
+	require.Nil(t, writer.Write(&output.ResultEvent{Info: high, MatcherStatus: true}), "could not write result")
+	require.Nil(t, writer.Write(&output.ResultEvent{Info: high}), "could not write failure")
+	progress.IncrementRequests()
+	progress.IncrementMatched()
+
+	builder := &strings.Builder{}
+	writePrometheusMetrics(builder, progress.(*StatsTicker).stats)
+	metrics := builder.String()
+	for _, line := range []string{
+		"# TYPE nuclei_requests_total counter",
+		"nuclei_requests_total 1",
+		"nuclei_requests_expected 10",
+		"nuclei_templates 3",
+		"nuclei_hosts 2",
+		"nuclei_matched_total 1",
+		`nuclei_findings_total{severity="high"} 1`,
+		`nuclei_findings_total{severity="info"} 0`,
+		`nuclei_protocol_requests_total{protocol="http"} 2`,
+		`nuclei_protocol_errors_total{protocol="http"} 1`,
+		`nuclei_protocol_requests_total{protocol="dns"} 1`,
+	} {
+		require.Contains(t, metrics, line+"\n", "metric not exposed")
+	}
+	require.Contains(t, metrics, "nuclei_goroutines ", "goroutines not exposed")
+}
+
+func TestMetricsWriterDisabled(t *testing.T) {
+	progress, err := NewStatsTicker(0, false, false, false, false, 0, "")
+	require.Nil(t, err, "could not create progress")
+
+	writer := &nopWriter{}
+	require.Equal(t, output.Writer(writer), MetricsWriter(writer, progress), "writer wrapped without prometheus metrics")
+}
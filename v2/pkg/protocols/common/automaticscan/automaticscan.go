@@ -176,7 +176,7 @@ func (s *Service) processWappalyzerInputPair(input *contextargs.MetaInput) {
 	}
 
 	if s.opts.Options.Verbose {
-		gologger.Verbose().Msgf("Wappalyzer fingerprints %v for %s\n", normalized, input)
+		gologger.Verbose().Msgf("Wappalyzer fingerprints %v for %s\n", normalized, input.PrettyPrint())
 	}
 
 	for k := range normalized {
@@ -202,7 +202,7 @@ func (s *Service) processWappalyzerInputPair(input *contextargs.MetaInput) {
 	uniqueTags := sliceutil.Dedupe(items)
 
 	templatesList := s.store.LoadTemplatesWithTags(s.allTemplates, uniqueTags)
-	gologger.Info().Msgf("Executing tags (%v) for host %s (%d templates)", strings.Join(uniqueTags, ","), input, len(templatesList))
+	gologger.Info().Msgf("Executing tags (%v) for host %s (%d templates)", strings.Join(uniqueTags, ","), input.PrettyPrint(), len(templatesList))
 	for _, t := range templatesList {
 		s.opts.Progress.AddToTotal(int64(t.Executer.Requests()))
 
@@ -14,6 +14,19 @@ type MetaInput struct {
 	Input string `json:"input,omitempty"`
 	// CustomIP to use for connection
 	CustomIP string `json:"customIP,omitempty"`
+	// Request is the http request imported for the target if any
+	Request *HTTPRequest `json:"request,omitempty"`
+}
+
+// HTTPRequest is a http request imported along with a target, such as from
+// an OpenAPI spec, used by the http protocol as the base of its requests
+type HTTPRequest struct {
+	// Method is the method of the request
+	Method string `json:"method,omitempty"`
+	// Headers contains the headers of the request
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the body of the request
+	Body string `json:"body,omitempty"`
 }
 
 func (metaInput *MetaInput) marshalToBuffer() (bytes.Buffer, error) {
@@ -58,6 +71,7 @@ func (metaInput *MetaInput) Clone() *MetaInput {
 	return &MetaInput{
 		Input:    metaInput.Input,
 		CustomIP: metaInput.CustomIP,
+		Request:  metaInput.Request,
 	}
 }
 
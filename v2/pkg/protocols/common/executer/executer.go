@@ -19,6 +19,8 @@ import (
 type Executer struct {
 	requests []protocols.Request
 	options  *protocols.ExecuterOptions
+	// graph is the dependency graph of the requests, nil if they run sequentially
+	graph *requestGraph
 }
 
 var _ protocols.Executer = &Executer{}
@@ -47,7 +49,7 @@ func (e *Executer) Compile() error {
 			return err
 		}
 	}
-	return nil
+	return e.compileGraph()
 }
 
 // Requests returns the total number of requests the rule will perform
@@ -70,7 +72,51 @@ func (e *Executer) Execute(input *contextargs.Context) (bool, error) {
 		})
 	}
 	previous := make(map[string]interface{})
-	for _, req := range e.requests {
+	flow := newFlow()
+	var stopped bool
+	onEvent := func(req protocols.Request, prefix string, event *output.InternalWrappedEvent) {
+		flow.add(req, prefix, event)
+		ID := req.GetID()
+		if ID != "" {
+			builder := &strings.Builder{}
+			for k, v := range event.InternalEvent {
+				builder.WriteString(ID)
+				builder.WriteString("_")
+				builder.WriteString(k)
+				previous[builder.String()] = v
+				builder.Reset()
+			}
+		}
+		// If no results were found, and also interactsh is not being used
+		// in that case we can skip it, otherwise we've to show failure in
+		// case of matcher-status flag.
+		if !event.HasOperatorResult() && !event.UsesInteractsh {
+			if err := e.options.Output.WriteFailure(event.InternalEvent); err != nil {
+				gologger.Warning().Msgf("Could not write failure event to output: %s\n", err)
+			}
+		} else {
+			if writer.WriteResult(event, e.options.Output, e.options.Progress, e.options.IssuesClient) {
+				results.CompareAndSwap(false, true)
+			} else {
+				if err := e.options.Output.WriteFailure(event.InternalEvent); err != nil {
+					gologger.Warning().Msgf("Could not write failure event to output: %s\n", err)
+				}
+			}
+		}
+	}
+	shouldStop := func() bool {
+		return results.Load() && (e.options.StopAtFirstMatch || e.options.Options.StopAtFirstMatch)
+	}
+
+	requests := e.requests
+	// requests with dependencies are executed concurrently following the graph
+	if e.graph != nil {
+		stopped = e.executeGraph(input, dynamicValues, shouldStop, flow.next, onEvent)
+		requests = nil
+	}
+	for _, req := range requests {
+		req := req
+		prefix := flow.next(req)
 		inputItem := input.Clone()
 		if e.options.InputHelper != nil && input.MetaInput.Input != "" {
 			if inputItem.MetaInput.Input = e.options.InputHelper.Transform(inputItem.MetaInput.Input, req.Type()); inputItem.MetaInput.Input == "" {
@@ -79,33 +125,7 @@ func (e *Executer) Execute(input *contextargs.Context) (bool, error) {
 		}
 
 		err := req.ExecuteWithResults(inputItem, dynamicValues, previous, func(event *output.InternalWrappedEvent) {
-			ID := req.GetID()
-			if ID != "" {
-				builder := &strings.Builder{}
-				for k, v := range event.InternalEvent {
-					builder.WriteString(ID)
-					builder.WriteString("_")
-					builder.WriteString(k)
-					previous[builder.String()] = v
-					builder.Reset()
-				}
-			}
-			// If no results were found, and also interactsh is not being used
-			// in that case we can skip it, otherwise we've to show failure in
-			// case of matcher-status flag.
-			if !event.HasOperatorResult() && !event.UsesInteractsh {
-				if err := e.options.Output.WriteFailure(event.InternalEvent); err != nil {
-					gologger.Warning().Msgf("Could not write failure event to output: %s\n", err)
-				}
-			} else {
-				if writer.WriteResult(event, e.options.Output, e.options.Progress, e.options.IssuesClient) {
-					results.CompareAndSwap(false, true)
-				} else {
-					if err := e.options.Output.WriteFailure(event.InternalEvent); err != nil {
-						gologger.Warning().Msgf("Could not write failure event to output: %s\n", err)
-					}
-				}
-			}
+			onEvent(req, prefix, event)
 		})
 		if err != nil {
 			if e.options.HostErrorsCache != nil {
@@ -114,10 +134,19 @@ func (e *Executer) Execute(input *contextargs.Context) (bool, error) {
 			gologger.Warning().Msgf("[%s] Could not execute request for %s: %s\n", e.options.TemplateID, input.MetaInput.PrettyPrint(), err)
 		}
 		// If a match was found and stop at first match is set, break out of the loop and return
-		if results.Load() && (e.options.StopAtFirstMatch || e.options.Options.StopAtFirstMatch) {
+		if shouldStop() {
+			stopped = true
 			break
 		}
 	}
+	// Flow matchers are only evaluated once all the requests have run
+	if e.options.FlowOperators != nil && !stopped {
+		if event := flow.match(e.options.FlowOperators, previous, e.options.Options.Debug || e.options.Options.DebugResponse); event != nil {
+			if writer.WriteResult(event, e.options.Output, e.options.Progress, e.options.IssuesClient) {
+				results.CompareAndSwap(false, true)
+			}
+		}
+	}
 	return results.Load(), nil
 }
 
@@ -131,9 +160,40 @@ func (e *Executer) ExecuteWithResults(input *contextargs.Context, callback proto
 	}
 	previous := make(map[string]interface{})
 	results := &atomic.Bool{}
+	flow := newFlow()
+	var stopped bool
+	onEvent := func(req protocols.Request, prefix string, event *output.InternalWrappedEvent) {
+		flow.add(req, prefix, event)
+		ID := req.GetID()
+		if ID != "" {
+			builder := &strings.Builder{}
+			for k, v := range event.InternalEvent {
+				builder.WriteString(ID)
+				builder.WriteString("_")
+				builder.WriteString(k)
+				previous[builder.String()] = v
+				builder.Reset()
+			}
+		}
+		if event.OperatorsResult == nil {
+			return
+		}
+		results.CompareAndSwap(false, true)
+		callback(event)
+	}
+	shouldStop := func() bool {
+		return results.Load() && (e.options.StopAtFirstMatch || e.options.Options.StopAtFirstMatch)
+	}
 
-	for _, req := range e.requests {
+	requests := e.requests
+	// requests with dependencies are executed concurrently following the graph
+	if e.graph != nil {
+		stopped = e.executeGraph(input, dynamicValues, shouldStop, flow.next, onEvent)
+		requests = nil
+	}
+	for _, req := range requests {
 		req := req
+		prefix := flow.next(req)
 
 		inputItem := input.Clone()
 		if e.options.InputHelper != nil && input.MetaInput.Input != "" {
@@ -143,22 +203,7 @@ func (e *Executer) ExecuteWithResults(input *contextargs.Context, callback proto
 		}
 
 		err := req.ExecuteWithResults(inputItem, dynamicValues, previous, func(event *output.InternalWrappedEvent) {
-			ID := req.GetID()
-			if ID != "" {
-				builder := &strings.Builder{}
-				for k, v := range event.InternalEvent {
-					builder.WriteString(ID)
-					builder.WriteString("_")
-					builder.WriteString(k)
-					previous[builder.String()] = v
-					builder.Reset()
-				}
-			}
-			if event.OperatorsResult == nil {
-				return
-			}
-			results.CompareAndSwap(false, true)
-			callback(event)
+			onEvent(req, prefix, event)
 		})
 		if err != nil {
 			if e.options.HostErrorsCache != nil {
@@ -167,9 +212,16 @@ func (e *Executer) ExecuteWithResults(input *contextargs.Context, callback proto
 			gologger.Warning().Msgf("[%s] Could not execute request for %s: %s\n", e.options.TemplateID, input.MetaInput.PrettyPrint(), err)
 		}
 		// If a match was found and stop at first match is set, break out of the loop and return
-		if results.Load() && (e.options.StopAtFirstMatch || e.options.Options.StopAtFirstMatch) {
+		if shouldStop() {
+			stopped = true
 			break
 		}
 	}
+	// Flow matchers are only evaluated once all the requests have run
+	if e.options.FlowOperators != nil && !stopped {
+		if event := flow.match(e.options.FlowOperators, previous, e.options.Options.Debug || e.options.Options.DebugResponse); event != nil {
+			callback(event)
+		}
+	}
 	return nil
 }
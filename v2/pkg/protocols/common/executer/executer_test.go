@@ -0,0 +1,215 @@
+package executer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/generators"
+	templateTypes "github.com/projectdiscovery/nuclei/v2/pkg/templates/types"
+	"github.com/projectdiscovery/nuclei/v2/pkg/testutils"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// mockRequest is a request returning a static event with an optional extracted value
+type mockRequest struct {
+	id        string
+	event     output.InternalEvent
+	extractor string
+	extracted string
+	matched   bool
+	dependsOn []string
+	// received are the dynamic and previous values the request was executed with
+	received output.InternalEvent
+	// before is called before the request returns its event
+	before func()
+}
+
+func (m *mockRequest) Compile(options *protocols.ExecuterOptions) error { return nil }
+func (m *mockRequest) Requests() int                                    { return 1 }
+func (m *mockRequest) GetID() string                                    { return m.id }
+func (m *mockRequest) GetDependsOn() []string                           { return m.dependsOn }
+func (m *mockRequest) Match(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
+	return protocols.MakeDefaultMatchFunc(data, matcher)
+}
+func (m *mockRequest) Extract(data map[string]interface{}, extractor *extractors.Extractor) map[string]struct{} {
+	return protocols.MakeDefaultExtractFunc(data, extractor)
+}
+func (m *mockRequest) ExecuteWithResults(input *contextargs.Context, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	m.received = generators.MergeMaps(dynamicValues, previous)
+	if m.before != nil {
+		m.before()
+	}
+	event := &output.InternalWrappedEvent{InternalEvent: m.event}
+	if m.extractor != "" || m.matched {
+		event.OperatorsResult = &operators.Result{
+			Matched:       m.matched,
+			Extracted:     m.extractor != "",
+			Matches:       make(map[string][]string),
+			Extracts:      make(map[string][]string),
+			DynamicValues: make(map[string][]string),
+		}
+		if m.extractor != "" {
+			event.OperatorsResult.DynamicValues[m.extractor] = []string{m.extracted}
+		}
+		event.Results = m.MakeResultEvent(event)
+	}
+	callback(event)
+	return nil
+}
+func (m *mockRequest) MakeResultEventItem(wrapped *output.InternalWrappedEvent) *output.ResultEvent {
+	return &output.ResultEvent{Host: types.ToString(wrapped.InternalEvent["host"]), MatcherStatus: true}
+}
+func (m *mockRequest) MakeResultEvent(wrapped *output.InternalWrappedEvent) []*output.ResultEvent {
+	return protocols.MakeDefaultResultEvent(m, wrapped)
+}
+func (m *mockRequest) GetCompiledOperators() []*operators.Operators { return nil }
+func (m *mockRequest) Type() templateTypes.ProtocolType             { return templateTypes.HTTPProtocol }
+
+func TestExecuterFlowMatchers(t *testing.T) {
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+
+	newExecuter := func(stopAtFirstMatch bool, matched bool, dsl string) (*Executer, *[]*output.ResultEvent) {
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{ID: "flow-matchers"})
+		var results []*output.ResultEvent
+		executerOpts.Output.(*testutils.MockOutputWriter).WriteCallback = func(event *output.ResultEvent) {
+			results = append(results, event)
+		}
+		executerOpts.StopAtFirstMatch = stopAtFirstMatch
+
+		flowOperators := &operators.Operators{Matchers: []*matchers.Matcher{{Name: "same-token", Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher}, DSL: []string{dsl}}}}
+		require.Nil(t, flowOperators.Compile(), "could not compile flow operators")
+		executerOpts.FlowOperators = flowOperators
+
+		requests := []protocols.Request{
+			&mockRequest{event: output.InternalEvent{"host": "example.com", "status_code": 200}, extractor: "token", extracted: "abc", matched: matched},
+			&mockRequest{id: "login", event: output.InternalEvent{"host": "example.com", "status_code": 302, "body": "token=abc"}},
+		}
+		return NewExecuter(requests, executerOpts), &results
+	}
+
+	t.Run("match", func(t *testing.T) {
+		executer, results := newExecuter(false, false, `http_1_status_code == 200 && login_status_code == 302 && contains(http_2_body, token)`)
+		matched, err := executer.Execute(contextargs.NewWithInput("example.com"))
+		require.Nil(t, err, "could not execute flow")
+		require.True(t, matched, "could not match flow")
+		require.Len(t, *results, 1, "could not get flow result")
+		require.Equal(t, "same-token", (*results)[0].MatcherName, "could not get flow matcher name")
+	})
+	t.Run("no-match", func(t *testing.T) {
+		executer, results := newExecuter(false, false, `http_1_status_code == 302`)
+		matched, err := executer.Execute(contextargs.NewWithInput("example.com"))
+		require.Nil(t, err, "could not execute flow")
+		require.False(t, matched, "could match flow")
+		require.Empty(t, *results, "could get flow result")
+	})
+	t.Run("stop-at-first-match", func(t *testing.T) {
+		executer, results := newExecuter(true, true, `http_1_status_code == 200`)
+		matched, err := executer.Execute(contextargs.NewWithInput("example.com"))
+		require.Nil(t, err, "could not execute flow")
+		require.True(t, matched, "could not match first request")
+		require.Len(t, *results, 1, "flow matchers were evaluated after stop at first match")
+		require.Empty(t, (*results)[0].MatcherName, "flow matchers were evaluated after stop at first match")
+	})
+	t.Run("with-results", func(t *testing.T) {
+		executer, _ := newExecuter(false, false, `http_2_status_code == 302 && token == "abc"`)
+		var events []*output.InternalWrappedEvent
+		err := executer.ExecuteWithResults(contextargs.NewWithInput("example.com"), func(event *output.InternalWrappedEvent) {
+			events = append(events, event)
+		})
+		require.Nil(t, err, "could not execute flow")
+		require.Len(t, events, 2, "could not get flow event")
+		require.True(t, events[1].OperatorsResult.Matched, "could not match flow")
+	})
+}
+
+func TestRequestGraph(t *testing.T) {
+	graph, err := newRequestGraph([]protocols.Request{&mockRequest{id: "a"}, &mockRequest{id: "b"}})
+	require.Nil(t, err, "could not build graph")
+	require.Nil(t, graph, "got graph without dependencies")
+
+	graph, err = newRequestGraph([]protocols.Request{&mockRequest{id: "a"}, &mockRequest{id: "b", dependsOn: []string{"a"}}, &mockRequest{dependsOn: []string{"a", "b"}}})
+	require.Nil(t, err, "could not build graph")
+	require.Equal(t, [][]int{nil, {0}, {0, 1}}, graph.dependencies, "wrong graph dependencies")
+
+	_, err = newRequestGraph([]protocols.Request{&mockRequest{id: "a", dependsOn: []string{"missing"}}})
+	require.EqualError(t, err, "request a depends on unknown request missing")
+
+	_, err = newRequestGraph([]protocols.Request{&mockRequest{id: "a"}, &mockRequest{id: "a"}, &mockRequest{dependsOn: []string{"a"}}})
+	require.EqualError(t, err, "request #3 depends on a which is the id of multiple requests")
+
+	_, err = newRequestGraph([]protocols.Request{
+		&mockRequest{id: "a", dependsOn: []string{"c"}},
+		&mockRequest{id: "b", dependsOn: []string{"a"}},
+		&mockRequest{id: "c", dependsOn: []string{"b"}},
+	})
+	require.EqualError(t, err, "request dependencies contain a cycle: a -> c -> b -> a")
+
+	_, err = newRequestGraph([]protocols.Request{&mockRequest{id: "a", dependsOn: []string{"a"}}})
+	require.EqualError(t, err, "request dependencies contain a cycle: a -> a")
+}
+
+func TestExecuterRequestDependencies(t *testing.T) {
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{ID: "request-dependencies"})
+
+	// b and c only complete once both have started, which requires running them concurrently
+	started := &sync.WaitGroup{}
+	started.Add(2)
+	waitBoth := func() {
+		started.Done()
+		waited := make(chan struct{})
+		go func() {
+			started.Wait()
+			close(waited)
+		}()
+		select {
+		case <-waited:
+		case <-time.After(5 * time.Second):
+			t.Error("independent requests did not run concurrently")
+		}
+	}
+	login := &mockRequest{id: "login", event: output.InternalEvent{"status_code": 200}, extractor: "token", extracted: "abc"}
+	b := &mockRequest{id: "b", event: output.InternalEvent{"status_code": 201}, extractor: "b_value", extracted: "b", dependsOn: []string{"login"}, before: waitBoth}
+	c := &mockRequest{id: "c", event: output.InternalEvent{"status_code": 202}, extractor: "c_value", extracted: "c", dependsOn: []string{"login"}, before: waitBoth}
+	d := &mockRequest{id: "d", event: output.InternalEvent{"status_code": 203}, dependsOn: []string{"b", "c"}}
+	unrelated := &mockRequest{event: output.InternalEvent{"status_code": 204}}
+
+	executer := NewExecuter([]protocols.Request{d, c, b, login, unrelated}, executerOpts)
+	require.Nil(t, executer.Compile(), "could not compile executer")
+
+	var events int
+	err := executer.ExecuteWithResults(contextargs.NewWithInput("example.com"), func(event *output.InternalWrappedEvent) {
+		events++
+	})
+	require.Nil(t, err, "could not execute requests")
+	require.Equal(t, 3, events, "could not get events of requests")
+
+	require.Equal(t, "abc", b.received["token"], "could not pass extracted value to dependent")
+	require.Equal(t, 200, b.received["login_status_code"], "could not pass response value to dependent")
+	require.NotContains(t, b.received, "c_value", "got value of request running concurrently")
+	require.Equal(t, "abc", d.received["token"], "could not pass extracted value to indirect dependent")
+	require.Equal(t, "b", d.received["b_value"], "could not pass extracted value to dependent")
+	require.Equal(t, "c", d.received["c_value"], "could not pass extracted value to dependent")
+	require.Equal(t, 202, d.received["c_status_code"], "could not pass response value to dependent")
+	require.NotContains(t, unrelated.received, "token", "got value of unrelated request")
+
+	t.Run("cycle", func(t *testing.T) {
+		executer := NewExecuter([]protocols.Request{
+			&mockRequest{id: "a", dependsOn: []string{"b"}},
+			&mockRequest{id: "b", dependsOn: []string{"a"}},
+		}, executerOpts)
+		require.ErrorContains(t, executer.Compile(), "request dependencies contain a cycle: a -> b -> a")
+	})
+}
@@ -0,0 +1,93 @@
+package executer
+
+import (
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
+)
+
+// flow accumulates the responses of all the requests of a template
+// so that flow matchers can be evaluated once every request has run.
+//
+// The data available to flow matchers contains:
+//
+//	<key>                  - values of the last response of the flow
+//	<protocol>_<n>_<key>   - values of the last response of the nth request of a protocol (e.g. http_1_body)
+//	<id>_<key>             - values of the last response of requests with an id
+//	<extractor>            - values of named and internal extractors of all the requests
+//
+// Extracted values are a single value if only one value was extracted, otherwise a list.
+type flow struct {
+	data     map[string]interface{}
+	extracts map[string]interface{}
+	counts   map[string]int
+	last     protocols.Request
+}
+
+func newFlow() *flow {
+	return &flow{
+		data:     make(map[string]interface{}),
+		extracts: make(map[string]interface{}),
+		counts:   make(map[string]int),
+	}
+}
+
+// next returns the prefix for the responses of the next request of the flow
+func (f *flow) next(request protocols.Request) string {
+	protocol := request.Type().String()
+	f.counts[protocol]++
+	return fmt.Sprintf("%s_%d_", protocol, f.counts[protocol])
+}
+
+// add adds a response event of a request to the flow
+func (f *flow) add(request protocols.Request, prefix string, event *output.InternalWrappedEvent) {
+	for k, v := range event.InternalEvent {
+		f.data[k] = v
+		f.data[prefix+k] = v
+	}
+	f.last = request
+
+	if event.OperatorsResult == nil {
+		return
+	}
+	for _, values := range []map[string][]string{event.OperatorsResult.Extracts, event.OperatorsResult.DynamicValues} {
+		for name, extracted := range values {
+			if name == "" || len(extracted) == 0 {
+				continue
+			}
+			if len(extracted) == 1 {
+				f.extracts[name] = extracted[0]
+			} else {
+				f.extracts[name] = extracted
+			}
+		}
+	}
+}
+
+// match evaluates the flow operators on the accumulated responses and returns
+// an event if the flow matched. Nil is returned if no request has responded.
+func (f *flow) match(compiled *operators.Operators, previous map[string]interface{}, isDebug bool) *output.InternalWrappedEvent {
+	if f.last == nil {
+		return nil
+	}
+	data := make(map[string]interface{}, len(f.data)+len(previous)+len(f.extracts))
+	for k, v := range f.data {
+		data[k] = v
+	}
+	for k, v := range previous {
+		data[k] = v
+	}
+	for k, v := range f.extracts {
+		data[k] = v
+	}
+
+	result, ok := compiled.Execute(data, protocols.MakeDefaultMatchFunc, protocols.MakeDefaultExtractFunc, isDebug)
+	if !ok || result == nil {
+		return nil
+	}
+	event := &output.InternalWrappedEvent{InternalEvent: data, OperatorsResult: result}
+	event.Results = f.last.MakeResultEvent(event)
+	return event
+}
@@ -0,0 +1,256 @@
+package executer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+)
+
+// dependentRequest is implemented by requests declaring dependencies
+// on other requests of the template with depends-on.
+type dependentRequest interface {
+	// GetDependsOn returns the IDs of the requests the request depends on.
+	GetDependsOn() []string
+}
+
+// requestGraph is the dependency graph of the requests of a template.
+type requestGraph struct {
+	// dependencies contains the indexes of the requests each request depends on
+	dependencies [][]int
+}
+
+// newRequestGraph builds the dependency graph of requests, returning nil
+// if none of the requests declares dependencies.
+//
+// An error is returned if a dependency is unknown or ambiguous, or if
+// the dependencies contain a cycle.
+func newRequestGraph(requests []protocols.Request) (*requestGraph, error) {
+	ids := make(map[string]int)
+	duplicated := make(map[string]struct{})
+	for i, request := range requests {
+		id := request.GetID()
+		if id == "" {
+			continue
+		}
+		if _, ok := ids[id]; ok {
+			duplicated[id] = struct{}{}
+		}
+		ids[id] = i
+	}
+
+	var hasDependencies bool
+	dependencies := make([][]int, len(requests))
+	for i, request := range requests {
+		dependent, ok := request.(dependentRequest)
+		if !ok {
+			continue
+		}
+		for _, id := range dependent.GetDependsOn() {
+			index, ok := ids[id]
+			if !ok {
+				return nil, fmt.Errorf("request %s depends on unknown request %s", requestName(requests, i), id)
+			}
+			if _, ok := duplicated[id]; ok {
+				return nil, fmt.Errorf("request %s depends on %s which is the id of multiple requests", requestName(requests, i), id)
+			}
+			dependencies[i] = append(dependencies[i], index)
+			hasDependencies = true
+		}
+	}
+	if !hasDependencies {
+		return nil, nil
+	}
+	if cycle := findCycle(dependencies); cycle != nil {
+		names := make([]string, len(cycle))
+		for i, index := range cycle {
+			names[i] = requestName(requests, index)
+		}
+		return nil, fmt.Errorf("request dependencies contain a cycle: %s", strings.Join(names, " -> "))
+	}
+	return &requestGraph{dependencies: dependencies}, nil
+}
+
+// requestName returns the id of a request, or its position in the template if it has none
+func requestName(requests []protocols.Request, index int) string {
+	if id := requests[index].GetID(); id != "" {
+		return id
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
+
+// findCycle returns the indexes of the requests forming a dependency cycle,
+// starting and ending with the same request, or nil if there is no cycle.
+func findCycle(dependencies [][]int) []int {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(dependencies))
+	var path []int
+
+	var visit func(node int) []int
+	visit = func(node int) []int {
+		state[node] = visiting
+		path = append(path, node)
+		for _, dependency := range dependencies[node] {
+			switch state[dependency] {
+			case visiting:
+				for i, index := range path {
+					if index == dependency {
+						return append(append([]int{}, path[i:]...), dependency)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[node] = visited
+		return nil
+	}
+	for node := range dependencies {
+		if state[node] == unvisited {
+			if cycle := visit(node); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// graphNode contains the values a request of the graph passes on to its dependents
+type graphNode struct {
+	// values are the dynamic values of the request including the extracted ones
+	values map[string]interface{}
+	// previous are the <id>_<key> values of the request and its dependencies
+	previous map[string]interface{}
+}
+
+// executeGraph executes the requests concurrently, each one as soon as the requests
+// it depends on have completed. Every request receives the values extracted by its
+// dependencies, direct or not, along with their <id>_<key> values.
+//
+// Events are passed to onEvent one at a time. Requests not started yet are skipped
+// once stop returns true, in which case executeGraph returns true as well.
+func (e *Executer) executeGraph(input *contextargs.Context, dynamicValues map[string]interface{}, stop func() bool, prefix func(protocols.Request) string, onEvent func(protocols.Request, string, *output.InternalWrappedEvent)) bool {
+	nodes := make([]graphNode, len(e.requests))
+	done := make([]chan struct{}, len(e.requests))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	// prefixes are assigned in template order so they don't depend on the scheduling
+	prefixes := make([]string, len(e.requests))
+	for i, req := range e.requests {
+		prefixes[i] = prefix(req)
+	}
+
+	mutex := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	for i, req := range e.requests {
+		wg.Add(1)
+		go func(i int, req protocols.Request) {
+			defer wg.Done()
+			defer close(done[i])
+
+			values := make(map[string]interface{}, len(dynamicValues))
+			for k, v := range dynamicValues {
+				values[k] = v
+			}
+			previous := make(map[string]interface{})
+			for _, dependency := range e.graph.dependencies[i] {
+				<-done[dependency]
+				for k, v := range nodes[dependency].values {
+					values[k] = v
+				}
+				for k, v := range nodes[dependency].previous {
+					previous[k] = v
+				}
+			}
+			node := graphNode{values: values, previous: previous}
+			defer func() {
+				mutex.Lock()
+				nodes[i] = node
+				mutex.Unlock()
+			}()
+
+			if stop() {
+				return
+			}
+			inputItem := input.Clone()
+			if e.options.InputHelper != nil && input.MetaInput.Input != "" {
+				if inputItem.MetaInput.Input = e.options.InputHelper.Transform(inputItem.MetaInput.Input, req.Type()); inputItem.MetaInput.Input == "" {
+					return
+				}
+			}
+
+			// the request gets its own copies as requests may update them
+			requestValues := make(map[string]interface{}, len(values))
+			for k, v := range values {
+				requestValues[k] = v
+			}
+			requestPrevious := make(map[string]interface{}, len(previous))
+			for k, v := range previous {
+				requestPrevious[k] = v
+			}
+			extracted := make(map[string]interface{})
+			own := make(map[string]interface{})
+			err := req.ExecuteWithResults(inputItem, requestValues, requestPrevious, func(event *output.InternalWrappedEvent) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				onEvent(req, prefixes[i], event)
+				if id := req.GetID(); id != "" {
+					for k, v := range event.InternalEvent {
+						own[id+"_"+k] = v
+					}
+				}
+				if event.OperatorsResult != nil {
+					for name, value := range event.OperatorsResult.DynamicValues {
+						if len(value) == 1 {
+							extracted[name] = value[0]
+						} else if len(value) > 1 {
+							extracted[name] = value
+						}
+					}
+				}
+			})
+			if err != nil {
+				if e.options.HostErrorsCache != nil {
+					e.options.HostErrorsCache.MarkFailed(input.MetaInput.ID(), err)
+				}
+				gologger.Warning().Msgf("[%s] Could not execute request for %s: %s\n", e.options.TemplateID, input.MetaInput.PrettyPrint(), err)
+			}
+
+			mutex.Lock()
+			for k, v := range extracted {
+				node.values[k] = v
+			}
+			for k, v := range own {
+				node.previous[k] = v
+			}
+			mutex.Unlock()
+		}(i, req)
+	}
+	wg.Wait()
+	return stop()
+}
+
+// compileGraph builds the dependency graph of the requests of the executer
+func (e *Executer) compileGraph() error {
+	graph, err := newRequestGraph(e.requests)
+	if err != nil {
+		return errors.Wrap(err, "could not compile request dependencies")
+	}
+	e.graph = graph
+	return nil
+}
@@ -13,7 +13,7 @@ import (
 type PayloadGenerator struct {
 	Type     AttackType
 	catalog  catalog.Catalog
-	payloads map[string][]string
+	payloads map[string]ValueGenerator
 }
 
 // New creates a new generator structure for payload generation
@@ -111,19 +111,19 @@ func (i *Iterator) Total() int {
 	switch i.Type {
 	case BatteringRamAttack:
 		for _, p := range i.payloads {
-			count += len(p.values)
+			count += p.values.Len()
 		}
 	case PitchForkAttack:
-		count = len(i.payloads[0].values)
+		count = i.payloads[0].values.Len()
 		for _, p := range i.payloads {
-			if count > len(p.values) {
-				count = len(p.values)
+			if count > p.values.Len() {
+				count = p.values.Len()
 			}
 		}
 	case ClusterBombAttack:
 		count = 1
 		for _, p := range i.payloads {
-			count *= len(p.values)
+			count = saturatedProduct(count, p.values.Len())
 		}
 	}
 	return count
@@ -229,12 +229,12 @@ func (i *Iterator) clusterbombIteratorReset() {
 type payloadIterator struct {
 	index  int
 	name   string
-	values []string
+	values ValueGenerator
 }
 
 // next returns true if there are more values in payload iterator
 func (i *payloadIterator) next() bool {
-	return i.index < len(i.values)
+	return i.index < i.values.Len()
 }
 
 // resetPosition resets the position of the payload iterator
@@ -249,5 +249,5 @@ func (i *payloadIterator) incrementPosition() {
 
 // value returns the value of the payload at an index
 func (i *payloadIterator) value() string {
-	return i.values[i.index]
+	return i.values.Value(i.index)
 }
@@ -10,9 +10,10 @@ import (
 	"github.com/spf13/cast"
 )
 
-// loadPayloads loads the input payloads from a map to a data map
-func (generator *PayloadGenerator) loadPayloads(payloads map[string]interface{}, templatePath, templateDirectory string, sandbox bool) (map[string][]string, error) {
-	loadedPayloads := make(map[string][]string)
+// loadPayloads loads the input payloads from a map to a data map,
+// payloads declared as maps being created from their value generator.
+func (generator *PayloadGenerator) loadPayloads(payloads map[string]interface{}, templatePath, templateDirectory string, sandbox bool) (map[string]ValueGenerator, error) {
+	loadedPayloads := make(map[string]ValueGenerator)
 
 	for name, payload := range payloads {
 		switch pt := payload.(type) {
@@ -20,7 +21,7 @@ func (generator *PayloadGenerator) loadPayloads(payloads map[string]interface{},
 			elements := strings.Split(pt, "\n")
 			//golint:gomnd // this is not a magic number
 			if len(elements) >= 2 {
-				loadedPayloads[name] = elements
+				loadedPayloads[name] = staticValues(elements)
 			} else {
 				if sandbox {
 					pt = filepath.Clean(pt)
@@ -33,10 +34,18 @@ func (generator *PayloadGenerator) loadPayloads(payloads map[string]interface{},
 				if err != nil {
 					return nil, errors.Wrap(err, "could not load payloads")
 				}
-				loadedPayloads[name] = payloads
+				loadedPayloads[name] = staticValues(payloads)
 			}
 		case interface{}:
-			loadedPayloads[name] = cast.ToStringSlice(pt)
+			if options, ok := generatorOptions(pt); ok {
+				values, err := newValueGenerator(options)
+				if err != nil {
+					return nil, errors.Wrapf(err, "could not load payload %s", name)
+				}
+				loadedPayloads[name] = values
+				continue
+			}
+			loadedPayloads[name] = staticValues(cast.ToStringSlice(pt))
 		}
 	}
 	return loadedPayloads, nil
@@ -27,14 +27,14 @@ func TestLoadPayloads(t *testing.T) {
 			"new": fullpath,
 		}, "/test", tempdir, true)
 		require.NoError(t, err, "could not load payloads")
-		require.Equal(t, map[string][]string{"new": {"test", "another"}}, values, "could not get values")
+		require.Equal(t, map[string]ValueGenerator{"new": staticValues{"test", "another"}}, values, "could not get values")
 	})
 	t.Run("template-directory", func(t *testing.T) {
 		values, err := generator.loadPayloads(map[string]interface{}{
 			"new": fullpath,
 		}, filepath.Join(tempdir, "test.yaml"), "/test", true)
 		require.NoError(t, err, "could not load payloads")
-		require.Equal(t, map[string][]string{"new": {"test", "another"}}, values, "could not get values")
+		require.Equal(t, map[string]ValueGenerator{"new": staticValues{"test", "another"}}, values, "could not get values")
 	})
 	t.Run("no-sandbox-unix", func(t *testing.T) {
 		if osutils.IsWindows() {
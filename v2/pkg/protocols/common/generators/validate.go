@@ -43,6 +43,10 @@ func (g *PayloadGenerator) validate(payloads map[string]interface{}, templatePat
 				return fmt.Errorf("the %s file for payload %s does not exist or does not contain enough elements", payloadType, name)
 			}
 		case interface{}:
+			// value generators are validated when created
+			if _, ok := generatorOptions(payloadType); ok {
+				continue
+			}
 			loadedPayloads := types.ToStringSlice(payloadType)
 			if len(loadedPayloads) == 0 {
 				return fmt.Errorf("the payload %s does not contain enough elements", name)
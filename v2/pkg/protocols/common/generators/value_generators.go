@@ -0,0 +1,328 @@
+package generators
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// MaxGeneratedValues is the maximum number of values of a value generator,
+// larger generators requiring a limit.
+const MaxGeneratedValues = 10_000_000
+
+// ValueGenerator generates the values of a payload lazily from their index,
+// so that large payload spaces don't have to be enumerated in files. Values
+// are random access so that generators work with every attack type.
+type ValueGenerator interface {
+	// Len returns the number of values of the generator
+	Len() int
+	// Value returns the value at index, in [0, Len())
+	Value(index int) string
+}
+
+// ValueGeneratorFunc creates a value generator from the options of a payload
+type ValueGeneratorFunc func(options map[string]interface{}) (ValueGenerator, error)
+
+var (
+	valueGeneratorsMutex sync.RWMutex
+	valueGenerators      = map[string]ValueGeneratorFunc{
+		"range":   newRangeGenerator,
+		"date":    newDateGenerator,
+		"charset": newCharsetGenerator,
+	}
+)
+
+// RegisterValueGenerator registers a value generator that payloads can reference
+// by name, replacing any generator registered with the same name.
+func RegisterValueGenerator(name string, generator ValueGeneratorFunc) {
+	valueGeneratorsMutex.Lock()
+	defer valueGeneratorsMutex.Unlock()
+
+	valueGenerators[strings.ToLower(name)] = generator
+}
+
+// ValueGenerators returns the names of the registered value generators
+func ValueGenerators() []string {
+	valueGeneratorsMutex.RLock()
+	defer valueGeneratorsMutex.RUnlock()
+
+	names := make([]string, 0, len(valueGenerators))
+	for name := range valueGenerators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// generatorOptions returns the options of a payload declared as a map
+func generatorOptions(payload interface{}) (map[string]interface{}, bool) {
+	switch payload.(type) {
+	case map[string]interface{}, map[interface{}]interface{}:
+		options, err := cast.ToStringMapE(payload)
+		return options, err == nil
+	}
+	return nil, false
+}
+
+// newValueGenerator creates the value generator of a payload from its options,
+// the generator option naming the generator and limit bounding its values.
+func newValueGenerator(options map[string]interface{}) (ValueGenerator, error) {
+	name := strings.ToLower(cast.ToString(options["generator"]))
+	if name == "" {
+		return nil, errors.New("payload map is missing the generator")
+	}
+	valueGeneratorsMutex.RLock()
+	create, ok := valueGenerators[name]
+	valueGeneratorsMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown payload generator %s, supported generators are %s", name, strings.Join(ValueGenerators(), ", "))
+	}
+	generator, err := create(options)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create %s payload generator", name)
+	}
+
+	limit, err := cast.ToIntE(options["limit"])
+	if err != nil || limit < 0 {
+		return nil, fmt.Errorf("invalid limit %v for %s payload generator", options["limit"], name)
+	}
+	if limit > 0 && limit < generator.Len() {
+		generator = &limitedGenerator{ValueGenerator: generator, limit: limit}
+	}
+	if generator.Len() > MaxGeneratedValues {
+		return nil, fmt.Errorf("%s payload generator yields more than %d values, use a limit", name, MaxGeneratedValues)
+	}
+	if generator.Len() == 0 {
+		return nil, fmt.Errorf("%s payload generator yields no values", name)
+	}
+	return generator, nil
+}
+
+// staticValues are the values of a payload list or file
+type staticValues []string
+
+func (s staticValues) Len() int {
+	return len(s)
+}
+
+func (s staticValues) Value(index int) string {
+	return s[index]
+}
+
+// limitedGenerator bounds the values of a generator to the first limit ones
+type limitedGenerator struct {
+	ValueGenerator
+	limit int
+}
+
+func (l *limitedGenerator) Len() int {
+	return l.limit
+}
+
+// rangeGenerator generates the numbers from start to end by step
+type rangeGenerator struct {
+	start, step int
+	count       int
+	format      string
+}
+
+// newRangeGenerator creates a generator of the numbers from start (0 by default)
+// to end included, incrementing by step (1 by default, negative to count down)
+// and formatted with the format verb (%d by default, such as %05d or %x).
+func newRangeGenerator(options map[string]interface{}) (ValueGenerator, error) {
+	if options["end"] == nil {
+		return nil, errors.New("end is required")
+	}
+	start, err := cast.ToIntE(options["start"])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid start")
+	}
+	end, err := cast.ToIntE(options["end"])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid end")
+	}
+	step := 1
+	if options["step"] != nil {
+		if step, err = cast.ToIntE(options["step"]); err != nil {
+			return nil, errors.Wrap(err, "invalid step")
+		}
+	}
+	if step == 0 || (end-start)/step < 0 {
+		return nil, errors.New("step doesn't go from start to end")
+	}
+	format := cast.ToString(options["format"])
+	if format == "" {
+		format = "%d"
+	}
+	return &rangeGenerator{start: start, step: step, count: saturatedCount((end-start)/step, 1), format: format}, nil
+}
+
+func (r *rangeGenerator) Len() int {
+	return r.count
+}
+
+func (r *rangeGenerator) Value(index int) string {
+	return fmt.Sprintf(r.format, r.start+index*r.step)
+}
+
+// dateGenerator generates the dates from start to end by step
+type dateGenerator struct {
+	start  time.Time
+	step   time.Duration
+	count  int
+	layout string
+}
+
+// dateLayouts are the layouts accepted for the start and end of date generators
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// newDateGenerator creates a generator of the dates from start to end included,
+// incrementing by step (1d by default, a duration which also accepts days with
+// the d unit) and formatted with the go time layout (2006-01-02 by default).
+func newDateGenerator(options map[string]interface{}) (ValueGenerator, error) {
+	start, err := parseGeneratorDate(cast.ToString(options["start"]))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid start")
+	}
+	end, err := parseGeneratorDate(cast.ToString(options["end"]))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid end")
+	}
+	step := 24 * time.Hour
+	if value := cast.ToString(options["step"]); value != "" {
+		if step, err = parseGeneratorDuration(value); err != nil {
+			return nil, errors.Wrap(err, "invalid step")
+		}
+	}
+	span := end.Sub(start)
+	if step == 0 || span/step < 0 {
+		return nil, errors.New("step doesn't go from start to end")
+	}
+	layout := cast.ToString(options["format"])
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+	return &dateGenerator{start: start, step: step, count: saturatedCount(int(span/step), 1), layout: layout}, nil
+}
+
+func (d *dateGenerator) Len() int {
+	return d.count
+}
+
+func (d *dateGenerator) Value(index int) string {
+	return d.start.Add(time.Duration(index) * d.step).Format(d.layout)
+}
+
+// parseGeneratorDate parses a date in one of the accepted layouts
+func parseGeneratorDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("date is required")
+	}
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("could not parse date %s", value)
+}
+
+// parseGeneratorDuration parses a duration, with days as the d unit
+func parseGeneratorDuration(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		count, err := cast.ToIntE(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// charsetGenerator generates the strings of the characters of a charset
+type charsetGenerator struct {
+	charset []rune
+	// counts are the number of strings of each length from min to max
+	counts []int
+	min    int
+	count  int
+}
+
+// newCharsetGenerator creates a generator of all the strings made of the
+// characters of charset with a length from min (1 by default) to max (min by
+// default), shorter strings first and in the order of the charset.
+func newCharsetGenerator(options map[string]interface{}) (ValueGenerator, error) {
+	charset := []rune(cast.ToString(options["charset"]))
+	if len(charset) == 0 {
+		return nil, errors.New("charset is required")
+	}
+	min := 1
+	if options["min"] != nil {
+		value, err := cast.ToIntE(options["min"])
+		if err != nil || value < 1 {
+			return nil, fmt.Errorf("invalid min %v", options["min"])
+		}
+		min = value
+	}
+	max := min
+	if options["max"] != nil {
+		value, err := cast.ToIntE(options["max"])
+		if err != nil || value < min {
+			return nil, fmt.Errorf("invalid max %v", options["max"])
+		}
+		max = value
+	}
+
+	generator := &charsetGenerator{charset: charset, min: min}
+	for length := min; length <= max; length++ {
+		count := 1
+		for i := 0; i < length; i++ {
+			count = saturatedProduct(count, len(charset))
+		}
+		generator.counts = append(generator.counts, count)
+		generator.count = saturatedCount(generator.count, count)
+	}
+	return generator, nil
+}
+
+func (c *charsetGenerator) Len() int {
+	return c.count
+}
+
+func (c *charsetGenerator) Value(index int) string {
+	length := c.min
+	for _, count := range c.counts {
+		if index < count {
+			break
+		}
+		index -= count
+		length++
+	}
+	value := make([]rune, length)
+	for i := length - 1; i >= 0; i-- {
+		value[i] = c.charset[index%len(c.charset)]
+		index /= len(c.charset)
+	}
+	return string(value)
+}
+
+// saturatedCount returns a+b, saturated at math.MaxInt
+func saturatedCount(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// saturatedProduct returns a*b, saturated at math.MaxInt
+func saturatedProduct(a, b int) int {
+	if a != 0 && b > math.MaxInt/a {
+		return math.MaxInt
+	}
+	return a * b
+}
@@ -0,0 +1,91 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/catalog/disk"
+)
+
+func generatedValues(t *testing.T, options map[string]interface{}) []string {
+	generator, err := newValueGenerator(options)
+	require.Nil(t, err, "could not create value generator")
+
+	values := make([]string, 0, generator.Len())
+	for i := 0; i < generator.Len(); i++ {
+		values = append(values, generator.Value(i))
+	}
+	return values
+}
+
+func TestValueGenerators(t *testing.T) {
+	t.Run("range", func(t *testing.T) {
+		values := generatedValues(t, map[string]interface{}{"generator": "range", "start": 8, "end": 12, "step": 2, "format": "%03d"})
+		require.Equal(t, []string{"008", "010", "012"}, values, "could not get range values")
+
+		values = generatedValues(t, map[string]interface{}{"generator": "range", "start": 3, "end": 1, "step": -1})
+		require.Equal(t, []string{"3", "2", "1"}, values, "could not get descending range values")
+	})
+	t.Run("date", func(t *testing.T) {
+		values := generatedValues(t, map[string]interface{}{"generator": "date", "start": "2023-02-27", "end": "2023-03-02"})
+		require.Equal(t, []string{"2023-02-27", "2023-02-28", "2023-03-01", "2023-03-02"}, values, "could not get date values")
+
+		values = generatedValues(t, map[string]interface{}{"generator": "date", "start": "2023-01-01", "end": "2023-01-15", "step": "7d", "format": "20060102"})
+		require.Equal(t, []string{"20230101", "20230108", "20230115"}, values, "could not get weekly date values")
+	})
+	t.Run("charset", func(t *testing.T) {
+		values := generatedValues(t, map[string]interface{}{"generator": "charset", "charset": "ab", "min": 1, "max": 2})
+		require.Equal(t, []string{"a", "b", "aa", "ab", "ba", "bb"}, values, "could not get charset values")
+	})
+	t.Run("limit", func(t *testing.T) {
+		values := generatedValues(t, map[string]interface{}{"generator": "charset", "charset": "abcdefghijklmnopqrstuvwxyz", "max": 8, "limit": 3})
+		require.Equal(t, []string{"a", "b", "c"}, values, "could not get limited values")
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, options := range []map[string]interface{}{
+			{"start": 1, "end": 2},
+			{"generator": "unknown"},
+			{"generator": "range", "start": 1},
+			{"generator": "range", "start": 1, "end": 5, "step": -1},
+			{"generator": "date", "start": "2023-01-02", "end": "2023-01-01"},
+			{"generator": "charset", "charset": "ab", "min": 2, "max": 1},
+			{"generator": "charset", "charset": "abcdefghijklmnopqrstuvwxyz", "max": 8},
+			{"generator": "range", "end": 100000000},
+		} {
+			_, err := newValueGenerator(options)
+			require.NotNil(t, err, "could create invalid value generator %v", options)
+		}
+	})
+	t.Run("register", func(t *testing.T) {
+		RegisterValueGenerator("constant", func(options map[string]interface{}) (ValueGenerator, error) {
+			return staticValues{"constant"}, nil
+		})
+		values := generatedValues(t, map[string]interface{}{"generator": "constant"})
+		require.Equal(t, []string{"constant"}, values, "could not get registered generator values")
+	})
+}
+
+func TestClusterbombValueGenerator(t *testing.T) {
+	usernames := []string{"admin", "root"}
+
+	catalogInstance := disk.NewCatalog("")
+	generator, err := New(map[string]interface{}{
+		"username": usernames,
+		"id":       map[interface{}]interface{}{"generator": "range", "start": 1, "end": 3},
+	}, ClusterBombAttack, "", false, catalogInstance, "")
+	require.Nil(t, err, "could not create generator")
+
+	iterator := generator.NewIterator()
+	require.Equal(t, 6, iterator.Total(), "could not get correct clusterbomb total")
+
+	seen := make(map[string]struct{})
+	for {
+		value, ok := iterator.Value()
+		if !ok {
+			break
+		}
+		seen[value["username"].(string)+":"+value["id"].(string)] = struct{}{}
+	}
+	require.Len(t, seen, 6, "could not get all clusterbomb combinations")
+}
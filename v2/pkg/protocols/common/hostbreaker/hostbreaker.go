@@ -0,0 +1,293 @@
+package hostbreaker
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// DefaultBaseDelay is the delay before the first retry of a throttled request
+const DefaultBaseDelay = time.Second
+
+// maxRetryAfter bounds Retry-After values before the configured maximum delay is applied
+const maxRetryAfter = 24 * time.Hour
+
+// DefaultMaxDelay is the maximum delay used when none is configured
+const DefaultMaxDelay = 30 * time.Second
+
+// Breaker protects rate-limited or failing hosts from being hammered.
+//
+// Throttled requests (429 and 503 responses) are retried with an exponential
+// backoff honoring the Retry-After header, during which every other request
+// to the same host waits as well. Once a host fails threshold times in a row
+// its circuit opens and requests to it are skipped until the cooldown expires,
+// after which a single trial request either closes or reopens the circuit.
+// If rate headers are respected, requests to a host also wait for the delay
+// advertised by the rate limit headers of its responses.
+type Breaker struct {
+	options Options
+	hosts   sync.Map
+
+	trips   atomic.Int64
+	retries atomic.Int64
+	skipped atomic.Int64
+}
+
+// Options contains the configuration options for the breaker
+type Options struct {
+	// MaxRetries is the maximum number of retries of a throttled request (0 = disabled)
+	MaxRetries int
+	// BaseDelay is the delay before the first retry, doubled for every further retry
+	BaseDelay time.Duration
+	// MaxDelay is the maximum delay before a retry, including Retry-After values
+	MaxDelay time.Duration
+	// Threshold is the number of consecutive failures opening the circuit of a host (0 = disabled)
+	Threshold int
+	// Cooldown is the duration requests to a host are skipped once its circuit opens
+	Cooldown time.Duration
+	// RespectRateHeaders delays the requests to a host by the delay advertised by the
+	// rate limit headers of its responses, capped by the maximum delay
+	RespectRateHeaders bool
+}
+
+// Stats contains the statistics of the breaker
+type Stats struct {
+	// Trips is the number of times the circuit of a host was opened
+	Trips int64
+	// Retries is the number of throttled requests that were retried
+	Retries int64
+	// Skipped is the number of requests skipped due to an open circuit
+	Skipped int64
+}
+
+type hostState struct {
+	sync.Mutex
+	failures  int
+	trial     bool
+	openUntil time.Time
+	waitUntil time.Time
+}
+
+// New returns a new breaker. It returns nil if retries, circuit breaking
+// and rate headers are all disabled by the options.
+func New(options Options) *Breaker {
+	if options.MaxRetries <= 0 && options.Threshold <= 0 && !options.RespectRateHeaders {
+		return nil
+	}
+	if options.BaseDelay <= 0 {
+		options.BaseDelay = DefaultBaseDelay
+	}
+	if options.MaxDelay < options.BaseDelay {
+		options.MaxDelay = options.BaseDelay
+	}
+	return &Breaker{options: options}
+}
+
+func (b *Breaker) state(host string) *hostState {
+	value, _ := b.hosts.LoadOrStore(host, &hostState{})
+	return value.(*hostState)
+}
+
+// Allow waits for any pending backoff of a host and returns false if
+// the request must be skipped as the circuit of the host is open.
+//
+// Once the cooldown expires a single trial request is allowed, whose
+// result must be reported with Record. A nil breaker allows all requests.
+func (b *Breaker) Allow(host string) bool {
+	if b == nil {
+		return true
+	}
+	state := b.state(host)
+
+	state.Lock()
+	now := time.Now()
+	if b.options.Threshold > 0 && state.failures >= b.options.Threshold {
+		if now.Before(state.openUntil) {
+			state.Unlock()
+			b.skipped.Add(1)
+			return false
+		}
+		// half-open, keep others out while the trial request is in flight
+		state.openUntil = now.Add(b.options.Cooldown)
+		state.trial = true
+	}
+	wait := state.waitUntil.Sub(now)
+	state.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	return true
+}
+
+// Retry returns the delay after which a throttled request to a host should
+// be retried, or false if the response is not throttled or the retries are
+// exhausted. attempt is the number of retries already made for the request.
+func (b *Breaker) Retry(host string, attempt int, resp *http.Response) (time.Duration, bool) {
+	if b == nil || attempt >= b.options.MaxRetries || !IsThrottled(resp) {
+		return 0, false
+	}
+	delay := b.Backoff(attempt, resp.Header.Get("Retry-After"))
+	b.wait(host, delay)
+
+	b.retries.Add(1)
+	return delay, true
+}
+
+// Throttle delays the next requests to a host by the delay advertised by the
+// rate limit headers of its response, capped by the maximum delay, if rate
+// headers are respected. It returns the delay if any.
+func (b *Breaker) Throttle(host string, resp *http.Response) (time.Duration, bool) {
+	if b == nil || !b.options.RespectRateHeaders || resp == nil {
+		return 0, false
+	}
+	delay, ok := RateLimitDelay(resp.Header, time.Now())
+	if !ok || delay <= 0 {
+		return 0, false
+	}
+	if delay > b.options.MaxDelay {
+		delay = b.options.MaxDelay
+	}
+	b.wait(host, delay)
+	return delay, true
+}
+
+// wait makes the requests to a host wait for delay from now
+func (b *Breaker) wait(host string, delay time.Duration) {
+	state := b.state(host)
+	state.Lock()
+	if waitUntil := time.Now().Add(delay); waitUntil.After(state.waitUntil) {
+		state.waitUntil = waitUntil
+	}
+	state.Unlock()
+}
+
+// Record records the outcome of a request to a host. Errors and throttled
+// responses count as failures, anything else closes the circuit of the host.
+func (b *Breaker) Record(host string, resp *http.Response, err error) {
+	if b == nil || b.options.Threshold <= 0 {
+		return
+	}
+	state := b.state(host)
+	state.Lock()
+	defer state.Unlock()
+
+	trial := state.trial
+	state.trial = false
+	if err == nil && !IsThrottled(resp) {
+		state.failures = 0
+		return
+	}
+	state.failures++
+	// open on reaching the threshold and reopen on a failed trial request,
+	// late failures of requests sent before the circuit opened are ignored
+	if state.failures == b.options.Threshold || trial {
+		state.openUntil = time.Now().Add(b.options.Cooldown)
+		b.trips.Add(1)
+		gologger.Verbose().Msgf("Skipping requests to %s for %s after %d consecutive failures", host, b.options.Cooldown, state.failures)
+	}
+}
+
+// Backoff returns the delay before a retry. The Retry-After value, either in
+// seconds or as a http date, takes precedence over the exponential backoff.
+// The delay never exceeds the configured maximum.
+func (b *Breaker) Backoff(attempt int, retryAfter string) time.Duration {
+	delay, ok := parseRetryAfter(retryAfter, time.Now())
+	if !ok {
+		delay = b.options.BaseDelay
+		for i := 0; i < attempt && delay < b.options.MaxDelay; i++ {
+			delay *= 2
+		}
+	}
+	if delay > b.options.MaxDelay {
+		delay = b.options.MaxDelay
+	}
+	return delay
+}
+
+// Stats returns the statistics of the breaker
+func (b *Breaker) Stats() Stats {
+	if b == nil {
+		return Stats{}
+	}
+	return Stats{Trips: b.trips.Load(), Retries: b.retries.Load(), Skipped: b.skipped.Load()}
+}
+
+// IsThrottled returns true if the response signals the host is rate limiting
+// or temporarily unable to handle requests.
+func IsThrottled(resp *http.Response) bool {
+	return resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable)
+}
+
+// RateLimitDelay returns the delay advertised by the rate limit headers of a
+// response relative to now, which is the Retry-After header either in seconds
+// or as a http date, or the X-RateLimit-Reset (or RateLimit-Reset) header once
+// X-RateLimit-Remaining (or RateLimit-Remaining) is 0. Reset values are either
+// seconds or unix timestamps. Delays are bounded to a day.
+func RateLimitDelay(header http.Header, now time.Time) (time.Duration, bool) {
+	if delay, ok := parseRetryAfter(header.Get("Retry-After"), now); ok {
+		return delay, true
+	}
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		if strings.TrimSpace(header.Get(prefix+"Remaining")) != "0" {
+			continue
+		}
+		if delay, ok := parseRateLimitReset(header.Get(prefix+"Reset"), now); ok {
+			return delay, true
+		}
+	}
+	return 0, false
+}
+
+// minResetTimestamp is the value above which rate limit resets are unix
+// timestamps rather than seconds, a delay of over a year
+const minResetTimestamp = 365 * 24 * 60 * 60
+
+// parseRateLimitReset parses the value of a rate limit reset header relative to now
+func parseRateLimitReset(value string, now time.Time) (time.Duration, bool) {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	delay := time.Duration(seconds) * time.Second
+	if seconds > minResetTimestamp {
+		delay = time.Unix(seconds, 0).Sub(now)
+	}
+	if delay < 0 {
+		return 0, true
+	}
+	if delay > maxRetryAfter {
+		return maxRetryAfter, true
+	}
+	return delay, true
+}
+
+// parseRetryAfter parses the value of a Retry-After header relative to now
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		if seconds > int(maxRetryAfter/time.Second) {
+			return maxRetryAfter, true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := date.Sub(now); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
@@ -0,0 +1,137 @@
+package hostbreaker
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreakerDisabled(t *testing.T) {
+	breaker := New(Options{})
+	require.Nil(t, breaker, "breaker should be disabled")
+
+	breaker.Record("example.com", nil, errors.New("connection refused"))
+	require.True(t, breaker.Allow("example.com"), "disabled breaker skipped request")
+	_, retry := breaker.Retry("example.com", 0, &http.Response{StatusCode: http.StatusTooManyRequests})
+	require.False(t, retry, "disabled breaker retried request")
+	require.Equal(t, Stats{}, breaker.Stats(), "disabled breaker has stats")
+}
+
+func TestBreakerCircuit(t *testing.T) {
+	breaker := New(Options{Threshold: 3, Cooldown: 50 * time.Millisecond})
+
+	throttled := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	breaker.Record("example.com", nil, errors.New("connection refused"))
+	breaker.Record("example.com", throttled, nil)
+	require.True(t, breaker.Allow("example.com"), "circuit opened before threshold")
+	breaker.Record("example.com", throttled, nil)
+
+	require.False(t, breaker.Allow("example.com"), "circuit not opened at threshold")
+	require.True(t, breaker.Allow("another.com"), "circuit should be tracked per host")
+
+	time.Sleep(60 * time.Millisecond)
+	require.True(t, breaker.Allow("example.com"), "trial request not allowed after cooldown")
+	require.False(t, breaker.Allow("example.com"), "more than one trial request allowed")
+	breaker.Record("example.com", nil, errors.New("connection refused"))
+	require.False(t, breaker.Allow("example.com"), "circuit not reopened by failed trial")
+
+	time.Sleep(60 * time.Millisecond)
+	require.True(t, breaker.Allow("example.com"), "trial request not allowed after cooldown")
+	breaker.Record("example.com", &http.Response{StatusCode: http.StatusOK}, nil)
+	require.True(t, breaker.Allow("example.com"), "circuit not closed by successful trial")
+	require.True(t, breaker.Allow("example.com"), "circuit not closed by successful trial")
+
+	require.Equal(t, Stats{Trips: 2, Skipped: 3}, breaker.Stats(), "wrong breaker stats")
+}
+
+func TestBreakerRetry(t *testing.T) {
+	breaker := New(Options{MaxRetries: 2, BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second})
+
+	_, retry := breaker.Retry("example.com", 0, &http.Response{StatusCode: http.StatusOK})
+	require.False(t, retry, "successful response retried")
+
+	throttled := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	delay, retry := breaker.Retry("example.com", 0, throttled)
+	require.True(t, retry, "throttled response not retried")
+	require.Equal(t, 10*time.Millisecond, delay, "wrong first retry delay")
+
+	start := time.Now()
+	require.True(t, breaker.Allow("example.com"), "request skipped during backoff")
+	require.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond, "request did not wait for host backoff")
+
+	delay, retry = breaker.Retry("example.com", 1, throttled)
+	require.True(t, retry, "throttled response not retried")
+	require.Equal(t, 20*time.Millisecond, delay, "wrong second retry delay")
+
+	_, retry = breaker.Retry("example.com", 2, throttled)
+	require.False(t, retry, "retried more than max retries")
+	require.Equal(t, int64(2), breaker.Stats().Retries, "wrong retry stats")
+}
+
+func TestBreakerBackoff(t *testing.T) {
+	breaker := New(Options{MaxRetries: 5, BaseDelay: time.Second, MaxDelay: 10 * time.Second})
+
+	require.Equal(t, time.Second, breaker.Backoff(0, ""), "wrong backoff")
+	require.Equal(t, 4*time.Second, breaker.Backoff(2, ""), "wrong exponential backoff")
+	require.Equal(t, 10*time.Second, breaker.Backoff(10, ""), "backoff not capped")
+	require.Equal(t, 3*time.Second, breaker.Backoff(0, "3"), "retry-after seconds not honored")
+	require.Equal(t, 10*time.Second, breaker.Backoff(0, "99999999999"), "retry-after not capped")
+	require.Equal(t, 2*time.Second, breaker.Backoff(1, "invalid"), "invalid retry-after not ignored")
+
+	date := time.Now().Add(5 * time.Second).UTC().Format(http.TimeFormat)
+	delay := breaker.Backoff(0, date)
+	require.True(t, delay > 3*time.Second && delay <= 5*time.Second, "retry-after date not honored: %s", delay)
+	require.Equal(t, time.Duration(0), breaker.Backoff(0, "Mon, 02 Jan 2006 15:04:05 GMT"), "past retry-after date not honored")
+}
+
+func TestBreakerThrottle(t *testing.T) {
+	breaker := New(Options{RespectRateHeaders: true, BaseDelay: 10 * time.Millisecond, MaxDelay: 20 * time.Millisecond})
+	require.NotNil(t, breaker, "breaker should respect rate headers")
+
+	_, ok := breaker.Throttle("example.com", &http.Response{StatusCode: http.StatusOK, Header: http.Header{}})
+	require.False(t, ok, "response without rate headers throttled")
+
+	limited := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	limited.Header.Set("X-RateLimit-Remaining", "0")
+	limited.Header.Set("X-RateLimit-Reset", "60")
+	delay, ok := breaker.Throttle("example.com", limited)
+	require.True(t, ok, "exhausted rate limit not throttled")
+	require.Equal(t, 20*time.Millisecond, delay, "rate limit delay not capped")
+
+	start := time.Now()
+	require.True(t, breaker.Allow("example.com"), "request skipped while throttled")
+	require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond, "request did not wait for rate limit")
+
+	_, ok = New(Options{MaxRetries: 1}).Throttle("example.com", limited)
+	require.False(t, ok, "rate headers respected without option")
+}
+
+func TestRateLimitDelay(t *testing.T) {
+	now := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		headers map[string]string
+		delay   time.Duration
+		ok      bool
+	}{
+		{headers: map[string]string{"Retry-After": "5"}, delay: 5 * time.Second, ok: true},
+		{headers: map[string]string{"Retry-After": "Mon, 02 Jan 2023 15:04:35 GMT"}, delay: 30 * time.Second, ok: true},
+		{headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "10"}, delay: 10 * time.Second, ok: true},
+		{headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1672671905"}, delay: time.Minute, ok: true},
+		{headers: map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": "3"}, delay: 3 * time.Second, ok: true},
+		{headers: map[string]string{"X-RateLimit-Remaining": "10", "X-RateLimit-Reset": "10"}},
+		{headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "invalid"}},
+		{headers: map[string]string{}},
+	}
+	for _, test := range tests {
+		header := http.Header{}
+		for name, value := range test.headers {
+			header.Set(name, value)
+		}
+		delay, ok := RateLimitDelay(header, now)
+		require.Equal(t, test.ok, ok, "wrong rate limit detection for %v", test.headers)
+		require.Equal(t, test.delay, delay, "wrong rate limit delay for %v", test.headers)
+	}
+}
@@ -70,7 +70,7 @@ func (c *Cache) normalizeCacheValue(value string) string {
 				} else {
 					finalPort = "80"
 				}
-				hostname = net.JoinHostPort(parsed.Host, finalPort)
+				hostname = net.JoinHostPort(parsed.Hostname(), finalPort)
 			}
 			finalValue = hostname
 		}
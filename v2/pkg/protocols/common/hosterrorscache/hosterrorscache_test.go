@@ -139,3 +139,12 @@ func TestCacheMarkFailedConcurrent(t *testing.T) {
 		require.EqualValues(t, test.expected, value.errors.Load())
 	}
 }
+
+func TestCacheNormalizeIPv6(t *testing.T) {
+	cache := New(3, DefaultMaxHostsCount, nil)
+
+	require.Equal(t, "[::1]:443", cache.normalizeCacheValue("https://[::1]"), "could not normalize ipv6 url")
+	require.Equal(t, "[::1]:8443", cache.normalizeCacheValue("https://[::1]:8443/path"), "could not normalize ipv6 url with port")
+	require.Equal(t, "[fe80::1%eth0]:80", cache.normalizeCacheValue("http://[fe80::1%25eth0]/"), "could not normalize scoped ipv6 url")
+	require.Equal(t, "[::1]:8443", cache.normalizeCacheValue("[::1]:8443"), "ipv6 host port modified")
+}
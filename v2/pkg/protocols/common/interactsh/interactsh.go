@@ -150,6 +150,9 @@ func (c *Client) processInteractionForRequest(interaction *server.Interaction, d
 	data.Event.InternalEvent["interactsh_request"] = interaction.RawRequest
 	data.Event.InternalEvent["interactsh_response"] = interaction.RawResponse
 	data.Event.InternalEvent["interactsh_ip"] = interaction.RemoteAddress
+	if c.options.ExportInteractions {
+		data.interactions = append(data.interactions, &output.InteractionRecord{URL: c.interactionURL(interaction), Interaction: interaction})
+	}
 	data.Event.Unlock()
 
 	result, matched := data.Operators.Execute(data.Event.InternalEvent, data.MatchFunc, data.ExtractFunc, c.options.Debug || c.options.DebugRequest || c.options.DebugResponse)
@@ -170,6 +173,7 @@ func (c *Client) processInteractionForRequest(interaction *server.Interaction, d
 	data.Event.Results = data.MakeResultFunc(data.Event)
 	for _, event := range data.Event.Results {
 		event.Interaction = interaction
+		event.Interactions = data.interactions
 	}
 	data.Event.Unlock()
 
@@ -293,6 +297,9 @@ type RequestData struct {
 	Operators      *operators.Operators
 	MatchFunc      operators.MatchFunc
 	ExtractFunc    operators.ExtractFunc
+
+	// interactions are the interactions received for the request so far
+	interactions []*output.InteractionRecord
 }
 
 // RequestEvent is the event for a network request sent by nuclei.
@@ -321,6 +328,18 @@ func (c *Client) RequestEvent(interactshURLs []string, data *RequestData) {
 	}
 }
 
+// Interactions returns the interactions received so far for an interactsh url
+// which is not yet correlated to a request event. The interactions are kept
+// so that they are still processed once the request event is registered.
+func (c *Client) Interactions(interactshURL string) []*server.Interaction {
+	id := strings.TrimRight(strings.TrimSuffix(interactshURL, c.getHostname()), ".")
+	interactions, err := c.interactions.Get(id)
+	if err != nil {
+		return nil
+	}
+	return interactions
+}
+
 // HasMatchers returns true if an operator has interactsh part
 // matchers or extractors.
 //
@@ -354,6 +373,11 @@ func HasMarkers(data string) bool {
 	return interactshURLMarkerRegex.Match([]byte(data))
 }
 
+// GetMarkers returns the interactsh markers contained in the text
+func GetMarkers(data string) []string {
+	return interactshURLMarkerRegex.FindAllString(data, -1)
+}
+
 func (c *Client) debugPrintInteraction(interaction *server.Interaction, event *operators.Result) {
 	builder := &bytes.Buffer{}
 
@@ -403,6 +427,15 @@ func hash(internalEvent output.InternalEvent) string {
 	return fmt.Sprintf("%s:%s", templateId, host)
 }
 
+// interactionURL returns the interactsh url an interaction was received
+// for, which is made of its correlation id and the server hostname.
+func (c *Client) interactionURL(interaction *server.Interaction) string {
+	if hostname := c.getHostname(); hostname != "" {
+		return interaction.UniqueID + "." + hostname
+	}
+	return interaction.UniqueID
+}
+
 func (c *Client) getHostname() string {
 	c.RLock()
 	defer c.RUnlock()
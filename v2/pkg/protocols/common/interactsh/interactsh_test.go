@@ -0,0 +1,88 @@
+package interactsh
+
+import (
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/interactsh/pkg/server"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/progress"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingWriter struct {
+	output.Writer
+	results []*output.ResultEvent
+}
+
+func (w *recordingWriter) Write(event *output.ResultEvent) error {
+	w.results = append(w.results, event)
+	return nil
+}
+
+func TestProcessInteractionExportInteractions(t *testing.T) {
+	writer := &recordingWriter{}
+	progressImpl, _ := progress.NewStatsTicker(0, false, false, false, false, 0, "")
+	options := DefaultOptions(writer, nil, progressImpl)
+	options.ExportInteractions = true
+	client, err := New(options)
+	require.Nil(t, err, "could not create client")
+	client.setHostname("oast.example.com")
+
+	op := &operators.Operators{Matchers: []*matchers.Matcher{{Type: matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher}, Part: "interactsh_protocol", Words: []string{"http"}}}}
+	require.Nil(t, op.Compile(), "could not compile operators")
+	data := &RequestData{
+		Event:     &output.InternalWrappedEvent{InternalEvent: output.InternalEvent{"template-id": "oob", "host": "example.com"}},
+		Operators: op,
+		MatchFunc: func(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
+			value, _ := data[matcher.Part].(string)
+			return matcher.MatchWords(value, nil)
+		},
+		MakeResultFunc: func(wrapped *output.InternalWrappedEvent) []*output.ResultEvent {
+			return []*output.ResultEvent{{TemplateID: "oob", Host: "example.com"}}
+		},
+	}
+
+	dns := &server.Interaction{Protocol: "dns", UniqueID: "abcdef", FullId: "abcdef", RawRequest: "dns request", RemoteAddress: "10.0.0.1", Timestamp: time.Now()}
+	require.False(t, client.processInteractionForRequest(dns, data), "dns interaction matched")
+	http := &server.Interaction{Protocol: "http", UniqueID: "abcdef", FullId: "test.abcdef", RawRequest: "GET / HTTP/1.1", RemoteAddress: "10.0.0.2", Timestamp: time.Now()}
+	require.True(t, client.processInteractionForRequest(http, data), "http interaction not matched")
+
+	require.Len(t, writer.results, 1, "wrong number of results")
+	result := writer.results[0]
+	require.Equal(t, http, result.Interaction, "wrong matched interaction")
+	require.Equal(t, []*output.InteractionRecord{
+		{URL: "abcdef.oast.example.com", Interaction: dns},
+		{URL: "abcdef.oast.example.com", Interaction: http},
+	}, result.Interactions, "wrong correlated interactions")
+}
+
+func TestInteractionsBeforeRequestEvent(t *testing.T) {
+	writer := &recordingWriter{}
+	progressImpl, _ := progress.NewStatsTicker(0, false, false, false, false, 0, "")
+	client, err := New(DefaultOptions(writer, nil, progressImpl))
+	require.Nil(t, err, "could not create client")
+	client.setHostname("oast.example.com")
+
+	require.Empty(t, client.Interactions("abcdef.oast.example.com"), "got interactions without any received")
+	http := &server.Interaction{Protocol: "http", UniqueID: "abcdef", FullId: "abcdef", RawRequest: "GET / HTTP/1.1", RemoteAddress: "10.0.0.2", Timestamp: time.Now()}
+	require.Nil(t, client.interactions.Set("abcdef", []*server.Interaction{http}), "could not store interaction")
+	require.Equal(t, []*server.Interaction{http}, client.Interactions("abcdef.oast.example.com"), "wrong interactions")
+
+	op := &operators.Operators{Matchers: []*matchers.Matcher{{Type: matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher}, Part: "interactsh_protocol", Words: []string{"http"}}}}
+	require.Nil(t, op.Compile(), "could not compile operators")
+	client.RequestEvent([]string{"abcdef.oast.example.com"}, &RequestData{
+		Event:     &output.InternalWrappedEvent{InternalEvent: output.InternalEvent{"template-id": "oob", "host": "example.com"}},
+		Operators: op,
+		MatchFunc: func(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
+			value, _ := data[matcher.Part].(string)
+			return matcher.MatchWords(value, nil)
+		},
+		MakeResultFunc: func(wrapped *output.InternalWrappedEvent) []*output.ResultEvent {
+			return []*output.ResultEvent{{TemplateID: "oob", Host: "example.com"}}
+		},
+	})
+	require.Len(t, writer.results, 1, "observed interaction not correlated to the request event")
+}
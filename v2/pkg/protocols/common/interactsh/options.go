@@ -45,6 +45,8 @@ type Options struct {
 	NoInteractsh bool
 	// NoColor dissbles printing colors for matches
 	NoColor bool
+	// ExportInteractions adds all the interactions correlated with a matched request to its results
+	ExportInteractions bool
 
 	StopAtFirstMatch bool
 	HTTPClient       *retryablehttp.Client
@@ -0,0 +1,366 @@
+// Package ja3 implements tls client hello spoofing based on JA3 fingerprints.
+//
+// A JA3 fingerprint is made of five comma separated fields containing
+// dash separated decimal values:
+//
+//	SSLVersion,Ciphers,Extensions,EllipticCurves,EllipticCurvePointFormats
+//
+// Ciphers, extensions and curves are sent in the order of the fingerprint.
+// The content of the extensions is not part of the fingerprint, so known
+// extensions are sent with the values of common browsers and unknown
+// extensions are sent empty.
+package ja3
+
+import (
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	utls "github.com/refraction-networking/utls"
+)
+
+// Spec is a parsed JA3 fingerprint
+type Spec struct {
+	// Version is the tls version of the client hello
+	Version uint16
+	// CipherSuites are the cipher suites of the client hello
+	CipherSuites []uint16
+	// Extensions are the extension ids of the client hello
+	Extensions []uint16
+	// Curves are the supported elliptic curves of the client hello
+	Curves []uint16
+	// PointFormats are the supported elliptic curve point formats of the client hello
+	PointFormats []uint8
+}
+
+// Parse parses a JA3 fingerprint string
+func Parse(value string) (*Spec, error) {
+	fields := strings.Split(strings.TrimSpace(value), ",")
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid ja3 %q: expected 5 fields, got %d", value, len(fields))
+	}
+	version, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ja3 version")
+	}
+	switch uint16(version) {
+	case tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13:
+	default:
+		return nil, fmt.Errorf("invalid ja3 version %d", version)
+	}
+
+	spec := &Spec{Version: uint16(version)}
+	if spec.CipherSuites, err = parseList(fields[1]); err != nil {
+		return nil, errors.Wrap(err, "invalid ja3 ciphers")
+	}
+	if len(spec.CipherSuites) == 0 {
+		return nil, errors.New("invalid ja3: no ciphers specified")
+	}
+	if spec.Extensions, err = parseList(fields[2]); err != nil {
+		return nil, errors.Wrap(err, "invalid ja3 extensions")
+	}
+	if spec.Curves, err = parseList(fields[3]); err != nil {
+		return nil, errors.Wrap(err, "invalid ja3 curves")
+	}
+	pointFormats, err := parseList(fields[4])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ja3 point formats")
+	}
+	for _, pointFormat := range pointFormats {
+		if pointFormat > 0xff {
+			return nil, fmt.Errorf("invalid ja3 point format %d", pointFormat)
+		}
+		spec.PointFormats = append(spec.PointFormats, uint8(pointFormat))
+	}
+	return spec, nil
+}
+
+func parseList(value string) ([]uint16, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parts := strings.Split(value, "-")
+	values := make([]uint16, 0, len(parts))
+	for _, part := range parts {
+		parsed, err := strconv.ParseUint(part, 10, 16)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, uint16(parsed))
+	}
+	return values, nil
+}
+
+// String returns the JA3 fingerprint string of the spec
+func (s *Spec) String() string {
+	pointFormats := make([]uint16, 0, len(s.PointFormats))
+	for _, pointFormat := range s.PointFormats {
+		pointFormats = append(pointFormats, uint16(pointFormat))
+	}
+	return fingerprint(s.Version, s.CipherSuites, s.Extensions, s.Curves, pointFormats)
+}
+
+// ClientHelloSpec returns the utls client hello spec of the fingerprint
+// advertising the alpn protocols if the alpn extension is part of it.
+func (s *Spec) ClientHelloSpec(alpn []string) *utls.ClientHelloSpec {
+	maxVersion := s.Version
+	for _, extension := range s.Extensions {
+		if extension == 43 {
+			maxVersion = tls.VersionTLS13
+		}
+	}
+	if len(alpn) == 0 {
+		alpn = []string{"http/1.1"}
+	}
+
+	spec := &utls.ClientHelloSpec{
+		TLSVersMin:         tls.VersionTLS10,
+		TLSVersMax:         maxVersion,
+		CipherSuites:       s.CipherSuites,
+		CompressionMethods: []uint8{0},
+	}
+	for _, id := range s.Extensions {
+		spec.Extensions = append(spec.Extensions, s.extension(id, maxVersion, alpn))
+	}
+	return spec
+}
+
+var defaultSignatureAlgorithms = []utls.SignatureScheme{
+	utls.ECDSAWithP256AndSHA256,
+	utls.PSSWithSHA256,
+	utls.PKCS1WithSHA256,
+	utls.ECDSAWithP384AndSHA384,
+	utls.PSSWithSHA384,
+	utls.PKCS1WithSHA384,
+	utls.PSSWithSHA512,
+	utls.PKCS1WithSHA512,
+	utls.PKCS1WithSHA1,
+}
+
+// extension returns the utls extension of an extension id
+func (s *Spec) extension(id, maxVersion uint16, alpn []string) utls.TLSExtension {
+	switch id {
+	case 0:
+		return &utls.SNIExtension{}
+	case 5:
+		return &utls.StatusRequestExtension{}
+	case 10:
+		curves := make([]utls.CurveID, 0, len(s.Curves))
+		for _, curve := range s.Curves {
+			curves = append(curves, utls.CurveID(curve))
+		}
+		return &utls.SupportedCurvesExtension{Curves: curves}
+	case 11:
+		return &utls.SupportedPointsExtension{SupportedPoints: s.PointFormats}
+	case 13:
+		return &utls.SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: defaultSignatureAlgorithms}
+	case 16:
+		return &utls.ALPNExtension{AlpnProtocols: alpn}
+	case 17:
+		return &utls.StatusRequestV2Extension{}
+	case 18:
+		return &utls.SCTExtension{}
+	case 21:
+		return &utls.UtlsPaddingExtension{GetPaddingLen: utls.BoringPaddingStyle}
+	case 23:
+		return &utls.UtlsExtendedMasterSecretExtension{}
+	case 27:
+		return &utls.UtlsCompressCertExtension{Algorithms: []utls.CertCompressionAlgo{utls.CertCompressionBrotli}}
+	case 28:
+		return &utls.FakeRecordSizeLimitExtension{Limit: 0x4001}
+	case 34:
+		return &utls.FakeDelegatedCredentialsExtension{SupportedSignatureAlgorithms: defaultSignatureAlgorithms}
+	case 35:
+		return &utls.SessionTicketExtension{}
+	case 43:
+		versions := []uint16{utls.VersionTLS13, utls.VersionTLS12}
+		if s.Version < tls.VersionTLS12 {
+			versions = append(versions, utls.VersionTLS11, utls.VersionTLS10)
+		}
+		return &utls.SupportedVersionsExtension{Versions: versions}
+	case 45:
+		return &utls.PSKKeyExchangeModesExtension{Modes: []uint8{utls.PskModeDHE}}
+	case 50:
+		return &utls.SignatureAlgorithmsCertExtension{SupportedSignatureAlgorithms: defaultSignatureAlgorithms}
+	case 51:
+		return &utls.KeyShareExtension{KeyShares: []utls.KeyShare{{Group: s.keyShareCurve()}}}
+	case 13172:
+		return &utls.NPNExtension{}
+	case 17513:
+		return &utls.ApplicationSettingsExtension{SupportedProtocols: alpn}
+	case 30032:
+		return &utls.FakeChannelIDExtension{}
+	case 65281:
+		return &utls.RenegotiationInfoExtension{Renegotiation: utls.RenegotiateOnceAsClient}
+	}
+	return &utls.GenericExtension{Id: id}
+}
+
+// keyShareCurve returns the curve used for the tls 1.3 key share
+func (s *Spec) keyShareCurve() utls.CurveID {
+	for _, curve := range s.Curves {
+		switch utls.CurveID(curve) {
+		case utls.X25519, utls.CurveP256, utls.CurveP384, utls.CurveP521:
+			return utls.CurveID(curve)
+		}
+	}
+	return utls.X25519
+}
+
+// Conn is a tls connection using a spoofed client hello
+type Conn struct {
+	*utls.UConn
+	ja3 string
+}
+
+// JA3 returns the JA3 fingerprint of the client hello sent
+func (c *Conn) JA3() string {
+	return c.ja3
+}
+
+// Client returns a tls client connection for conn sending the client hello of spec.
+//
+// The handshake is performed on the first read or write, or when Handshake is called.
+func Client(conn net.Conn, spec *Spec, config *tls.Config, alpn []string) (*Conn, error) {
+	uconfig := &utls.Config{
+		ServerName:         config.ServerName,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+		MinVersion:         config.MinVersion,
+		Renegotiation:      utls.RenegotiationSupport(config.Renegotiation),
+	}
+	uconn := utls.UClient(conn, uconfig, utls.HelloCustom)
+	if err := uconn.ApplyPreset(spec.ClientHelloSpec(alpn)); err != nil {
+		return nil, errors.Wrap(err, "could not apply ja3 client hello")
+	}
+	if err := uconn.BuildHandshakeState(); err != nil {
+		return nil, errors.Wrap(err, "could not build ja3 client hello")
+	}
+	ja3, err := FromClientHello(uconn.HandshakeState.Hello.Raw)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{UConn: uconn, ja3: ja3}, nil
+}
+
+// Fingerprint returns the JA3 fingerprint of the client hello built for spec
+func Fingerprint(spec *Spec) (string, error) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	conn, err := Client(client, spec, &tls.Config{ServerName: "localhost"}, nil)
+	if err != nil {
+		return "", err
+	}
+	return conn.JA3(), nil
+}
+
+// Hash returns the md5 hash of a JA3 fingerprint
+func Hash(ja3 string) string {
+	hash := md5.Sum([]byte(ja3))
+	return hex.EncodeToString(hash[:])
+}
+
+// FromClientHello returns the JA3 fingerprint of a raw client hello handshake message
+func FromClientHello(raw []byte) (string, error) {
+	errMalformed := errors.New("malformed client hello")
+
+	// handshake type (1) + length (3) + version (2) + random (32)
+	if len(raw) < 38 || raw[0] != 1 {
+		return "", errMalformed
+	}
+	version := binary.BigEndian.Uint16(raw[4:6])
+	data := raw[38:]
+
+	readVector := func(lengthSize int) ([]byte, bool) {
+		if len(data) < lengthSize {
+			return nil, false
+		}
+		var length int
+		for _, b := range data[:lengthSize] {
+			length = length<<8 | int(b)
+		}
+		data = data[lengthSize:]
+		if len(data) < length {
+			return nil, false
+		}
+		vector := data[:length]
+		data = data[length:]
+		return vector, true
+	}
+
+	if _, ok := readVector(1); !ok { // session id
+		return "", errMalformed
+	}
+	cipherData, ok := readVector(2)
+	if !ok {
+		return "", errMalformed
+	}
+	if _, ok := readVector(1); !ok { // compression methods
+		return "", errMalformed
+	}
+	extensionData, _ := readVector(2)
+
+	var ciphers, extensions, curves, pointFormats []uint16
+	for i := 0; i+1 < len(cipherData); i += 2 {
+		ciphers = append(ciphers, binary.BigEndian.Uint16(cipherData[i:]))
+	}
+	for len(extensionData) >= 4 {
+		id := binary.BigEndian.Uint16(extensionData)
+		length := int(binary.BigEndian.Uint16(extensionData[2:]))
+		if len(extensionData) < 4+length {
+			return "", errMalformed
+		}
+		body := extensionData[4 : 4+length]
+		extensionData = extensionData[4+length:]
+		extensions = append(extensions, id)
+
+		switch id {
+		case 10:
+			if len(body) >= 2 {
+				for i := 2; i+1 < len(body); i += 2 {
+					curves = append(curves, binary.BigEndian.Uint16(body[i:]))
+				}
+			}
+		case 11:
+			if len(body) >= 1 {
+				for _, pointFormat := range body[1:] {
+					pointFormats = append(pointFormats, uint16(pointFormat))
+				}
+			}
+		}
+	}
+	return fingerprint(version, ciphers, extensions, curves, pointFormats), nil
+}
+
+// fingerprint builds a JA3 fingerprint string ignoring grease values
+func fingerprint(version uint16, ciphers, extensions, curves, pointFormats []uint16) string {
+	builder := &strings.Builder{}
+	builder.WriteString(strconv.Itoa(int(version)))
+	for _, values := range [][]uint16{ciphers, extensions, curves, pointFormats} {
+		builder.WriteString(",")
+		var written bool
+		for _, value := range values {
+			if isGREASE(value) {
+				continue
+			}
+			if written {
+				builder.WriteString("-")
+			}
+			builder.WriteString(strconv.Itoa(int(value)))
+			written = true
+		}
+	}
+	return builder.String()
+}
+
+// isGREASE returns true if value is a grease value (RFC 8701)
+func isGREASE(value uint16) bool {
+	return value&0x0f0f == 0x0a0a && value>>8 == value&0xff
+}
@@ -0,0 +1,71 @@
+package ja3
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const chromeJA3 = "771,4865-4866-4867-49195-49199-49196-49200-52393-52392-49171-49172-156-157-47-53,0-23-65281-10-11-35-16-5-13-18-51-45-43-27-17513-21,29-23-24,0"
+
+func TestParse(t *testing.T) {
+	spec, err := Parse(chromeJA3)
+	require.Nil(t, err, "could not parse ja3")
+	require.Equal(t, uint16(tls.VersionTLS12), spec.Version, "could not get version")
+	require.Len(t, spec.CipherSuites, 15, "could not get ciphers")
+	require.Equal(t, []uint16{29, 23, 24}, spec.Curves, "could not get curves")
+	require.Equal(t, []uint8{0}, spec.PointFormats, "could not get point formats")
+	require.Equal(t, chromeJA3, spec.String(), "could not get ja3 string")
+
+	for _, value := range []string{"", "771,,,", "771,4865,0,29", "999,4865,0,29,0", "771,,0,29,0", "771,4865,0,29,256", "771,a-b,0,29,0"} {
+		_, err := Parse(value)
+		require.NotNil(t, err, "could parse invalid ja3 %q", value)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	for _, value := range []string{
+		chromeJA3,
+		"771,49195-49199-52393-52392-49196-49200-49161-49171-49162-49172-156-157-47-53,0-23-65281-10-11-35-16-5-13,29-23-24,0",
+		"769,47-53-5-10-49161-49162-49171-49172-50-56-19-4,0-10-11,23-24-25,0",
+	} {
+		spec, err := Parse(value)
+		require.Nil(t, err, "could not parse ja3")
+
+		ja3, err := Fingerprint(spec)
+		require.Nil(t, err, "could not get fingerprint")
+		require.Equal(t, value, ja3, "could not spoof ja3")
+	}
+	require.Equal(t, "cd08e31494f9531f560d64c695473da9", Hash(chromeJA3), "could not get ja3 hash")
+}
+
+func TestClient(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	spec, err := Parse(chromeJA3)
+	require.Nil(t, err, "could not parse ja3")
+
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	require.Nil(t, err, "could not connect to server")
+	defer conn.Close()
+
+	client, err := Client(conn, spec, &tls.Config{ServerName: "example.com", InsecureSkipVerify: true}, nil)
+	require.Nil(t, err, "could not create ja3 client")
+	require.Nil(t, client.Handshake(), "could not perform handshake")
+	require.Equal(t, chromeJA3, client.JA3(), "could not get sent ja3")
+
+	_, err = io.WriteString(client, "GET / HTTP/1.1\r\nHost: example.com\r\nConnection: close\r\n\r\n")
+	require.Nil(t, err, "could not write request")
+	response, err := io.ReadAll(client)
+	require.Nil(t, err, "could not read response")
+	require.Contains(t, string(response), "ok", "could not get response")
+}
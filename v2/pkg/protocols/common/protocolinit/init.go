@@ -14,7 +14,11 @@ import (
 
 // Init initializes the client pools for the protocols
 func Init(options *types.Options) error {
-	uarand.Default = uarand.NewWithCustomList(userAgents)
+	agents := userAgents
+	if len(options.UserAgents) > 0 {
+		agents = options.UserAgents
+	}
+	uarand.Default = uarand.NewWithCustomList(agents)
 
 	if err := protocolstate.Init(options); err != nil {
 		return err
@@ -11,6 +11,7 @@ import (
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/networkpolicy"
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/nuclei/v2/pkg/utils"
 )
 
 // Dialer is a shared fastdialer instance for host DNS resolution
@@ -21,52 +22,73 @@ func Init(options *types.Options) error {
 	if Dialer != nil {
 		return nil
 	}
+	opts, err := dialerOptions(options)
+	if err != nil {
+		return err
+	}
+	dialer, err := fastdialer.NewDialer(opts)
+	if err != nil {
+		return errors.Wrap(err, "could not create dialer")
+	}
+	Dialer = dialer
+	return nil
+}
+
+// dialerOptions returns the options of the Dialer based on user configuration
+func dialerOptions(options *types.Options) (fastdialer.Options, error) {
 	opts := fastdialer.DefaultOptions
+	opts.DialerTimeout = options.GetDialTimeout()
 
 	switch {
 	case options.SourceIP != "" && options.Interface != "":
 		isAssociated, err := isIpAssociatedWithInterface(options.SourceIP, options.Interface)
 		if err != nil {
-			return err
+			return opts, err
 		}
 		if isAssociated {
 			opts.Dialer = &net.Dialer{
+				Timeout: opts.DialerTimeout,
 				LocalAddr: &net.TCPAddr{
 					IP: net.ParseIP(options.SourceIP),
 				},
 			}
 		} else {
-			return fmt.Errorf("source ip (%s) is not associated with the interface (%s)", options.SourceIP, options.Interface)
+			return opts, fmt.Errorf("source ip (%s) is not associated with the interface (%s)", options.SourceIP, options.Interface)
 		}
 	case options.SourceIP != "":
 		isAssociated, err := isIpAssociatedWithInterface(options.SourceIP, "any")
 		if err != nil {
-			return err
+			return opts, err
 		}
 		if isAssociated {
 			opts.Dialer = &net.Dialer{
+				Timeout: opts.DialerTimeout,
 				LocalAddr: &net.TCPAddr{
 					IP: net.ParseIP(options.SourceIP),
 				},
 			}
 		} else {
-			return fmt.Errorf("source ip (%s) is not associated with any network interface", options.SourceIP)
+			return opts, fmt.Errorf("source ip (%s) is not associated with any network interface", options.SourceIP)
 		}
 	case options.Interface != "":
 		ifadrr, err := interfaceAddress(options.Interface)
 		if err != nil {
-			return err
+			return opts, err
 		}
 		opts.Dialer = &net.Dialer{
+			Timeout: opts.DialerTimeout,
 			LocalAddr: &net.TCPAddr{
 				IP: ifadrr,
 			},
 		}
 	}
-	if types.ProxySocksURL != "" {
+	if types.ProxyPool != nil && types.ProxyPool.HasSocks() {
+		var dialer proxy.Dialer = types.ProxyPool
+		opts.ProxyDialer = &dialer
+	} else if types.ProxySocksURL != "" {
 		proxyURL, err := url.Parse(types.ProxySocksURL)
 		if err != nil {
-			return err
+			return opts, err
 		}
 		var forward *net.Dialer
 		if opts.Dialer != nil {
@@ -80,7 +102,7 @@ func Init(options *types.Options) error {
 		}
 		dialer, err := proxy.FromURL(proxyURL, forward)
 		if err != nil {
-			return err
+			return opts, err
 		}
 		opts.ProxyDialer = &dialer
 	}
@@ -97,12 +119,7 @@ func Init(options *types.Options) error {
 	opts.WithDialerHistory = true
 	opts.WithZTLS = options.ZTLS
 	opts.SNIName = options.SNI
-	dialer, err := fastdialer.NewDialer(opts)
-	if err != nil {
-		return errors.Wrap(err, "could not create dialer")
-	}
-	Dialer = dialer
-	return nil
+	return opts, nil
 }
 
 // isIpAssociatedWithInterface checks if the given IP is associated with the given interface.
@@ -157,6 +174,13 @@ func interfaceAddresses(interfaceName string) ([]net.Addr, error) {
 	return addrs, nil
 }
 
+// GetDialedIP returns the ip dialed by a dialer for the host of an address,
+// with or without port. The dialer records ipv6 hosts with their brackets.
+func GetDialedIP(dialer *fastdialer.Dialer, address string) string {
+	host, _ := utils.SplitHostPort(address)
+	return dialer.GetDialedIP(utils.BracketIPv6(host))
+}
+
 // Close closes the global shared fastdialer
 func Close() {
 	if Dialer != nil {
@@ -0,0 +1,27 @@
+package protocolstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+func TestDialerOptionsTimeout(t *testing.T) {
+	opts, err := dialerOptions(&types.Options{Timeout: 10, DialTimeout: 250 * time.Millisecond})
+	require.Nil(t, err, "could not get dialer options")
+	require.Equal(t, 250*time.Millisecond, opts.DialerTimeout, "wrong dialer timeout")
+	require.Nil(t, opts.Dialer, "custom dialer without source ip")
+
+	opts, err = dialerOptions(&types.Options{Timeout: 3})
+	require.Nil(t, err, "could not get dialer options")
+	require.Equal(t, 3*time.Second, opts.DialerTimeout, "dialer timeout not defaulting to the timeout")
+
+	opts, err = dialerOptions(&types.Options{Timeout: 10, DialTimeout: 250 * time.Millisecond, SourceIP: "127.0.0.1"})
+	require.Nil(t, err, "could not get dialer options")
+	require.NotNil(t, opts.Dialer, "could not get source ip dialer")
+	require.Equal(t, 250*time.Millisecond, opts.Dialer.Timeout, "wrong source ip dialer timeout")
+	require.Equal(t, "127.0.0.1:0", opts.Dialer.LocalAddr.String(), "wrong source ip")
+}
@@ -0,0 +1,63 @@
+package protocolstate
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/fastdialer/fastdialer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	iputil "github.com/projectdiscovery/utils/ip"
+)
+
+// DialContextFunc is a function dialing a network connection
+type DialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// HasSeparateTLSHandshake returns true if tls connections must be dialed with
+// DialTLS for the tls handshake to have its own timeout. The dialer performs the
+// handshake of the connections it dials within the dial timeout otherwise.
+func HasSeparateTLSHandshake(options *types.Options) bool {
+	return !options.ZTLS && (options.DialTimeout > 0 || options.TLSHandshakeTimeout > 0)
+}
+
+// DialTLS dials a connection to address with dial and performs the tls
+// handshake within the tls handshake timeout of the options.
+//
+// The server name defaults to the one of the context set by the dialer
+// annotations or to the host of the address if it's not an ip.
+func DialTLS(ctx context.Context, dial DialContextFunc, network, address string, config *tls.Config, options *types.Options) (net.Conn, error) {
+	conn, err := dial(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10}
+	} else {
+		config = config.Clone()
+	}
+	if config.ServerName == "" {
+		if serverName, ok := ctx.Value(fastdialer.SniName).(string); ok && serverName != "" {
+			config.ServerName = serverName
+		} else if host, _, splitErr := net.SplitHostPort(address); splitErr == nil && !iputil.IsIP(host) {
+			config.ServerName = host
+		}
+	}
+
+	handshakeCtx, cancel := context.WithTimeout(ctx, options.GetTLSHandshakeTimeout())
+	defer cancel()
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(handshakeCtx); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "could not perform tls handshake")
+	}
+	return tlsConn, nil
+}
+
+// TLSDialer returns a function dialing tls connections with DialTLS
+func TLSDialer(dial DialContextFunc, config *tls.Config, options *types.Options) DialContextFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		return DialTLS(ctx, dial, network, address, config, options)
+	}
+}
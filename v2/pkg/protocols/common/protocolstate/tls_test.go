@@ -0,0 +1,58 @@
+package protocolstate
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+func TestDialTLS(t *testing.T) {
+	var dialer net.Dialer
+	options := &types.Options{Timeout: 10, TLSHandshakeTimeout: 200 * time.Millisecond}
+
+	// the listener accepts connections without ever completing handshakes
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	defer listener.Close()
+	go func() {
+		var conns []net.Conn
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				break
+			}
+			conns = append(conns, conn)
+		}
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	start := time.Now()
+	_, err = DialTLS(context.Background(), dialer.DialContext, "tcp", listener.Addr().String(), nil, options)
+	require.NotNil(t, err, "could complete handshake with silent server")
+	require.Less(t, time.Since(start), 5*time.Second, "handshake not bounded by tls handshake timeout")
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	conn, err := DialTLS(context.Background(), dialer.DialContext, "tcp", server.Listener.Addr().String(), nil, options)
+	require.Nil(t, err, "could not dial tls server")
+	conn.Close()
+}
+
+func TestHasSeparateTLSHandshake(t *testing.T) {
+	require.False(t, HasSeparateTLSHandshake(&types.Options{Timeout: 10}), "separate handshake without timeouts")
+	require.True(t, HasSeparateTLSHandshake(&types.Options{Timeout: 10, DialTimeout: time.Second}), "no separate handshake with dial timeout")
+	require.False(t, HasSeparateTLSHandshake(&types.Options{Timeout: 10, TLSHandshakeTimeout: time.Second, ZTLS: true}), "separate handshake with ztls")
+
+	options := &types.Options{Timeout: 10, DialTimeout: 2 * time.Second}
+	require.Equal(t, 10*time.Second, options.GetTLSHandshakeTimeout(), "wrong default tls handshake timeout")
+	require.Equal(t, 12*time.Second, options.GetConnectTimeout(), "wrong connect timeout")
+}
@@ -0,0 +1,281 @@
+package proxypool
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/proxy"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// DefaultCheckInterval is the interval between two health checks of the proxies
+const DefaultCheckInterval = 30 * time.Second
+
+// ErrNoHealthyProxy is returned when every proxy of the pool failed its last health check
+var ErrNoHealthyProxy = errors.New("no healthy proxy available in the pool")
+
+// defaultPorts are the ports used for proxy urls without an explicit port
+var defaultPorts = map[string]string{
+	"http":    "80",
+	"https":   "443",
+	"socks5":  "1080",
+	"socks5h": "1080",
+}
+
+// Pool distributes requests across a list of http and socks5 proxies.
+//
+// Proxies are picked in a round-robin fashion among the ones passing the
+// periodic health checks. A proxy failing a check is removed from the
+// rotation until a later check succeeds again.
+type Pool struct {
+	proxies []*Proxy
+	counter atomic.Uint64
+	timeout time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Proxy is a proxy server of the pool
+type Proxy struct {
+	// URL is the url of the proxy server
+	URL *url.URL
+
+	healthy atomic.Bool
+	// dialer dials connections through socks5 proxies, nil for http proxies
+	dialer proxy.ContextDialer
+}
+
+// New returns a pool of the given proxy urls, dropping the unreachable ones
+// from the rotation. An error is returned if none of the proxies is reachable.
+func New(proxyURLs []string, timeout time.Duration) (*Pool, error) {
+	if len(proxyURLs) == 0 {
+		return nil, errors.New("no proxy provided for the pool")
+	}
+	pool := &Pool{timeout: timeout, stop: make(chan struct{})}
+	for _, value := range proxyURLs {
+		parsed, err := parseProxyURL(value)
+		if err != nil {
+			return nil, err
+		}
+		item := &Proxy{URL: parsed}
+		if strings.HasPrefix(parsed.Scheme, "socks5") {
+			dialer, err := proxy.FromURL(parsed, &net.Dialer{Timeout: timeout})
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not create dialer for proxy %s", item)
+			}
+			contextDialer, ok := dialer.(proxy.ContextDialer)
+			if !ok {
+				return nil, fmt.Errorf("proxy %s does not support dialing with a context", item)
+			}
+			item.dialer = contextDialer
+		}
+		item.healthy.Store(true)
+		pool.proxies = append(pool.proxies, item)
+	}
+	if pool.Check() == 0 {
+		return nil, errors.New("none of the proxies of the pool is reachable")
+	}
+	return pool, nil
+}
+
+// parseProxyURL parses a proxy url, adding the default port of its scheme if missing
+func parseProxyURL(value string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse proxy %s", value)
+	}
+	port, ok := defaultPorts[parsed.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported scheme for proxy %s", parsed.Redacted())
+	}
+	if parsed.Hostname() == "" {
+		return nil, fmt.Errorf("no host for proxy %s", parsed.Redacted())
+	}
+	if parsed.Port() == "" {
+		parsed.Host = net.JoinHostPort(parsed.Hostname(), port)
+	}
+	return parsed, nil
+}
+
+// String returns the url of the proxy without its password
+func (p *Proxy) String() string {
+	return p.URL.Redacted()
+}
+
+// Healthy returns true if the proxy passed its last health check
+func (p *Proxy) Healthy() bool {
+	return p.healthy.Load()
+}
+
+// Start runs the health checks of the proxies at every interval until the pool is closed
+func (p *Pool) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				if p.Check() == 0 {
+					gologger.Warning().Msgf("None of the %d proxies of the pool is reachable\n", len(p.proxies))
+				}
+			}
+		}
+	}()
+}
+
+// Close stops the health checks of the pool
+func (p *Pool) Close() {
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+	p.wg.Wait()
+}
+
+// Check checks concurrently whether the proxies are reachable, updating the
+// rotation accordingly, and returns the number of healthy proxies.
+func (p *Pool) Check() int {
+	var healthy atomic.Int64
+	wg := &sync.WaitGroup{}
+	for _, item := range p.proxies {
+		wg.Add(1)
+		go func(item *Proxy) {
+			defer wg.Done()
+
+			err := p.check(item)
+			if err == nil {
+				healthy.Add(1)
+			}
+			wasHealthy := item.healthy.Swap(err == nil)
+			switch {
+			case wasHealthy && err != nil:
+				gologger.Warning().Msgf("Removing unreachable proxy %s from the pool: %s\n", item, err)
+			case !wasHealthy && err == nil:
+				gologger.Info().Msgf("Proxy %s is reachable again, adding it back to the pool\n", item)
+			}
+		}(item)
+	}
+	wg.Wait()
+	return int(healthy.Load())
+}
+
+// check connects to a proxy, performing the socks5 greeting for socks5 proxies
+func (p *Pool) check(item *Proxy) error {
+	conn, err := net.DialTimeout("tcp", item.URL.Host, p.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if item.dialer == nil {
+		return nil
+	}
+	_ = conn.SetDeadline(time.Now().Add(p.timeout))
+	// version 5 offering no authentication, plus username/password if the proxy has credentials
+	greeting := []byte{0x05, 0x01, 0x00}
+	if item.URL.User != nil {
+		greeting = []byte{0x05, 0x02, 0x00, 0x02}
+	}
+	if _, err := conn.Write(greeting); err != nil {
+		return err
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[0] != 0x05 {
+		return fmt.Errorf("unexpected socks version %d", reply[0])
+	}
+	if reply[1] == 0xff {
+		return errors.New("no acceptable socks authentication method")
+	}
+	return nil
+}
+
+// Len returns the number of proxies of the pool
+func (p *Pool) Len() int {
+	return len(p.proxies)
+}
+
+// HasSocks returns true if the pool contains socks5 proxies
+func (p *Pool) HasSocks() bool {
+	for _, item := range p.proxies {
+		if item.dialer != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the next healthy proxy of the pool
+func (p *Pool) Next() (*Proxy, error) {
+	return p.next(false)
+}
+
+// next returns the next healthy proxy of the pool, restricted to socks5 proxies if socks is true
+func (p *Pool) next(socks bool) (*Proxy, error) {
+	start := p.counter.Add(1)
+	for i := range p.proxies {
+		item := p.proxies[(start+uint64(i))%uint64(len(p.proxies))]
+		if item.Healthy() && (!socks || item.dialer != nil) {
+			return item, nil
+		}
+	}
+	return nil, ErrNoHealthyProxy
+}
+
+// ProxyURL returns the proxy for a request, to be used as the proxy function
+// of http transports. The proxy is recorded in the selection of the request context.
+func (p *Pool) ProxyURL(req *http.Request) (*url.URL, error) {
+	item, err := p.Next()
+	if err != nil {
+		return nil, err
+	}
+	recordSelection(req.Context(), item)
+	return item.URL, nil
+}
+
+// SetTransport configures an http transport to send requests through the proxies of the pool.
+// Proxies are dialed directly as the transport dialer may itself use the socks5 proxies of the pool.
+func (p *Pool) SetTransport(transport *http.Transport) {
+	transport.Proxy = p.ProxyURL
+	transport.DialContext = (&net.Dialer{Timeout: p.timeout}).DialContext
+	transport.DialTLSContext = nil
+}
+
+// Dial connects to the address through the next healthy socks5 proxy
+func (p *Pool) Dial(network, address string) (net.Conn, error) {
+	return p.DialContext(context.Background(), network, address)
+}
+
+// DialContext connects to the address through the next healthy socks5 proxy.
+// The proxy is recorded in the selection of the context.
+func (p *Pool) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	item, err := p.next(true)
+	if err != nil {
+		return nil, err
+	}
+	recordSelection(ctx, item)
+	gologger.Debug().Msgf("Connecting to %s through proxy %s", address, item)
+	return item.dialer.DialContext(ctx, network, address)
+}
@@ -0,0 +1,52 @@
+package proxypool
+
+import (
+	"context"
+	"sync"
+)
+
+type selectionKey struct{}
+
+// Selection records the proxy selected for the requests made with a context
+type Selection struct {
+	mutex sync.Mutex
+	proxy *Proxy
+}
+
+// Track returns a context recording the proxy selected by the pool in the returned selection
+func Track(ctx context.Context) (context.Context, *Selection) {
+	selection := &Selection{}
+	return context.WithValue(ctx, selectionKey{}, selection), selection
+}
+
+// Proxy returns the last proxy selected, nil if none was
+func (s *Selection) Proxy() *Proxy {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.proxy
+}
+
+// String returns the url of the last proxy selected, empty if none was
+func (s *Selection) String() string {
+	if proxy := s.Proxy(); proxy != nil {
+		return proxy.String()
+	}
+	return ""
+}
+
+// recordSelection records the proxy in the selection of the context if it is tracked
+func recordSelection(ctx context.Context, proxy *Proxy) {
+	if ctx == nil {
+		return
+	}
+	selection, ok := ctx.Value(selectionKey{}).(*Selection)
+	if !ok {
+		return
+	}
+	selection.mutex.Lock()
+	selection.proxy = proxy
+	selection.mutex.Unlock()
+}
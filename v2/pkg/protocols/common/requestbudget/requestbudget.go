@@ -0,0 +1,129 @@
+package requestbudget
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// Budget limits the number of requests a single template can
+// send to a single host.
+//
+// Once the budget for a host is exhausted every further request
+// is rejected and a warning is emitted once for that host.
+type Budget struct {
+	templateID  string
+	maxRequests int64
+	hosts       sync.Map
+}
+
+type budgetItem struct {
+	requests atomic.Int64
+	sync.Once
+}
+
+// New returns a new request budget for a template. It returns nil
+// if maxRequests is not a positive number, which disables the budget.
+func New(templateID string, maxRequests int) *Budget {
+	if maxRequests <= 0 {
+		return nil
+	}
+	return &Budget{templateID: templateID, maxRequests: int64(maxRequests)}
+}
+
+// Take consumes a request from the budget of a host and returns false
+// if the budget has already been exhausted. A nil budget never exhausts.
+func (b *Budget) Take(host string) bool {
+	if b == nil {
+		return true
+	}
+	value, _ := b.hosts.LoadOrStore(host, &budgetItem{})
+	item := value.(*budgetItem)
+
+	if item.requests.Add(1) > b.maxRequests {
+		item.Do(func() {
+			gologger.Warning().Msgf("[%s] Skipping further requests for %s as max requests budget of %d was reached", b.templateID, host, b.maxRequests)
+		})
+		return false
+	}
+	return true
+}
+
+// skipped returns the number of requests rejected by the budget for each host
+func (b *Budget) skipped() map[string]int64 {
+	skipped := make(map[string]int64)
+	b.hosts.Range(func(key, value interface{}) bool {
+		if requests := value.(*budgetItem).requests.Load(); requests > b.maxRequests {
+			skipped[key.(string)] = requests - b.maxRequests
+		}
+		return true
+	})
+	return skipped
+}
+
+// Tracker keeps track of the budgets of the templates of a scan
+// to report the requests they skipped.
+type Tracker struct {
+	mutex   sync.Mutex
+	budgets []*Budget
+}
+
+// Stat is the number of requests a template skipped for a host
+type Stat struct {
+	TemplateID  string
+	Host        string
+	MaxRequests int64
+	Skipped     int64
+}
+
+// NewTracker returns a new tracker of request budgets
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// New returns a new request budget for a template tracked by the tracker.
+// A nil tracker returns an untracked budget.
+func (t *Tracker) New(templateID string, maxRequests int) *Budget {
+	budget := New(templateID, maxRequests)
+	if t == nil || budget == nil {
+		return budget
+	}
+	t.mutex.Lock()
+	t.budgets = append(t.budgets, budget)
+	t.mutex.Unlock()
+	return budget
+}
+
+// Stats returns the requests skipped by the tracked budgets for each
+// template and host, sorted by template and host.
+func (t *Tracker) Stats() []Stat {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// templates compiled more than once, such as in workflows, share their stats
+	indexes := make(map[[2]string]int)
+	var stats []Stat
+	for _, budget := range t.budgets {
+		for host, skipped := range budget.skipped() {
+			key := [2]string{budget.templateID, host}
+			if index, ok := indexes[key]; ok {
+				stats[index].Skipped += skipped
+				continue
+			}
+			indexes[key] = len(stats)
+			stats = append(stats, Stat{TemplateID: budget.templateID, Host: host, MaxRequests: budget.maxRequests, Skipped: skipped})
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TemplateID != stats[j].TemplateID {
+			return stats[i].TemplateID < stats[j].TemplateID
+		}
+		return stats[i].Host < stats[j].Host
+	})
+	return stats
+}
@@ -0,0 +1,86 @@
+package requestbudget
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgetTake(t *testing.T) {
+	budget := New("test-template", 3)
+
+	for i := 0; i < 10; i++ {
+		got := budget.Take("example.com")
+		if i < 3 {
+			require.True(t, got, "request within budget was rejected")
+		} else {
+			require.False(t, got, "request over budget was allowed")
+		}
+	}
+	require.True(t, budget.Take("another.com"), "budget should be tracked per host")
+}
+
+func TestBudgetDisabled(t *testing.T) {
+	budget := New("test-template", 0)
+	require.Nil(t, budget, "budget should be disabled")
+
+	for i := 0; i < 10; i++ {
+		require.True(t, budget.Take("example.com"), "disabled budget rejected request")
+	}
+}
+
+func TestBudgetConcurrentTake(t *testing.T) {
+	budget := New("test-template", 50)
+
+	var allowed atomic.Int32
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if budget.Take("example.com") {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(50), allowed.Load(), "wrong number of allowed requests")
+}
+
+func TestTrackerStats(t *testing.T) {
+	tracker := NewTracker()
+	first := tracker.New("first-template", 2)
+	second := tracker.New("second-template", 1)
+	require.Nil(t, tracker.New("unlimited-template", 0), "budget should be disabled")
+
+	for i := 0; i < 5; i++ {
+		first.Take("b.com")
+		second.Take("a.com")
+	}
+	first.Take("a.com")
+	first.Take("a.com")
+	first.Take("a.com")
+
+	// a template compiled again shares the stats of the first compilation
+	recompiled := tracker.New("first-template", 2)
+	for i := 0; i < 4; i++ {
+		recompiled.Take("a.com")
+	}
+
+	require.Equal(t, []Stat{
+		{TemplateID: "first-template", Host: "a.com", MaxRequests: 2, Skipped: 3},
+		{TemplateID: "first-template", Host: "b.com", MaxRequests: 2, Skipped: 3},
+		{TemplateID: "second-template", Host: "a.com", MaxRequests: 1, Skipped: 4},
+	}, tracker.Stats(), "wrong budget stats")
+}
+
+func TestTrackerDisabled(t *testing.T) {
+	var tracker *Tracker
+	budget := tracker.New("test-template", 1)
+	require.NotNil(t, budget, "untracked budget should be created")
+	budget.Take("example.com")
+	budget.Take("example.com")
+	require.Empty(t, tracker.Stats(), "nil tracker returned stats")
+}
@@ -22,3 +22,9 @@ package variables
 // 1. VariablesMap
 // 2. PayloadsMap
 // Everytime Linear Sources are updated , Non-Linear Sources need to be re-evaluated
+
+// Variables are evaluated in dependency order (see Variable.evaluationOrder),
+// each one after the variables it references, and variables referencing each
+// other in a cycle are rejected when the template is compiled.
+// Variables only referencing constants are evaluated once when the template is
+// loaded (eager), the others every time the request is built (lazy).
@@ -2,6 +2,9 @@ package variables
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
 	"strings"
 
 	"github.com/alecthomas/jsonschema"
@@ -19,6 +22,9 @@ import (
 type Variable struct {
 	LazyEval                        bool `yaml:"-" json:"-"` // LazyEval is used to evaluate variables lazily if it using any expression or global variables
 	utils.InsertionOrderedStringMap `yaml:"-" json:"-"`
+
+	// order is the evaluation order of the variables computed when they are compiled
+	order []string
 }
 
 func (variables *Variable) JSONSchemaType() *jsonschema.Type {
@@ -39,12 +45,7 @@ func (variables *Variable) UnmarshalYAML(unmarshal func(interface{}) error) erro
 	if variables.LazyEval || variables.checkForLazyEval() {
 		return nil
 	}
-
-	evaluated := variables.Evaluate(map[string]interface{}{})
-
-	for k, v := range evaluated {
-		variables.Set(k, v)
-	}
+	variables.evaluateConstants()
 	return nil
 }
 
@@ -53,18 +54,56 @@ func (variables *Variable) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &variables.InsertionOrderedStringMap); err != nil {
 		return err
 	}
-	evaluated := variables.Evaluate(map[string]interface{}{})
+	variables.evaluateConstants()
+	return nil
+}
 
-	for k, v := range evaluated {
-		variables.Set(k, v)
+// evaluateConstants evaluates once the variables which only reference constant
+// values or other constant variables, eg. {{md5("secret")}}. The variables
+// referencing values only known at runtime (payloads, extracted values, etc)
+// are left as is and evaluated for every request.
+func (variables *Variable) evaluateConstants() {
+	order, err := variables.evaluationOrder()
+	if err != nil {
+		// the dependency cycle is reported when the template is compiled
+		return
+	}
+	variables.order = order
+	_, dependencies := variables.dependencies()
+	values := make(map[string]interface{}, len(order))
+	variables.ForEach(func(key string, value interface{}) {
+		values[key] = value
+	})
+
+	constants := make(map[string]interface{}, len(order))
+	for _, key := range order {
+		resolved := true
+		for _, dependency := range dependencies[key] {
+			if _, ok := constants[dependency]; !ok {
+				resolved = false
+				break
+			}
+		}
+		if !resolved {
+			continue
+		}
+		evaluated := evaluateVariableValue(types.ToString(values[key]), constants, nil)
+		if expressions.ContainsUnresolvedVariables(evaluated) != nil {
+			continue
+		}
+		constants[key] = evaluated
+		variables.Set(key, evaluated)
 	}
-	return nil
 }
 
-// Evaluate returns a finished map of variables based on set values
+// Evaluate returns a finished map of variables based on set values.
+//
+// Variables are evaluated after the variables they reference, so a variable can
+// reference the variables declared after it. A value having the name of a variable
+// takes precedence over the value of the variable.
 func (variables *Variable) Evaluate(values map[string]interface{}) map[string]interface{} {
 	result := make(map[string]interface{}, variables.Len())
-	variables.ForEach(func(key string, value interface{}) {
+	variables.forEachOrdered(func(key string, value interface{}) {
 		valueString := types.ToString(value)
 		combined := generators.MergeMaps(values, result)
 		if value, ok := combined[key]; ok {
@@ -80,7 +119,7 @@ func (variables *Variable) EvaluateWithInteractsh(values map[string]interface{},
 	result := make(map[string]interface{}, variables.Len())
 
 	var interactURLs []string
-	variables.ForEach(func(key string, value interface{}) {
+	variables.forEachOrdered(func(key string, value interface{}) {
 		valueString := types.ToString(value)
 		if strings.Contains(valueString, "interactsh-url") {
 			valueString, interactURLs = interact.Replace(valueString, interactURLs)
@@ -94,6 +133,176 @@ func (variables *Variable) EvaluateWithInteractsh(values map[string]interface{},
 	return result, interactURLs
 }
 
+// expressionRegex matches the expressions in variable values
+var expressionRegex = regexp.MustCompile(`{{(.+?)}}`)
+
+// identifierRegex matches the identifiers of an expression
+var identifierRegex = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// quotedRegex matches the string literals of an expression
+var quotedRegex = regexp.MustCompile(`'[^']*'|"[^"]*"`)
+
+// dependencies returns the variables referenced by the value of each variable
+func (variables *Variable) dependencies() ([]string, map[string][]string) {
+	var keys []string
+	variables.ForEach(func(key string, _ interface{}) {
+		keys = append(keys, key)
+	})
+	isVariable := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		isVariable[key] = struct{}{}
+	}
+
+	dependencies := make(map[string][]string, len(keys))
+	variables.ForEach(func(key string, value interface{}) {
+		seen := make(map[string]struct{})
+		for _, expression := range expressionRegex.FindAllStringSubmatch(types.ToString(value), -1) {
+			literalsRemoved := quotedRegex.ReplaceAllString(expression[1], "")
+			for _, identifier := range identifierRegex.FindAllString(literalsRemoved, -1) {
+				if _, ok := isVariable[identifier]; !ok || identifier == key {
+					continue
+				}
+				if _, ok := seen[identifier]; !ok {
+					seen[identifier] = struct{}{}
+					dependencies[key] = append(dependencies[key], identifier)
+				}
+			}
+		}
+	})
+	return keys, dependencies
+}
+
+// evaluationOrder returns the variables in the order they must be evaluated, each
+// one after the variables it references and in declaration order otherwise.
+// An error is returned if variables reference each other in a cycle.
+func (variables *Variable) evaluationOrder() ([]string, error) {
+	keys, dependencies := variables.dependencies()
+	order := make([]string, 0, len(keys))
+	ordered := make(map[string]struct{}, len(keys))
+	for len(order) < len(keys) {
+		progressed := false
+		for _, key := range keys {
+			if _, ok := ordered[key]; ok {
+				continue
+			}
+			ready := true
+			for _, dependency := range dependencies[key] {
+				if _, ok := ordered[dependency]; !ok {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				order = append(order, key)
+				ordered[key] = struct{}{}
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			return nil, fmt.Errorf("variables form a dependency cycle: %s", strings.Join(findCycle(keys, ordered, dependencies), " -> "))
+		}
+	}
+	return order, nil
+}
+
+// findCycle returns a dependency cycle among the variables not ordered yet
+func findCycle(keys []string, ordered map[string]struct{}, dependencies map[string][]string) []string {
+	var start string
+	for _, key := range keys {
+		if _, ok := ordered[key]; !ok {
+			start = key
+			break
+		}
+	}
+	// every unordered variable depends on an unordered one, so following
+	// the dependencies from any of them eventually visits one again
+	var path []string
+	visited := make(map[string]int)
+	for current := start; ; {
+		if index, ok := visited[current]; ok {
+			return append(path[index:], current)
+		}
+		visited[current] = len(path)
+		path = append(path, current)
+		for _, dependency := range dependencies[current] {
+			if _, ok := ordered[dependency]; !ok {
+				current = dependency
+				break
+			}
+		}
+	}
+}
+
+// CheckCycles returns an error if the variables reference each other in a cycle,
+// otherwise it stores the evaluation order of the variables for their evaluation.
+func (variables *Variable) CheckCycles() error {
+	order, err := variables.evaluationOrder()
+	if err != nil {
+		return err
+	}
+	variables.order = order
+	return nil
+}
+
+// forEachOrdered calls fn for the variables in evaluation order, falling
+// back to declaration order if variables reference each other in a cycle.
+//
+// The order stored when the variables were compiled is used, it is only
+// computed here for variables never compiled or with variables added since.
+func (variables *Variable) forEachOrdered(fn func(key string, value interface{})) {
+	order := variables.order
+	if len(order) != variables.Len() {
+		var err error
+		if order, err = variables.evaluationOrder(); err != nil {
+			variables.ForEach(fn)
+			return
+		}
+	}
+	values := make(map[string]interface{}, len(order))
+	variables.ForEach(func(key string, value interface{}) {
+		values[key] = value
+	})
+	for _, key := range order {
+		fn(key, values[key])
+	}
+}
+
+// envReferenceRegex matches the references to environment variables in variable values,
+// either as {{env "NAME"}} or {{env("NAME")}}
+var envReferenceRegex = regexp.MustCompile(`{{\s*env\s*(?:\(\s*["']([^"']+)["']\s*\)|["']([^"']+)["'])\s*}}`)
+
+// ExpandEnv replaces the references to environment variables in the values of the
+// variables by the value of the environment variables. An error is returned if
+// the environment variables are referenced while not allowed or are not set.
+func (variables *Variable) ExpandEnv(allowed bool) error {
+	var expandErr error
+	variables.ForEach(func(key string, value interface{}) {
+		valueString, ok := value.(string)
+		if !ok || expandErr != nil || !envReferenceRegex.MatchString(valueString) {
+			return
+		}
+		expanded := envReferenceRegex.ReplaceAllStringFunc(valueString, func(reference string) string {
+			match := envReferenceRegex.FindStringSubmatch(reference)
+			name := match[1] + match[2]
+			if !allowed {
+				expandErr = fmt.Errorf("variable %s references environment variable %s but environment variables are not enabled", key, name)
+				return reference
+			}
+			envValue, ok := os.LookupEnv(name)
+			if !ok {
+				expandErr = fmt.Errorf("environment variable %s referenced by variable %s is not set", name, key)
+				return reference
+			}
+			return envValue
+		})
+		if expandErr == nil {
+			variables.Set(key, expanded)
+		}
+	})
+	return expandErr
+}
+
 // evaluateVariableValue expression and returns final value
 func evaluateVariableValue(expression string, values, processing map[string]interface{}) string {
 	finalMap := generators.MergeMaps(values, processing)
@@ -41,3 +41,91 @@ a6: "123456"`
 	require.Equal(t, map[string]interface{}{"a2": "098f6bcd4621d373cade4e832627b4f6", "a3": "this_is_random_text", "a4": a4, "a5": "moc.elgoog", "a6": "123456"}, result, "could not get correct elements")
 
 }
+
+func TestVariablesExpandEnv(t *testing.T) {
+	t.Setenv("NUCLEI_TEST_TOKEN", "secret")
+	data := `token: '{{env "NUCLEI_TEST_TOKEN"}}'
+auth: "Bearer {{env('NUCLEI_TEST_TOKEN')}}"
+host: "{{Hostname}}"`
+
+	variables := Variable{}
+	err := yaml.Unmarshal([]byte(data), &variables)
+	require.NoError(t, err, "could not unmarshal variables")
+
+	err = variables.ExpandEnv(false)
+	require.Error(t, err, "environment variables expanded while not allowed")
+
+	require.NoError(t, variables.ExpandEnv(true), "could not expand environment variables")
+	result := variables.Evaluate(map[string]interface{}{"Hostname": "example.com"})
+	require.Equal(t, map[string]interface{}{"token": "secret", "auth": "Bearer secret", "host": "example.com"}, result, "could not get expanded variables")
+
+	variables = Variable{}
+	err = yaml.Unmarshal([]byte(`token: '{{env "NUCLEI_TEST_UNSET_TOKEN"}}'`), &variables)
+	require.NoError(t, err, "could not unmarshal variables")
+	err = variables.ExpandEnv(true)
+	require.EqualError(t, err, "environment variable NUCLEI_TEST_UNSET_TOKEN referenced by variable token is not set", "unset environment variable expanded")
+}
+
+func TestVariablesEvaluationOrder(t *testing.T) {
+	data := `signature: "{{md5(concat(token, secret))}}"
+secret: '{{to_upper("s3cret")}}'
+token: "{{to_lower(extracted)}}"
+url: "{{BaseURL}}/{{signature}}"`
+
+	variables := Variable{}
+	err := yaml.Unmarshal([]byte(data), &variables)
+	require.NoError(t, err, "could not unmarshal variables")
+	require.NoError(t, variables.CheckCycles(), "found cycle in acyclic variables")
+	require.Equal(t, []string{"secret", "token", "signature", "url"}, variables.order, "could not store evaluation order")
+
+	result := variables.Evaluate(map[string]interface{}{"extracted": "ABC", "BaseURL": "https://example.com"})
+	require.Equal(t, map[string]interface{}{
+		"signature": "3c6e124520879c5beeacd59bbd67d8ad",
+		"secret":    "S3CRET",
+		"token":     "abc",
+		"url":       "https://example.com/3c6e124520879c5beeacd59bbd67d8ad",
+	}, result, "could not evaluate variables referencing later variables")
+
+	result = variables.Evaluate(map[string]interface{}{"extracted": "ABC", "token": "xyz"})
+	require.Equal(t, "xyz", result["token"], "value did not take precedence over variable")
+}
+
+func TestVariablesEvaluateConstants(t *testing.T) {
+	data := `constant: '{{md5("nuclei")}}'
+derived: "{{to_upper(constant)}}"
+runtime: "{{md5(payload)}}"
+chained: "{{concat(derived, runtime)}}"`
+
+	variables := Variable{}
+	err := yaml.Unmarshal([]byte(data), &variables)
+	require.NoError(t, err, "could not unmarshal variables")
+
+	values := make(map[string]interface{})
+	variables.ForEach(func(key string, value interface{}) {
+		values[key] = value
+	})
+	require.Equal(t, map[string]interface{}{
+		"constant": "709b38b27304df6257a86a60df742c4c",
+		"derived":  "709B38B27304DF6257A86A60DF742C4C",
+		"runtime":  "{{md5(payload)}}",
+		"chained":  "{{concat(derived, runtime)}}",
+	}, values, "could not evaluate constant variables once")
+}
+
+func TestVariablesCheckCycles(t *testing.T) {
+	data := `a: "{{b}}-{{md5('c')}}"
+b: "{{to_lower(c)}}"
+c: "{{a}}"
+d: "{{Hostname}}"`
+
+	variables := Variable{}
+	err := yaml.Unmarshal([]byte(data), &variables)
+	require.NoError(t, err, "could not unmarshal variables")
+	require.EqualError(t, variables.CheckCycles(), "variables form a dependency cycle: a -> b -> c -> a", "could not detect cycle")
+
+	variables = Variable{}
+	err = yaml.Unmarshal([]byte(`a: "{{md5('b')}}"
+b: "{{a}}"`), &variables)
+	require.NoError(t, err, "could not unmarshal variables")
+	require.NoError(t, variables.CheckCycles(), "string literal referenced as variable")
+}
@@ -40,6 +40,14 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchRegex(types.ToString(item)))
 	case matchers.BinaryMatcher:
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(types.ToString(item)))
+	case matchers.JSONSchemaMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchJSONSchema(types.ToString(item)))
+	case matchers.DifferentialMatcher:
+		baseline, ok := request.getMatchPart(matcher.Baseline, data)
+		if !ok {
+			return false, []string{}
+		}
+		return matcher.ResultWithMatchedSnippet(matcher.MatchDifferential(types.ToString(item), types.ToString(baseline)))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
 	}
@@ -91,6 +91,11 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 		}
 	}
 
+	// Check if the request budget of the template for the host is exhausted
+	if !request.options.RequestBudget.Take(input.MetaInput.Input) {
+		request.options.Progress.IncrementSkippedRequestsBy(1)
+		return nil
+	}
 	request.options.RateLimiter.Take()
 
 	// Send the request to the target servers
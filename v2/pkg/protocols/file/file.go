@@ -84,6 +84,7 @@ var RequestPartDefinitions = map[string]string{
 	"path":              "Path is the path of file on local filesystem",
 	"type":              "Type is the type of request made",
 	"raw,body,all,data": "Raw contains the raw file contents",
+	"truncated":         "True if the file was larger than the maximum size processed",
 }
 
 // defaultDenylist contains common extensions to exclude
@@ -123,6 +124,9 @@ func (request *Request) Compile(options *protocols.ExecuterOptions) error {
 		request.maxSize = -1
 	default:
 		request.maxSize = defaultMaxReadSize
+		if options.MaxBodySize > 0 && int64(options.MaxBodySize) < request.maxSize {
+			request.maxSize = int64(options.MaxBodySize)
+		}
 	}
 
 	request.options = options
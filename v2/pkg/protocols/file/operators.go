@@ -28,6 +28,16 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchRegex(itemStr))
 	case matchers.BinaryMatcher:
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(itemStr))
+	case matchers.JSONSchemaMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchJSONSchema(itemStr))
+	case matchers.DifferentialMatcher:
+		baseline, ok := request.getMatchPart(matcher.Baseline, data)
+		if !ok {
+			return false, []string{}
+		}
+		return matcher.ResultWithMatchedSnippet(matcher.MatchDifferential(itemStr, baseline))
+	case matchers.StructureMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchStructure(itemStr))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
 	}
@@ -46,6 +56,8 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractRegex(itemStr)
 	case extractors.KValExtractor:
 		return extractor.ExtractKval(data)
+	case extractors.StructureExtractor:
+		return extractor.ExtractStructure(itemStr)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
 	}
@@ -186,16 +186,17 @@ func (request *Request) processFile(filePath, input string, previousInternalEven
 
 func (request *Request) processReader(reader io.Reader, filePath, input string, totalBytes int64, previousInternalEvent output.InternalEvent) (*output.InternalWrappedEvent, []FileMatch, error) {
 	fileReader := io.LimitReader(reader, request.maxSize)
-	fileMatches, opResult := request.findMatchesWithReader(fileReader, input, filePath, totalBytes, previousInternalEvent)
+	truncated := request.maxSize >= 0 && totalBytes > request.maxSize
+	fileMatches, opResult := request.findMatchesWithReader(fileReader, input, filePath, totalBytes, truncated, previousInternalEvent)
 	if opResult == nil && len(fileMatches) == 0 {
 		return nil, nil, errEmptyResult
 	}
 
 	// build event structure to interface with internal logic
-	return request.buildEvent(input, filePath, fileMatches, opResult, previousInternalEvent), fileMatches, nil
+	return request.buildEvent(input, filePath, fileMatches, opResult, truncated, previousInternalEvent), fileMatches, nil
 }
 
-func (request *Request) findMatchesWithReader(reader io.Reader, input, filePath string, totalBytes int64, previous output.InternalEvent) ([]FileMatch, *operators.Result) {
+func (request *Request) findMatchesWithReader(reader io.Reader, input, filePath string, totalBytes int64, truncated bool, previous output.InternalEvent) ([]FileMatch, *operators.Result) {
 	var bytesCount, linesCount, wordsCount int
 	isResponseDebug := request.options.Options.Debug || request.options.Options.DebugResponse
 	totalBytesString := units.BytesSize(float64(totalBytes))
@@ -230,6 +231,7 @@ func (request *Request) findMatchesWithReader(reader io.Reader, input, filePath
 
 		gologger.Verbose().Msgf("[%s] Processing file %s chunk %s/%s", request.options.TemplateID, filePath, processedBytes, totalBytesString)
 		dslMap := request.responseToDSLMap(lineContent, input, filePath)
+		dslMap["truncated"] = truncated
 		for k, v := range previous {
 			dslMap[k] = v
 		}
@@ -291,10 +293,11 @@ func (request *Request) findMatchesWithReader(reader io.Reader, input, filePath
 	return fileMatches, opResult
 }
 
-func (request *Request) buildEvent(input, filePath string, fileMatches []FileMatch, operatorResult *operators.Result, previous output.InternalEvent) *output.InternalWrappedEvent {
+func (request *Request) buildEvent(input, filePath string, fileMatches []FileMatch, operatorResult *operators.Result, truncated bool, previous output.InternalEvent) *output.InternalWrappedEvent {
 	exprLines := make(map[string][]int)
 	exprBytes := make(map[string][]int)
 	internalEvent := request.responseToDSLMap("", input, filePath)
+	internalEvent["truncated"] = truncated
 	for k, v := range previous {
 		internalEvent[k] = v
 	}
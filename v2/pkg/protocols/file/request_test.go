@@ -79,3 +79,46 @@ func TestFileExecuteWithResults(t *testing.T) {
 	require.Equal(t, "1.1.1.1", finalEvent.Results[0].ExtractedResults[0], "could not get correct extracted results")
 	finalEvent = nil
 }
+
+func TestFileExecuteMaxBodySize(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-file-max-body-size"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.MaxBodySize = 16
+	request := &Request{
+		ID:         templateID,
+		Extensions: []string{"all"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Name:  "test",
+				Part:  "raw",
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"TEST"},
+			}},
+		},
+		options: executerOpts,
+	}
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile file request")
+
+	tempDir, err := os.MkdirTemp("", "test-*")
+	require.Nil(t, err, "could not create temporary directory")
+	defer os.RemoveAll(tempDir)
+
+	err = os.WriteFile(filepath.Join(tempDir, "config.yaml"), []byte("TEST\n0123456789\n1.1.1.1\n"), os.ModePerm)
+	require.Nil(t, err, "could not write temporary file")
+
+	var finalEvent *output.InternalWrappedEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput(tempDir), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute file request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.Equal(t, true, finalEvent.InternalEvent["truncated"], "could not get truncated flag")
+	require.True(t, finalEvent.Results[0].Truncated, "truncation not included in output")
+}
@@ -0,0 +1,69 @@
+package headless
+
+import (
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/compare"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/headless/engine"
+)
+
+// observationActions are the actions which only load or observe the page,
+// without interacting with it or altering its requests, and can be shared.
+var observationActions = map[engine.ActionType]struct{}{
+	engine.ActionNavigate:          {},
+	engine.ActionWaitLoad:          {},
+	engine.ActionWaitEvent:         {},
+	engine.ActionWaitVisible:       {},
+	engine.ActionWaitResponse:      {},
+	engine.ActionSleep:             {},
+	engine.ActionExtract:           {},
+	engine.ActionGetResource:       {},
+	engine.ActionGetElementInfo:    {},
+	engine.ActionExtractLinks:      {},
+	engine.ActionExtractForms:      {},
+	engine.ActionJSEndpoints:       {},
+	engine.ActionServiceWorkers:    {},
+	engine.ActionComputedStyle:     {},
+	engine.ActionMixedContent:      {},
+	engine.ActionDOMHash:           {},
+	engine.ActionRedirects:         {},
+	engine.ActionAccessibilityTree: {},
+	engine.ActionCSPNonce:          {},
+	engine.ActionWebVitals:         {},
+	engine.ActionMetadata:          {},
+	engine.ActionClickjacking:      {},
+	engine.ActionEventHandlers:     {},
+	engine.ActionWASM:              {},
+	engine.ActionTrackers:          {},
+	engine.ActionStorage:           {},
+	engine.ActionPermissionsPolicy: {},
+	engine.ActionSourceMaps:        {},
+	engine.ActionPrefilledFields:   {},
+	engine.ActionInsecureForms:     {},
+}
+
+// CanCluster returns true if the request can be clustered.
+//
+// Requests are clustered if their steps are identical and only load or observe
+// the page, so that a single rendered page can be shared by their operators.
+// Requests interacting with the page, such as clicking or typing, running scripts
+// or altering the requests of the page are never clustered.
+func (request *Request) CanCluster(other *Request) bool {
+	if len(request.Payloads) > 0 || len(other.Payloads) > 0 || request.ID != "" || request.StopAtFirstMatch || other.StopAtFirstMatch {
+		return false
+	}
+	if request.UserAgent.Value != other.UserAgent.Value || request.CustomUserAgent != other.CustomUserAgent {
+		return false
+	}
+	if len(request.Steps) != len(other.Steps) {
+		return false
+	}
+	for i, step := range request.Steps {
+		otherStep := other.Steps[i]
+		if _, ok := observationActions[step.ActionType.ActionType]; !ok {
+			return false
+		}
+		if step.ActionType.ActionType != otherStep.ActionType.ActionType || step.Name != otherStep.Name || !compare.StringMap(step.Data, otherStep.Data) {
+			return false
+		}
+	}
+	return true
+}
@@ -26,7 +26,7 @@ type Action struct {
 	Description string `yaml:"description,omitempty" json:"description,omitempty" jsonschema:"title=description for headless action,description=Description of the headless action"`
 	// description: |
 	//   Action is the type of the action to perform.
-	ActionType ActionTypeHolder `yaml:"action" json:"action" jsonschema:"title=action to perform,description=Type of actions to perform,enum=navigate,enum=script,enum=click,enum=rightclick,enum=text,enum=screenshot,enum=time,enum=select,enum=files,enum=waitload,enum=getresource,enum=extract,enum=setmethod,enum=addheader,enum=setheader,enum=deleteheader,enum=setbody,enum=waitevent,enum=keyboard,enum=debug,enum=sleep"`
+	ActionType ActionTypeHolder `yaml:"action" json:"action" jsonschema:"title=action to perform,description=Type of actions to perform,enum=navigate,enum=script,enum=click,enum=rightclick,enum=text,enum=screenshot,enum=time,enum=select,enum=files,enum=waitload,enum=getresource,enum=extract,enum=setmethod,enum=addheader,enum=setheader,enum=deleteheader,enum=setbody,enum=waitevent,enum=keyboard,enum=debug,enum=sleep,enum=elementinfo,enum=screenshotdiff,enum=links,enum=forms,enum=exportsession,enum=importsession,enum=setreferrer,enum=waitresponse,enum=paste,enum=jsendpoints,enum=screenshotelement,enum=setcookie,enum=mockresponse,enum=serviceworkers,enum=unload,enum=computedstyle,enum=evalonnewdocument,enum=mixedcontent,enum=domhash,enum=setorigin,enum=switchtab,enum=openredirect,enum=fillform,enum=redirects,enum=axtree,enum=domxss,enum=waitinteraction,enum=cspnonce,enum=webvitals,enum=metadata,enum=clickjacking,enum=eventhandlers,enum=wasm,enum=trackers,enum=storage,enum=websocket,enum=corsprobe,enum=prototypepollution,enum=permissionspolicy,enum=sourcemaps,enum=prefilledfields,enum=insecureforms,enum=domclobbering"`
 }
 
 // String returns the string representation of an action
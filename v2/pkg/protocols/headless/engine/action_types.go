@@ -80,60 +80,275 @@ const (
 	// ActionWaitVisible waits until an element appears.
 	// name:waitvisible
 	ActionWaitVisible
+	// ActionGetElementInfo gets the bounding box and visibility details of an element.
+	// name:elementinfo
+	ActionGetElementInfo
+	// ActionScreenshotDiff compares a screenshot of the page with a baseline image.
+	// name:screenshotdiff
+	ActionScreenshotDiff
+	// ActionExtractLinks extracts all the links from the rendered page.
+	// name:links
+	ActionExtractLinks
+	// ActionExtractForms extracts all the forms from the rendered page.
+	// name:forms
+	ActionExtractForms
+	// ActionExportSession exports the cookies and storage of the page to a file.
+	// name:exportsession
+	ActionExportSession
+	// ActionImportSession imports the cookies and storage of the page from a file.
+	// name:importsession
+	ActionImportSession
+	// ActionSetReferrer sets the referrer of the next navigation.
+	// name:setreferrer
+	ActionSetReferrer
+	// ActionWaitResponse waits for a network response matching a url pattern.
+	// name:waitresponse
+	ActionWaitResponse
+	// ActionPaste pastes text into an input element.
+	// name:paste
+	ActionPaste
+	// ActionJSEndpoints extracts the endpoints referenced by the scripts loaded by the page.
+	// name:jsendpoints
+	ActionJSEndpoints
+	// ActionScreenshotElement takes a screenshot of an element.
+	// name:screenshotelement
+	ActionScreenshotElement
+	// ActionSetCookie sets a cookie with its full attributes in the browser.
+	// name:setcookie
+	ActionSetCookie
+	// ActionMockResponse fulfills the requests matching a url pattern with a specified response.
+	// name:mockresponse
+	ActionMockResponse
+	// ActionServiceWorkers enumerates the service workers registered in the browser.
+	// name:serviceworkers
+	ActionServiceWorkers
+	// ActionUnload navigates away from or reloads the page capturing the requests sent while leaving it.
+	// name:unload
+	ActionUnload
+	// ActionComputedStyle gets the computed styles of an element.
+	// name:computedstyle
+	ActionComputedStyle
+	// ActionEvalOnNewDocument injects a script evaluated before the page scripts on every new document.
+	// name:evalonnewdocument
+	ActionEvalOnNewDocument
+	// ActionMixedContent detects the insecure subresources loaded by a https page.
+	// name:mixedcontent
+	ActionMixedContent
+	// ActionDOMHash computes a hash of the normalized dom for change detection.
+	// name:domhash
+	ActionDOMHash
+	// ActionSetOrigin sets the Origin header of the next request and captures its cors headers.
+	// name:setorigin
+	ActionSetOrigin
+	// ActionSwitchTab switches the actions to a popup opened by the page, or back to the page.
+	// name:switchtab
+	ActionSwitchTab
+	// ActionOpenRedirect navigates to an url and detects redirects, including client-side ones, to a marker host.
+	// name:openredirect
+	ActionOpenRedirect
+	// ActionFillForm fills the fields of a form and submits it.
+	// name:fillform
+	ActionFillForm
+	// ActionRedirects detects the meta refresh and javascript redirects of the page.
+	// name:redirects
+	ActionRedirects
+	// ActionAccessibilityTree captures the accessibility tree of the page.
+	// name:axtree
+	ActionAccessibilityTree
+	// ActionDOMXSS detects dom based xss by observing the tainted values received by dangerous sinks.
+	// name:domxss
+	ActionDOMXSS
+	// ActionWaitInteraction waits until an interactsh interaction is received for the page.
+	// name:waitinteraction
+	ActionWaitInteraction
+	// ActionCSPNonce detects the content security policy nonces reused across document responses.
+	// name:cspnonce
+	ActionCSPNonce
+	// ActionWebVitals collects the largest contentful paint, layout shifts and long tasks of the page.
+	// name:webvitals
+	ActionWebVitals
+	// ActionMetadata collects the meta tags and json-ld structured data of the page.
+	// name:metadata
+	ActionMetadata
+	// ActionClickjacking detects whether the page can be framed by another origin for clickjacking.
+	// name:clickjacking
+	ActionClickjacking
+	// ActionEventHandlers extracts the inline event handlers and javascript urls of the page.
+	// name:eventhandlers
+	ActionEventHandlers
+	// ActionWASM detects the WebAssembly modules compiled or fetched by the page.
+	// name:wasm
+	ActionWASM
+	// ActionTrackers detects the third-party trackers and analytics sdks loaded by the page.
+	// name:trackers
+	ActionTrackers
+	// ActionStorage measures the localStorage and IndexedDB storage of the page.
+	// name:storage
+	ActionStorage
+	// ActionWebSocket modifies and injects the websocket frames of the page.
+	// name:websocket
+	ActionWebSocket
+	// ActionCORSProbe probes an endpoint with varied origins for credentialed cors misconfigurations.
+	// name:corsprobe
+	ActionCORSProbe
+	// ActionPrototypePollution detects client-side prototype pollution from the query or the fragment.
+	// name:prototypepollution
+	ActionPrototypePollution
+	// ActionPermissionsPolicy captures the permissions policy of the page, with the legacy feature policy.
+	// name:permissionspolicy
+	ActionPermissionsPolicy
+	// ActionSourceMaps detects the inline and accessible external source maps of the loaded scripts.
+	// name:sourcemaps
+	ActionSourceMaps
+	// ActionPrefilledFields detects the password and sensitive fields of the page having a value before any user input.
+	// name:prefilledfields
+	ActionPrefilledFields
+	// ActionInsecureForms detects the forms of the page submitting over http or to another origin.
+	// name:insecureforms
+	ActionInsecureForms
+	// ActionDOMClobbering detects the globals read by the page which elements injected by id or name can clobber.
+	// name:domclobbering
+	ActionDOMClobbering
 	// limit
 	limit
 )
 
 // ActionStringToAction converts an action from string to internal representation
 var ActionStringToAction = map[string]ActionType{
-	"navigate":     ActionNavigate,
-	"script":       ActionScript,
-	"click":        ActionClick,
-	"rightclick":   ActionRightClick,
-	"text":         ActionTextInput,
-	"screenshot":   ActionScreenshot,
-	"time":         ActionTimeInput,
-	"select":       ActionSelectInput,
-	"files":        ActionFilesInput,
-	"waitload":     ActionWaitLoad,
-	"getresource":  ActionGetResource,
-	"extract":      ActionExtract,
-	"setmethod":    ActionSetMethod,
-	"addheader":    ActionAddHeader,
-	"setheader":    ActionSetHeader,
-	"deleteheader": ActionDeleteHeader,
-	"setbody":      ActionSetBody,
-	"waitevent":    ActionWaitEvent,
-	"keyboard":     ActionKeyboard,
-	"debug":        ActionDebug,
-	"sleep":        ActionSleep,
-	"waitvisible":  ActionWaitVisible,
+	"navigate":           ActionNavigate,
+	"script":             ActionScript,
+	"click":              ActionClick,
+	"rightclick":         ActionRightClick,
+	"text":               ActionTextInput,
+	"screenshot":         ActionScreenshot,
+	"time":               ActionTimeInput,
+	"select":             ActionSelectInput,
+	"files":              ActionFilesInput,
+	"waitload":           ActionWaitLoad,
+	"getresource":        ActionGetResource,
+	"extract":            ActionExtract,
+	"setmethod":          ActionSetMethod,
+	"addheader":          ActionAddHeader,
+	"setheader":          ActionSetHeader,
+	"deleteheader":       ActionDeleteHeader,
+	"setbody":            ActionSetBody,
+	"waitevent":          ActionWaitEvent,
+	"keyboard":           ActionKeyboard,
+	"debug":              ActionDebug,
+	"sleep":              ActionSleep,
+	"waitvisible":        ActionWaitVisible,
+	"elementinfo":        ActionGetElementInfo,
+	"screenshotdiff":     ActionScreenshotDiff,
+	"links":              ActionExtractLinks,
+	"forms":              ActionExtractForms,
+	"exportsession":      ActionExportSession,
+	"importsession":      ActionImportSession,
+	"setreferrer":        ActionSetReferrer,
+	"waitresponse":       ActionWaitResponse,
+	"paste":              ActionPaste,
+	"jsendpoints":        ActionJSEndpoints,
+	"screenshotelement":  ActionScreenshotElement,
+	"setcookie":          ActionSetCookie,
+	"mockresponse":       ActionMockResponse,
+	"serviceworkers":     ActionServiceWorkers,
+	"unload":             ActionUnload,
+	"computedstyle":      ActionComputedStyle,
+	"evalonnewdocument":  ActionEvalOnNewDocument,
+	"mixedcontent":       ActionMixedContent,
+	"domhash":            ActionDOMHash,
+	"setorigin":          ActionSetOrigin,
+	"switchtab":          ActionSwitchTab,
+	"openredirect":       ActionOpenRedirect,
+	"fillform":           ActionFillForm,
+	"redirects":          ActionRedirects,
+	"axtree":             ActionAccessibilityTree,
+	"domxss":             ActionDOMXSS,
+	"waitinteraction":    ActionWaitInteraction,
+	"cspnonce":           ActionCSPNonce,
+	"webvitals":          ActionWebVitals,
+	"metadata":           ActionMetadata,
+	"clickjacking":       ActionClickjacking,
+	"eventhandlers":      ActionEventHandlers,
+	"wasm":               ActionWASM,
+	"trackers":           ActionTrackers,
+	"storage":            ActionStorage,
+	"websocket":          ActionWebSocket,
+	"corsprobe":          ActionCORSProbe,
+	"prototypepollution": ActionPrototypePollution,
+	"permissionspolicy":  ActionPermissionsPolicy,
+	"sourcemaps":         ActionSourceMaps,
+	"prefilledfields":    ActionPrefilledFields,
+	"insecureforms":      ActionInsecureForms,
+	"domclobbering":      ActionDOMClobbering,
 }
 
 // ActionToActionString converts an action from  internal representation to string
 var ActionToActionString = map[ActionType]string{
-	ActionNavigate:     "navigate",
-	ActionScript:       "script",
-	ActionClick:        "click",
-	ActionRightClick:   "rightclick",
-	ActionTextInput:    "text",
-	ActionScreenshot:   "screenshot",
-	ActionTimeInput:    "time",
-	ActionSelectInput:  "select",
-	ActionFilesInput:   "files",
-	ActionWaitLoad:     "waitload",
-	ActionGetResource:  "getresource",
-	ActionExtract:      "extract",
-	ActionSetMethod:    "setmethod",
-	ActionAddHeader:    "addheader",
-	ActionSetHeader:    "setheader",
-	ActionDeleteHeader: "deleteheader",
-	ActionSetBody:      "setbody",
-	ActionWaitEvent:    "waitevent",
-	ActionKeyboard:     "keyboard",
-	ActionDebug:        "debug",
-	ActionSleep:        "sleep",
-	ActionWaitVisible:  "waitvisible",
+	ActionNavigate:           "navigate",
+	ActionScript:             "script",
+	ActionClick:              "click",
+	ActionRightClick:         "rightclick",
+	ActionTextInput:          "text",
+	ActionScreenshot:         "screenshot",
+	ActionTimeInput:          "time",
+	ActionSelectInput:        "select",
+	ActionFilesInput:         "files",
+	ActionWaitLoad:           "waitload",
+	ActionGetResource:        "getresource",
+	ActionExtract:            "extract",
+	ActionSetMethod:          "setmethod",
+	ActionAddHeader:          "addheader",
+	ActionSetHeader:          "setheader",
+	ActionDeleteHeader:       "deleteheader",
+	ActionSetBody:            "setbody",
+	ActionWaitEvent:          "waitevent",
+	ActionKeyboard:           "keyboard",
+	ActionDebug:              "debug",
+	ActionSleep:              "sleep",
+	ActionWaitVisible:        "waitvisible",
+	ActionGetElementInfo:     "elementinfo",
+	ActionScreenshotDiff:     "screenshotdiff",
+	ActionExtractLinks:       "links",
+	ActionExtractForms:       "forms",
+	ActionExportSession:      "exportsession",
+	ActionImportSession:      "importsession",
+	ActionSetReferrer:        "setreferrer",
+	ActionWaitResponse:       "waitresponse",
+	ActionPaste:              "paste",
+	ActionJSEndpoints:        "jsendpoints",
+	ActionScreenshotElement:  "screenshotelement",
+	ActionSetCookie:          "setcookie",
+	ActionMockResponse:       "mockresponse",
+	ActionServiceWorkers:     "serviceworkers",
+	ActionUnload:             "unload",
+	ActionComputedStyle:      "computedstyle",
+	ActionEvalOnNewDocument:  "evalonnewdocument",
+	ActionMixedContent:       "mixedcontent",
+	ActionDOMHash:            "domhash",
+	ActionSetOrigin:          "setorigin",
+	ActionSwitchTab:          "switchtab",
+	ActionOpenRedirect:       "openredirect",
+	ActionFillForm:           "fillform",
+	ActionRedirects:          "redirects",
+	ActionAccessibilityTree:  "axtree",
+	ActionDOMXSS:             "domxss",
+	ActionWaitInteraction:    "waitinteraction",
+	ActionCSPNonce:           "cspnonce",
+	ActionWebVitals:          "webvitals",
+	ActionMetadata:           "metadata",
+	ActionClickjacking:       "clickjacking",
+	ActionEventHandlers:      "eventhandlers",
+	ActionWASM:               "wasm",
+	ActionTrackers:           "trackers",
+	ActionStorage:            "storage",
+	ActionWebSocket:          "websocket",
+	ActionCORSProbe:          "corsprobe",
+	ActionPrototypePollution: "prototypepollution",
+	ActionPermissionsPolicy:  "permissionspolicy",
+	ActionSourceMaps:         "sourcemaps",
+	ActionPrefilledFields:    "prefilledfields",
+	ActionInsecureForms:      "insecureforms",
+	ActionDOMClobbering:      "domclobbering",
 }
 
 // GetSupportedActionTypes returns list of supported types
@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// SetCookie is a cookie set by a Set-Cookie header of a response received by the page
+type SetCookie struct {
+	// URL is the url of the response which set the cookie
+	URL string `json:"url"`
+	// Name is the name of the cookie
+	Name string `json:"name"`
+	// Value is the value of the cookie
+	Value string `json:"value"`
+	// Domain is the domain attribute of the cookie
+	Domain string `json:"domain,omitempty"`
+	// Path is the path attribute of the cookie
+	Path string `json:"path,omitempty"`
+	// Expires is the expires attribute of the cookie in RFC3339 format
+	Expires string `json:"expires,omitempty"`
+	// MaxAge is the max-age attribute of the cookie, negative if it is zero or negative
+	MaxAge int `json:"max_age,omitempty"`
+	// Secure is true if the cookie has the secure attribute
+	Secure bool `json:"secure"`
+	// HTTPOnly is true if the cookie has the httponly attribute
+	HTTPOnly bool `json:"httponly"`
+	// SameSite is the samesite attribute of the cookie (Strict, Lax or None)
+	SameSite string `json:"samesite,omitempty"`
+	// Cleared is true if the cookie was expired by the header, clearing it
+	Cleared bool `json:"cleared"`
+	// ThirdParty is true if the cookie was set by a response from a third-party domain
+	ThirdParty bool `json:"third_party"`
+	// Raw is the value of the Set-Cookie header
+	Raw string `json:"raw"`
+}
+
+// addSetCookies records the cookies set by the Set-Cookie headers of a response
+func (p *Page) addSetCookies(responseURL string, headers http.Header, received time.Time) {
+	cookies := parseSetCookies(responseURL, headers, received)
+	if len(cookies) == 0 {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.SetCookies = append(p.SetCookies, cookies...)
+}
+
+// parseSetCookies parses the Set-Cookie headers of a response, ignoring invalid cookies.
+func parseSetCookies(responseURL string, headers http.Header, received time.Time) []SetCookie {
+	var values []string
+	for _, value := range headers.Values("Set-Cookie") {
+		// chrome joins multiple headers with the same name using newlines
+		values = append(values, strings.Split(value, "\n")...)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	parsed := (&http.Response{Header: http.Header{"Set-Cookie": values}}).Cookies()
+	cookies := make([]SetCookie, 0, len(parsed))
+	for _, cookie := range parsed {
+		setCookie := SetCookie{
+			URL:      responseURL,
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			MaxAge:   cookie.MaxAge,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HttpOnly,
+			SameSite: sameSiteString(cookie.SameSite),
+			Cleared:  cookie.MaxAge < 0 || (!cookie.Expires.IsZero() && cookie.Expires.Before(received)),
+			Raw:      cookie.Raw,
+		}
+		if !cookie.Expires.IsZero() {
+			setCookie.Expires = cookie.Expires.UTC().Format(time.RFC3339)
+		}
+		cookies = append(cookies, setCookie)
+	}
+	return cookies
+}
+
+// sameSiteString returns the attribute value of a samesite mode
+func sameSiteString(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return ""
+	}
+}
+
+// DumpSetCookies returns the cookies set by the responses received by the page as json.
+//
+// Cookies are third-party if the registrable domain of the response setting them
+// differs from the one of the first document loaded by the page.
+func (p *Page) DumpSetCookies() string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	var firstParty string
+	for _, historyData := range p.History {
+		if historyData.ResourceType == proto.NetworkResourceTypeDocument {
+			firstParty = registrableDomain(historyData.URL)
+			break
+		}
+	}
+
+	cookies := make([]SetCookie, len(p.SetCookies))
+	for i, cookie := range p.SetCookies {
+		domain := registrableDomain(cookie.URL)
+		cookie.ThirdParty = firstParty != "" && domain != "" && domain != firstParty
+		cookies[i] = cookie
+	}
+	data, _ := json.Marshal(cookies)
+	return string(data)
+}
@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSetCookies(t *testing.T) {
+	received := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	headers := http.Header{}
+	headers.Add("Set-Cookie", "session=abc; Domain=example.com; Path=/; Secure; HttpOnly; SameSite=Lax; Expires=Fri, 01 Jan 2100 00:00:00 GMT")
+	headers.Add("Set-Cookie", "theme=dark; Max-Age=3600\nold=; Max-Age=0")
+	headers.Add("Set-Cookie", "legacy=1; Expires=Thu, 01 Jan 1970 00:00:00 GMT")
+
+	cookies := parseSetCookies("https://example.com/login", headers, received)
+	require.Len(t, cookies, 4, "could not parse set cookies")
+
+	require.Equal(t, SetCookie{
+		URL:      "https://example.com/login",
+		Name:     "session",
+		Value:    "abc",
+		Domain:   "example.com",
+		Path:     "/",
+		Expires:  "2100-01-01T00:00:00Z",
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: "Lax",
+		Raw:      "session=abc; Domain=example.com; Path=/; Secure; HttpOnly; SameSite=Lax; Expires=Fri, 01 Jan 2100 00:00:00 GMT",
+	}, cookies[0], "could not parse cookie attributes")
+	require.Equal(t, 3600, cookies[1].MaxAge, "could not parse max-age")
+	require.False(t, cookies[1].Cleared, "got cleared cookie with positive max-age")
+	require.Equal(t, "old", cookies[2].Name, "could not split joined set cookie headers")
+	require.True(t, cookies[2].Cleared, "could not detect cookie cleared with max-age")
+	require.True(t, cookies[3].Cleared, "could not detect cookie cleared with expires")
+
+	require.Nil(t, parseSetCookies("https://example.com/", http.Header{}, received), "got cookies without set cookie headers")
+}
+
+func TestDumpSetCookies(t *testing.T) {
+	page := &Page{mutex: &sync.RWMutex{}, History: []HistoryData{
+		{URL: "https://www.example.com/", ResourceType: proto.NetworkResourceTypeDocument},
+	}}
+	page.addSetCookies("https://www.example.com/", http.Header{"Set-Cookie": {"session=abc"}}, time.Now())
+	page.addSetCookies("https://cdn.tracker.net/pixel.gif", http.Header{"Set-Cookie": {"uid=123; SameSite=None; Secure"}}, time.Now())
+
+	var cookies []SetCookie
+	require.Nil(t, json.Unmarshal([]byte(page.DumpSetCookies()), &cookies), "could not unmarshal set cookies")
+	require.Len(t, cookies, 2, "could not get set cookies")
+	require.False(t, cookies[0].ThirdParty, "got third-party first-party cookie")
+	require.True(t, cookies[1].ThirdParty, "could not detect third-party cookie")
+	require.Equal(t, "None", cookies[1].SameSite, "could not parse samesite")
+}
@@ -0,0 +1,189 @@
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// corsCheck is an origin set by the setorigin action for the next request
+// matching its url pattern, along with the cors headers of the response.
+type corsCheck struct {
+	name   string
+	origin string
+	// url is the pattern of the request url, nil matching any request
+	url *regexp.Regexp
+	// failedIndex is the index of the first failed request following the action
+	failedIndex int
+
+	requestURL       string
+	received         bool
+	allowOrigin      string
+	allowCredentials string
+}
+
+// corsResult is the outcome of a cors check stored as json in the output
+type corsResult struct {
+	URL              string `json:"url"`
+	Origin           string `json:"origin"`
+	AllowOrigin      string `json:"allow_origin"`
+	AllowCredentials string `json:"allow_credentials"`
+	Reflected        bool   `json:"reflected"`
+	Error            string `json:"error,omitempty"`
+}
+
+// defaultCORSProbeOrigin is the attacker origin of the corsprobe action
+const defaultCORSProbeOrigin = "https://nuclei-cors.example"
+
+// corsProbeKinds are the kinds of origins sent by the corsprobe action
+var corsProbeKinds = []string{"reflected", "null", "subdomain", "suffix"}
+
+// corsProbeResult is the outcome of a corsprobe request stored as json in the output
+type corsProbeResult struct {
+	Kind             string `json:"kind"`
+	Origin           string `json:"origin"`
+	StatusCode       int    `json:"status_code"`
+	AllowOrigin      string `json:"allow_origin"`
+	AllowCredentials string `json:"allow_credentials"`
+	Vulnerable       bool   `json:"vulnerable"`
+	Error            string `json:"error,omitempty"`
+}
+
+// corsProbeOrigin returns the origin of a kind of corsprobe for a target url
+func corsProbeOrigin(kind string, target, attacker *url.URL) string {
+	switch kind {
+	case "null":
+		return "null"
+	case "subdomain":
+		return target.Scheme + "://nuclei-cors." + target.Host
+	case "suffix":
+		return target.Scheme + "://" + target.Hostname() + "." + attacker.Host
+	default:
+		return attacker.Scheme + "://" + attacker.Host
+	}
+}
+
+// FailedRequest is a request of the page which failed to load
+type FailedRequest struct {
+	// URL is the url of the request
+	URL string
+	// ResourceType is the type of the resource requested
+	ResourceType proto.NetworkResourceType
+	// ErrorText is the error reported by the browser
+	ErrorText string
+	// CORSError is the reason the request was blocked by cors, if any
+	CORSError string
+}
+
+// matches returns true if a request url is the one the origin was set for,
+// or matches the url pattern if the origin wasn't used yet.
+func (check *corsCheck) matches(requestURL string) bool {
+	if check.requestURL != "" {
+		return requestURL == check.requestURL
+	}
+	return check.url == nil || check.url.MatchString(requestURL)
+}
+
+// takeCORSCheck returns the pending cors check for a request url, marking
+// it as used so that the origin is only set for a single request.
+func (p *Page) takeCORSCheck(requestURL string) *corsCheck {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, check := range p.corsChecks {
+		if check.requestURL == "" && check.matches(requestURL) {
+			check.requestURL = requestURL
+			return check
+		}
+	}
+	return nil
+}
+
+// setCORSResponse records the cors headers of the response to a cors check request
+func (p *Page) setCORSResponse(check *corsCheck, headers http.Header) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	check.received = true
+	check.allowOrigin = headers.Get("Access-Control-Allow-Origin")
+	check.allowCredentials = headers.Get("Access-Control-Allow-Credentials")
+}
+
+// captureFailedRequests records the requests of the page which fail to load,
+// including the ones blocked by cors, in the failed requests of the page.
+func (p *Page) captureFailedRequests() {
+	p.mutex.Lock()
+	if p.capturingFailures {
+		p.mutex.Unlock()
+		return
+	}
+	p.capturingFailures = true
+	p.mutex.Unlock()
+
+	// events are handled sequentially so the urls don't need locking
+	urls := make(map[proto.NetworkRequestID]string)
+	wait := p.page.EachEvent(
+		func(e *proto.NetworkRequestWillBeSent) { urls[e.RequestID] = e.Request.URL },
+		func(e *proto.NetworkLoadingFailed) {
+			failed := FailedRequest{URL: urls[e.RequestID], ResourceType: e.Type, ErrorText: e.ErrorText}
+			if e.CorsErrorStatus != nil {
+				failed.CORSError = string(e.CorsErrorStatus.CorsError)
+			}
+			p.mutex.Lock()
+			p.FailedRequests = append(p.FailedRequests, failed)
+			p.mutex.Unlock()
+		},
+	)
+	go wait()
+}
+
+// writeCORSOutputs writes the outcome of the cors checks of the page to the output
+func (p *Page) writeCORSOutputs(out map[string]string) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for _, check := range p.corsChecks {
+		for key, value := range corsOutputs(check, p.FailedRequests) {
+			out[key] = value
+		}
+	}
+}
+
+// corsOutputs returns the output values of a cors check. The cors error is
+// the one of the first request blocked by cors matching the check.
+func corsOutputs(check *corsCheck, failedRequests []FailedRequest) map[string]string {
+	result := corsResult{
+		URL:              check.requestURL,
+		Origin:           check.origin,
+		AllowOrigin:      check.allowOrigin,
+		AllowCredentials: check.allowCredentials,
+		Reflected:        check.received && check.allowOrigin == check.origin,
+	}
+	if check.failedIndex <= len(failedRequests) {
+		for _, failed := range failedRequests[check.failedIndex:] {
+			if failed.CORSError != "" && check.matches(failed.URL) {
+				result.Error = failed.CORSError
+				break
+			}
+		}
+	}
+
+	out := make(map[string]string)
+	if data, err := json.Marshal(result); err == nil {
+		out[check.name] = string(data)
+	}
+	out[check.name+"_url"] = result.URL
+	out[check.name+"_origin"] = result.Origin
+	out[check.name+"_allow_origin"] = result.AllowOrigin
+	out[check.name+"_allow_credentials"] = result.AllowCredentials
+	out[check.name+"_reflected"] = strconv.FormatBool(result.Reflected)
+	out[check.name+"_credentials"] = strconv.FormatBool(strings.EqualFold(strings.TrimSpace(result.AllowCredentials), "true"))
+	out[check.name+"_blocked"] = strconv.FormatBool(result.Error != "")
+	out[check.name+"_error"] = result.Error
+	return out
+}
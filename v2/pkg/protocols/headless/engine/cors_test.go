@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"net/http"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakeCORSCheck(t *testing.T) {
+	page := &Page{mutex: &sync.RWMutex{}}
+	api := &corsCheck{name: "api", origin: "https://evil.com", url: regexp.MustCompile(`/api/`)}
+	next := &corsCheck{name: "next", origin: "null"}
+	page.corsChecks = []*corsCheck{api, next}
+
+	require.Equal(t, next, page.takeCORSCheck("https://example.com/"), "wrong check for request")
+	require.Nil(t, page.takeCORSCheck("https://example.com/app.js"), "check used more than once")
+	require.Equal(t, api, page.takeCORSCheck("https://example.com/api/user"), "wrong check for api request")
+	require.Nil(t, page.takeCORSCheck("https://example.com/api/user"), "check used more than once")
+
+	headers := http.Header{}
+	headers.Set("Access-Control-Allow-Origin", "https://evil.com")
+	headers.Set("Access-Control-Allow-Credentials", "true")
+	page.setCORSResponse(api, headers)
+
+	out := make(map[string]string)
+	page.writeCORSOutputs(out)
+	require.Equal(t, "https://example.com/api/user", out["api_url"], "wrong request url")
+	require.Equal(t, "https://evil.com", out["api_allow_origin"], "wrong allowed origin")
+	require.Equal(t, "true", out["api_reflected"], "reflected origin not detected")
+	require.Equal(t, "true", out["api_credentials"], "allowed credentials not detected")
+	require.Equal(t, `{"url":"https://example.com/api/user","origin":"https://evil.com","allow_origin":"https://evil.com","allow_credentials":"true","reflected":true}`, out["api"], "wrong cors result")
+	require.Equal(t, "false", out["next_reflected"], "origin reflected without response")
+}
+
+func TestCORSOutputsBlocked(t *testing.T) {
+	check := &corsCheck{name: "cors", origin: "https://evil.com", url: regexp.MustCompile(`/api/`), failedIndex: 1}
+	failed := []FailedRequest{
+		{URL: "https://example.com/api/old", CORSError: "MissingAllowOriginHeader"},
+		{URL: "https://example.com/logo.png", ErrorText: "net::ERR_FAILED"},
+		{URL: "https://example.com/api/user", CORSError: "PreflightMissingAllowOriginHeader"},
+	}
+
+	out := corsOutputs(check, failed)
+	require.Equal(t, "true", out["cors_blocked"], "blocked preflight not detected")
+	require.Equal(t, "PreflightMissingAllowOriginHeader", out["cors_error"], "wrong cors error")
+	require.Equal(t, "false", out["cors_reflected"], "blocked origin reflected")
+
+	check.requestURL = "https://example.com/api/other"
+	out = corsOutputs(check, failed)
+	require.Equal(t, "false", out["cors_blocked"], "failure of another request reported")
+}
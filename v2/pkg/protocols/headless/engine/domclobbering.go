@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// domClobberingNames are the globals commonly read by pages as optional
+// configuration, which elements with the same id or name can clobber
+var domClobberingNames = []string{
+	"config", "CONFIG", "settings", "options", "defaultConfig", "globalConfig", "appConfig",
+	"env", "ENV", "debug", "DEBUG", "isAdmin", "admin", "user", "currentUser",
+	"baseUrl", "baseURL", "apiUrl", "cdnUrl", "scriptUrl", "redirectUrl", "returnUrl",
+	"callback", "nonce", "csrfToken",
+}
+
+// clobberingNameRegex matches the names that can be clobbered as globals
+var clobberingNameRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// clobberedGlobal is a global read by the page while clobbered by an injected element
+type clobberedGlobal struct {
+	// Name is the name of the global, the id and name of the injected element
+	Name string `json:"name"`
+	// Reads is the number of times the page read the clobbered global
+	Reads int `json:"reads"`
+	// Assigned is true if the page assigned the global after reading it
+	Assigned bool `json:"assigned"`
+	// Source is the location of the script which first read the global
+	Source string `json:"source"`
+}
+
+// parseClobberingNames returns the comma separated names, or the
+// default clobbering targets if empty, failing on invalid identifiers
+func parseClobberingNames(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return domClobberingNames, nil
+	}
+	var names []string
+	seen := make(map[string]struct{})
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !clobberingNameRegex.MatchString(name) {
+			return nil, errors.Errorf("invalid clobbering name %s", name)
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// domClobberingHookJS injects at the start of the document an anchor with the
+// id and name of each name not already defined, and hooks the named access on
+// the window prototype to record the reads of the page, which get the anchor
+// as a clobbered global. Hooking the prototype, as named properties are, keeps
+// the var declarations and assignments of the page shadowing the anchors.
+const domClobberingHookJS = `(names) => {
+	if (window.__nucleiDOMClobbering) {
+		return;
+	}
+	const state = {globals: [], container: document.createElement('div')};
+	Object.defineProperty(window, '__nucleiDOMClobbering', {value: state, configurable: true});
+	state.container.hidden = true;
+	state.container.setAttribute('data-nuclei-clobbering', '');
+	const prototype = Object.getPrototypeOf(window);
+	const caller = () => {
+		const lines = (new Error().stack || '').split('\n');
+		return (lines[3] || '').trim().replace(/^at /, '');
+	};
+	for (const name of names) {
+		if (name in window) {
+			continue;
+		}
+		const element = document.createElement('a');
+		element.id = name;
+		element.setAttribute('name', name);
+		element.href = 'clobbered:' + name;
+		state.container.appendChild(element);
+		const global = {name: name, reads: 0, assigned: false, source: ''};
+		state.globals.push(global);
+		Object.defineProperty(prototype, name, {
+			configurable: true,
+			get() {
+				global.reads++;
+				if (!global.source) {
+					global.source = caller();
+				}
+				return element;
+			},
+			set(value) {
+				global.assigned = true;
+				Object.defineProperty(this, name, {value: value, writable: true, enumerable: true, configurable: true});
+			},
+		});
+	}
+	const inject = () => {
+		if (!document.documentElement) {
+			return false;
+		}
+		document.documentElement.appendChild(state.container);
+		return true;
+	};
+	if (!inject()) {
+		const observer = new MutationObserver(() => inject() && observer.disconnect());
+		observer.observe(document, {childList: true});
+	}
+}`
+
+// domClobberingResultJS returns whether the document is loaded and the
+// globals read by the page while clobbered by the injected anchors
+const domClobberingResultJS = `() => {
+	const state = window.__nucleiDOMClobbering;
+	return {
+		complete: document.readyState === 'complete',
+		clobbered: state ? state.globals.filter(global => global.reads > 0) : [],
+	};
+}`
+
+// domClobberingCleanupJS removes the injected anchors and the hooks of the names
+const domClobberingCleanupJS = `() => {
+	const state = window.__nucleiDOMClobbering;
+	if (!state) {
+		return;
+	}
+	state.container.remove();
+	const prototype = Object.getPrototypeOf(window);
+	for (const global of state.globals) {
+		delete prototype[global.name];
+	}
+	delete window.__nucleiDOMClobbering;
+}`
@@ -0,0 +1,23 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClobberingNames(t *testing.T) {
+	names, err := parseClobberingNames("")
+	require.Nil(t, err, "could not parse default names")
+	require.Equal(t, domClobberingNames, names, "wrong default names")
+
+	names, err = parseClobberingNames(" appConfig, $debug ,appConfig,,_user1")
+	require.Nil(t, err, "could not parse names")
+	require.Equal(t, []string{"appConfig", "$debug", "_user1"}, names, "wrong names")
+
+	_, err = parseClobberingNames("config,1config")
+	require.NotNil(t, err, "could parse invalid name")
+
+	_, err = parseClobberingNames("config,x]y")
+	require.NotNil(t, err, "could parse invalid name")
+}
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 
+	"github.com/corpix/uarand"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/pkg/errors"
@@ -18,22 +20,101 @@ import (
 
 // Browser is a browser structure for nuclei headless module
 type Browser struct {
-	customAgent  string
+	customAgent string
+	// rotateAgent is true if a random user agent is used for each page
+	rotateAgent  bool
 	tempDir      string
 	previousPIDs map[int32]struct{} // track already running PIDs
 	engine       *rod.Browser
 	httpclient   *http.Client
 	options      *types.Options
+
+	// pages limits the number of concurrently open pages
+	pages chan struct{}
+	// instancesCond guards the instance and page counters used for recycling
+	instancesCond   *sync.Cond
+	activeInstances int
+	// createdPages is the number of pages opened since the browser was launched
+	createdPages int
 }
 
 // New creates a new nuclei headless browser module
 func New(options *types.Options) (*Browser, error) {
+	customAgent := getCustomAgent(options)
+
+	// the browser is not launched when replaying recorded sessions
+	if options.HeadlessReplay != "" {
+		return &Browser{
+			customAgent:   customAgent,
+			rotateAgent:   rotateUserAgent(options, customAgent),
+			options:       options,
+			instancesCond: sync.NewCond(&sync.Mutex{}),
+		}, nil
+	}
+
 	dataStore, err := os.MkdirTemp("", "nuclei-*")
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create temporary directory")
 	}
 	previousPIDs := processutil.FindProcesses(processutil.IsChromeProcess)
 
+	browser, err := launchBrowser(options, dataStore)
+	if err != nil {
+		return nil, err
+	}
+	httpclient, err := newHttpClient(options)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := &Browser{
+		tempDir:       dataStore,
+		customAgent:   customAgent,
+		rotateAgent:   rotateUserAgent(options, customAgent),
+		engine:        browser,
+		httpclient:    httpclient,
+		options:       options,
+		instancesCond: sync.NewCond(&sync.Mutex{}),
+	}
+	if options.HeadlessMaxPages > 0 {
+		engine.pages = make(chan struct{}, options.HeadlessMaxPages)
+	}
+	engine.previousPIDs = previousPIDs
+	return engine, nil
+}
+
+// getCustomAgent returns the user agent from the custom headers of options
+func getCustomAgent(options *types.Options) string {
+	customAgent := ""
+	for _, option := range options.CustomHeaders {
+		parts := strings.SplitN(option, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if strings.EqualFold(parts[0], "User-Agent") {
+			customAgent = parts[1]
+		}
+	}
+	return customAgent
+}
+
+// rotateUserAgent returns true if the user agent is rotated for each page.
+// User agents specified in the custom headers take precedence over the rotation.
+func rotateUserAgent(options *types.Options, customAgent string) bool {
+	return (options.RotateUserAgent || len(options.UserAgents) > 0) && customAgent == ""
+}
+
+// pageUserAgent returns the user agent of a new page
+func (b *Browser) pageUserAgent() string {
+	if b.rotateAgent {
+		return uarand.GetRandom()
+	}
+	return b.customAgent
+}
+
+// launchBrowser launches a new chrome process using dataStore as user data
+// directory and returns a browser connected to it.
+func launchBrowser(options *types.Options, dataStore string) (*rod.Browser, error) {
 	chromeLauncher := launcher.New().
 		Leakless(false).
 		Set("disable-gpu", "true").
@@ -84,31 +165,7 @@ func New(options *types.Options) (*Browser, error) {
 	if browserErr := browser.Connect(); browserErr != nil {
 		return nil, browserErr
 	}
-	customAgent := ""
-	for _, option := range options.CustomHeaders {
-		parts := strings.SplitN(option, ":", 2)
-		if len(parts) != 2 {
-			continue
-		}
-		if strings.EqualFold(parts[0], "User-Agent") {
-			customAgent = parts[1]
-		}
-	}
-
-	httpclient, err := newHttpClient(options)
-	if err != nil {
-		return nil, err
-	}
-
-	engine := &Browser{
-		tempDir:     dataStore,
-		customAgent: customAgent,
-		engine:      browser,
-		httpclient:  httpclient,
-		options:     options,
-	}
-	engine.previousPIDs = previousPIDs
-	return engine, nil
+	return browser, nil
 }
 
 // MustDisableSandbox determines if the current os and user needs sandbox mode disabled
@@ -130,6 +187,9 @@ func (b *Browser) UserAgent() string {
 
 // Close closes the browser engine
 func (b *Browser) Close() {
+	if b.engine == nil {
+		return
+	}
 	b.engine.Close()
 	os.RemoveAll(b.tempDir)
 	processutil.CloseProcesses(processutil.IsChromeProcess, b.previousPIDs)
@@ -0,0 +1,30 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/corpix/uarand"
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+func TestPageUserAgent(t *testing.T) {
+	previous := uarand.Default
+	uarand.Default = uarand.NewWithCustomList([]string{"rotated-agent/1.0"})
+	defer func() {
+		uarand.Default = previous
+	}()
+
+	options := &types.Options{RotateUserAgent: true}
+	browser := &Browser{customAgent: getCustomAgent(options), rotateAgent: rotateUserAgent(options, getCustomAgent(options))}
+	require.Equal(t, "rotated-agent/1.0", browser.pageUserAgent(), "could not rotate user agent")
+
+	options = &types.Options{UserAgents: []string{"rotated-agent/1.0"}, CustomHeaders: []string{"User-Agent:explicit-agent"}}
+	browser = &Browser{customAgent: getCustomAgent(options), rotateAgent: rotateUserAgent(options, getCustomAgent(options))}
+	require.Equal(t, "explicit-agent", browser.pageUserAgent(), "could not get custom header user agent")
+
+	options = &types.Options{}
+	browser = &Browser{customAgent: getCustomAgent(options), rotateAgent: rotateUserAgent(options, getCustomAgent(options))}
+	require.Empty(t, browser.pageUserAgent(), "got user agent without rotation")
+}
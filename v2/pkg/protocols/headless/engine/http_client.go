@@ -47,7 +47,9 @@ func newHttpClient(options *types.Options) (*http.Client, error) {
 		MaxConnsPerHost:     500,
 		TLSClientConfig:     tlsConfig,
 	}
-	if types.ProxyURL != "" {
+	if types.ProxyPool != nil {
+		types.ProxyPool.SetTransport(transport)
+	} else if types.ProxyURL != "" {
 		if proxyURL, err := url.Parse(types.ProxyURL); err == nil {
 			transport.Proxy = http.ProxyURL(proxyURL)
 		}
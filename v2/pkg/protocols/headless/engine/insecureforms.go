@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"net/url"
+	"strings"
+)
+
+// pageForm is a form submission target of a page as returned by insecureFormsJS
+type pageForm struct {
+	// ID is the id attribute of the form
+	ID string `json:"id"`
+	// Name is the name attribute of the form
+	Name string `json:"name"`
+	// Action is the action attribute of the form, or the formaction of its submitter
+	Action string `json:"action"`
+	// Method is the method of the submission
+	Method string `json:"method"`
+	// Password is true if the form has a password input
+	Password bool `json:"password"`
+	// Submitter is true if the target is the formaction of a submit button
+	Submitter bool `json:"submitter"`
+}
+
+// insecureForm is a form submitting insecurely or to another origin
+type insecureForm struct {
+	// ID is the id attribute of the form
+	ID string `json:"id"`
+	// Name is the name attribute of the form
+	Name string `json:"name,omitempty"`
+	// Action is the resolved url the form submits to
+	Action string `json:"action"`
+	// Method is the method of the submission
+	Method string `json:"method"`
+	// Password is true if the form has a password input
+	Password bool `json:"password"`
+	// Submitter is true if the target is the formaction of a submit button
+	Submitter bool `json:"submitter,omitempty"`
+	// Insecure is true if the form submits over http from a https page
+	Insecure bool `json:"insecure"`
+	// CrossOrigin is true if the form submits to another origin
+	CrossOrigin bool `json:"cross_origin"`
+	// ThirdParty is true if the form submits to another registrable domain
+	ThirdParty bool `json:"third_party"`
+}
+
+// insecureForms returns the forms of the page at pageURL submitting over http
+// from a https page or to another origin. Actions are resolved against baseURI,
+// the base url of the document, empty actions submitting to the page itself,
+// and the non http targets, such as javascript: urls, are ignored.
+func insecureForms(pageURL, baseURI string, forms []pageForm) []insecureForm {
+	page, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	base := page
+	if parsed, err := url.Parse(baseURI); err == nil && baseURI != "" {
+		base = parsed
+	}
+	pageOrigin := urlOrigin(page)
+	pageDomain := registrableDomain(pageURL)
+
+	results := []insecureForm{}
+	for _, form := range forms {
+		action := strings.TrimSpace(form.Action)
+		var resolved string
+		if action == "" {
+			resolved = pageURL
+		} else {
+			normalized, ok := normalizeEndpoint(base, action)
+			if !ok {
+				continue
+			}
+			resolved = normalized
+		}
+		target, err := url.Parse(resolved)
+		if err != nil {
+			continue
+		}
+		result := insecureForm{
+			ID:          form.ID,
+			Name:        form.Name,
+			Action:      resolved,
+			Method:      strings.ToUpper(form.Method),
+			Password:    form.Password,
+			Submitter:   form.Submitter,
+			Insecure:    strings.EqualFold(page.Scheme, "https") && strings.EqualFold(target.Scheme, "http"),
+			CrossOrigin: urlOrigin(target) != pageOrigin,
+			ThirdParty:  registrableDomain(resolved) != pageDomain,
+		}
+		if result.Method == "" {
+			result.Method = "GET"
+		}
+		if result.Insecure || result.CrossOrigin {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// urlOrigin returns the origin of an url, with the default port of its scheme
+func urlOrigin(u *url.URL) string {
+	scheme := strings.ToLower(u.Scheme)
+	port := u.Port()
+	if port == "" {
+		switch scheme {
+		case "https":
+			port = "443"
+		case "http":
+			port = "80"
+		}
+	}
+	return scheme + "://" + strings.ToLower(u.Hostname()) + ":" + port
+}
@@ -0,0 +1,31 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsecureForms(t *testing.T) {
+	forms := []pageForm{
+		{ID: "search", Action: "/search", Method: "get"},
+		{ID: "self", Action: "", Method: "post", Password: true},
+		{ID: "login", Action: "http://example.com/login", Method: "post", Password: true},
+		{ID: "sso", Action: "https://auth.example.com/login", Method: "post"},
+		{ID: "leak", Name: "newsletter", Action: "//collector.example.net/submit"},
+		{ID: "port", Action: "https://example.com:8443/login", Method: "post"},
+		{ID: "script", Action: "javascript:void(0)"},
+		{ID: "login", Action: "http://example.com/register", Method: "post", Submitter: true},
+	}
+	results := insecureForms("https://example.com/account/", "https://example.com/app/", forms)
+	require.Equal(t, []insecureForm{
+		{ID: "login", Action: "http://example.com/login", Method: "POST", Password: true, Insecure: true, CrossOrigin: true},
+		{ID: "sso", Action: "https://auth.example.com/login", Method: "POST", CrossOrigin: true},
+		{ID: "leak", Name: "newsletter", Action: "https://collector.example.net/submit", Method: "GET", CrossOrigin: true, ThirdParty: true},
+		{ID: "port", Action: "https://example.com:8443/login", Method: "POST", CrossOrigin: true},
+		{ID: "login", Action: "http://example.com/register", Method: "POST", Submitter: true, Insecure: true, CrossOrigin: true},
+	}, results, "wrong insecure forms")
+
+	results = insecureForms("http://example.com/", "", []pageForm{{Action: "http://example.com:80/login"}, {Action: "search"}})
+	require.Empty(t, results, "same origin forms of a http page flagged")
+}
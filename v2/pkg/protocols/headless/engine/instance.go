@@ -3,20 +3,33 @@ package engine
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 	"github.com/go-rod/rod/lib/utils"
+	"github.com/projectdiscovery/interactsh/pkg/server"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/interactsh"
 )
 
 // Instance is an isolated browser instance opened for doing operations with it.
 type Instance struct {
-	browser *Browser
-	engine  *rod.Browser
+	browser   *Browser
+	engine    *rod.Browser
+	closeOnce sync.Once
+	// maxBodySize is the maximum size of response bodies recorded in page history
+	maxBodySize int
 
 	// redundant due to dependency cycle
-	interactsh *interactsh.Client
+	interactsh interactshClient
+}
+
+// interactshClient generates the interactsh urls of the pages and
+// returns the interactions received for them
+type interactshClient interface {
+	NewURLWithData(data string) (string, error)
+	Interactions(interactshURL string) []*server.Interaction
 }
 
 // NewInstance creates a new instance for the current browser.
@@ -27,8 +40,16 @@ type Instance struct {
 // Users can also choose to run the login->actions process again
 // which uses a new incognito browser instance to run actions.
 func (b *Browser) NewInstance() (*Instance, error) {
+	// pages are created from recordings without a browser when replaying
+	if b.engine == nil {
+		return &Instance{browser: b}, nil
+	}
+	if err := b.acquireInstance(); err != nil {
+		return nil, err
+	}
 	browser, err := b.engine.Incognito()
 	if err != nil {
+		b.releaseInstance()
 		return nil, err
 	}
 
@@ -40,12 +61,38 @@ func (b *Browser) NewInstance() (*Instance, error) {
 
 // Close closes all the tabs and pages for a browser instance
 func (i *Instance) Close() error {
-	return i.engine.Close()
+	var err error
+	i.closeOnce.Do(func() {
+		if i.engine == nil {
+			return
+		}
+		err = i.engine.Close()
+		i.browser.releaseInstance()
+	})
+	return err
+}
+
+// ClearCookies clears the cookies of the isolated browser instance, so that
+// it can be reused without carrying the sessions of its previous pages
+func (i *Instance) ClearCookies() error {
+	if i.engine == nil {
+		return nil
+	}
+	return proto.StorageClearCookies{BrowserContextID: i.engine.BrowserContextID}.Call(i.engine)
 }
 
 // SetInteractsh client
 func (i *Instance) SetInteractsh(interactsh *interactsh.Client) {
-	i.interactsh = interactsh
+	// a nil client must leave interactsh disabled instead of a typed nil
+	i.interactsh = nil
+	if interactsh != nil {
+		i.interactsh = interactsh
+	}
+}
+
+// SetMaxBodySize sets the maximum size of response bodies recorded in page history
+func (i *Instance) SetMaxBodySize(size int) {
+	i.maxBodySize = size
 }
 
 // maxBackoffSleeper is a backoff sleeper respecting max backoff values
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -8,6 +9,11 @@ import (
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/interactsh/pkg/server"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/utils"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"golang.org/x/net/publicsuffix"
 )
 
 // Page is a single page in an isolated browser instance
@@ -21,37 +27,135 @@ type Page struct {
 	History        []HistoryData
 	InteractshURLs []string
 	payloads       map[string]interface{}
+	// interactshMarkers maps interactsh markers to the urls generated for this page
+	interactshMarkers map[string]string
+	// Console contains the console messages of the page when recording or replaying
+	Console []ConsoleMessage
+	// SetCookies contains the cookies set by the responses received by the page
+	SetCookies []SetCookie
+	// WebSocketFrames contains the websocket frames recorded by the websocket actions
+	WebSocketFrames []WebSocketFrame
+	// recorder records the page events when headless recording is enabled
+	recorder *recorder
+	// replayedBody is the html of a page replayed from a recording
+	replayedBody string
+	// replayedURL is the final url of a page replayed from a recording
+	replayedURL string
+	// referrer is the referrer set for the next navigation of the page
+	referrer *pendingReferrer
+	// userAgent is the user agent of the page, empty for the browser default
+	userAgent string
+	// responseIndex is the index of the history entry following the last response matched by waitresponse
+	responseIndex int
+	// seenInteractions are the interactsh interactions returned by waitinteraction
+	seenInteractions map[*server.Interaction]struct{}
+	// newDocumentScripts maps the names of evalonnewdocument actions to the removal of their scripts
+	newDocumentScripts map[string]func() error
+	// corsChecks are the origins set by setorigin actions and the cors headers received
+	corsChecks []*corsCheck
+	// FailedRequests contains the requests of the page which failed to load once captured
+	FailedRequests []FailedRequest
+	// capturingFailures is true once the failed requests of the page are captured
+	capturingFailures bool
+	// mainPage is the page created for the run, page being the popup switched to if any
+	mainPage *rod.Page
+	// Popups contains the pages opened by the page or its popups during the run
+	Popups []*Popup
+	// stopTrackingPopups stops tracking the popups opened by the page
+	stopTrackingPopups func()
+	// closeOnce releases the page of the browser only once
+	closeOnce *sync.Once
+}
+
+// pendingReferrer is a referrer set by the setreferrer action
+type pendingReferrer struct {
+	// Referrer is the value of the referrer
+	Referrer string
+	// Policy is the referrer policy used for the navigation
+	Policy proto.PageReferrerPolicy
+	// URL is the url of the navigation the referrer is used for
+	URL string
 }
 
 // HistoryData contains the page request/response pairs
 type HistoryData struct {
 	RawRequest  string
 	RawResponse string
+	// Method is the method of the request
+	Method string
+	// URL is the url of the request
+	URL string
+	// ResourceType is the type of the resource requested (Document, XHR, Fetch, etc)
+	ResourceType proto.NetworkResourceType
+	// RequestBody is the request body, truncated to the configured maximum size
+	RequestBody string
+	// Referrer is the value of the Referer header sent with the request
+	Referrer string
+	// StatusCode is the status code of the response
+	StatusCode int
+	// ResponseBody is the body of the response
+	ResponseBody string
+	// ResponseHeaders are the headers of the response
+	ResponseHeaders http.Header
+	// Truncated is true if the response body was truncated to the maximum body size
+	Truncated bool
+}
+
+// outgoingResourceTypes are the resource types initiated by page scripts
+var outgoingResourceTypes = map[proto.NetworkResourceType]struct{}{
+	proto.NetworkResourceTypeXHR:   {},
+	proto.NetworkResourceTypeFetch: {},
+	proto.NetworkResourceTypePing:  {},
 }
 
 // Run runs a list of actions by creating a new page in the browser.
 func (i *Instance) Run(baseURL *url.URL, actions []*Action, payloads map[string]interface{}, timeout time.Duration) (map[string]string, *Page, error) {
+	if i.browser.options.HeadlessReplay != "" {
+		return i.replay(baseURL, actions, payloads)
+	}
+
+	i.browser.acquirePage()
 	page, err := i.engine.Page(proto.TargetCreateTarget{})
 	if err != nil {
+		i.browser.releasePage()
 		return nil, nil, err
 	}
 	page = page.Timeout(timeout)
 
-	if i.browser.customAgent != "" {
-		if userAgentErr := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{UserAgent: i.browser.customAgent}); userAgentErr != nil {
-			return nil, nil, userAgentErr
-		}
+	createdPage := &Page{page: page, mainPage: page, instance: i, mutex: &sync.RWMutex{}, closeOnce: &sync.Once{}, payloads: payloads, interactshMarkers: make(map[string]string)}
+	if i.browser.options.HeadlessRecord != "" {
+		createdPage.startRecording()
+	}
+	data, err := createdPage.run(baseURL, actions)
+	if createdPage.recorder != nil {
+		createdPage.saveRecording(baseURL, actions, data, err)
 	}
+	if err != nil {
+		createdPage.Close()
+		return nil, nil, err
+	}
+	return data, createdPage, nil
+}
+
+// run prepares the page and executes the actions on it
+func (p *Page) run(baseURL *url.URL, actions []*Action) (map[string]string, error) {
+	page := p.page
 
-	createdPage := &Page{page: page, instance: i, mutex: &sync.RWMutex{}, payloads: payloads}
+	p.userAgent = p.instance.browser.pageUserAgent()
+	if p.userAgent != "" {
+		if userAgentErr := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{UserAgent: p.userAgent}); userAgentErr != nil {
+			return nil, userAgentErr
+		}
+	}
 
-	// in case the page has request/response modification rules - enable global hijacking
-	if createdPage.hasModificationRules() || containsModificationActions(actions...) {
+	// in case the page has request/response modification rules - enable global hijacking.
+	// requests are also hijacked with a proxy pool so that they are sent through the pool.
+	if p.hasModificationRules() || containsModificationActions(actions...) || types.ProxyPool != nil {
 		hijackRouter := page.HijackRequests()
-		if err := hijackRouter.Add("*", "", createdPage.routingRuleHandler); err != nil {
-			return nil, nil, err
+		if err := hijackRouter.Add("*", "", p.routingRuleHandler); err != nil {
+			return nil, err
 		}
-		createdPage.hijackRouter = hijackRouter
+		p.hijackRouter = hijackRouter
 		go hijackRouter.Run()
 	} else {
 		hijackRouter := NewHijack(page)
@@ -59,41 +163,46 @@ func (i *Instance) Run(baseURL *url.URL, actions []*Action, payloads map[string]
 			URLPattern:   "*",
 			RequestStage: proto.FetchRequestStageResponse,
 		})
-		createdPage.hijackNative = hijackRouter
-		hijackRouterHandler := hijackRouter.Start(createdPage.routingRuleHandlerNative)
+		p.hijackNative = hijackRouter
+		hijackRouterHandler := hijackRouter.Start(p.routingRuleHandlerNative(page, nil))
 		go func() {
 			_ = hijackRouterHandler()
 		}()
 	}
+	p.trackPopups()
 
 	if err := page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{Viewport: &proto.PageViewport{
 		Scale:  1,
 		Width:  float64(1920),
 		Height: float64(1080),
 	}}); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	if _, err := page.SetExtraHeaders([]string{"Accept-Language", "en, en-GB, en-us;"}); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	data, err := createdPage.ExecuteActions(baseURL, actions)
-	if err != nil {
-		return nil, nil, err
-	}
-	return data, createdPage, nil
+	return p.ExecuteActions(baseURL, actions)
 }
 
 // Close closes a browser page
 func (p *Page) Close() {
-	if p.hijackRouter != nil {
-		_ = p.hijackRouter.Stop()
-	}
-	if p.hijackNative != nil {
-		_ = p.hijackNative.Stop()
+	// replayed pages don't have a browser page
+	if p.page == nil {
+		return
 	}
-	p.page.Close()
+	p.closeOnce.Do(func() {
+		if p.hijackRouter != nil {
+			_ = p.hijackRouter.Stop()
+		}
+		if p.hijackNative != nil {
+			_ = p.hijackNative.Stop()
+		}
+		p.closePopups()
+		p.mainPage.Close()
+		p.instance.browser.releasePage()
+	})
 }
 
 // Page returns the current page for the actions
@@ -106,8 +215,29 @@ func (p *Page) Browser() *rod.Browser {
 	return p.instance.engine
 }
 
+// HTML returns the html of the current page
+func (p *Page) HTML() string {
+	if p.page == nil {
+		return p.replayedBody
+	}
+	html, err := p.page.Element("html")
+	if err != nil {
+		return ""
+	}
+	body, _ := html.HTML()
+	return body
+}
+
+// UserAgent returns the user agent set for the page
+func (p *Page) UserAgent() string {
+	return p.userAgent
+}
+
 // URL returns the URL for the current page.
 func (p *Page) URL() string {
+	if p.page == nil {
+		return p.replayedURL
+	}
 	info, err := p.page.Info()
 	if err != nil {
 		return ""
@@ -128,6 +258,179 @@ func (p *Page) DumpHistory() string {
 	return historyDump.String()
 }
 
+// DumpOutgoingRequests returns the method, url and body of the
+// fetch/XHR/beacon requests initiated by the page.
+func (p *Page) DumpOutgoingRequests() string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	var outgoingDump strings.Builder
+	for _, historyData := range p.History {
+		if _, ok := outgoingResourceTypes[historyData.ResourceType]; !ok {
+			continue
+		}
+		outgoingDump.WriteString(historyData.Method + " " + historyData.URL + "\n")
+		if historyData.RequestBody != "" {
+			outgoingDump.WriteString(historyData.RequestBody + "\n")
+		}
+	}
+	return outgoingDump.String()
+}
+
+// DumpReferrers returns the url and the Referer header
+// of the requests made by the page with a referrer.
+func (p *Page) DumpReferrers() string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	var referrersDump strings.Builder
+	for _, historyData := range p.History {
+		if historyData.Referrer == "" {
+			continue
+		}
+		referrersDump.WriteString(historyData.URL + " " + historyData.Referrer + "\n")
+	}
+	return referrersDump.String()
+}
+
+// DocumentHeaders returns the response headers of the last document
+// loaded by the page, or nil if no document was loaded.
+func (p *Page) DocumentHeaders() http.Header {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for i := len(p.History) - 1; i >= 0; i-- {
+		if p.History[i].ResourceType == proto.NetworkResourceTypeDocument {
+			return p.History[i].ResponseHeaders
+		}
+	}
+	return nil
+}
+
+// ResourceCounts returns the number of resources loaded by the page, by resource
+// type and by third-party domain.
+//
+// Resources are third-party if their registrable domain differs from the one of
+// the first document loaded by the page. Counts are returned as resources,
+// resources_<type>, third_party_resources, third_party_resources_<type> and
+// third_party_domains, with types in lowercase (script, image, xhr, etc).
+func (p *Page) ResourceCounts() map[string]interface{} {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	var firstParty string
+	for _, historyData := range p.History {
+		if historyData.ResourceType == proto.NetworkResourceTypeDocument {
+			firstParty = registrableDomain(historyData.URL)
+			break
+		}
+	}
+
+	counts := map[string]interface{}{"resources": 0, "third_party_resources": 0, "third_party_domains": 0}
+	increment := func(key string) {
+		count, _ := counts[key].(int)
+		counts[key] = count + 1
+	}
+	thirdPartyDomains := make(map[string]struct{})
+	for _, historyData := range p.History {
+		resourceType := strings.ToLower(string(historyData.ResourceType))
+		if resourceType == "" {
+			resourceType = "other"
+		}
+		increment("resources")
+		increment("resources_" + resourceType)
+
+		domain := registrableDomain(historyData.URL)
+		if firstParty == "" || domain == "" || domain == firstParty {
+			continue
+		}
+		increment("third_party_resources")
+		increment("third_party_resources_" + resourceType)
+		thirdPartyDomains[domain] = struct{}{}
+	}
+	counts["third_party_domains"] = len(thirdPartyDomains)
+	return counts
+}
+
+// registrableDomain returns the registrable domain of the host of an url,
+// or the host itself if it has no public suffix (ip addresses, localhost).
+func registrableDomain(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return domain
+	}
+	return host
+}
+
+// takeReferrer returns the referrer set for a navigation request to
+// target, clearing it so that it is only used for a single navigation.
+func (p *Page) takeReferrer(target string) string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.referrer == nil || p.referrer.URL != target {
+		return ""
+	}
+	referrer := p.referrer.Referrer
+	p.referrer = nil
+	return referrer
+}
+
+// truncateRequestBody truncates a request body to the configured maximum size
+func (p *Page) truncateRequestBody(body string) string {
+	if maxSize := p.instance.browser.options.HeadlessRequestBodySize; maxSize > 0 && len(body) > maxSize {
+		return body[:maxSize]
+	}
+	return body
+}
+
+// transcodeResponseBody transcodes a response body in a legacy charset, declared
+// by its content type or its meta charset, to utf-8 if responses are transcoded.
+func (p *Page) transcodeResponseBody(body, contentType string) string {
+	if !p.instance.browser.options.TranscodeResponse {
+		return body
+	}
+	transcoded, _, err := utils.TranscodeToUTF8(contentType, []byte(body))
+	if err != nil {
+		return body
+	}
+	return string(transcoded)
+}
+
+// truncateResponseBody truncates a response body to the maximum body size,
+// returning true if it was truncated.
+func (p *Page) truncateResponseBody(body string) (string, bool) {
+	if maxSize := p.instance.maxBodySize; maxSize > 0 && len(body) > maxSize {
+		return body[:maxSize], true
+	}
+	return body, false
+}
+
+// Truncated returns true if a response body of the page history was truncated
+func (p *Page) Truncated() bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for _, historyData := range p.History {
+		if historyData.Truncated {
+			return true
+		}
+	}
+	return false
+}
+
+// addConsoleMessage adds a message to the page console messages
+func (p *Page) addConsoleMessage(message ConsoleMessage) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.Console = append(p.Console, message)
+}
+
 // addToHistory adds a request/response pair to the page history
 func (p *Page) addToHistory(historyData ...HistoryData) {
 	p.mutex.Lock()
@@ -136,11 +439,32 @@ func (p *Page) addToHistory(historyData ...HistoryData) {
 	p.History = append(p.History, historyData...)
 }
 
-func (p *Page) addInteractshURL(URLs ...string) {
+// replaceInteractshURLs replaces the interactsh markers in data with urls.
+//
+// A marker resolves to the same url for the lifetime of the page, so
+// it can be referenced across actions (e.g. navigate and a later script).
+// Generated urls are recorded before returning for interaction correlation.
+func (p *Page) replaceInteractshURLs(data string) string {
+	if p.instance.interactsh == nil {
+		return data
+	}
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	p.InteractshURLs = append(p.InteractshURLs, URLs...)
+	for _, marker := range interactsh.GetMarkers(data) {
+		interactshURL, ok := p.interactshMarkers[marker]
+		if !ok {
+			generated, err := p.instance.interactsh.NewURLWithData(marker)
+			if err != nil {
+				continue
+			}
+			interactshURL = generated
+			p.interactshMarkers[marker] = interactshURL
+			p.InteractshURLs = append(p.InteractshURLs, interactshURL)
+		}
+		data = strings.Replace(data, marker, interactshURL, 1)
+	}
+	return data
 }
 
 func (p *Page) hasModificationRules() bool {
@@ -174,6 +498,12 @@ func containsAnyModificationActionType(actionTypes ...ActionType) bool {
 			return true
 		case ActionSetBody:
 			return true
+		case ActionSetReferrer:
+			return true
+		case ActionMockResponse:
+			return true
+		case ActionSetOrigin:
+			return true
 		}
 	}
 	return false
@@ -1,12 +1,25 @@
 package engine
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"image"
+	_ "image/png"
+	"io"
 	"net"
+	"net/http"
+	"net/http/httputil"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,10 +31,12 @@ import (
 	"github.com/go-rod/rod/lib/utils"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/interactsh/pkg/server"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/generators"
 	errorutil "github.com/projectdiscovery/utils/errors"
 	fileutil "github.com/projectdiscovery/utils/file"
 	folderutil "github.com/projectdiscovery/utils/folder"
+	sliceutil "github.com/projectdiscovery/utils/slice"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 	"github.com/segmentio/ksuid"
 )
@@ -80,6 +95,12 @@ func (p *Page) ExecuteActions(baseURL *url.URL, actions []*Action) (map[string]s
 			err = p.ActionSetBody(act, outData)
 		case ActionSetMethod:
 			err = p.ActionSetMethod(act, outData)
+		case ActionMockResponse:
+			err = p.ActionMockResponse(act, outData)
+		case ActionServiceWorkers:
+			err = p.ServiceWorkers(act, outData)
+		case ActionUnload:
+			err = p.Unload(act, outData, baseURL)
 		case ActionKeyboard:
 			err = p.KeyboardAction(act, outData)
 		case ActionDebug:
@@ -88,6 +109,86 @@ func (p *Page) ExecuteActions(baseURL *url.URL, actions []*Action) (map[string]s
 			err = p.SleepAction(act, outData)
 		case ActionWaitVisible:
 			err = p.WaitVisible(act, outData)
+		case ActionGetElementInfo:
+			err = p.GetElementInfo(act, outData)
+		case ActionComputedStyle:
+			err = p.ComputedStyle(act, outData)
+		case ActionEvalOnNewDocument:
+			err = p.EvalOnNewDocument(act, outData)
+		case ActionMixedContent:
+			err = p.MixedContent(act, outData)
+		case ActionDOMHash:
+			err = p.DOMHash(act, outData)
+		case ActionSetOrigin:
+			err = p.SetOrigin(act, outData)
+		case ActionSwitchTab:
+			err = p.SwitchTab(act, outData)
+		case ActionFillForm:
+			err = p.FillForm(act, outData)
+		case ActionRedirects:
+			err = p.Redirects(act, outData)
+		case ActionAccessibilityTree:
+			err = p.AccessibilityTree(act, outData)
+		case ActionDOMXSS:
+			err = p.DOMXSS(act, outData, baseURL)
+		case ActionWaitInteraction:
+			err = p.WaitInteraction(act, outData)
+		case ActionCSPNonce:
+			err = p.CSPNonce(act, outData)
+		case ActionWebVitals:
+			err = p.WebVitals(act, outData, baseURL)
+		case ActionMetadata:
+			err = p.Metadata(act, outData)
+		case ActionClickjacking:
+			err = p.Clickjacking(act, outData, baseURL)
+		case ActionEventHandlers:
+			err = p.EventHandlers(act, outData)
+		case ActionWASM:
+			err = p.WASM(act, outData, baseURL)
+		case ActionTrackers:
+			err = p.Trackers(act, outData)
+		case ActionStorage:
+			err = p.Storage(act, outData)
+		case ActionWebSocket:
+			err = p.WebSocket(act, outData, baseURL)
+		case ActionCORSProbe:
+			err = p.CORSProbe(act, outData, baseURL)
+		case ActionPrototypePollution:
+			err = p.PrototypePollution(act, outData, baseURL)
+		case ActionPermissionsPolicy:
+			err = p.PermissionsPolicy(act, outData)
+		case ActionSourceMaps:
+			err = p.SourceMaps(act, outData)
+		case ActionPrefilledFields:
+			err = p.PrefilledFields(act, outData)
+		case ActionInsecureForms:
+			err = p.InsecureForms(act, outData)
+		case ActionDOMClobbering:
+			err = p.DOMClobbering(act, outData, baseURL)
+		case ActionOpenRedirect:
+			err = p.OpenRedirect(act, outData, baseURL)
+		case ActionScreenshotDiff:
+			err = p.ScreenshotDiff(act, outData)
+		case ActionExtractLinks:
+			err = p.ExtractLinks(act, outData)
+		case ActionExtractForms:
+			err = p.ExtractForms(act, outData)
+		case ActionExportSession:
+			err = p.ExportSession(act, outData, baseURL)
+		case ActionWaitResponse:
+			err = p.WaitResponse(act, outData)
+		case ActionSetReferrer:
+			err = p.SetReferrer(act, outData)
+		case ActionImportSession:
+			err = p.ImportSession(act, outData, baseURL)
+		case ActionPaste:
+			err = p.PasteElement(act, outData)
+		case ActionJSEndpoints:
+			err = p.ExtractJSEndpoints(act, outData)
+		case ActionScreenshotElement:
+			err = p.ScreenshotElement(act, outData)
+		case ActionSetCookie:
+			err = p.SetCookie(act, outData, baseURL)
 		default:
 			continue
 		}
@@ -95,6 +196,7 @@ func (p *Page) ExecuteActions(baseURL *url.URL, actions []*Action) (map[string]s
 			return nil, errors.Wrap(err, "error occurred executing action")
 		}
 	}
+	p.writeCORSOutputs(outData)
 	return outData, nil
 }
 
@@ -103,6 +205,8 @@ type rule struct {
 	Action ActionType
 	Part   string
 	Args   map[string]string
+	// URL is the pattern of the request urls the rule applies to
+	URL *regexp.Regexp
 }
 
 // WaitVisible waits until an element appears.
@@ -232,6 +336,53 @@ func (p *Page) ActionSetMethod(act *Action, out map[string]string) error {
 	return nil
 }
 
+// ActionMockResponse executes a MockResponse action fulfilling the requests
+// matching the url pattern with the specified response instead of forwarding them.
+func (p *Page) ActionMockResponse(act *Action, out map[string]string) error {
+	pattern := p.getActionArgWithDefaultValues(act, "url")
+	if pattern == "" {
+		return errinvalidArguments
+	}
+	urlRegex, err := regexp.Compile(pattern)
+	if err != nil {
+		return errors.Wrap(err, "could not compile url pattern")
+	}
+
+	args := make(map[string]string)
+	args["method"] = p.getActionArgWithDefaultValues(act, "method")
+	args["status"] = p.getActionArgWithDefaultValues(act, "status")
+	args["headers"] = p.getActionArgWithDefaultValues(act, "headers")
+	args["body"] = p.getActionArgWithDefaultValues(act, "body")
+	if args["status"] != "" {
+		if status, err := strconv.Atoi(args["status"]); err != nil || status < 100 || status > 599 {
+			return fmt.Errorf("invalid status code %s", args["status"])
+		}
+	}
+	if _, err := parseMockHeaders(args["headers"]); err != nil {
+		return err
+	}
+	p.rules = append(p.rules, rule{Action: ActionMockResponse, Part: "response", Args: args, URL: urlRegex})
+	return nil
+}
+
+// parseMockHeaders parses the newline separated headers of a mocked response
+// returning them as name and value pairs.
+func parseMockHeaders(headers string) ([]string, error) {
+	var pairs []string
+	for _, line := range strings.Split(headers, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %s", line)
+		}
+		pairs = append(pairs, strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return pairs, nil
+}
+
 // NavigateURL executes an ActionLoadURL actions loading a URL for the page.
 func (p *Page) NavigateURL(action *Action, out map[string]string, parsed *url.URL /*TODO review unused parameter*/) error {
 	URL := p.getActionArgWithDefaultValues(action, "url")
@@ -239,23 +390,590 @@ func (p *Page) NavigateURL(action *Action, out map[string]string, parsed *url.UR
 		return errinvalidArguments
 	}
 
-	// Handle the dynamic value substitution here.
+	final := navigationURL(URL, parsed)
+	if referrer := p.navigationReferrer(final); referrer != nil {
+		_ = p.page.StopLoading()
+		res, err := proto.PageNavigate{URL: final, Referrer: referrer.Referrer, ReferrerPolicy: referrer.Policy}.Call(p.page)
+		if err != nil {
+			return errors.Wrap(err, "could not navigate")
+		}
+		if res.ErrorText != "" {
+			return errors.Wrap(&rod.ErrNavigation{Reason: res.ErrorText}, "could not navigate")
+		}
+		return nil
+	}
+	if err := p.page.Navigate(final); err != nil {
+		return errors.Wrap(err, "could not navigate")
+	}
+	return nil
+}
+
+// navigationURL returns the url of a navigation with the dynamic values replaced
+func navigationURL(URL string, parsed *url.URL) string {
 	URL, parsed = baseURLWithTemplatePrefs(URL, parsed)
 	if strings.HasSuffix(parsed.Path, "/") && strings.Contains(URL, "{{BaseURL}}/") {
 		parsed.Path = strings.TrimSuffix(parsed.Path, "/")
 	}
 	parsedString := parsed.String()
-	final := replaceWithValues(URL, map[string]interface{}{
+	return replaceWithValues(URL, map[string]interface{}{
 		"Hostname": parsed.Hostname(),
 		"BaseURL":  parsedString,
 	})
+}
 
-	if err := p.page.Navigate(final); err != nil {
-		return errors.Wrap(err, "could not navigate")
+// SetReferrer sets the referrer of the next navigation of the page
+func (p *Page) SetReferrer(act *Action, out map[string]string) error {
+	referrer := p.getActionArgWithDefaultValues(act, "referrer")
+	if referrer == "" {
+		return errinvalidArguments
+	}
+	policy := proto.PageReferrerPolicyUnsafeURL
+	if value := p.getActionArgWithDefaultValues(act, "policy"); value != "" {
+		policy = proto.PageReferrerPolicy(value)
+	}
+
+	p.mutex.Lock()
+	p.referrer = &pendingReferrer{Referrer: referrer, Policy: policy}
+	p.mutex.Unlock()
+	return nil
+}
+
+// cookieAttributes are the arguments of the setcookie action
+var cookieAttributes = []string{"name", "value", "url", "domain", "path", "secure", "httponly", "samesite", "expires"}
+
+// SetCookie sets a cookie with the attributes provided in the action arguments.
+//
+// The cookie is scoped to the target url unless a url or domain is specified.
+func (p *Page) SetCookie(act *Action, out map[string]string, baseURL *url.URL) error {
+	args := make(map[string]string, len(cookieAttributes))
+	for _, attribute := range cookieAttributes {
+		args[attribute] = p.getActionArgWithDefaultValues(act, attribute)
+	}
+	cookie, err := newSetCookie(args, baseURL, time.Now())
+	if err != nil {
+		return err
+	}
+	if warning := insecureCookieWarning(cookie, baseURL); warning != "" {
+		gologger.Warning().Msgf("Cookie %s %s\n", cookie.Name, warning)
+	}
+	if _, err := cookie.Call(p.page); err != nil {
+		return errors.Wrap(err, "could not set cookie")
+	}
+	return nil
+}
+
+// newSetCookie builds the cookie to set from the setcookie action arguments.
+func newSetCookie(args map[string]string, baseURL *url.URL, now time.Time) (*proto.NetworkSetCookie, error) {
+	if args["name"] == "" {
+		return nil, errinvalidArguments
+	}
+	cookie := &proto.NetworkSetCookie{
+		Name:     args["name"],
+		Value:    args["value"],
+		URL:      args["url"],
+		Domain:   args["domain"],
+		Path:     args["path"],
+		Secure:   args["secure"] == "true",
+		HTTPOnly: args["httponly"] == "true",
+	}
+	if cookie.URL == "" && cookie.Domain == "" {
+		if baseURL == nil {
+			return nil, errors.New("url or domain is required to set a cookie")
+		}
+		cookie.URL = baseURL.String()
+	}
+
+	switch strings.ToLower(args["samesite"]) {
+	case "":
+	case "strict":
+		cookie.SameSite = proto.NetworkCookieSameSiteStrict
+	case "lax":
+		cookie.SameSite = proto.NetworkCookieSameSiteLax
+	case "none":
+		cookie.SameSite = proto.NetworkCookieSameSiteNone
+	default:
+		return nil, fmt.Errorf("invalid samesite value %s", args["samesite"])
+	}
+
+	// expires is either a duration relative to now or a unix timestamp
+	if expires := args["expires"]; expires != "" {
+		if duration, err := time.ParseDuration(expires); err == nil {
+			cookie.Expires = proto.TimeSinceEpoch(now.Add(duration).Unix())
+		} else if timestamp, err := strconv.ParseInt(expires, 10, 64); err == nil {
+			cookie.Expires = proto.TimeSinceEpoch(timestamp)
+		} else {
+			return nil, fmt.Errorf("invalid expires value %s", expires)
+		}
+	}
+	return cookie, nil
+}
+
+// insecureCookieWarning returns a warning if the cookie will not be
+// sent to its origin, as secure cookies are only sent over https.
+func insecureCookieWarning(cookie *proto.NetworkSetCookie, baseURL *url.URL) string {
+	if cookie.SameSite == proto.NetworkCookieSameSiteNone && !cookie.Secure {
+		return "has samesite none without secure and will be rejected by the browser"
+	}
+	if !cookie.Secure {
+		return ""
+	}
+	origin := baseURL
+	if cookie.URL != "" {
+		parsed, err := url.Parse(cookie.URL)
+		if err != nil {
+			return ""
+		}
+		origin = parsed
+	}
+	if origin != nil && origin.Scheme != "https" {
+		return fmt.Sprintf("is secure but will not be sent to the non-https origin %s", origin.Host)
+	}
+	return ""
+}
+
+// SetOrigin sets the Origin header of the next request with a url matching
+// the url pattern, or of the next request if none is specified, to test the
+// cors policy of the target.
+//
+// Once the actions are executed, the Access-Control-Allow-Origin and
+// Access-Control-Allow-Credentials headers of the response are stored in
+// <name>_allow_origin and <name>_allow_credentials (name is cors by default),
+// along with <name>_reflected if the origin was allowed as is and
+// <name>_credentials if credentials were allowed. Requests blocked by cors,
+// such as the ones with a failed preflight, are reported in <name>_blocked
+// and <name>_error. The whole outcome is also stored as json in <name>.
+func (p *Page) SetOrigin(act *Action, out map[string]string) error {
+	origin := p.getActionArgWithDefaultValues(act, "origin")
+	if origin == "" {
+		return errinvalidArguments
+	}
+	check := &corsCheck{name: act.Name, origin: origin}
+	if check.name == "" {
+		check.name = "cors"
+	}
+	if pattern := p.getActionArgWithDefaultValues(act, "url"); pattern != "" {
+		urlRegex, err := regexp.Compile(pattern)
+		if err != nil {
+			return errors.Wrap(err, "could not compile url pattern")
+		}
+		check.url = urlRegex
+	}
+	p.captureFailedRequests()
+
+	p.mutex.Lock()
+	check.failedIndex = len(p.FailedRequests)
+	p.corsChecks = append(p.corsChecks, check)
+	p.mutex.Unlock()
+	return nil
+}
+
+// SwitchTab switches the following actions to a popup opened by the page, e.g. with
+// window.open, waiting for it to open if needed, and captures its url and content.
+// A positive index selects a popup in the order they were opened and 0 switches back
+// to the page, otherwise the last popup with an url matching the url pattern is used.
+func (p *Page) SwitchTab(act *Action, out map[string]string) error {
+	index := -1
+	if value := p.getActionArgWithDefaultValues(act, "index"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return errors.Errorf("invalid tab index %s", value)
+		}
+		index = parsed
+	}
+	var urlRegex *regexp.Regexp
+	if pattern := p.getActionArgWithDefaultValues(act, "url"); pattern != "" {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return errors.Wrap(err, "could not compile url pattern")
+		}
+		urlRegex = compiled
+	}
+	timeout, err := getTimeout(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	pollTime, err := getPollTime(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong polling time given")
+	}
+
+	var popup *Popup
+	if index != 0 {
+		deadline := time.Now().Add(timeout)
+		for {
+			p.mutex.RLock()
+			if selected := selectPopup(p.Popups, index, urlRegex); selected != nil && selected.page != nil {
+				popup = selected
+			}
+			p.mutex.RUnlock()
+			if popup != nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				return errors.New("no popup to switch to was opened")
+			}
+			time.Sleep(pollTime)
+		}
+		p.page = popup.page
+	} else {
+		p.page = p.mainPage
+	}
+	if err := p.page.WaitLoad(); err != nil {
+		return errors.Wrap(err, "could not wait for tab to load")
+	}
+	windowName, err := p.page.Eval("() => window.name")
+	if err != nil {
+		return errors.Wrap(err, "could not get window name")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "tab"
+	}
+	out[name] = p.URL()
+	out[name+"_html"] = p.HTML()
+	out[name+"_window_name"] = windowName.Value.Str()
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if popup != nil {
+		out[name+"_history"] = dumpPopupHistory(popup)
+	}
+	out[name+"_count"] = strconv.Itoa(len(p.Popups))
+	out[name+"_urls"] = popupURLs(p.Popups)
+	return nil
+}
+
+// redirectHop is a document requested by the navigation of the page
+type redirectHop struct {
+	URL string
+	// Server is true if the document was requested following a http redirect
+	Server bool
+}
+
+// OpenRedirect navigates to an url and detects whether the page is redirected
+// to the marker host, by the server or client-side once loaded, e.g. with
+// javascript or a meta refresh. The payload (https://<marker>/ by default) is
+// set in the comma separated params of the url, if given, otherwise the url
+// is expected to contain it.
+//
+// Whether the page was redirected to the marker host is stored in the output
+// as the name of the action (redirect by default), along with the destination
+// in <name>_url, the kind of redirect (server or client) in <name>_type, the
+// url of the page once done in <name>_final_url and the urls of the documents
+// requested since the navigation, one per line, in <name>_chain.
+func (p *Page) OpenRedirect(act *Action, out map[string]string, baseURL *url.URL) error {
+	marker := strings.ToLower(p.getActionArgWithDefaultValues(act, "marker"))
+	if marker == "" {
+		return errinvalidArguments
+	}
+	URL := p.getActionArgWithDefaultValues(act, "url")
+	if URL == "" {
+		URL = "{{BaseURL}}"
+	}
+	payload := p.getActionArgWithDefaultValues(act, "payload")
+	if payload == "" {
+		payload = "https://" + marker + "/"
+	}
+	target, err := injectRedirectPayload(navigationURL(URL, baseURL), p.getActionArgWithDefaultValues(act, "params"), payload)
+	if err != nil {
+		return err
+	}
+	timeout, err := getTimeout(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	pollTime, err := getPollTime(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong polling time given")
+	}
+
+	var chain []redirectHop
+	chainMutex := &sync.Mutex{}
+	page, cancel := p.page.WithCancel()
+	defer cancel()
+	frameID := proto.PageFrameID(p.page.TargetID)
+	wait := page.EachEvent(func(e *proto.NetworkRequestWillBeSent) {
+		if e.Type != proto.NetworkResourceTypeDocument || e.FrameID != frameID {
+			return
+		}
+		chainMutex.Lock()
+		chain = append(chain, redirectHop{URL: e.Request.URL, Server: e.RedirectResponse != nil})
+		chainMutex.Unlock()
+	})
+	go wait()
+
+	// redirects to the marker host usually fail to load, which is not an error here
+	navigationErr := p.page.Navigate(target)
+
+	var hop redirectHop
+	var found bool
+	deadline := time.Now().Add(timeout)
+	for {
+		chainMutex.Lock()
+		hop, found = findRedirect(chain, marker)
+		chainMutex.Unlock()
+		if found || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(pollTime)
+	}
+	if navigationErr != nil && !found {
+		return errors.Wrap(navigationErr, "could not navigate")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "redirect"
+	}
+	out[name] = strconv.FormatBool(found)
+	out[name+"_url"] = ""
+	out[name+"_type"] = ""
+	if found {
+		out[name+"_url"] = hop.URL
+		out[name+"_type"] = "client"
+		if hop.Server {
+			out[name+"_type"] = "server"
+		}
+	}
+	out[name+"_final_url"] = p.URL()
+
+	chainMutex.Lock()
+	defer chainMutex.Unlock()
+	urls := make([]string, 0, len(chain))
+	for _, item := range chain {
+		urls = append(urls, item.URL)
+	}
+	out[name+"_chain"] = strings.Join(urls, "\n")
+	return nil
+}
+
+// injectRedirectPayload sets the payload as the value of the comma separated query params of an url
+func injectRedirectPayload(target, params, payload string) (string, error) {
+	if params == "" {
+		return target, nil
+	}
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse url")
+	}
+	query := parsed.Query()
+	for _, param := range strings.Split(params, ",") {
+		if param = strings.TrimSpace(param); param != "" {
+			query.Set(param, payload)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// findRedirect returns the first document of a navigation chain, after the
+// navigation itself, located on the marker host or one of its subdomains.
+func findRedirect(chain []redirectHop, marker string) (redirectHop, bool) {
+	for i := 1; i < len(chain); i++ {
+		parsed, err := url.Parse(chain[i].URL)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(parsed.Hostname())
+		if host == marker || strings.HasSuffix(host, "."+marker) {
+			return chain[i], true
+		}
+	}
+	return redirectHop{}, false
+}
+
+// redirectSourcesJS returns the meta refresh contents, the inline scripts and the
+// onload handlers of the document along with its url.
+const redirectSourcesJS = `() => {
+	const meta = Array.from(document.querySelectorAll('meta[http-equiv]')).filter(m => m.httpEquiv.toLowerCase() === 'refresh').map(m => m.content);
+	const scripts = Array.from(document.scripts).filter(s => !s.src).map(s => s.text);
+	for (const element of document.querySelectorAll('[onload]')) {
+		scripts.push(element.getAttribute('onload'));
+	}
+	return {url: document.URL, meta: meta, scripts: scripts};
+}`
+
+// redirectSources are the sources of the redirects of a document
+type redirectSources struct {
+	URL     string   `json:"url"`
+	Meta    []string `json:"meta"`
+	Scripts []string `json:"scripts"`
+}
+
+// autoRedirect is a redirect performed by a document without user interaction
+type autoRedirect struct {
+	// URL is the absolute target url of the redirect
+	URL string `json:"url"`
+	// Source is meta for meta refresh, script for redirects found in the
+	// scripts and navigation for navigations requested during the observation
+	Source string `json:"source"`
+	// Delay is the delay in seconds of a meta refresh
+	Delay string `json:"delay,omitempty"`
+	// Reason is the reason of a requested navigation (scriptInitiated, metaTagRefresh, etc)
+	Reason string `json:"reason,omitempty"`
+}
+
+// scriptRedirectRegex matches the assignments of string literals to the
+// location and the calls to location.replace and location.assign.
+var scriptRedirectRegex = regexp.MustCompile("\\blocation(?:\\.href)?\\s*=\\s*(?:\"([^\"\\n]*)\"|'([^'\\n]*)'|`([^`\\n]*)`)|\\blocation\\.(?:replace|assign)\\s*\\(\\s*(?:\"([^\"\\n]*)\"|'([^'\\n]*)'|`([^`\\n]*)`)")
+
+// Redirects detects the redirects performed by the loaded document without user
+// interaction. The meta refresh tags of the document and the location changes of
+// its inline scripts and onload handlers are inspected without following them,
+// then the navigations requested by the page are observed until the timeout
+// expires or one is requested, e.g. by a script redirecting after a delay.
+//
+// The redirects are stored as json in the output as the name of the action
+// (redirects by default), along with <name>_found for matching, the target of
+// the meta refresh in <name>_meta, the targets found in the scripts in
+// <name>_script and the observed navigations in <name>_observed, one per line.
+func (p *Page) Redirects(act *Action, out map[string]string) error {
+	timeout, err := getTimeout(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	pollTime, err := getPollTime(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong polling time given")
 	}
+
+	var observed []autoRedirect
+	observedMutex := &sync.Mutex{}
+	page, cancel := p.page.WithCancel()
+	defer cancel()
+	frameID := proto.PageFrameID(p.page.TargetID)
+	wait := page.EachEvent(func(e *proto.PageFrameRequestedNavigation) {
+		if e.FrameID != frameID {
+			return
+		}
+		observedMutex.Lock()
+		observed = append(observed, autoRedirect{URL: e.URL, Source: "navigation", Reason: string(e.Reason)})
+		observedMutex.Unlock()
+	})
+	go wait()
+
+	result, err := p.page.Eval(redirectSourcesJS)
+	if err != nil {
+		return errors.Wrap(err, "could not inspect document")
+	}
+	sources := &redirectSources{}
+	if err := result.Value.Unmarshal(sources); err != nil {
+		return errors.Wrap(err, "could not unmarshal redirect sources")
+	}
+	redirects := findStaticRedirects(sources)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		observedMutex.Lock()
+		done := len(observed) > 0
+		observedMutex.Unlock()
+		if done || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(pollTime)
+	}
+	observedMutex.Lock()
+	redirects = append(redirects, observed...)
+	observedMutex.Unlock()
+
+	data, err := json.Marshal(redirects)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal redirects")
+	}
+	name := act.Name
+	if name == "" {
+		name = "redirects"
+	}
+	targets := make(map[string][]string)
+	for _, redirect := range redirects {
+		targets[redirect.Source] = append(targets[redirect.Source], redirect.URL)
+	}
+	out[name] = string(data)
+	out[name+"_found"] = strconv.FormatBool(len(redirects) > 0)
+	out[name+"_meta"] = strings.Join(targets["meta"], "\n")
+	out[name+"_script"] = strings.Join(targets["script"], "\n")
+	out[name+"_observed"] = strings.Join(targets["navigation"], "\n")
 	return nil
 }
 
+// findStaticRedirects returns the redirects of the meta refresh tags and the
+// scripts of a document, resolved against its url and deduplicated.
+func findStaticRedirects(sources *redirectSources) []autoRedirect {
+	redirects := []autoRedirect{}
+	base, err := url.Parse(sources.URL)
+	if err != nil {
+		return redirects
+	}
+	seen := make(map[string]struct{})
+	add := func(redirect autoRedirect) {
+		target, err := base.Parse(strings.TrimSpace(redirect.URL))
+		if err != nil {
+			return
+		}
+		redirect.URL = target.String()
+		if _, ok := seen[redirect.Source+redirect.URL]; ok {
+			return
+		}
+		seen[redirect.Source+redirect.URL] = struct{}{}
+		redirects = append(redirects, redirect)
+	}
+
+	for _, content := range sources.Meta {
+		if delay, target, ok := parseMetaRefresh(content); ok {
+			add(autoRedirect{URL: target, Source: "meta", Delay: delay})
+		}
+	}
+	for _, script := range sources.Scripts {
+		for _, match := range scriptRedirectRegex.FindAllStringSubmatch(script, -1) {
+			for _, target := range match[1:] {
+				// dynamic template literals can't be resolved statically
+				if target != "" && !strings.Contains(target, "${") {
+					add(autoRedirect{URL: target, Source: "script"})
+				}
+			}
+		}
+	}
+	return redirects
+}
+
+// parseMetaRefresh parses the content of a meta refresh tag, e.g. 5; url=/next,
+// returning its delay and target url. Refreshes without url reload the document
+// and are not returned.
+func parseMetaRefresh(content string) (string, string, bool) {
+	delay, target, ok := strings.Cut(content, ";")
+	if !ok {
+		delay, target, ok = strings.Cut(content, ",")
+	}
+	if !ok {
+		return "", "", false
+	}
+	target = strings.TrimSpace(target)
+	if len(target) > 3 && strings.EqualFold(target[:3], "url") {
+		if rest := strings.TrimSpace(target[3:]); strings.HasPrefix(rest, "=") {
+			target = strings.TrimSpace(rest[1:])
+		}
+	}
+	target = strings.Trim(target, `"'`)
+	if target == "" {
+		return "", "", false
+	}
+	return strings.TrimSpace(delay), target, true
+}
+
+// navigationReferrer returns the referrer set for the navigation
+// to target, and marks it so that the hijack handler sets it on the request.
+func (p *Page) navigationReferrer(target string) *pendingReferrer {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.referrer == nil {
+		return nil
+	}
+	p.referrer.URL = target
+	return p.referrer
+}
+
 // RunScript runs a script on the loaded page
 func (p *Page) RunScript(action *Action, out map[string]string) error {
 	code := p.getActionArgWithDefaultValues(action, "code")
@@ -331,6 +1049,53 @@ func (p *Page) Screenshot(act *Action, out map[string]string) error {
 	if err != nil {
 		return errors.Wrap(err, "could not take screenshot")
 	}
+	return p.saveScreenshot(act, to, data)
+}
+
+// ScreenshotElement takes a screenshot of the bounding box of an element.
+//
+// The screenshot is written to the path of the to argument if specified,
+// otherwise it is stored base64 encoded in the output as the name of the action.
+func (p *Page) ScreenshotElement(act *Action, out map[string]string) error {
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	if err = element.ScrollIntoView(); err != nil {
+		return errors.Wrap(err, errCouldNotScroll)
+	}
+	result, err := element.Eval(elementInfoJS)
+	if err != nil {
+		return errors.Wrap(err, "could not get element info")
+	}
+	info := result.Value
+	if info.Get("width").Num() == 0 || info.Get("height").Num() == 0 {
+		return errors.New("could not take screenshot of zero-sized element")
+	}
+	if !info.Get("in_viewport").Bool() {
+		return errors.New("could not take screenshot of off-screen element")
+	}
+	data, err := element.Screenshot(proto.PageCaptureScreenshotFormatPng, 0)
+	if err != nil {
+		return errors.Wrap(err, "could not take element screenshot")
+	}
+
+	to := p.getActionArgWithDefaultValues(act, "to")
+	if to == "" {
+		if act.Name != "" {
+			out[act.Name] = base64.StdEncoding.EncodeToString(data)
+		}
+		if p.recorder != nil {
+			p.recorder.addScreenshot(ksuid.New().String()+".png", data)
+		}
+		return nil
+	}
+	return p.saveScreenshot(act, to, data)
+}
+
+// saveScreenshot writes a png screenshot to the path to, appending the
+// png extension if needed and creating its directory if mkdir is set.
+func (p *Page) saveScreenshot(act *Action, to string, data []byte) error {
 	if p.getActionArgWithDefaultValues(act, "mkdir") == "true" && stringsutil.ContainsAny(to, folderutil.UnixPathSeparator, folderutil.WindowsPathSeparator) {
 		// creates new directory if needed based on path `to`
 		// TODO: replace all permission bits with fileutil constants (https://github.com/projectdiscovery/utils/issues/113)
@@ -347,162 +1112,3542 @@ func (p *Page) Screenshot(act *Action, out map[string]string) error {
 		// return custom error as overwriting files is not supported
 		return errorutil.NewWithTag("screenshot", "failed to write screenshot, file %v already exists", filePath)
 	}
-	err = os.WriteFile(filePath, data, 0540)
+	if err := os.WriteFile(filePath, data, 0540); err != nil {
+		return errors.Wrap(err, "could not write screenshot")
+	}
+	if p.recorder != nil {
+		p.recorder.addScreenshot(filePath, data)
+	}
+	gologger.Info().Msgf("Screenshot successfully saved at %v\n", filePath)
+	return nil
+}
+
+// ScreenshotDiff compares a screenshot of the page with a baseline image
+// and stores the percentage of different pixels in the data map.
+//
+// If the baseline doesn't exist yet, the screenshot is saved as the
+// baseline and the difference is reported as 0.
+func (p *Page) ScreenshotDiff(act *Action, out map[string]string) error {
+	baselinePath := p.getActionArgWithDefaultValues(act, "baseline")
+	if baselinePath == "" {
+		return errors.New("baseline can't be empty")
+	}
+	if !strings.HasSuffix(baselinePath, ".png") {
+		baselinePath += ".png"
+	}
+
+	tolerance := 0
+	if value := p.getActionArgWithDefaultValues(act, "tolerance"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > 255 {
+			return errors.New("tolerance must be a number between 0 and 255")
+		}
+		tolerance = parsed
+	}
+	ignore, err := parseIgnoreRegions(p.getActionArgWithDefaultValues(act, "ignore"))
+	if err != nil {
+		return errors.Wrap(err, "could not parse ignore regions")
+	}
+
+	data, err := p.page.Screenshot(p.getActionArgWithDefaultValues(act, "fullpage") == "true", &proto.PageCaptureScreenshot{})
+	if err != nil {
+		return errors.Wrap(err, "could not take screenshot")
+	}
+
+	if !fileutil.FileExists(baselinePath) {
+		if p.getActionArgWithDefaultValues(act, "mkdir") == "true" {
+			if err := os.MkdirAll(filepath.Dir(baselinePath), 0700); err != nil {
+				return errorutil.NewWithErr(err).Msgf("failed to create directory while writing baseline")
+			}
+		}
+		if err := os.WriteFile(baselinePath, data, 0540); err != nil {
+			return errors.Wrap(err, "could not write baseline")
+		}
+		gologger.Info().Msgf("Baseline screenshot successfully saved at %v\n", baselinePath)
+		if act.Name != "" {
+			out[act.Name] = "0"
+		}
+		return nil
+	}
+
+	baselineFile, err := os.Open(baselinePath)
+	if err != nil {
+		return errors.Wrap(err, "could not open baseline")
+	}
+	defer baselineFile.Close()
+
+	baseline, _, err := image.Decode(baselineFile)
+	if err != nil {
+		return errors.Wrap(err, "could not decode baseline")
+	}
+	current, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "could not decode screenshot")
+	}
+	if act.Name != "" {
+		out[act.Name] = strconv.FormatFloat(diffImages(baseline, current, tolerance, ignore), 'f', 2, 64)
+	}
+	return nil
+}
+
+// InputElement executes input element actions for an element.
+func (p *Page) InputElement(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	value := p.getActionArgWithDefaultValues(act, "value")
+	if value == "" {
+		return errinvalidArguments
+	}
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	if err = element.ScrollIntoView(); err != nil {
+		return errors.Wrap(err, errCouldNotScroll)
+	}
+	if err = element.Input(value); err != nil {
+		return errors.Wrap(err, "could not input element")
+	}
+	return nil
+}
+
+// pasteJS dispatches a paste clipboard event carrying the text on an element
+// and returns false if a handler cancelled the default action.
+const pasteJS = `(text) => {
+	const data = new DataTransfer();
+	data.setData('text/plain', text);
+	const event = new ClipboardEvent('paste', {clipboardData: data, bubbles: true, cancelable: true});
+	return this.dispatchEvent(event);
+}`
+
+// PasteElement pastes text into an element, or the focused element if no
+// element is specified.
+//
+// A paste event is dispatched with the text as clipboard data, and the text
+// is inserted if no paste handler cancelled it.
+func (p *Page) PasteElement(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	value := p.getActionArgWithDefaultValues(act, "value")
+	if value == "" {
+		return errinvalidArguments
+	}
+	var element *rod.Element
+	var err error
+	if act.Data["selector"] == "" && act.Data["by"] == "" {
+		element, err = p.page.ElementByJS(&rod.EvalOptions{JS: "() => document.activeElement"})
+	} else {
+		element, err = p.pageElementBy(act.Data)
+	}
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	if err = element.ScrollIntoView(); err != nil {
+		return errors.Wrap(err, errCouldNotScroll)
+	}
+	if err = element.Focus(); err != nil {
+		return errors.Wrap(err, "could not focus element")
+	}
+	result, err := element.Eval(pasteJS, value)
+	if err != nil {
+		return errors.Wrap(err, "could not dispatch paste event")
+	}
+	if !result.Value.Bool() {
+		return nil
+	}
+	if err = p.page.InsertText(value); err != nil {
+		return errors.Wrap(err, "could not paste text")
+	}
+	return nil
+}
+
+// TimeInputElement executes time input on an element
+func (p *Page) TimeInputElement(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	value := p.getActionArgWithDefaultValues(act, "value")
+	if value == "" {
+		return errinvalidArguments
+	}
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	if err = element.ScrollIntoView(); err != nil {
+		return errors.Wrap(err, errCouldNotScroll)
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return errors.Wrap(err, "could not parse time")
+	}
+	if err := element.InputTime(t); err != nil {
+		return errors.Wrap(err, "could not input element")
+	}
+	return nil
+}
+
+// SelectInputElement executes select input statement action on a element
+func (p *Page) SelectInputElement(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	value := p.getActionArgWithDefaultValues(act, "value")
+	if value == "" {
+		return errinvalidArguments
+	}
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	if err = element.ScrollIntoView(); err != nil {
+		return errors.Wrap(err, errCouldNotScroll)
+	}
+
+	selectedBool := false
+	if p.getActionArgWithDefaultValues(act, "selected") == "true" {
+		selectedBool = true
+	}
+	by := p.getActionArgWithDefaultValues(act, "selector")
+	if err := element.Select([]string{value}, selectedBool, selectorBy(by)); err != nil {
+		return errors.Wrap(err, "could not select input")
+	}
+	return nil
+}
+
+// WaitLoad waits for the page to load
+func (p *Page) WaitLoad(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	p.page.Timeout(2 * time.Second).WaitNavigation(proto.PageLifecycleEventNameFirstMeaningfulPaint)()
+
+	// Wait for the window.onload event and also wait for the network requests
+	// to become idle for a maximum duration of 3 seconds. If the requests
+	// do not finish,
+	if err := p.page.WaitLoad(); err != nil {
+		return errors.Wrap(err, "could not wait load event")
+	}
+	_ = p.page.WaitIdle(1 * time.Second)
+	return nil
+}
+
+// GetResource gets a resource from an element from page.
+func (p *Page) GetResource(act *Action, out map[string]string) error {
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	resource, err := element.Resource()
+	if err != nil {
+		return errors.Wrap(err, "could not get src for element")
+	}
+	if act.Name != "" {
+		out[act.Name] = string(resource)
+	}
+	return nil
+}
+
+// elementInfoJS collects geometry and visibility details for the element bound to this.
+//
+// An element is considered occluded when the topmost element at its center point
+// is neither the element itself nor one of its descendants.
+const elementInfoJS = `() => {
+	const rect = this.getBoundingClientRect();
+	const style = window.getComputedStyle(this);
+	const inViewport = rect.bottom > 0 && rect.right > 0 &&
+		rect.top < window.innerHeight && rect.left < window.innerWidth;
+	const visible = rect.width > 0 && rect.height > 0 &&
+		style.display !== "none" && style.visibility !== "hidden" && style.opacity !== "0";
+	let occluded = false;
+	if (visible && inViewport) {
+		const top = document.elementFromPoint(rect.left + rect.width / 2, rect.top + rect.height / 2);
+		occluded = top !== null && top !== this && !this.contains(top);
+	}
+	return {
+		x: rect.x + window.scrollX,
+		y: rect.y + window.scrollY,
+		width: rect.width,
+		height: rect.height,
+		visible: visible,
+		in_viewport: inViewport,
+		occluded: occluded,
+		z_index: style.zIndex,
+	};
+}`
+
+// GetElementInfo gets the bounding box, visibility and z-index of an element.
+//
+// Values are stored as <name>_x, <name>_y, <name>_width, <name>_height,
+// <name>_visible, <name>_in_viewport, <name>_occluded and <name>_z_index.
+func (p *Page) GetElementInfo(act *Action, out map[string]string) error {
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	result, err := element.Eval(elementInfoJS)
+	if err != nil {
+		return errors.Wrap(err, "could not get element info")
+	}
+	if act.Name == "" {
+		return nil
+	}
+	info := result.Value
+	for _, key := range []string{"x", "y", "width", "height"} {
+		out[act.Name+"_"+key] = strconv.FormatFloat(info.Get(key).Num(), 'f', -1, 64)
+	}
+	for _, key := range []string{"visible", "in_viewport", "occluded"} {
+		out[act.Name+"_"+key] = strconv.FormatBool(info.Get(key).Bool())
+	}
+	out[act.Name+"_z_index"] = info.Get("z_index").Str()
+	return nil
+}
+
+// computedStyleJS returns the computed value of the style properties of the
+// element bound to this, or of all its properties when none are specified.
+const computedStyleJS = `(properties) => {
+	const style = window.getComputedStyle(this);
+	const names = properties.length > 0 ? properties : Array.from(style);
+	const result = {};
+	for (const name of names) {
+		result[name] = style.getPropertyValue(name).trim();
+	}
+	return result;
+}`
+
+// ComputedStyle gets the computed styles of an element on the page.
+//
+// The styles are stored as json in <name> and, when properties are specified,
+// each property is also stored in <name>_<property> with dashes replaced by
+// underscores (e.g. <name>_background_color).
+func (p *Page) ComputedStyle(act *Action, out map[string]string) error {
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	properties := parseStyleProperties(p.getActionArgWithDefaultValues(act, "properties"))
+	result, err := element.Eval(computedStyleJS, properties)
+	if err != nil {
+		return errors.Wrap(err, "could not get computed style")
+	}
+	if act.Name == "" {
+		return nil
+	}
+
+	styles := make(map[string]string)
+	for property, value := range result.Value.Map() {
+		styles[property] = value.Str()
+	}
+	data, err := json.Marshal(styles)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal computed style")
+	}
+	out[act.Name] = string(data)
+	for _, property := range properties {
+		out[act.Name+"_"+styleVariableName(property)] = styles[property]
+	}
+	return nil
+}
+
+// parseStyleProperties parses a comma separated list of style properties
+func parseStyleProperties(value string) []string {
+	properties := []string{}
+	seen := make(map[string]struct{})
+	for _, property := range strings.Split(value, ",") {
+		property = strings.TrimSpace(property)
+		// custom properties are case-sensitive
+		if !strings.HasPrefix(property, "--") {
+			property = strings.ToLower(property)
+		}
+		if _, ok := seen[property]; ok || property == "" {
+			continue
+		}
+		seen[property] = struct{}{}
+		properties = append(properties, property)
+	}
+	return properties
+}
+
+// styleVariableName returns the variable suffix of a style property
+func styleVariableName(property string) string {
+	return strings.ReplaceAll(strings.TrimLeft(property, "-"), "-", "_")
+}
+
+// EvalOnNewDocument injects a script evaluated at the start of every document
+// loaded by the page before any of its own scripts, and runs it on the current
+// document as well.
+//
+// A script injected by a named action is removed by a later action with remove
+// set to that name. When signal is specified, the js function is polled until it
+// returns a truthy value or the timeout expires, storing the value in <name>.
+// An expired timeout is not an error, <name> is left empty instead.
+func (p *Page) EvalOnNewDocument(act *Action, out map[string]string) error {
+	if remove := p.getActionArgWithDefaultValues(act, "remove"); remove != "" {
+		return p.removeNewDocumentScript(remove)
+	}
+	code := p.getActionArgWithDefaultValues(act, "code")
+	if code == "" {
+		return errinvalidArguments
+	}
+	removeScript, err := p.page.EvalOnNewDocument(code)
+	if err != nil {
+		return errors.Wrap(err, "could not add script to evaluate on new document")
+	}
+	if act.Name != "" {
+		p.mutex.Lock()
+		if p.newDocumentScripts == nil {
+			p.newDocumentScripts = make(map[string]func() error)
+		}
+		p.newDocumentScripts[act.Name] = removeScript
+		p.mutex.Unlock()
+	}
+
+	// the current document has already started, so run the script on it too
+	result, err := proto.RuntimeEvaluate{Expression: code}.Call(p.page)
+	if err != nil {
+		return errors.Wrap(err, "could not evaluate script")
+	}
+	if result.ExceptionDetails != nil {
+		return fmt.Errorf("could not evaluate script: %s", result.ExceptionDetails.Text)
+	}
+
+	signal := p.getActionArgWithDefaultValues(act, "signal")
+	if signal == "" {
+		return nil
+	}
+	timeout, err := getTimeout(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	pollTime, err := getPollTime(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong polling time given")
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		// evaluation fails while the page navigates, keep polling until the deadline
+		if data, err := p.page.Eval(signal); err == nil {
+			if value := signalValue(data.Value.Val()); value != "" {
+				if act.Name != "" {
+					out[act.Name] = value
+				}
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil
+		}
+		time.Sleep(pollTime)
+	}
+}
+
+// removeNewDocumentScript removes the script injected by the evalonnewdocument action with name
+func (p *Page) removeNewDocumentScript(name string) error {
+	p.mutex.Lock()
+	removeScript, ok := p.newDocumentScripts[name]
+	delete(p.newDocumentScripts, name)
+	p.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no script injected by %s", name)
+	}
+	if err := removeScript(); err != nil {
+		return errors.Wrap(err, "could not remove script to evaluate on new document")
+	}
+	return nil
+}
+
+// signalValue returns the value returned by a signal function as a string,
+// empty if the value is falsy. Values other than strings are json encoded.
+func signalValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		if !v {
+			return ""
+		}
+	case float64:
+		if v == 0 {
+			return ""
+		}
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// FilesInput acts with a file input element on page
+func (p *Page) FilesInput(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	if err = element.ScrollIntoView(); err != nil {
+		return errors.Wrap(err, errCouldNotScroll)
+	}
+	value := p.getActionArgWithDefaultValues(act, "value")
+	filesPaths := strings.Split(value, ",")
+	if err := element.SetFiles(filesPaths); err != nil {
+		return errors.Wrap(err, "could not set files")
+	}
+	return nil
+}
+
+// ExtractElement extracts from an element on the page.
+func (p *Page) ExtractElement(act *Action, out map[string]string) error {
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	if err = element.ScrollIntoView(); err != nil {
+		return errors.Wrap(err, errCouldNotScroll)
+	}
+	switch p.getActionArgWithDefaultValues(act, "target") {
+	case "attribute":
+		attrName := p.getActionArgWithDefaultValues(act, "attribute")
+		if attrName == "" {
+			return errors.New("attribute can't be empty")
+		}
+		attrValue, err := element.Attribute(attrName)
+		if err != nil {
+			return errors.Wrap(err, "could not get attribute")
+		}
+		if act.Name != "" {
+			out[act.Name] = *attrValue
+		}
+	default:
+		text, err := element.Text()
+		if err != nil {
+			return errors.Wrap(err, "could not get element text node")
+		}
+		if act.Name != "" {
+			out[act.Name] = text
+		}
+	}
+	return nil
+}
+
+// extractLinksJS returns the unique absolute urls of all the links on the page
+const extractLinksJS = `() => {
+	const links = new Set();
+	for (const link of document.querySelectorAll("a[href]")) {
+		if (link.href) {
+			links.add(link.href);
+		}
+	}
+	return Array.from(links);
+}`
+
+// extractFormsJS returns the unique forms on the page along with their inputs
+const extractFormsJS = `() => {
+	const seen = new Set();
+	const forms = [];
+	for (const form of document.forms) {
+		const inputs = [];
+		for (const element of form.elements) {
+			if (!element.name) {
+				continue;
+			}
+			inputs.push({ name: element.name, type: element.type || "", value: element.value || "" });
+		}
+		const item = {
+			action: form.action,
+			method: (form.getAttribute("method") || "GET").toUpperCase(),
+			inputs: inputs,
+		};
+		const key = JSON.stringify(item);
+		if (!seen.has(key)) {
+			seen.add(key);
+			forms.push(item);
+		}
+	}
+	return forms;
+}`
+
+// ExtractLinks extracts the absolute urls of all the links on the rendered page as a json array.
+func (p *Page) ExtractLinks(act *Action, out map[string]string) error {
+	result, err := p.page.Eval(extractLinksJS)
+	if err != nil {
+		return errors.Wrap(err, "could not extract links")
+	}
+	if act.Name != "" {
+		out[act.Name] = result.Value.JSON("", "")
+	}
+	return nil
+}
+
+// ExtractForms extracts all the forms on the rendered page with their action,
+// method and inputs as a json array.
+func (p *Page) ExtractForms(act *Action, out map[string]string) error {
+	result, err := p.page.Eval(extractFormsJS)
+	if err != nil {
+		return errors.Wrap(err, "could not extract forms")
+	}
+	if act.Name != "" {
+		out[act.Name] = result.Value.JSON("", "")
+	}
+	return nil
+}
+
+// extractMetadataJS collects the meta tags of the rendered page keyed by their
+// lowercased name, property, http-equiv or itemprop attribute, and the content
+// of its json-ld scripts.
+const extractMetadataJS = `() => {
+	const metas = {};
+	for (const meta of document.querySelectorAll('meta')) {
+		let key = meta.getAttribute('name') || meta.getAttribute('property') || meta.getAttribute('http-equiv') || meta.getAttribute('itemprop');
+		let content = meta.getAttribute('content');
+		if (!key && meta.hasAttribute('charset')) {
+			key = 'charset';
+			content = meta.getAttribute('charset');
+		}
+		if (!key || content === null) {
+			continue;
+		}
+		key = key.trim().toLowerCase();
+		metas[key] = key in metas ? metas[key] + '\n' + content : content;
+	}
+	const scripts = Array.from(document.querySelectorAll('script[type="application/ld+json" i]')).map(script => script.textContent);
+	return {metas: metas, jsonld: scripts};
+}`
+
+// pageMetadata are the meta tags and json-ld scripts of a page
+type pageMetadata struct {
+	Metas  map[string]string `json:"metas"`
+	JSONLD []string          `json:"jsonld"`
+}
+
+// Metadata collects the meta tags and the json-ld structured data of the
+// rendered page, for fingerprinting and finding leaks in structured data.
+//
+// The meta tags are stored as a json object of the content keyed by the
+// lowercased name, property, http-equiv or itemprop attribute in <name>_meta,
+// the content of repeated keys being newline separated, and the parsed json-ld
+// objects as a json array in <name>_jsonld, scripts which are not valid json
+// being skipped. Both are stored together as the name of the action (metadata
+// by default).
+func (p *Page) Metadata(act *Action, out map[string]string) error {
+	result, err := p.page.Eval(extractMetadataJS)
+	if err != nil {
+		return errors.Wrap(err, "could not extract metadata")
+	}
+	metadata := &pageMetadata{}
+	if err := result.Value.Unmarshal(metadata); err != nil {
+		return errors.Wrap(err, "could not unmarshal metadata")
+	}
+
+	jsonld := make([]json.RawMessage, 0, len(metadata.JSONLD))
+	for _, script := range metadata.JSONLD {
+		script = strings.TrimSpace(script)
+		if !json.Valid([]byte(script)) {
+			continue
+		}
+		jsonld = append(jsonld, json.RawMessage(script))
+	}
+	metas := metadata.Metas
+	if metas == nil {
+		metas = make(map[string]string)
+	}
+	data, err := json.Marshal(map[string]interface{}{"meta": metas, "jsonld": jsonld})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal metadata")
+	}
+	metasData, _ := json.Marshal(metas)
+	jsonldData, _ := json.Marshal(jsonld)
+
+	name := act.Name
+	if name == "" {
+		name = "metadata"
+	}
+	out[name] = string(data)
+	out[name+"_meta"] = string(metasData)
+	out[name+"_jsonld"] = string(jsonldData)
+	return nil
+}
+
+// maxEventHandlers is the maximum number of inline event handlers extracted from a page
+const maxEventHandlers = 1000
+
+// extractEventHandlersJS returns the inline event handler attributes and the
+// javascript: urls of the elements of the rendered page, including the ones
+// in open shadow roots, with the tag and attribute they were found in.
+const extractEventHandlersJS = `(limit) => {
+	const urlAttributes = ['href', 'src', 'action', 'formaction', 'data', 'xlink:href'];
+	const handlers = [];
+	const walk = (root) => {
+		for (const element of root.querySelectorAll('*')) {
+			if (handlers.length >= limit) {
+				return;
+			}
+			for (const attribute of element.attributes) {
+				const name = attribute.name.toLowerCase();
+				if (name.startsWith('on') && name.length > 2) {
+					handlers.push({tag: element.tagName.toLowerCase(), attribute: name, id: element.id || '', code: attribute.value});
+				} else if (urlAttributes.includes(name)) {
+					// browsers strip leading whitespace and control characters and tabs and newlines anywhere in the scheme
+					const value = attribute.value.replace(/^[\u0000- ]+/, '');
+					const match = value.match(/^j[\t\n\r]*a[\t\n\r]*v[\t\n\r]*a[\t\n\r]*s[\t\n\r]*c[\t\n\r]*r[\t\n\r]*i[\t\n\r]*p[\t\n\r]*t[\t\n\r]*:/i);
+					if (match) {
+						handlers.push({tag: element.tagName.toLowerCase(), attribute: name, id: element.id || '', code: value.slice(match[0].length)});
+					}
+				}
+			}
+			if (element.shadowRoot) {
+				walk(element.shadowRoot);
+			}
+		}
+	};
+	walk(document);
+	return handlers.slice(0, limit);
+}`
+
+// eventHandler is an inline event handler or javascript url of an element
+type eventHandler struct {
+	Tag       string `json:"tag"`
+	Attribute string `json:"attribute"`
+	ID        string `json:"id,omitempty"`
+	Code      string `json:"code"`
+}
+
+// EventHandlers extracts the inline event handler attributes (onclick, onerror,
+// etc.) and the javascript: urls of the elements of the rendered page, which
+// surface payloads reflected or stored in handler attributes and the inline
+// code blocking a strict content security policy.
+//
+// The handlers are stored as a json array of objects with the tag, attribute,
+// id and code of each handler as the name of the action (eventhandlers by
+// default), and their code newline separated in <name>_code for matching.
+// At most maxEventHandlers handlers are extracted.
+func (p *Page) EventHandlers(act *Action, out map[string]string) error {
+	result, err := p.page.Eval(extractEventHandlersJS, maxEventHandlers)
+	if err != nil {
+		return errors.Wrap(err, "could not extract event handlers")
+	}
+	handlers := []eventHandler{}
+	if err := result.Value.Unmarshal(&handlers); err != nil {
+		return errors.Wrap(err, "could not unmarshal event handlers")
+	}
+
+	code := make([]string, 0, len(handlers))
+	for _, handler := range handlers {
+		code = append(code, handler.Code)
+	}
+	data, err := json.Marshal(handlers)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal event handlers")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "eventhandlers"
+	}
+	out[name] = string(data)
+	out[name+"_code"] = strings.Join(code, "\n")
+	return nil
+}
+
+// wasmHookJS records the WebAssembly modules compiled or instantiated by a
+// document from its start in window.__nucleiWasm, with the api used, the url
+// of the streamed response, the size of the buffer, the imports and exports
+// of the module and the base64 encoded buffer if capture is true.
+const wasmHookJS = `(capture) => {
+	if (window.__nucleiWasm || typeof WebAssembly === 'undefined') {
+		return;
+	}
+	const modules = window.__nucleiWasm = [];
+	const OriginalModule = WebAssembly.Module;
+	const bytesOf = (source) => {
+		if (source instanceof ArrayBuffer) {
+			return new Uint8Array(source.slice(0));
+		}
+		if (ArrayBuffer.isView(source)) {
+			return new Uint8Array(source.buffer.slice(source.byteOffset, source.byteOffset + source.byteLength));
+		}
+		return null;
+	};
+	const toBase64 = (bytes) => {
+		let binary = '';
+		for (let i = 0; i < bytes.length; i += 0x8000) {
+			binary += String.fromCharCode.apply(null, bytes.subarray(i, i + 0x8000));
+		}
+		return btoa(binary);
+	};
+	const record = (api, url, bytes, module) => {
+		const entry = {api: api, url: url || '', size: bytes ? bytes.length : 0, imports: [], exports: []};
+		if (capture && bytes) {
+			entry.data = toBase64(bytes);
+		}
+		try {
+			entry.imports = OriginalModule.imports(module).map(item => item.module + '.' + item.name);
+			entry.exports = OriginalModule.exports(module).map(item => item.name);
+		} catch (e) {}
+		modules.push(entry);
+	};
+	const wrap = (name, streaming) => {
+		const original = WebAssembly[name];
+		if (!original) {
+			return;
+		}
+		WebAssembly[name] = function(source, ...args) {
+			const result = original.call(this, source, ...args);
+			if (source instanceof OriginalModule) {
+				return result;
+			}
+			const bytes = streaming ? null : bytesOf(source);
+			Promise.resolve(result).then(value => {
+				const module = value instanceof OriginalModule ? value : value && value.module;
+				if (!streaming) {
+					record(name, '', bytes, module);
+					return;
+				}
+				Promise.resolve(source).then(response => record(name, response && response.url, null, module), () => {});
+			}, () => {});
+			return result;
+		};
+	};
+	wrap('compile', false);
+	wrap('instantiate', false);
+	wrap('compileStreaming', true);
+	wrap('instantiateStreaming', true);
+	WebAssembly.Module = new Proxy(OriginalModule, {
+		construct(target, args, newTarget) {
+			const module = Reflect.construct(target, args, newTarget);
+			record('Module', '', bytesOf(args[0]), module);
+			return module;
+		},
+	});
+}`
+
+// wasmMagic is the magic number starting the WebAssembly binary format
+const wasmMagic = "\x00asm"
+
+// wasmModule is a WebAssembly module compiled by the page or fetched by it
+type wasmModule struct {
+	// API is the WebAssembly api compiling the module, or network for fetched modules
+	API string `json:"api"`
+	// URL is the url of the module if it was fetched or streamed
+	URL string `json:"url,omitempty"`
+	// Size is the size of the module in bytes, 0 if unknown
+	Size int `json:"size"`
+	// SHA256 is the hex encoded sha256 hash of the fetched module
+	SHA256 string `json:"sha256,omitempty"`
+	// Imports are the imports of the module as module.name
+	Imports []string `json:"imports,omitempty"`
+	// Exports are the names of the exports of the module
+	Exports []string `json:"exports,omitempty"`
+	// Data is the base64 encoded module, captured if asked
+	Data string `json:"data,omitempty"`
+	// Truncated is true if the fetched module was truncated to the maximum body size
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// isWasmResponse returns true if the history entry is a WebAssembly module
+func isWasmResponse(historyData HistoryData) bool {
+	if strings.HasPrefix(historyData.ResponseBody, wasmMagic) {
+		return true
+	}
+	if historyData.ResponseHeaders != nil && strings.HasPrefix(historyData.ResponseHeaders.Get("Content-Type"), "application/wasm") {
+		return true
+	}
+	parsed, err := url.Parse(historyData.URL)
+	return err == nil && strings.HasSuffix(strings.ToLower(parsed.Path), ".wasm")
+}
+
+// WASM navigates to url ({{BaseURL}} by default) with the WebAssembly apis
+// hooked at the start of every document, and reports the modules compiled or
+// instantiated by the page after observing it for window seconds (3 by
+// default), along with the .wasm modules fetched by it from the page history.
+// Unexpected WebAssembly is itself a signal as modules can hide logic.
+//
+// The modules are stored as a json array of objects with the api, url, size,
+// imports and exports of each module as the name of the action (wasm by
+// default), with the fetched modules having the network api and their sha256
+// hash. <name>_detected is true if any module was found, <name>_count is the
+// number of modules and <name>_urls the newline separated module urls. If the
+// capture argument is true, the base64 encoded modules are included as data.
+func (p *Page) WASM(act *Action, out map[string]string, baseURL *url.URL) error {
+	URL := p.getActionArgWithDefaultValues(act, "url")
+	if URL == "" {
+		URL = "{{BaseURL}}"
+	}
+	window, err := geTimeParameter(p, act, "window", 3, time.Second)
+	if err != nil {
+		return errors.Wrap(err, "Wrong observation window given")
+	}
+	capture := p.getActionArgWithDefaultValues(act, "capture") == "true"
+
+	removeHook, err := p.page.EvalOnNewDocument(fmt.Sprintf("(%s)(%t)", wasmHookJS, capture))
+	if err != nil {
+		return errors.Wrap(err, "could not inject webassembly hook")
+	}
+	defer func() {
+		_ = removeHook()
+	}()
+	p.mutex.RLock()
+	historyStart := len(p.History)
+	p.mutex.RUnlock()
+	if err := p.page.Navigate(navigationURL(URL, baseURL)); err != nil {
+		return errors.Wrap(err, "could not navigate")
+	}
+
+	deadline := time.Now().Add(window)
+	if pageDeadline, ok := p.page.GetContext().Deadline(); ok && pageDeadline.Add(-time.Second).Before(deadline) {
+		// keep some time to read the modules before the page times out
+		deadline = pageDeadline.Add(-time.Second)
+	}
+	time.Sleep(time.Until(deadline))
+
+	result, err := p.page.Eval("() => window.__nucleiWasm || []")
+	if err != nil {
+		return errors.Wrap(err, "could not read webassembly modules")
+	}
+	modules := []wasmModule{}
+	if err := result.Value.Unmarshal(&modules); err != nil {
+		return errors.Wrap(err, "could not unmarshal webassembly modules")
+	}
+
+	p.mutex.RLock()
+	history := p.History[historyStart:]
+	p.mutex.RUnlock()
+	fetched := make(map[string]int)
+	for _, historyData := range history {
+		if !isWasmResponse(historyData) {
+			continue
+		}
+		hash := sha256.Sum256([]byte(historyData.ResponseBody))
+		module := wasmModule{
+			API:       "network",
+			URL:       historyData.URL,
+			Size:      len(historyData.ResponseBody),
+			SHA256:    hex.EncodeToString(hash[:]),
+			Truncated: historyData.Truncated,
+		}
+		if capture {
+			module.Data = base64.StdEncoding.EncodeToString([]byte(historyData.ResponseBody))
+		}
+		fetched[module.URL] = module.Size
+		modules = append(modules, module)
+	}
+
+	var urls []string
+	seen := make(map[string]struct{})
+	for i, module := range modules {
+		// streamed modules have no buffer, use the size of the fetched module
+		if module.Size == 0 && module.URL != "" {
+			modules[i].Size = fetched[module.URL]
+		}
+		if _, ok := seen[module.URL]; module.URL == "" || ok {
+			continue
+		}
+		seen[module.URL] = struct{}{}
+		urls = append(urls, module.URL)
+	}
+	data, err := json.Marshal(modules)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal webassembly modules")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "wasm"
+	}
+	out[name] = string(data)
+	out[name+"_detected"] = strconv.FormatBool(len(modules) > 0)
+	out[name+"_count"] = strconv.Itoa(len(modules))
+	out[name+"_urls"] = strings.Join(urls, "\n")
+	return nil
+}
+
+// detectedTracker is a request of the page to a known tracker
+type detectedTracker struct {
+	// Name is the name of the tracker
+	Name string `json:"name"`
+	// Category is the category of the tracker
+	Category string `json:"category"`
+	// URL is the url of the request
+	URL string `json:"url"`
+	// Type is the resource type of the request (Script, Image, XHR, etc)
+	Type string `json:"type"`
+}
+
+// Trackers classifies the requests of the page history, such as the scripts,
+// pixels and beacons it loaded, against the known third-party trackers and
+// analytics sdks, for privacy audits.
+//
+// Custom trackers are given as newline separated pattern,name,category entries
+// in the trackers argument and take precedence over the built-in ones, which
+// are not used if the builtin argument is false. A pattern is a domain also
+// matching its subdomains optionally followed by a path prefix (facebook.com/tr),
+// or a path suffix matching self-hosted trackers on any host (/matomo.js).
+//
+// The detected trackers are stored as a json array of objects with the name,
+// category, url and resource type of each request as the name of the action
+// (trackers by default), with the newline separated unique names and categories
+// in order of detection in <name>_names and <name>_categories, and the number
+// of distinct trackers in <name>_count.
+func (p *Page) Trackers(act *Action, out map[string]string) error {
+	trackers, err := parseTrackers(p.getActionArgWithDefaultValues(act, "trackers"))
+	if err != nil {
+		return err
+	}
+	if p.getActionArgWithDefaultValues(act, "builtin") != "false" {
+		trackers = append(trackers, defaultTrackers...)
+	}
+
+	p.mutex.RLock()
+	history := p.History
+	p.mutex.RUnlock()
+
+	detected := []detectedTracker{}
+	seenURLs := make(map[string]struct{})
+	var names, categories []string
+	for _, historyData := range history {
+		if _, ok := seenURLs[historyData.URL]; ok {
+			continue
+		}
+		item, ok := classifyTracker(trackers, historyData.URL)
+		if !ok {
+			continue
+		}
+		seenURLs[historyData.URL] = struct{}{}
+		names = append(names, item.Name)
+		categories = append(categories, item.Category)
+		detected = append(detected, detectedTracker{Name: item.Name, Category: item.Category, URL: historyData.URL, Type: string(historyData.ResourceType)})
+	}
+	data, err := json.Marshal(detected)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal trackers")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "trackers"
+	}
+	out[name] = string(data)
+	names = sliceutil.Dedupe(names)
+	out[name+"_names"] = strings.Join(names, "\n")
+	out[name+"_categories"] = strings.Join(sliceutil.Dedupe(categories), "\n")
+	out[name+"_count"] = strconv.Itoa(len(names))
+	return nil
+}
+
+// storageUsage is the client-side storage usage of an origin
+type storageUsage struct {
+	// Origin is the security origin of the storage
+	Origin string `json:"origin"`
+	// Usage is the storage usage of the origin in bytes
+	Usage float64 `json:"usage"`
+	// Quota is the storage quota of the origin in bytes
+	Quota float64 `json:"quota"`
+	// UsageBreakdown is the storage usage of the origin in bytes per storage type
+	UsageBreakdown map[string]float64 `json:"usage_breakdown"`
+	// LocalStorageEntries is the number of localStorage entries
+	LocalStorageEntries int `json:"local_storage_entries"`
+	// LocalStorageBytes is the size of the localStorage keys and values in bytes
+	LocalStorageBytes int `json:"local_storage_bytes"`
+	// IndexedDB are the IndexedDB databases of the origin
+	IndexedDB []indexedDBUsage `json:"indexeddb"`
+}
+
+// indexedDBUsage is the usage of an IndexedDB database
+type indexedDBUsage struct {
+	// Name is the name of the database
+	Name string `json:"name"`
+	// Version is the version of the database
+	Version float64 `json:"version"`
+	// Entries is the number of entries of the object stores of the database
+	Entries int `json:"entries"`
+	// ObjectStores are the number of entries per object store of the database
+	ObjectStores map[string]int `json:"object_stores"`
+}
+
+// Storage measures the client-side storage of the origin of the page: the
+// number of localStorage entries and their size, the IndexedDB databases with
+// the number of entries of their object stores, and the storage usage and quota
+// reported by the browser. It measures the storage at the time it runs, so it
+// should come after the actions filling it, such as a login.
+//
+// The usage is stored as a json object as the name of the action (storage by
+// default), with the usage and quota in bytes in <name>_usage and <name>_quota,
+// the localStorage entries and bytes in <name>_local_storage_entries and
+// <name>_local_storage_bytes, the IndexedDB usage in bytes in <name>_indexeddb_usage,
+// and the newline separated IndexedDB database names and their number in
+// <name>_indexeddb_databases and <name>_indexeddb_count.
+func (p *Page) Storage(act *Action, out map[string]string) error {
+	tree, err := proto.PageGetFrameTree{}.Call(p.page)
+	if err != nil {
+		return errors.Wrap(err, "could not get frame tree")
+	}
+	origins := frameOrigins(&proto.PageFrameTree{Frame: tree.FrameTree.Frame})
+	if len(origins) == 0 {
+		return errors.New("page has no storage origin")
+	}
+	usage := &storageUsage{Origin: origins[0], UsageBreakdown: make(map[string]float64), IndexedDB: []indexedDBUsage{}}
+
+	quota, err := proto.StorageGetUsageAndQuota{Origin: usage.Origin}.Call(p.page)
+	if err != nil {
+		return errors.Wrap(err, "could not get storage usage")
+	}
+	usage.Usage, usage.Quota = quota.Usage, quota.Quota
+	for _, item := range quota.UsageBreakdown {
+		usage.UsageBreakdown[string(item.StorageType)] = item.Usage
+	}
+
+	if err := (proto.DOMStorageEnable{}).Call(p.page); err != nil {
+		return errors.Wrap(err, "could not enable dom storage")
+	}
+	items, err := proto.DOMStorageGetDOMStorageItems{
+		StorageID: &proto.DOMStorageStorageID{SecurityOrigin: usage.Origin, IsLocalStorage: true},
+	}.Call(p.page)
+	if err != nil {
+		return errors.Wrap(err, "could not get local storage")
+	}
+	for _, item := range items.Entries {
+		if len(item) != 2 {
+			continue
+		}
+		usage.LocalStorageEntries++
+		usage.LocalStorageBytes += len(item[0]) + len(item[1])
+	}
+
+	if err := (proto.IndexedDBEnable{}).Call(p.page); err != nil {
+		return errors.Wrap(err, "could not enable indexeddb")
+	}
+	names, err := proto.IndexedDBRequestDatabaseNames{SecurityOrigin: usage.Origin}.Call(p.page)
+	if err != nil {
+		return errors.Wrap(err, "could not get indexeddb databases")
+	}
+	for _, databaseName := range names.DatabaseNames {
+		database, err := proto.IndexedDBRequestDatabase{SecurityOrigin: usage.Origin, DatabaseName: databaseName}.Call(p.page)
+		if err != nil {
+			return errors.Wrapf(err, "could not get indexeddb database %s", databaseName)
+		}
+		item := indexedDBUsage{Name: databaseName, Version: database.DatabaseWithObjectStores.Version, ObjectStores: make(map[string]int)}
+		for _, store := range database.DatabaseWithObjectStores.ObjectStores {
+			metadata, err := proto.IndexedDBGetMetadata{SecurityOrigin: usage.Origin, DatabaseName: databaseName, ObjectStoreName: store.Name}.Call(p.page)
+			if err != nil {
+				return errors.Wrapf(err, "could not get indexeddb object store %s", store.Name)
+			}
+			item.ObjectStores[store.Name] = int(metadata.EntriesCount)
+			item.Entries += int(metadata.EntriesCount)
+		}
+		usage.IndexedDB = append(usage.IndexedDB, item)
+	}
+
+	data, err := json.Marshal(usage)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal storage usage")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "storage"
+	}
+	out[name] = string(data)
+	out[name+"_usage"] = strconv.FormatFloat(usage.Usage, 'f', 0, 64)
+	out[name+"_quota"] = strconv.FormatFloat(usage.Quota, 'f', 0, 64)
+	out[name+"_local_storage_entries"] = strconv.Itoa(usage.LocalStorageEntries)
+	out[name+"_local_storage_bytes"] = strconv.Itoa(usage.LocalStorageBytes)
+	out[name+"_indexeddb_usage"] = strconv.FormatFloat(usage.UsageBreakdown[string(proto.StorageStorageTypeIndexeddb)], 'f', 0, 64)
+	out[name+"_indexeddb_databases"] = strings.Join(names.DatabaseNames, "\n")
+	out[name+"_indexeddb_count"] = strconv.Itoa(len(names.DatabaseNames))
+	return nil
+}
+
+// WebSocket navigates to url ({{BaseURL}} by default) with the websockets of
+// the page hooked at the start of every document, for window seconds (3 by
+// default) bounded by the page timeout, to modify and inject websocket frames.
+// CDP only observes websocket frames, so the WebSocket api of the page is hooked
+// instead, which doesn't cover the websockets of workers.
+//
+// Only the websockets whose url matches the endpoint regex are hooked, all of them
+// if it's not given. The parts of their text frames matching the match regex are
+// replaced with replace ($1 for groups) in the frames of direction (sent by default,
+// received or both), and the newline separated frames of inject are sent once they
+// open. The frames of the hooked websockets, with the original data of the modified
+// ones, are recorded in the page websocket frames available as websocket_frames.
+//
+// The frames are stored as a json array of objects with the url, direction, data
+// and the original data of each frame as the name of the action (websocket by
+// default), with the number of frames in <name>_count, the newline separated urls
+// of the websockets in <name>_urls and the newline separated data of the received
+// frames in <name>_received.
+func (p *Page) WebSocket(act *Action, out map[string]string, baseURL *url.URL) error {
+	URL := p.getActionArgWithDefaultValues(act, "url")
+	if URL == "" {
+		URL = "{{BaseURL}}"
+	}
+	window, err := geTimeParameter(p, act, "window", 3, time.Second)
+	if err != nil {
+		return errors.Wrap(err, "Wrong observation window given")
+	}
+	config := &webSocketHookConfig{
+		Endpoint:  p.getActionArgWithDefaultValues(act, "endpoint"),
+		Match:     p.getActionArgWithDefaultValues(act, "match"),
+		Replace:   p.getActionArgWithDefaultValues(act, "replace"),
+		Direction: p.getActionArgWithDefaultValues(act, "direction"),
+		Inject:    []string{},
+		Max:       maxWebSocketFrames,
+	}
+	for _, pattern := range []string{config.Endpoint, config.Match} {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return errors.Wrapf(err, "invalid regex %s", pattern)
+		}
+	}
+	switch config.Direction {
+	case "":
+		config.Direction = "sent"
+	case "sent", "received", "both":
+	default:
+		return errors.Errorf("invalid direction %s, expected sent, received or both", config.Direction)
+	}
+	for _, frame := range strings.Split(p.getActionArgWithDefaultValues(act, "inject"), "\n") {
+		if frame = strings.TrimRight(frame, "\r"); frame != "" && len(config.Inject) < maxInjectedWebSocketFrames {
+			config.Inject = append(config.Inject, frame)
+		}
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal websocket hook")
+	}
+
+	removeHook, err := p.page.EvalOnNewDocument(fmt.Sprintf("(%s)(%s)", webSocketHookJS, configJSON))
+	if err != nil {
+		return errors.Wrap(err, "could not inject websocket hook")
+	}
+	defer func() {
+		_ = removeHook()
+	}()
+	if err := p.page.Navigate(navigationURL(URL, baseURL)); err != nil {
+		return errors.Wrap(err, "could not navigate")
+	}
+
+	deadline := time.Now().Add(window)
+	if pageDeadline, ok := p.page.GetContext().Deadline(); ok && pageDeadline.Add(-time.Second).Before(deadline) {
+		// keep some time to read the frames before the page times out
+		deadline = pageDeadline.Add(-time.Second)
+	}
+	time.Sleep(time.Until(deadline))
+
+	result, err := p.page.Eval("() => window.__nucleiWebSocket || []")
+	if err != nil {
+		return errors.Wrap(err, "could not read websocket frames")
+	}
+	frames := []WebSocketFrame{}
+	if err := result.Value.Unmarshal(&frames); err != nil {
+		return errors.Wrap(err, "could not unmarshal websocket frames")
+	}
+	var urls, received []string
+	for i, frame := range frames {
+		frames[i].Data = p.truncateRequestBody(frame.Data)
+		frames[i].Original = p.truncateRequestBody(frame.Original)
+		urls = append(urls, frame.URL)
+		if frame.Direction == "received" {
+			received = append(received, frames[i].Data)
+		}
+	}
+	p.addWebSocketFrames(frames...)
+
+	data, err := json.Marshal(frames)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal websocket frames")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "websocket"
+	}
+	out[name] = string(data)
+	out[name+"_count"] = strconv.Itoa(len(frames))
+	out[name+"_urls"] = strings.Join(sliceutil.Dedupe(urls), "\n")
+	out[name+"_received"] = strings.Join(received, "\n")
+	return nil
+}
+
+// CORSProbe requests url ({{BaseURL}} by default) with method (GET by default)
+// once per kind of origin, with the cookies and user agent of the page, to find
+// the origins trusted along with credentials by its cors policy. The kinds are
+// reflected (the attacker origin, https://nuclei-cors.example by default), null,
+// subdomain (a subdomain of the target) and suffix (the target host followed by
+// the attacker host), all of them unless a comma separated subset is given.
+//
+// An origin is vulnerable if it's allowed as is by Access-Control-Allow-Origin
+// along with Access-Control-Allow-Credentials: true. The probes are stored in the
+// page history and their outcome as a json array as the name of the action
+// (corsprobe by default), with whether an origin is vulnerable in <name>_vulnerable,
+// the comma separated vulnerable kinds in <name>_kinds and the first vulnerable
+// origin with its cors headers in <name>_origin, <name>_allow_origin and
+// <name>_allow_credentials.
+func (p *Page) CORSProbe(act *Action, out map[string]string, baseURL *url.URL) error {
+	URL := p.getActionArgWithDefaultValues(act, "url")
+	if URL == "" {
+		URL = "{{BaseURL}}"
+	}
+	target, err := url.Parse(navigationURL(URL, baseURL))
+	if err != nil || target.Host == "" {
+		return errors.Errorf("invalid url %s", URL)
+	}
+	method := strings.ToUpper(p.getActionArgWithDefaultValues(act, "method"))
+	if method == "" {
+		method = http.MethodGet
+	}
+	attacker := p.getActionArgWithDefaultValues(act, "origin")
+	if attacker == "" {
+		attacker = defaultCORSProbeOrigin
+	}
+	attackerURL, err := url.Parse(attacker)
+	if err != nil || attackerURL.Scheme == "" || attackerURL.Host == "" {
+		return errors.Errorf("invalid origin %s", attacker)
+	}
+	kinds := corsProbeKinds
+	if value := p.getActionArgWithDefaultValues(act, "kinds"); value != "" {
+		kinds = nil
+		for _, kind := range strings.Split(value, ",") {
+			kind = strings.ToLower(strings.TrimSpace(kind))
+			if !sliceutil.Contains(corsProbeKinds, kind) {
+				return errors.Errorf("invalid kind %s, expected one of %s", kind, strings.Join(corsProbeKinds, ","))
+			}
+			kinds = append(kinds, kind)
+		}
+	}
+
+	headers := make(http.Header)
+	if userAgent, err := p.page.Eval("() => navigator.userAgent"); err == nil {
+		headers.Set("User-Agent", userAgent.Value.Str())
+	}
+	if cookies, err := p.page.Cookies([]string{target.String()}); err == nil && len(cookies) > 0 {
+		values := make([]string, 0, len(cookies))
+		for _, cookie := range cookies {
+			values = append(values, cookie.Name+"="+cookie.Value)
+		}
+		headers.Set("Cookie", strings.Join(values, "; "))
+	}
+
+	results := make([]corsProbeResult, 0, len(kinds))
+	for _, kind := range kinds {
+		result := corsProbeResult{Kind: kind, Origin: corsProbeOrigin(kind, target, attackerURL)}
+		if err := p.corsProbe(method, target.String(), headers, &result); err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal cors probes")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "corsprobe"
+	}
+	out[name] = string(data)
+	out[name+"_origin"] = ""
+	out[name+"_allow_origin"] = ""
+	out[name+"_allow_credentials"] = ""
+	var vulnerable []string
+	for _, result := range results {
+		if !result.Vulnerable {
+			continue
+		}
+		if len(vulnerable) == 0 {
+			out[name+"_origin"] = result.Origin
+			out[name+"_allow_origin"] = result.AllowOrigin
+			out[name+"_allow_credentials"] = result.AllowCredentials
+		}
+		vulnerable = append(vulnerable, result.Kind)
+	}
+	out[name+"_vulnerable"] = strconv.FormatBool(len(vulnerable) > 0)
+	out[name+"_kinds"] = strings.Join(vulnerable, ",")
+	return nil
+}
+
+// corsProbe sends a cors probe request with the origin of the result, storing
+// the request in the page history and the cors headers of the response in the result.
+func (p *Page) corsProbe(method, target string, headers http.Header, result *corsProbeResult) error {
+	req, err := http.NewRequestWithContext(p.page.GetContext(), method, target, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+	req.Header = headers.Clone()
+	req.Header.Set("Origin", result.Origin)
+	var rawReq string
+	if raw, err := httputil.DumpRequestOut(req, false); err == nil {
+		rawReq = string(raw)
+	}
+
+	resp, err := p.instance.browser.httpclient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not send request")
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "could not read response")
+	}
+	result.StatusCode = resp.StatusCode
+	result.AllowOrigin = resp.Header.Get("Access-Control-Allow-Origin")
+	result.AllowCredentials = resp.Header.Get("Access-Control-Allow-Credentials")
+	result.Vulnerable = result.AllowOrigin == result.Origin && strings.EqualFold(strings.TrimSpace(result.AllowCredentials), "true")
+
+	responseBody, truncated := p.truncateResponseBody(string(body))
+	var rawResp string
+	if raw, err := httputil.DumpResponse(resp, false); err == nil {
+		rawResp = string(raw) + responseBody
+	}
+	p.addToHistory(HistoryData{
+		RawRequest:      rawReq,
+		RawResponse:     rawResp,
+		Method:          method,
+		URL:             target,
+		ResourceType:    proto.NetworkResourceTypeOther,
+		StatusCode:      resp.StatusCode,
+		ResponseBody:    responseBody,
+		ResponseHeaders: resp.Header,
+		Truncated:       truncated,
+	})
+	return nil
+}
+
+// PrototypePollution detects client-side prototype pollution by navigating to url
+// ({{BaseURL}} by default) with prototype pollution payloads (__proto__[key]=value,
+// __proto__.key=value and their constructor.prototype variants) in the sources
+// (query and fragment by default), each with its own property named after the
+// marker (a random one by default), while the properties are hooked on
+// Object.prototype at the start of every document to observe the ones the page
+// pollutes. The page is observed until a property is polluted or the timeout
+// expires, bounded by the page timeout, and the properties are then deleted.
+//
+// The output contains true or false as the name of the action (prototypepollution
+// by default), with the source of the first polluted property in <name>_source,
+// its payload path in <name>_path and its name in <name>_key, all the polluted
+// properties in <name>_polluted as json and the navigated url in <name>_url.
+func (p *Page) PrototypePollution(act *Action, out map[string]string, baseURL *url.URL) error {
+	marker := strings.ToLower(p.getActionArgWithDefaultValues(act, "marker"))
+	if marker == "" {
+		marker = "nuclei" + strings.ToLower(ksuid.New().String())
+	}
+	sources := prototypePollutionSources
+	if value := p.getActionArgWithDefaultValues(act, "sources"); value != "" {
+		sources = nil
+		for _, source := range strings.Split(value, ",") {
+			if source = strings.TrimSpace(source); source == "" {
+				continue
+			}
+			if !sliceutil.Contains(prototypePollutionSources, source) {
+				return errors.Errorf("unsupported source %s, expected query or fragment", source)
+			}
+			sources = append(sources, source)
+		}
+	}
+	URL := p.getActionArgWithDefaultValues(act, "url")
+	if URL == "" {
+		URL = "{{BaseURL}}"
+	}
+	vectors := prototypePollutionVectors(marker, sources)
+	target, err := injectPrototypePollution(navigationURL(URL, baseURL), vectors, marker)
+	if err != nil {
+		return errors.Wrap(err, "could not parse url")
+	}
+	timeout, err := getTimeout(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	pollTime, err := getPollTime(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong polling time given")
+	}
+
+	keys := make([]string, 0, len(vectors))
+	for _, vector := range vectors {
+		keys = append(keys, vector.Key)
+	}
+	arguments, err := json.Marshal(keys)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal hook arguments")
+	}
+	removeHook, err := p.page.EvalOnNewDocument(fmt.Sprintf("(%s)(%s)", prototypePollutionHookJS, arguments))
+	if err != nil {
+		return errors.Wrap(err, "could not hook prototype")
+	}
+	defer func() {
+		_ = removeHook()
+	}()
+	if err := p.page.Navigate(target); err != nil {
+		return errors.Wrap(err, "could not navigate")
+	}
+
+	deadline := time.Now().Add(timeout)
+	if pageDeadline, ok := p.page.GetContext().Deadline(); ok && pageDeadline.Before(deadline) {
+		deadline = pageDeadline
+	}
+	polluted := []pollutedProperty{}
+	for {
+		// the document may be replaced while polling, in which case the properties are polled again
+		if result, err := p.page.Eval(prototypePollutionResultJS, keys); err == nil {
+			_ = result.Value.Unmarshal(&polluted)
+		}
+		if len(polluted) > 0 || time.Now().Add(pollTime).After(deadline) {
+			break
+		}
+		time.Sleep(pollTime)
+	}
+	_, _ = p.page.Eval(prototypePollutionCleanupJS, keys)
+
+	for i, property := range polluted {
+		for _, vector := range vectors {
+			if vector.Key == property.Key {
+				polluted[i].Source, polluted[i].Path = vector.Source, vector.Path
+			}
+		}
+	}
+	data, err := json.Marshal(polluted)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal polluted properties")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "prototypepollution"
+	}
+	out[name] = strconv.FormatBool(len(polluted) > 0)
+	out[name+"_source"] = ""
+	out[name+"_path"] = ""
+	out[name+"_key"] = ""
+	if len(polluted) > 0 {
+		out[name+"_source"] = polluted[0].Source
+		out[name+"_path"] = polluted[0].Path
+		out[name+"_key"] = polluted[0].Key
+	}
+	out[name+"_polluted"] = string(data)
+	out[name+"_url"] = target
+	return nil
+}
+
+// fillFormJS fills the fields of the form bound to this, or containing it,
+// and submits it. Values are set with the native setters and input and change
+// events are dispatched so that frameworks tracking the fields see the values.
+const fillFormJS = `(fields, submit) => {
+	const form = this instanceof HTMLFormElement ? this : this.closest('form');
+	if (!form) {
+		return {error: 'element is not in a form'};
+	}
+	const missing = [];
+	const unsupported = [];
+	const setValue = (element, value) => {
+		const prototype = Object.getPrototypeOf(element);
+		const descriptor = Object.getOwnPropertyDescriptor(prototype, 'value');
+		if (descriptor && descriptor.set) {
+			descriptor.set.call(element, value);
+		} else {
+			element.value = value;
+		}
+	};
+	const notify = (element) => {
+		element.dispatchEvent(new Event('input', {bubbles: true}));
+		element.dispatchEvent(new Event('change', {bubbles: true}));
+	};
+	for (const [name, value] of fields) {
+		const item = form.elements.namedItem(name);
+		if (!item) {
+			missing.push(name);
+			continue;
+		}
+		const elements = item instanceof RadioNodeList ? Array.from(item) : [item];
+		for (const element of elements) {
+			const type = (element.type || '').toLowerCase();
+			if (type === 'file') {
+				unsupported.push(name);
+			} else if (type === 'checkbox' || type === 'radio') {
+				element.checked = elements.length > 1 ? element.value === value : !['', 'false', 'off', '0'].includes(value.toLowerCase());
+			} else if (element instanceof HTMLSelectElement) {
+				const option = Array.from(element.options).find(option => option.value === value) || Array.from(element.options).find(option => option.text.trim() === value);
+				element.value = option ? option.value : value;
+			} else {
+				setValue(element, value);
+			}
+			notify(element);
+		}
+	}
+	if (missing.length > 0 || unsupported.length > 0) {
+		return {missing: missing, unsupported: unsupported};
+	}
+	const result = {action: form.action, method: (form.getAttribute('method') || 'get').toUpperCase()};
+	if (submit === 'auto') {
+		const button = Array.from(form.elements).find(element => element.type === 'submit' && !element.disabled);
+		if (button) {
+			button.click();
+		} else if (form.requestSubmit) {
+			form.requestSubmit();
+		} else {
+			HTMLFormElement.prototype.submit.call(form);
+		}
+	} else if (submit === 'script') {
+		HTMLFormElement.prototype.submit.call(form);
+	}
+	return result;
+}`
+
+// fillFormResult is the outcome of filling a form
+type fillFormResult struct {
+	Error       string   `json:"error"`
+	Missing     []string `json:"missing"`
+	Unsupported []string `json:"unsupported"`
+	Action      string   `json:"action"`
+	Method      string   `json:"method"`
+}
+
+// FillForm fills the fields of a form, selected like the other elements and
+// the first form of the page by default, and submits it.
+//
+// The fields are given as newline separated name=value pairs, with the values
+// used as is and supporting payloads and dynamic values. Inputs, textareas and selects are
+// filled by name, checkboxes and radios are checked by value and the fields
+// not given are left untouched. The form is submitted by clicking its submit
+// button, or requesting its submission if it has none, unless submit is set
+// to script to call form.submit() or none to only fill it.
+//
+// The action url and method of the form are stored in the output as the name
+// of the action and <name>_method if the action is named.
+func (p *Page) FillForm(act *Action, out map[string]string) error {
+	fields, err := parseFormFields(p.getActionArgWithDefaultValues(act, "fields"))
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return errinvalidArguments
+	}
+	submit := strings.ToLower(p.getActionArgWithDefaultValues(act, "submit"))
+	if submit == "" {
+		submit = "auto"
+	}
+	if submit != "auto" && submit != "script" && submit != "none" {
+		return fmt.Errorf("invalid submit mode %s", submit)
+	}
+
+	data := act.Data
+	if data["by"] == "" && data["selector"] == "" {
+		data = map[string]string{"selector": "form"}
+	}
+	element, err := p.pageElementBy(data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	if err = element.ScrollIntoView(); err != nil {
+		return errors.Wrap(err, errCouldNotScroll)
+	}
+	value, err := element.Eval(fillFormJS, fields, submit)
+	if err != nil {
+		return errors.Wrap(err, "could not fill form")
+	}
+	result := &fillFormResult{}
+	if err := value.Value.Unmarshal(result); err != nil {
+		return errors.Wrap(err, "could not unmarshal form result")
+	}
+	switch {
+	case result.Error != "":
+		return errors.New(result.Error)
+	case len(result.Missing) > 0:
+		return fmt.Errorf("could not find form fields %s", strings.Join(result.Missing, ", "))
+	case len(result.Unsupported) > 0:
+		return fmt.Errorf("could not fill file inputs %s, use the files action instead", strings.Join(result.Unsupported, ", "))
+	}
+	if act.Name != "" {
+		out[act.Name] = result.Action
+		out[act.Name+"_method"] = result.Method
+	}
+	return nil
+}
+
+// parseFormFields parses the newline separated name=value fields of a form
+// returning them as name and value pairs in order.
+func parseFormFields(fields string) ([][2]string, error) {
+	var pairs [][2]string
+	for _, line := range strings.Split(fields, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid form field %s", strings.TrimSpace(line))
+		}
+		pairs = append(pairs, [2]string{strings.TrimSpace(name), strings.TrimRight(value, "\r")})
+	}
+	return pairs, nil
+}
+
+// defaultAXTreeLimit is the default maximum number of nodes of the accessibility tree captured
+const defaultAXTreeLimit = 1000
+
+// axNode is a node of the accessibility tree of a page
+type axNode struct {
+	Role        string `json:"role"`
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Properties are the states and attributes of the node, e.g. focusable or hidden
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// AccessibilityTree captures the accessibility tree of the page once it is loaded,
+// which exposes the interactive elements with their computed roles and names even
+// when they are visually hidden.
+//
+// The nodes ignored for accessibility are skipped and, if roles is given, only
+// the nodes with one of the comma separated roles are kept. At most limit nodes
+// (1000 by default) are captured, up to depth levels of the tree if given.
+//
+// The nodes are stored as json in the output as the name of the action (axtree
+// by default), along with their role: name pairs one per line in <name>_text,
+// their number in <name>_count and <name>_truncated if the limit was reached.
+func (p *Page) AccessibilityTree(act *Action, out map[string]string) error {
+	limit := defaultAXTreeLimit
+	if value := p.getActionArgWithDefaultValues(act, "limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return errors.Errorf("invalid node limit %s", value)
+		}
+		limit = parsed
+	}
+	request := proto.AccessibilityGetFullAXTree{}
+	if value := p.getActionArgWithDefaultValues(act, "depth"); value != "" {
+		depth, err := strconv.Atoi(value)
+		if err != nil || depth < 0 {
+			return errors.Errorf("invalid tree depth %s", value)
+		}
+		request.Depth = &depth
+	}
+	var roles map[string]struct{}
+	if value := p.getActionArgWithDefaultValues(act, "roles"); value != "" {
+		roles = make(map[string]struct{})
+		for _, role := range strings.Split(value, ",") {
+			if role = strings.ToLower(strings.TrimSpace(role)); role != "" {
+				roles[role] = struct{}{}
+			}
+		}
+	}
+
+	if err := p.page.WaitLoad(); err != nil {
+		return errors.Wrap(err, "could not wait for page to load")
+	}
+	result, err := request.Call(p.page)
+	if err != nil {
+		return errors.Wrap(err, "could not get accessibility tree")
+	}
+	nodes, truncated := flattenAXTree(result.Nodes, roles, limit)
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal accessibility tree")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "axtree"
+	}
+	lines := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		lines = append(lines, node.Role+": "+node.Name)
+	}
+	out[name] = string(data)
+	out[name+"_text"] = strings.Join(lines, "\n")
+	out[name+"_count"] = strconv.Itoa(len(nodes))
+	out[name+"_truncated"] = strconv.FormatBool(truncated)
+	return nil
+}
+
+// flattenAXTree returns at most limit nodes of an accessibility tree which are
+// not ignored, and have one of the roles if any, and whether nodes were left out
+// because of the limit. Inline text boxes, which duplicate their static text, are skipped.
+func flattenAXTree(tree []*proto.AccessibilityAXNode, roles map[string]struct{}, limit int) ([]axNode, bool) {
+	nodes := []axNode{}
+	for _, item := range tree {
+		if item.Ignored {
+			continue
+		}
+		role := axValueString(item.Role)
+		if role == "" || role == "InlineTextBox" {
+			continue
+		}
+		if _, ok := roles[strings.ToLower(role)]; roles != nil && !ok {
+			continue
+		}
+		if len(nodes) == limit {
+			return nodes, true
+		}
+		node := axNode{
+			Role:        role,
+			Name:        axValueString(item.Name),
+			Value:       axValueString(item.Value),
+			Description: axValueString(item.Description),
+		}
+		for _, property := range item.Properties {
+			if node.Properties == nil {
+				node.Properties = make(map[string]string)
+			}
+			node.Properties[string(property.Name)] = axValueString(property.Value)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, false
+}
+
+// axValueString returns an accessibility value as a string, empty if it has none
+func axValueString(value *proto.AccessibilityAXValue) string {
+	if value == nil || value.Value.Nil() {
+		return ""
+	}
+	return value.Value.Str()
+}
+
+// domXSSSinks are the dangerous sinks instrumented by the domxss action
+var domXSSSinks = []string{"innerHTML", "outerHTML", "insertAdjacentHTML", "document.write", "document.writeln", "eval", "Function", "setTimeout", "setInterval"}
+
+// domXSSHookJS wraps the enabled sinks of every document to record the string
+// values containing the marker they receive in window.__nucleiDOMXSS.
+const domXSSHookJS = `(marker, sinks) => {
+	if (window.__nucleiDOMXSS) {
+		return;
+	}
+	const found = window.__nucleiDOMXSS = [];
+	const report = (sink, value) => {
+		if (typeof value === 'string' && value.toLowerCase().includes(marker)) {
+			found.push({sink: sink, value: value.slice(0, 2048)});
+		}
+	};
+	const wrapSetter = (owner, property) => {
+		const descriptor = Object.getOwnPropertyDescriptor(owner, property);
+		if (!sinks.includes(property) || !descriptor || !descriptor.set) {
+			return;
+		}
+		Object.defineProperty(owner, property, {
+			configurable: true,
+			enumerable: descriptor.enumerable,
+			get: descriptor.get,
+			set: function (value) {
+				report(property, value);
+				return descriptor.set.call(this, value);
+			},
+		});
+	};
+	const wrapMethod = (owner, property, sink) => {
+		const original = owner[property];
+		if (!sinks.includes(sink) || typeof original !== 'function') {
+			return;
+		}
+		const wrapper = function (...args) {
+			args.forEach(arg => report(sink, arg));
+			return new.target ? Reflect.construct(original, args, new.target) : original.apply(this, args);
+		};
+		wrapper.prototype = original.prototype;
+		owner[property] = wrapper;
+	};
+	wrapSetter(Element.prototype, 'innerHTML');
+	wrapSetter(ShadowRoot.prototype, 'innerHTML');
+	wrapSetter(Element.prototype, 'outerHTML');
+	wrapMethod(Element.prototype, 'insertAdjacentHTML', 'insertAdjacentHTML');
+	wrapMethod(Document.prototype, 'write', 'document.write');
+	wrapMethod(Document.prototype, 'writeln', 'document.writeln');
+	wrapMethod(window, 'eval', 'eval');
+	wrapMethod(window, 'Function', 'Function');
+	wrapMethod(window, 'setTimeout', 'setTimeout');
+	wrapMethod(window, 'setInterval', 'setInterval');
+}`
+
+// taintedSink is a sink which received the tainted value
+type taintedSink struct {
+	Sink  string `json:"sink"`
+	Value string `json:"value"`
+}
+
+// DOMXSS detects dom based xss by navigating to url with the payload in the
+// comma separated query params, or in the fragment if fragment is true or no
+// params are given, while the dangerous sinks of every document are hooked to
+// observe the values containing the marker they receive.
+//
+// The marker (a random one by default) is the tainted value looked for, and the
+// payload defaults to an html tag with the marker as id. The sinks are observed
+// until one received the marker or the timeout expires, bounded by the page timeout.
+//
+// The output contains true or false as the name of the action (domxss by default),
+// with the first sink receiving the marker in <name>_sink and its value in
+// <name>_evidence, all the sinks in <name>_sinks as json, <name>_verbatim if the
+// payload reached a sink unmodified and the navigated url in <name>_url.
+func (p *Page) DOMXSS(act *Action, out map[string]string, baseURL *url.URL) error {
+	marker := strings.ToLower(p.getActionArgWithDefaultValues(act, "marker"))
+	if marker == "" {
+		marker = "nuclei" + strings.ToLower(ksuid.New().String())
+	}
+	payload := p.getActionArgWithDefaultValues(act, "payload")
+	if payload == "" {
+		payload = "'\"><img src=x id=" + marker + ">"
+	}
+	sinks := domXSSSinks
+	if value := p.getActionArgWithDefaultValues(act, "sinks"); value != "" {
+		sinks = nil
+		for _, sink := range strings.Split(value, ",") {
+			if sink = strings.TrimSpace(sink); sink == "" {
+				continue
+			}
+			if !sliceutil.Contains(domXSSSinks, sink) {
+				return errors.Errorf("unsupported sink %s", sink)
+			}
+			sinks = append(sinks, sink)
+		}
+	}
+	URL := p.getActionArgWithDefaultValues(act, "url")
+	if URL == "" {
+		URL = "{{BaseURL}}"
+	}
+	params := p.getActionArgWithDefaultValues(act, "params")
+	target, err := injectRedirectPayload(navigationURL(URL, baseURL), params, payload)
+	if err != nil {
+		return err
+	}
+	if params == "" || p.getActionArgWithDefaultValues(act, "fragment") == "true" {
+		target = strings.SplitN(target, "#", 2)[0] + "#" + payload
+	}
+	timeout, err := getTimeout(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	pollTime, err := getPollTime(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong polling time given")
+	}
+
+	arguments, err := json.Marshal([]interface{}{marker, sinks})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal hook arguments")
+	}
+	hook := fmt.Sprintf("(%s)(...%s)", domXSSHookJS, arguments)
+	removeHook, err := p.page.EvalOnNewDocument(hook)
+	if err != nil {
+		return errors.Wrap(err, "could not hook sinks")
+	}
+	defer func() {
+		_ = removeHook()
+	}()
+	if err := p.page.Navigate(target); err != nil {
+		return errors.Wrap(err, "could not navigate")
+	}
+
+	deadline := time.Now().Add(timeout)
+	if pageDeadline, ok := p.page.GetContext().Deadline(); ok && pageDeadline.Before(deadline) {
+		deadline = pageDeadline
+	}
+	tainted := []taintedSink{}
+	for {
+		// the document may be replaced while polling, in which case the sinks are polled again
+		if result, err := p.page.Eval("() => window.__nucleiDOMXSS || []"); err == nil {
+			_ = result.Value.Unmarshal(&tainted)
+		}
+		if len(tainted) > 0 || time.Now().Add(pollTime).After(deadline) {
+			break
+		}
+		time.Sleep(pollTime)
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "domxss"
+	}
+	data, err := json.Marshal(tainted)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal tainted sinks")
+	}
+	out[name] = strconv.FormatBool(len(tainted) > 0)
+	out[name+"_sink"] = ""
+	out[name+"_evidence"] = ""
+	if len(tainted) > 0 {
+		out[name+"_sink"] = tainted[0].Sink
+		out[name+"_evidence"] = tainted[0].Value
+	}
+	verbatim := false
+	for _, item := range tainted {
+		verbatim = verbatim || strings.Contains(item.Value, payload)
+	}
+	out[name+"_sinks"] = string(data)
+	out[name+"_verbatim"] = strconv.FormatBool(verbatim)
+	out[name+"_url"] = target
+	return nil
+}
+
+// webVitalsObserverJS records the largest contentful paint, the layout shifts and
+// the long tasks of a document from its start in window.__nucleiWebVitals.
+const webVitalsObserverJS = `() => {
+	if (window.__nucleiWebVitals) {
+		return;
+	}
+	const vitals = window.__nucleiWebVitals = {lcp: 0, cls: 0, longTasks: []};
+	const observe = (type, callback) => {
+		try {
+			new PerformanceObserver(list => list.getEntries().forEach(callback)).observe({type, buffered: true});
+		} catch (e) {}
+	};
+	observe('largest-contentful-paint', entry => { vitals.lcp = entry.startTime; });
+	observe('layout-shift', entry => {
+		if (!entry.hadRecentInput) {
+			vitals.cls += entry.value;
+		}
+	});
+	observe('longtask', entry => { vitals.longTasks.push({start: entry.startTime, duration: entry.duration}); });
+}`
+
+// webVitals are the web vitals and long tasks recorded for a document
+type webVitals struct {
+	// LCP is the largest contentful paint time in milliseconds
+	LCP float64 `json:"lcp"`
+	// CLS is the cumulative layout shift score
+	CLS float64 `json:"cls"`
+	// LongTasks are the tasks blocking the main thread for more than 50ms
+	LongTasks []longTask `json:"longTasks"`
+}
+
+// longTask is a task blocking the main thread for more than 50ms
+type longTask struct {
+	// Start is the start time of the task in milliseconds
+	Start float64 `json:"start"`
+	// Duration is the duration of the task in milliseconds
+	Duration float64 `json:"duration"`
+}
+
+// WebVitals navigates to url ({{BaseURL}} by default) with an observer injected
+// at the start of every document, and collects the largest contentful paint,
+// the cumulative layout shift and the long tasks of the page after observing
+// it for window seconds (5 by default), bounded by the page timeout.
+//
+// The output contains the vitals as json as the name of the action (web_vitals
+// by default), along with the numeric <name>_lcp and <name>_cls, the number of
+// long tasks in <name>_long_tasks, their total duration in <name>_long_tasks_duration
+// and the duration of the longest one in <name>_longest_task (in milliseconds).
+func (p *Page) WebVitals(act *Action, out map[string]string, baseURL *url.URL) error {
+	URL := p.getActionArgWithDefaultValues(act, "url")
+	if URL == "" {
+		URL = "{{BaseURL}}"
+	}
+	window, err := geTimeParameter(p, act, "window", 5, time.Second)
+	if err != nil {
+		return errors.Wrap(err, "Wrong observation window given")
+	}
+
+	removeObserver, err := p.page.EvalOnNewDocument(fmt.Sprintf("(%s)()", webVitalsObserverJS))
+	if err != nil {
+		return errors.Wrap(err, "could not inject web vitals observer")
+	}
+	defer func() {
+		_ = removeObserver()
+	}()
+	if err := p.page.Navigate(navigationURL(URL, baseURL)); err != nil {
+		return errors.Wrap(err, "could not navigate")
+	}
+
+	deadline := time.Now().Add(window)
+	if pageDeadline, ok := p.page.GetContext().Deadline(); ok && pageDeadline.Add(-time.Second).Before(deadline) {
+		// keep some time to read the vitals before the page times out
+		deadline = pageDeadline.Add(-time.Second)
+	}
+	time.Sleep(time.Until(deadline))
+
+	result, err := p.page.Eval("() => window.__nucleiWebVitals")
+	if err != nil {
+		return errors.Wrap(err, "could not read web vitals")
+	}
+	vitals := &webVitals{}
+	if err := result.Value.Unmarshal(vitals); err != nil {
+		return errors.Wrap(err, "could not unmarshal web vitals")
+	}
+	data, err := json.Marshal(vitals)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal web vitals")
+	}
+
+	var total, longest float64
+	for _, task := range vitals.LongTasks {
+		total += task.Duration
+		if task.Duration > longest {
+			longest = task.Duration
+		}
+	}
+	name := act.Name
+	if name == "" {
+		name = "web_vitals"
+	}
+	out[name] = string(data)
+	out[name+"_lcp"] = strconv.FormatFloat(vitals.LCP, 'f', 0, 64)
+	out[name+"_cls"] = strconv.FormatFloat(vitals.CLS, 'f', 4, 64)
+	out[name+"_long_tasks"] = strconv.Itoa(len(vitals.LongTasks))
+	out[name+"_long_tasks_duration"] = strconv.FormatFloat(total, 'f', 0, 64)
+	out[name+"_longest_task"] = strconv.FormatFloat(longest, 'f', 0, 64)
+	return nil
+}
+
+// defaultEndpointPatterns match the quoted urls and paths found in scripts
+var defaultEndpointPatterns = []*regexp.Regexp{
+	regexp.MustCompile("[\"'`]((?:https?:)?//[^\"'`\\s<>]+)[\"'`]"),
+	regexp.MustCompile("[\"'`](/[a-zA-Z0-9_\\-][^\"'`\\s<>]*)[\"'`]"),
+	regexp.MustCompile("[\"'`](\\.{1,2}/[^\"'`\\s<>]+)[\"'`]"),
+	regexp.MustCompile("[\"'`]([a-zA-Z0-9_\\-/.]+\\.(?:php|asp|aspx|jsp|json|action|do|cgi)(?:\\?[^\"'`\\s<>]*)?)[\"'`]"),
+}
+
+// inlineScriptRegex matches the inline scripts of a document
+var inlineScriptRegex = regexp.MustCompile(`(?is)<script[^>]*>(.*?)</script>`)
+
+// ExtractJSEndpoints extracts the endpoints referenced by the scripts loaded by
+// the page and the inline scripts of its documents as a json array of absolute urls.
+//
+// Endpoints are matched with the newline separated regexes of the patterns
+// argument, using the first capture group if any, or the default patterns.
+// They are resolved against the url of the script and deduplicated.
+func (p *Page) ExtractJSEndpoints(act *Action, out map[string]string) error {
+	patterns := defaultEndpointPatterns
+	if value := act.GetArg("patterns"); value != "" {
+		patterns = nil
+		for _, pattern := range strings.Split(value, "\n") {
+			if pattern = strings.TrimSpace(pattern); pattern == "" {
+				continue
+			}
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return errors.Wrapf(err, "could not compile endpoint pattern %s", pattern)
+			}
+			patterns = append(patterns, compiled)
+		}
+	}
+
+	p.mutex.RLock()
+	history := p.History
+	p.mutex.RUnlock()
+
+	endpoints := []string{}
+	seen := make(map[string]struct{})
+	for _, historyData := range history {
+		var scripts []string
+		switch historyData.ResourceType {
+		case proto.NetworkResourceTypeScript:
+			scripts = []string{historyData.ResponseBody}
+		case proto.NetworkResourceTypeDocument:
+			for _, match := range inlineScriptRegex.FindAllStringSubmatch(historyData.ResponseBody, -1) {
+				scripts = append(scripts, match[1])
+			}
+		default:
+			continue
+		}
+		base, err := url.Parse(historyData.URL)
+		if err != nil {
+			continue
+		}
+		for _, script := range scripts {
+			for _, endpoint := range matchEndpoints(script, patterns) {
+				resolved, ok := normalizeEndpoint(base, endpoint)
+				if !ok {
+					continue
+				}
+				if _, ok := seen[resolved]; ok {
+					continue
+				}
+				seen[resolved] = struct{}{}
+				endpoints = append(endpoints, resolved)
+			}
+		}
+	}
+	if act.Name != "" {
+		data, err := json.Marshal(endpoints)
+		if err != nil {
+			return errors.Wrap(err, "could not marshal endpoints")
+		}
+		out[act.Name] = string(data)
+	}
+	return nil
+}
+
+// matchEndpoints returns the endpoints matched by the patterns in a script
+func matchEndpoints(script string, patterns []*regexp.Regexp) []string {
+	var endpoints []string
+	for _, pattern := range patterns {
+		for _, match := range pattern.FindAllStringSubmatch(script, -1) {
+			if len(match) > 1 {
+				endpoints = append(endpoints, match[1])
+			} else {
+				endpoints = append(endpoints, match[0])
+			}
+		}
+	}
+	return endpoints
+}
+
+// normalizeEndpoint resolves an endpoint against the url of the script it
+// was found in, returning false if it is not a http(s) url.
+func normalizeEndpoint(base *url.URL, endpoint string) (string, bool) {
+	parsed, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil {
+		return "", false
+	}
+	resolved := base.ResolveReference(parsed)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" || resolved.Host == "" {
+		return "", false
+	}
+	resolved.Fragment = ""
+	return resolved.String(), true
+}
+
+// mixedContent is an insecure subresource loaded by a https page
+type mixedContent struct {
+	// URL is the url of the subresource
+	URL string `json:"url"`
+	// Type is the resource type of the subresource (Script, Image, etc)
+	Type string `json:"type"`
+	// Active is true for subresources able to alter the page (scripts, stylesheets,
+	// frames, etc) and false for passive ones (images and media)
+	Active bool `json:"active"`
+}
+
+// passiveResourceTypes are the resource types of optionally-blockable (passive) mixed content
+var passiveResourceTypes = map[proto.NetworkResourceType]struct{}{
+	proto.NetworkResourceTypeImage: {},
+	proto.NetworkResourceTypeMedia: {},
+}
+
+// MixedContent detects the insecure (http or ws) subresources loaded by the
+// current https document of the page from the requests captured in its history.
+//
+// The mixed content is stored as json in the output as the name of the action
+// (mixed_content by default), along with <name>_found, <name>_active and
+// <name>_passive booleans for matching.
+func (p *Page) MixedContent(act *Action, out map[string]string) error {
+	info, err := p.page.Info()
+	if err != nil {
+		return errors.Wrap(err, "could not get page info")
+	}
+	p.mutex.RLock()
+	history := p.History
+	p.mutex.RUnlock()
+
+	items := findMixedContent(history, info.URL)
+	var active, passive bool
+	for _, item := range items {
+		if item.Active {
+			active = true
+		} else {
+			passive = true
+		}
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal mixed content")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "mixed_content"
+	}
+	out[name] = string(data)
+	out[name+"_found"] = strconv.FormatBool(len(items) > 0)
+	out[name+"_active"] = strconv.FormatBool(active)
+	out[name+"_passive"] = strconv.FormatBool(passive)
+	return nil
+}
+
+// findMixedContent returns the insecure subresources requested after the
+// last load of the document at pageURL, if it is a https document.
+func findMixedContent(history []HistoryData, pageURL string) []mixedContent {
+	items := []mixedContent{}
+	page, err := url.Parse(pageURL)
+	if err != nil || page.Scheme != "https" {
+		return items
+	}
+	page.Fragment = ""
+
+	// skip requests of previous documents, including insecure redirects to the page
+	start := 0
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].ResourceType != proto.NetworkResourceTypeDocument {
+			continue
+		}
+		if documentURL, err := url.Parse(history[i].URL); err == nil {
+			documentURL.Fragment = ""
+			if documentURL.String() == page.String() {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	seen := make(map[string]struct{})
+	for _, historyData := range history[start:] {
+		resource, err := url.Parse(historyData.URL)
+		if err != nil || (resource.Scheme != "http" && resource.Scheme != "ws") {
+			continue
+		}
+		if _, ok := seen[historyData.URL]; ok {
+			continue
+		}
+		seen[historyData.URL] = struct{}{}
+		_, passive := passiveResourceTypes[historyData.ResourceType]
+		items = append(items, mixedContent{URL: historyData.URL, Type: string(historyData.ResourceType), Active: !passive})
+	}
+	return items
+}
+
+// cspSources are the nonces and hashes of the content security policy of a document response
+type cspSources struct {
+	// URL is the url of the document
+	URL string `json:"url"`
+	// Nonces are the nonce sources of the policy
+	Nonces []string `json:"nonces,omitempty"`
+	// Hashes are the hash sources of the policy (sha256, sha384 or sha512)
+	Hashes []string `json:"hashes,omitempty"`
+}
+
+// cspHashPrefixes are the prefixes of the hash sources of a content security policy
+var cspHashPrefixes = []string{"sha256-", "sha384-", "sha512-"}
+
+// CSPNonce reloads the page and detects the content security policy nonces
+// reused across the document responses captured in its history. A nonce
+// must be unique for every response, otherwise it doesn't protect against
+// injected scripts anymore.
+//
+// The page is reloaded reloads times (1 by default, 0 to only use the
+// history). The nonces and hashes of the policies are stored as json in
+// the output as the name of the action (csp_nonce by default), along with
+// <name>_reused, the comma separated reused nonces, and <name>_found.
+func (p *Page) CSPNonce(act *Action, out map[string]string) error {
+	reloads := 1
+	if value := p.getActionArgWithDefaultValues(act, "reloads"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return errors.Errorf("invalid reloads %s", value)
+		}
+		reloads = parsed
+	}
+	for i := 0; i < reloads; i++ {
+		if err := p.page.Reload(); err != nil {
+			return errors.Wrap(err, "could not reload page")
+		}
+		if err := p.WaitLoad(act, out); err != nil {
+			return err
+		}
+	}
+	p.mutex.RLock()
+	history := p.History
+	p.mutex.RUnlock()
+
+	policies, reused := findCSPNonceReuse(history)
+	data, err := json.Marshal(policies)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal csp sources")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "csp_nonce"
+	}
+	out[name] = string(data)
+	out[name+"_reused"] = strings.Join(reused, ",")
+	out[name+"_found"] = strconv.FormatBool(len(reused) > 0)
+	return nil
+}
+
+// findCSPNonceReuse returns the content security policy sources of the document
+// responses in history and the nonces used by more than one of them.
+func findCSPNonceReuse(history []HistoryData) ([]cspSources, []string) {
+	policies := []cspSources{}
+	responses := make(map[string]int)
+	reused := []string{}
+	for _, historyData := range history {
+		if historyData.ResourceType != proto.NetworkResourceTypeDocument {
+			continue
+		}
+		var headers []string
+		headers = append(headers, historyData.ResponseHeaders.Values("Content-Security-Policy")...)
+		headers = append(headers, historyData.ResponseHeaders.Values("Content-Security-Policy-Report-Only")...)
+		if len(headers) == 0 {
+			continue
+		}
+		sources := parseCSPSources(strings.Join(headers, ";"))
+		sources.URL = historyData.URL
+		policies = append(policies, sources)
+
+		for _, nonce := range sources.Nonces {
+			if responses[nonce]++; responses[nonce] == 2 {
+				reused = append(reused, nonce)
+			}
+		}
+	}
+	return policies, reused
+}
+
+// parseCSPSources returns the unique nonce and hash sources of a content security policy
+func parseCSPSources(policy string) cspSources {
+	sources := cspSources{}
+	seen := make(map[string]struct{})
+	for _, directive := range strings.Split(policy, ";") {
+		for _, source := range strings.Fields(directive) {
+			source = strings.Trim(source, "'")
+			if _, ok := seen[source]; ok {
+				continue
+			}
+			lower := strings.ToLower(source)
+			switch {
+			case strings.HasPrefix(lower, "nonce-"):
+				sources.Nonces = append(sources.Nonces, source[len("nonce-"):])
+			case stringsutil.HasPrefixAny(lower, cspHashPrefixes...):
+				sources.Hashes = append(sources.Hashes, source)
+			default:
+				continue
+			}
+			seen[source] = struct{}{}
+		}
+	}
+	return sources
+}
+
+// clickjackingFrameJS frames the url in an iframe of the document, marking the
+// window to detect navigations of the top window, and resolves with true once
+// the iframe is loaded or false after timeout milliseconds.
+const clickjackingFrameJS = `(url, timeout) => new Promise(resolve => {
+	window.__nucleiClickjacking = true;
+	const frame = document.createElement('iframe');
+	frame.id = '__nuclei_clickjacking';
+	frame.style.cssText = 'width:1024px;height:768px';
+	const timer = setTimeout(() => resolve(false), timeout);
+	frame.onload = () => {
+		clearTimeout(timer);
+		resolve(true);
+	};
+	frame.src = url;
+	(document.body || document.documentElement).appendChild(frame);
+})`
+
+// clickjackingStateJS returns whether the iframe added by clickjackingFrameJS
+// was blocked or hidden by frame busting scripts and removes it.
+const clickjackingStateJS = `() => {
+	if (!window.__nucleiClickjacking) {
+		return {navigated: true};
+	}
+	delete window.__nucleiClickjacking;
+	const frame = document.getElementById('__nuclei_clickjacking');
+	if (!frame) {
+		return {blocked: true};
+	}
+	let document_ = null;
+	try {
+		document_ = frame.contentDocument;
+	} catch (e) {}
+	frame.remove();
+	if (!document_ || !document_.documentElement || document_.URL === 'about:blank') {
+		return {blocked: true};
+	}
+	const isHidden = element => {
+		if (!element) {
+			return false;
+		}
+		const style = document_.defaultView.getComputedStyle(element);
+		return style.display === 'none' || style.visibility === 'hidden' || Number(style.opacity) === 0;
+	};
+	return {hidden: !document_.body || isHidden(document_.documentElement) || isHidden(document_.body)};
+}`
+
+// clickjackingState is the state of the framed document
+type clickjackingState struct {
+	// Navigated is true if the top window was navigated by the framed document
+	Navigated bool `json:"navigated"`
+	// Blocked is true if the browser refused to frame the document
+	Blocked bool `json:"blocked"`
+	// Hidden is true if the framed document hid its content
+	Hidden bool `json:"hidden"`
+}
+
+// Clickjacking navigates to url ({{BaseURL}} by default) and frames it in the
+// loaded document to detect whether it can be used for clickjacking. The page
+// is left on the document, the iframe being removed once done.
+//
+// The iframe must load within timeout seconds (10 by default), and frame busting
+// scripts are given wait seconds (2 by default) to navigate the top window or
+// hide the content of the framed document. As the document frames itself, the
+// X-Frame-Options sameorigin and the frame-ancestors directive of the content
+// security policy of the document response, which only allow some origins, are
+// checked against an attacker origin.
+//
+// Whether the document can be framed by another origin is stored in the output
+// as the name of the action (clickjacking by default), along with the protection
+// preventing it in <name>_blocked_by (x-frame-options, frame-ancestors,
+// frame-busting or browser if the iframe failed to load otherwise), the headers in <name>_x_frame_options and <name>_frame_ancestors
+// and whether frame busting was detected in <name>_frame_busting.
+func (p *Page) Clickjacking(act *Action, out map[string]string, baseURL *url.URL) error {
+	URL := p.getActionArgWithDefaultValues(act, "url")
+	if URL == "" {
+		URL = "{{BaseURL}}"
+	}
+	timeout, err := geTimeParameter(p, act, "timeout", 10, time.Second)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	wait, err := geTimeParameter(p, act, "wait", 2, time.Second)
+	if err != nil {
+		return errors.Wrap(err, "Wrong wait given")
+	}
+
+	p.mutex.RLock()
+	start := len(p.History)
+	p.mutex.RUnlock()
+	target := navigationURL(URL, baseURL)
+	if err := p.page.Navigate(target); err != nil {
+		return errors.Wrap(err, "could not navigate")
+	}
+	if err := p.page.WaitLoad(); err != nil {
+		return errors.Wrap(err, "could not wait load event")
+	}
+	p.mutex.RLock()
+	headers := documentResponseHeaders(p.History[start:], p.URL())
+	p.mutex.RUnlock()
+
+	if _, err := p.page.Eval(clickjackingFrameJS, p.URL(), timeout.Milliseconds()); err != nil {
+		return errors.Wrap(err, "could not frame document")
+	}
+	time.Sleep(wait)
+	state := &clickjackingState{}
+	result, err := p.page.Eval(clickjackingStateJS)
+	if err != nil {
+		// the evaluation context is destroyed when the top window is navigated
+		state.Navigated = true
+	} else if err := result.Value.Unmarshal(state); err != nil {
+		return errors.Wrap(err, "could not unmarshal framed document state")
+	}
+
+	xFrameOptions, frameAncestors, blockedBy := clickjackingProtection(headers)
+	frameBusting := state.Navigated || state.Hidden
+	switch {
+	case frameBusting:
+		blockedBy = "frame-busting"
+	case state.Blocked && blockedBy == "":
+		// denied even to the document itself, e.g. with frame-ancestors 'none'
+		switch {
+		case frameAncestors != "":
+			blockedBy = "frame-ancestors"
+		case xFrameOptions != "":
+			blockedBy = "x-frame-options"
+		default:
+			blockedBy = "browser"
+		}
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "clickjacking"
+	}
+	out[name] = strconv.FormatBool(blockedBy == "")
+	out[name+"_blocked_by"] = blockedBy
+	out[name+"_x_frame_options"] = xFrameOptions
+	out[name+"_frame_ancestors"] = frameAncestors
+	out[name+"_frame_busting"] = strconv.FormatBool(frameBusting)
+	return nil
+}
+
+// PermissionsPolicy captures the effective Permissions-Policy of the document
+// response of the page, merged with the legacy Feature-Policy header.
+//
+// The output contains the allowlists of the declared features as json in the
+// name of the action (permissions_policy by default), the features allowing any
+// origin in <name>_allow_all and the ones disabled for every origin in
+// <name>_disabled. The allowlist of each feature is in <name>_<feature>, with
+// dashes replaced by underscores, as space separated *, self, src and origins,
+// or none if the feature is disabled. The raw headers are in <name>_header and
+// <name>_feature_policy.
+func (p *Page) PermissionsPolicy(act *Action, out map[string]string) error {
+	p.mutex.RLock()
+	headers := documentResponseHeaders(p.History, p.URL())
+	p.mutex.RUnlock()
+
+	policy := effectivePermissionsPolicy(headers)
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal permissions policy")
+	}
+	allowAll, disabled := permissionsPolicyFeatures(policy)
+
+	name := act.Name
+	if name == "" {
+		name = "permissions_policy"
+	}
+	out[name] = string(data)
+	out[name+"_header"] = strings.Join(headers.Values("Permissions-Policy"), ", ")
+	out[name+"_feature_policy"] = strings.Join(headers.Values("Feature-Policy"), ", ")
+	out[name+"_allow_all"] = strings.Join(allowAll, ",")
+	out[name+"_disabled"] = strings.Join(disabled, ",")
+	for feature, allowlist := range policy {
+		value := strings.Join(allowlist, " ")
+		if value == "" {
+			value = permissionsPolicyNone
+		}
+		out[name+"_"+strings.ReplaceAll(feature, "-", "_")] = value
+	}
+	return nil
+}
+
+// documentResponseHeaders returns the response headers of the last load of
+// the document at pageURL in history, or of the last document otherwise.
+func documentResponseHeaders(history []HistoryData, pageURL string) http.Header {
+	var headers http.Header
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].ResourceType != proto.NetworkResourceTypeDocument {
+			continue
+		}
+		if history[i].URL == pageURL {
+			return history[i].ResponseHeaders
+		}
+		if headers == nil {
+			headers = history[i].ResponseHeaders
+		}
+	}
+	return headers
+}
+
+// clickjackingProtection returns the X-Frame-Options header and the sources of the
+// frame-ancestors directives of a document response, along with the protection
+// preventing another origin from framing it if any (x-frame-options or frame-ancestors).
+//
+// As browsers do, X-Frame-Options is ignored if the content security policy has
+// a frame-ancestors directive, which allows any origin only with a * or scheme source.
+func clickjackingProtection(headers http.Header) (string, string, string) {
+	xFrameOptions := strings.TrimSpace(headers.Get("X-Frame-Options"))
+
+	var frameAncestors []string
+	blocked := false
+	for _, policy := range headers.Values("Content-Security-Policy") {
+		for _, directive := range strings.Split(policy, ";") {
+			fields := strings.Fields(directive)
+			if len(fields) == 0 || !strings.EqualFold(fields[0], "frame-ancestors") {
+				continue
+			}
+			sources := fields[1:]
+			frameAncestors = append(frameAncestors, strings.Join(sources, " "))
+
+			allowed := false
+			for _, source := range sources {
+				if source == "*" || stringsutil.EqualFoldAny(source, "https:", "http:") {
+					allowed = true
+				}
+			}
+			// each policy must allow framing
+			blocked = blocked || !allowed
+			break
+		}
+	}
+	if len(frameAncestors) > 0 {
+		if blocked {
+			return xFrameOptions, strings.Join(frameAncestors, ", "), "frame-ancestors"
+		}
+		return xFrameOptions, strings.Join(frameAncestors, ", "), ""
+	}
+
+	// invalid values and allow-from, unsupported by browsers, don't protect
+	value, _, _ := strings.Cut(xFrameOptions, ",")
+	if stringsutil.EqualFoldAny(strings.TrimSpace(value), "deny", "sameorigin") {
+		return xFrameOptions, "", "x-frame-options"
+	}
+	return xFrameOptions, "", ""
+}
+
+// domHashJS serializes the element bound to this as a tree of elements
+// and text nodes, after removing the nodes matching the ignore selector.
+const domHashJS = `(ignore) => {
+	const root = this.cloneNode(true);
+	if (ignore) {
+		for (const node of root.querySelectorAll(ignore)) {
+			node.remove();
+		}
+	}
+	const serialize = (node) => {
+		if (node.nodeType === Node.TEXT_NODE) {
+			return {text: node.nodeValue};
+		}
+		if (node.nodeType !== Node.ELEMENT_NODE) {
+			return null;
+		}
+		const attributes = {};
+		for (const attribute of node.attributes) {
+			attributes[attribute.name] = attribute.value;
+		}
+		const children = [];
+		const childNodes = node.content ? node.content.childNodes : node.childNodes;
+		for (const child of childNodes) {
+			const serialized = serialize(child);
+			if (serialized) {
+				children.push(serialized);
+			}
+		}
+		return {tag: node.tagName.toLowerCase(), attributes: attributes, children: children};
+	};
+	return serialize(root);
+}`
+
+// domNode is an element or text node of a serialized dom
+type domNode struct {
+	Tag        string            `json:"tag"`
+	Text       string            `json:"text"`
+	Attributes map[string]string `json:"attributes"`
+	Children   []*domNode        `json:"children"`
+}
+
+// fetchSourceMapJS fetches a source map from the page context, with its cookies
+const fetchSourceMapJS = `async (url) => {
+	try {
+		const response = await fetch(url, {credentials: "include"});
+		return {status: response.status, body: await response.text()};
+	} catch (e) {
+		return {status: 0, body: "", error: String(e)};
+	}
+}`
+
+// SourceMaps detects the source maps referenced by the scripts loaded by the
+// page from its history, through the SourceMap header or the sourceMappingURL
+// comment of the scripts. Inline maps embedded as data uris are decoded, while
+// external maps are fetched through the page context, as the page would.
+// Exposed production source maps leak the original source code.
+//
+// The source maps are stored as a json array of objects with the script, url,
+// status code and accessibility of each map as the name of the action
+// (sourcemaps by default). <name>_found is true if any source map is accessible,
+// <name>_count is the number of accessible maps and <name>_urls the newline
+// separated urls of the accessible external maps. If the sources argument is
+// true, the original source file names of the maps are included in the output
+// and in <name>_sources, newline separated.
+func (p *Page) SourceMaps(act *Action, out map[string]string) error {
+	withSources := p.getActionArgWithDefaultValues(act, "sources") == "true"
+
+	p.mutex.RLock()
+	history := p.History
+	p.mutex.RUnlock()
+
+	maps := []sourceMapResult{}
+	seenScripts := make(map[string]struct{})
+	seenMaps := make(map[string]struct{})
+	for _, historyData := range history {
+		if historyData.ResourceType != proto.NetworkResourceTypeScript {
+			continue
+		}
+		if _, ok := seenScripts[historyData.URL]; ok {
+			continue
+		}
+		seenScripts[historyData.URL] = struct{}{}
+		reference := sourceMapReference(historyData)
+		if reference == "" {
+			continue
+		}
+
+		result := sourceMapResult{Script: historyData.URL}
+		var data []byte
+		if strings.HasPrefix(strings.ToLower(reference), "data:") {
+			result.Inline = true
+			decoded, err := decodeDataURI(reference)
+			if err != nil {
+				result.Error = err.Error()
+				maps = append(maps, result)
+				continue
+			}
+			data = decoded
+		} else {
+			base, err := url.Parse(historyData.URL)
+			if err != nil {
+				continue
+			}
+			resolved, ok := normalizeEndpoint(base, reference)
+			if !ok {
+				continue
+			}
+			if _, ok := seenMaps[resolved]; ok {
+				continue
+			}
+			seenMaps[resolved] = struct{}{}
+			result.URL = resolved
+
+			fetched, err := p.page.Eval(fetchSourceMapJS, resolved)
+			if err != nil {
+				return errors.Wrap(err, "could not fetch source map")
+			}
+			var response struct {
+				Status int    `json:"status"`
+				Body   string `json:"body"`
+				Error  string `json:"error"`
+			}
+			if err := fetched.Value.Unmarshal(&response); err != nil {
+				return errors.Wrap(err, "could not unmarshal source map response")
+			}
+			result.StatusCode = response.Status
+			if response.Error != "" || response.Status != http.StatusOK {
+				result.Error = response.Error
+				if result.Error == "" {
+					result.Error = "unexpected status code"
+				}
+				maps = append(maps, result)
+				continue
+			}
+			data = []byte(response.Body)
+		}
+
+		sources, content, err := parseSourceMap(data)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Accessible = true
+			result.SourcesContent = content
+			if withSources {
+				result.Sources = sources
+			}
+		}
+		maps = append(maps, result)
+	}
+
+	var urls, sources []string
+	seenSources := make(map[string]struct{})
+	accessible := 0
+	for _, result := range maps {
+		if !result.Accessible {
+			continue
+		}
+		accessible++
+		if result.URL != "" {
+			urls = append(urls, result.URL)
+		}
+		for _, source := range result.Sources {
+			if _, ok := seenSources[source]; ok {
+				continue
+			}
+			seenSources[source] = struct{}{}
+			sources = append(sources, source)
+		}
+	}
+	data, err := json.Marshal(maps)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal source maps")
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "sourcemaps"
+	}
+	out[name] = string(data)
+	out[name+"_found"] = strconv.FormatBool(accessible > 0)
+	out[name+"_count"] = strconv.Itoa(accessible)
+	out[name+"_urls"] = strings.Join(urls, "\n")
+	if withSources {
+		out[name+"_sources"] = strings.Join(sources, "\n")
+	}
+	return nil
+}
+
+// prefilledFieldsJS returns the inputs and textareas of the page which can be
+// typed in, with the length of their value, the value itself only if reveal is
+// true so that secrets don't leave the page otherwise.
+const prefilledFieldsJS = `(reveal) => {
+	const ignored = new Set(["hidden", "submit", "button", "reset", "image", "checkbox", "radio", "file", "range", "color"]);
+	const autofilled = (element) => {
+		for (const selector of [":autofill", ":-webkit-autofill"]) {
+			try {
+				if (element.matches(selector)) {
+					return true;
+				}
+			} catch (e) {}
+		}
+		return false;
+	};
+	const fields = [];
+	for (const element of document.querySelectorAll("input, textarea")) {
+		const type = element.tagName === "TEXTAREA" ? "textarea" : (element.type || "text");
+		if (ignored.has(type)) {
+			continue;
+		}
+		const value = element.value || "";
+		fields.push({
+			name: element.getAttribute("name") || "",
+			id: element.id || "",
+			type: type,
+			autocomplete: element.getAttribute("autocomplete") || "",
+			form: element.form ? element.form.action : "",
+			length: value.length,
+			attribute: value !== "" && value === element.defaultValue,
+			autofilled: autofilled(element),
+			value: reveal ? value : "",
+		});
+	}
+	return fields;
+}`
+
+// PrefilledFields detects the password and sensitive fields of the page having
+// a value before any user input, such as the stored credentials of a shared or
+// kiosk session echoed into the page. It checks the fields at the time it runs,
+// so it should come right after the page is loaded.
+//
+// Fields are sensitive if they are password inputs, have a password, one time
+// code or card autocomplete token, or a name or id matching the names regex
+// (passwords, secrets, tokens, api keys, otp, pin, card numbers and cvv by
+// default). Hidden inputs, such as csrf tokens, are ignored.
+//
+// The prefilled fields are stored as a json array of objects with the name, type,
+// form, reason, source (markup, autofill or script) and value length of each
+// field as the name of the action (prefilled_fields by default). <name>_found is
+// true if any sensitive field is prefilled, <name>_password if any password
+// input is, <name>_count is their number and <name>_names their newline separated
+// names. The values are never output unless the reveal argument is true, which
+// adds them to the json objects and to <name>_values, newline separated.
+func (p *Page) PrefilledFields(act *Action, out map[string]string) error {
+	var names *regexp.Regexp
+	if expression := p.getActionArgWithDefaultValues(act, "names"); expression != "" {
+		compiled, err := regexp.Compile(expression)
+		if err != nil {
+			return errors.Wrap(err, "could not compile names regex")
+		}
+		names = compiled
+	}
+	reveal := p.getActionArgWithDefaultValues(act, "reveal") == "true"
+
+	result, err := p.page.Eval(prefilledFieldsJS, reveal)
+	if err != nil {
+		return errors.Wrap(err, "could not get page fields")
+	}
+	var fields []pageField
+	if err := result.Value.Unmarshal(&fields); err != nil {
+		return errors.Wrap(err, "could not unmarshal page fields")
+	}
+	prefilled := prefilledSensitiveFields(fields, names)
+	data, err := json.Marshal(prefilled)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal prefilled fields")
+	}
+
+	var fieldNames, values []string
+	password := false
+	for _, field := range prefilled {
+		fieldNames = append(fieldNames, field.Name)
+		values = append(values, field.Value)
+		if field.Type == "password" {
+			password = true
+		}
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "prefilled_fields"
+	}
+	out[name] = string(data)
+	out[name+"_found"] = strconv.FormatBool(len(prefilled) > 0)
+	out[name+"_password"] = strconv.FormatBool(password)
+	out[name+"_count"] = strconv.Itoa(len(prefilled))
+	out[name+"_names"] = strings.Join(fieldNames, "\n")
+	if reveal {
+		out[name+"_values"] = strings.Join(values, "\n")
+	}
+	return nil
+}
+
+// insecureFormsJS returns the base url of the document and the submission
+// targets of its forms, the form action and the formaction of its submit buttons.
+const insecureFormsJS = `() => {
+	const forms = [];
+	for (const form of document.forms) {
+		const method = (form.getAttribute("method") || "GET").toLowerCase();
+		const target = {
+			id: form.id || "",
+			name: form.getAttribute("name") || "",
+			method: method,
+			password: form.querySelector("input[type=password i]") !== null,
+		};
+		forms.push(Object.assign({action: form.getAttribute("action") || "", submitter: false}, target));
+		for (const submitter of form.querySelectorAll("button[formaction], input[formaction]")) {
+			forms.push(Object.assign({}, target, {
+				action: submitter.getAttribute("formaction"),
+				method: (submitter.getAttribute("formmethod") || method).toLowerCase(),
+				submitter: true,
+			}));
+		}
+	}
+	return {base: document.baseURI, forms: forms};
+}`
+
+// InsecureForms detects the forms of the rendered page submitting over http
+// from a https page, or to another origin than the page, which leak the data
+// they submit, such as credentials, in cleartext or to unexpected hosts. The
+// action of the forms, and the formaction of their submit buttons, are resolved
+// against the page, empty actions submitting to the page itself.
+//
+// The flagged forms are stored as a json array of objects with the id, name,
+// resolved action, method and password input presence of each form along with
+// the insecure, cross_origin and third_party (another registrable domain) flags,
+// as the name of the action (insecure_forms by default). <name>_found is true
+// if any form is flagged, <name>_insecure, <name>_cross_origin and
+// <name>_third_party if any form has the flag, <name>_password if any flagged
+// form has a password input, <name>_count is the number of flagged forms and
+// <name>_actions their newline separated actions.
+func (p *Page) InsecureForms(act *Action, out map[string]string) error {
+	result, err := p.page.Eval(insecureFormsJS)
+	if err != nil {
+		return errors.Wrap(err, "could not get page forms")
+	}
+	var document struct {
+		Base  string     `json:"base"`
+		Forms []pageForm `json:"forms"`
+	}
+	if err := result.Value.Unmarshal(&document); err != nil {
+		return errors.Wrap(err, "could not unmarshal page forms")
+	}
+	forms := insecureForms(p.URL(), document.Base, document.Forms)
+	data, err := json.Marshal(forms)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal insecure forms")
+	}
+
+	var actions []string
+	var insecure, crossOrigin, thirdParty, password bool
+	for _, form := range forms {
+		actions = append(actions, form.Action)
+		insecure = insecure || form.Insecure
+		crossOrigin = crossOrigin || form.CrossOrigin
+		thirdParty = thirdParty || form.ThirdParty
+		password = password || form.Password
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "insecure_forms"
+	}
+	out[name] = string(data)
+	out[name+"_found"] = strconv.FormatBool(len(forms) > 0)
+	out[name+"_insecure"] = strconv.FormatBool(insecure)
+	out[name+"_cross_origin"] = strconv.FormatBool(crossOrigin)
+	out[name+"_third_party"] = strconv.FormatBool(thirdParty)
+	out[name+"_password"] = strconv.FormatBool(password)
+	out[name+"_count"] = strconv.Itoa(len(forms))
+	out[name+"_actions"] = strings.Join(sliceutil.Dedupe(actions), "\n")
+	return nil
+}
+
+// DOMClobbering detects dom clobbering by navigating to url ({{BaseURL}} by
+// default) while an anchor with the id and name of each of the names (comma
+// separated, common configuration globals by default) is injected at the start
+// of every document, its named access being hooked to record the globals the
+// page reads and gets the anchor for instead of their undefined value. The page
+// is observed until it is loaded with a clobbered global or the timeout expires,
+// bounded by the page timeout, and the anchors and hooks are then removed.
+//
+// The output contains true or false as the name of the action (domclobbering
+// by default), with the clobbered names in <name>_names, the clobbered globals
+// along with the location of the script reading them in <name>_clobbered as
+// json and the navigated url in <name>_url.
+func (p *Page) DOMClobbering(act *Action, out map[string]string, baseURL *url.URL) error {
+	names, err := parseClobberingNames(p.getActionArgWithDefaultValues(act, "names"))
+	if err != nil {
+		return err
+	}
+	URL := p.getActionArgWithDefaultValues(act, "url")
+	if URL == "" {
+		URL = "{{BaseURL}}"
+	}
+	target := navigationURL(URL, baseURL)
+	timeout, err := getTimeout(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	pollTime, err := getPollTime(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong polling time given")
+	}
+
+	arguments, err := json.Marshal(names)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal hook arguments")
+	}
+	removeHook, err := p.page.EvalOnNewDocument(fmt.Sprintf("(%s)(%s)", domClobberingHookJS, arguments))
+	if err != nil {
+		return errors.Wrap(err, "could not hook globals")
+	}
+	defer func() {
+		_ = removeHook()
+	}()
+	if err := p.page.Navigate(target); err != nil {
+		return errors.Wrap(err, "could not navigate")
+	}
+
+	deadline := time.Now().Add(timeout)
+	if pageDeadline, ok := p.page.GetContext().Deadline(); ok && pageDeadline.Before(deadline) {
+		deadline = pageDeadline
+	}
+	var result struct {
+		Complete  bool              `json:"complete"`
+		Clobbered []clobberedGlobal `json:"clobbered"`
+	}
+	for {
+		// the document may be replaced while polling, in which case the globals are polled again
+		if value, err := p.page.Eval(domClobberingResultJS); err == nil {
+			_ = value.Value.Unmarshal(&result)
+		}
+		if (result.Complete && len(result.Clobbered) > 0) || time.Now().Add(pollTime).After(deadline) {
+			break
+		}
+		time.Sleep(pollTime)
+	}
+	_, _ = p.page.Eval(domClobberingCleanupJS)
+
+	clobbered := result.Clobbered
+	if clobbered == nil {
+		clobbered = []clobberedGlobal{}
+	}
+	data, err := json.Marshal(clobbered)
 	if err != nil {
-		return errors.Wrap(err, "could not write screenshot")
+		return errors.Wrap(err, "could not marshal clobbered globals")
 	}
-	gologger.Info().Msgf("Screenshot successfully saved at %v\n", filePath)
+	clobberedNames := make([]string, 0, len(clobbered))
+	for _, global := range clobbered {
+		clobberedNames = append(clobberedNames, global.Name)
+	}
+
+	name := act.Name
+	if name == "" {
+		name = "domclobbering"
+	}
+	out[name] = strconv.FormatBool(len(clobbered) > 0)
+	out[name+"_names"] = strings.Join(clobberedNames, ",")
+	out[name+"_clobbered"] = string(data)
+	out[name+"_url"] = target
 	return nil
 }
 
-// InputElement executes input element actions for an element.
-func (p *Page) InputElement(act *Action, out map[string]string /*TODO review unused parameter*/) error {
-	value := p.getActionArgWithDefaultValues(act, "value")
-	if value == "" {
-		return errinvalidArguments
+// DOMHash computes a sha256 hash of the rendered dom of the page, or of an
+// element of it, to detect changes against a known baseline.
+//
+// The dom is normalized before hashing so that volatile content does not
+// change the hash:
+//   - comments are dropped and the nodes matching the ignore css selector are removed
+//   - attributes are sorted and the ones whose name fully matches one of the
+//     comma separated attributes regexes (case-insensitive) are stripped
+//   - the matches of the patterns regexes, one per line, are removed
+//     from text and attribute values
+//   - whitespace is collapsed and whitespace-only text is dropped
+//
+// The hash is stored in the output as the name of the action (dom_hash by
+// default) and, when a baseline hash is given, <name>_changed tells whether
+// it differs from the baseline.
+func (p *Page) DOMHash(act *Action, out map[string]string) error {
+	normalizer, err := newDOMNormalizer(p.getActionArgWithDefaultValues(act, "attributes"), p.getActionArgWithDefaultValues(act, "patterns"))
+	if err != nil {
+		return err
+	}
+	var element *rod.Element
+	if act.Data["selector"] == "" && act.Data["by"] == "" {
+		element, err = p.page.Element("html")
+	} else {
+		element, err = p.pageElementBy(act.Data)
 	}
-	element, err := p.pageElementBy(act.Data)
 	if err != nil {
 		return errors.Wrap(err, errCouldNotGetElement)
 	}
-	if err = element.ScrollIntoView(); err != nil {
-		return errors.Wrap(err, errCouldNotScroll)
+	result, err := element.Eval(domHashJS, p.getActionArgWithDefaultValues(act, "ignore"))
+	if err != nil {
+		return errors.Wrap(err, "could not serialize dom")
 	}
-	if err = element.Input(value); err != nil {
-		return errors.Wrap(err, "could not input element")
+	root := &domNode{}
+	if err := result.Value.Unmarshal(root); err != nil {
+		return errors.Wrap(err, "could not parse serialized dom")
+	}
+	hash := normalizer.hash(root)
+
+	name := act.Name
+	if name == "" {
+		name = "dom_hash"
+	}
+	out[name] = hash
+	if baseline := p.getActionArgWithDefaultValues(act, "baseline"); baseline != "" {
+		out[name+"_changed"] = strconv.FormatBool(!strings.EqualFold(strings.TrimSpace(baseline), hash))
 	}
 	return nil
 }
 
-// TimeInputElement executes time input on an element
-func (p *Page) TimeInputElement(act *Action, out map[string]string /*TODO review unused parameter*/) error {
-	value := p.getActionArgWithDefaultValues(act, "value")
-	if value == "" {
-		return errinvalidArguments
+// domNormalizer normalizes a serialized dom before hashing
+type domNormalizer struct {
+	attributes []*regexp.Regexp
+	patterns   []*regexp.Regexp
+}
+
+var reDOMWhitespace = regexp.MustCompile(`\s+`)
+
+// newDOMNormalizer returns a normalizer stripping the attributes with names fully
+// matching the comma separated attributes regexes, and the matches of the line
+// separated patterns regexes from text and attribute values.
+func newDOMNormalizer(attributes, patterns string) (*domNormalizer, error) {
+	normalizer := &domNormalizer{}
+	for _, attribute := range strings.Split(attributes, ",") {
+		if attribute = strings.TrimSpace(attribute); attribute == "" {
+			continue
+		}
+		compiled, err := regexp.Compile("(?i)^(?:" + attribute + ")$")
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid attributes pattern %s", attribute)
+		}
+		normalizer.attributes = append(normalizer.attributes, compiled)
 	}
-	element, err := p.pageElementBy(act.Data)
-	if err != nil {
-		return errors.Wrap(err, errCouldNotGetElement)
+	for _, pattern := range strings.Split(patterns, "\n") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %s", pattern)
+		}
+		normalizer.patterns = append(normalizer.patterns, compiled)
 	}
-	if err = element.ScrollIntoView(); err != nil {
-		return errors.Wrap(err, errCouldNotScroll)
+	return normalizer, nil
+}
+
+// hash returns the hex encoded sha256 hash of the normalized dom
+func (n *domNormalizer) hash(root *domNode) string {
+	builder := &strings.Builder{}
+	n.serialize(builder, root)
+	sum := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// serialize writes the normalized markup of a node to the builder
+func (n *domNormalizer) serialize(builder *strings.Builder, node *domNode) {
+	if node.Tag == "" {
+		if text := n.normalize(node.Text); text != "" {
+			builder.WriteString(html.EscapeString(text))
+		}
+		return
 	}
-	t, err := time.Parse(time.RFC3339, value)
-	if err != nil {
-		return errors.Wrap(err, "could not parse time")
+	names := make([]string, 0, len(node.Attributes))
+	for name := range node.Attributes {
+		if !n.stripped(name) {
+			names = append(names, name)
+		}
 	}
-	if err := element.InputTime(t); err != nil {
-		return errors.Wrap(err, "could not input element")
+	sort.Strings(names)
+
+	builder.WriteString("<" + node.Tag)
+	for _, name := range names {
+		builder.WriteString(" " + name + "=\"" + html.EscapeString(n.normalize(node.Attributes[name])) + "\"")
 	}
-	return nil
+	builder.WriteString(">")
+	for _, child := range node.Children {
+		n.serialize(builder, child)
+	}
+	builder.WriteString("</" + node.Tag + ">")
 }
 
-// SelectInputElement executes select input statement action on a element
-func (p *Page) SelectInputElement(act *Action, out map[string]string /*TODO review unused parameter*/) error {
-	value := p.getActionArgWithDefaultValues(act, "value")
-	if value == "" {
-		return errinvalidArguments
+// stripped returns true if an attribute is stripped from the dom
+func (n *domNormalizer) stripped(name string) bool {
+	for _, attribute := range n.attributes {
+		if attribute.MatchString(name) {
+			return true
+		}
 	}
-	element, err := p.pageElementBy(act.Data)
+	return false
+}
+
+// normalize removes the patterns from a value and collapses its whitespace
+func (n *domNormalizer) normalize(value string) string {
+	for _, pattern := range n.patterns {
+		value = pattern.ReplaceAllString(value, "")
+	}
+	return strings.TrimSpace(reDOMWhitespace.ReplaceAllString(value, " "))
+}
+
+// ServiceWorkers enumerates the service workers registered in the browser
+// using the service worker domain, storing their scopes and script urls as
+// json in the output as the name of the action.
+//
+// The domain is only enabled while the registrations are collected for
+// the duration of the timeout argument in seconds (1 by default).
+func (p *Page) ServiceWorkers(act *Action, out map[string]string) error {
+	timeout, err := geTimeParameter(p, act, "timeout", 1, time.Second)
 	if err != nil {
-		return errors.Wrap(err, errCouldNotGetElement)
+		return errors.Wrap(err, "Wrong timeout given")
 	}
-	if err = element.ScrollIntoView(); err != nil {
-		return errors.Wrap(err, errCouldNotScroll)
+
+	ctx, cancel := context.WithTimeout(p.page.GetContext(), timeout)
+	defer cancel()
+	// subscribe before enabling the domain as the existing
+	// registrations are only sent once when it is enabled
+	events := p.page.Context(ctx).Event()
+	if err := (proto.ServiceWorkerEnable{}).Call(p.page); err != nil {
+		return errors.Wrap(err, "could not enable service workers")
 	}
+	defer func() {
+		_ = proto.ServiceWorkerDisable{}.Call(p.page)
+	}()
 
-	selectedBool := false
-	if p.getActionArgWithDefaultValues(act, "selected") == "true" {
-		selectedBool = true
+	registry := newServiceWorkerRegistry()
+	for msg := range events {
+		registrations := &proto.ServiceWorkerWorkerRegistrationUpdated{}
+		versions := &proto.ServiceWorkerWorkerVersionUpdated{}
+		if msg.Load(registrations) {
+			registry.updateRegistrations(registrations.Registrations)
+		} else if msg.Load(versions) {
+			registry.updateVersions(versions.Versions)
+		}
 	}
-	by := p.getActionArgWithDefaultValues(act, "selector")
-	if err := element.Select([]string{value}, selectedBool, selectorBy(by)); err != nil {
-		return errors.Wrap(err, "could not select input")
+
+	if act.Name != "" {
+		data, err := json.Marshal(registry.serviceWorkers())
+		if err != nil {
+			return errors.Wrap(err, "could not marshal service workers")
+		}
+		out[act.Name] = string(data)
 	}
 	return nil
 }
 
-// WaitLoad waits for the page to load
-func (p *Page) WaitLoad(act *Action, out map[string]string /*TODO review unused parameter*/) error {
-	p.page.Timeout(2 * time.Second).WaitNavigation(proto.PageLifecycleEventNameFirstMeaningfulPaint)()
+// serviceWorker is a service worker registered in the browser
+type serviceWorker struct {
+	Scope     string `json:"scope"`
+	ScriptURL string `json:"script_url"`
+	Status    string `json:"status"`
+}
 
-	// Wait for the window.onload event and also wait for the network requests
-	// to become idle for a maximum duration of 3 seconds. If the requests
-	// do not finish,
-	if err := p.page.WaitLoad(); err != nil {
-		return errors.Wrap(err, "could not wait load event")
+// serviceWorkerRegistry keeps the service worker registrations and their
+// latest version from the events of the service worker domain.
+type serviceWorkerRegistry struct {
+	scopes   map[proto.ServiceWorkerRegistrationID]string
+	versions map[proto.ServiceWorkerRegistrationID]*proto.ServiceWorkerServiceWorkerVersion
+}
+
+func newServiceWorkerRegistry() *serviceWorkerRegistry {
+	return &serviceWorkerRegistry{
+		scopes:   make(map[proto.ServiceWorkerRegistrationID]string),
+		versions: make(map[proto.ServiceWorkerRegistrationID]*proto.ServiceWorkerServiceWorkerVersion),
 	}
-	_ = p.page.WaitIdle(1 * time.Second)
-	return nil
 }
 
-// GetResource gets a resource from an element from page.
-func (p *Page) GetResource(act *Action, out map[string]string) error {
-	element, err := p.pageElementBy(act.Data)
-	if err != nil {
-		return errors.Wrap(err, errCouldNotGetElement)
+func (r *serviceWorkerRegistry) updateRegistrations(registrations []*proto.ServiceWorkerServiceWorkerRegistration) {
+	for _, registration := range registrations {
+		if registration.IsDeleted {
+			delete(r.scopes, registration.RegistrationID)
+			continue
+		}
+		r.scopes[registration.RegistrationID] = registration.ScopeURL
 	}
-	resource, err := element.Resource()
+}
+
+func (r *serviceWorkerRegistry) updateVersions(versions []*proto.ServiceWorkerServiceWorkerVersion) {
+	for _, version := range versions {
+		// redundant versions have been replaced by a newer version
+		if version.Status == proto.ServiceWorkerServiceWorkerVersionStatusRedundant {
+			continue
+		}
+		r.versions[version.RegistrationID] = version
+	}
+}
+
+// serviceWorkers returns the registered service workers sorted by scope
+func (r *serviceWorkerRegistry) serviceWorkers() []serviceWorker {
+	workers := make([]serviceWorker, 0, len(r.scopes))
+	for id, scope := range r.scopes {
+		worker := serviceWorker{Scope: scope}
+		if version, ok := r.versions[id]; ok {
+			worker.ScriptURL = version.ScriptURL
+			worker.Status = string(version.Status)
+		}
+		workers = append(workers, worker)
+	}
+	sort.Slice(workers, func(i, j int) bool {
+		return workers[i].Scope < workers[j].Scope
+	})
+	return workers
+}
+
+// unloadResult is the beforeunload dialog and the requests captured by the unload action
+type unloadResult struct {
+	Dialog        bool            `json:"dialog"`
+	DialogMessage string          `json:"dialog_message,omitempty"`
+	Requests      []unloadRequest `json:"requests"`
+}
+
+// unloadRequest is a request sent by the page while it was unloaded
+type unloadRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Type   string `json:"type"`
+}
+
+// Unload navigates away from the page, or reloads it when no url is specified,
+// handling the beforeunload dialog and capturing the requests sent during unload.
+//
+// Browsers only show the beforeunload dialog of pages which received
+// a user interaction, such as a click.
+func (p *Page) Unload(act *Action, out map[string]string, baseURL *url.URL) error {
+	timeout, err := geTimeParameter(p, act, "timeout", 2, time.Second)
 	if err != nil {
-		return errors.Wrap(err, "could not get src for element")
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	accept := !strings.EqualFold(p.getActionArgWithDefaultValues(act, "accept"), "false")
+	var target string
+	if URL := p.getActionArgWithDefaultValues(act, "url"); URL != "" {
+		target = navigationURL(URL, baseURL)
+	}
+
+	p.mutex.RLock()
+	start := len(p.History)
+	p.mutex.RUnlock()
+
+	ctx, cancel := context.WithCancel(p.page.GetContext())
+	defer cancel()
+	events := p.page.Context(ctx).Event()
+
+	// the navigation doesn't complete while the beforeunload dialog is open
+	triggered := make(chan error, 1)
+	go func() {
+		if target == "" {
+			triggered <- proto.PageReload{}.Call(p.page)
+			return
+		}
+		res, err := proto.PageNavigate{URL: target}.Call(p.page)
+		if err == nil && res.ErrorText != "" {
+			err = &rod.ErrNavigation{Reason: res.ErrorText}
+		}
+		triggered <- err
+	}()
+
+	result := &unloadResult{Requests: []unloadRequest{}}
+	var settled <-chan time.Time
+loop:
+	for {
+		select {
+		case msg, ok := <-events:
+			if !ok {
+				break loop
+			}
+			dialog := &proto.PageJavascriptDialogOpening{}
+			if !msg.Load(dialog) {
+				continue
+			}
+			if dialog.Type == proto.PageDialogTypeBeforeunload {
+				result.Dialog = true
+				result.DialogMessage = dialog.Message
+			}
+			if err := (proto.PageHandleJavaScriptDialog{Accept: accept}).Call(p.page); err != nil {
+				return errors.Wrap(err, "could not handle dialog")
+			}
+		case err := <-triggered:
+			// dismissing the beforeunload dialog cancels the navigation
+			if err != nil && (accept || !result.Dialog) {
+				return errors.Wrap(err, "could not unload page")
+			}
+			settled = time.After(timeout)
+		case <-settled:
+			break loop
+		}
+	}
+
+	p.mutex.RLock()
+	for _, historyData := range p.History[start:] {
+		result.Requests = append(result.Requests, unloadRequest{Method: historyData.Method, URL: historyData.URL, Type: string(historyData.ResourceType)})
 	}
+	p.mutex.RUnlock()
+
 	if act.Name != "" {
-		out[act.Name] = string(resource)
+		data, err := json.Marshal(result)
+		if err != nil {
+			return errors.Wrap(err, "could not marshal unload result")
+		}
+		out[act.Name] = string(data)
 	}
 	return nil
 }
 
-// FilesInput acts with a file input element on page
-func (p *Page) FilesInput(act *Action, out map[string]string /*TODO review unused parameter*/) error {
-	element, err := p.pageElementBy(act.Data)
+// ExportSession exports the cookies of the browser along with the local and
+// session storage of every origin loaded in the page to a session bundle file.
+func (p *Page) ExportSession(act *Action, out map[string]string, baseURL *url.URL) error {
+	file := p.getSessionFilePath(act, baseURL)
+	if file == "" {
+		return errinvalidArguments
+	}
+
+	cookies, err := proto.NetworkGetAllCookies{}.Call(p.page)
 	if err != nil {
-		return errors.Wrap(err, errCouldNotGetElement)
+		return errors.Wrap(err, "could not get cookies")
 	}
-	if err = element.ScrollIntoView(); err != nil {
-		return errors.Wrap(err, errCouldNotScroll)
+	bundle := &sessionBundle{Cookies: cookies.Cookies, Storage: make(map[string]*originStorage)}
+
+	tree, err := proto.PageGetFrameTree{}.Call(p.page)
+	if err != nil {
+		return errors.Wrap(err, "could not get frame tree")
 	}
-	value := p.getActionArgWithDefaultValues(act, "value")
-	filesPaths := strings.Split(value, ",")
-	if err := element.SetFiles(filesPaths); err != nil {
-		return errors.Wrap(err, "could not set files")
+	if err := (proto.DOMStorageEnable{}).Call(p.page); err != nil {
+		return errors.Wrap(err, "could not enable dom storage")
+	}
+	for _, origin := range frameOrigins(tree.FrameTree) {
+		storage := &originStorage{}
+		for _, isLocalStorage := range []bool{true, false} {
+			items, err := proto.DOMStorageGetDOMStorageItems{
+				StorageID: &proto.DOMStorageStorageID{SecurityOrigin: origin, IsLocalStorage: isLocalStorage},
+			}.Call(p.page)
+			if err != nil {
+				return errors.Wrapf(err, "could not get storage for %s", origin)
+			}
+			if isLocalStorage {
+				storage.LocalStorage = storageItemsToMap(items.Entries)
+			} else {
+				storage.SessionStorage = storageItemsToMap(items.Entries)
+			}
+		}
+		bundle.Storage[origin] = storage
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal session")
+	}
+	if p.getActionArgWithDefaultValues(act, "mkdir") == "true" && stringsutil.ContainsAny(file, folderutil.UnixPathSeparator, folderutil.WindowsPathSeparator) {
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+			return errorutil.NewWithErr(err).Msgf("failed to create directory while writing session")
+		}
+	}
+	// session files contain credentials so they are only readable by the user
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return errors.Wrap(err, "could not write session")
+	}
+	if act.Name != "" {
+		out[act.Name] = file
 	}
 	return nil
 }
 
-// ExtractElement extracts from an element on the page.
-func (p *Page) ExtractElement(act *Action, out map[string]string) error {
-	element, err := p.pageElementBy(act.Data)
+// ImportSession restores a session bundle exported with ExportSession.
+//
+// Cookies are set immediately while the storage of each origin is
+// restored when a document of that origin is loaded, so the action
+// should be used before navigating to the target.
+func (p *Page) ImportSession(act *Action, out map[string]string, baseURL *url.URL) error {
+	file := p.getSessionFilePath(act, baseURL)
+	if file == "" {
+		return errinvalidArguments
+	}
+
+	data, err := os.ReadFile(file)
 	if err != nil {
-		return errors.Wrap(err, errCouldNotGetElement)
+		return errors.Wrap(err, "could not read session")
 	}
-	if err = element.ScrollIntoView(); err != nil {
-		return errors.Wrap(err, errCouldNotScroll)
+	bundle := &sessionBundle{}
+	if err := json.Unmarshal(data, bundle); err != nil {
+		return errors.Wrap(err, "could not unmarshal session")
 	}
-	switch p.getActionArgWithDefaultValues(act, "target") {
-	case "attribute":
-		attrName := p.getActionArgWithDefaultValues(act, "attribute")
-		if attrName == "" {
-			return errors.New("attribute can't be empty")
-		}
-		attrValue, err := element.Attribute(attrName)
-		if err != nil {
-			return errors.Wrap(err, "could not get attribute")
-		}
-		if act.Name != "" {
-			out[act.Name] = *attrValue
+
+	if len(bundle.Cookies) > 0 {
+		if err := p.page.SetCookies(proto.CookiesToParams(bundle.Cookies)); err != nil {
+			return errors.Wrap(err, "could not set cookies")
 		}
-	default:
-		text, err := element.Text()
+	}
+	if len(bundle.Storage) > 0 {
+		script, err := importStorageScript(bundle.Storage)
 		if err != nil {
-			return errors.Wrap(err, "could not get element text node")
+			return errors.Wrap(err, "could not build storage script")
 		}
-		if act.Name != "" {
-			out[act.Name] = text
+		if _, err := p.page.EvalOnNewDocument(script); err != nil {
+			return errors.Wrap(err, "could not restore storage")
 		}
 	}
 	return nil
 }
 
+// getSessionFilePath returns the session file path of an action
+// with the hostname of the target replaced in the path.
+func (p *Page) getSessionFilePath(act *Action, baseURL *url.URL) string {
+	file := p.getActionArgWithDefaultValues(act, "file")
+	if baseURL == nil {
+		return file
+	}
+	return replaceWithValues(file, map[string]interface{}{
+		"Hostname": baseURL.Hostname(),
+	})
+}
+
 type protoEvent struct {
 	event string
 }
@@ -537,6 +4682,139 @@ func (p *Page) WaitEvent(act *Action, out map[string]string /*TODO review unused
 	return nil
 }
 
+// WaitResponse waits for a response to a request with a url matching a pattern
+// and captures its body and status code. Responses received since the previous
+// waitresponse action are matched, so the request can be triggered before waiting.
+func (p *Page) WaitResponse(act *Action, out map[string]string) error {
+	pattern := p.getActionArgWithDefaultValues(act, "url")
+	if pattern == "" {
+		return errinvalidArguments
+	}
+	urlRegex, err := regexp.Compile(pattern)
+	if err != nil {
+		return errors.Wrap(err, "could not compile url pattern")
+	}
+	method := p.getActionArgWithDefaultValues(act, "method")
+
+	timeout, err := getTimeout(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	pollTime, err := getPollTime(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong polling time given")
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if historyData, ok := p.nextResponse(urlRegex, method); ok {
+			name := act.Name
+			if name == "" {
+				name = "response"
+			}
+			out[name] = historyData.ResponseBody
+			out[name+"_status"] = strconv.Itoa(historyData.StatusCode)
+			out[name+"_url"] = historyData.URL
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("no response matching %s received", pattern)
+		}
+		time.Sleep(pollTime)
+	}
+}
+
+// nextResponse returns the first response in history received since the
+// previously matched one with a request url matching urlRegex.
+func (p *Page) nextResponse(urlRegex *regexp.Regexp, method string) (HistoryData, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for i := p.responseIndex; i < len(p.History); i++ {
+		historyData := p.History[i]
+		if method != "" && !strings.EqualFold(historyData.Method, method) {
+			continue
+		}
+		if urlRegex.MatchString(historyData.URL) {
+			p.responseIndex = i + 1
+			return historyData, true
+		}
+	}
+	return HistoryData{}, false
+}
+
+// WaitInteraction waits until an interactsh interaction is received for one
+// of the interactsh urls of the page, optionally with the given protocol.
+//
+// The interaction is only observed, it is still correlated to the request
+// event once the actions are executed so that matchers can use it.
+func (p *Page) WaitInteraction(act *Action, out map[string]string) error {
+	if p.instance.interactsh == nil {
+		return errors.New("interactsh is not enabled")
+	}
+	protocol := p.getActionArgWithDefaultValues(act, "protocol")
+
+	timeout, err := geTimeParameter(p, act, "timeout", 10, time.Second)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	pollTime, err := getPollTime(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong polling time given")
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		interactshURL, interaction, ok, err := p.nextInteraction(protocol)
+		if err != nil {
+			return err
+		}
+		if ok {
+			name := act.Name
+			if name == "" {
+				name = "interaction"
+			}
+			out[name] = interaction.RawRequest
+			out[name+"_protocol"] = interaction.Protocol
+			out[name+"_response"] = interaction.RawResponse
+			out[name+"_ip"] = interaction.RemoteAddress
+			out[name+"_url"] = interactshURL
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("no interaction received")
+		}
+		time.Sleep(pollTime)
+	}
+}
+
+// nextInteraction returns the first interaction received for the interactsh
+// urls of the page which wasn't returned before, along with its url.
+func (p *Page) nextInteraction(protocol string) (string, *server.Interaction, bool, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if len(p.InteractshURLs) == 0 {
+		return "", nil, false, errors.New("no interactsh url used by the page")
+	}
+	if p.seenInteractions == nil {
+		p.seenInteractions = make(map[*server.Interaction]struct{})
+	}
+	for _, interactshURL := range p.InteractshURLs {
+		for _, interaction := range p.instance.interactsh.Interactions(interactshURL) {
+			if _, ok := p.seenInteractions[interaction]; ok {
+				continue
+			}
+			if protocol != "" && !strings.EqualFold(interaction.Protocol, protocol) {
+				continue
+			}
+			p.seenInteractions[interaction] = struct{}{}
+			return interactshURL, interaction, true, nil
+		}
+	}
+	return "", nil, false, nil
+}
+
 // pageElementBy returns a page element from a variety of inputs.
 //
 // Supported values for by: r -> selector & regex, x -> xpath, js -> eval js,
@@ -636,10 +4914,6 @@ func (p *Page) getActionArgWithDefaultValues(action *Action, arg string) string
 func (p *Page) getActionArgWithValues(action *Action, arg string, values map[string]interface{}) string {
 	argValue := action.GetArg(arg)
 	argValue = replaceWithValues(argValue, values)
-	if p.instance.interactsh != nil {
-		var interactshURLs []string
-		argValue, interactshURLs = p.instance.interactsh.Replace(argValue, p.InteractshURLs)
-		p.addInteractshURL(interactshURLs...)
-	}
+	argValue = p.replaceInteractshURLs(argValue)
 	return argValue
 }
@@ -1,6 +1,9 @@
 package engine
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
@@ -11,10 +14,15 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
 	"github.com/stretchr/testify/require"
+	"github.com/ysmood/gson"
 
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v2/pkg/testutils/testheadless"
@@ -165,6 +173,32 @@ func TestActionTextInput(t *testing.T) {
 	})
 }
 
+func TestActionPaste(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>Nuclei Test Page</body>
+			<input type="text" onpaste="this.setAttribute('pasted', event.clipboardData.getData('text/plain'))">
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionPaste}, Data: map[string]string{"selector": "input", "value": "<img src=x>"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		el := page.Page().MustElement("input")
+		val := el.MustAttribute("pasted")
+		require.NotNil(t, val, "could not get paste event")
+		require.Equal(t, "<img src=x>", *val, "could not get paste event data")
+		require.Equal(t, "<img src=x>", el.MustText(), "could not get pasted value")
+	})
+}
+
 func TestActionHeadersChange(t *testing.T) {
 	actions := []*Action{
 		{ActionType: ActionTypeHolder{ActionType: ActionSetHeader}, Data: map[string]string{"part": "request", "key": "Test", "value": "Hello"}},
@@ -184,6 +218,45 @@ func TestActionHeadersChange(t *testing.T) {
 	})
 }
 
+func TestActionMockResponse(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionMockResponse}, Data: map[string]string{"url": "/api/user$", "status": "201", "headers": "Content-Type: application/json\nX-Mocked: true", "body": `{"role":"admin"}`}},
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Data: map[string]string{"code": "() => document.body.innerText"}, Name: "role"},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/user" {
+			_, _ = fmt.Fprint(w, `{"role":"user"}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<html><body><script>fetch("/api/user").then(r => r.json()).then(d => document.body.innerText = d.role)</script></body></html>`)
+	}
+
+	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Eventually(t, func() bool {
+			return strings.TrimSpace(page.Page().MustElement("body").MustText()) == "admin"
+		}, 5*time.Second, 100*time.Millisecond, "could not mock response")
+		for _, history := range page.History {
+			if strings.HasSuffix(history.URL, "/api/user") {
+				require.Equal(t, 201, history.StatusCode, "could not mock status code")
+				require.Equal(t, "true", history.ResponseHeaders.Get("X-Mocked"), "could not mock headers")
+			}
+		}
+	})
+}
+
+func TestParseMockHeaders(t *testing.T) {
+	pairs, err := parseMockHeaders("Content-Type: application/json\n\nSet-Cookie: a=b; Path=/")
+	require.Nil(t, err, "could not parse headers")
+	require.Equal(t, []string{"Content-Type", "application/json", "Set-Cookie", "a=b; Path=/"}, pairs, "could not get header pairs")
+
+	_, err = parseMockHeaders("invalid header")
+	require.NotNil(t, err, "could not detect invalid header")
+}
+
 func TestActionScreenshot(t *testing.T) {
 	response := `
 		<html>
@@ -240,6 +313,170 @@ func TestActionScreenshotToDir(t *testing.T) {
 	})
 }
 
+func TestActionScreenshotElement(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<div id="banner" style="width:120px;height:40px;background:red">Banner</div>
+				<div id="empty"></div>
+			</body>
+		</html>`
+
+	filePath := filepath.Join(os.TempDir(), "element-"+strconv.Itoa(rand.Intn(1000)), "banner.png")
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScreenshotElement}, Data: map[string]string{"selector": "#banner"}, Name: "banner"},
+		{ActionType: ActionTypeHolder{ActionType: ActionScreenshotElement}, Data: map[string]string{"selector": "#banner", "to": filePath, "mkdir": "true"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		data, err := base64.StdEncoding.DecodeString(out["banner"])
+		require.Nil(t, err, "could not decode element screenshot")
+		require.True(t, bytes.HasPrefix(data, []byte("\x89PNG")), "could not get png element screenshot")
+		require.FileExists(t, filePath, "could not find screenshot file %v", filePath)
+		if err := os.RemoveAll(filepath.Dir(filePath)); err != nil {
+			t.Logf("got error %v while deleting temp file", err)
+		}
+	})
+
+	actions = []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScreenshotElement}, Data: map[string]string{"selector": "#empty"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.ErrorContains(t, err, "zero-sized", "could not detect zero-sized element")
+	})
+}
+
+func TestNewSetCookie(t *testing.T) {
+	baseURL, _ := url.Parse("http://example.com/login")
+	now := time.Unix(1700000000, 0)
+
+	cookie, err := newSetCookie(map[string]string{"name": "session", "value": "abc", "samesite": "Lax", "httponly": "true", "expires": "1h"}, baseURL, now)
+	require.Nil(t, err, "could not build cookie")
+	require.Equal(t, "http://example.com/login", cookie.URL, "could not scope cookie to target")
+	require.Equal(t, proto.NetworkCookieSameSiteLax, cookie.SameSite, "could not set samesite")
+	require.True(t, cookie.HTTPOnly, "could not set httponly")
+	require.Equal(t, proto.TimeSinceEpoch(1700003600), cookie.Expires, "could not set relative expiry")
+	require.Empty(t, insecureCookieWarning(cookie, baseURL), "got warning for insecure cookie")
+
+	cookie, err = newSetCookie(map[string]string{"name": "session", "domain": ".example.com", "secure": "true", "expires": "1800000000"}, baseURL, now)
+	require.Nil(t, err, "could not build cookie")
+	require.Empty(t, cookie.URL, "got url for domain cookie")
+	require.Equal(t, proto.TimeSinceEpoch(1800000000), cookie.Expires, "could not set absolute expiry")
+	require.Contains(t, insecureCookieWarning(cookie, baseURL), "non-https", "could not warn for secure cookie on http")
+
+	cookie, err = newSetCookie(map[string]string{"name": "session", "url": "https://example.com", "secure": "true", "samesite": "none"}, baseURL, now)
+	require.Nil(t, err, "could not build cookie")
+	require.Empty(t, insecureCookieWarning(cookie, baseURL), "got warning for secure cookie on https")
+
+	cookie, err = newSetCookie(map[string]string{"name": "session", "samesite": "none"}, baseURL, now)
+	require.Nil(t, err, "could not build cookie")
+	require.Contains(t, insecureCookieWarning(cookie, baseURL), "samesite none", "could not warn for samesite none without secure")
+
+	_, err = newSetCookie(map[string]string{"value": "abc"}, baseURL, now)
+	require.ErrorIs(t, err, errinvalidArguments, "could not detect missing name")
+	_, err = newSetCookie(map[string]string{"name": "session", "samesite": "loose"}, baseURL, now)
+	require.NotNil(t, err, "could not detect invalid samesite")
+	_, err = newSetCookie(map[string]string{"name": "session"}, nil, now)
+	require.NotNil(t, err, "could not detect missing cookie scope")
+}
+
+func TestActionServiceWorkers(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Data: map[string]string{"code": "() => navigator.serviceWorker.ready.then(() => true)"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionServiceWorkers}, Name: "workers"},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sw.js" {
+			w.Header().Set("Content-Type", "application/javascript")
+			_, _ = fmt.Fprint(w, `self.addEventListener("fetch", () => {})`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<html><body><script>navigator.serviceWorker.register("/sw.js")</script></body></html>`)
+	}
+
+	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		var workers []serviceWorker
+		require.Nil(t, json.Unmarshal([]byte(out["workers"]), &workers), "could not unmarshal service workers")
+		require.Len(t, workers, 1, "could not get service workers")
+		require.True(t, strings.HasSuffix(workers[0].ScriptURL, "/sw.js"), "could not get service worker script url")
+	})
+}
+
+func TestServiceWorkerRegistry(t *testing.T) {
+	registry := newServiceWorkerRegistry()
+	registry.updateRegistrations([]*proto.ServiceWorkerServiceWorkerRegistration{
+		{RegistrationID: "1", ScopeURL: "https://example.com/"},
+		{RegistrationID: "2", ScopeURL: "https://example.com/app/"},
+	})
+	registry.updateVersions([]*proto.ServiceWorkerServiceWorkerVersion{
+		{RegistrationID: "1", ScriptURL: "https://example.com/sw.js", Status: proto.ServiceWorkerServiceWorkerVersionStatusActivated},
+		{RegistrationID: "2", ScriptURL: "https://example.com/app/old.js", Status: proto.ServiceWorkerServiceWorkerVersionStatusActivated},
+	})
+	registry.updateVersions([]*proto.ServiceWorkerServiceWorkerVersion{
+		{RegistrationID: "2", ScriptURL: "https://example.com/app/new.js", Status: proto.ServiceWorkerServiceWorkerVersionStatusInstalled},
+		{RegistrationID: "2", ScriptURL: "https://example.com/app/old.js", Status: proto.ServiceWorkerServiceWorkerVersionStatusRedundant},
+	})
+	require.Equal(t, []serviceWorker{
+		{Scope: "https://example.com/", ScriptURL: "https://example.com/sw.js", Status: "activated"},
+		{Scope: "https://example.com/app/", ScriptURL: "https://example.com/app/new.js", Status: "installed"},
+	}, registry.serviceWorkers(), "could not get service workers")
+
+	registry.updateRegistrations([]*proto.ServiceWorkerServiceWorkerRegistration{{RegistrationID: "1", IsDeleted: true}})
+	require.Len(t, registry.serviceWorkers(), 1, "could not remove deleted registration")
+}
+
+func TestActionUnload(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionClick}, Data: map[string]string{"selector": "button"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionUnload}, Data: map[string]string{"url": "{{BaseURL}}/next"}, Name: "unload"},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/beacon":
+			w.WriteHeader(http.StatusNoContent)
+		case "/next":
+			_, _ = fmt.Fprint(w, `<html><body>next</body></html>`)
+		default:
+			_, _ = fmt.Fprint(w, `<html><body><button>leave</button><script>
+window.addEventListener("beforeunload", (e) => { e.preventDefault(); e.returnValue = ""; });
+window.addEventListener("pagehide", () => navigator.sendBeacon("/beacon", "bye"));
+</script></body></html>`)
+		}
+	}
+
+	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		var result unloadResult
+		require.Nil(t, json.Unmarshal([]byte(out["unload"]), &result), "could not unmarshal unload result")
+		require.True(t, result.Dialog, "could not get beforeunload dialog")
+		var beacon bool
+		for _, request := range result.Requests {
+			if strings.HasSuffix(request.URL, "/beacon") {
+				beacon = request.Method == http.MethodPost
+			}
+		}
+		require.True(t, beacon, "could not capture unload beacon")
+		require.True(t, strings.HasSuffix(page.URL(), "/next"), "could not navigate away")
+	})
+}
+
 func TestActionTimeInput(t *testing.T) {
 	response := `
 		<html>
@@ -383,179 +620,1518 @@ func TestActionExtract(t *testing.T) {
 	})
 }
 
-func TestActionSetMethod(t *testing.T) {
+func TestActionGetElementInfo(t *testing.T) {
 	response := `
 		<html>
 			<head>
 				<title>Nuclei Test Page</title>
 			</head>
+			<body style="margin:0">
+				<div id="box" style="position:absolute;left:10px;top:20px;width:100px;height:50px;z-index:5">box</div>
+				<div id="cover" style="position:absolute;left:200px;top:20px;width:100px;height:50px">covered</div>
+				<div style="position:absolute;left:200px;top:20px;width:100px;height:50px;z-index:10">overlay</div>
+				<div id="hidden" style="display:none">hidden</div>
+			</body>
 		</html>`
 
 	actions := []*Action{
 		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
-		{ActionType: ActionTypeHolder{ActionType: ActionSetMethod}, Data: map[string]string{"part": "x", "method": "SET"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionGetElementInfo}, Data: map[string]string{"selector": "#box"}, Name: "box"},
+		{ActionType: ActionTypeHolder{ActionType: ActionGetElementInfo}, Data: map[string]string{"by": "x", "xpath": "//div[@id='cover']"}, Name: "cover"},
+		{ActionType: ActionTypeHolder{ActionType: ActionGetElementInfo}, Data: map[string]string{"selector": "#hidden"}, Name: "hidden"},
 	}
 
 	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
 		require.Nil(t, err, "could not run page actions")
-		require.Equal(t, "SET", page.rules[0].Args["method"], "could not find resource")
+		require.Equal(t, "10", out["box_x"], "could not get element x")
+		require.Equal(t, "20", out["box_y"], "could not get element y")
+		require.Equal(t, "100", out["box_width"], "could not get element width")
+		require.Equal(t, "50", out["box_height"], "could not get element height")
+		require.Equal(t, "5", out["box_z_index"], "could not get element z-index")
+		require.Equal(t, "true", out["box_visible"], "could not get element visibility")
+		require.Equal(t, "false", out["box_occluded"], "could not get element occlusion")
+		require.Equal(t, "true", out["cover_occluded"], "could not detect occluded element")
+		require.Equal(t, "false", out["hidden_visible"], "could not detect hidden element")
 	})
 }
 
-func TestActionAddHeader(t *testing.T) {
+func TestActionComputedStyle(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+				<style>.offscreen { position: absolute; left: -9999px; --admin: yes; }</style>
+			</head>
+			<body>
+				<a id="admin" class="offscreen" href="/admin">admin</a>
+				<form id="hidden" style="display:none"></form>
+			</body>
+		</html>`
+
 	actions := []*Action{
-		{ActionType: ActionTypeHolder{ActionType: ActionAddHeader}, Data: map[string]string{"part": "request", "key": "Test", "value": "Hello"}},
 		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
 		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionComputedStyle}, Data: map[string]string{"selector": "#admin", "properties": "position, Left, --admin"}, Name: "admin"},
+		{ActionType: ActionTypeHolder{ActionType: ActionComputedStyle}, Data: map[string]string{"by": "x", "xpath": "//form[@id='hidden']"}, Name: "form"},
 	}
 
-	handler := func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("Test") == "Hello" {
-			_, _ = fmt.Fprintln(w, `found`)
-		}
-	}
-
-	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
 		require.Nil(t, err, "could not run page actions")
-		require.Equal(t, "found", strings.ToLower(strings.TrimSpace(page.Page().MustElement("html").MustText())), "could not set header correctly")
+		require.Equal(t, "absolute", out["admin_position"], "could not get computed position")
+		require.Equal(t, "-9999px", out["admin_left"], "could not get computed left")
+		require.Equal(t, "yes", out["admin_admin"], "could not get custom property")
+		require.JSONEq(t, `{"position":"absolute","left":"-9999px","--admin":"yes"}`, out["admin"], "could not get computed styles")
+
+		var styles map[string]string
+		require.Nil(t, json.Unmarshal([]byte(out["form"]), &styles), "could not unmarshal computed styles")
+		require.Equal(t, "none", styles["display"], "could not get all computed styles")
 	})
 }
 
-func TestActionDeleteHeader(t *testing.T) {
+func TestParseStyleProperties(t *testing.T) {
+	require.Equal(t, []string{"display", "background-color", "--Theme"}, parseStyleProperties(" Display,background-color,,display, --Theme"), "could not parse style properties")
+	require.Empty(t, parseStyleProperties(""), "got properties for empty value")
+	require.Equal(t, "background_color", styleVariableName("background-color"), "could not get style variable name")
+	require.Equal(t, "Theme", styleVariableName("--Theme"), "could not get custom property variable name")
+}
+
+func TestActionEvalOnNewDocument(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+				<script>window.seen = typeof window.hooked; setTimeout(() => { window.sink = location.hash || "fired"; }, 100);</script>
+			</head>
+			<body>Nuclei Test Page</body>
+		</html>`
+
 	actions := []*Action{
-		{ActionType: ActionTypeHolder{ActionType: ActionAddHeader}, Data: map[string]string{"part": "request", "key": "Test1", "value": "Hello"}},
-		{ActionType: ActionTypeHolder{ActionType: ActionAddHeader}, Data: map[string]string{"part": "request", "key": "Test2", "value": "World"}},
-		{ActionType: ActionTypeHolder{ActionType: ActionDeleteHeader}, Data: map[string]string{"part": "request", "key": "Test2"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionEvalOnNewDocument}, Data: map[string]string{"code": "window.hooked = 1;"}, Name: "hook"},
 		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
 		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Data: map[string]string{"code": "() => window.seen"}, Name: "hooked"},
+		{ActionType: ActionTypeHolder{ActionType: ActionEvalOnNewDocument}, Data: map[string]string{"code": "window.probe = true;", "signal": "() => window.sink"}, Name: "signal"},
+		{ActionType: ActionTypeHolder{ActionType: ActionEvalOnNewDocument}, Data: map[string]string{"code": "void 0;", "signal": "() => window.missing", "timeout": "1"}, Name: "missing"},
+		{ActionType: ActionTypeHolder{ActionType: ActionEvalOnNewDocument}, Data: map[string]string{"remove": "hook"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Data: map[string]string{"code": "() => window.seen"}, Name: "removed"},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Data: map[string]string{"code": "() => String(window.probe)"}, Name: "probe"},
 	}
 
-	handler := func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("Test1") == "Hello" && r.Header.Get("Test2") == "" {
-			_, _ = fmt.Fprintln(w, `header deleted`)
-		}
-	}
-
-	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
 		require.Nil(t, err, "could not run page actions")
-		require.Equal(t, "header deleted", strings.ToLower(strings.TrimSpace(page.Page().MustElement("html").MustText())), "could not delete header correctly")
+		require.Equal(t, "number", out["hooked"], "script did not run before page scripts")
+		require.Equal(t, "fired", out["signal"], "could not wait for signal")
+		require.Empty(t, out["missing"], "got value for missing signal")
+		require.Equal(t, "undefined", out["removed"], "script ran after being removed")
+		require.Equal(t, "true", out["probe"], "script without name was not persisted")
 	})
 }
 
-func TestActionSetBody(t *testing.T) {
+func TestSignalValue(t *testing.T) {
+	require.Equal(t, "", signalValue(nil), "got value for nil signal")
+	require.Equal(t, "", signalValue(false), "got value for false signal")
+	require.Equal(t, "", signalValue(float64(0)), "got value for zero signal")
+	require.Equal(t, "", signalValue(""), "got value for empty signal")
+	require.Equal(t, "true", signalValue(true), "could not get bool signal")
+	require.Equal(t, "polluted", signalValue("polluted"), "could not get string signal")
+	require.Equal(t, "2", signalValue(float64(2)), "could not get number signal")
+	require.Equal(t, `{"sink":"eval"}`, signalValue(map[string]interface{}{"sink": "eval"}), "could not get object signal")
+}
+
+func TestFindMixedContent(t *testing.T) {
+	history := []HistoryData{
+		{URL: "http://example.com/", ResourceType: proto.NetworkResourceTypeDocument},
+		{URL: "http://example.com/old.js", ResourceType: proto.NetworkResourceTypeScript},
+		{URL: "https://example.com/", ResourceType: proto.NetworkResourceTypeDocument},
+		{URL: "https://example.com/app.js", ResourceType: proto.NetworkResourceTypeScript},
+		{URL: "http://cdn.example.com/lib.js", ResourceType: proto.NetworkResourceTypeScript},
+		{URL: "http://cdn.example.com/logo.png", ResourceType: proto.NetworkResourceTypeImage},
+		{URL: "http://cdn.example.com/logo.png", ResourceType: proto.NetworkResourceTypeImage},
+		{URL: "http://ads.example.com/frame", ResourceType: proto.NetworkResourceTypeDocument},
+	}
+
+	items := findMixedContent(history, "https://example.com/#top")
+	require.Equal(t, []mixedContent{
+		{URL: "http://cdn.example.com/lib.js", Type: "Script", Active: true},
+		{URL: "http://cdn.example.com/logo.png", Type: "Image", Active: false},
+		{URL: "http://ads.example.com/frame", Type: "Document", Active: true},
+	}, items, "could not find mixed content")
+
+	require.Empty(t, findMixedContent(history, "http://example.com/"), "found mixed content on http page")
+	require.Empty(t, findMixedContent(history[:4], "https://example.com/"), "found mixed content on secure page")
+}
+
+func TestDOMNormalizer(t *testing.T) {
+	newNode := func(nonce, timestamp string) *domNode {
+		return &domNode{Tag: "html", Children: []*domNode{
+			{Tag: "body", Attributes: map[string]string{"class": "main", "data-nonce": nonce, "id": "page"}, Children: []*domNode{
+				{Text: "\n\t  "},
+				{Tag: "p", Children: []*domNode{{Text: "  Updated   at " + timestamp + " "}}},
+				{Tag: "script", Attributes: map[string]string{"NONCE": nonce}},
+			}},
+		}}
+	}
+
+	normalizer, err := newDOMNormalizer("", "")
+	require.Nil(t, err, "could not create normalizer")
+	builder := &strings.Builder{}
+	normalizer.serialize(builder, newNode("abc", "12:00"))
+	require.Equal(t, `<html><body class="main" data-nonce="abc" id="page"><p>Updated at 12:00</p><script NONCE="abc"></script></body></html>`, builder.String(), "could not serialize dom")
+	require.NotEqual(t, normalizer.hash(newNode("abc", "12:00")), normalizer.hash(newNode("def", "13:00")), "volatile content not hashed")
+
+	normalizer, err = newDOMNormalizer("nonce, data-.*", `\d{2}:\d{2}`+"\n")
+	require.Nil(t, err, "could not create normalizer")
+	builder = &strings.Builder{}
+	normalizer.serialize(builder, newNode("abc", "12:00"))
+	require.Equal(t, `<html><body class="main" id="page"><p>Updated at</p><script></script></body></html>`, builder.String(), "could not normalize dom")
+	require.Equal(t, normalizer.hash(newNode("abc", "12:00")), normalizer.hash(newNode("def", "13:00")), "volatile content hashed")
+	require.Len(t, normalizer.hash(newNode("abc", "12:00")), 64, "wrong hash length")
+
+	_, err = newDOMNormalizer("(", "")
+	require.NotNil(t, err, "invalid attributes pattern accepted")
+}
+
+func TestActionExtractLinksAndForms(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<a href="/first">first</a>
+				<a href="/first">duplicate</a>
+				<a href="https://example.com/second">second</a>
+				<form action="/login" method="post">
+					<input type="text" name="username" value="admin">
+					<input type="password" name="password">
+					<input type="submit" value="Login">
+				</form>
+			</body>
+		</html>`
+
 	actions := []*Action{
-		{ActionType: ActionTypeHolder{ActionType: ActionSetBody}, Data: map[string]string{"part": "request", "body": "hello"}},
 		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
 		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionExtractLinks}, Name: "links"},
+		{ActionType: ActionTypeHolder{ActionType: ActionExtractForms}, Name: "forms"},
 	}
 
-	handler := func(w http.ResponseWriter, r *http.Request) {
-		body, _ := io.ReadAll(r.Body)
-		_, _ = fmt.Fprintln(w, string(body))
-	}
-
-	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
 		require.Nil(t, err, "could not run page actions")
-		require.Equal(t, "hello", strings.ToLower(strings.TrimSpace(page.Page().MustElement("html").MustText())), "could not set header correctly")
+
+		var links []string
+		require.Nil(t, json.Unmarshal([]byte(out["links"]), &links), "could not unmarshal links")
+		require.Len(t, links, 2, "could not deduplicate links")
+		require.True(t, strings.HasPrefix(links[0], "http://") && strings.HasSuffix(links[0], "/first"), "could not get absolute link")
+		require.Equal(t, "https://example.com/second", links[1], "could not extract links")
+
+		var forms []struct {
+			Action string `json:"action"`
+			Method string `json:"method"`
+			Inputs []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"inputs"`
+		}
+		require.Nil(t, json.Unmarshal([]byte(out["forms"]), &forms), "could not unmarshal forms")
+		require.Len(t, forms, 1, "could not extract forms")
+		require.True(t, strings.HasSuffix(forms[0].Action, "/login"), "could not get absolute form action")
+		require.Equal(t, "POST", forms[0].Method, "could not get form method")
+		require.Len(t, forms[0].Inputs, 2, "could not get named form inputs")
+		require.Equal(t, "admin", forms[0].Inputs[0].Value, "could not get form input value")
 	})
 }
 
-func TestActionKeyboard(t *testing.T) {
+func TestOutgoingRequestsHistory(t *testing.T) {
 	response := `
 		<html>
 			<head>
 				<title>Nuclei Test Page</title>
 			</head>
 			<body>
-				<input type="text" name="test" id="test">
+				<script>
+					fetch("/collect", {method: "POST", body: "marker-" + "x".repeat(20)}).then(() => { document.title = "done"; });
+				</script>
 			</body>
 		</html>`
 
 	actions := []*Action{
 		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
 		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
-		{ActionType: ActionTypeHolder{ActionType: ActionClick}, Data: map[string]string{"selector": "input"}},
-		{ActionType: ActionTypeHolder{ActionType: ActionKeyboard}, Data: map[string]string{"keys": "Test2"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionSleep}, Data: map[string]string{"duration": "1"}},
 	}
 
-	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/collect" {
+			return
+		}
+		_, _ = fmt.Fprintln(w, response)
+	}, func(page *Page, err error, out map[string]string) {
 		require.Nil(t, err, "could not run page actions")
-		el := page.Page().MustElement("input")
-		require.Equal(t, "Test2", el.MustText(), "could not get input change value")
+		outgoing := page.DumpOutgoingRequests()
+		require.Contains(t, outgoing, "POST http://", "could not get outgoing request")
+		require.Contains(t, outgoing, "/collect", "could not get outgoing request url")
+		require.Contains(t, outgoing, "marker-xxxx", "could not get outgoing request body")
 	})
 }
 
-func TestActionSleep(t *testing.T) {
+func TestResourceCounts(t *testing.T) {
+	page := &Page{mutex: &sync.RWMutex{}, History: []HistoryData{
+		{URL: "https://www.example.com/", ResourceType: proto.NetworkResourceTypeDocument},
+		{URL: "https://static.example.com/app.js", ResourceType: proto.NetworkResourceTypeScript},
+		{URL: "https://cdn.tracker.net/a.js", ResourceType: proto.NetworkResourceTypeScript},
+		{URL: "https://miner.example.org/m.js", ResourceType: proto.NetworkResourceTypeScript},
+		{URL: "https://cdn.tracker.net/pixel.gif", ResourceType: proto.NetworkResourceTypeImage},
+	}}
+
+	counts := page.ResourceCounts()
+	require.Equal(t, 5, counts["resources"], "could not get resources count")
+	require.Equal(t, 3, counts["resources_script"], "could not get script resources count")
+	require.Equal(t, 3, counts["third_party_resources"], "could not get third-party resources count")
+	require.Equal(t, 2, counts["third_party_resources_script"], "could not get third-party script resources count")
+	require.Equal(t, 1, counts["third_party_resources_image"], "could not get third-party image resources count")
+	require.Equal(t, 2, counts["third_party_domains"], "could not get third-party domains count")
+	require.Nil(t, counts["third_party_resources_document"], "could not exclude first-party resources")
+}
+
+func TestActionJSEndpoints(t *testing.T) {
+	page := &Page{mutex: &sync.RWMutex{}, History: []HistoryData{
+		{
+			URL:          "https://example.com/app/",
+			ResourceType: proto.NetworkResourceTypeDocument,
+			ResponseBody: `<html><a href="/not-script">x</a><script>fetch("/api/v1/users?id=1");</script></html>`,
+		},
+		{
+			URL:          "https://static.example.com/js/main.js",
+			ResourceType: proto.NetworkResourceTypeScript,
+			ResponseBody: `const a = "/api/v1/users?id=1"; axios.get('./config.json'); load("https://api.example.com/graphql#x"); const b = "javascript:void(0)";`,
+		},
+		{
+			URL:          "https://example.com/style.css",
+			ResourceType: proto.NetworkResourceTypeStylesheet,
+			ResponseBody: `body { background: url("/img/bg.png"); }`,
+		},
+	}}
+
+	out := make(map[string]string)
+	err := page.ExtractJSEndpoints(&Action{Name: "endpoints"}, out)
+	require.Nil(t, err, "could not extract endpoints")
+
+	var endpoints []string
+	require.Nil(t, json.Unmarshal([]byte(out["endpoints"]), &endpoints), "could not unmarshal endpoints")
+	require.ElementsMatch(t, []string{
+		"https://example.com/api/v1/users?id=1",
+		"https://static.example.com/api/v1/users?id=1",
+		"https://static.example.com/js/config.json",
+		"https://api.example.com/graphql",
+	}, endpoints, "could not get endpoints")
+
+	out = make(map[string]string)
+	err = page.ExtractJSEndpoints(&Action{Name: "endpoints", Data: map[string]string{"patterns": `"(/api/[^"]+)"`}}, out)
+	require.Nil(t, err, "could not extract endpoints with patterns")
+	require.Equal(t, `["https://example.com/api/v1/users?id=1","https://static.example.com/api/v1/users?id=1"]`, out["endpoints"], "could not get endpoints with patterns")
+}
+
+func TestActionWaitResponse(t *testing.T) {
 	response := `
 		<html>
 			<head>
 				<title>Nuclei Test Page</title>
 			</head>
-			<button style="display:none" id="test">Wait for me!</button>
-			<script>
-				setTimeout(() => document.querySelector('#test').style.display = '', 1000);
-			</script>
+			<body>
+				<button id="load" onclick="fetch('/api/user')">Load</button>
+			</body>
 		</html>`
 
 	actions := []*Action{
 		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
-		{ActionType: ActionTypeHolder{ActionType: ActionSleep}, Data: map[string]string{"duration": "2"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionClick}, Data: map[string]string{"selector": "#load"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitResponse}, Name: "user", Data: map[string]string{"url": "/api/user$", "timeout": "5"}},
 	}
 
-	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/user" {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprint(w, `{"email":"admin@example.com"}`)
+			return
+		}
+		_, _ = fmt.Fprintln(w, response)
+	}, func(page *Page, err error, out map[string]string) {
 		require.Nil(t, err, "could not run page actions")
-		require.True(t, page.Page().MustElement("button").MustVisible(), "could not get button")
+		require.Equal(t, `{"email":"admin@example.com"}`, out["user"], "could not capture response body")
+		require.Equal(t, "201", out["user_status"], "could not capture response status")
+		require.True(t, strings.HasSuffix(out["user_url"], "/api/user"), "could not capture response url")
 	})
 }
 
-func TestActionWaitVisible(t *testing.T) {
+func TestActionSwitchTab(t *testing.T) {
 	response := `
 		<html>
 			<head>
 				<title>Nuclei Test Page</title>
 			</head>
-			<button style="display:none" id="test">Wait for me!</button>
-			<script>
-				setTimeout(() => document.querySelector('#test').style.display = '', 1000);
-			</script>
+			<body>
+				<button id="open" onclick="window.open('/popup?token=secret')">Open</button>
+			</body>
 		</html>`
 
 	actions := []*Action{
 		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
-		{ActionType: ActionTypeHolder{ActionType: ActionWaitVisible}, Data: map[string]string{"by": "x", "xpath": "//button[@id='test']"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionClick}, Data: map[string]string{"selector": "#open"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionSwitchTab}, Name: "popup", Data: map[string]string{"url": "/popup", "timeout": "5"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionSwitchTab}, Name: "main", Data: map[string]string{"index": "0"}},
 	}
 
-	t.Run("wait for an element being visible", func(t *testing.T) {
-		testHeadlessSimpleResponse(t, response, actions, 2*time.Second, func(page *Page, err error, out map[string]string) {
-			require.Nil(t, err, "could not run page actions")
-
-			page.Page().MustElement("button").MustVisible()
-		})
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/popup" {
+			_, _ = fmt.Fprint(w, `<html><body><script>window.name = "payload"</script>Popup Token</body></html>`)
+			return
+		}
+		_, _ = fmt.Fprintln(w, response)
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.True(t, strings.HasSuffix(out["popup"], "/popup?token=secret"), "could not capture popup url")
+		require.Contains(t, out["popup_html"], "Popup Token", "could not capture popup content")
+		require.Equal(t, "payload", out["popup_window_name"], "could not capture popup window name")
+		require.Equal(t, "1", out["popup_count"], "could not enumerate popups")
+		require.Contains(t, out["main_html"], "Nuclei Test Page", "could not switch back to the page")
+		require.Len(t, page.Popups, 1, "popup not tracked")
 	})
+}
 
-	t.Run("timeout because of element not visible", func(t *testing.T) {
-		// increased timeout from time.Second/2 to time.Second due to random fails (probably due to overhead and system)
-		testHeadlessSimpleResponse(t, response, actions, time.Second, func(page *Page, err error, out map[string]string) {
-			require.Error(t, err)
-			require.Contains(t, err.Error(), "Element did not appear in the given amount of time")
-		})
+func TestActionOpenRedirect(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionOpenRedirect}, Data: map[string]string{"url": "{{BaseURL}}/login", "params": "next", "marker": "nuclei.invalid", "timeout": "5"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionOpenRedirect}, Name: "safe", Data: map[string]string{"url": "{{BaseURL}}/safe", "params": "next", "marker": "nuclei.invalid", "timeout": "1"}},
+	}
+
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			_, _ = fmt.Fprint(w, `<html><body><script>setTimeout(() => { location.href = new URLSearchParams(location.search).get("next") }, 100)</script></body></html>`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<html><body>Safe</body></html>`)
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "true", out["redirect"], "could not detect client-side redirect")
+		require.Equal(t, "https://nuclei.invalid/", out["redirect_url"], "wrong redirect destination")
+		require.Equal(t, "client", out["redirect_type"], "wrong redirect type")
+		require.Equal(t, "false", out["safe"], "redirect detected without redirect")
+		require.True(t, strings.HasSuffix(out["safe_final_url"], "/safe?next=https%3A%2F%2Fnuclei.invalid%2F"), "wrong final url")
 	})
 }
 
-func testHeadlessSimpleResponse(t *testing.T, response string, actions []*Action, timeout time.Duration, assert func(page *Page, pageErr error, out map[string]string)) {
-	t.Helper()
-	testHeadless(t, actions, timeout, func(w http.ResponseWriter, r *http.Request) {
-		_, _ = fmt.Fprintln(w, response)
-	}, assert)
+func TestInjectRedirectPayload(t *testing.T) {
+	target, err := injectRedirectPayload("https://example.com/login?next=/home&lang=en", "next, return", "https://nuclei.invalid/")
+	require.Nil(t, err, "could not inject payload")
+	require.Equal(t, "https://example.com/login?lang=en&next=https%3A%2F%2Fnuclei.invalid%2F&return=https%3A%2F%2Fnuclei.invalid%2F", target, "payload not injected")
+
+	target, err = injectRedirectPayload("https://example.com/login?next=https://nuclei.invalid/", "", "https://nuclei.invalid/")
+	require.Nil(t, err, "could not inject payload")
+	require.Equal(t, "https://example.com/login?next=https://nuclei.invalid/", target, "url without params modified")
 }
 
-func testHeadless(t *testing.T, actions []*Action, timeout time.Duration, handler func(w http.ResponseWriter, r *http.Request), assert func(page *Page, pageErr error, extractedData map[string]string)) {
-	t.Helper()
-	_ = protocolstate.Init(&types.Options{})
+func TestFindRedirect(t *testing.T) {
+	chain := []redirectHop{
+		{URL: "https://nuclei.invalid/start"},
+		{URL: "https://example.com/login", Server: true},
+		{URL: "https://sub.nuclei.invalid/", Server: false},
+	}
+	hop, found := findRedirect(chain, "nuclei.invalid")
+	require.True(t, found, "could not find redirect")
+	require.Equal(t, chain[2], hop, "wrong redirect found")
+
+	_, found = findRedirect(chain[:2], "nuclei.invalid")
+	require.False(t, found, "navigation reported as redirect")
+	_, found = findRedirect([]redirectHop{{URL: "https://example.com/"}, {URL: "https://evilnuclei.invalid/"}}, "nuclei.invalid")
+	require.False(t, found, "redirect to other host reported")
+}
 
-	browser, err := New(&types.Options{ShowBrowser: false, UseInstalledChrome: testheadless.HeadlessLocal})
-	require.Nil(t, err, "could not create browser")
-	defer browser.Close()
+func TestActionFillForm(t *testing.T) {
+	response := `
+		<html>
+			<body>
+				<form action="/login" method="post">
+					<input name="username" value="guest">
+					<input name="password" type="password">
+					<input name="remember" type="checkbox">
+					<select name="lang"><option value="en">English</option><option value="fr">French</option></select>
+					<textarea name="note">untouched</textarea>
+					<button type="submit">Login</button>
+				</form>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionFillForm}, Name: "login", Data: map[string]string{"fields": "username=admin\npassword=' or 1=1-- \nremember=true\nlang=French"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+	}
+
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			_ = r.ParseForm()
+			_, _ = fmt.Fprintf(w, "<html><body>%s|%s|%s|%s|%s</body></html>", r.PostForm.Get("username"), r.PostForm.Get("password"), r.PostForm.Get("remember"), r.PostForm.Get("lang"), r.PostForm.Get("note"))
+			return
+		}
+		_, _ = fmt.Fprintln(w, response)
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.True(t, strings.HasSuffix(out["login"], "/login"), "could not capture form action")
+		require.Equal(t, "POST", out["login_method"], "could not capture form method")
+		require.Contains(t, page.HTML(), "admin|' or 1=1-- |on|fr|untouched", "form not filled and submitted")
+	})
+}
+
+func TestParseFormFields(t *testing.T) {
+	fields, err := parseFormFields("username=admin\n\n  password=a=b \r\n")
+	require.Nil(t, err, "could not parse form fields")
+	require.Equal(t, [][2]string{{"username", "admin"}, {"password", "a=b "}}, fields, "wrong form fields")
+
+	_, err = parseFormFields("username")
+	require.NotNil(t, err, "field without value parsed")
+}
+
+func TestActionRedirects(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<meta http-equiv="refresh" content="30; url=/meta">
+				<script>setTimeout(() => { window.location.replace("/delayed") }, 200)</script>
+			</head>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionRedirects}, Data: map[string]string{"timeout": "5"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "true", out["redirects_found"], "could not detect redirects")
+		require.True(t, strings.HasSuffix(out["redirects_meta"], "/meta"), "could not detect meta refresh")
+		require.True(t, strings.HasSuffix(out["redirects_script"], "/delayed"), "could not detect script redirect")
+		require.True(t, strings.HasSuffix(out["redirects_observed"], "/delayed"), "could not observe script redirect")
+	})
+}
+
+func TestParseMetaRefresh(t *testing.T) {
+	tests := []struct {
+		content string
+		delay   string
+		target  string
+		ok      bool
+	}{
+		{content: "0; url=https://example.com/", delay: "0", target: "https://example.com/", ok: true},
+		{content: "5;URL='/next?a=1'", delay: "5", target: "/next?a=1", ok: true},
+		{content: "3, https://example.com/", delay: "3", target: "https://example.com/", ok: true},
+		{content: "10", ok: false},
+		{content: "1; url=", ok: false},
+	}
+	for _, test := range tests {
+		delay, target, ok := parseMetaRefresh(test.content)
+		require.Equal(t, test.ok, ok, "wrong meta refresh detection for %s", test.content)
+		require.Equal(t, test.delay, delay, "wrong meta refresh delay for %s", test.content)
+		require.Equal(t, test.target, target, "wrong meta refresh target for %s", test.content)
+	}
+}
+
+func TestFindStaticRedirects(t *testing.T) {
+	sources := &redirectSources{
+		URL:  "https://example.com/app/",
+		Meta: []string{"0; url=../login", "60"},
+		Scripts: []string{
+			`if (!token) { window.location.href = "/login"; } else { location.replace('https://evil.example/'); }`,
+			"document.location = `next`; top.location.assign(\"//cdn.example.com/x\");",
+			"location.href = `/${path}`; if (location.href == \"/x\") {}",
+			`window.location.href = "/login";`,
+		},
+	}
+	require.Equal(t, []autoRedirect{
+		{URL: "https://example.com/login", Source: "meta", Delay: "0"},
+		{URL: "https://example.com/login", Source: "script"},
+		{URL: "https://evil.example/", Source: "script"},
+		{URL: "https://example.com/app/next", Source: "script"},
+		{URL: "https://cdn.example.com/x", Source: "script"},
+	}, findStaticRedirects(sources), "could not find redirects")
+}
+
+func TestActionAccessibilityTree(t *testing.T) {
+	response := `
+		<html>
+			<body>
+				<h1>Nuclei Test Page</h1>
+				<button style="opacity: 0" aria-label="Delete account">X</button>
+				<a href="/admin">Admin</a>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionAccessibilityTree}, Name: "controls", Data: map[string]string{"roles": "button, link"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "button: Delete account\nlink: Admin", out["controls_text"], "could not capture accessibility tree")
+		require.Equal(t, "2", out["controls_count"], "wrong node count")
+		require.Equal(t, "false", out["controls_truncated"], "tree truncated")
+	})
+}
+
+func TestFlattenAXTree(t *testing.T) {
+	value := func(v interface{}) *proto.AccessibilityAXValue {
+		return &proto.AccessibilityAXValue{Value: gson.New(v)}
+	}
+	tree := []*proto.AccessibilityAXNode{
+		{Role: value("RootWebArea"), Name: value("Test")},
+		{Role: value("button"), Name: value("Delete"), Properties: []*proto.AccessibilityAXProperty{{Name: "focusable", Value: value(true)}}},
+		{Role: value("generic"), Ignored: true},
+		{Role: value("StaticText"), Name: value("Delete")},
+		{Role: value("InlineTextBox"), Name: value("Delete")},
+		{Role: value("link"), Name: value("Admin")},
+	}
+
+	nodes, truncated := flattenAXTree(tree, nil, 10)
+	require.False(t, truncated, "tree truncated under the limit")
+	require.Equal(t, []axNode{
+		{Role: "RootWebArea", Name: "Test"},
+		{Role: "button", Name: "Delete", Properties: map[string]string{"focusable": "true"}},
+		{Role: "StaticText", Name: "Delete"},
+		{Role: "link", Name: "Admin"},
+	}, nodes, "could not flatten tree")
+
+	nodes, truncated = flattenAXTree(tree, map[string]struct{}{"link": {}, "button": {}}, 1)
+	require.True(t, truncated, "tree not truncated over the limit")
+	require.Equal(t, []axNode{{Role: "button", Name: "Delete", Properties: map[string]string{"focusable": "true"}}}, nodes, "could not filter tree by role")
+}
+
+func TestActionDOMXSS(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionDOMXSS}, Data: map[string]string{"url": "{{BaseURL}}/search", "marker": "nucleimarker", "timeout": "5"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionDOMXSS}, Name: "safe", Data: map[string]string{"url": "{{BaseURL}}/safe", "params": "q", "marker": "nucleimarker", "timeout": "1"}},
+	}
+
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search" {
+			_, _ = fmt.Fprint(w, `<html><body><div id="results"></div><script>document.getElementById("results").innerHTML = "Results for " + decodeURIComponent(location.hash.slice(1))</script></body></html>`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<html><body><div id="results"></div><script>document.getElementById("results").textContent = new URLSearchParams(location.search).get("q")</script></body></html>`)
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "true", out["domxss"], "could not detect tainted sink")
+		require.Equal(t, "innerHTML", out["domxss_sink"], "wrong tainted sink")
+		require.Equal(t, `Results for '"><img src=x id=nucleimarker>`, out["domxss_evidence"], "wrong evidence")
+		require.Equal(t, "true", out["domxss_verbatim"], "payload not received verbatim")
+		require.Equal(t, "false", out["safe"], "tainted sink detected without sink")
+		require.Equal(t, "[]", out["safe_sinks"], "wrong sinks")
+	})
+}
+
+func TestActionSetMethod(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionSetMethod}, Data: map[string]string{"part": "x", "method": "SET"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "SET", page.rules[0].Args["method"], "could not find resource")
+	})
+}
+
+func TestActionAddHeader(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionAddHeader}, Data: map[string]string{"part": "request", "key": "Test", "value": "Hello"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Test") == "Hello" {
+			_, _ = fmt.Fprintln(w, `found`)
+		}
+	}
+
+	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "found", strings.ToLower(strings.TrimSpace(page.Page().MustElement("html").MustText())), "could not set header correctly")
+	})
+}
+
+func TestActionSetReferrer(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionSetReferrer}, Data: map[string]string{"referrer": "https://referrer.example.com/secret?token=1"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Name: "referrer", Data: map[string]string{"code": "() => document.referrer"}},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, r.Header.Get("Referer"))
+	}
+
+	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "https://referrer.example.com/secret?token=1", strings.TrimSpace(page.Page().MustElement("html").MustText()), "could not set referer header")
+		require.Equal(t, "https://referrer.example.com/secret?token=1", out["referrer"], "could not set document referrer")
+		require.Contains(t, page.DumpReferrers(), "https://referrer.example.com/secret?token=1", "could not get referrer from history")
+	})
+}
+
+func TestDocumentHeaders(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Powered-By", "nuclei")
+		w.Header().Add("X-Duplicate", "first")
+		w.Header().Add("X-Duplicate", "second")
+		_, _ = fmt.Fprintln(w, "headers")
+	}
+
+	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		headers := page.DocumentHeaders()
+		require.Equal(t, "nuclei", headers.Get("X-Powered-By"), "could not get document header")
+		require.Contains(t, strings.Join(headers.Values("X-Duplicate"), "\n"), "second", "could not get duplicate document headers")
+	})
+}
+
+func TestActionDeleteHeader(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionAddHeader}, Data: map[string]string{"part": "request", "key": "Test1", "value": "Hello"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionAddHeader}, Data: map[string]string{"part": "request", "key": "Test2", "value": "World"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionDeleteHeader}, Data: map[string]string{"part": "request", "key": "Test2"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Test1") == "Hello" && r.Header.Get("Test2") == "" {
+			_, _ = fmt.Fprintln(w, `header deleted`)
+		}
+	}
+
+	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "header deleted", strings.ToLower(strings.TrimSpace(page.Page().MustElement("html").MustText())), "could not delete header correctly")
+	})
+}
+
+func TestActionSetBody(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionSetBody}, Data: map[string]string{"part": "request", "body": "hello"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintln(w, string(body))
+	}
+
+	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "hello", strings.ToLower(strings.TrimSpace(page.Page().MustElement("html").MustText())), "could not set header correctly")
+	})
+}
+
+func TestActionKeyboard(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<input type="text" name="test" id="test">
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionClick}, Data: map[string]string{"selector": "input"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionKeyboard}, Data: map[string]string{"keys": "Test2"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		el := page.Page().MustElement("input")
+		require.Equal(t, "Test2", el.MustText(), "could not get input change value")
+	})
+}
+
+func TestActionSleep(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<button style="display:none" id="test">Wait for me!</button>
+			<script>
+				setTimeout(() => document.querySelector('#test').style.display = '', 1000);
+			</script>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionSleep}, Data: map[string]string{"duration": "2"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.True(t, page.Page().MustElement("button").MustVisible(), "could not get button")
+	})
+}
+
+func TestActionWaitVisible(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<button style="display:none" id="test">Wait for me!</button>
+			<script>
+				setTimeout(() => document.querySelector('#test').style.display = '', 1000);
+			</script>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitVisible}, Data: map[string]string{"by": "x", "xpath": "//button[@id='test']"}},
+	}
+
+	t.Run("wait for an element being visible", func(t *testing.T) {
+		testHeadlessSimpleResponse(t, response, actions, 2*time.Second, func(page *Page, err error, out map[string]string) {
+			require.Nil(t, err, "could not run page actions")
+
+			page.Page().MustElement("button").MustVisible()
+		})
+	})
+
+	t.Run("timeout because of element not visible", func(t *testing.T) {
+		// increased timeout from time.Second/2 to time.Second due to random fails (probably due to overhead and system)
+		testHeadlessSimpleResponse(t, response, actions, time.Second, func(page *Page, err error, out map[string]string) {
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "Element did not appear in the given amount of time")
+		})
+	})
+}
+
+func TestActionCSPNonce(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionCSPNonce}, Data: map[string]string{"reloads": "2"}},
+	}
+
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "script-src 'nonce-r4nd0m' 'strict-dynamic'")
+		_, _ = fmt.Fprintln(w, `<html><body><script nonce="r4nd0m">document.title = "csp"</script></body></html>`)
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "true", out["csp_nonce_found"], "could not detect nonce reuse")
+		require.Equal(t, "r4nd0m", out["csp_nonce_reused"], "wrong reused nonces")
+	})
+}
+
+func TestActionWebVitals(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<h1>Nuclei Test Page</h1>
+				<script>
+					setTimeout(() => {
+						const start = Date.now();
+						while (Date.now() - start < 300) {}
+					}, 100);
+				</script>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionWebVitals}, Data: map[string]string{"window": "2"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "1", out["web_vitals_long_tasks"], "could not observe long task")
+		longest, err := strconv.Atoi(out["web_vitals_longest_task"])
+		require.Nil(t, err, "could not parse longest task")
+		require.GreaterOrEqual(t, longest, 300, "wrong longest task duration")
+		require.NotEqual(t, "0", out["web_vitals_lcp"], "could not observe largest contentful paint")
+		require.Equal(t, "0.0000", out["web_vitals_cls"], "wrong cumulative layout shift")
+	})
+}
+
+func TestActionMetadata(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<meta charset="utf-8">
+				<meta name="Generator" content="WordPress 6.2">
+				<meta property="og:image" content="/first.png">
+				<meta property="og:image" content="/second.png">
+				<script type="application/ld+json">{"@type": "Organization", "email": "admin@example.com"}</script>
+				<script type="application/ld+json">{invalid</script>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<h1>Nuclei Test Page</h1>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionMetadata}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+
+		var metas map[string]string
+		require.Nil(t, json.Unmarshal([]byte(out["metadata_meta"]), &metas), "could not unmarshal meta tags")
+		require.Equal(t, map[string]string{"charset": "utf-8", "generator": "WordPress 6.2", "og:image": "/first.png\n/second.png"}, metas, "could not extract meta tags")
+		require.Equal(t, `[{"@type":"Organization","email":"admin@example.com"}]`, out["metadata_jsonld"], "could not extract json-ld")
+		require.Contains(t, out["metadata"], `"generator":"WordPress 6.2"`, "could not store metadata")
+	})
+}
+
+func TestActionClickjacking(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionClickjacking}, Data: map[string]string{"wait": "1"}},
+	}
+	responses := map[string]func(w http.ResponseWriter){
+		"none": func(w http.ResponseWriter) {
+			_, _ = fmt.Fprintln(w, "<html><body><button>Delete account</button></body></html>")
+		},
+		"header": func(w http.ResponseWriter) {
+			w.Header().Set("X-Frame-Options", "DENY")
+			_, _ = fmt.Fprintln(w, "<html><body><button>Delete account</button></body></html>")
+		},
+		"script": func(w http.ResponseWriter) {
+			_, _ = fmt.Fprintln(w, `<html><head><style id="anti">body{display:none !important;}</style></head><body><button>Delete account</button>
+				<script>if (self === top) { document.getElementById('anti').remove(); }</script></body></html>`)
+		},
+	}
+	expected := map[string][2]string{
+		"none":   {"true", ""},
+		"header": {"false", "x-frame-options"},
+		"script": {"false", "frame-busting"},
+	}
+
+	for protection, want := range expected {
+		actions[0].Data["url"] = "{{BaseURL}}/?protection=" + protection
+		testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+			responses[r.URL.Query().Get("protection")](w)
+		}, func(page *Page, err error, out map[string]string) {
+			require.Nil(t, err, "could not run page actions")
+			require.Equal(t, want[0], out["clickjacking"], "wrong framing result for %s protection", protection)
+			require.Equal(t, want[1], out["clickjacking_blocked_by"], "wrong protection for %s protection", protection)
+		})
+	}
+}
+
+func TestClickjackingProtection(t *testing.T) {
+	tests := []struct {
+		headers        http.Header
+		frameAncestors string
+		blockedBy      string
+	}{
+		{headers: http.Header{}},
+		{headers: http.Header{"X-Frame-Options": {"SAMEORIGIN"}}, blockedBy: "x-frame-options"},
+		{headers: http.Header{"X-Frame-Options": {"ALLOW-FROM https://example.com"}}},
+		{headers: http.Header{"Content-Security-Policy": {"default-src 'self'; frame-ancestors 'self' https://example.com"}}, frameAncestors: "'self' https://example.com", blockedBy: "frame-ancestors"},
+		{headers: http.Header{"Content-Security-Policy": {"frame-ancestors *"}, "X-Frame-Options": {"DENY"}}, frameAncestors: "*"},
+		{headers: http.Header{"Content-Security-Policy": {"frame-ancestors https:", "frame-ancestors 'none'"}}, frameAncestors: "https:, 'none'", blockedBy: "frame-ancestors"},
+		{headers: http.Header{"Content-Security-Policy-Report-Only": {"frame-ancestors 'none'"}}},
+	}
+	for _, test := range tests {
+		_, frameAncestors, blockedBy := clickjackingProtection(test.headers)
+		require.Equal(t, test.frameAncestors, frameAncestors, "wrong frame ancestors for %v", test.headers)
+		require.Equal(t, test.blockedBy, blockedBy, "wrong protection for %v", test.headers)
+	}
+}
+
+func TestActionEventHandlers(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<img id="avatar" src="/missing.png" onerror="alert(document.domain)">
+				<a href=" JaVaScript:alert(1)">link</a>
+				<a href="/safe" onclick="track()">safe</a>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionEventHandlers}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+
+		var handlers []eventHandler
+		require.Nil(t, json.Unmarshal([]byte(out["eventhandlers"]), &handlers), "could not unmarshal event handlers")
+		require.Equal(t, []eventHandler{
+			{Tag: "img", Attribute: "onerror", ID: "avatar", Code: "alert(document.domain)"},
+			{Tag: "a", Attribute: "href", Code: "alert(1)"},
+			{Tag: "a", Attribute: "onclick", Code: "track()"},
+		}, handlers, "could not extract event handlers")
+		require.Equal(t, "alert(document.domain)\nalert(1)\ntrack()", out["eventhandlers_code"], "could not store handler code")
+	})
+}
+
+func TestActionWASM(t *testing.T) {
+	// an empty module exporting nothing
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<script>
+					WebAssembly.instantiate(new Uint8Array([0, 97, 115, 109, 1, 0, 0, 0]));
+					fetch('/module.wasm').then(response => response.arrayBuffer()).then(buffer => WebAssembly.compile(buffer));
+				</script>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionWASM}, Data: map[string]string{"window": "2", "capture": "true"}},
+	}
+
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/module.wasm" {
+			w.Header().Set("Content-Type", "application/wasm")
+			_, _ = w.Write(module)
+			return
+		}
+		_, _ = fmt.Fprintln(w, response)
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "true", out["wasm_detected"], "could not detect webassembly")
+		require.Equal(t, "3", out["wasm_count"], "wrong number of modules")
+		require.True(t, strings.HasSuffix(out["wasm_urls"], "/module.wasm"), "could not get module url")
+
+		var modules []wasmModule
+		require.Nil(t, json.Unmarshal([]byte(out["wasm"]), &modules), "could not unmarshal modules")
+		var apis []string
+		for _, item := range modules {
+			apis = append(apis, item.API)
+			require.Equal(t, len(module), item.Size, "wrong module size")
+			require.Equal(t, base64.StdEncoding.EncodeToString(module), item.Data, "could not capture module")
+		}
+		require.ElementsMatch(t, []string{"instantiate", "compile", "network"}, apis, "wrong module apis")
+	})
+}
+
+func TestActionTrackers(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+				<script src="/piwik.js"></script>
+				<script src="/vendor/track.js"></script>
+			</head>
+			<body>
+				<h1>Nuclei Test Page</h1>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionTrackers}, Data: map[string]string{"trackers": "/vendor/track.js,Vendor Tracker,advertising"}},
+	}
+
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".js") {
+			w.Header().Set("Content-Type", "application/javascript")
+			return
+		}
+		_, _ = fmt.Fprintln(w, response)
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "2", out["trackers_count"], "wrong number of trackers")
+		require.ElementsMatch(t, []string{"Matomo", "Vendor Tracker"}, strings.Split(out["trackers_names"], "\n"), "wrong tracker names")
+		require.ElementsMatch(t, []string{"analytics", "advertising"}, strings.Split(out["trackers_categories"], "\n"), "wrong tracker categories")
+
+		var trackers []detectedTracker
+		require.Nil(t, json.Unmarshal([]byte(out["trackers"]), &trackers), "could not unmarshal trackers")
+		for _, item := range trackers {
+			require.Equal(t, "Script", item.Type, "wrong tracker resource type")
+		}
+	})
+}
+
+func TestActionStorage(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<h1>Nuclei Test Page</h1>
+				<script>
+					localStorage.setItem("token", "abcd");
+					localStorage.setItem("user", "nuclei");
+					const open = indexedDB.open("nuclei-db", 2);
+					open.onupgradeneeded = () => open.result.createObjectStore("secrets");
+					open.onsuccess = () => {
+						const tx = open.result.transaction("secrets", "readwrite");
+						tx.objectStore("secrets").put("value", "key");
+						tx.oncomplete = () => { document.title = "stored"; };
+					};
+				</script>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Data: map[string]string{"code": "() => new Promise(resolve => { const check = () => document.title === 'stored' ? resolve() : setTimeout(check, 50); check(); })"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionStorage}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "2", out["storage_local_storage_entries"], "wrong number of local storage entries")
+		require.Equal(t, "19", out["storage_local_storage_bytes"], "wrong local storage size")
+		require.Equal(t, "nuclei-db", out["storage_indexeddb_databases"], "wrong indexeddb databases")
+		require.Equal(t, "1", out["storage_indexeddb_count"], "wrong number of indexeddb databases")
+
+		usage := &storageUsage{}
+		require.Nil(t, json.Unmarshal([]byte(out["storage"]), usage), "could not unmarshal storage usage")
+		require.Len(t, usage.IndexedDB, 1, "wrong indexeddb databases")
+		require.Equal(t, map[string]int{"secrets": 1}, usage.IndexedDB[0].ObjectStores, "wrong indexeddb object stores")
+		require.Positive(t, usage.Quota, "missing storage quota")
+	})
+}
+
+func TestActionWebSocket(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<h1>Nuclei Test Page</h1>
+				<script>
+					window.received = [];
+					const socket = new WebSocket("ws://" + location.host + "/ws");
+					socket.onopen = () => socket.send("hello user");
+					socket.onmessage = (event) => window.received.push(event.data);
+				</script>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionWebSocket}, Data: map[string]string{"endpoint": "/ws$", "match": "user", "replace": "admin", "inject": "{malformed", "window": "2"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Name: "received", Data: map[string]string{"code": "() => window.received.sort().join(',')"}},
+	}
+
+	var mu sync.Mutex
+	var serverReceived []string
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws" {
+			_, _ = fmt.Fprintln(w, response)
+			return
+		}
+		conn, _, _, err := ws.UpgradeHTTP(r, w)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			msg, op, err := wsutil.ReadClientData(conn)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			serverReceived = append(serverReceived, string(msg))
+			mu.Unlock()
+			_ = wsutil.WriteServerMessage(conn, op, append([]byte("echo:"), msg...))
+		}
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		mu.Lock()
+		require.ElementsMatch(t, []string{"{malformed", "hello admin"}, serverReceived, "wrong frames received by the server")
+		mu.Unlock()
+		require.Equal(t, "echo:hello admin,echo:{malformed", out["received"], "wrong frames received by the page")
+		require.Equal(t, "4", out["websocket_count"], "wrong number of frames")
+		require.ElementsMatch(t, []string{"echo:{malformed", "echo:hello admin"}, strings.Split(out["websocket_received"], "\n"), "wrong received frames")
+
+		var frames []WebSocketFrame
+		require.Nil(t, json.Unmarshal([]byte(page.DumpWebSocketFrames()), &frames), "could not unmarshal websocket frames")
+		var modified, injected int
+		for _, frame := range frames {
+			if frame.Modified {
+				modified++
+				require.Equal(t, "hello user", frame.Original, "wrong original frame")
+			}
+			if frame.Injected {
+				injected++
+			}
+		}
+		require.Equal(t, 1, modified, "wrong number of modified frames")
+		require.Equal(t, 1, injected, "wrong number of injected frames")
+	})
+}
+
+func TestActionCORSProbe(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionCORSProbe}, Data: map[string]string{"url": "{{BaseURL}}/api"}},
+	}
+
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api" {
+			_, _ = fmt.Fprintln(w, "<html><body><script>document.cookie = 'session=abcd';</script></body></html>")
+			return
+		}
+		// trusts null and any origin starting with the target hostname
+		origin := r.Header.Get("Origin")
+		if origin == "null" || strings.HasPrefix(origin, "http://"+strings.Split(r.Host, ":")[0]) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		_, _ = fmt.Fprintln(w, `{"cookie":"`+r.Header.Get("Cookie")+`"}`)
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "true", out["corsprobe_vulnerable"], "cors misconfiguration not detected")
+		require.Equal(t, "null,suffix", out["corsprobe_kinds"], "wrong vulnerable kinds")
+		require.Equal(t, "null", out["corsprobe_origin"], "wrong vulnerable origin")
+		require.Equal(t, "true", out["corsprobe_allow_credentials"], "wrong allowed credentials")
+
+		var results []corsProbeResult
+		require.Nil(t, json.Unmarshal([]byte(out["corsprobe"]), &results), "could not unmarshal cors probes")
+		require.Len(t, results, 4, "wrong number of cors probes")
+		require.Equal(t, "https://nuclei-cors.example", results[0].Origin, "wrong reflected origin")
+		require.False(t, results[0].Vulnerable, "reflected origin reported vulnerable")
+
+		var probes int
+		for _, item := range page.History {
+			if strings.HasSuffix(item.URL, "/api") {
+				probes++
+				require.Contains(t, item.ResponseBody, "session=abcd", "page cookies not sent")
+			}
+		}
+		require.Equal(t, 4, probes, "cors probes not stored in history")
+	})
+}
+
+func TestCORSProbeOrigin(t *testing.T) {
+	target, _ := url.Parse("https://example.com:8443/api")
+	attacker, _ := url.Parse(defaultCORSProbeOrigin)
+	origins := make(map[string]string)
+	for _, kind := range corsProbeKinds {
+		origins[kind] = corsProbeOrigin(kind, target, attacker)
+	}
+	require.Equal(t, map[string]string{
+		"reflected": "https://nuclei-cors.example",
+		"null":      "null",
+		"subdomain": "https://nuclei-cors.example.com:8443",
+		"suffix":    "https://example.com.nuclei-cors.example",
+	}, origins, "wrong cors probe origins")
+}
+
+func TestActionPrototypePollution(t *testing.T) {
+	// merges the fragment params into an object without guarding __proto__
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<h1>Nuclei Test Page</h1>
+				<script>
+					const params = {};
+					for (const pair of location.hash.slice(1).split("&")) {
+						const [path, value] = pair.split("=").map(decodeURIComponent);
+						const keys = path.replace(/\]/g, "").split(/\[|\./);
+						let target = params;
+						for (const key of keys.slice(0, -1)) {
+							target = target[key] = target[key] || {};
+						}
+						target[keys[keys.length - 1]] = value;
+					}
+				</script>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionPrototypePollution}, Data: map[string]string{"marker": "nuclei"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Name: "clean", Data: map[string]string{"code": "() => String(({}).nucleif0 === undefined && ({}).nucleif1 === undefined)"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "true", out["prototypepollution"], "prototype pollution not detected")
+		require.Equal(t, "fragment", out["prototypepollution_source"], "wrong source")
+		require.Equal(t, "__proto__[nucleif0]", out["prototypepollution_path"], "wrong polluted path")
+		require.Equal(t, "nucleif0", out["prototypepollution_key"], "wrong polluted key")
+		require.Equal(t, "true", out["clean"], "polluted properties not cleaned up")
+
+		var polluted []pollutedProperty
+		require.Nil(t, json.Unmarshal([]byte(out["prototypepollution_polluted"]), &polluted), "could not unmarshal polluted properties")
+		require.Len(t, polluted, 4, "wrong number of polluted properties")
+		require.Equal(t, "nuclei", polluted[0].Value, "wrong polluted value")
+	})
+}
+
+func TestInjectPrototypePollution(t *testing.T) {
+	vectors := prototypePollutionVectors("nuclei", []string{"query", "fragment"})
+	require.Len(t, vectors, 8, "wrong number of vectors")
+	require.Equal(t, pollutionVector{Source: "fragment", Path: "constructor[prototype][nucleif2]", Key: "nucleif2"}, vectors[6], "wrong vector")
+
+	target, err := injectPrototypePollution("https://example.com/?a=b#old", vectors[:1], "nuclei")
+	require.Nil(t, err, "could not inject payloads")
+	require.Equal(t, "https://example.com/?a=b&__proto__[nucleiq0]=nuclei", target, "wrong query payload")
+
+	target, err = injectPrototypePollution("https://example.com/#old", vectors[4:6], "nuclei")
+	require.Nil(t, err, "could not inject payloads")
+	require.Equal(t, "https://example.com/#__proto__[nucleif0]=nuclei&__proto__.nucleif1=nuclei", target, "wrong fragment payload")
+}
+
+func TestActionPermissionsPolicy(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionPermissionsPolicy}},
+	}
+
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Permissions-Policy", `camera=(), geolocation=(self "https://maps.example.com"), microphone=*`)
+		w.Header().Set("Feature-Policy", "display-capture 'self'")
+		_, _ = fmt.Fprintln(w, "<html><body>permissions</body></html>")
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "microphone", out["permissions_policy_allow_all"], "wrong features allowing any origin")
+		require.Equal(t, "camera", out["permissions_policy_disabled"], "wrong disabled features")
+		require.Equal(t, "none", out["permissions_policy_camera"], "wrong camera allowlist")
+		require.Equal(t, "self https://maps.example.com", out["permissions_policy_geolocation"], "wrong geolocation allowlist")
+		require.Equal(t, "self", out["permissions_policy_display_capture"], "wrong feature policy allowlist")
+	})
+}
+
+func TestActionSourceMaps(t *testing.T) {
+	inline := base64.StdEncoding.EncodeToString([]byte(`{"version":3,"sources":["src/inline.ts"],"mappings":"AAAA"}`))
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionSourceMaps}, Data: map[string]string{"sources": "true"}},
+	}
+
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/main.js":
+			w.Header().Set("Content-Type", "application/javascript")
+			_, _ = fmt.Fprint(w, "var a = 1;\n//# sourceMappingURL=main.js.map\n")
+		case "/main.js.map":
+			_, _ = fmt.Fprint(w, `{"version":3,"sources":["src/app.ts"],"sourcesContent":["const a = 1;"],"mappings":"AAAA"}`)
+		case "/inline.js":
+			w.Header().Set("Content-Type", "application/javascript")
+			_, _ = fmt.Fprintf(w, "var b = 2;\n//# sourceMappingURL=data:application/json;base64,%s\n", inline)
+		case "/missing.js":
+			w.Header().Set("Content-Type", "application/javascript")
+			_, _ = fmt.Fprint(w, "var c = 3;\n//# sourceMappingURL=missing.js.map\n")
+		case "/missing.js.map":
+			http.NotFound(w, r)
+		default:
+			_, _ = fmt.Fprintln(w, `<html><body><script src="/main.js"></script><script src="/inline.js"></script><script src="/missing.js"></script></body></html>`)
+		}
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "true", out["sourcemaps_found"], "source maps not found")
+		require.Equal(t, "2", out["sourcemaps_count"], "wrong number of accessible source maps")
+		require.True(t, strings.HasSuffix(out["sourcemaps_urls"], "/main.js.map"), "wrong source map urls")
+		require.ElementsMatch(t, []string{"src/app.ts", "src/inline.ts"}, strings.Split(out["sourcemaps_sources"], "\n"), "wrong original sources")
+
+		var maps []sourceMapResult
+		require.Nil(t, json.Unmarshal([]byte(out["sourcemaps"]), &maps), "could not unmarshal source maps")
+		require.Len(t, maps, 3, "wrong number of source maps")
+		for _, result := range maps {
+			if strings.HasSuffix(result.Script, "/missing.js") {
+				require.False(t, result.Accessible, "missing source map accessible")
+				require.Equal(t, http.StatusNotFound, result.StatusCode, "wrong missing source map status code")
+			}
+		}
+	})
+}
+
+func TestActionPrefilledFields(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionPrefilledFields}},
+		{ActionType: ActionTypeHolder{ActionType: ActionPrefilledFields}, Name: "revealed", Data: map[string]string{"reveal": "true"}},
+	}
+
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, `<html><body><form action="/login">
+<input name="username" value="kiosk">
+<input type="password" name="password" value="hunter2">
+<input type="hidden" name="csrf_token" value="abc">
+<input name="otp" id="otp">
+<input type="password" name="confirm">
+</form><script>document.getElementById("otp").value = "123456";</script></body></html>`)
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "true", out["prefilled_fields_found"], "prefilled fields not found")
+		require.Equal(t, "true", out["prefilled_fields_password"], "prefilled password not found")
+		require.Equal(t, "2", out["prefilled_fields_count"], "wrong number of prefilled fields")
+		require.Equal(t, "password\notp", out["prefilled_fields_names"], "wrong prefilled field names")
+		require.NotContains(t, out["prefilled_fields"], "hunter2", "secret value output without reveal")
+		require.NotContains(t, out, "prefilled_fields_values", "values output without reveal")
+
+		var fields []prefilledField
+		require.Nil(t, json.Unmarshal([]byte(out["prefilled_fields"]), &fields), "could not unmarshal prefilled fields")
+		require.Len(t, fields, 2, "wrong number of prefilled fields")
+		require.Equal(t, "markup", fields[0].Source, "wrong password source")
+		require.Equal(t, "script", fields[1].Source, "wrong otp source")
+
+		require.Equal(t, "hunter2\n123456", out["revealed_values"], "wrong revealed values")
+	})
+}
+
+func TestActionInsecureForms(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionInsecureForms}},
+	}
+
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, `<html><body>
+<form id="search" action="/search"><input name="q"></form>
+<form id="login" method="post" action="http://collector.example/login"><input type="password" name="password"></form>
+<form id="profile" method="post"><input name="name"><button formaction="https://api.example/profile">Save</button></form>
+</body></html>`)
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "true", out["insecure_forms_found"], "insecure forms not found")
+		require.Equal(t, "2", out["insecure_forms_count"], "wrong number of insecure forms")
+		require.Equal(t, "true", out["insecure_forms_cross_origin"], "cross origin forms not found")
+		require.Equal(t, "true", out["insecure_forms_password"], "password form not found")
+		require.Equal(t, "http://collector.example/login\nhttps://api.example/profile", out["insecure_forms_actions"], "wrong insecure form actions")
+
+		var forms []insecureForm
+		require.Nil(t, json.Unmarshal([]byte(out["insecure_forms"]), &forms), "could not unmarshal insecure forms")
+		require.Len(t, forms, 2, "wrong number of insecure forms")
+		require.Equal(t, "login", forms[0].ID, "wrong form id")
+		require.Equal(t, "POST", forms[0].Method, "wrong form method")
+		require.True(t, forms[1].Submitter, "formaction not reported as submitter")
+	})
+}
+
+func TestActionDOMClobbering(t *testing.T) {
+	// reads optional configuration globals, the var declaration shadowing the anchor
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<h1>Nuclei Test Page</h1>
+				<script>
+					var settings = window.appConfig || {};
+					var debug = false;
+					document.title = String(settings.href || debug);
+				</script>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionDOMClobbering}, Data: map[string]string{"names": "appConfig,debug,unused"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Name: "clean", Data: map[string]string{"code": "() => String(!document.querySelector('[data-nuclei-clobbering]') && !('appConfig' in window) && !('unused' in window))"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "true", out["domclobbering"], "dom clobbering not detected")
+		require.Equal(t, "appConfig", out["domclobbering_names"], "wrong clobbered names")
+		require.Equal(t, "true", out["clean"], "injected anchors not cleaned up")
+
+		var clobbered []clobberedGlobal
+		require.Nil(t, json.Unmarshal([]byte(out["domclobbering_clobbered"]), &clobbered), "could not unmarshal clobbered globals")
+		require.Len(t, clobbered, 1, "wrong number of clobbered globals")
+		require.Equal(t, 1, clobbered[0].Reads, "wrong number of reads")
+		require.False(t, clobbered[0].Assigned, "clobbered global assigned")
+		require.NotEmpty(t, clobbered[0].Source, "no source of the read")
+	})
+}
+
+func TestFindCSPNonceReuse(t *testing.T) {
+	document := func(url, policy string) HistoryData {
+		headers := http.Header{}
+		if policy != "" {
+			headers.Set("Content-Security-Policy", policy)
+		}
+		return HistoryData{URL: url, ResourceType: proto.NetworkResourceTypeDocument, ResponseHeaders: headers}
+	}
+	history := []HistoryData{
+		document("https://example.com/", "script-src 'nonce-abc' 'sha256-B2yPHKaXnvFWtRChIbabYmUBFZdVfKKXHbWtWidDVF8='; style-src 'nonce-abc'"),
+		{URL: "https://example.com/app.js", ResourceType: proto.NetworkResourceTypeScript, ResponseHeaders: http.Header{"Content-Security-Policy": {"script-src 'nonce-abc'"}}},
+		document("https://example.com/", "script-src 'nonce-def'"),
+		document("https://example.com/login", "script-src 'NONCE-abc'"),
+		document("https://example.com/about", ""),
+	}
+
+	policies, reused := findCSPNonceReuse(history)
+	require.Equal(t, []cspSources{
+		{URL: "https://example.com/", Nonces: []string{"abc"}, Hashes: []string{"sha256-B2yPHKaXnvFWtRChIbabYmUBFZdVfKKXHbWtWidDVF8="}},
+		{URL: "https://example.com/", Nonces: []string{"def"}},
+		{URL: "https://example.com/login", Nonces: []string{"abc"}},
+	}, policies, "wrong csp sources")
+	require.Equal(t, []string{"abc"}, reused, "wrong reused nonces")
+
+	_, reused = findCSPNonceReuse(history[:3])
+	require.Empty(t, reused, "found reuse of unique nonces")
+}
+
+func testHeadlessSimpleResponse(t *testing.T, response string, actions []*Action, timeout time.Duration, assert func(page *Page, pageErr error, out map[string]string)) {
+	t.Helper()
+	testHeadless(t, actions, timeout, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, response)
+	}, assert)
+}
+
+func testHeadless(t *testing.T, actions []*Action, timeout time.Duration, handler func(w http.ResponseWriter, r *http.Request), assert func(page *Page, pageErr error, extractedData map[string]string)) {
+	t.Helper()
+	_ = protocolstate.Init(&types.Options{})
+
+	browser, err := New(&types.Options{ShowBrowser: false, UseInstalledChrome: testheadless.HeadlessLocal})
+	require.Nil(t, err, "could not create browser")
+	defer browser.Close()
 
 	instance, err := browser.NewInstance()
 	require.Nil(t, err, "could not create browser instance")
@@ -587,4 +2163,69 @@ func TestContainsAnyModificationActionType(t *testing.T) {
 	if !containsAnyModificationActionType(ActionSetMethod, ActionAddHeader, ActionSetHeader, ActionDeleteHeader, ActionSetBody) {
 		t.Error("Expected true, got false")
 	}
+	if !containsAnyModificationActionType(ActionMockResponse) {
+		t.Error("Expected true, got false")
+	}
+	if !containsAnyModificationActionType(ActionSetOrigin) {
+		t.Error("Expected true, got false")
+	}
+}
+
+func TestActionExportImportSession(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>Nuclei Test Page</body>
+		</html>`
+
+	sessionFile := filepath.Join(t.TempDir(), "session-{{Hostname}}.json")
+
+	exportActions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Data: map[string]string{"code": `() => { document.cookie = "session=secret"; localStorage.setItem("token", "local"); sessionStorage.setItem("tab", "session"); }`}},
+		{ActionType: ActionTypeHolder{ActionType: ActionExportSession}, Data: map[string]string{"file": sessionFile}, Name: "session"},
+	}
+
+	testHeadlessSimpleResponse(t, response, exportActions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.NotContains(t, out["session"], "{{Hostname}}", "could not replace hostname in session file")
+
+		data, err := os.ReadFile(out["session"])
+		require.Nil(t, err, "could not read session file")
+
+		bundle := &sessionBundle{}
+		require.Nil(t, json.Unmarshal(data, bundle), "could not unmarshal session file")
+		require.Len(t, bundle.Cookies, 1, "could not export cookies")
+		require.Equal(t, "secret", bundle.Cookies[0].Value)
+		require.Len(t, bundle.Storage, 1, "could not export storage")
+		for _, storage := range bundle.Storage {
+			require.Equal(t, map[string]string{"token": "local"}, storage.LocalStorage)
+			require.Equal(t, map[string]string{"tab": "session"}, storage.SessionStorage)
+		}
+	})
+
+	importActions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionImportSession}, Data: map[string]string{"file": sessionFile}},
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScript}, Data: map[string]string{"code": `() => document.cookie + "|" + localStorage.getItem("token") + "|" + sessionStorage.getItem("tab")`}, Name: "restored"},
+	}
+
+	testHeadlessSimpleResponse(t, response, importActions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "session=secret|local|session", out["restored"], "could not import session")
+	})
+}
+
+func TestBaseURLWithTemplatePrefsIPv6(t *testing.T) {
+	parsed, err := url.Parse("http://[::1]:8080")
+	require.Nil(t, err, "could not parse url")
+
+	data, parsed := baseURLWithTemplatePrefs("{{BaseURL}}:8443/admin", parsed)
+	require.Equal(t, "{{BaseURL}}/admin", data, "template port not removed")
+	require.Equal(t, "[::1]:8443", parsed.Host, "wrong ipv6 host")
+	require.Equal(t, "::1", parsed.Hostname(), "wrong ipv6 hostname")
 }
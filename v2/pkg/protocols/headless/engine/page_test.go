@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/projectdiscovery/interactsh/pkg/server"
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// stubInteractsh generates sequential interactsh urls for the markers
+type stubInteractsh struct {
+	markers []string
+}
+
+func (s *stubInteractsh) NewURLWithData(data string) (string, error) {
+	s.markers = append(s.markers, data)
+	return fmt.Sprintf("c%d.oast.example", len(s.markers)), nil
+}
+
+func (s *stubInteractsh) Interactions(interactshURL string) []*server.Interaction {
+	return nil
+}
+
+func TestPageReplaceInteractshURLs(t *testing.T) {
+	client := &stubInteractsh{}
+	browser := &Browser{options: &types.Options{}}
+	page := &Page{
+		instance:          &Instance{browser: browser, interactsh: client},
+		mutex:             &sync.RWMutex{},
+		interactshMarkers: make(map[string]string),
+	}
+
+	navigate := &Action{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}/?callback=http://{{interactsh-url}}"}}
+	require.Equal(t, "{{BaseURL}}/?callback=http://c1.oast.example", page.getActionArg(navigate, "url"), "could not replace marker")
+
+	// the script code is resolved, and its urls recorded, before the script is evaluated
+	script := &Action{ActionType: ActionTypeHolder{ActionType: ActionScript}, Data: map[string]string{"code": "() => fetch('http://{{interactsh-url}}/' + '{{interactsh-url_1}}')"}}
+	code := page.getActionArgWithDefaultValues(script, "code")
+	require.Equal(t, "() => fetch('http://c1.oast.example/' + 'c2.oast.example')", code, "could not replace markers in script")
+	require.Equal(t, []string{"c1.oast.example", "c2.oast.example"}, page.InteractshURLs, "urls not recorded before the script")
+
+	require.Equal(t, "c1.oast.example c2.oast.example", page.replaceInteractshURLs("{{interactsh-url}} {{interactsh-url_1}}"), "markers not resolved to the same urls")
+	require.Equal(t, []string{"c1.oast.example", "c2.oast.example"}, page.InteractshURLs, "urls recorded more than once")
+	require.Equal(t, []string{"{{interactsh-url}}", "{{interactsh-url_1}}"}, client.markers, "urls generated more than once for a marker")
+}
+
+func TestPageReplaceInteractshURLsDisabled(t *testing.T) {
+	instance := &Instance{}
+	instance.SetInteractsh(nil)
+	page := &Page{instance: instance, mutex: &sync.RWMutex{}, interactshMarkers: make(map[string]string)}
+
+	require.Equal(t, "http://{{interactsh-url}}", page.replaceInteractshURLs("http://{{interactsh-url}}"), "marker replaced without interactsh")
+	require.Empty(t, page.InteractshURLs, "urls recorded without interactsh")
+}
@@ -0,0 +1,214 @@
+package engine
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// permissionsPolicyNone is the value of the features disabled for every origin
+const permissionsPolicyNone = "none"
+
+// effectivePermissionsPolicy returns the allowlists of the features declared by
+// the Permissions-Policy and legacy Feature-Policy headers of a document response.
+//
+// As browsers do, a feature declared by both headers gets the allowlist of the
+// Permissions-Policy header. Allowlists are made of *, self, src and origins, a
+// disabled feature having an empty allowlist.
+func effectivePermissionsPolicy(headers http.Header) map[string][]string {
+	policy := parseFeaturePolicy(strings.Join(headers.Values("Feature-Policy"), ","))
+	for feature, allowlist := range parsePermissionsPolicy(strings.Join(headers.Values("Permissions-Policy"), ",")) {
+		policy[feature] = allowlist
+	}
+	return policy
+}
+
+// parsePermissionsPolicy parses a Permissions-Policy header, a structured field
+// dictionary of features with an inner list or a single item allowlist such as
+// camera=(), geolocation=(self "https://example.com"), microphone=*.
+//
+// Members which are not valid allowlists are ignored, and as in dictionaries
+// the last member of a feature declared more than once wins.
+func parsePermissionsPolicy(header string) map[string][]string {
+	policy := make(map[string][]string)
+	for _, member := range splitUnquoted(header, ',') {
+		feature, value, ok := strings.Cut(strings.TrimSpace(member), "=")
+		feature = strings.TrimSpace(feature)
+		if !ok || feature == "" || feature != strings.ToLower(feature) {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		var items []string
+		if strings.HasPrefix(value, "(") {
+			end := indexUnquoted(value, ')')
+			if end < 0 {
+				continue
+			}
+			items = splitUnquoted(value[1:end], ' ')
+		} else {
+			items = []string{value}
+		}
+
+		allowlist := []string{}
+		valid := true
+		for _, item := range items {
+			// parameters of the items don't change the allowlist
+			if index := indexUnquoted(item, ';'); index >= 0 {
+				item = item[:index]
+			}
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			if strings.HasPrefix(item, `"`) {
+				origin, ok := unquoteStructuredString(item)
+				if !ok {
+					valid = false
+					break
+				}
+				allowlist = appendAllowlist(allowlist, origin)
+				continue
+			}
+			switch item {
+			case "*", "self", "src":
+				allowlist = appendAllowlist(allowlist, item)
+			default:
+				// unknown tokens are ignored, as other item types are invalid
+				if !isStructuredToken(item) {
+					valid = false
+				}
+			}
+		}
+		if valid {
+			policy[feature] = allowlist
+		}
+	}
+	return policy
+}
+
+// parseFeaturePolicy parses a legacy Feature-Policy header, made of directives
+// of a feature with a space separated allowlist such as camera 'none'; geolocation
+// 'self' https://example.com, multiple policies being separated by commas.
+func parseFeaturePolicy(header string) map[string][]string {
+	policy := make(map[string][]string)
+	for _, directive := range strings.FieldsFunc(header, func(r rune) bool { return r == ';' || r == ',' }) {
+		fields := strings.Fields(directive)
+		if len(fields) == 0 {
+			continue
+		}
+		feature := strings.ToLower(fields[0])
+		if _, ok := policy[feature]; ok {
+			// the first declaration of a feature wins
+			continue
+		}
+		allowlist := []string{}
+		for _, source := range fields[1:] {
+			value := strings.ToLower(strings.Trim(source, "'"))
+			switch value {
+			case permissionsPolicyNone:
+			case "*", "self", "src":
+				allowlist = appendAllowlist(allowlist, value)
+			default:
+				allowlist = appendAllowlist(allowlist, source)
+			}
+		}
+		// features without an allowlist default to self
+		if len(fields) == 1 {
+			allowlist = append(allowlist, "self")
+		}
+		policy[feature] = allowlist
+	}
+	return policy
+}
+
+// permissionsPolicyFeatures returns the sorted features allowing any origin
+// and the sorted features disabled for every origin
+func permissionsPolicyFeatures(policy map[string][]string) ([]string, []string) {
+	allowAll, disabled := []string{}, []string{}
+	for feature, allowlist := range policy {
+		if len(allowlist) == 0 {
+			disabled = append(disabled, feature)
+		}
+		for _, item := range allowlist {
+			if item == "*" {
+				allowAll = append(allowAll, feature)
+				break
+			}
+		}
+	}
+	sort.Strings(allowAll)
+	sort.Strings(disabled)
+	return allowAll, disabled
+}
+
+// appendAllowlist appends an item to an allowlist unless already present
+func appendAllowlist(allowlist []string, item string) []string {
+	for _, value := range allowlist {
+		if value == item {
+			return allowlist
+		}
+	}
+	return append(allowlist, item)
+}
+
+// splitUnquoted splits s around the separators outside of double quoted strings
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	for {
+		index := indexUnquoted(s, sep)
+		if index < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:index])
+		s = s[index+1:]
+	}
+}
+
+// indexUnquoted returns the index of the first c outside of double quoted strings, or -1
+func indexUnquoted(s string, c byte) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == c:
+			return i
+		}
+	}
+	return -1
+}
+
+// unquoteStructuredString returns the value of a structured field string
+func unquoteStructuredString(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", false
+	}
+	var builder strings.Builder
+	for i := 1; i < len(s)-1; i++ {
+		if s[i] == '\\' {
+			if i+1 >= len(s)-1 || (s[i+1] != '"' && s[i+1] != '\\') {
+				return "", false
+			}
+			i++
+		} else if s[i] == '"' {
+			return "", false
+		}
+		builder.WriteByte(s[i])
+	}
+	return builder.String(), true
+}
+
+// isStructuredToken returns true if s is a structured field token
+func isStructuredToken(s string) bool {
+	if s == "" || !(s[0] == '*' || (s[0] >= 'a' && s[0] <= 'z') || (s[0] >= 'A' && s[0] <= 'Z')) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] <= ' ' || s[i] >= 0x7f || strings.IndexByte(`"(),;<=>?@[\]{}`, s[i]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
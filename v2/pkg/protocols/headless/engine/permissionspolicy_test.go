@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePermissionsPolicy(t *testing.T) {
+	policy := parsePermissionsPolicy(`camera=(), geolocation=(self "https://maps.example.com";report-to=a), microphone=*, fullscreen=self, payment=("https://a.com, b" src),usb`)
+	require.Equal(t, map[string][]string{
+		"camera":      {},
+		"geolocation": {"self", "https://maps.example.com"},
+		"microphone":  {"*"},
+		"fullscreen":  {"self"},
+		"payment":     {"https://a.com, b", "src"},
+	}, policy, "wrong permissions policy")
+
+	policy = parsePermissionsPolicy(`camera=*, camera=(self), Geolocation=*, microphone=(self 1), usb=("unterminated)`)
+	require.Equal(t, map[string][]string{"camera": {"self"}}, policy, "invalid members not ignored")
+}
+
+func TestParseFeaturePolicy(t *testing.T) {
+	policy := parseFeaturePolicy(`camera 'none'; Geolocation 'self' https://maps.example.com; microphone *; fullscreen, camera *`)
+	require.Equal(t, map[string][]string{
+		"camera":      {},
+		"geolocation": {"self", "https://maps.example.com"},
+		"microphone":  {"*"},
+		"fullscreen":  {"self"},
+	}, policy, "wrong feature policy")
+}
+
+func TestEffectivePermissionsPolicy(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Feature-Policy", "camera *; microphone 'self'")
+	headers.Add("Permissions-Policy", "camera=()")
+	headers.Add("Permissions-Policy", "geolocation=*")
+
+	policy := effectivePermissionsPolicy(headers)
+	require.Equal(t, map[string][]string{
+		"camera":      {},
+		"microphone":  {"self"},
+		"geolocation": {"*"},
+	}, policy, "wrong effective policy")
+
+	allowAll, disabled := permissionsPolicyFeatures(policy)
+	require.Equal(t, []string{"geolocation"}, allowAll, "wrong features allowing any origin")
+	require.Equal(t, []string{"camera"}, disabled, "wrong disabled features")
+}
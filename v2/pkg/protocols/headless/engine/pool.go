@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+)
+
+// recycleBrowser relaunches the chrome process of a browser, replaced in tests
+var recycleBrowser = (*Browser).recycle
+
+// acquirePage blocks until a new page can be opened in the browser
+// based on the maximum number of concurrent pages, and counts the
+// page towards the pages after which the browser is recycled.
+func (b *Browser) acquirePage() {
+	if b.pages != nil {
+		b.pages <- struct{}{}
+	}
+	b.instancesCond.L.Lock()
+	b.createdPages++
+	b.instancesCond.L.Unlock()
+}
+
+// releasePage releases a page previously acquired with acquirePage
+func (b *Browser) releasePage() {
+	if b.pages != nil {
+		<-b.pages
+	}
+}
+
+// acquireInstance registers a new instance for the browser.
+//
+// Once the configured number of pages have been opened, new instances wait
+// for the active ones to be closed and the browser is relaunched to reclaim
+// the memory used by the chrome process. The pages of an instance belong to
+// the chrome process, so an instance shared by many pages keeps opening them
+// until it is closed and the browser is recycled before the next instance.
+func (b *Browser) acquireInstance() error {
+	b.instancesCond.L.Lock()
+	defer b.instancesCond.L.Unlock()
+
+	if recycleAfter := b.options.HeadlessRecyclePages; recycleAfter > 0 {
+		for b.createdPages >= recycleAfter && b.activeInstances > 0 {
+			b.instancesCond.Wait()
+		}
+		if b.createdPages >= recycleAfter {
+			if err := recycleBrowser(b); err != nil {
+				return err
+			}
+			b.createdPages = 0
+			b.instancesCond.Broadcast()
+		}
+	}
+	b.activeInstances++
+	return nil
+}
+
+// releaseInstance releases an instance previously acquired with acquireInstance
+func (b *Browser) releaseInstance() {
+	b.instancesCond.L.Lock()
+	defer b.instancesCond.L.Unlock()
+
+	b.activeInstances--
+	b.instancesCond.Broadcast()
+}
+
+// recycle closes the current chrome process and launches a new one
+// with a fresh user data directory.
+func (b *Browser) recycle() error {
+	dataStore, err := os.MkdirTemp("", "nuclei-*")
+	if err != nil {
+		return errors.Wrap(err, "could not create temporary directory")
+	}
+	_ = b.engine.Close()
+	os.RemoveAll(b.tempDir)
+
+	browser, err := launchBrowser(b.options, dataStore)
+	if err != nil {
+		return errors.Wrap(err, "could not recycle browser")
+	}
+	b.engine = browser
+	b.tempDir = dataStore
+	gologger.Verbose().Msgf("Recycled headless browser after %d pages", b.createdPages)
+	return nil
+}
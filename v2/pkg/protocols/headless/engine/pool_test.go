@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// newPoolBrowser returns a browser with the page and instance pools only
+func newPoolBrowser(maxPages, recyclePages int) *Browser {
+	browser := &Browser{
+		options:       &types.Options{HeadlessMaxPages: maxPages, HeadlessRecyclePages: recyclePages},
+		instancesCond: sync.NewCond(&sync.Mutex{}),
+	}
+	if maxPages > 0 {
+		browser.pages = make(chan struct{}, maxPages)
+	}
+	return browser
+}
+
+// stubRecycle replaces the relaunch of the browser with a counter
+func stubRecycle(t *testing.T) *int {
+	recycled := new(int)
+	previous := recycleBrowser
+	recycleBrowser = func(b *Browser) error {
+		*recycled++
+		return nil
+	}
+	t.Cleanup(func() {
+		recycleBrowser = previous
+	})
+	return recycled
+}
+
+func TestBrowserAcquirePage(t *testing.T) {
+	browser := newPoolBrowser(2, 0)
+	browser.acquirePage()
+	browser.acquirePage()
+	require.Equal(t, 2, browser.createdPages, "could not count pages")
+
+	acquired := make(chan struct{})
+	go func() {
+		browser.acquirePage()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("page acquired beyond the maximum pages")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	browser.releasePage()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("could not acquire page after release")
+	}
+	require.Equal(t, 3, browser.createdPages, "could not count pages")
+}
+
+func TestBrowserAcquirePageUnlimited(t *testing.T) {
+	browser := newPoolBrowser(0, 0)
+	for i := 0; i < 10; i++ {
+		browser.acquirePage()
+	}
+	browser.releasePage()
+	require.Equal(t, 10, browser.createdPages, "could not count pages")
+}
+
+func TestBrowserRecycleAfterPages(t *testing.T) {
+	recycled := stubRecycle(t)
+	browser := newPoolBrowser(0, 3)
+
+	// a shared instance opens pages beyond the limit until it is closed
+	require.NoError(t, browser.acquireInstance(), "could not acquire instance")
+	for i := 0; i < 5; i++ {
+		browser.acquirePage()
+		browser.releasePage()
+	}
+	require.Equal(t, 0, *recycled, "browser recycled with an active instance")
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- browser.acquireInstance()
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("instance acquired before the active instances were released")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	browser.releaseInstance()
+	select {
+	case err := <-acquired:
+		require.NoError(t, err, "could not acquire instance")
+	case <-time.After(time.Second):
+		t.Fatal("could not acquire instance after release")
+	}
+	require.Equal(t, 1, *recycled, "browser not recycled after pages")
+	require.Equal(t, 0, browser.createdPages, "pages not reset after recycle")
+	require.Equal(t, 1, browser.activeInstances, "wrong active instances")
+}
+
+func TestBrowserNoRecycleBelowPages(t *testing.T) {
+	recycled := stubRecycle(t)
+	browser := newPoolBrowser(0, 3)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, browser.acquireInstance(), "could not acquire instance")
+		browser.acquirePage()
+		browser.releasePage()
+		browser.releaseInstance()
+	}
+	require.Equal(t, 1, *recycled, "wrong number of recycles")
+	require.Equal(t, 1, browser.createdPages, "wrong pages after recycle")
+}
+
+func TestBrowserRecycleDisabled(t *testing.T) {
+	recycled := stubRecycle(t)
+	browser := newPoolBrowser(0, 0)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, browser.acquireInstance(), "could not acquire instance")
+		browser.acquirePage()
+		browser.releasePage()
+	}
+	require.Equal(t, 0, *recycled, "browser recycled while disabled")
+	require.Equal(t, 10, browser.activeInstances, "wrong active instances")
+}
@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/gologger"
+)
+
+// Popup is a page opened by the page during the run, e.g. with window.open
+type Popup struct {
+	// TargetID is the id of the popup target
+	TargetID proto.TargetTargetID
+	// OpenerID is the id of the target which opened the popup
+	OpenerID proto.TargetTargetID
+	// URL is the last url of the popup
+	URL string
+	// Closed is true once the popup was closed
+	Closed bool
+	// History contains the requests of the popup once it is attached
+	History []HistoryData
+
+	page   *rod.Page
+	hijack *Hijack
+}
+
+// trackPopups tracks the pages opened by the page, or by its popups, in the popups of the page
+func (p *Page) trackPopups() {
+	browser, cancel := p.instance.engine.WithCancel()
+	p.stopTrackingPopups = cancel
+
+	// events are handled sequentially so the openers don't need locking
+	openers := map[proto.TargetTargetID]struct{}{p.page.TargetID: {}}
+	wait := browser.EachEvent(
+		func(e *proto.TargetTargetCreated) {
+			info := e.TargetInfo
+			if info.Type != proto.TargetTargetInfoTypePage {
+				return
+			}
+			if _, ok := openers[info.OpenerID]; !ok {
+				return
+			}
+			openers[info.TargetID] = struct{}{}
+
+			popup := &Popup{TargetID: info.TargetID, OpenerID: info.OpenerID, URL: info.URL}
+			p.mutex.Lock()
+			p.Popups = append(p.Popups, popup)
+			p.mutex.Unlock()
+			// attaching calls the browser which can't be done from the event handler
+			go p.attachPopup(popup)
+		},
+		func(e *proto.TargetTargetInfoChanged) {
+			p.updatePopup(e.TargetInfo.TargetID, func(popup *Popup) { popup.URL = e.TargetInfo.URL })
+		},
+		func(e *proto.TargetTargetDestroyed) {
+			p.updatePopup(e.TargetID, func(popup *Popup) { popup.Closed = true })
+		},
+	)
+	go wait()
+}
+
+// attachPopup attaches to a popup recording its requests in its history and the page history
+func (p *Page) attachPopup(popup *Popup) {
+	page, err := p.instance.engine.PageFromTarget(popup.TargetID)
+	if err != nil {
+		gologger.Debug().Msgf("Could not attach to popup %s: %s\n", popup.URL, err)
+		return
+	}
+	page = page.Context(p.mainPage.GetContext())
+
+	hijack := NewHijack(page)
+	hijack.SetPattern(&proto.FetchRequestPattern{
+		URLPattern:   "*",
+		RequestStage: proto.FetchRequestStageResponse,
+	})
+	hijackHandler := hijack.Start(p.routingRuleHandlerNative(page, popup))
+	go func() {
+		_ = hijackHandler()
+	}()
+
+	p.mutex.Lock()
+	popup.page = page
+	popup.hijack = hijack
+	p.mutex.Unlock()
+}
+
+// updatePopup updates the popup of a target if it is tracked
+func (p *Page) updatePopup(targetID proto.TargetTargetID, update func(popup *Popup)) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, popup := range p.Popups {
+		if popup.TargetID == targetID {
+			update(popup)
+			return
+		}
+	}
+}
+
+// closePopups stops tracking popups and closes the ones still open
+func (p *Page) closePopups() {
+	if p.stopTrackingPopups != nil {
+		p.stopTrackingPopups()
+	}
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for _, popup := range p.Popups {
+		if popup.hijack != nil {
+			_ = popup.hijack.Stop()
+		}
+		if popup.page != nil && !popup.Closed {
+			_ = popup.page.Close()
+		}
+	}
+}
+
+// selectPopup returns the popup to switch to. A positive index selects a popup
+// in the order they were opened, otherwise the last open popup with an url
+// matching urlRegex, if any, is selected.
+func selectPopup(popups []*Popup, index int, urlRegex *regexp.Regexp) *Popup {
+	if index > 0 {
+		if index <= len(popups) && !popups[index-1].Closed {
+			return popups[index-1]
+		}
+		return nil
+	}
+	for i := len(popups) - 1; i >= 0; i-- {
+		popup := popups[i]
+		if popup.Closed || (urlRegex != nil && !urlRegex.MatchString(popup.URL)) {
+			continue
+		}
+		return popup
+	}
+	return nil
+}
+
+// dumpPopupHistory returns the requests and responses of a popup
+func dumpPopupHistory(popup *Popup) string {
+	var historyDump strings.Builder
+	for _, historyData := range popup.History {
+		historyDump.WriteString(historyData.RawRequest)
+		historyDump.WriteString(historyData.RawResponse)
+	}
+	return historyDump.String()
+}
+
+// popupURLs returns the urls of the popups, one per line
+func popupURLs(popups []*Popup) string {
+	urls := make([]string, 0, len(popups))
+	for _, popup := range popups {
+		urls = append(urls, popup.URL)
+	}
+	return strings.Join(urls, "\n")
+}
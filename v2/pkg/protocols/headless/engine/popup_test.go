@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectPopup(t *testing.T) {
+	login := &Popup{TargetID: "1", URL: "https://auth.example.com/login"}
+	closed := &Popup{TargetID: "2", URL: "https://auth.example.com/callback?token=1", Closed: true}
+	blank := &Popup{TargetID: "3", URL: "about:blank"}
+	popups := []*Popup{login, closed, blank}
+
+	require.Equal(t, blank, selectPopup(popups, -1, nil), "last popup not selected")
+	require.Equal(t, login, selectPopup(popups, 1, nil), "popup not selected by index")
+	require.Nil(t, selectPopup(popups, 2, nil), "closed popup selected")
+	require.Nil(t, selectPopup(popups, 4, nil), "popup selected with index out of range")
+	require.Equal(t, login, selectPopup(popups, -1, regexp.MustCompile(`auth\.example\.com`)), "popup not selected by url")
+	require.Nil(t, selectPopup(popups, -1, regexp.MustCompile(`token=`)), "closed popup selected by url")
+	require.Nil(t, selectPopup(nil, -1, nil), "popup selected without popups")
+
+	require.Equal(t, "https://auth.example.com/login\nhttps://auth.example.com/callback?token=1\nabout:blank", popupURLs(popups), "wrong popup urls")
+}
+
+func TestDumpPopupHistory(t *testing.T) {
+	popup := &Popup{History: []HistoryData{
+		{RawRequest: "GET /login HTTP/1.1\n", RawResponse: "HTTP/1.1 200 OK\n\n"},
+		{RawRequest: "GET /callback HTTP/1.1\n", RawResponse: "HTTP/1.1 302 Found\n\n"},
+	}}
+	require.Equal(t, "GET /login HTTP/1.1\nHTTP/1.1 200 OK\n\nGET /callback HTTP/1.1\nHTTP/1.1 302 Found\n\n", dumpPopupHistory(popup), "wrong popup history")
+}
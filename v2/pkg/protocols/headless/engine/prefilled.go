@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"regexp"
+	"strings"
+)
+
+// sensitiveFieldNameRegex matches the names and ids of the fields holding secrets,
+// the short words only when delimited so that footprint or spinner don't match
+var sensitiveFieldNameRegex = regexp.MustCompile(`(?i)(pass|pwd|secret|token|api.?key|private.?key|cc.?num|card.?(num|no)|(^|[^a-z])(otp|totp|mfa|2fa|cvv|cvc|csc|ssn|pin)([^a-z]|$))`)
+
+// sensitiveAutocompleteTokens are the autocomplete tokens of the fields holding secrets
+var sensitiveAutocompleteTokens = []string{"current-password", "new-password", "one-time-code", "cc-number", "cc-csc"}
+
+// pageField is an input or textarea of a page as returned by prefilledFieldsJS
+type pageField struct {
+	// Name is the name attribute of the field
+	Name string `json:"name"`
+	// ID is the id attribute of the field
+	ID string `json:"id"`
+	// Type is the type of the field
+	Type string `json:"type"`
+	// Autocomplete is the autocomplete attribute of the field
+	Autocomplete string `json:"autocomplete"`
+	// Form is the action of the form of the field
+	Form string `json:"form"`
+	// Length is the length of the value of the field
+	Length int `json:"length"`
+	// Attribute is true if the value comes from the value attribute of the markup
+	Attribute bool `json:"attribute"`
+	// Autofilled is true if the field was autofilled by the browser
+	Autofilled bool `json:"autofilled"`
+	// Value is the value of the field, only returned if revealed
+	Value string `json:"value"`
+}
+
+// prefilledField is a sensitive field of a page with a value set before any user input
+type prefilledField struct {
+	// Name is the name of the field, or its id if it has no name
+	Name string `json:"name"`
+	// Type is the type of the field
+	Type string `json:"type"`
+	// Autocomplete is the autocomplete attribute of the field
+	Autocomplete string `json:"autocomplete,omitempty"`
+	// Form is the action of the form of the field
+	Form string `json:"form,omitempty"`
+	// Reason is why the field is sensitive (type, autocomplete or name)
+	Reason string `json:"reason"`
+	// Source is where the value comes from: markup for the value attribute
+	// served in the page, autofill for the browser, or script otherwise
+	Source string `json:"source"`
+	// Length is the length of the value
+	Length int `json:"length"`
+	// Value is the value of the field, only set if revealed
+	Value string `json:"value,omitempty"`
+}
+
+// sensitiveFieldReason returns why a field is sensitive: its password type, a
+// sensitive autocomplete token or a name matching names (or the default names
+// regex if nil). Hidden fields, used for csrf tokens, are never sensitive.
+func sensitiveFieldReason(field pageField, names *regexp.Regexp) string {
+	fieldType := strings.ToLower(field.Type)
+	if fieldType == "hidden" {
+		return ""
+	}
+	if fieldType == "password" {
+		return "type"
+	}
+	for _, token := range strings.Fields(strings.ToLower(field.Autocomplete)) {
+		for _, sensitive := range sensitiveAutocompleteTokens {
+			if token == sensitive {
+				return "autocomplete"
+			}
+		}
+	}
+	if names == nil {
+		names = sensitiveFieldNameRegex
+	}
+	if (field.Name != "" && names.MatchString(field.Name)) || (field.ID != "" && names.MatchString(field.ID)) {
+		return "name"
+	}
+	return ""
+}
+
+// prefilledSensitiveFields returns the sensitive fields having a value
+func prefilledSensitiveFields(fields []pageField, names *regexp.Regexp) []prefilledField {
+	prefilled := []prefilledField{}
+	for _, field := range fields {
+		if field.Length == 0 {
+			continue
+		}
+		reason := sensitiveFieldReason(field, names)
+		if reason == "" {
+			continue
+		}
+		item := prefilledField{
+			Name:         field.Name,
+			Type:         strings.ToLower(field.Type),
+			Autocomplete: field.Autocomplete,
+			Form:         field.Form,
+			Reason:       reason,
+			Length:       field.Length,
+			Value:        field.Value,
+		}
+		if item.Name == "" {
+			item.Name = field.ID
+		}
+		switch {
+		case field.Autofilled:
+			item.Source = "autofill"
+		case field.Attribute:
+			item.Source = "markup"
+		default:
+			item.Source = "script"
+		}
+		prefilled = append(prefilled, item)
+	}
+	return prefilled
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSensitiveFieldReason(t *testing.T) {
+	for field, expected := range map[pageField]string{
+		{Name: "password", Type: "PASSWORD"}:                                        "type",
+		{Name: "code", Type: "text", Autocomplete: "one-time-code"}:                 "autocomplete",
+		{Name: "pwd", Type: "text", Autocomplete: "section-login current-password"}: "autocomplete",
+		{Name: "api_key", Type: "text"}:                                             "name",
+		{ID: "user-pin", Type: "tel"}:                                               "name",
+		{Name: "card_number", Type: "text"}:                                         "name",
+		{Name: "csrf_token", Type: "hidden"}:                                        "",
+		{Name: "footprint", Type: "text"}:                                           "",
+		{Name: "spinner", Type: "text"}:                                             "",
+		{Name: "username", Type: "text", Autocomplete: "username"}:                  "",
+	} {
+		require.Equal(t, expected, sensitiveFieldReason(field, nil), "wrong reason for %+v", field)
+	}
+
+	names := regexp.MustCompile(`(?i)^member_id$`)
+	require.Equal(t, "name", sensitiveFieldReason(pageField{Name: "member_id", Type: "text"}, names), "custom names not used")
+	require.Empty(t, sensitiveFieldReason(pageField{Name: "api_key", Type: "text"}, names), "default names used with custom names")
+}
+
+func TestPrefilledSensitiveFields(t *testing.T) {
+	fields := []pageField{
+		{Name: "username", Type: "text", Length: 5, Attribute: true},
+		{Name: "password", Type: "password", Form: "https://example.com/login", Length: 6, Attribute: true},
+		{ID: "otp", Type: "text", Length: 6},
+		{Name: "secret", Type: "text", Length: 4, Autofilled: true},
+		{Name: "new_password", Type: "password"},
+	}
+	prefilled := prefilledSensitiveFields(fields, nil)
+	require.Equal(t, []prefilledField{
+		{Name: "password", Type: "password", Form: "https://example.com/login", Reason: "type", Source: "markup", Length: 6},
+		{Name: "otp", Type: "text", Reason: "name", Source: "script", Length: 6},
+		{Name: "secret", Type: "text", Reason: "name", Source: "autofill", Length: 4},
+	}, prefilled, "wrong prefilled fields")
+	require.Empty(t, prefilledSensitiveFields(fields[4:], nil), "empty field reported as prefilled")
+}
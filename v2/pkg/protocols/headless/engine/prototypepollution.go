@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// prototypePollutionSources are the parts of the url the payloads are injected in
+var prototypePollutionSources = []string{"query", "fragment"}
+
+// prototypePollutionPaths are the paths of the payloads, %s being the polluted property
+var prototypePollutionPaths = []string{"__proto__[%s]", "__proto__.%s", "constructor[prototype][%s]", "constructor.prototype.%s"}
+
+// pollutionVector is a prototype pollution payload injected in the url
+type pollutionVector struct {
+	Source string `json:"source"`
+	Path   string `json:"path"`
+	Key    string `json:"key"`
+}
+
+// pollutedProperty is a property of Object.prototype set by the page
+type pollutedProperty struct {
+	Source string `json:"source"`
+	Path   string `json:"path"`
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+}
+
+// prototypePollutionVectors returns the payloads of the sources, each with its
+// own property named after the marker so that a polluted property identifies them
+func prototypePollutionVectors(marker string, sources []string) []pollutionVector {
+	var vectors []pollutionVector
+	for _, source := range sources {
+		for i, path := range prototypePollutionPaths {
+			key := fmt.Sprintf("%s%c%d", marker, source[0], i)
+			vectors = append(vectors, pollutionVector{Source: source, Path: fmt.Sprintf(path, key), Key: key})
+		}
+	}
+	return vectors
+}
+
+// injectPrototypePollution returns the url with the payloads of the vectors set
+// to value in its query or its fragment, replacing the existing fragment
+func injectPrototypePollution(target string, vectors []pollutionVector, value string) (string, error) {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+	var query, fragment []string
+	for _, vector := range vectors {
+		// brackets are kept as is as some parsers don't decode them
+		param := vector.Path + "=" + url.QueryEscape(value)
+		if vector.Source == "fragment" {
+			fragment = append(fragment, param)
+		} else {
+			query = append(query, param)
+		}
+	}
+	if len(query) > 0 {
+		if parsed.RawQuery != "" {
+			query = append([]string{parsed.RawQuery}, query...)
+		}
+		parsed.RawQuery = strings.Join(query, "&")
+	}
+	parsed.Fragment, parsed.RawFragment = "", ""
+	target = parsed.String()
+	if len(fragment) > 0 {
+		target += "#" + strings.Join(fragment, "&")
+	}
+	return target, nil
+}
+
+// prototypePollutionHookJS defines the properties of the vectors on Object.prototype
+// at the start of every document with a setter recording the values assigned to
+// Object.prototype itself, and defining an own property on any other object so
+// that the page behaves as if the properties didn't exist.
+const prototypePollutionHookJS = `(keys) => {
+	if (window.__nucleiPrototypePollution) {
+		return;
+	}
+	const polluted = window.__nucleiPrototypePollution = [];
+	for (const key of keys) {
+		let value;
+		Object.defineProperty(Object.prototype, key, {
+			configurable: true,
+			get() {
+				return value;
+			},
+			set(newValue) {
+				if (this !== Object.prototype) {
+					Object.defineProperty(this, key, {value: newValue, writable: true, enumerable: true, configurable: true});
+					return;
+				}
+				value = newValue;
+				polluted.push({key: key, value: String(newValue)});
+			},
+		});
+	}
+}`
+
+// prototypePollutionResultJS returns the properties polluted in the document,
+// including the ones redefined as data properties which bypassed the setter.
+const prototypePollutionResultJS = `(keys) => {
+	const polluted = (window.__nucleiPrototypePollution || []).slice();
+	for (const key of keys) {
+		const descriptor = Object.getOwnPropertyDescriptor(Object.prototype, key);
+		if (descriptor && 'value' in descriptor && !polluted.some(item => item.key === key)) {
+			polluted.push({key: key, value: String(descriptor.value)});
+		}
+	}
+	return polluted;
+}`
+
+// prototypePollutionCleanupJS deletes the properties of the vectors from Object.prototype
+const prototypePollutionCleanupJS = `(keys) => {
+	for (const key of keys) {
+		delete Object.prototype[key];
+	}
+}`
@@ -0,0 +1,251 @@
+package engine
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+	fileutil "github.com/projectdiscovery/utils/file"
+)
+
+// RecordingVersion is the version of the headless recording format.
+//
+// A recording is a json document written for each executed page with the fields:
+//
+//	version     - version of the recording format
+//	url         - url the actions were executed for
+//	timestamp   - time the recording was started
+//	output      - values returned by the actions
+//	error       - error returned while executing the actions, if any
+//	body        - html of the page once the actions were executed
+//	history     - request/response pairs of the page
+//	console     - messages logged to the console of the page
+//	screenshots - screenshots taken by the actions keyed by their file path
+//	events      - network, page and runtime cdp events in the order they were received
+//
+// The version is increased on incompatible changes, and recordings
+// with a different version are refused when replaying.
+const RecordingVersion = 1
+
+// Recording is a recorded headless page session
+type Recording struct {
+	Version     int               `json:"version"`
+	URL         string            `json:"url"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Output      map[string]string `json:"output,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Body        string            `json:"body,omitempty"`
+	FinalURL    string            `json:"final_url,omitempty"`
+	History     []HistoryData     `json:"history,omitempty"`
+	Console     []ConsoleMessage  `json:"console,omitempty"`
+	Screenshots map[string][]byte `json:"screenshots,omitempty"`
+	Events      []RecordedEvent   `json:"events,omitempty"`
+}
+
+// ConsoleMessage is a message logged to the console of a page
+type ConsoleMessage struct {
+	// Type is the type of the console call (log, error, warning, etc)
+	Type string `json:"type"`
+	// Text is the text of the logged arguments separated by spaces
+	Text string `json:"text"`
+}
+
+// RecordedEvent is a cdp event received for a page
+type RecordedEvent struct {
+	// Method is the cdp method of the event
+	Method string `json:"method"`
+	// Offset is the number of milliseconds since the start of the recording
+	Offset int64 `json:"offset"`
+	// Params contains the parameters of the event
+	Params json.RawMessage `json:"params"`
+}
+
+// recorder records the events of a page
+type recorder struct {
+	mutex       sync.Mutex
+	start       time.Time
+	events      []RecordedEvent
+	screenshots map[string][]byte
+}
+
+func newRecorder() *recorder {
+	return &recorder{start: time.Now(), screenshots: make(map[string][]byte)}
+}
+
+// addEvent records a cdp event
+func (r *recorder) addEvent(event proto.Event) {
+	params, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.events = append(r.events, RecordedEvent{
+		Method: event.ProtoEvent(),
+		Offset: time.Since(r.start).Milliseconds(),
+		Params: params,
+	})
+}
+
+// addScreenshot records a screenshot written to path
+func (r *recorder) addScreenshot(path string, data []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.screenshots[path] = data
+}
+
+// startRecording records the network, page and runtime events of the page
+func (p *Page) startRecording() {
+	p.recorder = newRecorder()
+
+	wait := p.page.EachEvent(
+		func(e *proto.NetworkRequestWillBeSent) { p.recorder.addEvent(e) },
+		func(e *proto.NetworkResponseReceived) { p.recorder.addEvent(e) },
+		func(e *proto.NetworkLoadingFinished) { p.recorder.addEvent(e) },
+		func(e *proto.NetworkLoadingFailed) { p.recorder.addEvent(e) },
+		func(e *proto.PageFrameNavigated) { p.recorder.addEvent(e) },
+		func(e *proto.PageLoadEventFired) { p.recorder.addEvent(e) },
+		func(e *proto.RuntimeExceptionThrown) { p.recorder.addEvent(e) },
+		func(e *proto.RuntimeConsoleAPICalled) {
+			p.recorder.addEvent(e)
+			p.addConsoleMessage(consoleMessageFromEvent(e))
+		},
+	)
+	go wait()
+}
+
+// saveRecording writes the recording of the page to the recordings directory
+func (p *Page) saveRecording(baseURL *url.URL, actions []*Action, output map[string]string, runErr error) {
+	p.recorder.mutex.Lock()
+	recording := &Recording{
+		Version:     RecordingVersion,
+		URL:         baseURL.String(),
+		Timestamp:   p.recorder.start,
+		Output:      output,
+		Screenshots: p.recorder.screenshots,
+		Events:      p.recorder.events,
+	}
+	p.recorder.mutex.Unlock()
+
+	if runErr != nil {
+		recording.Error = runErr.Error()
+	} else {
+		recording.Body = p.HTML()
+		recording.FinalURL = p.URL()
+	}
+	p.mutex.RLock()
+	recording.History = p.History
+	recording.Console = p.Console
+	p.mutex.RUnlock()
+
+	directory := p.instance.browser.options.HeadlessRecord
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		gologger.Warning().Msgf("Could not create headless recordings directory: %s\n", err)
+		return
+	}
+	data, err := json.Marshal(recording)
+	if err != nil {
+		gologger.Warning().Msgf("Could not marshal headless recording: %s\n", err)
+		return
+	}
+	path := recordingPath(directory, baseURL, actions, p.payloads)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		gologger.Warning().Msgf("Could not write headless recording: %s\n", err)
+		return
+	}
+	gologger.Verbose().Msgf("Headless session for %s recorded at %s", baseURL, path)
+}
+
+// replay creates a page from a recording without launching a browser
+func (i *Instance) replay(baseURL *url.URL, actions []*Action, payloads map[string]interface{}) (map[string]string, *Page, error) {
+	path := recordingPath(i.browser.options.HeadlessReplay, baseURL, actions, payloads)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "could not read headless recording for %s", baseURL)
+	}
+	recording := &Recording{}
+	if err := json.Unmarshal(data, recording); err != nil {
+		return nil, nil, errors.Wrap(err, "could not unmarshal headless recording")
+	}
+	if recording.Version != RecordingVersion {
+		return nil, nil, fmt.Errorf("unsupported headless recording version %d (expected %d)", recording.Version, RecordingVersion)
+	}
+	if recording.Error != "" {
+		return nil, nil, errors.New(recording.Error)
+	}
+
+	for screenshotPath, screenshot := range recording.Screenshots {
+		if fileutil.FileExists(screenshotPath) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(screenshotPath), 0700); err != nil {
+			return nil, nil, errors.Wrap(err, "could not create screenshot directory")
+		}
+		if err := os.WriteFile(screenshotPath, screenshot, 0540); err != nil {
+			return nil, nil, errors.Wrap(err, "could not write screenshot")
+		}
+	}
+
+	page := &Page{
+		instance:          i,
+		mutex:             &sync.RWMutex{},
+		payloads:          payloads,
+		interactshMarkers: make(map[string]string),
+		History:           recording.History,
+		Console:           recording.Console,
+		replayedBody:      recording.Body,
+		replayedURL:       recording.FinalURL,
+	}
+	output := recording.Output
+	if output == nil {
+		output = make(map[string]string)
+	}
+	return output, page, nil
+}
+
+// recordingPath returns the path of the recording for the actions executed on a url
+func recordingPath(directory string, baseURL *url.URL, actions []*Action, payloads map[string]interface{}) string {
+	hash := sha1.New()
+	hash.Write([]byte(baseURL.String()))
+	for _, action := range actions {
+		hash.Write([]byte(action.String()))
+	}
+	keys := make([]string, 0, len(payloads))
+	for key := range payloads {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		hash.Write([]byte(fmt.Sprintf("%s=%v", key, payloads[key])))
+	}
+	return filepath.Join(directory, hex.EncodeToString(hash.Sum(nil))+".json")
+}
+
+// consoleMessageFromEvent converts a console api call to a console message
+func consoleMessageFromEvent(e *proto.RuntimeConsoleAPICalled) ConsoleMessage {
+	args := make([]string, 0, len(e.Args))
+	for _, arg := range e.Args {
+		switch {
+		case arg.Value.Nil():
+			args = append(args, arg.Description)
+		case arg.Value.Str() != "":
+			args = append(args, arg.Value.Str())
+		default:
+			args = append(args, arg.Value.JSON("", ""))
+		}
+	}
+	return ConsoleMessage{Type: string(e.Type), Text: strings.Join(args, " ")}
+}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+func TestReplayRecording(t *testing.T) {
+	directory := t.TempDir()
+	screenshotPath := filepath.Join(t.TempDir(), "screenshots", "page.png")
+
+	baseURL, err := url.Parse("https://example.com")
+	require.Nil(t, err, "could not parse url")
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionScreenshot}, Data: map[string]string{"to": screenshotPath}},
+	}
+	payloads := map[string]interface{}{"username": "admin"}
+
+	writeRecording := func(recording *Recording) {
+		data, err := json.Marshal(recording)
+		require.Nil(t, err, "could not marshal recording")
+		require.Nil(t, os.WriteFile(recordingPath(directory, baseURL, actions, payloads), data, 0600), "could not write recording")
+	}
+	writeRecording(&Recording{
+		Version:     RecordingVersion,
+		URL:         baseURL.String(),
+		Timestamp:   time.Now(),
+		Output:      map[string]string{"title": "Example Domain"},
+		Body:        "<html><body>Example Domain</body></html>",
+		History:     []HistoryData{{RawRequest: "GET / HTTP/1.1\r\n\r\n", RawResponse: "HTTP/1.1 200 OK\r\n\r\n", Method: "GET", URL: baseURL.String()}},
+		Console:     []ConsoleMessage{{Type: "log", Text: "loaded"}},
+		Screenshots: map[string][]byte{screenshotPath: []byte("png")},
+	})
+
+	browser, err := New(&types.Options{HeadlessReplay: directory})
+	require.Nil(t, err, "could not create replay browser")
+	defer browser.Close()
+
+	instance, err := browser.NewInstance()
+	require.Nil(t, err, "could not create replay instance")
+	defer instance.Close()
+
+	out, page, err := instance.Run(baseURL, actions, payloads, time.Second)
+	require.Nil(t, err, "could not replay recording")
+	defer page.Close()
+
+	require.Equal(t, map[string]string{"title": "Example Domain"}, out)
+	require.Equal(t, "<html><body>Example Domain</body></html>", page.HTML())
+	require.Equal(t, "GET / HTTP/1.1\r\n\r\nHTTP/1.1 200 OK\r\n\r\n", page.DumpHistory())
+	require.Equal(t, []ConsoleMessage{{Type: "log", Text: "loaded"}}, page.Console)
+
+	screenshot, err := os.ReadFile(screenshotPath)
+	require.Nil(t, err, "could not restore screenshot")
+	require.Equal(t, []byte("png"), screenshot)
+
+	_, _, err = instance.Run(baseURL, actions, map[string]interface{}{"username": "root"}, time.Second)
+	require.NotNil(t, err, "replayed recording for different payloads")
+
+	writeRecording(&Recording{Version: RecordingVersion + 1})
+	_, _, err = instance.Run(baseURL, actions, payloads, time.Second)
+	require.NotNil(t, err, "replayed recording with unsupported version")
+
+	writeRecording(&Recording{Version: RecordingVersion, Error: "could not navigate"})
+	_, _, err = instance.Run(baseURL, actions, payloads, time.Second)
+	require.EqualError(t, err, "could not navigate")
+}
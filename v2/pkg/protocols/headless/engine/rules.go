@@ -2,8 +2,11 @@ package engine
 
 import (
 	"fmt"
+	"net/http"
 	"net/http/httputil"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
@@ -35,7 +38,18 @@ func (p *Page) routingRuleHandler(ctx *rod.Hijack) {
 			ctx.Request.SetBody(body)
 		}
 	}
-	_ = ctx.LoadResponse(p.instance.browser.httpclient, true)
+	corsCheck := p.takeCORSCheck(ctx.Request.URL().String())
+	if corsCheck != nil {
+		ctx.Request.Req().Header.Set("Origin", corsCheck.origin)
+	}
+	if ctx.Request.Type() == proto.NetworkResourceTypeDocument {
+		if referrer := p.takeReferrer(ctx.Request.URL().String()); referrer != "" {
+			ctx.Request.Req().Header.Set("Referer", referrer)
+		}
+	}
+	if !p.mockResponse(ctx) {
+		_ = ctx.LoadResponse(p.instance.browser.httpclient, true)
+	}
 
 	for _, rule := range p.rules {
 		if rule.Part != "response" {
@@ -65,37 +79,97 @@ func (p *Page) routingRuleHandler(ctx *rod.Hijack) {
 
 	// attempts to rebuild the response
 	var rawResp strings.Builder
+	var statusCode int
+	responseHeaders := make(http.Header)
+	responseBody, truncated := p.truncateResponseBody(p.transcodeResponseBody(ctx.Response.Body(), ctx.Response.Headers().Get("Content-Type")))
 	respPayloads := ctx.Response.Payload()
 	if respPayloads != nil {
+		statusCode = respPayloads.ResponseCode
 		rawResp.WriteString(fmt.Sprintf("HTTP/1.1 %d %s\n", respPayloads.ResponseCode, respPayloads.ResponsePhrase))
 		for _, header := range respPayloads.ResponseHeaders {
 			rawResp.WriteString(header.Name + ": " + header.Value + "\n")
+			responseHeaders.Add(header.Name, header.Value)
 		}
 		rawResp.WriteString("\n")
-		rawResp.WriteString(ctx.Response.Body())
+		rawResp.WriteString(responseBody)
+	}
+	if corsCheck != nil {
+		p.setCORSResponse(corsCheck, responseHeaders)
 	}
 
 	// dump request
 	historyData := HistoryData{
-		RawRequest:  rawReq,
-		RawResponse: rawResp.String(),
+		RawRequest:      rawReq,
+		RawResponse:     rawResp.String(),
+		Method:          req.Method,
+		URL:             req.URL.String(),
+		ResourceType:    ctx.Request.Type(),
+		RequestBody:     p.truncateRequestBody(ctx.Request.Body()),
+		Referrer:        req.Header.Get("Referer"),
+		StatusCode:      statusCode,
+		ResponseBody:    responseBody,
+		ResponseHeaders: responseHeaders,
+		Truncated:       truncated,
 	}
 	p.addToHistory(historyData)
+	p.addSetCookies(historyData.URL, responseHeaders, time.Now())
+}
+
+// mockResponse fulfills the request with the response of the first mockresponse
+// rule matching it, returning false if no rule matched the request.
+func (p *Page) mockResponse(ctx *rod.Hijack) bool {
+	req := ctx.Request.Req()
+	for _, rule := range p.rules {
+		if rule.Action != ActionMockResponse || !rule.URL.MatchString(req.URL.String()) {
+			continue
+		}
+		if method := rule.Args["method"]; method != "" && !strings.EqualFold(method, req.Method) {
+			continue
+		}
+
+		statusCode := http.StatusOK
+		if status, err := strconv.Atoi(rule.Args["status"]); err == nil {
+			statusCode = status
+		}
+		payload := ctx.Response.Payload()
+		payload.ResponseCode = statusCode
+		payload.ResponsePhrase = http.StatusText(statusCode)
+		headers, _ := parseMockHeaders(rule.Args["headers"])
+		ctx.Response.SetHeader(headers...)
+		ctx.Response.SetHeader("Content-Length", strconv.Itoa(len(rule.Args["body"])))
+		ctx.Response.SetBody(rule.Args["body"])
+		return true
+	}
+	return false
+}
+
+// routingRuleHandlerNative returns the native proxy rule handler of a browser page,
+// either the page of the run or a popup whose requests are also added to its history.
+func (p *Page) routingRuleHandlerNative(page *rod.Page, popup *Popup) HijackHandler {
+	return func(e *proto.FetchRequestPaused) error {
+		return p.handleNativeRequest(page, popup, e)
+	}
 }
 
-// routingRuleHandlerNative handles native proxy rule
-func (p *Page) routingRuleHandlerNative(e *proto.FetchRequestPaused) error {
-	body, _ := FetchGetResponseBody(p.page, e)
-	headers := make(map[string][]string)
+// handleNativeRequest records a request paused by the native proxy in the history
+func (p *Page) handleNativeRequest(page *rod.Page, popup *Popup, e *proto.FetchRequestPaused) error {
+	headers := make(http.Header)
 	for _, h := range e.ResponseHeaders {
-		headers[h.Name] = []string{h.Value}
+		headers.Add(h.Name, h.Value)
 	}
+	fetchedBody, _ := FetchGetResponseBody(page, e)
+	body, truncated := p.truncateResponseBody(p.transcodeResponseBody(string(fetchedBody), headers.Get("Content-Type")))
 
 	var statusCode int
 	if e.ResponseStatusCode != nil {
 		statusCode = *e.ResponseStatusCode
 	}
 
+	var requestBody string
+	if e.Request.HasPostData {
+		requestBody = p.truncateRequestBody(e.Request.PostData)
+	}
+
 	// attempts to rebuild request
 	var rawReq strings.Builder
 	rawReq.WriteString(fmt.Sprintf("%s %s %s\n", e.Request.Method, e.Request.URL, "HTTP/1.1"))
@@ -103,7 +177,7 @@ func (p *Page) routingRuleHandlerNative(e *proto.FetchRequestPaused) error {
 		rawReq.WriteString(fmt.Sprintf("%s\n", header.String()))
 	}
 	if e.Request.HasPostData {
-		rawReq.WriteString(fmt.Sprintf("\n%s\n", e.Request.PostData))
+		rawReq.WriteString(fmt.Sprintf("\n%s\n", requestBody))
 	}
 
 	// attempts to rebuild the response
@@ -113,14 +187,29 @@ func (p *Page) routingRuleHandlerNative(e *proto.FetchRequestPaused) error {
 		rawResp.WriteString(header.Name + ": " + header.Value + "\n")
 	}
 	rawResp.WriteString("\n")
-	rawResp.Write(body)
+	rawResp.WriteString(body)
 
 	// dump request
 	historyData := HistoryData{
-		RawRequest:  rawReq.String(),
-		RawResponse: rawResp.String(),
+		RawRequest:      rawReq.String(),
+		RawResponse:     rawResp.String(),
+		Method:          e.Request.Method,
+		URL:             e.Request.URL,
+		ResourceType:    e.ResourceType,
+		RequestBody:     requestBody,
+		Referrer:        e.Request.Headers["Referer"].Str(),
+		StatusCode:      statusCode,
+		ResponseBody:    body,
+		ResponseHeaders: headers,
+		Truncated:       truncated,
 	}
 	p.addToHistory(historyData)
+	if popup != nil {
+		p.mutex.Lock()
+		popup.History = append(popup.History, historyData)
+		p.mutex.Unlock()
+	}
+	p.addSetCookies(historyData.URL, headers, time.Now())
 
-	return FetchContinueRequest(p.page, e)
+	return FetchContinueRequest(page, e)
 }
@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"image"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// diffImages returns the percentage of pixels that differ between two images.
+//
+// A pixel is considered different when any of its color channels differs
+// by more than tolerance (0-255). Pixels inside ignore regions are skipped,
+// while pixels present in only one of the images are always counted as different.
+func diffImages(baseline, current image.Image, tolerance int, ignore []image.Rectangle) float64 {
+	bounds := baseline.Bounds().Union(current.Bounds())
+
+	var total, different int
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			point := image.Pt(x, y)
+			if isPointIgnored(point, ignore) {
+				continue
+			}
+			total++
+
+			if !point.In(baseline.Bounds()) || !point.In(current.Bounds()) {
+				different++
+				continue
+			}
+			if !isPixelEqual(baseline, current, point, tolerance) {
+				different++
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(different) / float64(total) * 100
+}
+
+func isPixelEqual(baseline, current image.Image, point image.Point, tolerance int) bool {
+	r1, g1, b1, a1 := baseline.At(point.X, point.Y).RGBA()
+	r2, g2, b2, a2 := current.At(point.X, point.Y).RGBA()
+
+	// RGBA returns 16 bit values, scale tolerance accordingly
+	maxDelta := uint32(tolerance) * 0x101
+	return channelDelta(r1, r2) <= maxDelta && channelDelta(g1, g2) <= maxDelta &&
+		channelDelta(b1, b2) <= maxDelta && channelDelta(a1, a2) <= maxDelta
+}
+
+func channelDelta(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func isPointIgnored(point image.Point, ignore []image.Rectangle) bool {
+	for _, region := range ignore {
+		if point.In(region) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIgnoreRegions parses regions in the format x,y,width,height
+// separated by semicolons (e.g. 0,0,1920,80;0,1000,1920,80).
+func parseIgnoreRegions(value string) ([]image.Rectangle, error) {
+	var regions []image.Rectangle
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ",")
+		if len(parts) != 4 {
+			return nil, errors.Errorf("invalid ignore region %q", item)
+		}
+		values := make([]int, len(parts))
+		for i, part := range parts {
+			parsed, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid ignore region %q", item)
+			}
+			values[i] = parsed
+		}
+		regions = append(regions, image.Rect(values[0], values[1], values[0]+values[2], values[1]+values[3]))
+	}
+	return regions, nil
+}
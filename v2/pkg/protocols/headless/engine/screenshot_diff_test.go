@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestImage(width, height int, fill color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, fill)
+		}
+	}
+	return img
+}
+
+func TestDiffImages(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	t.Run("identical", func(t *testing.T) {
+		require.Equal(t, float64(0), diffImages(newTestImage(10, 10, white), newTestImage(10, 10, white), 0, nil))
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		current := newTestImage(10, 10, white)
+		for x := 0; x < 10; x++ {
+			current.Set(x, 0, color.RGBA{A: 255})
+		}
+		require.Equal(t, float64(10), diffImages(newTestImage(10, 10, white), current, 0, nil))
+	})
+
+	t.Run("tolerance", func(t *testing.T) {
+		current := newTestImage(10, 10, color.RGBA{R: 250, G: 250, B: 250, A: 255})
+		require.Equal(t, float64(100), diffImages(newTestImage(10, 10, white), current, 0, nil))
+		require.Equal(t, float64(0), diffImages(newTestImage(10, 10, white), current, 5, nil))
+	})
+
+	t.Run("ignore", func(t *testing.T) {
+		current := newTestImage(10, 10, white)
+		current.Set(1, 1, color.RGBA{A: 255})
+		ignore := []image.Rectangle{image.Rect(0, 0, 5, 5)}
+		require.Equal(t, float64(0), diffImages(newTestImage(10, 10, white), current, 0, ignore))
+	})
+
+	t.Run("size-mismatch", func(t *testing.T) {
+		require.Equal(t, float64(50), diffImages(newTestImage(10, 10, white), newTestImage(10, 5, white), 0, nil))
+	})
+}
+
+func TestParseIgnoreRegions(t *testing.T) {
+	regions, err := parseIgnoreRegions("0,0,100,50; 10,20,30,40")
+	require.Nil(t, err, "could not parse ignore regions")
+	require.Equal(t, []image.Rectangle{image.Rect(0, 0, 100, 50), image.Rect(10, 20, 40, 60)}, regions)
+
+	regions, err = parseIgnoreRegions("")
+	require.Nil(t, err, "could not parse empty ignore regions")
+	require.Empty(t, regions)
+
+	_, err = parseIgnoreRegions("0,0,100")
+	require.NotNil(t, err, "invalid ignore region was parsed")
+}
@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// sessionImportedKey is the session storage key used to mark
+// an origin as already restored from a session bundle.
+const sessionImportedKey = "__nuclei_session_imported"
+
+// sessionBundle is a browser session exported by the exportsession action
+type sessionBundle struct {
+	// Cookies contains all the cookies of the browser context
+	Cookies []*proto.NetworkCookie `json:"cookies"`
+	// Storage contains the local and session storage keyed by security origin
+	Storage map[string]*originStorage `json:"storage,omitempty"`
+}
+
+// originStorage contains the web storage items of a single origin
+type originStorage struct {
+	LocalStorage   map[string]string `json:"local_storage,omitempty"`
+	SessionStorage map[string]string `json:"session_storage,omitempty"`
+}
+
+// frameOrigins returns the unique security origins of a frame tree
+func frameOrigins(tree *proto.PageFrameTree) []string {
+	var origins []string
+	seen := make(map[string]struct{})
+
+	var walk func(tree *proto.PageFrameTree)
+	walk = func(tree *proto.PageFrameTree) {
+		if tree == nil || tree.Frame == nil {
+			return
+		}
+		origin := tree.Frame.SecurityOrigin
+		if parsed, err := url.Parse(origin); err == nil && parsed.Host != "" {
+			if _, ok := seen[origin]; !ok {
+				seen[origin] = struct{}{}
+				origins = append(origins, origin)
+			}
+		}
+		for _, child := range tree.ChildFrames {
+			walk(child)
+		}
+	}
+	walk(tree)
+	return origins
+}
+
+// storageItemsToMap converts dom storage items to a map
+func storageItemsToMap(items []proto.DOMStorageItem) map[string]string {
+	values := make(map[string]string, len(items))
+	for _, item := range items {
+		if len(item) != 2 || item[0] == sessionImportedKey {
+			continue
+		}
+		values[item[0]] = item[1]
+	}
+	return values
+}
+
+// importStorageScript returns a script restoring the storage of a bundle
+// for the origin of each new document. Storage is restored only once per
+// origin so that changes made by the application after the import are kept.
+func importStorageScript(storage map[string]*originStorage) (string, error) {
+	data, err := json.Marshal(storage)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`(() => {
+  const storage = %s;
+  const entry = storage[window.location.origin];
+  if (!entry) {
+    return;
+  }
+  try {
+    if (window.sessionStorage.getItem(%q)) {
+      return;
+    }
+    for (const [key, value] of Object.entries(entry.local_storage || {})) {
+      window.localStorage.setItem(key, value);
+    }
+    for (const [key, value] of Object.entries(entry.session_storage || {})) {
+      window.sessionStorage.setItem(key, value);
+    }
+    window.sessionStorage.setItem(%q, "1");
+  } catch (e) {}
+})()`, data, sessionImportedKey, sessionImportedKey), nil
+}
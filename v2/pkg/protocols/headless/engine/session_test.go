@@ -0,0 +1,25 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameOrigins(t *testing.T) {
+	tree := &proto.PageFrameTree{
+		Frame: &proto.PageFrame{SecurityOrigin: "https://example.com"},
+		ChildFrames: []*proto.PageFrameTree{
+			{Frame: &proto.PageFrame{SecurityOrigin: "https://cdn.example.com"}},
+			{Frame: &proto.PageFrame{SecurityOrigin: "https://example.com"}},
+			{Frame: &proto.PageFrame{SecurityOrigin: "null"}},
+		},
+	}
+	require.Equal(t, []string{"https://example.com", "https://cdn.example.com"}, frameOrigins(tree))
+}
+
+func TestStorageItemsToMap(t *testing.T) {
+	items := []proto.DOMStorageItem{{"token", "value"}, {sessionImportedKey, "1"}, {"invalid"}}
+	require.Equal(t, map[string]string{"token": "value"}, storageItemsToMap(items))
+}
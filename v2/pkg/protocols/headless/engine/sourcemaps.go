@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// sourceMappingURLRegex matches the sourceMappingURL comments of a script,
+// including the legacy //@ form and the /*# */ form used by stylesheets
+var sourceMappingURLRegex = regexp.MustCompile(`(?m)(?://|/\*)[#@][ \t]*sourceMappingURL=([^\s'"*]+)`)
+
+// sourceMapResult is a source map referenced by a script stored as json in the output
+type sourceMapResult struct {
+	// Script is the url of the script referencing the source map
+	Script string `json:"script"`
+	// URL is the resolved url of an external source map
+	URL string `json:"url,omitempty"`
+	// Inline is true for source maps embedded in the script as data uris
+	Inline bool `json:"inline"`
+	// StatusCode is the status code of the response of an external source map
+	StatusCode int `json:"status_code,omitempty"`
+	// Accessible is true if the source map could be retrieved and parsed
+	Accessible bool `json:"accessible"`
+	// Sources are the original source file names, if asked
+	Sources []string `json:"sources,omitempty"`
+	// SourcesContent is true if the source map embeds the original sources
+	SourcesContent bool `json:"sources_content"`
+	// Error is the reason the source map is not accessible
+	Error string `json:"error,omitempty"`
+}
+
+// sourceMap is the subset of a source map (revision 3) used to list the original sources
+type sourceMap struct {
+	Version        int                `json:"version"`
+	Mappings       *string            `json:"mappings"`
+	Sources        []string           `json:"sources"`
+	SourceRoot     string             `json:"sourceRoot"`
+	SourcesContent []*string          `json:"sourcesContent"`
+	Sections       []sourceMapSection `json:"sections"`
+}
+
+// sourceMapSection is a section of an index source map
+type sourceMapSection struct {
+	Map *sourceMap `json:"map"`
+}
+
+// sourceMapReference returns the source map url referenced by a script, from
+// the SourceMap (or legacy X-SourceMap) response header which takes precedence,
+// or else the last sourceMappingURL comment of the script.
+func sourceMapReference(historyData HistoryData) string {
+	if historyData.ResponseHeaders != nil {
+		for _, header := range []string{"SourceMap", "X-SourceMap"} {
+			if value := strings.TrimSpace(historyData.ResponseHeaders.Get(header)); value != "" {
+				return value
+			}
+		}
+	}
+	matches := sourceMappingURLRegex.FindAllStringSubmatch(historyData.ResponseBody, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[len(matches)-1][1]
+}
+
+// decodeDataURI decodes the data of a base64 or percent encoded data uri
+func decodeDataURI(uri string) ([]byte, error) {
+	header, data, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok {
+		return nil, errors.New("invalid data uri")
+	}
+	if strings.HasSuffix(strings.ToLower(header), ";base64") {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			// some bundlers omit the padding
+			decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "="))
+		}
+		return decoded, err
+	}
+	decoded, err := url.PathUnescape(data)
+	return []byte(decoded), err
+}
+
+// parseSourceMap parses a source map returning its original sources with their
+// source root, and whether it embeds their content.
+//
+// Responses which are not source maps, such as the html fallback pages served
+// by single page applications for unknown paths, return an error.
+func parseSourceMap(data []byte) ([]string, bool, error) {
+	parsed := &sourceMap{}
+	if err := json.Unmarshal(data, parsed); err != nil {
+		return nil, false, errors.Wrap(err, "not a source map")
+	}
+	if parsed.Version == 0 || (parsed.Mappings == nil && len(parsed.Sections) == 0) {
+		return nil, false, errors.New("not a source map")
+	}
+	var sources []string
+	var content bool
+	var collect func(parsed *sourceMap)
+	collect = func(parsed *sourceMap) {
+		for _, source := range parsed.Sources {
+			if parsed.SourceRoot != "" && !strings.Contains(source, "://") {
+				source = strings.TrimSuffix(parsed.SourceRoot, "/") + "/" + strings.TrimPrefix(source, "/")
+			}
+			sources = append(sources, source)
+		}
+		for _, sourceContent := range parsed.SourcesContent {
+			if sourceContent != nil && *sourceContent != "" {
+				content = true
+			}
+		}
+		for _, section := range parsed.Sections {
+			if section.Map != nil {
+				collect(section.Map)
+			}
+		}
+	}
+	collect(parsed)
+	return sources, content, nil
+}
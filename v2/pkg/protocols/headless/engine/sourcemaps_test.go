@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceMapReference(t *testing.T) {
+	script := HistoryData{ResponseBody: "var a=1;\n//# sourceMappingURL=old.js.map\nvar b=2;\n//# sourceMappingURL=main.js.map\n"}
+	require.Equal(t, "main.js.map", sourceMapReference(script), "last comment not used")
+
+	script.ResponseHeaders = http.Header{"X-Sourcemap": []string{"/maps/main.js.map"}}
+	require.Equal(t, "/maps/main.js.map", sourceMapReference(script), "header not preferred")
+
+	legacy := HistoryData{ResponseBody: "var a=1;\n/*@ sourceMappingURL=legacy.map */"}
+	require.Equal(t, "legacy.map", sourceMapReference(legacy), "legacy comment not matched")
+	require.Empty(t, sourceMapReference(HistoryData{ResponseBody: "var sourceMappingURL = 1;"}), "variable matched as a comment")
+}
+
+func TestDecodeDataURI(t *testing.T) {
+	encoded := base64.RawStdEncoding.EncodeToString([]byte(`{"version":3}`))
+	data, err := decodeDataURI("data:application/json;charset=utf-8;base64," + encoded)
+	require.Nil(t, err, "could not decode unpadded base64 data uri")
+	require.Equal(t, `{"version":3}`, string(data), "wrong base64 data")
+
+	data, err = decodeDataURI(`data:application/json,%7B%22version%22%3A3%7D`)
+	require.Nil(t, err, "could not decode percent encoded data uri")
+	require.Equal(t, `{"version":3}`, string(data), "wrong percent encoded data")
+
+	_, err = decodeDataURI("data:application/json;base64")
+	require.NotNil(t, err, "invalid data uri decoded")
+}
+
+func TestParseSourceMap(t *testing.T) {
+	sources, content, err := parseSourceMap([]byte(`{"version":3,"sourceRoot":"webpack:///","sources":["src/app.ts","https://cdn.example.com/lib.js"],"sourcesContent":["const a = 1;",null],"mappings":"AAAA"}`))
+	require.Nil(t, err, "could not parse source map")
+	require.Equal(t, []string{"webpack:///src/app.ts", "https://cdn.example.com/lib.js"}, sources, "wrong sources")
+	require.True(t, content, "sources content not detected")
+
+	sources, content, err = parseSourceMap([]byte(`{"version":3,"sections":[{"offset":{"line":0,"column":0},"map":{"version":3,"sources":["a.js"],"mappings":""}}]}`))
+	require.Nil(t, err, "could not parse index source map")
+	require.Equal(t, []string{"a.js"}, sources, "wrong index map sources")
+	require.False(t, content, "sources content detected")
+
+	_, _, err = parseSourceMap([]byte("<html><body>app</body></html>"))
+	require.NotNil(t, err, "html fallback parsed as a source map")
+	_, _, err = parseSourceMap([]byte(`{"name":"package"}`))
+	require.NotNil(t, err, "json document parsed as a source map")
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// tracker is a known third-party tracker or analytics sdk
+type tracker struct {
+	// Name is the name of the tracker
+	Name string
+	// Category is the category of the tracker (analytics, advertising, etc)
+	Category string
+	// Domain is the domain the tracker is loaded from, matching its subdomains,
+	// empty for trackers matched on the path only
+	Domain string
+	// Path is the prefix of the path of the tracker urls if it has a domain,
+	// or the suffix of the path of the self-hosted trackers otherwise
+	Path string
+}
+
+// defaultTrackers are the built-in known trackers
+var defaultTrackers = mustParseTrackers(`
+google-analytics.com,Google Analytics,analytics
+analytics.google.com,Google Analytics,analytics
+googletagmanager.com,Google Tag Manager,tag-manager
+doubleclick.net,DoubleClick,advertising
+googlesyndication.com,Google AdSense,advertising
+googleadservices.com,Google Ads,advertising
+connect.facebook.net,Facebook Pixel,advertising
+facebook.com/tr,Facebook Pixel,advertising
+bat.bing.com,Microsoft Advertising,advertising
+snap.licdn.com,LinkedIn Insight Tag,advertising
+px.ads.linkedin.com,LinkedIn Insight Tag,advertising
+static.ads-twitter.com,Twitter Pixel,advertising
+analytics.twitter.com,Twitter Pixel,advertising
+analytics.tiktok.com,TikTok Pixel,advertising
+sc-static.net,Snap Pixel,advertising
+ct.pinterest.com,Pinterest Tag,advertising
+s.pinimg.com/ct,Pinterest Tag,advertising
+criteo.com,Criteo,advertising
+criteo.net,Criteo,advertising
+taboola.com,Taboola,advertising
+outbrain.com,Outbrain,advertising
+adnxs.com,Xandr,advertising
+quantserve.com,Quantcast,advertising
+scorecardresearch.com,Comscore,analytics
+cdn.segment.com,Segment,analytics
+api.segment.io,Segment,analytics
+mixpanel.com,Mixpanel,analytics
+mxpnl.com,Mixpanel,analytics
+amplitude.com,Amplitude,analytics
+heapanalytics.com,Heap,analytics
+mc.yandex.ru,Yandex Metrica,analytics
+plausible.io,Plausible,analytics
+static.cloudflareinsights.com,Cloudflare Web Analytics,analytics
+/matomo.js,Matomo,analytics
+/piwik.js,Matomo,analytics
+hotjar.com,Hotjar,session-recording
+fullstory.com,FullStory,session-recording
+clarity.ms,Microsoft Clarity,session-recording
+mouseflow.com,Mouseflow,session-recording
+crazyegg.com,Crazy Egg,session-recording
+js-agent.newrelic.com,New Relic,monitoring
+bam.nr-data.net,New Relic,monitoring
+browser.sentry-cdn.com,Sentry,error-tracking
+ingest.sentry.io,Sentry,error-tracking
+cdn.optimizely.com,Optimizely,ab-testing
+js.hs-scripts.com,HubSpot,marketing
+js.hs-analytics.net,HubSpot,marketing
+munchkin.marketo.net,Marketo,marketing
+pi.pardot.com,Pardot,marketing
+`)
+
+// parseTrackers parses newline separated trackers in pattern,name,category
+// format where the pattern is a domain optionally followed by a path prefix,
+// or a path suffix starting with / matching self-hosted trackers on any host.
+// Empty lines and lines starting with # are ignored.
+func parseTrackers(value string) ([]tracker, error) {
+	var trackers []tracker
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Split(line, ",")
+		if len(parts) != 3 {
+			return nil, errors.Errorf("invalid tracker %s, expected pattern,name,category", line)
+		}
+		pattern, name, category := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), strings.TrimSpace(parts[2])
+		if pattern == "" || name == "" || category == "" {
+			return nil, errors.Errorf("invalid tracker %s, expected pattern,name,category", line)
+		}
+
+		item := tracker{Name: name, Category: category}
+		if strings.HasPrefix(pattern, "/") {
+			item.Path = pattern
+		} else if index := strings.Index(pattern, "/"); index != -1 {
+			item.Domain, item.Path = strings.ToLower(pattern[:index]), pattern[index:]
+		} else {
+			item.Domain = strings.ToLower(pattern)
+		}
+		trackers = append(trackers, item)
+	}
+	return trackers, nil
+}
+
+// mustParseTrackers parses trackers, panicking on invalid ones
+func mustParseTrackers(value string) []tracker {
+	trackers, err := parseTrackers(value)
+	if err != nil {
+		panic(err)
+	}
+	return trackers
+}
+
+// matches returns true if the url is one of the tracker
+func (t tracker) matches(parsed *url.URL) bool {
+	if t.Domain == "" {
+		return strings.HasSuffix(parsed.Path, t.Path)
+	}
+	hostname := strings.ToLower(parsed.Hostname())
+	if hostname != t.Domain && !strings.HasSuffix(hostname, "."+t.Domain) {
+		return false
+	}
+	return strings.HasPrefix(parsed.Path, t.Path)
+}
+
+// classifyTracker returns the first tracker matching the url, if any
+func classifyTracker(trackers []tracker, rawURL string) (tracker, bool) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return tracker{}, false
+	}
+	for _, item := range trackers {
+		if item.matches(parsed) {
+			return item, true
+		}
+	}
+	return tracker{}, false
+}
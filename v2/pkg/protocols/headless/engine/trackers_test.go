@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyTracker(t *testing.T) {
+	trackers, err := parseTrackers("# custom trackers\ncdn.example.com/track,Example Tracker,analytics\n")
+	require.Nil(t, err, "could not parse trackers")
+	trackers = append(trackers, defaultTrackers...)
+
+	tests := map[string]string{
+		"https://www.google-analytics.com/analytics.js":      "Google Analytics",
+		"https://www.facebook.com/tr?id=1&ev=PageView":       "Facebook Pixel",
+		"https://cdn.example.com/track/v1.js":                "Example Tracker",
+		"https://example.org/assets/matomo.js":               "Matomo",
+		"https://static.hotjar.com/c/hotjar-1.js?sv=6":       "Hotjar",
+		"https://www.facebook.com/login":                     "",
+		"https://cdn.example.com/app.js":                     "",
+		"https://notgoogle-analytics.com/analytics.js":       "",
+		"https://google-analytics.com.attacker.com/track.js": "",
+	}
+	for rawURL, expected := range tests {
+		item, ok := classifyTracker(trackers, rawURL)
+		require.Equal(t, expected != "", ok, "wrong classification of %s", rawURL)
+		require.Equal(t, expected, item.Name, "wrong tracker for %s", rawURL)
+	}
+
+	_, err = parseTrackers("invalid,tracker")
+	require.NotNil(t, err, "invalid tracker parsed")
+}
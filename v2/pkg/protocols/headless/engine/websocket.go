@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"encoding/json"
+)
+
+// maxWebSocketFrames is the maximum number of websocket frames recorded by a hook
+const maxWebSocketFrames = 1000
+
+// maxInjectedWebSocketFrames is the maximum number of frames injected in a websocket
+const maxInjectedWebSocketFrames = 100
+
+// WebSocketFrame is a websocket frame sent or received by the page
+type WebSocketFrame struct {
+	// URL is the url of the websocket
+	URL string `json:"url"`
+	// Direction is the direction of the frame (sent or received)
+	Direction string `json:"direction"`
+	// Data is the data of the frame as sent or delivered to the page, or a
+	// description of its size for binary frames
+	Data string `json:"data"`
+	// Original is the data of the frame before it was modified
+	Original string `json:"original,omitempty"`
+	// Modified is true if the frame was modified
+	Modified bool `json:"modified,omitempty"`
+	// Injected is true if the frame was injected
+	Injected bool `json:"injected,omitempty"`
+	// Binary is true for binary frames
+	Binary bool `json:"binary,omitempty"`
+}
+
+// webSocketHookConfig is the configuration of the websocket hook
+type webSocketHookConfig struct {
+	// Endpoint is the regex matching the urls of the websockets hooked, all if empty
+	Endpoint string `json:"endpoint"`
+	// Match is the regex matching the parts of the text frames replaced
+	Match string `json:"match"`
+	// Replace is the replacement of the matched parts, with $1 style groups
+	Replace string `json:"replace"`
+	// Direction is the direction of the frames modified (sent, received or both)
+	Direction string `json:"direction"`
+	// Inject are the frames sent once a hooked websocket opens
+	Inject []string `json:"inject"`
+	// Max is the maximum number of frames recorded
+	Max int `json:"max"`
+}
+
+// webSocketHookJS replaces the WebSocket constructor of the page to record
+// the frames of the websockets matching the endpoint, modify their text
+// frames and inject frames once they open.
+//
+// Received frames are modified by stopping the message event before the
+// listeners of the page and dispatching a copy with the modified data.
+const webSocketHookJS = `(config) => {
+	if (window.__nucleiWebSocket) {
+		return;
+	}
+	const frames = window.__nucleiWebSocket = [];
+	const endpoint = config.endpoint ? new RegExp(config.endpoint) : null;
+	const match = config.match ? new RegExp(config.match, 'g') : null;
+	const record = (frame) => {
+		if (frames.length < config.max) {
+			frames.push(frame);
+		}
+	};
+	const describe = (data) => {
+		if (typeof data === 'string') {
+			return {data: data, binary: false};
+		}
+		const size = data.byteLength !== undefined ? data.byteLength : (data.size || 0);
+		return {data: '[binary ' + size + ' bytes]', binary: true};
+	};
+	const modify = (direction, data) => {
+		if (!match || typeof data !== 'string' || (config.direction !== 'both' && config.direction !== direction)) {
+			return data;
+		}
+		return data.replace(match, config.replace);
+	};
+	const synthetic = new WeakSet();
+	const NativeWebSocket = window.WebSocket;
+	const nativeSend = NativeWebSocket.prototype.send;
+	class HookedWebSocket extends NativeWebSocket {
+		constructor(...args) {
+			super(...args);
+			this.__nucleiHooked = !endpoint || endpoint.test(this.url);
+			if (!this.__nucleiHooked) {
+				return;
+			}
+			this.addEventListener('open', () => {
+				for (const frame of config.inject) {
+					nativeSend.call(this, frame);
+					record({url: this.url, direction: 'sent', data: frame, injected: true});
+				}
+			});
+			this.addEventListener('message', (event) => {
+				if (synthetic.has(event)) {
+					return;
+				}
+				const data = modify('received', event.data);
+				const frame = Object.assign({url: this.url, direction: 'received'}, describe(event.data));
+				if (data === event.data) {
+					record(frame);
+					return;
+				}
+				record(Object.assign(frame, {data: data, original: event.data, modified: true}));
+				event.stopImmediatePropagation();
+				const modified = new MessageEvent('message', {data: data, origin: event.origin, lastEventId: event.lastEventId, ports: Array.from(event.ports)});
+				synthetic.add(modified);
+				this.dispatchEvent(modified);
+			});
+		}
+		send(data) {
+			if (!this.__nucleiHooked) {
+				return nativeSend.call(this, data);
+			}
+			const modified = modify('sent', data);
+			const frame = Object.assign({url: this.url, direction: 'sent'}, describe(data));
+			if (modified !== data) {
+				Object.assign(frame, {data: modified, original: data, modified: true});
+			}
+			record(frame);
+			return nativeSend.call(this, modified);
+		}
+	}
+	window.WebSocket = HookedWebSocket;
+}`
+
+// addWebSocketFrames records websocket frames of the page
+func (p *Page) addWebSocketFrames(frames ...WebSocketFrame) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.WebSocketFrames = append(p.WebSocketFrames, frames...)
+}
+
+// DumpWebSocketFrames returns the websocket frames recorded by the page as json
+func (p *Page) DumpWebSocketFrames() string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	frames := p.WebSocketFrames
+	if frames == nil {
+		frames = []WebSocketFrame{}
+	}
+	data, _ := json.Marshal(frames)
+	return string(data)
+}
@@ -29,7 +29,20 @@ type Request struct {
 	//   Payloads support both key-values combinations where a list
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
+	//
+	//   Payloads can also be generated lazily from a map naming a value generator:
+	//   range (start, end, step, format), date (start, end, step, format) or
+	//   charset (charset, min, max), bounded by an optional limit of values.
 	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the headless request,description=Payloads contains any payloads for the current request"`
+	// description: |
+	//   MaxCombinations is the maximum number of payload combinations the steps are run with
+	//   for an input, the remaining combinations being skipped. Default is 0 (no limit).
+	//
+	//   The steps are run once per combination, each one reported with its payload values.
+	// examples:
+	//   - name: Try at most 50 payload combinations
+	//     value: 50
+	MaxCombinations int `yaml:"max-combinations,omitempty" json:"max-combinations,omitempty" jsonschema:"title=maximum payload combinations,description=Maximum number of payload combinations the steps are run with for an input"`
 
 	// description: |
 	//   Steps is the list of actions to run for headless request
@@ -60,14 +73,25 @@ type Request struct {
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"template-id":    "ID of the template executed",
-	"template-info":  "Info Block of the template executed",
-	"template-path":  "Path of the template executed",
-	"host":           "Host is the input to the template",
-	"matched":        "Matched is the input which was matched upon",
-	"type":           "Type is the type of request made",
-	"req":            "Headless request made from the client",
-	"resp,body,data": "Headless response received from client (default)",
+	"template-id":           "ID of the template executed",
+	"template-info":         "Info Block of the template executed",
+	"template-path":         "Path of the template executed",
+	"host":                  "Host is the input to the template",
+	"matched":               "Matched is the input which was matched upon",
+	"type":                  "Type is the type of request made",
+	"req":                   "Headless request made from the client",
+	"resp,body,data":        "Headless response received from client (default)",
+	"outgoing_requests":     "Method, URL and body of the fetch/XHR requests made by the page",
+	"referrers":             "URL and Referer header of the requests made by the page with a referrer",
+	"headers":               "Response headers of the last loaded document as a case-insensitive map (e.g. headers[\"x-powered-by\"])",
+	"resources":             "Number of resources loaded by the page, also available by lowercase resource type (e.g. resources_script)",
+	"third_party_resources": "Number of resources loaded from third-party domains, also available by resource type (e.g. third_party_resources_script)",
+	"third_party_domains":   "Number of distinct third-party domains resources were loaded from",
+	"set_cookies":           "JSON array of the cookies set by the Set-Cookie headers of every response with their url, parsed attributes and cleared and third_party flags",
+	"websocket_frames":      "JSON array of the websocket frames recorded by the websocket actions with their url, direction, data and original data if modified",
+	"user_agent":            "User-Agent set for the page (only if custom or rotated)",
+	"truncated":             "True if the page html or a response body of the history was truncated to the maximum body size",
+	"final_url":             "URL of the page once the actions were executed, after server and client-side redirects",
 }
 
 // Step is a headless protocol request step.
@@ -81,6 +105,11 @@ func (request *Request) GetID() string {
 	return request.ID
 }
 
+// Options returns executer options for headless request
+func (request *Request) Options() *protocols.ExecuterOptions {
+	return request.options
+}
+
 // Compile compiles the protocol request for further execution.
 func (request *Request) Compile(options *protocols.ExecuterOptions) error {
 	// TODO: logic similar to network + http => probably can be refactored
@@ -96,6 +125,9 @@ func (request *Request) Compile(options *protocols.ExecuterOptions) error {
 		}
 	}
 
+	if request.MaxCombinations < 0 {
+		return errors.Errorf("invalid max-combinations %d", request.MaxCombinations)
+	}
 	if len(request.Payloads) > 0 {
 		var err error
 		request.generator, err = generators.New(request.Payloads, request.AttackType.Value, options.TemplatePath, options.Options.Sandbox, options.Catalog, options.Options.AttackType)
@@ -134,5 +166,12 @@ func (request *Request) Compile(options *protocols.ExecuterOptions) error {
 
 // Requests returns the total number of requests the YAML rule will perform
 func (request *Request) Requests() int {
-	return 1
+	if request.generator == nil {
+		return 1
+	}
+	total := request.generator.NewIterator().Total()
+	if request.MaxCombinations > 0 && total > request.MaxCombinations {
+		return request.MaxCombinations
+	}
+	return total
 }
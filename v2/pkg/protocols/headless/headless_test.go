@@ -0,0 +1,49 @@
+package headless
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/model"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/headless/engine"
+	"github.com/projectdiscovery/nuclei/v2/pkg/testutils"
+)
+
+func TestHeadlessCompileMaxCombinations(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-headless"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	payloads := map[string]interface{}{"xss": []string{"<img src=x>", "<svg onload=1>", "javascript:1"}}
+
+	request := &Request{Payloads: payloads}
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile headless request")
+	require.Equal(t, 3, request.Requests(), "wrong number of requests")
+
+	request = &Request{Payloads: payloads, MaxCombinations: 2}
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile headless request")
+	require.Equal(t, 2, request.Requests(), "wrong number of capped requests")
+
+	request = &Request{Payloads: payloads, MaxCombinations: -1}
+	require.NotNil(t, request.Compile(executerOpts), "could compile invalid max-combinations")
+}
+
+func TestContainsStatefulActions(t *testing.T) {
+	steps := []*engine.Action{
+		{ActionType: engine.ActionTypeHolder{ActionType: engine.ActionNavigate}},
+		{ActionType: engine.ActionTypeHolder{ActionType: engine.ActionTextInput}},
+		{ActionType: engine.ActionTypeHolder{ActionType: engine.ActionClick}},
+	}
+	require.False(t, containsStatefulActions(steps), "stateless steps can't share an instance")
+
+	steps = append(steps, &engine.Action{ActionType: engine.ActionTypeHolder{ActionType: engine.ActionScript}})
+	require.True(t, containsStatefulActions(steps), "steps running scripts share an instance")
+}
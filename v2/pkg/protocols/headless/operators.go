@@ -1,6 +1,7 @@
 package headless
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/projectdiscovery/nuclei/v2/pkg/model"
@@ -9,6 +10,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v2/pkg/output"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/utils"
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
 )
 
@@ -28,6 +30,16 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchRegex(itemStr))
 	case matchers.BinaryMatcher:
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(itemStr))
+	case matchers.JSONSchemaMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchJSONSchema(itemStr))
+	case matchers.DifferentialMatcher:
+		baseline, ok := request.getMatchPart(matcher.Baseline, data)
+		if !ok {
+			return false, []string{}
+		}
+		return matcher.ResultWithMatchedSnippet(matcher.MatchDifferential(itemStr, baseline))
+	case matchers.StructureMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchStructure(itemStr))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
 	}
@@ -46,6 +58,8 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractRegex(itemStr)
 	case extractors.KValExtractor:
 		return extractor.ExtractKval(data)
+	case extractors.StructureExtractor:
+		return extractor.ExtractStructure(itemStr)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
 	}
@@ -70,17 +84,20 @@ func (request *Request) getMatchPart(part string, data output.InternalEvent) (st
 }
 
 // responseToDSLMap converts a headless response to a map for use in DSL matching
-func (request *Request) responseToDSLMap(resp, req, host, matched string, history string) output.InternalEvent {
+func (request *Request) responseToDSLMap(resp, req, host, matched string, history, outgoingRequests, referrers string, headers http.Header) output.InternalEvent {
 	return output.InternalEvent{
-		"host":          host,
-		"matched":       matched,
-		"req":           req,
-		"data":          resp,
-		"history":       history,
-		"type":          request.Type().String(),
-		"template-id":   request.options.TemplateID,
-		"template-info": request.options.TemplateInfo,
-		"template-path": request.options.TemplatePath,
+		"host":              host,
+		"matched":           matched,
+		"req":               req,
+		"data":              resp,
+		"history":           history,
+		"outgoing_requests": outgoingRequests,
+		"referrers":         referrers,
+		"headers":           utils.HeadersToMap(headers),
+		"type":              request.Type().String(),
+		"template-id":       request.options.TemplateID,
+		"template-info":     request.options.TemplateInfo,
+		"template-path":     request.options.TemplatePath,
 	}
 }
 
@@ -107,6 +124,7 @@ func (request *Request) MakeResultEventItem(wrapped *output.InternalWrappedEvent
 		IP:               types.ToString(wrapped.InternalEvent["ip"]),
 		Request:          types.ToString(wrapped.InternalEvent["request"]),
 		Response:         types.ToString(wrapped.InternalEvent["data"]),
+		Metadata:         wrapped.OperatorsResult.PayloadValues,
 	}
 	return data
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/responsehighlighter"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/utils/vardump"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/headless/engine"
 	protocolutils "github.com/projectdiscovery/nuclei/v2/pkg/protocols/utils"
 	templateTypes "github.com/projectdiscovery/nuclei/v2/pkg/templates/types"
 )
@@ -52,51 +53,114 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 		}
 	}
 
-	if request.generator != nil {
-		iterator := request.generator.NewIterator()
-		for {
-			value, ok := iterator.Value()
-			if !ok {
-				break
-			}
-			if gotmatches && (request.StopAtFirstMatch || request.options.Options.StopAtFirstMatch || request.options.StopAtFirstMatch) {
-				return nil
-			}
-			value = generators.MergeMaps(value, payloads)
-			if err := request.executeRequestWithPayloads(inputURL, value, previous, wrappedCallback); err != nil {
-				return err
-			}
+	if request.generator == nil {
+		instance, err := request.newInstance(inputURL)
+		if err != nil {
+			return err
 		}
-	} else {
-		value := maps.Clone(payloads)
-		if err := request.executeRequestWithPayloads(inputURL, value, previous, wrappedCallback); err != nil {
+		defer instance.Close()
+		return request.executeRequestWithPayloads(instance, inputURL, maps.Clone(payloads), nil, previous, wrappedCallback)
+	}
+
+	// the combinations share an instance with its cookies cleared between them,
+	// unless the steps may leave other state behind in the browser instance
+	var shared *engine.Instance
+	if !containsStatefulActions(request.Steps) {
+		instance, err := request.newInstance(inputURL)
+		if err != nil {
+			return err
+		}
+		defer instance.Close()
+		shared = instance
+	}
+
+	iterator := request.generator.NewIterator()
+	for combinations := 0; ; combinations++ {
+		value, ok := iterator.Value()
+		if !ok {
+			break
+		}
+		if request.MaxCombinations > 0 && combinations >= request.MaxCombinations {
+			gologger.Verbose().Msgf("[%s] Skipped the payload combinations beyond the maximum of %d for %s", request.options.TemplateID, request.MaxCombinations, inputURL)
+			break
+		}
+		if gotmatches && (request.StopAtFirstMatch || request.options.Options.StopAtFirstMatch || request.options.StopAtFirstMatch) {
+			return nil
+		}
+		if err := request.executeCombination(shared, inputURL, value, generators.MergeMaps(value, payloads), previous, wrappedCallback); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (request *Request) executeRequestWithPayloads(inputURL string, payloads map[string]interface{}, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+// executeCombination runs the steps with a payload combination, in the shared
+// instance cleared of the cookies of the previous combinations if any
+func (request *Request) executeCombination(shared *engine.Instance, inputURL string, combination, payloads map[string]interface{}, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	instance := shared
+	if instance == nil {
+		var err error
+		if instance, err = request.newInstance(inputURL); err != nil {
+			return err
+		}
+		defer instance.Close()
+	} else if err := instance.ClearCookies(); err != nil {
+		request.options.Output.Request(request.options.TemplatePath, inputURL, request.Type().String(), err)
+		request.options.Progress.IncrementFailedRequestsBy(1)
+		return errors.Wrap(err, "could not clear cookies")
+	}
+	return request.executeRequestWithPayloads(instance, inputURL, payloads, combination, previous, callback)
+}
+
+// newInstance returns a new isolated browser instance for the request
+func (request *Request) newInstance(inputURL string) (*engine.Instance, error) {
 	instance, err := request.options.Browser.NewInstance()
 	if err != nil {
 		request.options.Output.Request(request.options.TemplatePath, inputURL, request.Type().String(), err)
 		request.options.Progress.IncrementFailedRequestsBy(1)
-		return errors.Wrap(err, errCouldGetHtmlElement)
+		return nil, errors.Wrap(err, errCouldGetHtmlElement)
 	}
-	defer instance.Close()
+	instance.SetInteractsh(request.options.Interactsh)
+	instance.SetMaxBodySize(request.options.MaxBodySize)
+	return instance, nil
+}
+
+// statefulActions are the actions which may leave state other than cookies,
+// such as web storage or scripts, in the browser instance the page belongs to
+var statefulActions = map[engine.ActionType]struct{}{
+	engine.ActionScript:            {},
+	engine.ActionEvalOnNewDocument: {},
+	engine.ActionImportSession:     {},
+}
+
+// containsStatefulActions returns true if any of the steps is a stateful action
+func containsStatefulActions(steps []*engine.Action) bool {
+	for _, step := range steps {
+		if _, ok := statefulActions[step.ActionType.ActionType]; ok {
+			return true
+		}
+	}
+	return false
+}
 
+// executeRequestWithPayloads runs the steps in an instance, the values of the
+// payload combination if any being reported with the results
+func (request *Request) executeRequestWithPayloads(instance *engine.Instance, inputURL string, payloads, combination map[string]interface{}, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
 	if vardump.EnableVarDump {
 		gologger.Debug().Msgf("Protocol request variables: \n%s\n", vardump.DumpVariables(payloads))
 	}
 
-	instance.SetInteractsh(request.options.Interactsh)
-
 	parsedURL, err := url.Parse(inputURL)
 	if err != nil {
 		request.options.Output.Request(request.options.TemplatePath, inputURL, request.Type().String(), err)
 		request.options.Progress.IncrementFailedRequestsBy(1)
 		return errors.Wrap(err, errCouldGetHtmlElement)
 	}
+	// Check if the request budget of the template for the host is exhausted
+	if !request.options.RequestBudget.Take(inputURL) {
+		request.options.Progress.IncrementSkippedRequestsBy(1)
+		return nil
+	}
 	timeout := time.Duration(request.options.Options.PageTimeout) * time.Second
 	out, page, err := instance.Run(parsedURL, request.Steps, payloads, timeout)
 	if err != nil {
@@ -123,13 +187,20 @@ func (request *Request) executeRequestWithPayloads(inputURL string, payloads map
 
 	}
 
-	var responseBody string
-	html, err := page.Page().Element("html")
-	if err == nil {
-		responseBody, _ = html.HTML()
-	}
+	html, truncated := request.options.TruncateBody([]byte(page.HTML()))
+	responseBody := string(html)
 
-	outputEvent := request.responseToDSLMap(responseBody, reqBuilder.String(), inputURL, inputURL, page.DumpHistory())
+	outputEvent := request.responseToDSLMap(responseBody, reqBuilder.String(), inputURL, inputURL, page.DumpHistory(), page.DumpOutgoingRequests(), page.DumpReferrers(), page.DocumentHeaders())
+	for k, v := range page.ResourceCounts() {
+		outputEvent[k] = v
+	}
+	outputEvent["set_cookies"] = page.DumpSetCookies()
+	outputEvent["websocket_frames"] = page.DumpWebSocketFrames()
+	outputEvent["truncated"] = truncated || page.Truncated()
+	outputEvent["final_url"] = page.URL()
+	if userAgent := page.UserAgent(); userAgent != "" {
+		outputEvent["user_agent"] = userAgent
+	}
 	for k, v := range out {
 		outputEvent[k] = v
 	}
@@ -139,7 +210,9 @@ func (request *Request) executeRequestWithPayloads(inputURL string, payloads map
 
 	var event *output.InternalWrappedEvent
 	if len(page.InteractshURLs) == 0 {
-		event = eventcreator.CreateEvent(request, outputEvent, request.options.Options.Debug || request.options.Options.DebugResponse)
+		event = eventcreator.CreateEventWithAdditionalOptions(request, outputEvent, request.options.Options.Debug || request.options.Options.DebugResponse, func(wrappedEvent *output.InternalWrappedEvent) {
+			wrappedEvent.OperatorsResult.PayloadValues = combination
+		})
 		callback(event)
 	} else if request.options.Interactsh != nil {
 		event = &output.InternalWrappedEvent{InternalEvent: outputEvent}
@@ -56,6 +56,21 @@ func (g *generatedRequest) URL() string {
 	return ""
 }
 
+// userAgent returns the user agent sent with the request
+func (g *generatedRequest) userAgent() string {
+	if g.request != nil {
+		return g.request.Header.Get("User-Agent")
+	}
+	if g.rawRequest != nil {
+		for key, value := range g.rawRequest.Headers {
+			if strings.EqualFold(key, "User-Agent") {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+	return ""
+}
+
 // Total returns the total number of requests for the generator
 func (r *requestGenerator) Total() int {
 	if r.payloadIterator != nil {
@@ -107,7 +122,10 @@ func (r *requestGenerator) Make(ctx context.Context, input *contextargs.Context,
 	// optionvars are vars passed from CLI or env variables
 	optionVars := generators.BuildPayloadFromOptions(r.request.options.Options)
 
-	variablesMap, interactURLs := r.options.Variables.EvaluateWithInteractsh(generators.MergeMaps(defaultReqVars, optionVars), r.options.Interactsh)
+	// variables are evaluated for every request as they may reference
+	// the values extracted by previous requests or the payloads. The dynamic
+	// values and payloads having the name of a variable take precedence over it.
+	variablesMap, interactURLs := r.options.Variables.EvaluateWithInteractsh(generators.MergeMaps(dynamicValues, defaultReqVars, optionVars, payloads), r.options.Interactsh)
 	if len(interactURLs) > 0 {
 		r.interactshURLs = append(r.interactshURLs, interactURLs...)
 	}
@@ -151,7 +169,7 @@ func (r *requestGenerator) Make(ctx context.Context, input *contextargs.Context,
 	finalparams := parsed.Params
 	finalparams.Merge(reqURL.Params)
 	reqURL.Params = finalparams
-	return r.generateHttpRequest(ctx, reqURL, finalVars, payloads)
+	return r.generateHttpRequest(ctx, reqURL, input.MetaInput.Request, finalVars, payloads)
 }
 
 // selfContained templates do not need/use target data and all values i.e {{Hostname}} , {{BaseURL}} etc are already available
@@ -229,22 +247,40 @@ func (r *requestGenerator) makeSelfContainedRequest(ctx context.Context, data st
 	if err != nil {
 		return nil, errorutil.NewWithErr(err).Msgf("failed to parse %v in self contained request", data).WithTag("self-contained")
 	}
-	return r.generateHttpRequest(ctx, urlx, values, payloads)
+	return r.generateHttpRequest(ctx, urlx, nil, values, payloads)
 }
 
 // generateHttpRequest generates http request from request data from template and variables
+// imported = http request imported for the target, whose method, headers and body are used unless set by the template
 // finalVars = contains all variables including generator and protocol specific variables
 // generatorValues = contains variables used in fuzzing or other generator specific values
-func (r *requestGenerator) generateHttpRequest(ctx context.Context, urlx *urlutil.URL, finalVars, generatorValues map[string]interface{}) (*generatedRequest, error) {
+func (r *requestGenerator) generateHttpRequest(ctx context.Context, urlx *urlutil.URL, imported *contextargs.HTTPRequest, finalVars, generatorValues map[string]interface{}) (*generatedRequest, error) {
 	method, err := expressions.Evaluate(r.request.Method.String(), finalVars)
 	if err != nil {
 		return nil, ErrEvalExpression.Wrap(err).Msgf("failed to evaluate while generating http request")
 	}
+	var body interface{}
+	if imported != nil {
+		if method == "" {
+			method = imported.Method
+		}
+		if imported.Body != "" {
+			body = imported.Body
+		}
+	}
 	// Build a request on the specified URL
-	req, err := retryablehttp.NewRequestFromURLWithContext(ctx, method, urlx, nil)
+	req, err := retryablehttp.NewRequestFromURLWithContext(ctx, method, urlx, body)
 	if err != nil {
 		return nil, err
 	}
+	// the headers set by the template override the imported ones
+	if imported != nil {
+		for key, value := range imported.Headers {
+			if !r.request.hasHeader(key) {
+				req.Header.Set(key, value)
+			}
+		}
+	}
 
 	request, err := r.fillRequest(req, finalVars)
 	if err != nil {
@@ -260,10 +296,14 @@ func (r *requestGenerator) generateRawRequest(ctx context.Context, rawRequest st
 
 	var rawRequestData *raw.Request
 	var err error
-	if r.request.SelfContained {
+	switch {
+	case r.request.RawBytes:
+		// raw bytes requests are sent as written
+		rawRequestData, err = raw.ParseRawBytes(rawRequest)
+	case r.request.SelfContained:
 		// in self contained requests baseURL is extracted from raw request itself
 		rawRequestData, err = raw.ParseRawRequest(rawRequest, r.request.Unsafe)
-	} else {
+	default:
 		rawRequestData, err = raw.Parse(rawRequest, baseURL, r.request.Unsafe)
 	}
 	if err != nil {
@@ -272,7 +312,7 @@ func (r *requestGenerator) generateRawRequest(ctx context.Context, rawRequest st
 
 	// Unsafe option uses rawhttp library
 	if r.request.Unsafe {
-		if len(r.options.Options.CustomHeaders) > 0 {
+		if len(r.options.Options.CustomHeaders) > 0 && !r.request.RawBytes {
 			_ = rawRequestData.TryFillCustomHeaders(r.options.Options.CustomHeaders)
 		}
 		unsafeReq := &generatedRequest{rawRequest: rawRequestData, meta: generatorValues, original: r.request, interactshURLs: r.interactshURLs}
@@ -390,3 +430,13 @@ func (r *requestGenerator) fillRequest(req *retryablehttp.Request, values map[st
 
 	return req, nil
 }
+
+// hasHeader returns true if the request sets the header, case insensitively
+func (request *Request) hasHeader(name string) bool {
+	for key := range request.Headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
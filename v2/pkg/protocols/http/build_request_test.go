@@ -217,3 +217,75 @@ func areUnique(elements []string) bool {
 	}
 	return true
 }
+
+func TestMakeRequestUserAgent(t *testing.T) {
+	options := *testutils.DefaultOptions
+	options.UserAgents = []string{"custom-agent/1.0"}
+	testutils.Init(&options)
+	defer testutils.Init(testutils.DefaultOptions)
+
+	templateID := "testing-http"
+	executerOpts := testutils.NewMockExecuterOptions(&options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	makeRequest := func(request *Request) *generatedRequest {
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile http request")
+		generator := request.newGenerator(false)
+		inputData, payloads, _ := generator.nextValue()
+		req, err := generator.Make(context.Background(), contextargs.NewWithInput("https://example.com"), inputData, payloads, map[string]interface{}{})
+		require.Nil(t, err, "could not make http request")
+		return req
+	}
+
+	req := makeRequest(&Request{ID: templateID, Path: []string{"{{BaseURL}}"}, Method: HTTPMethodTypeHolder{MethodType: HTTPGet}})
+	require.Equal(t, "custom-agent/1.0", req.userAgent(), "could not get rotated user agent")
+
+	req = makeRequest(&Request{ID: templateID, Path: []string{"{{BaseURL}}"}, Method: HTTPMethodTypeHolder{MethodType: HTTPGet}, Headers: map[string]string{"User-Agent": "explicit-agent"}})
+	require.Equal(t, "explicit-agent", req.userAgent(), "could not get explicit user agent")
+
+	req = makeRequest(&Request{ID: templateID, Raw: []string{"GET / HTTP/1.1\r\nHost: {{Hostname}}\r\nuser-agent: raw-agent\r\n\r\n"}, Unsafe: true})
+	require.Equal(t, "raw-agent", req.userAgent(), "could not get unsafe request user agent")
+}
+
+func TestMakeRequestFromImportedRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	input := contextargs.NewWithInput("https://example.com/api/users")
+	input.MetaInput.Request = &contextargs.HTTPRequest{
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": "application/json", "X-API-Key": "secret"},
+		Body:    `{"name":"string"}`,
+	}
+	makeRequest := func(request *Request) *generatedRequest {
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile http request")
+		generator := request.newGenerator(false)
+		inputData, payloads, _ := generator.nextValue()
+		req, err := generator.Make(context.Background(), input, inputData, payloads, map[string]interface{}{})
+		require.Nil(t, err, "could not make http request")
+		return req
+	}
+
+	req := makeRequest(&Request{ID: templateID, Path: []string{"{{BaseURL}}"}})
+	bodyBytes, _ := req.request.BodyBytes()
+	require.Equal(t, "POST", req.request.Method, "could not get imported method")
+	require.Equal(t, "secret", req.request.Header.Get("X-API-Key"), "could not get imported header")
+	require.Equal(t, "application/json", req.request.Header.Get("Content-Type"), "could not get imported content type")
+	require.Equal(t, `{"name":"string"}`, string(bodyBytes), "could not get imported body")
+
+	// the method, headers and body of the template take precedence
+	req = makeRequest(&Request{ID: templateID, Path: []string{"{{BaseURL}}"}, Method: HTTPMethodTypeHolder{MethodType: HTTPPut}, Body: `{"name":"nuclei"}`, Headers: map[string]string{"X-API-Key": "template"}})
+	bodyBytes, _ = req.request.BodyBytes()
+	require.Equal(t, "PUT", req.request.Method, "could not get template method")
+	require.Equal(t, []string{"template"}, req.request.Header["X-API-Key"], "could not get template header")
+	require.Empty(t, req.request.Header.Get("X-API-Key"), "imported header sent along with the template one")
+	require.Equal(t, `{"name":"nuclei"}`, string(bodyBytes), "could not get template body")
+}
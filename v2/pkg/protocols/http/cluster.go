@@ -16,7 +16,8 @@ func (request *Request) CanCluster(other *Request) bool {
 	if request.Method != other.Method ||
 		request.MaxRedirects != other.MaxRedirects ||
 		request.CookieReuse != other.CookieReuse ||
-		request.Redirects != other.Redirects {
+		request.Redirects != other.Redirects ||
+		request.HTTP2Frames != other.HTTP2Frames {
 		return false
 	}
 	if !compare.StringSlice(request.Path, other.Path) {
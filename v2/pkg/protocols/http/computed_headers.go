@@ -0,0 +1,119 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/expressions"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/utils"
+)
+
+// ComputedHeadersDefinitions contains the fields of the outgoing request
+// available to the computed headers along with their description.
+var ComputedHeadersDefinitions = map[string]string{
+	"request_method":    "Method of the request",
+	"request_url":       "Full url of the request",
+	"request_host":      "Host of the request url, with the port if any",
+	"request_path":      "Escaped path of the request url (/ if empty)",
+	"request_query":     "Raw query of the request url, without the ?",
+	"request_body":      "Body of the request",
+	"request_headers":   "Headers of the request as a case-insensitive map (e.g. request_headers[\"content-type\"])",
+	"request_timestamp": "Unix timestamp of the request in seconds, the same for all the computed headers",
+	"request_date":      "Date of the request in http format (e.g. Mon, 02 Jan 2006 15:04:05 GMT)",
+	"request_amz_date":  "Date of the request in the compact ISO 8601 format used by signing schemes (e.g. 20060102T150405Z)",
+}
+
+// applyComputedHeaders evaluates the computed headers of the request with the
+// template values and the fields of the outgoing request, and sets them on it.
+func (request *Request) applyComputedHeaders(generatedRequest *generatedRequest, input string, values map[string]interface{}) error {
+	if len(request.ComputedHeaders) == 0 {
+		return nil
+	}
+	requestValues, err := computedHeadersContext(generatedRequest, input, time.Now())
+	if err != nil {
+		return err
+	}
+	values = generators.MergeMaps(values, requestValues)
+
+	computed := make(map[string]string, len(request.ComputedHeaders))
+	for name, value := range request.ComputedHeaders {
+		evaluated, err := expressions.Evaluate(dsl.ExpandMapIndexes(value), values)
+		if err != nil {
+			return errors.Wrapf(err, "could not evaluate computed header %s", name)
+		}
+		computed[name] = evaluated
+	}
+	// headers are set once all are evaluated so that none sees another computed header
+	for name, value := range computed {
+		if generatedRequest.rawRequest != nil {
+			generatedRequest.rawRequest.Headers[name] = value
+			// unsafe requests are sent as their raw bytes
+			if err := generatedRequest.rawRequest.SetUnsafeHeader(name, value); err != nil {
+				return errors.Wrapf(err, "could not set computed header %s", name)
+			}
+			continue
+		}
+		generatedRequest.request.Header.Set(name, value)
+		if strings.EqualFold(name, "Host") {
+			generatedRequest.request.Host = value
+		}
+	}
+	return nil
+}
+
+// computedHeadersContext returns the fields of the outgoing request sent to the
+// input available to the computed headers, see ComputedHeadersDefinitions.
+func computedHeadersContext(generatedRequest *generatedRequest, input string, now time.Time) (map[string]interface{}, error) {
+	var method, body string
+	headers := make(http.Header)
+	if generatedRequest.rawRequest != nil {
+		method = generatedRequest.rawRequest.Method
+		body = generatedRequest.rawRequest.Data
+		for name, value := range generatedRequest.rawRequest.Headers {
+			headers.Add(name, value)
+		}
+	} else {
+		method = generatedRequest.request.Method
+		data, err := generatedRequest.request.BodyBytes()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read request body")
+		}
+		body = string(data)
+		headers = generatedRequest.request.Header
+	}
+
+	requestURL := generatedRequest.URL()
+	// unsafe requests are sent to the input with their raw path
+	if requestURL == "" && generatedRequest.rawRequest != nil {
+		requestURL = unsafeRequestURL(input, generatedRequest.rawRequest.Path)
+	}
+	parsed, err := url.Parse(requestURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse request url")
+	}
+	path := parsed.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	now = now.UTC()
+	return map[string]interface{}{
+		"request_method":    method,
+		"request_url":       requestURL,
+		"request_host":      parsed.Host,
+		"request_path":      path,
+		"request_query":     parsed.RawQuery,
+		"request_body":      body,
+		"request_headers":   utils.HeadersToMap(headers),
+		"request_timestamp": strconv.FormatInt(now.Unix(), 10),
+		"request_date":      now.Format(http.TimeFormat),
+		"request_amz_date":  now.Format("20060102T150405Z"),
+	}, nil
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/eventcreator"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/tostring"
+)
+
+// ExecuteWithFixture runs the matchers and extractors of the request against
+// a raw http response, such as a fixture read from a file, instead of sending
+// the request to a target. Input is used as the host and matched url.
+//
+// The operators are run in debug mode so that the results contain all the
+// matchers which fired, named after their index if they have no name.
+func (request *Request) ExecuteWithFixture(input string, rawResponse []byte, callback protocols.OutputEventCallback) error {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(rawResponse)), nil)
+	if err != nil {
+		return errors.Wrap(err, "could not parse http response")
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return errors.Wrap(err, "could not read http response body")
+	}
+
+	dumpedResponse, err := dumpResponseWithRedirectChain(resp, body, request.options.Options.TranscodeResponse)
+	if err != nil {
+		return errors.Wrap(err, "could not read http response")
+	}
+	response := dumpedResponse[0]
+	outputEvent := request.responseToDSLMap(response.resp, input, input, "", tostring.UnsafeToString(response.fullResponse), tostring.UnsafeToString(response.body), tostring.UnsafeToString(response.headers), 0, nil)
+	if response.rawBody != nil && !bytes.Equal(response.rawBody, response.body) {
+		outputEvent["raw_body"] = tostring.UnsafeToString(response.rawBody)
+	}
+	if response.charset != "" {
+		outputEvent["charset"] = response.charset
+	}
+	if response.charsetBody != nil {
+		outputEvent["charset_body"] = tostring.UnsafeToString(response.charsetBody)
+	}
+	outputEvent["truncated"] = false
+	outputEvent["rate_limit_delay"] = rateLimitDelay(response.resp)
+
+	callback(eventcreator.CreateEvent(request, outputEvent, true))
+	return nil
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/model"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/testutils"
+)
+
+func TestExecuteWithFixture(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:     templateID,
+		Name:   "testing",
+		Path:   []string{"{{BaseURL}}"},
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{
+				{Type: matchers.MatcherTypeHolder{MatcherType: matchers.StatusMatcher}, Status: []int{200}},
+				{Name: "server", Type: matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher}, Part: "header", Words: []string{"nginx"}},
+				{Name: "missing", Type: matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher}, Words: []string{"not in the response"}},
+			},
+			Extractors: []*extractors.Extractor{
+				{Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.RegexExtractor}, Regex: []string{`version ([0-9.]+)`}, RegexGroup: 1},
+			},
+		},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	fixture := "HTTP/1.1 200 OK\r\nServer: nginx\r\nContent-Type: text/html\r\n\r\n<html>version 1.2.3</html>"
+	var event *output.InternalWrappedEvent
+	err = request.ExecuteWithFixture("fixture.txt", []byte(fixture), func(e *output.InternalWrappedEvent) {
+		event = e
+	})
+	require.Nil(t, err, "could not run request against fixture")
+	require.NotNil(t, event.OperatorsResult, "no operators result")
+
+	var fired []string
+	for name := range event.OperatorsResult.Matches {
+		fired = append(fired, name)
+	}
+	sort.Strings(fired)
+	require.Equal(t, []string{"server", "status-1"}, fired, "wrong matchers fired")
+	require.Equal(t, []string{"1.2.3"}, event.OperatorsResult.OutputExtracts, "wrong values extracted")
+	require.Equal(t, "fixture.txt", event.InternalEvent["matched"], "wrong matched input")
+
+	err = request.ExecuteWithFixture("fixture.txt", []byte("not a response"), func(e *output.InternalWrappedEvent) {})
+	require.NotNil(t, err, "invalid fixture parsed")
+}
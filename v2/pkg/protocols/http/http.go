@@ -12,6 +12,8 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/expressions"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/hostbreaker"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/ja3"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/http/fuzz"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/http/httpclientpool"
 	"github.com/projectdiscovery/rawhttp"
@@ -47,6 +49,16 @@ type Request struct {
 	//  in a combined manner allowing multi-request based matchers.
 	Name string `yaml:"name,omitempty" json:"name,omitempty" jsonschema:"title=name for the http request,description=Optional name for the HTTP Request"`
 	// description: |
+	//   DependsOn contains the IDs of the requests of the template this request depends on.
+	//
+	//   Once a request of the template declares dependencies, every request runs as soon as the
+	//   requests it depends on have completed, concurrently with the independent ones. The values
+	//   extracted by the dependencies are available to the request.
+	// examples:
+	//   - value: >
+	//       []string{"login"}
+	DependsOn []string `yaml:"depends-on,omitempty" json:"depends-on,omitempty" jsonschema:"title=ids of the requests this request depends on,description=IDs of the requests of the template to complete before this request"`
+	// description: |
 	//   Attack is the type of payload combinations to perform.
 	//
 	//   batteringram is inserts the same payload into all defined payload positions at once, pitchfork combines multiple payload sets and clusterbomb generates
@@ -71,6 +83,10 @@ type Request struct {
 	//   Payloads support both key-values combinations where a list
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
+	//
+	//   Payloads can also be generated lazily from a map naming a value generator:
+	//   range (start, end, step, format), date (start, end, step, format) or
+	//   charset (charset, min, max), bounded by an optional limit of values.
 	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the http request,description=Payloads contains any payloads for the current request"`
 
 	// description: |
@@ -80,6 +96,19 @@ type Request struct {
 	//       map[string]string{"Content-Type": "application/x-www-form-urlencoded", "Content-Length": "1", "Any-Header": "Any-Value"}
 	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"title=headers to send with the http request,description=Headers contains HTTP Headers to send with the request"`
 	// description: |
+	//   ComputedHeaders contains HTTP Headers computed with DSL expressions right before the request is sent.
+	//
+	//   Besides the template variables and extracted values, the expressions have access to the fields
+	//   of the outgoing request: `request_method`, `request_url`, `request_host`, `request_path`,
+	//   `request_query`, `request_body`, `request_headers` (case-insensitive map), `request_timestamp`
+	//   (unix seconds), `request_date` (http format) and `request_amz_date` (e.g. 20060102T150405Z),
+	//   which allows request signing schemes. The timestamp is the same for all the computed headers,
+	//   which don't see each other and override the headers of the request.
+	// examples:
+	//   - value: |
+	//       map[string]string{"X-Timestamp": "{{request_timestamp}}", "X-Signature": "{{hmac('sha256', request_method + request_path + request_timestamp, secret)}}"}
+	ComputedHeaders map[string]string `yaml:"computed-headers,omitempty" json:"computed-headers,omitempty" jsonschema:"title=headers computed before sending the http request,description=HTTP Headers computed with DSL expressions having access to the outgoing request fields"`
+	// description: |
 	//   RaceCount is the number of times to send a request in Race Condition Attack.
 	// examples:
 	//   - name: Send a request 5 times
@@ -131,6 +160,10 @@ type Request struct {
 	generator         *generators.PayloadGenerator // optional, only enabled when using payloads
 	httpClient        *retryablehttp.Client
 	rawhttpClient     *rawhttp.Client
+	// ja3 is the ja3 fingerprint of the client hello sent, if spoofed
+	ja3 string
+	// rateBreaker delays the requests as advertised by the rate limit headers, if respected
+	rateBreaker *hostbreaker.Breaker
 
 	// description: |
 	//   SelfContained specifies if the request is self-contained.
@@ -172,6 +205,17 @@ type Request struct {
 	//   control over the request, with no normalization performed by the client.
 	Unsafe bool `yaml:"unsafe,omitempty" json:"unsafe,omitempty" jsonschema:"title=use rawhttp non-strict-rfc client,description=Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests"`
 	// description: |
+	//   RawBytes sends the unsafe raw requests byte for byte over plaintext and tls
+	//   connections, for request smuggling and header injection tests.
+	//
+	//   Line endings are not converted to CRLF, the path of the input is not merged
+	//   with the request path and the custom headers are not inserted, so that malformed
+	//   content-length and transfer-encoding headers or bare CRs are sent as written.
+	//   The exact bytes received are available as `raw_response`, including the data
+	//   following the response until the connection is closed with `read-all`.
+	//   Redirects are not followed.
+	RawBytes bool `yaml:"raw-bytes,omitempty" json:"raw-bytes,omitempty" jsonschema:"title=send unsafe requests byte for byte,description=Sends the unsafe raw requests byte for byte capturing the raw response"`
+	// description: |
 	//   Race determines if all the request have to be attempted at the same time (Race Condition)
 	//
 	//   The actual number of requests that will be sent is determined by the `race_count`  field.
@@ -197,6 +241,50 @@ type Request struct {
 	// description: |
 	//   DigestAuthPassword specifies the password for digest authentication
 	DigestAuthPassword string `yaml:"digest-password,omitempty" json:"digest-password,omitempty" jsonschema:"title=specifies the password for digest authentication,description=Optional parameter which specifies the password for digest auth"`
+	// description: |
+	//   JA3 is the ja3 fingerprint to use for the tls client hello of the requests.
+	//
+	//   Ciphers, extensions and curves are sent in the order of the fingerprint. Overrides
+	//   the global ja3 option. Unsafe raw requests and requests made through http proxies
+	//   use the default client hello.
+	// examples:
+	//   - value: "\"771,4865-4866-4867-49195-49199,0-23-65281-10-11-35-16-5-13-51-45-43,29-23-24,0\""
+	JA3 string `yaml:"ja3,omitempty" json:"ja3,omitempty" jsonschema:"title=ja3 fingerprint of the client hello,description=JA3 fingerprint to use for the tls client hello of the requests"`
+	// description: |
+	//   HTTPVersion forces the http version used for the requests - negotiated if not specified.
+	//
+	//   With http2, https urls negotiate h2 with alpn and http urls use cleartext h2c with
+	//   prior knowledge. http3 requests are sent over QUIC and require https urls.
+	//   The negotiated version is available as the `http_version` variable. The global ja3
+	//   option only applies to http1 requests.
+	// values:
+	//   - "http1"
+	//   - "http2"
+	//   - "http3"
+	HTTPVersion string `yaml:"http-version,omitempty" json:"http-version,omitempty" jsonschema:"title=http version of the requests,description=HTTP version forced for the requests - negotiated if not specified,enum=http1,enum=http2,enum=http3"`
+	// description: |
+	//   HTTPVersionFallback retries the requests with the negotiated http version
+	//   if the server doesn't support the forced http version.
+	HTTPVersionFallback bool `yaml:"http-version-fallback,omitempty" json:"http-version-fallback,omitempty" jsonschema:"title=fallback from the forced http version,description=Retries requests with the negotiated http version if the server doesn't support the forced http version"`
+	// description: |
+	//   HTTP2Frames records the frames of the http2 connection of the requests to match
+	//   on stream-level details, such as the behaviour of servers vulnerable to http2 DoS patterns.
+	//
+	//   The requests are sent on a dedicated connection so that all its frames belong to
+	//   them. The frames are available as `h2_frames`, the pseudo-headers as `h2_pseudo_headers`
+	//   and `h2_request_pseudo_headers`, and the error codes of the RST_STREAM and GOAWAY
+	//   frames received as `h2_rst_stream` and `h2_goaway`. Requests failing with a stream
+	//   reset or a GOAWAY are still matched, with the error as `h2_stream_error`.
+	//   Requires the http2 http-version.
+	HTTP2Frames bool `yaml:"http2-frames,omitempty" json:"http2-frames,omitempty" jsonschema:"title=record the http2 frames,description=Records the frames of the http2 connection of the requests to match on pseudo-headers and stream errors"`
+	// description: |
+	//   RespectRateHeaders delays the next requests to a host by the delay advertised by the
+	//   rate limit headers of its responses.
+	//
+	//   Retry-After (in seconds or as an http date) takes precedence over the X-RateLimit-Reset
+	//   and RateLimit-Reset headers, which are only honored once the remaining requests are exhausted.
+	//   The delay is capped by the retry-backoff-max option and available as the `rate_limit_delay` variable.
+	RespectRateHeaders bool `yaml:"respect-rate-headers,omitempty" json:"respect-rate-headers,omitempty" jsonschema:"title=respect the rate limit headers,description=Delays the next requests to a host by the delay advertised by the rate limit headers of its responses"`
 }
 
 // Options returns executer options for http request
@@ -208,22 +296,40 @@ func (r *Request) Options() *protocols.ExecuterOptions {
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"template-id":           "ID of the template executed",
-	"template-info":         "Info Block of the template executed",
-	"template-path":         "Path of the template executed",
-	"host":                  "Host is the input to the template",
-	"matched":               "Matched is the input which was matched upon",
-	"type":                  "Type is the type of request made",
-	"request":               "HTTP request made from the client",
-	"response":              "HTTP response received from server",
-	"status_code":           "Status Code received from the Server",
-	"body":                  "HTTP response body received from server (default)",
-	"content_length":        "HTTP Response content length",
-	"header,all_headers":    "HTTP response headers",
-	"duration":              "HTTP request time duration",
-	"all":                   "HTTP response body + headers",
-	"cookies_from_response": "HTTP response cookies in name:value format",
-	"headers_from_response": "HTTP response headers in name:value format",
+	"template-id":               "ID of the template executed",
+	"template-info":             "Info Block of the template executed",
+	"template-path":             "Path of the template executed",
+	"host":                      "Host is the input to the template",
+	"matched":                   "Matched is the input which was matched upon",
+	"type":                      "Type is the type of request made",
+	"request":                   "HTTP request made from the client",
+	"response":                  "HTTP response received from server",
+	"status_code":               "Status Code received from the Server",
+	"body":                      "HTTP response body received from server (default)",
+	"raw_body":                  "HTTP response body before decompression (only if compressed)",
+	"raw_response":              "Exact bytes received for the request (only with raw-bytes)",
+	"charset":                   "Charset detected for the HTTP response body (only with transcode-response)",
+	"charset_body":              "HTTP response body in its original charset (only if transcoded)",
+	"content_length":            "HTTP Response content length",
+	"header,all_headers":        "HTTP response headers",
+	"duration":                  "HTTP request time duration",
+	"all":                       "HTTP response body + headers",
+	"cookies_from_response":     "HTTP response cookies in name:value format",
+	"headers_from_response":     "HTTP response headers in name:value format",
+	"headers":                   "HTTP response headers as a case-insensitive map (e.g. headers[\"x-powered-by\"])",
+	"ja3":                       "JA3 fingerprint of the tls client hello sent (only if a ja3 is configured)",
+	"ja3_hash":                  "MD5 hash of the JA3 fingerprint of the tls client hello sent",
+	"http_version":              "HTTP version negotiated for the response (e.g. HTTP/2.0)",
+	"user_agent":                "User-Agent sent with the request",
+	"truncated":                 "True if the response body was truncated to the maximum body size",
+	"rate_limit_delay":          "Delay in seconds advertised by the Retry-After or rate limit reset headers of the response (0 if none)",
+	"h2_frames":                 "HTTP/2 frames sent and received, one per line (only with http2-frames)",
+	"h2_pseudo_headers":         "HTTP/2 pseudo-headers of the response in name: value format (e.g. :status: 200)",
+	"h2_request_pseudo_headers": "HTTP/2 pseudo-headers of the request in name: value format",
+	"h2_rst_stream":             "Error codes of the HTTP/2 RST_STREAM frames received (e.g. REFUSED_STREAM)",
+	"h2_goaway":                 "Error codes of the HTTP/2 GOAWAY frames received (e.g. ENHANCE_YOUR_CALM)",
+	"h2_goaway_debug":           "Debug data of the HTTP/2 GOAWAY frames received",
+	"h2_stream_error":           "HTTP/2 stream or GOAWAY error the request failed with, if any",
 }
 
 // GetID returns the unique ID of the request if any.
@@ -231,6 +337,11 @@ func (request *Request) GetID() string {
 	return request.ID
 }
 
+// GetDependsOn returns the IDs of the requests the request depends on.
+func (request *Request) GetDependsOn() []string {
+	return request.DependsOn
+}
+
 func (request *Request) isRaw() bool {
 	return len(request.Raw) > 0
 }
@@ -263,12 +374,34 @@ func (request *Request) Compile(options *protocols.ExecuterOptions) error {
 			connectionConfiguration.NoTimeout = true
 		}
 	}
+	if ja3Value := request.JA3; ja3Value != "" || options.Options.JA3 != "" {
+		if ja3Value == "" {
+			ja3Value = options.Options.JA3
+		}
+		spec, err := ja3.Parse(ja3Value)
+		if err != nil {
+			return errors.Wrap(err, "could not parse ja3")
+		}
+		if request.ja3, err = ja3.Fingerprint(spec); err != nil {
+			return errors.Wrap(err, "could not build ja3 client hello")
+		}
+		connectionConfiguration.JA3 = ja3Value
+	}
+	connectionConfiguration.HTTPVersion = request.HTTPVersion
+	connectionConfiguration.HTTPVersionFallback = request.HTTPVersionFallback
 	request.connConfiguration = connectionConfiguration
 
 	client, err := httpclientpool.Get(options.Options, connectionConfiguration)
 	if err != nil {
 		return errors.Wrap(err, "could not get dns client")
 	}
+	if request.RespectRateHeaders {
+		maxDelay := options.Options.RetryBackoffMax
+		if maxDelay <= 0 {
+			maxDelay = hostbreaker.DefaultMaxDelay
+		}
+		request.rateBreaker = hostbreaker.New(hostbreaker.Options{MaxDelay: maxDelay, RespectRateHeaders: true})
+	}
 	request.customHeaders = make(map[string]string)
 	request.httpClient = client
 	request.options = options
@@ -285,7 +418,7 @@ func (request *Request) Compile(options *protocols.ExecuterOptions) error {
 	}
 	if len(request.Raw) > 0 {
 		for i, raw := range request.Raw {
-			if !strings.Contains(raw, "\r\n") {
+			if !strings.Contains(raw, "\r\n") && !request.RawBytes {
 				request.Raw[i] = strings.ReplaceAll(raw, "\n", "\r\n")
 			}
 		}
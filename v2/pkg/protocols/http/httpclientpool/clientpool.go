@@ -17,6 +17,7 @@ import (
 	"golang.org/x/net/publicsuffix"
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/ja3"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/utils"
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
@@ -29,6 +30,7 @@ var (
 	Dialer *fastdialer.Dialer
 
 	rawHttpClient     *rawhttp.Client
+	rawProxyClients   map[string]*rawhttp.Client
 	forceMaxRedirects int
 	poolMutex         *sync.RWMutex
 	normalClient      *retryablehttp.Client
@@ -46,6 +48,7 @@ func Init(options *types.Options) error {
 	}
 	poolMutex = &sync.RWMutex{}
 	clientPool = make(map[string]*retryablehttp.Client)
+	rawProxyClients = make(map[string]*rawhttp.Client)
 
 	client, err := wrappedGet(options, &Configuration{})
 	if err != nil {
@@ -98,6 +101,12 @@ type Configuration struct {
 	RedirectFlow RedirectFlow
 	// Connection defines custom connection configuration
 	Connection *ConnectionConfiguration
+	// JA3 is the ja3 fingerprint used for the tls client hello
+	JA3 string
+	// HTTPVersion is the http version forced for the requests
+	HTTPVersion string
+	// HTTPVersionFallback retries requests with the default transport if the server doesn't support the http version
+	HTTPVersionFallback bool
 }
 
 // Hash returns the hash of the configuration to allow client pooling
@@ -116,13 +125,18 @@ func (c *Configuration) Hash() string {
 	builder.WriteString(strconv.FormatBool(c.CookieReuse))
 	builder.WriteString("c")
 	builder.WriteString(strconv.FormatBool(c.Connection != nil))
+	builder.WriteString("j")
+	builder.WriteString(c.JA3)
+	builder.WriteString("v")
+	builder.WriteString(c.HTTPVersion)
+	builder.WriteString(strconv.FormatBool(c.HTTPVersionFallback))
 	hash := builder.String()
 	return hash
 }
 
 // HasStandardOptions checks whether the configuration requires custom settings
 func (c *Configuration) HasStandardOptions() bool {
-	return c.Threads == 0 && c.MaxRedirects == 0 && c.RedirectFlow == DontFollowRedirect && !c.CookieReuse && c.Connection == nil && !c.NoTimeout
+	return c.Threads == 0 && c.MaxRedirects == 0 && c.RedirectFlow == DontFollowRedirect && !c.CookieReuse && c.Connection == nil && !c.NoTimeout && c.JA3 == "" && c.HTTPVersion == ""
 }
 
 // GetRawHTTP returns the rawhttp request client
@@ -142,6 +156,32 @@ func GetRawHTTP(options *types.Options) *rawhttp.Client {
 	return rawHttpClient
 }
 
+// GetRawHTTPWithProxy returns the rawhttp request client sending requests through a proxy
+// of the pool, as rawhttp clients can't switch proxies between requests.
+func GetRawHTTPWithProxy(options *types.Options, proxyURL string) *rawhttp.Client {
+	poolMutex.RLock()
+	client, ok := rawProxyClients[proxyURL]
+	poolMutex.RUnlock()
+	if ok {
+		return client
+	}
+
+	// copy the default options as they are shared by reference
+	rawHttpOptions := *rawhttp.DefaultOptions
+	rawHttpOptions.Proxy = proxyURL
+	rawHttpOptions.Timeout = time.Duration(options.Timeout) * time.Second
+	client = rawhttp.NewClient(&rawHttpOptions)
+
+	poolMutex.Lock()
+	if existing, ok := rawProxyClients[proxyURL]; ok {
+		client = existing
+	} else {
+		rawProxyClients[proxyURL] = client
+	}
+	poolMutex.Unlock()
+	return client
+}
+
 // Get creates or gets a client for the protocol based on custom configuration
 func Get(options *types.Options, configuration *Configuration) (*retryablehttp.Client, error) {
 	if configuration.HasStandardOptions() {
@@ -234,8 +274,24 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 		TLSClientConfig:     tlsConfig,
 		DisableKeepAlives:   disableKeepAlives,
 	}
+	if protocolstate.HasSeparateTLSHandshake(options) {
+		transport.DialTLSContext = protocolstate.TLSDialer(Dialer.Dial, tlsConfig, options)
+	}
+	if options.ResponseReadTimeout > 0 && !configuration.NoTimeout {
+		transport.ResponseHeaderTimeout = options.ResponseReadTimeout
+	}
 
-	if types.ProxyURL != "" {
+	var ja3Spec *ja3.Spec
+	if configuration.JA3 != "" {
+		if ja3Spec, err = ja3.Parse(configuration.JA3); err != nil {
+			return nil, errors.Wrap(err, "could not parse ja3")
+		}
+		transport.DialTLSContext = ja3DialTLS(Dialer.Dial, ja3Spec, tlsConfig, options.GetTLSHandshakeTimeout())
+	}
+
+	if types.ProxyPool != nil {
+		types.ProxyPool.SetTransport(transport)
+	} else if types.ProxyURL != "" {
 		if proxyURL, err := url.Parse(types.ProxyURL); err == nil {
 			transport.Proxy = http.ProxyURL(proxyURL)
 		}
@@ -262,6 +318,32 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 				}
 				return tls.Client(conn, tlsConfig), nil
 			}
+			if protocolstate.HasSeparateTLSHandshake(options) {
+				transport.DialTLSContext = protocolstate.TLSDialer(dc.DialContext, tlsConfig, options)
+			}
+			if ja3Spec != nil {
+				transport.DialTLSContext = ja3DialTLS(dc.DialContext, ja3Spec, tlsConfig, options.GetTLSHandshakeTimeout())
+			}
+		}
+	}
+
+	var roundTripper http.RoundTripper = transport
+	switch configuration.HTTPVersion {
+	case "":
+	case HTTP1:
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	default:
+		if types.ProxyURL != "" || types.ProxyPool != nil || (configuration.HTTPVersion == HTTP3 && types.ProxySocksURL != "") {
+			return nil, errors.Errorf("%s requests are not supported through the configured proxy", configuration.HTTPVersion)
+		}
+		var fallback http.RoundTripper
+		if configuration.HTTPVersionFallback {
+			fallback = transport
+		}
+		roundTripper, err = newVersionTransport(configuration.HTTPVersion, transport.DialContext, tlsConfig, options.GetTLSHandshakeTimeout(), fallback)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -275,7 +357,7 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 	}
 
 	httpclient := &http.Client{
-		Transport:     transport,
+		Transport:     roundTripper,
 		CheckRedirect: makeCheckRedirectFunc(redirectFlow, maxRedirects),
 	}
 	if !configuration.NoTimeout {
@@ -296,6 +378,36 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 	return client, nil
 }
 
+// ja3DialTLS returns a tls dial function sending the client hello of a ja3 fingerprint.
+//
+// Only http/1.1 is advertised with alpn as the transport can't use http2 on custom tls connections.
+func ja3DialTLS(dial func(ctx context.Context, network, addr string) (net.Conn, error), spec *ja3.Spec, tlsConfig *tls.Config, handshakeTimeout time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		config := tlsConfig.Clone()
+		if config.ServerName == "" {
+			if host, _, splitErr := net.SplitHostPort(addr); splitErr == nil {
+				config.ServerName = host
+			}
+		}
+		tlsConn, err := ja3.Client(conn, spec, config, []string{"http/1.1"})
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		handshakeCtx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+		defer cancel()
+		if err := tlsConn.HandshakeContext(handshakeCtx); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "could not perform ja3 tls handshake")
+		}
+		return tlsConn, nil
+	}
+}
+
 type RedirectFlow uint8
 
 const (
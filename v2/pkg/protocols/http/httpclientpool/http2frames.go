@@ -0,0 +1,298 @@
+package httpclientpool
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// maxHTTP2Frames is the maximum number of frames recorded by a capture
+const maxHTTP2Frames = 1000
+
+// http2FrameHeaderLen is the length of the header of an http2 frame
+const http2FrameHeaderLen = 9
+
+// HTTP2Frame is an http2 frame sent or received on the connection of a request
+type HTTP2Frame struct {
+	// Direction is the direction of the frame (sent or received)
+	Direction string
+	// Type is the type of the frame (e.g. HEADERS, RST_STREAM)
+	Type string
+	// StreamID is the stream of the frame, 0 for connection frames
+	StreamID uint32
+	// Flags are the flags of the frame
+	Flags http2.Flags
+	// Length is the length of the payload of the frame
+	Length uint32
+	// ErrCode is the error code of the RST_STREAM and GOAWAY frames
+	ErrCode string
+	// DebugData is the debug data of the GOAWAY frames
+	DebugData string
+}
+
+// HTTP2Capture records the frames of the http2 connection of a request.
+//
+// Requests with a capture in their context are sent on a dedicated connection
+// so that all the frames of the connection belong to the request.
+type HTTP2Capture struct {
+	mutex    sync.Mutex
+	frames   []HTTP2Frame
+	sent     *http2FrameRecorder
+	received *http2FrameRecorder
+}
+
+type http2CaptureKey struct{}
+
+// WithHTTP2Capture returns a context recording the http2 frames of a request in capture
+func WithHTTP2Capture(ctx context.Context, capture *HTTP2Capture) context.Context {
+	return context.WithValue(ctx, http2CaptureKey{}, capture)
+}
+
+// http2CaptureFromContext returns the http2 capture of a request context, if any
+func http2CaptureFromContext(ctx context.Context) *HTTP2Capture {
+	capture, _ := ctx.Value(http2CaptureKey{}).(*HTTP2Capture)
+	return capture
+}
+
+// Frames returns the frames recorded by the capture
+func (c *HTTP2Capture) Frames() []HTTP2Frame {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]HTTP2Frame(nil), c.frames...)
+}
+
+// PseudoHeaders returns the pseudo-headers sent or received in name: value format
+func (c *HTTP2Capture) PseudoHeaders(direction string) []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	recorder := c.received
+	if direction == "sent" {
+		recorder = c.sent
+	}
+	if recorder == nil {
+		return nil
+	}
+	return append([]string(nil), recorder.pseudoHeaders...)
+}
+
+// reset clears the capture before the request is sent on a new connection,
+// returning a connection recording its frames.
+func (c *HTTP2Capture) reset(conn net.Conn) net.Conn {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.frames = nil
+	c.sent = newHTTP2FrameRecorder(c, "sent", len(http2.ClientPreface))
+	c.received = newHTTP2FrameRecorder(c, "received", 0)
+	return &http2CaptureConn{Conn: conn, sent: c.sent, received: c.received}
+}
+
+// hasReceived returns true if frames were received from the server
+func (c *HTTP2Capture) hasReceived() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, frame := range c.frames {
+		if frame.Direction == "received" {
+			return true
+		}
+	}
+	return false
+}
+
+// http2FrameRecorder parses the frames written or read on a connection
+type http2FrameRecorder struct {
+	capture   *HTTP2Capture
+	direction string
+	// preface is the number of bytes of the client preface left to skip
+	preface       int
+	pending       []byte
+	decoder       *hpack.Decoder
+	decoderFailed bool
+	pseudoHeaders []string
+}
+
+func newHTTP2FrameRecorder(capture *HTTP2Capture, direction string, preface int) *http2FrameRecorder {
+	recorder := &http2FrameRecorder{capture: capture, direction: direction, preface: preface}
+	recorder.decoder = hpack.NewDecoder(4096, func(field hpack.HeaderField) {
+		if strings.HasPrefix(field.Name, ":") {
+			recorder.pseudoHeaders = append(recorder.pseudoHeaders, field.Name+": "+field.Value)
+		}
+	})
+	return recorder
+}
+
+// write records the complete frames of the data written or read on the connection
+func (r *http2FrameRecorder) write(data []byte) {
+	r.capture.mutex.Lock()
+	defer r.capture.mutex.Unlock()
+
+	if r.preface > 0 {
+		skipped := r.preface
+		if skipped > len(data) {
+			skipped = len(data)
+		}
+		data = data[skipped:]
+		r.preface -= skipped
+	}
+	r.pending = append(r.pending, data...)
+	for len(r.pending) >= http2FrameHeaderLen {
+		length := int(r.pending[0])<<16 | int(r.pending[1])<<8 | int(r.pending[2])
+		if len(r.pending) < http2FrameHeaderLen+length {
+			break
+		}
+		r.record(r.pending[:http2FrameHeaderLen+length])
+		r.pending = r.pending[http2FrameHeaderLen+length:]
+	}
+}
+
+// record records a frame, decoding the pseudo-headers of the header blocks
+func (r *http2FrameRecorder) record(data []byte) {
+	header, err := http2.ReadFrameHeader(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	payload := data[http2FrameHeaderLen:]
+	frame := HTTP2Frame{Direction: r.direction, Type: header.Type.String(), StreamID: header.StreamID, Flags: header.Flags, Length: header.Length}
+
+	switch header.Type {
+	case http2.FrameHeaders:
+		if header.Flags.Has(http2.FlagHeadersPadded) && len(payload) > 0 {
+			padding := int(payload[0])
+			if padding >= len(payload) {
+				break
+			}
+			payload = payload[1 : len(payload)-padding]
+		}
+		if header.Flags.Has(http2.FlagHeadersPriority) {
+			if len(payload) < 5 {
+				break
+			}
+			payload = payload[5:]
+		}
+		r.decodeHeaders(payload, header.Flags.Has(http2.FlagHeadersEndHeaders))
+	case http2.FrameContinuation:
+		r.decodeHeaders(payload, header.Flags.Has(http2.FlagContinuationEndHeaders))
+	case http2.FrameRSTStream:
+		if len(payload) >= 4 {
+			frame.ErrCode = http2.ErrCode(binary.BigEndian.Uint32(payload)).String()
+		}
+	case http2.FrameGoAway:
+		if len(payload) >= 8 {
+			frame.ErrCode = http2.ErrCode(binary.BigEndian.Uint32(payload[4:])).String()
+			frame.DebugData = string(payload[8:])
+		}
+	}
+	if len(r.capture.frames) < maxHTTP2Frames {
+		r.capture.frames = append(r.capture.frames, frame)
+	}
+}
+
+// decodeHeaders decodes a header block fragment, a failure stopping the decoding
+// of the following blocks as the state of the hpack table is lost.
+func (r *http2FrameRecorder) decodeHeaders(fragment []byte, end bool) {
+	if r.decoderFailed {
+		return
+	}
+	if _, err := r.decoder.Write(fragment); err != nil {
+		r.decoderFailed = true
+		return
+	}
+	if end {
+		if err := r.decoder.Close(); err != nil {
+			r.decoderFailed = true
+		}
+	}
+}
+
+// http2CaptureConn is a connection recording the http2 frames written and read
+type http2CaptureConn struct {
+	net.Conn
+	sent     *http2FrameRecorder
+	received *http2FrameRecorder
+}
+
+func (c *http2CaptureConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.received.write(b[:n])
+	}
+	return n, err
+}
+
+func (c *http2CaptureConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.sent.write(b[:n])
+	}
+	return n, err
+}
+
+// http2CaptureBody closes the dedicated connection of a request with its response body
+type http2CaptureBody struct {
+	io.ReadCloser
+	conn *http2.ClientConn
+}
+
+func (b *http2CaptureBody) Close() error {
+	err := b.ReadCloser.Close()
+	_ = b.conn.Close()
+	return err
+}
+
+// roundTripWithCapture sends a request on a dedicated connection recording its frames
+func (t *http2Transport) roundTripWithCapture(req *http.Request, capture *HTTP2Capture) (*http.Response, error) {
+	addr := req.URL.Host
+	if req.URL.Port() == "" {
+		port := "443"
+		if req.URL.Scheme == "http" {
+			port = "80"
+		}
+		addr = net.JoinHostPort(req.URL.Hostname(), port)
+	}
+
+	transport := t.tls
+	var conn net.Conn
+	var err error
+	if req.URL.Scheme == "http" {
+		transport = t.cleartext
+		conn, err = t.dial(req.Context(), "tcp", addr)
+	} else {
+		tlsConfig := t.tls.TLSClientConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		conn, err = dialHTTP2TLS(req.Context(), t.dial, "tcp", addr, tlsConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	clientConn, err := transport.NewClientConn(capture.reset(conn))
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "could not create http2 connection")
+	}
+	resp, err := clientConn.RoundTrip(req)
+	if err != nil {
+		_ = clientConn.Close()
+		if req.URL.Scheme == "http" && !capture.hasReceived() && isHTTP2ProtocolError(err) {
+			return nil, errors.Wrapf(ErrHTTPVersionNotSupported, "server did not respond over h2c: %s", err)
+		}
+		return nil, err
+	}
+	resp.Body = &http2CaptureBody{ReadCloser: resp.Body, conn: clientConn}
+	return resp, nil
+}
@@ -0,0 +1,206 @@
+package httpclientpool
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"golang.org/x/net/http2"
+)
+
+// HTTP versions the requests of a template can be forced to use
+const (
+	// HTTP1 forces HTTP/1.1 requests, disabling http2 negotiation with alpn
+	HTTP1 = "http1"
+	// HTTP2 forces HTTP/2 requests. Requests to https urls negotiate h2 with alpn,
+	// requests to http urls use cleartext h2c with prior knowledge.
+	HTTP2 = "http2"
+	// HTTP3 forces HTTP/3 requests over QUIC
+	HTTP3 = "http3"
+)
+
+// HTTPVersions contains the http versions which can be forced for requests
+var HTTPVersions = []string{HTTP1, HTTP2, HTTP3}
+
+// ErrHTTPVersionNotSupported is returned when a server doesn't support the forced http version
+var ErrHTTPVersionNotSupported = errors.New("http version not supported by server")
+
+// dialContextFunc is a function dialing a network connection
+type dialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// newVersionTransport returns a transport forcing an http version for the requests.
+//
+// If fallback is not nil, requests failing because the server doesn't support
+// the http version are retried with the fallback transport.
+func newVersionTransport(version string, dial dialContextFunc, tlsConfig *tls.Config, timeout time.Duration, fallback http.RoundTripper) (http.RoundTripper, error) {
+	var transport http.RoundTripper
+	switch version {
+	case HTTP2:
+		transport = newHTTP2Transport(dial, tlsConfig)
+	case HTTP3:
+		transport = newHTTP3Transport(tlsConfig, timeout)
+	default:
+		return nil, errors.Errorf("invalid http version %q", version)
+	}
+	return &versionTransport{version: version, transport: transport, fallback: fallback}, nil
+}
+
+// versionTransport is a transport forcing an http version with an optional fallback
+type versionTransport struct {
+	version   string
+	transport http.RoundTripper
+	fallback  http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction
+func (t *versionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err == nil || t.fallback == nil || !errors.Is(err, ErrHTTPVersionNotSupported) {
+		return resp, err
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, err
+		}
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+	return t.fallback.RoundTrip(req)
+}
+
+// http2Transport sends requests over h2 for https urls and h2c for http urls
+type http2Transport struct {
+	dial      dialContextFunc
+	tls       *http2.Transport
+	cleartext *http2.Transport
+}
+
+func newHTTP2Transport(dial dialContextFunc, tlsConfig *tls.Config) *http2Transport {
+	return &http2Transport{
+		dial: dial,
+		tls: &http2.Transport{
+			TLSClientConfig: tlsConfig,
+			DialTLSContext: func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return dialHTTP2TLS(ctx, dial, network, addr, cfg)
+			},
+		},
+		cleartext: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dial(ctx, network, addr)
+			},
+		},
+	}
+}
+
+// RoundTrip executes a single HTTP transaction
+func (t *http2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// connection-specific headers are not allowed in http2, and closing the
+	// connection after a prior knowledge h2c request stalls the transport.
+	if req.Close || req.Header.Get("Connection") != "" {
+		req = req.Clone(req.Context())
+		req.Close = false
+		req.Header.Del("Connection")
+	}
+	if capture := http2CaptureFromContext(req.Context()); capture != nil {
+		return t.roundTripWithCapture(req, capture)
+	}
+	if req.URL.Scheme == "http" {
+		resp, err := t.cleartext.RoundTrip(req)
+		if err != nil && isHTTP2ProtocolError(err) {
+			return nil, errors.Wrapf(ErrHTTPVersionNotSupported, "server did not respond over h2c: %s", err)
+		}
+		return resp, err
+	}
+	return t.tls.RoundTrip(req)
+}
+
+// dialHTTP2TLS dials a tls connection negotiating h2 with alpn
+func dialHTTP2TLS(ctx context.Context, dial dialContextFunc, network, addr string, cfg *tls.Config) (net.Conn, error) {
+	conn, err := dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	config := cfg.Clone()
+	// http/1.1 is offered too so servers without h2 support complete the
+	// handshake instead of failing with a no application protocol alert.
+	config.NextProtos = []string{http2.NextProtoTLS, "http/1.1"}
+	if config.ServerName == "" {
+		if host, _, splitErr := net.SplitHostPort(addr); splitErr == nil {
+			config.ServerName = host
+		}
+	}
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if negotiated := tlsConn.ConnectionState().NegotiatedProtocol; negotiated != http2.NextProtoTLS {
+		tlsConn.Close()
+		if negotiated == "" {
+			negotiated = "http/1.1"
+		}
+		return nil, errors.Wrapf(ErrHTTPVersionNotSupported, "server negotiated %s instead of h2", negotiated)
+	}
+	return tlsConn, nil
+}
+
+// isHTTP2ProtocolError returns true if the error was caused by the server not speaking http2
+func isHTTP2ProtocolError(err error) bool {
+	var connErr http2.ConnectionError
+	var goAwayErr http2.GoAwayError
+	if errors.As(err, &connErr) || errors.As(err, &goAwayErr) {
+		return true
+	}
+	message := err.Error()
+	return strings.HasPrefix(message, "http2:") || strings.Contains(message, "unexpected EOF") || strings.Contains(message, "connection reset by peer")
+}
+
+// http3Transport sends requests over QUIC
+type http3Transport struct {
+	*http3.RoundTripper
+}
+
+func newHTTP3Transport(tlsConfig *tls.Config, timeout time.Duration) *http3Transport {
+	config := tlsConfig.Clone()
+	// http3 only supports tls 1.3
+	config.MinVersion = tls.VersionTLS13
+	config.Renegotiation = tls.RenegotiateNever
+
+	quicConfig := &quic.Config{}
+	if timeout > 0 {
+		quicConfig.HandshakeIdleTimeout = timeout
+	}
+	return &http3Transport{RoundTripper: &http3.RoundTripper{TLSClientConfig: config, QuicConfig: quicConfig}}
+}
+
+// RoundTrip executes a single HTTP transaction
+func (t *http3Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "https" {
+		return nil, errors.Errorf("http3 requires an https url, got %s", req.URL.Scheme)
+	}
+	resp, err := t.RoundTripper.RoundTrip(req)
+	if err != nil && isQUICHandshakeError(err) {
+		return nil, errors.Wrapf(ErrHTTPVersionNotSupported, "server did not respond over http3: %s", err)
+	}
+	return resp, err
+}
+
+// isQUICHandshakeError returns true if the error was caused by the server not speaking QUIC
+func isQUICHandshakeError(err error) bool {
+	var handshakeErr *quic.HandshakeTimeoutError
+	var idleErr *quic.IdleTimeoutError
+	var transportErr *quic.TransportError
+	var opErr *net.OpError
+	return errors.As(err, &handshakeErr) || errors.As(err, &idleErr) || errors.As(err, &transportErr) || errors.As(err, &opErr)
+}
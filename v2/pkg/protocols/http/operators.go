@@ -37,7 +37,23 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 	case matchers.RegexMatcher:
 		return matcher.ResultWithMatchedSnippet(matcher.MatchRegex(item))
 	case matchers.BinaryMatcher:
+		// binary matchers match the bytes of the body before it was transcoded
+		if matcher.Part == "" || matcher.Part == "body" {
+			if charsetBody, ok := data["charset_body"]; ok {
+				item = types.ToString(charsetBody)
+			}
+		}
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(item))
+	case matchers.JSONSchemaMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchJSONSchema(item))
+	case matchers.DifferentialMatcher:
+		baseline, ok := request.getMatchPart(matcher.Baseline, data)
+		if !ok {
+			return false, []string{}
+		}
+		return matcher.ResultWithMatchedSnippet(matcher.MatchDifferential(item, baseline))
+	case matchers.StructureMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchStructure(item))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
 	}
@@ -71,6 +87,8 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractXPath(item)
 	case extractors.JSONExtractor:
 		return extractor.ExtractJSON(item)
+	case extractors.StructureExtractor:
+		return extractor.ExtractStructure(item)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
 	}
@@ -124,6 +142,8 @@ func (request *Request) responseToDSLMap(resp *http.Response, host, matched, raw
 	data["body"] = body
 	data["all_headers"] = headers
 	data["header"] = headers
+	data["headers"] = utils.HeadersToMap(resp.Header)
+	data["http_version"] = resp.Proto
 	data["duration"] = duration.Seconds()
 	data["template-id"] = request.options.TemplateID
 	data["template-info"] = request.options.TemplateInfo
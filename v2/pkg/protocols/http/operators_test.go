@@ -1,11 +1,13 @@
 package http
 
 import (
+	"encoding/hex"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/japanese"
 
 	"github.com/projectdiscovery/nuclei/v2/pkg/model"
 	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
@@ -41,7 +43,7 @@ func TestResponseToDSLMap(t *testing.T) {
 	matched := "http://example.com/test/?test=1"
 
 	event := request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
-	require.Len(t, event, 15, "could not get correct number of items in dsl map")
+	require.Len(t, event, 17, "could not get correct number of items in dsl map")
 	require.Equal(t, exampleRawResponse, event["response"], "could not get correct resp")
 	require.Equal(t, "Test-Response", event["test"], "could not get correct resp for header")
 }
@@ -71,7 +73,7 @@ func TestHTTPOperatorMatch(t *testing.T) {
 	matched := "http://example.com/test/?test=1"
 
 	event := request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
-	require.Len(t, event, 15, "could not get correct number of items in dsl map")
+	require.Len(t, event, 17, "could not get correct number of items in dsl map")
 	require.Equal(t, exampleRawResponse, event["response"], "could not get correct resp")
 	require.Equal(t, "Test-Response", event["test"], "could not get correct resp for header")
 
@@ -132,6 +134,41 @@ func TestHTTPOperatorMatch(t *testing.T) {
 		require.True(t, isMatched, "could not match valid response")
 		require.Equal(t, []string{"example domain"}, matched)
 	})
+
+	t.Run("headers-map", func(t *testing.T) {
+		matcher := &matchers.Matcher{
+			Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+			DSL:  []string{`headers["test"] == "Test-Response" && headers["TEST"] == "Test-Response" && contains(headers['missing'], "x") == false`},
+		}
+		err = matcher.CompileMatchers()
+		require.Nil(t, err, "could not compile headers dsl matcher")
+
+		isMatched, _ := request.Match(event, matcher)
+		require.True(t, isMatched, "could not match headers map")
+	})
+
+	t.Run("transcoded", func(t *testing.T) {
+		body, err := japanese.ShiftJIS.NewEncoder().Bytes([]byte("<p>ログイン</p>"))
+		require.Nil(t, err, "could not encode body")
+		resp := &http.Response{Header: http.Header{"Content-Type": []string{"text/html; charset=Shift_JIS"}}}
+		response := &redirectedResponse{body: body, fullResponse: append([]byte("HTTP/1.1 200 OK\r\n\r\n"), body...)}
+		require.Nil(t, normalizeResponseBody(resp, response, true), "could not normalize response body")
+		require.Equal(t, "shift_jis", response.charset, "could not detect charset")
+		require.Equal(t, body, response.charsetBody, "could not keep untranscoded body")
+
+		event := request.responseToDSLMap(resp, host, matched, exampleRawRequest, string(response.fullResponse), string(response.body), exampleResponseHeader, 1*time.Second, map[string]interface{}{})
+		event["charset_body"] = string(response.charsetBody)
+
+		words := &matchers.Matcher{Type: matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher}, Words: []string{"ログイン"}}
+		require.Nil(t, words.CompileMatchers(), "could not compile words matcher")
+		isMatched, _ := request.Match(event, words)
+		require.True(t, isMatched, "could not match transcoded body")
+
+		binary := &matchers.Matcher{Type: matchers.MatcherTypeHolder{MatcherType: matchers.BinaryMatcher}, Binary: []string{hex.EncodeToString(body[3:9])}}
+		require.Nil(t, binary.CompileMatchers(), "could not compile binary matcher")
+		isMatched, _ = request.Match(event, binary)
+		require.True(t, isMatched, "could not match untranscoded body bytes")
+	})
 }
 
 func TestHTTPOperatorExtract(t *testing.T) {
@@ -159,7 +196,7 @@ func TestHTTPOperatorExtract(t *testing.T) {
 	matched := "http://example.com/test/?test=1"
 
 	event := request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
-	require.Len(t, event, 15, "could not get correct number of items in dsl map")
+	require.Len(t, event, 17, "could not get correct number of items in dsl map")
 	require.Equal(t, exampleRawResponse, event["response"], "could not get correct resp")
 	require.Equal(t, "Test-Response", event["test_header"], "could not get correct resp for header")
 
@@ -190,6 +227,30 @@ func TestHTTPOperatorExtract(t *testing.T) {
 		require.Equal(t, map[string]struct{}{"Test-Response": {}}, data, "could not extract correct kval data")
 	})
 
+	t.Run("kval-header-name", func(t *testing.T) {
+		extractor := &extractors.Extractor{
+			Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.KValExtractor},
+			KVal: []string{"Test-Header"},
+		}
+		err = extractor.CompileExtractors()
+		require.Nil(t, err, "could not compile kval extractor")
+
+		data := request.Extract(event, extractor)
+		require.Equal(t, map[string]struct{}{"Test-Response": {}}, data, "could not extract header by name")
+	})
+
+	t.Run("dsl-headers-map", func(t *testing.T) {
+		extractor := &extractors.Extractor{
+			Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.DSLExtractor},
+			DSL:  []string{`headers["test-header"]`},
+		}
+		err = extractor.CompileExtractors()
+		require.Nil(t, err, "could not compile dsl extractor")
+
+		data := request.Extract(event, extractor)
+		require.Equal(t, map[string]struct{}{"Test-Response": {}}, data, "could not extract header from headers map")
+	})
+
 	t.Run("json", func(t *testing.T) {
 		event["body"] = exampleJSONResponseBody
 
@@ -286,7 +347,7 @@ func TestHTTPMakeResult(t *testing.T) {
 	matched := "http://example.com/test/?test=1"
 
 	event := request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
-	require.Len(t, event, 15, "could not get correct number of items in dsl map")
+	require.Len(t, event, 17, "could not get correct number of items in dsl map")
 	require.Equal(t, exampleRawResponse, event["response"], "could not get correct resp")
 	require.Equal(t, "Test-Response", event["test"], "could not get correct resp for header")
 
@@ -127,15 +127,34 @@ func ParseRawRequest(request string, unsafe bool) (*Request, error) {
 	return req, nil
 }
 
+// ParseRawBytes parses a raw request to be sent byte for byte, without merging
+// the path of the input url or normalizing it in any way. Only the annotation
+// lines, which are not part of the request, are removed from the bytes.
+func ParseRawBytes(request string) (*Request, error) {
+	return readRawRequest(request, true)
+}
+
+// trimAnnotations removes the leading annotation lines of a raw request
+func trimAnnotations(request string) string {
+	for strings.HasPrefix(request, "@") {
+		index := strings.Index(request, "\n")
+		if index < 0 {
+			return ""
+		}
+		request = request[index+1:]
+	}
+	return request
+}
+
 // reads raw request line by line following convention
 func readRawRequest(request string, unsafe bool) (*Request, error) {
 	rawRequest := &Request{
 		Headers: make(map[string]string),
 	}
 
-	// store body if it is unsafe request
+	// store body if it is unsafe request, annotations not being sent
 	if unsafe {
-		rawRequest.UnsafeRawBytes = []byte(request)
+		rawRequest.UnsafeRawBytes = []byte(trimAnnotations(request))
 	}
 
 	// parse raw request
@@ -247,3 +266,33 @@ func (r *Request) TryFillCustomHeaders(headers []string) error {
 
 	return errors.New("no host header found")
 }
+
+// SetUnsafeHeader sets a header in the unsafe raw bytes of the request,
+// replacing the value of the first header with the same name if any or
+// inserting it after the host header otherwise.
+func (r *Request) SetUnsafeHeader(name, value string) error {
+	headersEnd := bytes.Index(r.UnsafeRawBytes, []byte("\r\n\r\n"))
+	if headersEnd < 0 {
+		headersEnd = len(r.UnsafeRawBytes)
+	}
+	// the first line is the request line
+	lineEnd := bytes.Index(r.UnsafeRawBytes, []byte("\r\n"))
+	for lineEnd >= 0 && lineEnd < headersEnd {
+		lineStart := lineEnd + 2
+		next := bytes.Index(r.UnsafeRawBytes[lineStart:], []byte("\r\n"))
+		if next < 0 {
+			break
+		}
+		lineEnd = lineStart + next
+		line := r.UnsafeRawBytes[lineStart:lineEnd]
+		if colon := bytes.IndexByte(line, ':'); colon > 0 && strings.EqualFold(strings.TrimSpace(string(line[:colon])), name) {
+			var buf bytes.Buffer
+			buf.Write(r.UnsafeRawBytes[:lineStart])
+			buf.WriteString(fmt.Sprintf("%s: %s", name, value))
+			buf.Write(r.UnsafeRawBytes[lineEnd:])
+			r.UnsafeRawBytes = buf.Bytes()
+			return nil
+		}
+	}
+	return r.TryFillCustomHeaders([]string{fmt.Sprintf("%s: %s", name, value)})
+}
@@ -94,6 +94,30 @@ func TestTryFillCustomHeaders(t *testing.T) {
 	require.Equal(t, expected, string(request.UnsafeRawBytes), "actual value and expected value are different")
 }
 
+func TestSetUnsafeHeader(t *testing.T) {
+	testValue := "POST /api HTTP/1.1\r\nHost: Test\r\nX-Signature: old\r\n\r\nX-Signature: body"
+	request, err := Parse(testValue, parseURL(t, "https://test.com"), true)
+	require.Nil(t, err, "could not parse unsafe request")
+
+	require.Nil(t, request.SetUnsafeHeader("x-signature", "new"), "could not replace header")
+	require.Nil(t, request.SetUnsafeHeader("X-Timestamp", "1"), "could not insert header")
+	expected := "POST /api HTTP/1.1\r\nHost: Test\r\nX-Timestamp: 1\r\nx-signature: new\r\n\r\nX-Signature: body"
+	require.Equal(t, expected, string(request.UnsafeRawBytes), "wrong unsafe request")
+}
+
+func TestParseRawBytes(t *testing.T) {
+	data := "@timeout: 10s\nPOST /a%2f..;/b HTTP/1.1\r\nHost: {{Hostname}}\r\nTransfer-Encoding:\tchunked\r\nContent-Length: 4\r\nX-Bare: a\rb\r\n\r\n0\r\n\r\nG"
+	request, err := ParseRawBytes(data)
+	require.Nil(t, err, "could not parse raw bytes request")
+	require.Equal(t, data[len("@timeout: 10s\n"):], string(request.UnsafeRawBytes), "raw bytes were modified")
+	require.Equal(t, "POST", request.Method, "could not parse method")
+	require.Equal(t, "/a%2f..;/b", request.Path, "could not parse path")
+
+	request, err = Parse("@Host: https://example.com\nGET /a HTTP/1.1\r\nHost: {{Hostname}}\r\n\r\n", parseURL(t, "https://test.com/"), true)
+	require.Nil(t, err, "could not parse unsafe request")
+	require.Equal(t, "GET /a HTTP/1.1\r\nHost: {{Hostname}}\r\n\r\n", string(request.UnsafeRawBytes), "annotations were not removed from unsafe request")
+}
+
 func parseURL(t *testing.T, inputurl string) *urlutil.URL {
 	urlx, err := urlutil.Parse(inputurl)
 	if err != nil {
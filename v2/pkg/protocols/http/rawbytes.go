@@ -0,0 +1,139 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/rawhttp/proxy"
+	iputil "github.com/projectdiscovery/utils/ip"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/http/httpclientpool"
+)
+
+// rawBytesConn is a connection sending requests byte for byte and
+// recording the exact bytes received for them
+type rawBytesConn struct {
+	net.Conn
+	received bytes.Buffer
+}
+
+func (c *rawBytesConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.received.Write(b[:n])
+	return n, err
+}
+
+// rawBytesBody is the body of a raw bytes response closing its connection
+type rawBytesBody struct {
+	io.Reader
+	conn net.Conn
+}
+
+func (b *rawBytesBody) Close() error {
+	return b.conn.Close()
+}
+
+// sendRawBytes sends the unsafe raw bytes of a request to the address of
+// target, with tls for https targets, through proxyURL if not empty, and
+// returns the parsed response along with the exact bytes received, which
+// grow as its body is read.
+//
+// The bytes are written as is, the connection being dialed directly instead
+// of through an http client so that nothing normalizes the request. With
+// forceReadAll the body is read until the connection is closed or times out,
+// regardless of the content length, capturing any data following the response.
+func sendRawBytes(target, proxyURL, method string, data []byte, sni string, timeout time.Duration, forceReadAll bool) (*http.Response, *bytes.Buffer, error) {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not parse target")
+	}
+	useTLS := strings.EqualFold(parsed.Scheme, "https")
+	address := parsed.Host
+	if parsed.Port() == "" {
+		port := "80"
+		if useTLS {
+			port = "443"
+		}
+		address = net.JoinHostPort(parsed.Hostname(), port)
+	}
+
+	conn, err := dialRawBytes(address, proxyURL, timeout)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not dial target")
+	}
+	if useTLS {
+		if sni == "" && !iputil.IsIP(parsed.Hostname()) {
+			sni = parsed.Hostname()
+		}
+		tlsConn := tls.Client(conn, &tls.Config{
+			InsecureSkipVerify: true,
+			ServerName:         sni,
+			MinVersion:         tls.VersionTLS10,
+			NextProtos:         []string{"http/1.1"},
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := tlsConn.HandshakeContext(ctx)
+		cancel()
+		if err != nil {
+			conn.Close()
+			return nil, nil, errors.Wrap(err, "could not perform tls handshake")
+		}
+		conn = tlsConn
+	}
+
+	recorder := &rawBytesConn{Conn: conn}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(data); err != nil {
+		conn.Close()
+		return nil, nil, errors.Wrap(err, "could not write request")
+	}
+	reader := bufio.NewReader(recorder)
+	resp, err := http.ReadResponse(reader, &http.Request{Method: method})
+	if err != nil {
+		conn.Close()
+		return nil, &recorder.received, errors.Wrap(err, "could not read response")
+	}
+	// the request only tells whether the response has a body
+	resp.Request = nil
+	if forceReadAll {
+		resp.Body = &rawBytesBody{Reader: reader, conn: conn}
+		resp.ContentLength = -1
+	} else {
+		resp.Body = &rawBytesBody{Reader: resp.Body, conn: conn}
+	}
+	return resp, &recorder.received, nil
+}
+
+// dialRawBytes dials address directly or through an http or socks5 proxy
+func dialRawBytes(address, proxyURL string, timeout time.Duration) (net.Conn, error) {
+	if proxyURL != "" {
+		parsed, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse proxy url")
+		}
+		switch parsed.Scheme {
+		case "http":
+			return proxy.HTTPDialer(proxyURL, timeout)(address)
+		case "socks5", "socks5h":
+			return proxy.Socks5Dialer(proxyURL, timeout)(address)
+		default:
+			return nil, errors.Errorf("unsupported proxy protocol: %s", parsed.Scheme)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if httpclientpool.Dialer != nil {
+		return httpclientpool.Dialer.Dial(ctx, "tcp", address)
+	}
+	dialer := &net.Dialer{}
+	return dialer.DialContext(ctx, "tcp", address)
+}
@@ -28,6 +28,9 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/responsehighlighter"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/ja3"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/proxypool"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/tostring"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/http/fuzz"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/http/httpclientpool"
@@ -473,12 +476,16 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			generatedRequest.meta[payloadName] = data
 		}
 	}
+	if err := request.applyComputedHeaders(generatedRequest, input.MetaInput.Input, generators.MergeMaps(finalMap, generatedRequest.dynamicValues, generatedRequest.meta)); err != nil {
+		return err
+	}
 
 	var (
 		resp          *http.Response
 		fromCache     bool
 		dumpedRequest []byte
 		err           error
+		http2Capture  *httpclientpool.HTTP2Capture
 	)
 
 	// Dump request for variables checks
@@ -504,8 +511,22 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			}
 		}
 	}
+	// Check if the request budget of the template for the host is exhausted
+	if !request.options.RequestBudget.Take(input.MetaInput.Input) {
+		request.options.Progress.IncrementSkippedRequestsBy(1)
+		return errStopExecution
+	}
+	// Check if the host is skipped by the breaker after repeated failures
+	if !request.options.HostBreaker.Allow(input.MetaInput.Input) {
+		request.options.Progress.IncrementSkippedRequestsBy(1)
+		return errStopExecution
+	}
+	// Wait for the delay advertised by the rate limit headers if respected by the template
+	request.rateBreaker.Allow(input.MetaInput.Input)
+
 	var formedURL string
-	var hostname string
+	var hostname, usedProxy string
+	var rawResponse *bytes.Buffer
 	timeStart := time.Now()
 	if generatedRequest.original.Pipeline {
 		if generatedRequest.rawRequest != nil {
@@ -521,23 +542,30 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		formedURL = generatedRequest.rawRequest.FullURL
 		// use request url as matched url if empty
 		if formedURL == "" {
-			urlx, err := urlutil.Parse(input.MetaInput.Input)
-			if err != nil {
-				formedURL = fmt.Sprintf("%s%s", formedURL, generatedRequest.rawRequest.Path)
-			} else {
-				urlx.Path = generatedRequest.rawRequest.Path
-				formedURL = fmt.Sprintf("%v://%v", urlx.Scheme, path.Join(urlx.Host, generatedRequest.rawRequest.Path))
-			}
+			formedURL = unsafeRequestURL(input.MetaInput.Input, generatedRequest.rawRequest.Path)
 		}
 		if parsed, parseErr := urlutil.ParseURL(formedURL, true); parseErr == nil {
 			hostname = parsed.Host
 		}
-		options := *generatedRequest.original.rawhttpClient.Options
-		options.FollowRedirects = request.Redirects
-		options.CustomRawBytes = generatedRequest.rawRequest.UnsafeRawBytes
-		options.ForceReadAllBody = request.ForceReadAllBody
-		options.SNI = request.options.Options.SNI
-		resp, err = generatedRequest.original.rawhttpClient.DoRawWithOptions(generatedRequest.rawRequest.Method, input.MetaInput.Input, generatedRequest.rawRequest.Path, generators.ExpandMapValues(generatedRequest.rawRequest.Headers), io.NopCloser(strings.NewReader(generatedRequest.rawRequest.Data)), &options)
+		rawhttpClient := generatedRequest.original.rawhttpClient
+		if types.ProxyPool != nil {
+			selected, proxyErr := types.ProxyPool.Next()
+			if proxyErr != nil {
+				return proxyErr
+			}
+			usedProxy = selected.String()
+			rawhttpClient = httpclientpool.GetRawHTTPWithProxy(request.options.Options, selected.URL.String())
+		}
+		if request.RawBytes {
+			resp, rawResponse, err = sendRawBytes(input.MetaInput.Input, rawhttpClient.Options.Proxy, generatedRequest.rawRequest.Method, generatedRequest.rawRequest.UnsafeRawBytes, request.options.Options.SNI, rawhttpClient.Options.Timeout, request.ForceReadAllBody)
+		} else {
+			options := *rawhttpClient.Options
+			options.FollowRedirects = request.Redirects
+			options.CustomRawBytes = generatedRequest.rawRequest.UnsafeRawBytes
+			options.ForceReadAllBody = request.ForceReadAllBody
+			options.SNI = request.options.Options.SNI
+			resp, err = rawhttpClient.DoRawWithOptions(generatedRequest.rawRequest.Method, input.MetaInput.Input, generatedRequest.rawRequest.Path, generators.ExpandMapValues(generatedRequest.rawRequest.Headers), io.NopCloser(strings.NewReader(generatedRequest.rawRequest.Data)), &options)
+		}
 	} else {
 		hostname = generatedRequest.request.URL.Host
 		formedURL = generatedRequest.request.URL.String()
@@ -565,7 +593,37 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 				}
 				httpclient = client
 			}
+			var selection *proxypool.Selection
+			if types.ProxyPool != nil {
+				var ctx context.Context
+				ctx, selection = proxypool.Track(generatedRequest.request.Context())
+				generatedRequest.request = generatedRequest.request.WithContext(ctx)
+			}
+			if request.HTTP2Frames {
+				http2Capture = &httpclientpool.HTTP2Capture{}
+				generatedRequest.request = generatedRequest.request.WithContext(httpclientpool.WithHTTP2Capture(generatedRequest.request.Context(), http2Capture))
+			}
 			resp, err = httpclient.Do(generatedRequest.request)
+			// retry throttled requests with backoff if enabled
+			for attempt := 0; err == nil; attempt++ {
+				delay, retry := request.options.HostBreaker.Retry(input.MetaInput.Input, attempt, resp)
+				if !retry {
+					break
+				}
+				_, _ = io.CopyN(io.Discard, resp.Body, drainReqSize)
+				resp.Body.Close()
+				gologger.Verbose().Msgf("[%s] Retrying throttled request to %s in %s (status %d)", request.options.TemplateID, formedURL, delay, resp.StatusCode)
+				time.Sleep(delay)
+				resp, err = httpclient.Do(generatedRequest.request)
+			}
+			usedProxy = selection.String()
+		}
+	}
+	if !fromCache {
+		request.options.HostBreaker.Record(input.MetaInput.Input, resp, err)
+		if err == nil {
+			request.options.HostBreaker.Throttle(input.MetaInput.Input, resp)
+			request.rateBreaker.Throttle(input.MetaInput.Input, resp)
 		}
 	}
 	// use request url as matched url if empty
@@ -605,18 +663,29 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		request.options.Output.Request(request.options.TemplatePath, formedURL, request.Type().String(), err)
 		request.options.Progress.IncrementErrorsBy(1)
 
+		// If the request failed with an http2 stream error, still match the
+		// captured frames so that servers resetting streams can be detected.
+		if http2Capture != nil && isHTTP2StreamError(http2Capture, err) {
+			outputEvent := request.responseToDSLMap(&http.Response{}, input.MetaInput.Input, formedURL, tostring.UnsafeToString(dumpedRequest), "", "", "", 0, generatedRequest.meta)
+			for k, v := range http2FramesToDSLMap(http2Capture, err) {
+				outputEvent[k] = v
+			}
+			event := eventcreator.CreateEventWithAdditionalOptions(request, generators.MergeMaps(generatedRequest.dynamicValues, outputEvent), request.options.Options.Debug || request.options.Options.DebugResponse, func(internalWrappedEvent *output.InternalWrappedEvent) {
+				internalWrappedEvent.OperatorsResult.PayloadValues = generatedRequest.meta
+			})
+			callback(event)
+			return err
+		}
+
 		// If we have interactsh markers and request times out, still send
 		// a callback event so in case we receive an interaction, correlation is possible.
 		if hasInteractMatchers {
 			outputEvent := request.responseToDSLMap(&http.Response{}, input.MetaInput.Input, formedURL, tostring.UnsafeToString(dumpedRequest), "", "", "", 0, generatedRequest.meta)
-			if i := strings.LastIndex(hostname, ":"); i != -1 {
-				hostname = hostname[:i]
-			}
 
 			if input.MetaInput.CustomIP != "" {
 				outputEvent["ip"] = input.MetaInput.CustomIP
 			} else {
-				outputEvent["ip"] = httpclientpool.Dialer.GetDialedIP(hostname)
+				outputEvent["ip"] = protocolstate.GetDialedIP(httpclientpool.Dialer, hostname)
 			}
 
 			event := &output.InternalWrappedEvent{InternalEvent: outputEvent}
@@ -644,7 +713,11 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		}
 	}
 
-	gologger.Verbose().Msgf("[%s] Sent HTTP request to %s", request.options.TemplateID, formedURL)
+	if usedProxy != "" {
+		gologger.Verbose().Msgf("[%s] Sent HTTP request to %s through proxy %s", request.options.TemplateID, formedURL, usedProxy)
+	} else {
+		gologger.Verbose().Msgf("[%s] Sent HTTP request to %s", request.options.TemplateID, formedURL)
+	}
 	request.options.Output.Request(request.options.TemplatePath, formedURL, request.Type().String(), err)
 
 	duration := time.Since(timeStart)
@@ -656,6 +729,7 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 
 	var dumpedResponse []redirectedResponse
 	var gotData []byte
+	var streamErr error
 	// If the status code is HTTP 101, we should not proceed with reading body.
 	if resp.StatusCode != http.StatusSwitchingProtocols {
 		var bodyReader io.Reader
@@ -669,7 +743,9 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		data, err := io.ReadAll(bodyReader)
 		if err != nil {
 			// Ignore body read due to server misconfiguration errors
-			if stringsutil.ContainsAny(err.Error(), "gzip: invalid header") {
+			if http2Capture != nil && isHTTP2StreamError(http2Capture, err) {
+				streamErr = err
+			} else if stringsutil.ContainsAny(err.Error(), "gzip: invalid header") {
 				gologger.Warning().Msgf("[%s] Server sent an invalid gzip header and it was not possible to read the uncompressed body for %s: %s", request.options.TemplateID, formedURL, err.Error())
 			} else if !stringsutil.ContainsAny(err.Error(), "unexpected EOF", "user canceled") { // ignore EOF and random error
 				return errors.Wrap(err, "could not read http body")
@@ -678,7 +754,7 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		gotData = data
 		resp.Body.Close()
 
-		dumpedResponse, err = dumpResponseWithRedirectChain(resp, data)
+		dumpedResponse, err = dumpResponseWithRedirectChain(resp, data, request.options.Options.TranscodeResponse)
 		if err != nil {
 			return errors.Wrap(err, "could not read http response with redirect chain")
 		}
@@ -693,10 +769,23 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		}
 	}
 
+	// bodies read up to the read limit may have been cut by it
+	readLimit := request.MaxSize
+	if readLimit == 0 {
+		readLimit = request.options.Options.ResponseReadSize
+	}
+	readTruncated := readLimit > 0 && len(gotData) >= readLimit
+
 	for _, response := range dumpedResponse {
 		if response.resp == nil {
 			continue // Skip nil responses
 		}
+		body, truncated := request.options.TruncateBody(response.body)
+		if truncated {
+			response.body = body
+			response.fullResponse = bytes.Join([][]byte{response.headers, body}, []byte{})
+		}
+		truncated = truncated || readTruncated
 		matchedURL := input.MetaInput.Input
 		if generatedRequest.rawRequest != nil {
 			if generatedRequest.rawRequest.FullURL != "" {
@@ -717,14 +806,41 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		finalEvent := make(output.InternalEvent)
 
 		outputEvent := request.responseToDSLMap(response.resp, input.MetaInput.Input, matchedURL, tostring.UnsafeToString(dumpedRequest), tostring.UnsafeToString(response.fullResponse), tostring.UnsafeToString(response.body), tostring.UnsafeToString(response.headers), duration, generatedRequest.meta)
-		if i := strings.LastIndex(hostname, ":"); i != -1 {
-			hostname = hostname[:i]
+		// keep the undecoded body around for binary matchers if it was decompressed
+		if response.rawBody != nil && !bytes.Equal(response.rawBody, response.body) {
+			outputEvent["raw_body"] = tostring.UnsafeToString(response.rawBody)
+		}
+		if response.charset != "" {
+			outputEvent["charset"] = response.charset
+		}
+		if response.charsetBody != nil {
+			outputEvent["charset_body"] = tostring.UnsafeToString(response.charsetBody)
+		}
+		if rawResponse != nil {
+			outputEvent["raw_response"] = rawResponse.String()
 		}
 		outputEvent["curl-command"] = curlCommand
+		outputEvent["truncated"] = truncated
+		outputEvent["rate_limit_delay"] = rateLimitDelay(response.resp)
+		if http2Capture != nil {
+			for k, v := range http2FramesToDSLMap(http2Capture, streamErr) {
+				outputEvent[k] = v
+			}
+		}
+		if usedProxy != "" {
+			outputEvent["proxy"] = usedProxy
+		}
+		if userAgent := generatedRequest.userAgent(); userAgent != "" {
+			outputEvent["user_agent"] = userAgent
+		}
+		if request.ja3 != "" {
+			outputEvent["ja3"] = request.ja3
+			outputEvent["ja3_hash"] = ja3.Hash(request.ja3)
+		}
 		if input.MetaInput.CustomIP != "" {
 			outputEvent["ip"] = input.MetaInput.CustomIP
 		} else {
-			outputEvent["ip"] = httpclientpool.Dialer.GetDialedIP(hostname)
+			outputEvent["ip"] = protocolstate.GetDialedIP(httpclientpool.Dialer, hostname)
 		}
 		if request.options.Interactsh != nil {
 			request.options.Interactsh.MakePlaceholders(generatedRequest.interactshURLs, outputEvent)
@@ -755,8 +871,7 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		}
 
 		responseContentType := resp.Header.Get("Content-Type")
-		isResponseTruncated := request.MaxSize > 0 && len(gotData) >= request.MaxSize
-		dumpResponse(event, request, response.fullResponse, formedURL, responseContentType, isResponseTruncated, input.MetaInput.Input)
+		dumpResponse(event, request, response.fullResponse, formedURL, responseContentType, truncated, input.MetaInput.Input)
 
 		callback(event)
 
@@ -872,3 +987,12 @@ func (request *Request) newContext(input *contextargs.Context) context.Context {
 	}
 	return context.Background()
 }
+
+// unsafeRequestURL returns the url of an unsafe request sent to the input with the path
+func unsafeRequestURL(input, rawPath string) string {
+	urlx, err := urlutil.Parse(input)
+	if err != nil {
+		return rawPath
+	}
+	return fmt.Sprintf("%v://%v", urlx.Scheme, path.Join(urlx.Host, rawPath))
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/utils"
 	"github.com/projectdiscovery/retryablehttp-go"
 	iputil "github.com/projectdiscovery/utils/ip"
 	stringsutil "github.com/projectdiscovery/utils/strings"
@@ -73,12 +74,7 @@ func (r *Request) parseAnnotations(rawRequest string, request *retryablehttp.Req
 		if isHostPort(value) {
 			request.URL.Host = value
 		} else {
-			hostPort := value
-			port := request.URL.Port()
-			if port != "" {
-				hostPort = net.JoinHostPort(hostPort, port)
-			}
-			request.URL.Host = hostPort
+			request.URL.Host = utils.JoinHostPort(value, request.URL.Port())
 		}
 		modified = true
 	}
@@ -46,3 +46,24 @@ func TestRequestParseAnnotationsTimeout(t *testing.T) {
 		require.False(t, deadlined, "could not get set request deadline")
 	})
 }
+
+func TestRequestParseAnnotationsHostIPv6(t *testing.T) {
+	request := &Request{connConfiguration: &httpclientpool.Configuration{}}
+
+	for annotation, expected := range map[string]string{
+		"[::1]:8443":        "[::1]:8443",
+		"[::1]":             "[::1]:8080",
+		"::1":               "[::1]:8080",
+		"https://[::1]":     "[::1]:8080",
+		"[fe80::1%25eth0]":  "[fe80::1%eth0]:8080",
+		"example.com":       "example.com:8080",
+		"example.com:10443": "example.com:10443",
+	} {
+		httpReq, err := retryablehttp.NewRequest(http.MethodGet, "http://example.org:8080/path", nil)
+		require.Nil(t, err, "could not create http request")
+
+		overrides, modified := request.parseAnnotations("@Host: "+annotation+"\nGET / HTTP/1.1\nHost: {{Hostname}}", httpReq)
+		require.True(t, modified, "could not get correct modified value")
+		require.Equal(t, expected, overrides.request.URL.Host, "could not override host with %s", annotation)
+	}
+}
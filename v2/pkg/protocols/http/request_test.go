@@ -1,12 +1,29 @@
 package http
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/projectdiscovery/retryablehttp-go"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"golang.org/x/net/http2/hpack"
 
 	"github.com/projectdiscovery/nuclei/v2/pkg/model"
 	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
@@ -15,6 +32,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v2/pkg/output"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/hostbreaker"
 	"github.com/projectdiscovery/nuclei/v2/pkg/testutils"
 )
 
@@ -94,3 +112,618 @@ Disallow: /c`))
 	require.NotNil(t, finalEvent, "could not get event output from request")
 	require.Equal(t, 3, matchCount, "could not get correct match count")
 }
+
+func TestHTTPRequestJA3(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http-ja3"
+	request := &Request{
+		ID:     templateID,
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Path:   []string{"{{BaseURL}}"},
+		JA3:    "771,49195-49199-52393-52392-49196-49200-49161-49171-49162-49172-156-157-47-53,0-23-65281-10-11-35-16-5-13,29-23-24,0",
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Part:  "body",
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"spoofed"},
+			}},
+		},
+	}
+	var cipherSuites []uint16
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("spoofed"))
+	}))
+	ts.TLS = &tls.Config{GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		cipherSuites = hello.CipherSuites
+		return nil, nil
+	}}
+	ts.StartTLS()
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var finalEvent *output.InternalWrappedEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.True(t, finalEvent.OperatorsResult.Matched, "could not match response")
+	require.Equal(t, request.JA3, finalEvent.InternalEvent["ja3"], "could not get spoofed ja3")
+	require.Equal(t, []uint16{49195, 49199, 52393, 52392, 49196, 49200, 49161, 49171, 49162, 49172, 156, 157, 47, 53}, cipherSuites, "could not spoof client hello ciphers")
+}
+
+func TestHTTPRequestHTTPVersion(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, r.Proto)
+	})
+
+	execute := func(t *testing.T, input, version string, fallback bool) (*output.InternalWrappedEvent, error) {
+		templateID := "testing-http-version"
+		request := &Request{
+			ID:                  templateID,
+			Method:              HTTPMethodTypeHolder{MethodType: HTTPGet},
+			Path:                []string{"{{BaseURL}}"},
+			HTTPVersion:         version,
+			HTTPVersionFallback: fallback,
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile http request")
+
+		var finalEvent *output.InternalWrappedEvent
+		err = request.ExecuteWithResults(contextargs.NewWithInput(input), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			finalEvent = event
+		})
+		return finalEvent, err
+	}
+
+	h2Server := httptest.NewUnstartedServer(handler)
+	h2Server.EnableHTTP2 = true
+	h2Server.StartTLS()
+	defer h2Server.Close()
+
+	h1Server := httptest.NewTLSServer(handler)
+	defer h1Server.Close()
+
+	h2cServer := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer h2cServer.Close()
+
+	t.Run("http2", func(t *testing.T) {
+		event, err := execute(t, h2Server.URL, "http2", false)
+		require.Nil(t, err, "could not execute http2 request")
+		require.Equal(t, "HTTP/2.0", event.InternalEvent["body"], "could not send http2 request")
+		require.Equal(t, "HTTP/2.0", event.InternalEvent["http_version"], "could not get negotiated http version")
+	})
+	t.Run("h2c", func(t *testing.T) {
+		event, err := execute(t, h2cServer.URL, "http2", false)
+		require.Nil(t, err, "could not execute h2c request")
+		require.Equal(t, "HTTP/2.0", event.InternalEvent["body"], "could not send h2c request")
+	})
+	t.Run("http1", func(t *testing.T) {
+		event, err := execute(t, h2Server.URL, "http1", false)
+		require.Nil(t, err, "could not execute http1 request")
+		require.Equal(t, "HTTP/1.1", event.InternalEvent["http_version"], "could not force http1 request")
+	})
+	t.Run("not-supported", func(t *testing.T) {
+		_, err := execute(t, h1Server.URL, "http2", false)
+		require.NotNil(t, err, "could send http2 request to http1 server")
+		require.Contains(t, err.Error(), "instead of h2", "could not get http version error")
+	})
+	t.Run("fallback", func(t *testing.T) {
+		event, err := execute(t, h1Server.URL, "http2", true)
+		require.Nil(t, err, "could not execute http2 request with fallback")
+		require.Equal(t, "HTTP/1.1", event.InternalEvent["http_version"], "could not fallback to http1")
+	})
+}
+
+func TestHTTPRequestHTTP2Frames(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+
+	// mockServer serves h2c with prior knowledge, answering the requests with respond
+	mockServer := func(t *testing.T, respond func(framer *http2.Framer, streamID uint32)) string {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.Nil(t, err, "could not listen")
+		t.Cleanup(func() { listener.Close() })
+
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					defer conn.Close()
+					if _, err := io.ReadFull(conn, make([]byte, len(http2.ClientPreface))); err != nil {
+						return
+					}
+					framer := http2.NewFramer(conn, conn)
+					_ = framer.WriteSettings()
+					for {
+						frame, err := framer.ReadFrame()
+						if err != nil {
+							return
+						}
+						switch frame := frame.(type) {
+						case *http2.SettingsFrame:
+							if !frame.IsAck() {
+								_ = framer.WriteSettingsAck()
+							}
+						case *http2.HeadersFrame:
+							respond(framer, frame.StreamID)
+						}
+					}
+				}()
+			}
+		}()
+		return "http://" + listener.Addr().String()
+	}
+	writeHeaders := func(framer *http2.Framer, streamID uint32, endStream bool) {
+		var block bytes.Buffer
+		encoder := hpack.NewEncoder(&block)
+		_ = encoder.WriteField(hpack.HeaderField{Name: ":status", Value: "200"})
+		_ = encoder.WriteField(hpack.HeaderField{Name: "content-type", Value: "text/plain"})
+		_ = framer.WriteHeaders(http2.HeadersFrameParam{StreamID: streamID, BlockFragment: block.Bytes(), EndHeaders: true, EndStream: endStream})
+	}
+
+	execute := func(t *testing.T, input string) (*output.InternalWrappedEvent, error) {
+		templateID := "testing-http2-frames"
+		request := &Request{
+			ID:          templateID,
+			Method:      HTTPMethodTypeHolder{MethodType: HTTPGet},
+			Path:        []string{"{{BaseURL}}/"},
+			HTTPVersion: "http2",
+			HTTP2Frames: true,
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+					DSL:  []string{`contains(h2_rst_stream, "ENHANCE_YOUR_CALM") || contains(h2_goaway, "ENHANCE_YOUR_CALM")`},
+				}},
+			},
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile http request")
+
+		var finalEvent *output.InternalWrappedEvent
+		err = request.ExecuteWithResults(contextargs.NewWithInput(input), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			finalEvent = event
+		})
+		return finalEvent, err
+	}
+
+	t.Run("pseudo-headers", func(t *testing.T) {
+		input := mockServer(t, func(framer *http2.Framer, streamID uint32) {
+			writeHeaders(framer, streamID, false)
+			_ = framer.WriteData(streamID, true, []byte("ok"))
+		})
+		event, err := execute(t, input)
+		require.Nil(t, err, "could not execute http2 request")
+		require.Equal(t, "ok", event.InternalEvent["body"], "could not get response body")
+		require.Equal(t, ":status: 200", event.InternalEvent["h2_pseudo_headers"], "could not get response pseudo-headers")
+		require.Contains(t, event.InternalEvent["h2_request_pseudo_headers"], ":method: GET", "could not get request pseudo-headers")
+		require.Contains(t, event.InternalEvent["h2_frames"], "received HEADERS stream=", "could not get frames")
+		require.Equal(t, "", event.InternalEvent["h2_rst_stream"], "unexpected stream reset")
+		require.False(t, event.OperatorsResult != nil && event.OperatorsResult.Matched, "matched without stream error")
+	})
+	t.Run("rst-stream", func(t *testing.T) {
+		input := mockServer(t, func(framer *http2.Framer, streamID uint32) {
+			_ = framer.WriteRSTStream(streamID, http2.ErrCodeEnhanceYourCalm)
+		})
+		event, err := execute(t, input)
+		require.NotNil(t, err, "reset request did not fail")
+		require.NotNil(t, event, "no event for reset request")
+		require.Equal(t, "ENHANCE_YOUR_CALM", event.InternalEvent["h2_rst_stream"], "could not get rst stream error code")
+		require.NotEmpty(t, event.InternalEvent["h2_stream_error"], "could not get stream error")
+		require.True(t, event.OperatorsResult.Matched, "could not match stream error")
+	})
+	t.Run("rst-stream-after-headers", func(t *testing.T) {
+		input := mockServer(t, func(framer *http2.Framer, streamID uint32) {
+			writeHeaders(framer, streamID, false)
+			_ = framer.WriteRSTStream(streamID, http2.ErrCodeEnhanceYourCalm)
+		})
+		event, err := execute(t, input)
+		require.Nil(t, err, "could not execute http2 request")
+		require.Equal(t, 200, event.InternalEvent["status_code"], "could not get status code")
+		require.Equal(t, "ENHANCE_YOUR_CALM", event.InternalEvent["h2_rst_stream"], "could not get rst stream error code")
+		require.True(t, event.OperatorsResult.Matched, "could not match stream error")
+	})
+	t.Run("goaway", func(t *testing.T) {
+		input := mockServer(t, func(framer *http2.Framer, streamID uint32) {
+			_ = framer.WriteGoAway(0, http2.ErrCodeEnhanceYourCalm, []byte("too many resets"))
+		})
+		event, err := execute(t, input)
+		require.NotNil(t, err, "request did not fail")
+		require.NotNil(t, event, "no event for request closed with goaway")
+		require.Equal(t, "ENHANCE_YOUR_CALM", event.InternalEvent["h2_goaway"], "could not get goaway error code")
+		require.Equal(t, "too many resets", event.InternalEvent["h2_goaway_debug"], "could not get goaway debug data")
+		require.True(t, event.OperatorsResult.Matched, "could not match goaway")
+	})
+}
+
+func TestHTTPRequestRetryBackoff(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http-retry-backoff"
+	request := &Request{
+		ID:     templateID,
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Path:   []string{"{{BaseURL}}"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:   matchers.MatcherTypeHolder{MatcherType: matchers.StatusMatcher},
+				Status: []int{200},
+			}},
+		},
+	}
+	var attempts atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.HostBreaker = hostbreaker.New(hostbreaker.Options{MaxRetries: 3, Threshold: 5, Cooldown: time.Minute})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var finalEvent *output.InternalWrappedEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.True(t, finalEvent.OperatorsResult.Matched, "could not match retried response")
+	require.Equal(t, int32(3), attempts.Load(), "throttled request was not retried")
+	require.Equal(t, hostbreaker.Stats{Retries: 2}, executerOpts.HostBreaker.Stats(), "wrong breaker stats")
+}
+
+func TestHTTPRequestRespectRateHeaders(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http-rate-headers"
+	request := &Request{
+		ID:                 templateID,
+		Method:             HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Path:               []string{"{{BaseURL}}/first", "{{BaseURL}}/second"},
+		RespectRateHeaders: true,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{"rate_limit_delay == 1"},
+			}},
+		},
+	}
+	var mu sync.Mutex
+	requested := make(map[string]time.Time)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = time.Now()
+		mu.Unlock()
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1")
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched int
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched++
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.Equal(t, 2, matched, "could not match rate limit delay")
+	require.GreaterOrEqual(t, requested["/second"].Sub(requested["/first"]), 900*time.Millisecond, "rate limit delay was not respected")
+}
+
+func TestHTTPRequestMaxBodySize(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http-max-body-size"
+	request := &Request{
+		ID:     templateID,
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Path:   []string{"{{BaseURL}}"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{"truncated && len(body) == 100"},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		_, _ = writer.Write(bytes.Repeat([]byte("a"), 1000))
+		_ = writer.Close()
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.MaxBodySize = 100
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var finalEvent *output.InternalWrappedEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.True(t, finalEvent.OperatorsResult.Matched, "could not match truncated body")
+	require.True(t, finalEvent.Results[0].Truncated, "truncation not included in output")
+}
+
+func TestHTTPRequestIPv6(t *testing.T) {
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("ipv6 loopback not available: %s", err)
+	}
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http-ipv6"
+	request := &Request{
+		ID:     templateID,
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Path:   []string{"{{BaseURL}}/{{Port}}"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"ipv6"},
+			}},
+		},
+	}
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "ipv6")
+	}))
+	ts.Listener = listener
+	ts.Start()
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var finalEvent *output.InternalWrappedEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.True(t, finalEvent.OperatorsResult.Matched, "could not match ipv6 response")
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.Equal(t, fmt.Sprintf("%s/%d", ts.URL, port), finalEvent.Results[0].Matched, "wrong matched url")
+	require.Equal(t, "::1", finalEvent.Results[0].IP, "wrong dialed ip")
+}
+
+func TestHTTPRequestComputedHeaders(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:      templateID,
+		Method:  HTTPMethodTypeHolder{MethodType: HTTPPost},
+		Path:    []string{"{{BaseURL}}/api/users?id=1"},
+		Body:    `{"name":"test"}`,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		ComputedHeaders: map[string]string{
+			"X-Timestamp": "{{request_timestamp}}",
+			"X-Signature": "{{hmac('sha256', request_method + request_path + request_query + request_timestamp + request_body, 'secret')}}",
+			"X-Content":   `{{request_headers["content-type"]}}`,
+		},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:   matchers.MatcherTypeHolder{MatcherType: matchers.StatusMatcher},
+				Status: []int{200},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(r.Method + r.URL.EscapedPath() + r.URL.RawQuery + r.Header.Get("X-Timestamp") + string(body)))
+		if r.Header.Get("X-Signature") != hex.EncodeToString(mac.Sum(nil)) || r.Header.Get("X-Content") != "application/json" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		matched = matched || (event.OperatorsResult != nil && event.OperatorsResult.Matched)
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "computed headers not accepted by the server")
+}
+
+func TestHTTPRequestComputedHeadersUnsafe(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:     templateID,
+		Unsafe: true,
+		Raw:    []string{"GET /api HTTP/1.1\r\nHost: {{Hostname}}\r\nX-Signature: placeholder\r\n\r\n"},
+		ComputedHeaders: map[string]string{
+			"X-Signature": "{{md5(request_method + request_path)}}",
+			"X-Computed":  "true",
+		},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:   matchers.MatcherTypeHolder{MatcherType: matchers.StatusMatcher},
+				Status: []int{200},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sum := md5.Sum([]byte("GET/api"))
+		if r.Header.Values("X-Signature")[0] != hex.EncodeToString(sum[:]) || len(r.Header.Values("X-Signature")) != 1 || r.Header.Get("X-Computed") != "true" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		matched = matched || (event.OperatorsResult != nil && event.OperatorsResult.Matched)
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "computed headers not sent with the unsafe request")
+}
+
+func TestComputedHeadersContext(t *testing.T) {
+	req, err := retryablehttp.NewRequest(http.MethodGet, "https://example.com:8443", nil)
+	require.Nil(t, err, "could not create request")
+	req.Header.Set("X-Api-Key", "key")
+
+	values, err := computedHeadersContext(&generatedRequest{request: req}, "https://example.com:8443", time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC))
+	require.Nil(t, err, "could not get computed headers context")
+	for field := range ComputedHeadersDefinitions {
+		require.Contains(t, values, field, "missing computed headers field")
+	}
+	require.Equal(t, "/", values["request_path"], "wrong default path")
+	require.Equal(t, "example.com:8443", values["request_host"], "wrong host")
+	require.Equal(t, "1672671845", values["request_timestamp"], "wrong timestamp")
+	require.Equal(t, "Mon, 02 Jan 2023 15:04:05 GMT", values["request_date"], "wrong date")
+	require.Equal(t, "20230102T150405Z", values["request_amz_date"], "wrong signing date")
+	require.Equal(t, "key", values["request_headers"].(map[string]interface{})["x-api-key"], "wrong headers")
+}
+
+func TestHTTPRequestRawBytes(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	const raw = "POST /smuggle HTTP/1.1\r\nHost: {{Hostname}}\r\nContent-Length: 4\r\nTransfer-Encoding : chunked\r\nX-Bare: a\rb\r\nX-LF: c\n\r\n0\r\n\r\nG"
+	const response = "HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nokHTTP/1.1 405 Method Not Allowed\r\n\r\n"
+
+	tlsServer := httptest.NewTLSServer(nil)
+	tlsConfig := tlsServer.TLS.Clone()
+	tlsServer.Close()
+
+	for _, useTLS := range []bool{false, true} {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.Nil(t, err, "could not listen")
+		scheme := "http"
+		if useTLS {
+			listener = tls.NewListener(listener, tlsConfig)
+			scheme = "https"
+		}
+		hostname := listener.Addr().String()
+		expected := strings.ReplaceAll(raw, "{{Hostname}}", hostname)
+
+		received := make(chan []byte, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				received <- nil
+				return
+			}
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+			var data []byte
+			buffer := make([]byte, 1024)
+			for len(data) < len(expected) {
+				n, err := conn.Read(buffer)
+				data = append(data, buffer[:n]...)
+				if err != nil {
+					break
+				}
+			}
+			received <- data
+			_, _ = conn.Write([]byte(response))
+		}()
+
+		request := &Request{
+			ID:               "testing-http-raw-bytes",
+			Raw:              []string{raw},
+			Unsafe:           true,
+			RawBytes:         true,
+			ForceReadAllBody: true,
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Type:   matchers.MatcherTypeHolder{MatcherType: matchers.StatusMatcher},
+					Status: []int{200},
+				}},
+			},
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   "testing-http-raw-bytes",
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		err = request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile http request")
+
+		var finalEvent *output.InternalWrappedEvent
+		err = request.ExecuteWithResults(contextargs.NewWithInput(scheme+"://"+hostname), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			finalEvent = event
+		})
+		listener.Close()
+		require.Nil(t, err, "could not execute http request over %s", scheme)
+		require.Equal(t, expected, string(<-received), "request bytes were modified over %s", scheme)
+		require.NotNil(t, finalEvent, "could not get event output from request")
+		require.True(t, finalEvent.OperatorsResult.Matched, "could not match raw bytes response over %s", scheme)
+		require.Equal(t, response, finalEvent.InternalEvent["raw_response"], "could not get raw response over %s", scheme)
+	}
+
+	err := (&Request{Raw: []string{raw}, RawBytes: true}).validate()
+	require.NotNil(t, err, "raw-bytes allowed without unsafe")
+}
@@ -4,23 +4,37 @@ import (
 	"bytes"
 	"compress/gzip"
 	"compress/zlib"
+	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httputil"
 	"strings"
+	"time"
 
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pkg/errors"
+	"golang.org/x/net/http2"
 	"golang.org/x/text/encoding/simplifiedchinese"
 	"golang.org/x/text/transform"
 
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/hostbreaker"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/http/httpclientpool"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/utils"
 	"github.com/projectdiscovery/rawhttp"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 )
 
 type redirectedResponse struct {
-	headers      []byte
-	body         []byte
+	headers []byte
+	body    []byte
+	rawBody []byte
+	// charsetBody is the body in its original charset if it was transcoded
+	charsetBody []byte
+	// charset is the charset detected for the body if transcoding
+	charset      string
 	fullResponse []byte
 	resp         *http.Response
 }
@@ -32,7 +46,9 @@ type redirectedResponse struct {
 // and returns the data to the user for matching and viewing in that order.
 //
 // Inspired from - https://github.com/ffuf/ffuf/issues/324#issuecomment-719858923
-func dumpResponseWithRedirectChain(resp *http.Response, body []byte) ([]redirectedResponse, error) {
+//
+// With transcode, the bodies in legacy charsets are transcoded to utf-8.
+func dumpResponseWithRedirectChain(resp *http.Response, body []byte, transcode bool) ([]redirectedResponse, error) {
 	var response []redirectedResponse
 
 	respData, err := httputil.DumpResponse(resp, false)
@@ -45,7 +61,7 @@ func dumpResponseWithRedirectChain(resp *http.Response, body []byte) ([]redirect
 		resp:         resp,
 		fullResponse: bytes.Join([][]byte{respData, body}, []byte{}),
 	}
-	if err := normalizeResponseBody(resp, &respObj); err != nil {
+	if err := normalizeResponseBody(resp, &respObj, transcode); err != nil {
 		return nil, err
 	}
 	response = append(response, respObj)
@@ -70,7 +86,7 @@ func dumpResponseWithRedirectChain(resp *http.Response, body []byte) ([]redirect
 			resp:         redirectResp,
 			fullResponse: bytes.Join([][]byte{respData, body}, []byte{}),
 		}
-		if err := normalizeResponseBody(redirectResp, &respObj); err != nil {
+		if err := normalizeResponseBody(redirectResp, &respObj, transcode); err != nil {
 			return nil, err
 		}
 		response = append(response, respObj)
@@ -79,13 +95,16 @@ func dumpResponseWithRedirectChain(resp *http.Response, body []byte) ([]redirect
 	return response, nil
 }
 
-// normalizeResponseBody performs normalization on the http response object.
-func normalizeResponseBody(resp *http.Response, response *redirectedResponse) error {
+// normalizeResponseBody performs normalization on the http response object,
+// transcoding the body to utf-8 from the charset declared by its content type
+// or its meta charset with transcode.
+func normalizeResponseBody(resp *http.Response, response *redirectedResponse, transcode bool) error {
 	var err error
 	// net/http doesn't automatically decompress the response body if an
 	// encoding has been specified by the user in the request so in case we have to
 	// manually do it.
 	dataOrig := response.body
+	response.rawBody = dataOrig
 	response.body, err = handleDecompression(resp, response.body)
 	// in case of error use original data
 	if err != nil {
@@ -93,9 +112,24 @@ func normalizeResponseBody(resp *http.Response, response *redirectedResponse) er
 	}
 	response.fullResponse = bytes.ReplaceAll(response.fullResponse, dataOrig, response.body)
 
+	responseContentType := resp.Header.Get("Content-Type")
+	if transcode {
+		transcoded, charset, err := utils.TranscodeToUTF8(responseContentType, response.body)
+		if err != nil {
+			return err
+		}
+		response.charset = charset
+		if !bytes.Equal(transcoded, response.body) {
+			// the untranscoded body is kept for binary matchers
+			response.charsetBody = response.body
+			response.fullResponse = bytes.ReplaceAll(response.fullResponse, response.body, transcoded)
+			response.body = transcoded
+		}
+		return nil
+	}
+
 	// Decode gbk response content-types
 	// gb18030 supersedes gb2312
-	responseContentType := resp.Header.Get("Content-Type")
 	if isContentTypeGbk(responseContentType) {
 		response.fullResponse, err = decodeGBK(response.fullResponse)
 		if err != nil {
@@ -127,11 +161,19 @@ func handleDecompression(resp *http.Response, bodyOrig []byte) (bodyDec []byte,
 	}
 
 	var reader io.ReadCloser
-	switch resp.Header.Get("Content-Encoding") {
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
 	case "gzip":
 		reader, err = gzip.NewReader(bytes.NewReader(bodyOrig))
 	case "deflate":
 		reader, err = zlib.NewReader(bytes.NewReader(bodyOrig))
+	case "br":
+		reader = io.NopCloser(brotli.NewReader(bytes.NewReader(bodyOrig)))
+	case "zstd":
+		var zstdReader *zstd.Decoder
+		zstdReader, err = zstd.NewReader(bytes.NewReader(bodyOrig))
+		if err == nil {
+			reader = zstdReader.IOReadCloser()
+		}
 	default:
 		return bodyOrig, nil
 	}
@@ -163,3 +205,68 @@ func isContentTypeGbk(contentType string) bool {
 	contentType = strings.ToLower(contentType)
 	return stringsutil.ContainsAny(contentType, "gbk", "gb2312", "gb18030")
 }
+
+// rateLimitDelay returns the delay in seconds, rounded up, advertised by the
+// rate limit headers of a response or 0 if none
+func rateLimitDelay(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	delay, ok := hostbreaker.RateLimitDelay(resp.Header, time.Now())
+	if !ok || delay <= 0 {
+		return 0
+	}
+	return int(math.Ceil(delay.Seconds()))
+}
+
+// http2FramesToDSLMap returns the stream-level details of the http2 frames
+// recorded for a request, along with the http2 error it failed with if any
+func http2FramesToDSLMap(capture *httpclientpool.HTTP2Capture, streamErr error) map[string]interface{} {
+	var frames, rstStream, goAway, goAwayDebug []string
+	for _, frame := range capture.Frames() {
+		line := fmt.Sprintf("%s %s stream=%d flags=0x%x length=%d", frame.Direction, frame.Type, frame.StreamID, uint8(frame.Flags), frame.Length)
+		if frame.ErrCode != "" {
+			line += " error=" + frame.ErrCode
+		}
+		frames = append(frames, line)
+		if frame.Direction != "received" {
+			continue
+		}
+		switch frame.Type {
+		case http2.FrameRSTStream.String():
+			rstStream = append(rstStream, frame.ErrCode)
+		case http2.FrameGoAway.String():
+			goAway = append(goAway, frame.ErrCode)
+			goAwayDebug = append(goAwayDebug, frame.DebugData)
+		}
+	}
+	var streamError string
+	if streamErr != nil {
+		streamError = streamErr.Error()
+	}
+	return map[string]interface{}{
+		"h2_frames":                 strings.Join(frames, "\n"),
+		"h2_pseudo_headers":         strings.Join(capture.PseudoHeaders("received"), "\n"),
+		"h2_request_pseudo_headers": strings.Join(capture.PseudoHeaders("sent"), "\n"),
+		"h2_rst_stream":             strings.Join(rstStream, "\n"),
+		"h2_goaway":                 strings.Join(goAway, "\n"),
+		"h2_goaway_debug":           strings.Join(goAwayDebug, "\n"),
+		"h2_stream_error":           streamError,
+	}
+}
+
+// isHTTP2StreamError returns true if the error was caused by an http2 stream
+// reset or a GOAWAY frame, as reported by the error or recorded by the capture
+func isHTTP2StreamError(capture *httpclientpool.HTTP2Capture, err error) bool {
+	var streamErr http2.StreamError
+	var goAwayErr http2.GoAwayError
+	if errors.As(err, &streamErr) || errors.As(err, &goAwayErr) {
+		return true
+	}
+	for _, frame := range capture.Frames() {
+		if frame.Direction == "received" && (frame.Type == http2.FrameRSTStream.String() || frame.Type == http2.FrameGoAway.String()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleDecompression(t *testing.T) {
+	body := []byte("<html>nuclei decompression test</html>")
+
+	compress := func(t *testing.T, fn func(w io.Writer) io.WriteCloser) []byte {
+		var buf bytes.Buffer
+		writer := fn(&buf)
+		_, err := writer.Write(body)
+		require.Nil(t, err, "could not write compressed data")
+		require.Nil(t, writer.Close(), "could not close compressed writer")
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		encoding string
+		data     []byte
+	}{
+		{"gzip", compress(t, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })},
+		{"deflate", compress(t, func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) })},
+		{"br", compress(t, func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) })},
+		{"zstd", compress(t, func(w io.Writer) io.WriteCloser {
+			encoder, _ := zstd.NewWriter(w)
+			return encoder
+		})},
+		{"BR", compress(t, func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) })},
+	}
+	for _, test := range tests {
+		t.Run(test.encoding, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{"Content-Encoding": []string{test.encoding}}}
+			decoded, err := handleDecompression(resp, test.data)
+			require.Nil(t, err, "could not decompress body")
+			require.Equal(t, body, decoded, "could not get decompressed body")
+		})
+	}
+
+	t.Run("mislabeled", func(t *testing.T) {
+		for _, encoding := range []string{"gzip", "deflate", "br", "zstd"} {
+			resp := &http.Response{Header: http.Header{"Content-Encoding": []string{encoding}}}
+			response := &redirectedResponse{body: body, fullResponse: body}
+			err := normalizeResponseBody(resp, response, false)
+			require.Nil(t, err, "could not normalize response body")
+			require.Equal(t, body, response.body, "mislabeled %s body was modified", encoding)
+			require.Equal(t, body, response.rawBody, "could not get raw body")
+		}
+	})
+}
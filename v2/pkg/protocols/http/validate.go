@@ -1,6 +1,13 @@
 package http
 
-import "github.com/pkg/errors"
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/http/httpclientpool"
+)
 
 func (request *Request) validate() error {
 	if request.Race && request.NeedsRequestCondition() {
@@ -11,5 +18,27 @@ func (request *Request) validate() error {
 		return errors.New("'redirects' and 'host-redirects' can't be used together")
 	}
 
+	if request.RawBytes && (!request.Unsafe || len(request.Raw) == 0) {
+		return errors.New("'raw-bytes' requires 'unsafe' raw requests")
+	}
+
+	if request.HTTPVersion != "" {
+		if !sliceutil.Contains(httpclientpool.HTTPVersions, request.HTTPVersion) {
+			return errors.Errorf("invalid 'http-version' %q, supported values are %s", request.HTTPVersion, strings.Join(httpclientpool.HTTPVersions, ", "))
+		}
+		if request.Unsafe || request.Pipeline {
+			return errors.New("'http-version' can't be used with 'unsafe' or 'pipeline'")
+		}
+		if request.JA3 != "" && request.HTTPVersion != httpclientpool.HTTP1 {
+			return errors.New("'ja3' can only be used with 'http1' 'http-version'")
+		}
+	}
+	if request.HTTPVersionFallback && request.HTTPVersion == "" {
+		return errors.New("'http-version-fallback' requires 'http-version'")
+	}
+	if request.HTTP2Frames && request.HTTPVersion != httpclientpool.HTTP2 {
+		return errors.New("'http2-frames' requires the 'http2' 'http-version'")
+	}
+
 	return nil
 }
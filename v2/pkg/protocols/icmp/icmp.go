@@ -0,0 +1,253 @@
+package icmp
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/eventcreator"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/replacer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/utils/vardump"
+	protocolutils "github.com/projectdiscovery/nuclei/v2/pkg/protocols/utils"
+	templateTypes "github.com/projectdiscovery/nuclei/v2/pkg/templates/types"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// Request is a request for the ICMP protocol
+type Request struct {
+	// Operators for the current request go here.
+	operators.Operators `yaml:",inline,omitempty" json:",inline,omitempty"`
+	CompiledOperators   *operators.Operators `yaml:"-" json:"-"`
+
+	// description: |
+	//   Host contains the host to send echo requests to.
+	//
+	//   Defaults to {{Hostname}} if not specified.
+	// examples:
+	//   - value: "\"{{Hostname}}\""
+	Host string `yaml:"host,omitempty" json:"host,omitempty" jsonschema:"title=host for the icmp request,description=Host contains the host to send echo requests to"`
+	// description: |
+	//   Count is the number of echo requests to send.
+	//
+	//   Defaults to 1 if not specified.
+	// examples:
+	//   - value: 3
+	Count int `yaml:"count,omitempty" json:"count,omitempty" jsonschema:"title=number of echo requests,description=Count is the number of echo requests to send"`
+
+	// cache any variables that may be needed for operation.
+	options *protocols.ExecuterOptions
+}
+
+// RequestPartDefinitions contains a mapping of request part definitions and their
+// description. Multiple definitions are separated by commas.
+// Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
+var RequestPartDefinitions = map[string]string{
+	"type":        "Type is the type of request made",
+	"host":        "Host is the input to the template",
+	"matched":     "Matched is the host which was pinged",
+	"ip":          "IP is the address the echo requests were sent to",
+	"reachable":   "Reachable is true if any echo reply was received",
+	"rtt":         "RTT is the average round trip time in milliseconds",
+	"min_rtt":     "Min RTT is the minimum round trip time in milliseconds",
+	"max_rtt":     "Max RTT is the maximum round trip time in milliseconds",
+	"ttl":         "TTL is the ttl (or hop limit) of the echo reply",
+	"sent":        "Sent is the number of echo requests sent",
+	"received":    "Received is the number of echo replies received",
+	"packet_loss": "Packet Loss is the percentage of echo requests without reply",
+	"privileged":  "Privileged is true if raw sockets were used for the requests",
+	"response":    "Ping style summary of the echo replies",
+}
+
+// Compile compiles the request generators preparing any requests possible.
+func (request *Request) Compile(options *protocols.ExecuterOptions) error {
+	request.options = options
+
+	if request.Host == "" {
+		request.Host = "{{Hostname}}"
+	}
+	if request.Count <= 0 {
+		request.Count = 1
+	}
+
+	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
+		compiled := &request.Operators
+		compiled.ExcludeMatchers = options.ExcludeMatchers
+		compiled.TemplateID = options.TemplateID
+		if err := compiled.Compile(); err != nil {
+			return errors.Wrap(err, "could not compile operators")
+		}
+		request.CompiledOperators = compiled
+	}
+	return nil
+}
+
+// Requests returns the total number of requests the rule will perform
+func (request *Request) Requests() int {
+	return 1
+}
+
+// GetID returns the ID for the request if any.
+func (request *Request) GetID() string {
+	return ""
+}
+
+// ExecuteWithResults executes the protocol requests and returns results instead of writing them.
+func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	// generate variables
+	defaultVars := protocolutils.GenerateVariables(input.MetaInput.Input, false, nil)
+	optionVars := generators.BuildPayloadFromOptions(request.options.Options)
+	vars := request.options.Variables.Evaluate(generators.MergeMaps(defaultVars, optionVars, dynamicValues))
+
+	variables := generators.MergeMaps(vars, defaultVars, optionVars, dynamicValues)
+
+	if vardump.EnableVarDump {
+		gologger.Debug().Msgf("Protocol request variables: \n%s\n", vardump.DumpVariables(variables))
+	}
+
+	host := replacer.Replace(request.Host, variables)
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	// Check if the request budget of the template for the host is exhausted
+	if !request.options.RequestBudget.Take(input.MetaInput.Input) {
+		request.options.Progress.IncrementSkippedRequestsBy(1)
+		return nil
+	}
+
+	ip, err := request.resolve(host, input.MetaInput.CustomIP)
+	if err != nil {
+		request.options.Output.Request(request.options.TemplatePath, host, request.Type().String(), err)
+		request.options.Progress.IncrementFailedRequestsBy(1)
+		return errors.Wrap(err, "could not resolve host")
+	}
+
+	timeout := time.Duration(request.options.Options.Timeout) * time.Second
+	result, err := ping(ip, request.Count, timeout)
+	if err != nil {
+		request.options.Output.Request(request.options.TemplatePath, host, request.Type().String(), err)
+		request.options.Progress.IncrementFailedRequestsBy(1)
+		return errors.Wrap(err, "could not make icmp request")
+	}
+	request.options.Progress.IncrementRequests()
+
+	request.options.Output.Request(request.options.TemplatePath, host, request.Type().String(), nil)
+	gologger.Verbose().Msgf("Sent ICMP request to %s", host)
+	if request.options.Options.Debug || request.options.Options.DebugRequests {
+		gologger.Debug().Msgf("[%s] Dumped ICMP request for %s (%s)", request.options.TemplateID, host, ip)
+	}
+
+	response := fmt.Sprintf("PING %s (%s): %s", host, ip, result.String())
+	min, avg, max := result.Stats()
+
+	data := make(map[string]interface{})
+	for k, v := range variables {
+		data[k] = v
+	}
+	data["type"] = request.Type().String()
+	data["host"] = input.MetaInput.Input
+	data["matched"] = host
+	data["ip"] = ip.String()
+	data["reachable"] = result.Reachable()
+	data["rtt"] = durationToMillis(avg)
+	data["min_rtt"] = durationToMillis(min)
+	data["max_rtt"] = durationToMillis(max)
+	data["ttl"] = result.TTL
+	data["sent"] = result.Sent
+	data["received"] = result.Received
+	data["packet_loss"] = result.PacketLoss()
+	data["privileged"] = result.Privileged
+	data["response"] = response
+
+	event := eventcreator.CreateEvent(request, data, request.options.Options.Debug || request.options.Options.DebugResponse)
+	if request.options.Options.Debug || request.options.Options.DebugResponse {
+		gologger.Debug().Msgf("[%s] Dumped ICMP response for %s", request.options.TemplateID, host)
+		gologger.Print().Msgf("%s", responsehighlighter.Highlight(event.OperatorsResult, response, request.options.Options.NoColor, false))
+	}
+
+	callback(event)
+	return nil
+}
+
+// resolve returns the ip address to send echo requests to, preferring ipv4 addresses
+func (request *Request) resolve(host, customIP string) (net.IP, error) {
+	if customIP != "" {
+		host = customIP
+	}
+	if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+		return ip, nil
+	}
+	if protocolstate.Dialer == nil {
+		return nil, errors.New("dialer is not initialized")
+	}
+	dnsData, err := protocolstate.Dialer.GetDNSData(host)
+	if err != nil {
+		return nil, err
+	}
+	for _, addresses := range [][]string{dnsData.A, dnsData.AAAA} {
+		for _, address := range addresses {
+			if ip := net.ParseIP(address); ip != nil {
+				return ip, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no ip address found for %s", host)
+}
+
+// Match performs matching operation for a matcher on model and returns:
+// true and a list of matched snippets if the matcher type is supports it
+// otherwise false and an empty string slice
+func (request *Request) Match(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
+	return protocols.MakeDefaultMatchFunc(data, matcher)
+}
+
+// Extract performs extracting operation for an extractor on model and returns true or false.
+func (request *Request) Extract(data map[string]interface{}, matcher *extractors.Extractor) map[string]struct{} {
+	return protocols.MakeDefaultExtractFunc(data, matcher)
+}
+
+// MakeResultEvent creates a result event from internal wrapped event
+func (request *Request) MakeResultEvent(wrapped *output.InternalWrappedEvent) []*output.ResultEvent {
+	return protocols.MakeDefaultResultEvent(request, wrapped)
+}
+
+// GetCompiledOperators returns a list of the compiled operators
+func (request *Request) GetCompiledOperators() []*operators.Operators {
+	return []*operators.Operators{request.CompiledOperators}
+}
+
+func (request *Request) MakeResultEventItem(wrapped *output.InternalWrappedEvent) *output.ResultEvent {
+	data := &output.ResultEvent{
+		TemplateID:       types.ToString(request.options.TemplateID),
+		TemplatePath:     types.ToString(request.options.TemplatePath),
+		Info:             request.options.TemplateInfo,
+		Type:             types.ToString(wrapped.InternalEvent["type"]),
+		Host:             types.ToString(wrapped.InternalEvent["host"]),
+		Matched:          types.ToString(wrapped.InternalEvent["matched"]),
+		Metadata:         wrapped.OperatorsResult.PayloadValues,
+		ExtractedResults: wrapped.OperatorsResult.OutputExtracts,
+		Timestamp:        time.Now(),
+		MatcherStatus:    true,
+		IP:               types.ToString(wrapped.InternalEvent["ip"]),
+		Response:         types.ToString(wrapped.InternalEvent["response"]),
+	}
+	return data
+}
+
+// Type returns the type of the protocol request
+func (request *Request) Type() templateTypes.ProtocolType {
+	return templateTypes.ICMPProtocol
+}
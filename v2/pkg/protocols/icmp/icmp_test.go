@@ -0,0 +1,88 @@
+package icmp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/model"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/requestbudget"
+	"github.com/projectdiscovery/nuclei/v2/pkg/testutils"
+)
+
+func TestICMPProtocol(t *testing.T) {
+	if conn, _, err := listen(false); err != nil {
+		t.Skipf("icmp sockets are not available: %s", err)
+	} else {
+		conn.Close()
+	}
+
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-icmp"
+	request := &Request{Count: 2}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile icmp request")
+	require.Equal(t, "{{Hostname}}", request.Host, "could not set default host")
+
+	var gotEvent output.InternalEvent
+	ctxArgs := contextargs.NewWithInput("127.0.0.1")
+	err = request.ExecuteWithResults(ctxArgs, nil, nil, func(event *output.InternalWrappedEvent) {
+		gotEvent = event.InternalEvent
+	})
+	require.Nil(t, err, "could not run icmp request")
+	require.Equal(t, true, gotEvent["reachable"], "could not ping localhost")
+	require.Equal(t, "127.0.0.1", gotEvent["ip"])
+	require.Equal(t, 2, gotEvent["sent"])
+	require.Positive(t, gotEvent["ttl"], "could not get ttl")
+}
+
+func TestICMPExecuteRequestBudget(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-icmp-budget"
+	request := &Request{}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.RequestBudget = requestbudget.New(templateID, 1)
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile icmp request")
+
+	// exhaust the budget of the host so that no echo request is sent
+	require.True(t, executerOpts.RequestBudget.Take("127.0.0.1"), "could not take from budget")
+
+	var gotEvent bool
+	err = request.ExecuteWithResults(contextargs.NewWithInput("127.0.0.1"), nil, nil, func(event *output.InternalWrappedEvent) {
+		gotEvent = true
+	})
+	require.Nil(t, err, "could not run icmp request")
+	require.False(t, gotEvent, "request sent beyond the budget")
+}
+
+func TestPingResult(t *testing.T) {
+	result := &pingResult{Sent: 4, Received: 2, RTTs: []time.Duration{time.Millisecond, 3 * time.Millisecond}, TTL: 64}
+	require.True(t, result.Reachable())
+	require.Equal(t, float64(50), result.PacketLoss())
+
+	min, avg, max := result.Stats()
+	require.Equal(t, time.Millisecond, min)
+	require.Equal(t, 2*time.Millisecond, avg)
+	require.Equal(t, 3*time.Millisecond, max)
+	require.Equal(t, "4 packets transmitted, 2 received, 50% packet loss\nrtt min/avg/max = 1.000/2.000/3.000 ms\nttl=64", result.String())
+
+	result = &pingResult{Sent: 1}
+	require.False(t, result.Reachable())
+	require.Equal(t, "1 packets transmitted, 0 received, 100% packet loss", result.String())
+}
@@ -0,0 +1,190 @@
+package icmp
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	protocolICMP     = 1
+	protocolIPv6ICMP = 58
+	// payloadSize is the size of the data sent in echo requests
+	payloadSize = 56
+)
+
+// pingResult contains the result of pinging a host
+type pingResult struct {
+	// Sent is the number of echo requests sent
+	Sent int
+	// Received is the number of echo replies received
+	Received int
+	// RTTs contains the round trip times of the echo replies
+	RTTs []time.Duration
+	// TTL is the ttl (or hop limit) of the last echo reply
+	TTL int
+	// Privileged is true if a raw socket was used for the requests
+	Privileged bool
+}
+
+// Reachable returns true if any echo reply was received
+func (r *pingResult) Reachable() bool {
+	return r.Received > 0
+}
+
+// PacketLoss returns the percentage of echo requests without reply
+func (r *pingResult) PacketLoss() float64 {
+	if r.Sent == 0 {
+		return 0
+	}
+	return float64(r.Sent-r.Received) / float64(r.Sent) * 100
+}
+
+// Stats returns the minimum, average and maximum round trip times
+func (r *pingResult) Stats() (min, avg, max time.Duration) {
+	if len(r.RTTs) == 0 {
+		return 0, 0, 0
+	}
+	var total time.Duration
+	min = r.RTTs[0]
+	for _, rtt := range r.RTTs {
+		total += rtt
+		if rtt < min {
+			min = rtt
+		}
+		if rtt > max {
+			max = rtt
+		}
+	}
+	return min, total / time.Duration(len(r.RTTs)), max
+}
+
+// String returns a ping style summary of the result
+func (r *pingResult) String() string {
+	summary := fmt.Sprintf("%d packets transmitted, %d received, %.0f%% packet loss", r.Sent, r.Received, r.PacketLoss())
+	if !r.Reachable() {
+		return summary
+	}
+	min, avg, max := r.Stats()
+	return fmt.Sprintf("%s\nrtt min/avg/max = %.3f/%.3f/%.3f ms\nttl=%d", summary, durationToMillis(min), durationToMillis(avg), durationToMillis(max), r.TTL)
+}
+
+func durationToMillis(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
+
+// listen opens an icmp socket for the ip version.
+//
+// Raw sockets require root or CAP_NET_RAW, so unprivileged datagram
+// sockets are used as a fallback when raw sockets can't be opened.
+func listen(isIPv6 bool) (*icmp.PacketConn, bool, error) {
+	network, udpNetwork, address := "ip4:icmp", "udp4", "0.0.0.0"
+	if isIPv6 {
+		network, udpNetwork, address = "ip6:ipv6-icmp", "udp6", "::"
+	}
+	conn, err := icmp.ListenPacket(network, address)
+	if err == nil {
+		return conn, true, nil
+	}
+	conn, udpErr := icmp.ListenPacket(udpNetwork, address)
+	if udpErr == nil {
+		return conn, false, nil
+	}
+	return nil, false, fmt.Errorf("could not open icmp socket: raw sockets require root or CAP_NET_RAW (%v) and unprivileged ping is not permitted, check net.ipv4.ping_group_range (%v)", err, udpErr)
+}
+
+// ping sends count echo requests to ip waiting up to timeout for each reply
+func ping(ip net.IP, count int, timeout time.Duration) (*pingResult, error) {
+	isIPv6 := ip.To4() == nil
+	conn, privileged, err := listen(isIPv6)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	requestType, replyType, protocol := icmp.Type(ipv4.ICMPTypeEcho), icmp.Type(ipv4.ICMPTypeEchoReply), protocolICMP
+	if isIPv6 {
+		requestType, replyType, protocol = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply, protocolIPv6ICMP
+		_ = conn.IPv6PacketConn().SetControlMessage(ipv6.FlagHopLimit, true)
+	} else {
+		_ = conn.IPv4PacketConn().SetControlMessage(ipv4.FlagTTL, true)
+	}
+
+	var destination net.Addr = &net.UDPAddr{IP: ip}
+	if privileged {
+		destination = &net.IPAddr{IP: ip}
+	}
+
+	result := &pingResult{Privileged: privileged}
+	id := rand.Intn(0xffff)
+	payload := make([]byte, payloadSize)
+	buffer := make([]byte, 1500)
+	for seq := 1; seq <= count; seq++ {
+		message := icmp.Message{Type: requestType, Body: &icmp.Echo{ID: id, Seq: seq, Data: payload}}
+		data, err := message.Marshal(nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not marshal echo request")
+		}
+
+		start := time.Now()
+		if _, err := conn.WriteTo(data, destination); err != nil {
+			return nil, errors.Wrap(err, "could not send echo request")
+		}
+		result.Sent++
+		_ = conn.SetReadDeadline(start.Add(timeout))
+
+		for {
+			n, ttl, peer, err := readFrom(conn, buffer, isIPv6)
+			if err != nil {
+				// timeout waiting for the reply
+				break
+			}
+			reply, err := icmp.ParseMessage(protocol, buffer[:n])
+			if err != nil || reply.Type != replyType {
+				continue
+			}
+			echo, ok := reply.Body.(*icmp.Echo)
+			// the kernel rewrites the id of unprivileged echo requests
+			if !ok || echo.Seq != seq || (privileged && echo.ID != id) || !peerIP(peer).Equal(ip) {
+				continue
+			}
+			result.Received++
+			result.RTTs = append(result.RTTs, time.Since(start))
+			result.TTL = ttl
+			break
+		}
+	}
+	return result, nil
+}
+
+// readFrom reads a packet from the connection along with its ttl
+func readFrom(conn *icmp.PacketConn, buffer []byte, isIPv6 bool) (int, int, net.Addr, error) {
+	if isIPv6 {
+		n, cm, peer, err := conn.IPv6PacketConn().ReadFrom(buffer)
+		if cm != nil {
+			return n, cm.HopLimit, peer, err
+		}
+		return n, 0, peer, err
+	}
+	n, cm, peer, err := conn.IPv4PacketConn().ReadFrom(buffer)
+	if cm != nil {
+		return n, cm.TTL, peer, err
+	}
+	return n, 0, peer, err
+}
+
+func peerIP(addr net.Addr) net.IP {
+	switch v := addr.(type) {
+	case *net.IPAddr:
+		return v.IP
+	case *net.UDPAddr:
+		return v.IP
+	}
+	return nil
+}
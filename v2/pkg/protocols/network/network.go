@@ -10,6 +10,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/expressions"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/ja3"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/network/networkclientpool"
 	fileutil "github.com/projectdiscovery/utils/file"
 )
@@ -24,6 +25,10 @@ type Request struct {
 	//
 	//   Usually it's set to `{{Hostname}}`. If you want to enable TLS for
 	//   TCP Connection, you can use `tls://{{Hostname}}`.
+	//
+	//   Unix domain sockets can be used with `unix://` followed by the path
+	//   of the socket, e.g. `unix:///var/run/docker.sock`. For inputs in the
+	//   `unix:///path/to/socket` format, `unix://{{Hostname}}` connects to the input socket.
 	// examples:
 	//   - value: |
 	//       []string{"{{Hostname}}"}
@@ -42,6 +47,10 @@ type Request struct {
 	//   Payloads support both key-values combinations where a list
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
+	//
+	//   Payloads can also be generated lazily from a map naming a value generator:
+	//   range (start, end, step, format), date (start, end, step, format) or
+	//   charset (charset, min, max), bounded by an optional limit of values.
 	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the network request,description=Payloads contains any payloads for the current request"`
 
 	// description: |
@@ -61,6 +70,13 @@ type Request struct {
 	// examples:
 	//   - value: false
 	ReadAll bool `yaml:"read-all,omitempty" json:"read-all,omitempty" jsonschema:"title=read all response stream,description=Read all response stream till the server stops sending"`
+	// description: |
+	//   JA3 is the ja3 fingerprint to use for the client hello of `tls://` connections.
+	//
+	//   Overrides the global ja3 option.
+	// examples:
+	//   - value: "\"771,4865-4866-4867-49195-49199,0-23-65281-10-11-35-16-5-13-51-45-43,29-23-24,0\""
+	JA3 string `yaml:"ja3,omitempty" json:"ja3,omitempty" jsonschema:"title=ja3 fingerprint of the client hello,description=JA3 fingerprint to use for the client hello of tls connections"`
 
 	// description: |
 	//   SelfContained specifies if the request is self-contained.
@@ -71,6 +87,10 @@ type Request struct {
 	CompiledOperators   *operators.Operators `yaml:"-"`
 
 	generator *generators.PayloadGenerator
+	// ja3Spec is the parsed ja3 fingerprint for tls connections, if any
+	ja3Spec *ja3.Spec
+	// ja3 is the ja3 fingerprint of the client hello sent
+	ja3 string
 	// cache any variables that may be needed for operation.
 	dialer  *fastdialer.Dialer
 	options *protocols.ExecuterOptions
@@ -89,11 +109,15 @@ var RequestPartDefinitions = map[string]string{
 	"request":       "Network request made from the client",
 	"body,all,data": "Network response received from server (default)",
 	"raw":           "Full Network protocol data",
+	"ja3":           "JA3 fingerprint of the tls client hello sent (only if a ja3 is configured)",
+	"ja3_hash":      "MD5 hash of the JA3 fingerprint of the tls client hello sent",
+	"truncated":     "True if the response was truncated to the maximum body size",
 }
 
 type addressKV struct {
 	address string
 	tls     bool
+	unix    bool
 }
 
 // Input is the input to send on the network
@@ -144,6 +168,11 @@ func (request *Request) Compile(options *protocols.ExecuterOptions) error {
 
 	request.options = options
 	for _, address := range request.Address {
+		// check if the connection should be made to a unix socket
+		if strings.HasPrefix(address, "unix://") {
+			request.addresses = append(request.addresses, addressKV{address: strings.TrimPrefix(address, "unix://"), unix: true})
+			continue
+		}
 		// check if the connection should be encrypted
 		if strings.HasPrefix(address, "tls://") {
 			shouldUseTLS = true
@@ -190,6 +219,18 @@ func (request *Request) Compile(options *protocols.ExecuterOptions) error {
 		}
 	}
 
+	if ja3Value := request.JA3; ja3Value != "" || options.Options.JA3 != "" {
+		if ja3Value == "" {
+			ja3Value = options.Options.JA3
+		}
+		if request.ja3Spec, err = ja3.Parse(ja3Value); err != nil {
+			return errors.Wrap(err, "could not parse ja3")
+		}
+		if request.ja3, err = ja3.Fingerprint(request.ja3Spec); err != nil {
+			return errors.Wrap(err, "could not build ja3 client hello")
+		}
+	}
+
 	// Create a client for the class
 	client, err := networkclientpool.Get(options.Options, &networkclientpool.Configuration{})
 	if err != nil {
@@ -28,6 +28,14 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchRegex(itemStr))
 	case matchers.BinaryMatcher:
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(itemStr))
+	case matchers.JSONSchemaMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchJSONSchema(itemStr))
+	case matchers.DifferentialMatcher:
+		baseline, ok := request.getMatchPart(matcher.Baseline, data)
+		if !ok {
+			return false, []string{}
+		}
+		return matcher.ResultWithMatchedSnippet(matcher.MatchDifferential(itemStr, baseline))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
 	}
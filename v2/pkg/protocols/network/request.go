@@ -2,6 +2,7 @@ package network
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -24,10 +25,13 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/responsehighlighter"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/ja3"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/replacer"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/utils/vardump"
 	protocolutils "github.com/projectdiscovery/nuclei/v2/pkg/protocols/utils"
 	templateTypes "github.com/projectdiscovery/nuclei/v2/pkg/templates/types"
+	"github.com/projectdiscovery/nuclei/v2/pkg/utils"
 )
 
 var _ protocols.Request = &Request{}
@@ -53,13 +57,17 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata
 		return errors.Wrap(err, "could not get address from url")
 	}
 	variables := protocolutils.GenerateVariables(address, false, nil)
+	// the socket path is used as the hostname of unix socket inputs
+	if strings.HasPrefix(input.MetaInput.Input, "unix://") {
+		variables["Hostname"] = address
+	}
 	variablesMap := request.options.Variables.Evaluate(variables)
 	variables = generators.MergeMaps(variablesMap, variables)
 
 	for _, kv := range request.addresses {
 		actualAddress := replacer.Replace(kv.address, variables)
 
-		if err := request.executeAddress(variables, actualAddress, address, input.MetaInput.Input, kv.tls, previous, callback); err != nil {
+		if err := request.executeAddress(variables, actualAddress, address, input.MetaInput.Input, kv, previous, callback); err != nil {
 			gologger.Warning().Msgf("Could not make network request for %s: %s\n", actualAddress, err)
 			continue
 		}
@@ -68,11 +76,11 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata
 }
 
 // executeAddress executes the request for an address
-func (request *Request) executeAddress(variables map[string]interface{}, actualAddress, address, input string, shouldUseTLS bool, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+func (request *Request) executeAddress(variables map[string]interface{}, actualAddress, address, input string, kv addressKV, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
 	variables = generators.MergeMaps(variables, map[string]interface{}{"Hostname": address})
 	payloads := generators.BuildPayloadFromOptions(request.options.Options)
 
-	if !strings.Contains(actualAddress, ":") {
+	if _, port := utils.SplitHostPort(actualAddress); !kv.unix && port == "" {
 		err := errors.New("no port provided in network protocol request")
 		request.options.Output.Request(request.options.TemplatePath, address, request.Type().String(), err)
 		request.options.Progress.IncrementFailedRequestsBy(1)
@@ -88,33 +96,46 @@ func (request *Request) executeAddress(variables map[string]interface{}, actualA
 				break
 			}
 			value = generators.MergeMaps(value, payloads)
-			if err := request.executeRequestWithPayloads(variables, actualAddress, address, input, shouldUseTLS, value, previous, callback); err != nil {
+			if err := request.executeRequestWithPayloads(variables, actualAddress, address, input, kv, value, previous, callback); err != nil {
 				return err
 			}
 		}
 	} else {
 		value := maps.Clone(payloads)
-		if err := request.executeRequestWithPayloads(variables, actualAddress, address, input, shouldUseTLS, value, previous, callback); err != nil {
+		if err := request.executeRequestWithPayloads(variables, actualAddress, address, input, kv, value, previous, callback); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (request *Request) executeRequestWithPayloads(variables map[string]interface{}, actualAddress, address, input string, shouldUseTLS bool, payloads map[string]interface{}, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+func (request *Request) executeRequestWithPayloads(variables map[string]interface{}, actualAddress, address, input string, kv addressKV, payloads map[string]interface{}, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
 	var (
 		hostname string
 		conn     net.Conn
 		err      error
 	)
 
+	// Check if the request budget of the template for the host is exhausted
+	if !request.options.RequestBudget.Take(input) {
+		request.options.Progress.IncrementSkippedRequestsBy(1)
+		return nil
+	}
 	if host, _, splitErr := net.SplitHostPort(actualAddress); splitErr == nil {
 		hostname = host
 	}
 
-	if shouldUseTLS {
+	switch {
+	case kv.unix:
+		conn, err = dialUnix(actualAddress, request.options.Options.GetDialTimeout())
+	case kv.tls && request.ja3Spec != nil:
+		conn, err = request.dialJA3(actualAddress, hostname)
+	case kv.tls && protocolstate.HasSeparateTLSHandshake(request.options.Options):
+		tlsConfig := &tls.Config{ServerName: request.options.Options.SNI, InsecureSkipVerify: true, MinVersion: tls.VersionTLS10}
+		conn, err = protocolstate.DialTLS(context.Background(), request.dialer.Dial, "tcp", actualAddress, tlsConfig, request.options.Options)
+	case kv.tls:
 		conn, err = request.dialer.DialTLS(context.Background(), "tcp", actualAddress)
-	} else {
+	default:
 		conn, err = request.dialer.Dial(context.Background(), "tcp", actualAddress)
 	}
 	if err != nil {
@@ -123,7 +144,7 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		return errors.Wrap(err, "could not connect to server request")
 	}
 	defer conn.Close()
-	_ = conn.SetReadDeadline(time.Now().Add(time.Duration(request.options.Options.Timeout) * time.Second))
+	_ = conn.SetReadDeadline(time.Now().Add(request.options.Options.GetResponseReadTimeout()))
 
 	var interactshURLs []string
 
@@ -250,6 +271,11 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 				responseBuilder.Write(buf[:nBuf])
 				final = append(final, buf...)
 				n += nBuf
+				// stop reading once the response exceeds the maximum body size
+				if maxSize := request.options.MaxBodySize; maxSize > 0 && n > maxSize {
+					closeTimer(readInterval)
+					break readSocket
+				}
 			}
 		}
 	} else {
@@ -262,9 +288,17 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		responseBuilder.Write(final[:n])
 	}
 
+	data, truncated := request.options.TruncateBody(final[:n])
 	response := responseBuilder.String()
-	outputEvent := request.responseToDSLMap(reqBuilder.String(), string(final[:n]), response, input, actualAddress)
-	outputEvent["ip"] = request.dialer.GetDialedIP(hostname)
+	outputEvent := request.responseToDSLMap(reqBuilder.String(), string(data), response, input, actualAddress)
+	outputEvent["truncated"] = truncated
+	if !kv.unix {
+		outputEvent["ip"] = protocolstate.GetDialedIP(request.dialer, hostname)
+	}
+	if kv.tls && request.ja3 != "" {
+		outputEvent["ja3"] = request.ja3
+		outputEvent["ja3_hash"] = ja3.Hash(request.ja3)
+	}
 	if request.options.StopAtFirstMatch {
 		outputEvent["stop-at-first-match"] = true
 	}
@@ -338,13 +372,41 @@ func displayCompactHexView(event *output.InternalWrappedEvent, response string,
 	}
 }
 
-// getAddress returns the address of the host to make request to
+// dialJA3 connects to address over tls sending the client hello of the ja3 fingerprint
+func (request *Request) dialJA3(address, hostname string) (net.Conn, error) {
+	conn, err := request.dialer.Dial(context.Background(), "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	serverName := hostname
+	if request.options.Options.SNI != "" {
+		serverName = request.options.Options.SNI
+	}
+	tlsConn, err := ja3.Client(conn, request.ja3Spec, &tls.Config{ServerName: serverName, InsecureSkipVerify: true, MinVersion: tls.VersionTLS10}, nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_ = tlsConn.SetDeadline(time.Now().Add(request.options.Options.GetTLSHandshakeTimeout()))
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "could not perform ja3 tls handshake")
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+	return tlsConn, nil
+}
+
+// getAddress returns the address of the host to make request to.
+// For unix socket inputs the path of the socket is returned.
 func getAddress(toTest string) (string, error) {
 	if strings.Contains(toTest, "://") {
 		parsed, err := url.Parse(toTest)
 		if err != nil {
 			return "", err
 		}
+		if parsed.Scheme == "unix" {
+			return parsed.Path, nil
+		}
 		toTest = parsed.Host
 	}
 	return toTest, nil
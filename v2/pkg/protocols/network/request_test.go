@@ -3,10 +3,15 @@ package network
 import (
 	"encoding/hex"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -17,6 +22,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v2/pkg/output"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/requestbudget"
 	"github.com/projectdiscovery/nuclei/v2/pkg/testutils"
 )
 
@@ -108,6 +114,170 @@ func TestNetworkExecuteWithResults(t *testing.T) {
 	require.Equal(t, "<h1>Example Domain</h1>", finalEvent.Results[0].ExtractedResults[0], "could not get correct extracted results")
 }
 
+func TestNetworkExecuteUnixSocket(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-network-unix"
+	request := &Request{
+		ID:      templateID,
+		Address: []string{"unix://{{Hostname}}"},
+		Inputs:  []*Input{{Data: "PING\r\n"}},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Name:  "test",
+				Part:  "data",
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"+PONG"},
+			}},
+		},
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "nuclei.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.Nil(t, err, "could not listen on unix socket")
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			buffer := make([]byte, 6)
+			if _, err := io.ReadFull(conn, buffer); err == nil && string(buffer) == "PING\r\n" {
+				_, _ = conn.Write([]byte("+PONG\r\n"))
+			}
+			conn.Close()
+		}
+	}()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile network request")
+
+	var finalEvent *output.InternalWrappedEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput("unix://"+socketPath), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute network request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.Equal(t, 1, len(finalEvent.Results), "could not get correct number of results")
+	require.Equal(t, "test", finalEvent.Results[0].MatcherName, "could not get correct matcher name of results")
+
+	_, err = dialUnix(filepath.Join(t.TempDir(), "missing.sock"), time.Second)
+	require.ErrorContains(t, err, "does not exist", "could not get missing socket error")
+}
+
+func TestNetworkExecuteIPv6(t *testing.T) {
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("ipv6 loopback not available: %s", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			buffer := make([]byte, 6)
+			if _, err := io.ReadFull(conn, buffer); err == nil && string(buffer) == "PING\r\n" {
+				_, _ = conn.Write([]byte("+PONG\r\n"))
+			}
+			conn.Close()
+		}
+	}()
+
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-network-ipv6"
+	request := &Request{
+		ID:      templateID,
+		Address: []string{"{{Hostname}}"},
+		Inputs:  []*Input{{Data: "PING\r\n"}},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Name:  "test",
+				Part:  "data",
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"+PONG"},
+			}},
+		},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile network request")
+
+	address := listener.Addr().String()
+	for _, input := range []string{address, "tcp://" + address} {
+		var finalEvent *output.InternalWrappedEvent
+		err = request.ExecuteWithResults(contextargs.NewWithInput(input), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			finalEvent = event
+		})
+		require.Nil(t, err, "could not execute network request")
+		require.NotNil(t, finalEvent, "could not get event output from request for %s", input)
+		require.Equal(t, 1, len(finalEvent.Results), "could not get correct number of results")
+		require.Equal(t, address, finalEvent.Results[0].Matched, "wrong matched address")
+		require.Equal(t, "::1", finalEvent.Results[0].IP, "wrong dialed ip")
+	}
+
+	var finalEvent *output.InternalWrappedEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput("[::1]"), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute network request")
+	require.Nil(t, finalEvent, "request sent to an ipv6 address without port")
+}
+
+func TestNetworkExecuteRequestBudget(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	defer listener.Close()
+	var connections atomic.Int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			connections.Add(1)
+			_, _ = conn.Write([]byte("+PONG\r\n"))
+			conn.Close()
+		}
+	}()
+
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-network-budget"
+	request := &Request{
+		ID:       templateID,
+		Address:  []string{"{{Hostname}}"},
+		ReadSize: 7,
+		Inputs:   []*Input{{Data: "PING\r\n"}},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.RequestBudget = requestbudget.New(templateID, 2)
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile network request")
+
+	for i := 0; i < 4; i++ {
+		err = request.ExecuteWithResults(contextargs.NewWithInput(listener.Addr().String()), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {})
+		require.Nil(t, err, "could not execute network request")
+	}
+	require.Equal(t, int32(2), connections.Load(), "requests sent beyond the budget")
+}
+
 var exampleBody = `<!doctype html>
 <html>
 <head>
@@ -0,0 +1,28 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// dialUnix connects to the unix domain socket at path
+func dialUnix(path string, timeout time.Duration) (net.Conn, error) {
+	conn, err := net.DialTimeout("unix", path, timeout)
+	if err == nil {
+		return conn, nil
+	}
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("unix socket %s does not exist", path)
+	case errors.Is(err, os.ErrPermission):
+		return nil, fmt.Errorf("permission denied for unix socket %s, check the permissions of the socket", path)
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return nil, fmt.Errorf("connection refused by unix socket %s, no process is listening on it", path)
+	}
+	return nil, err
+}
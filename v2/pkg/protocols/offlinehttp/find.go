@@ -47,8 +47,8 @@ func (request *Request) findGlobPathMatches(absPath string, processed map[string
 		return errors.Errorf("wildcard found, but unable to glob: %s\n", err)
 	}
 	for _, match := range matches {
-		if filepath.Ext(match) != ".txt" {
-			continue // only process .txt files
+		if !isResponseFile(match) {
+			continue // only process .txt and .har files
 		}
 		if _, ok := processed[match]; !ok {
 			processed[match] = struct{}{}
@@ -68,8 +68,8 @@ func (request *Request) findFileMatches(absPath string, processed map[string]str
 	if !info.Mode().IsRegular() {
 		return false, nil
 	}
-	if filepath.Ext(absPath) != ".txt" {
-		return false, nil // only process .txt files
+	if !isResponseFile(absPath) {
+		return false, nil // only process .txt and .har files
 	}
 	if _, ok := processed[absPath]; !ok {
 		processed[absPath] = struct{}{}
@@ -90,8 +90,8 @@ func (request *Request) findDirectoryMatches(absPath string, processed map[strin
 			if d.IsDir() {
 				return nil
 			}
-			if filepath.Ext(p) != ".txt" {
-				return nil // only process .txt files
+			if !isResponseFile(p) {
+				return nil // only process .txt and .har files
 			}
 			if _, ok := processed[p]; !ok {
 				callback(p)
@@ -102,3 +102,13 @@ func (request *Request) findDirectoryMatches(absPath string, processed map[strin
 	)
 	return err
 }
+
+// isResponseFile returns true if the path is a raw response or a har file
+func isResponseFile(path string) bool {
+	return filepath.Ext(path) == ".txt" || isHARFile(path)
+}
+
+// isHARFile returns true if the path is a http archive file
+func isHARFile(path string) bool {
+	return filepath.Ext(path) == ".har"
+}
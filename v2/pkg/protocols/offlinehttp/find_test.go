@@ -37,12 +37,13 @@ func TestFindResponses(t *testing.T) {
 		"final.txt":         "TEST",
 		"image_ignored.png": "TEST",
 		"test.txt":          "TEST",
+		"traffic.har":       "TEST",
 	}
 	for k, v := range files {
 		err = os.WriteFile(filepath.Join(tempDir, k), []byte(v), os.ModePerm)
 		require.Nil(t, err, "could not write temporary file")
 	}
-	expected := []string{"config.txt", "final.txt", "test.txt"}
+	expected := []string{"config.txt", "final.txt", "test.txt", "traffic.har"}
 	got := []string{}
 	err = request.getInputPaths(tempDir+"/*", func(item string) {
 		base := filepath.Base(item)
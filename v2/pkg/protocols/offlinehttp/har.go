@@ -0,0 +1,142 @@
+package offlinehttp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// harFile is a http archive containing the traffic captured by a browser or proxy
+type harFile struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+// harEntry is a request and its response in a http archive
+type harEntry struct {
+	Request  harRequest  `json:"request"`
+	Response harResponse `json:"response"`
+}
+
+type harRequest struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []harHeader `json:"headers"`
+	PostData    *struct {
+		Text string `json:"text"`
+	} `json:"postData"`
+}
+
+type harResponse struct {
+	Status     int         `json:"status"`
+	StatusText string      `json:"statusText"`
+	Headers    []harHeader `json:"headers"`
+	Content    struct {
+		Text     string `json:"text"`
+		Encoding string `json:"encoding"`
+	} `json:"content"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// harExchange is a raw request and response read from a http archive entry
+type harExchange struct {
+	URL         string
+	RawRequest  string
+	RawResponse string
+}
+
+// readHARExchanges reads the raw requests and responses of the entries of a http archive.
+//
+// Entries without a response, such as blocked or aborted requests, are skipped.
+func readHARExchanges(data []byte) ([]harExchange, error) {
+	har := &harFile{}
+	if err := json.Unmarshal(data, har); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal har")
+	}
+	exchanges := make([]harExchange, 0, len(har.Log.Entries))
+	for _, entry := range har.Log.Entries {
+		if entry.Response.Status == 0 {
+			continue
+		}
+		rawResponse, err := entry.Response.raw()
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read response for %s", entry.Request.URL)
+		}
+		exchanges = append(exchanges, harExchange{URL: entry.Request.URL, RawRequest: entry.Request.raw(), RawResponse: rawResponse})
+	}
+	return exchanges, nil
+}
+
+// raw returns the raw http request of a http archive request
+func (request *harRequest) raw() string {
+	builder := &strings.Builder{}
+	httpVersion := request.HTTPVersion
+	if httpVersion == "" {
+		httpVersion = "HTTP/1.1"
+	}
+	fmt.Fprintf(builder, "%s %s %s\r\n", request.Method, request.URL, httpVersion)
+	writeHARHeaders(builder, request.Headers)
+	builder.WriteString("\r\n")
+	if request.PostData != nil {
+		builder.WriteString(request.PostData.Text)
+	}
+	return builder.String()
+}
+
+// raw returns the raw http response of a http archive response.
+//
+// The content of archived responses is already decoded, so the headers
+// describing the transfer of the body are not included in the response.
+func (response *harResponse) raw() (string, error) {
+	body := response.Content.Text
+	if response.Content.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return "", errors.Wrap(err, "could not decode content")
+		}
+		body = string(decoded)
+	}
+	statusText := response.StatusText
+	if statusText == "" {
+		statusText = http.StatusText(response.Status)
+	}
+
+	builder := &strings.Builder{}
+	// http/2 and http/3 responses are written as http/1.1 as they can't be read otherwise
+	fmt.Fprintf(builder, "HTTP/1.1 %d %s\r\n", response.Status, statusText)
+	headers := make([]harHeader, 0, len(response.Headers))
+	for _, header := range response.Headers {
+		switch strings.ToLower(header.Name) {
+		case "content-length", "content-encoding", "transfer-encoding":
+			continue
+		}
+		headers = append(headers, header)
+	}
+	writeHARHeaders(builder, headers)
+	builder.WriteString("\r\n")
+	builder.WriteString(body)
+	return builder.String(), nil
+}
+
+// writeHARHeaders writes http archive headers skipping http/2 pseudo headers
+func writeHARHeaders(builder *strings.Builder, headers []harHeader) {
+	for _, header := range headers {
+		if strings.HasPrefix(header.Name, ":") {
+			continue
+		}
+		builder.WriteString(header.Name)
+		builder.WriteString(": ")
+		builder.WriteString(header.Value)
+		builder.WriteString("\r\n")
+	}
+}
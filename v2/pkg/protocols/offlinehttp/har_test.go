@@ -0,0 +1,57 @@
+package offlinehttp
+
+import (
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadHARExchanges(t *testing.T) {
+	har := `{
+  "log": {
+    "entries": [
+      {
+        "request": {
+          "method": "POST",
+          "url": "https://example.com/login",
+          "httpVersion": "h2",
+          "headers": [{"name": ":authority", "value": "example.com"}, {"name": "Content-Type", "value": "application/json"}],
+          "postData": {"text": "{\"user\":\"admin\"}"}
+        },
+        "response": {
+          "status": 200,
+          "statusText": "",
+          "headers": [{"name": "content-encoding", "value": "gzip"}, {"name": "content-length", "value": "10"}, {"name": "Server", "value": "nginx"}],
+          "content": {"text": "` + base64.StdEncoding.EncodeToString([]byte("welcome admin")) + `", "encoding": "base64"}
+        }
+      },
+      {
+        "request": {"method": "GET", "url": "https://example.com/blocked", "headers": []},
+        "response": {"status": 0, "headers": [], "content": {}}
+      }
+    ]
+  }
+}`
+
+	exchanges, err := readHARExchanges([]byte(har))
+	require.Nil(t, err, "could not read har")
+	require.Len(t, exchanges, 1, "could not skip entries without response")
+
+	exchange := exchanges[0]
+	require.Equal(t, "https://example.com/login", exchange.URL, "could not get entry url")
+	require.Equal(t, "POST https://example.com/login h2\r\nContent-Type: application/json\r\n\r\n{\"user\":\"admin\"}", exchange.RawRequest, "could not get raw request")
+
+	resp, err := readResponseFromString(exchange.RawResponse)
+	require.Nil(t, err, "could not read raw response")
+	require.Equal(t, 200, resp.StatusCode, "could not get status code")
+	require.Equal(t, "nginx", resp.Header.Get("Server"), "could not get header")
+	require.Empty(t, resp.Header.Get("Content-Encoding"), "could not remove content encoding")
+	body, err := io.ReadAll(resp.Body)
+	require.Nil(t, err, "could not read body")
+	require.Equal(t, "welcome admin", string(body), "could not decode body")
+
+	_, err = readHARExchanges([]byte("not a har"))
+	require.NotNil(t, err, "could not detect invalid har")
+}
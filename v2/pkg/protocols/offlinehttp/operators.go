@@ -38,6 +38,16 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchRegex(item))
 	case matchers.BinaryMatcher:
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(item))
+	case matchers.JSONSchemaMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchJSONSchema(item))
+	case matchers.DifferentialMatcher:
+		baseline, ok := getMatchPart(matcher.Baseline, data)
+		if !ok {
+			return false, []string{}
+		}
+		return matcher.ResultWithMatchedSnippet(matcher.MatchDifferential(item, baseline))
+	case matchers.StructureMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchStructure(item))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
 	}
@@ -67,6 +77,8 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractRegex(item)
 	case extractors.KValExtractor:
 		return extractor.ExtractKval(data)
+	case extractors.StructureExtractor:
+		return extractor.ExtractStructure(item)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
 	}
@@ -21,7 +21,11 @@ import (
 
 var _ protocols.Request = &Request{}
 
-const maxSize = 5 * 1024 * 1024
+const (
+	maxSize = 5 * 1024 * 1024
+	// maxHARSize is the max size of har files which contain many responses
+	maxHARSize = 100 * 1024 * 1024
+)
 
 // Type returns the type of the protocol request
 func (request *Request) Type() templateTypes.ProtocolType {
@@ -50,7 +54,11 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata
 				gologger.Error().Msgf("Could not stat file path %s: %s\n", data, err)
 				return
 			}
-			if stat.Size() >= int64(maxSize) {
+			limit := maxSize
+			if isHARFile(data) {
+				limit = maxHARSize
+			}
+			if stat.Size() >= int64(limit) {
 				gologger.Verbose().Msgf("Could not process path %s: exceeded max size\n", data)
 				return
 			}
@@ -60,40 +68,20 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata
 				gologger.Error().Msgf("Could not read file path %s: %s\n", data, err)
 				return
 			}
-			dataStr := tostring.UnsafeToString(buffer)
-
-			resp, err := readResponseFromString(dataStr)
-			if err != nil {
-				gologger.Error().Msgf("Could not read raw response %s: %s\n", data, err)
-				return
-			}
-
-			if request.options.Options.Debug || request.options.Options.DebugRequests {
-				gologger.Info().Msgf("[%s] Dumped offline-http request for %s", request.options.TemplateID, data)
-				gologger.Print().Msgf("%s", dataStr)
-			}
-			gologger.Verbose().Msgf("[%s] Sent OFFLINE-HTTP request to %s", request.options.TemplateID, data)
 
-			dumpedResponse, err := httputil.DumpResponse(resp, true)
-			if err != nil {
-				gologger.Error().Msgf("Could not dump raw http response %s: %s\n", data, err)
+			if !isHARFile(data) {
+				dataStr := tostring.UnsafeToString(buffer)
+				request.processResponse(data, data, data, dataStr, previous, callback)
 				return
 			}
-
-			body, err := io.ReadAll(resp.Body)
+			exchanges, err := readHARExchanges(buffer)
 			if err != nil {
-				gologger.Error().Msgf("Could not read raw http response body %s: %s\n", data, err)
+				gologger.Error().Msgf("Could not read har file %s: %s\n", data, err)
 				return
 			}
-
-			outputEvent := request.responseToDSLMap(resp, data, data, data, tostring.UnsafeToString(dumpedResponse), tostring.UnsafeToString(body), headersToString(resp.Header), 0, nil)
-			outputEvent["ip"] = ""
-			for k, v := range previous {
-				outputEvent[k] = v
+			for _, exchange := range exchanges {
+				request.processResponse(data, exchange.URL, exchange.RawRequest, exchange.RawResponse, previous, callback)
 			}
-
-			event := eventcreator.CreateEvent(request, outputEvent, request.options.Options.Debug || request.options.Options.DebugResponse)
-			callback(event)
 		}(data)
 	})
 	wg.Wait()
@@ -106,6 +94,43 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata
 	return nil
 }
 
+// processResponse matches a raw response read from the file at path
+// reporting the results with the matched value specified.
+func (request *Request) processResponse(path, matched, rawRequest, rawResponse string, previous output.InternalEvent, callback protocols.OutputEventCallback) {
+	resp, err := readResponseFromString(rawResponse)
+	if err != nil {
+		gologger.Error().Msgf("Could not read raw response %s: %s\n", matched, err)
+		return
+	}
+
+	if request.options.Options.Debug || request.options.Options.DebugRequests {
+		gologger.Info().Msgf("[%s] Dumped offline-http request for %s", request.options.TemplateID, matched)
+		gologger.Print().Msgf("%s", rawResponse)
+	}
+	gologger.Verbose().Msgf("[%s] Sent OFFLINE-HTTP request to %s", request.options.TemplateID, matched)
+
+	dumpedResponse, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		gologger.Error().Msgf("Could not dump raw http response %s: %s\n", matched, err)
+		return
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		gologger.Error().Msgf("Could not read raw http response body %s: %s\n", matched, err)
+		return
+	}
+
+	outputEvent := request.responseToDSLMap(resp, path, matched, rawRequest, tostring.UnsafeToString(dumpedResponse), tostring.UnsafeToString(body), headersToString(resp.Header), 0, nil)
+	outputEvent["ip"] = ""
+	for k, v := range previous {
+		outputEvent[k] = v
+	}
+
+	event := eventcreator.CreateEvent(request, outputEvent, request.options.Options.Debug || request.options.Options.DebugResponse)
+	callback(event)
+}
+
 // headersToString converts http headers to string
 func headersToString(headers http.Header) string {
 	builder := &strings.Builder{}
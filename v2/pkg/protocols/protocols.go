@@ -15,7 +15,9 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/progress"
 	"github.com/projectdiscovery/nuclei/v2/pkg/projectfile"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/hostbreaker"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/hosterrorscache"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/requestbudget"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/utils/excludematchers"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/variables"
@@ -65,6 +67,14 @@ type ExecuterOptions struct {
 	Interactsh *interactsh.Client
 	// HostErrorsCache is an optional cache for handling host errors
 	HostErrorsCache hosterrorscache.CacheInterface
+	// RequestBudget is an optional per host request budget for the template
+	RequestBudget *requestbudget.Budget
+	// RequestBudgets is an optional tracker of the request budgets of the templates
+	RequestBudgets *requestbudget.Tracker
+	// HostBreaker is an optional per host retry backoff and circuit breaker for http requests
+	HostBreaker *hostbreaker.Breaker
+	// MaxBodySize is the maximum size of response bodies after decompression, unlimited if not positive
+	MaxBodySize int
 	// Stop execution once first match is found (Assigned while parsing templates)
 	// Note: this is different from Options.StopAtFirstMatch (Assigned from CLI option)
 	StopAtFirstMatch bool
@@ -76,6 +86,8 @@ type ExecuterOptions struct {
 	InputHelper *input.Helper
 
 	Operators []*operators.Operators // only used by offlinehttp module
+	// FlowOperators are the operators evaluated on the responses of all the requests of the template
+	FlowOperators *operators.Operators
 
 	// DoNotCache bool disables optional caching of the templates structure
 	DoNotCache bool
@@ -91,6 +103,15 @@ func (e ExecuterOptions) Copy() ExecuterOptions {
 	return copy
 }
 
+// TruncateBody truncates a response body to the maximum body size,
+// returning true if the body was truncated.
+func (e *ExecuterOptions) TruncateBody(body []byte) ([]byte, bool) {
+	if e.MaxBodySize <= 0 || len(body) <= e.MaxBodySize {
+		return body, false
+	}
+	return body[:e.MaxBodySize], true
+}
+
 // Request is an interface implemented any protocol based request generator.
 type Request interface {
 	// Compile compiles the request generators preparing any requests possible.
@@ -147,6 +168,11 @@ func MakeDefaultResultEvent(request Request, wrapped *output.InternalWrappedEven
 		data := request.MakeResultEventItem(wrapped)
 		results = append(results, data)
 	}
+	for _, result := range results {
+		result.ValidationErrors = wrapped.OperatorsResult.ValidationErrors
+		result.Deltas = wrapped.OperatorsResult.Deltas
+		result.Truncated, _ = wrapped.InternalEvent["truncated"].(bool)
+	}
 	return results
 }
 
@@ -172,6 +198,8 @@ func MakeDefaultExtractFunc(data map[string]interface{}, extractor *extractors.E
 		return extractor.ExtractJSON(itemStr)
 	case extractors.XPathExtractor:
 		return extractor.ExtractXPath(itemStr)
+	case extractors.StructureExtractor:
+		return extractor.ExtractStructure(itemStr)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
 	}
@@ -201,6 +229,16 @@ func MakeDefaultMatchFunc(data map[string]interface{}, matcher *matchers.Matcher
 		return matcher.ResultWithMatchedSnippet(matcher.MatchRegex(item))
 	case matchers.BinaryMatcher:
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(item))
+	case matchers.JSONSchemaMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchJSONSchema(item))
+	case matchers.DifferentialMatcher:
+		baseline, ok := data[matcher.Baseline]
+		if !ok {
+			return false, nil
+		}
+		return matcher.ResultWithMatchedSnippet(matcher.MatchDifferential(item, types.ToString(baseline)))
+	case matchers.StructureMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchStructure(item))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), nil
 	}
@@ -0,0 +1,323 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// recordHeaderLength is the length of the header of a tls record
+	recordHeaderLength = 5
+	// maxRecordedLength is the maximum number of bytes recorded on a connection
+	maxRecordedLength = 64 * 1024
+	// ticketReadTimeout is the time waited for the tls 1.3 tickets sent after the handshake
+	ticketReadTimeout = time.Second
+
+	recordTypeChangeCipherSpec = 20
+	recordTypeHandshake        = 22
+
+	handshakeTypeClientHello      = 1
+	handshakeTypeServerHello      = 2
+	handshakeTypeNewSessionTicket = 4
+)
+
+// Session resumption mechanisms
+const (
+	resumptionTicket    = "ticket"
+	resumptionSessionID = "session-id"
+)
+
+// sessionResumption is the session resumption behavior of a server
+type sessionResumption struct {
+	// Resumed is true if the server resumed the session of a previous handshake
+	Resumed bool
+	// Mechanism is the mechanism of the resumed session (ticket or session-id)
+	Mechanism string
+	// TicketIssued is true if the server issued a session ticket
+	TicketIssued bool
+	// TicketLifetime is the lifetime hint of the ticket in seconds, 0 if unknown
+	// as tls 1.3 tickets are encrypted
+	TicketLifetime int
+	// SessionIDIssued is true if the server issued a tls 1.2 (or older) session id
+	SessionIDIssued bool
+}
+
+// values returns the session resumption variables of the result
+func (r *sessionResumption) values() map[string]interface{} {
+	values := map[string]interface{}{
+		"session_resumed":              r.Resumed,
+		"session_resumption_mechanism": r.Mechanism,
+		"session_ticket_issued":        r.TicketIssued,
+		"session_id_issued":            r.SessionIDIssued,
+	}
+	if r.TicketLifetime > 0 {
+		values["session_ticket_lifetime"] = r.TicketLifetime
+	}
+	return values
+}
+
+// recordingConn records the bytes written to and read from a connection
+type recordingConn struct {
+	net.Conn
+	written bytes.Buffer
+	read    bytes.Buffer
+}
+
+func (c *recordingConn) Write(b []byte) (int, error) {
+	if c.written.Len() < maxRecordedLength {
+		c.written.Write(b)
+	}
+	return c.Conn.Write(b)
+}
+
+func (c *recordingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 && c.read.Len() < maxRecordedLength {
+		c.read.Write(b[:n])
+	}
+	return n, err
+}
+
+// recordingSessionCache is a session cache recording whether a session was stored
+type recordingSessionCache struct {
+	tls.ClientSessionCache
+	mutex  sync.Mutex
+	stored bool
+}
+
+func (c *recordingSessionCache) Put(sessionKey string, cs *tls.ClientSessionState) {
+	if cs != nil {
+		c.mutex.Lock()
+		c.stored = true
+		c.mutex.Unlock()
+	}
+	c.ClientSessionCache.Put(sessionKey, cs)
+}
+
+// Stored returns true if a session was stored in the cache
+func (c *recordingSessionCache) Stored() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.stored
+}
+
+// probeSessionResumption performs successive handshakes with a server to detect
+// whether it resumes the sessions of the previous handshakes, with session
+// tickets or tls 1.2 session ids.
+//
+// Session ids are probed by replaying the client hello of the first handshake
+// with the session id issued by the server, as the go tls client only resumes
+// sessions with tickets, the server resuming the session if it echoes the id and
+// changes cipher spec right after its server hello.
+func probeSessionResumption(dial func() (net.Conn, error), serverName string, timeout time.Duration) (*sessionResumption, error) {
+	cache := &recordingSessionCache{ClientSessionCache: tls.NewLRUClientSessionCache(1)}
+	config := &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+		ClientSessionCache: cache,
+	}
+
+	first, state, err := resumptionHandshake(dial, config, timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not perform first handshake")
+	}
+	result := &sessionResumption{}
+	clientHello, clientID := parseClientHello(first.written.Bytes())
+	serverID, lifetime := parseServerHandshake(first.read.Bytes())
+	result.TicketLifetime = lifetime
+	result.TicketIssued = cache.Stored()
+	// a server echoing the random session id of the client doesn't issue one
+	result.SessionIDIssued = state.Version <= tls.VersionTLS12 && len(serverID) > 0 && !bytes.Equal(serverID, clientID)
+
+	if result.TicketIssued {
+		_, state, err := resumptionHandshake(dial, config, timeout)
+		if err == nil && state.DidResume {
+			result.Resumed = true
+			result.Mechanism = resumptionTicket
+			return result, nil
+		}
+	}
+	if result.SessionIDIssued && clientHello != nil {
+		replayed := replaceClientHelloSessionID(clientHello, serverID)
+		if replayed == nil {
+			return result, nil
+		}
+		resumed, err := replayClientHello(dial, replayed, serverID, timeout)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not replay client hello")
+		}
+		if resumed {
+			result.Resumed = true
+			result.Mechanism = resumptionSessionID
+		}
+	}
+	return result, nil
+}
+
+// resumptionHandshake performs a recorded handshake, reading the tls 1.3 tickets sent after it
+func resumptionHandshake(dial func() (net.Conn, error), config *tls.Config, timeout time.Duration) (*recordingConn, tls.ConnectionState, error) {
+	conn, err := dial()
+	if err != nil {
+		return nil, tls.ConnectionState{}, err
+	}
+	recorder := &recordingConn{Conn: conn}
+	client := tls.Client(recorder, config)
+	defer client.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if err := client.Handshake(); err != nil {
+		return recorder, tls.ConnectionState{}, err
+	}
+	state := client.ConnectionState()
+	if state.Version == tls.VersionTLS13 {
+		// the tickets are processed by the client while reading
+		_ = conn.SetReadDeadline(time.Now().Add(ticketReadTimeout))
+		_, _ = client.Read(make([]byte, 1))
+	}
+	return recorder, state, nil
+}
+
+// replayClientHello sends a client hello record and returns true if the server
+// resumes the session with the id, answering with a server hello echoing it
+// followed by a change cipher spec instead of its certificate.
+func replayClientHello(dial func() (net.Conn, error), clientHello, sessionID []byte, timeout time.Duration) (bool, error) {
+	conn, err := dial()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(clientHello); err != nil {
+		return false, err
+	}
+	var received bytes.Buffer
+	buffer := make([]byte, 4096)
+	for received.Len() < maxRecordedLength {
+		n, err := conn.Read(buffer)
+		received.Write(buffer[:n])
+		messages, changeCipherSpec := plaintextHandshakeMessages(received.Bytes())
+		if len(messages) > 0 && messages[0][0] == handshakeTypeServerHello {
+			if !bytes.Equal(helloSessionID(messages[0]), sessionID) {
+				return false, nil
+			}
+			if changeCipherSpec {
+				return len(messages) == 1, nil
+			}
+			if len(messages) > 1 {
+				return false, nil
+			}
+		}
+		if err != nil {
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+// plaintextHandshakeMessages returns the complete handshake messages of a tls
+// record stream sent before the first change cipher spec, which is reported.
+func plaintextHandshakeMessages(data []byte) ([][]byte, bool) {
+	var handshake []byte
+	changeCipherSpec := false
+	for len(data) >= recordHeaderLength {
+		length := int(binary.BigEndian.Uint16(data[3:5]))
+		if len(data) < recordHeaderLength+length {
+			break
+		}
+		recordType := data[0]
+		if recordType == recordTypeChangeCipherSpec {
+			changeCipherSpec = true
+			break
+		}
+		if recordType != recordTypeHandshake {
+			break
+		}
+		handshake = append(handshake, data[recordHeaderLength:recordHeaderLength+length]...)
+		data = data[recordHeaderLength+length:]
+	}
+
+	var messages [][]byte
+	for len(handshake) >= 4 {
+		length := int(handshake[1])<<16 | int(handshake[2])<<8 | int(handshake[3])
+		if len(handshake) < 4+length {
+			break
+		}
+		messages = append(messages, handshake[:4+length])
+		handshake = handshake[4+length:]
+	}
+	return messages, changeCipherSpec
+}
+
+// parseServerHandshake returns the session id of the server hello and the
+// lifetime hint of the tls 1.2 session ticket of a server record stream
+func parseServerHandshake(data []byte) ([]byte, int) {
+	var sessionID []byte
+	var lifetime int
+	messages, _ := plaintextHandshakeMessages(data)
+	for _, message := range messages {
+		switch message[0] {
+		case handshakeTypeServerHello:
+			sessionID = helloSessionID(message)
+		case handshakeTypeNewSessionTicket:
+			// tls 1.2 tickets are sent before the server changes cipher spec
+			if len(message) >= 8 {
+				lifetime = int(binary.BigEndian.Uint32(message[4:8]))
+			}
+		}
+	}
+	return sessionID, lifetime
+}
+
+// parseClientHello returns the first record of a client hello record stream
+// along with the session id of the client hello
+func parseClientHello(data []byte) ([]byte, []byte) {
+	if len(data) < recordHeaderLength || data[0] != recordTypeHandshake {
+		return nil, nil
+	}
+	length := int(binary.BigEndian.Uint16(data[3:5]))
+	if len(data) < recordHeaderLength+length {
+		return nil, nil
+	}
+	record := data[:recordHeaderLength+length]
+	messages, _ := plaintextHandshakeMessages(record)
+	if len(messages) == 0 || messages[0][0] != handshakeTypeClientHello {
+		return nil, nil
+	}
+	return record, helloSessionID(messages[0])
+}
+
+// helloSessionID returns the session id of a client or server hello message,
+// made of a header, the version, the random and the session id
+func helloSessionID(message []byte) []byte {
+	const sessionIDOffset = 4 + 2 + 32
+	if len(message) <= sessionIDOffset {
+		return nil
+	}
+	length := int(message[sessionIDOffset])
+	if len(message) < sessionIDOffset+1+length {
+		return nil
+	}
+	return message[sessionIDOffset+1 : sessionIDOffset+1+length]
+}
+
+// replaceClientHelloSessionID returns a copy of a client hello record with the
+// session id replaced, or nil if the ids don't have the same length
+func replaceClientHelloSessionID(record, sessionID []byte) []byte {
+	const sessionIDOffset = recordHeaderLength + 4 + 2 + 32
+	if len(record) <= sessionIDOffset || int(record[sessionIDOffset]) != len(sessionID) || len(record) < sessionIDOffset+1+len(sessionID) {
+		return nil
+	}
+	replaced := make([]byte, len(record))
+	copy(replaced, record)
+	copy(replaced[sessionIDOffset+1:], sessionID)
+	return replaced
+}
@@ -1,7 +1,10 @@
 package ssl
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
+	"math"
 	"net"
 	"time"
 
@@ -22,12 +25,15 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/ja3"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/utils/vardump"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/network/networkclientpool"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/utils"
 	protocolutils "github.com/projectdiscovery/nuclei/v2/pkg/protocols/utils"
 	templateTypes "github.com/projectdiscovery/nuclei/v2/pkg/templates/types"
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	nucleiutils "github.com/projectdiscovery/nuclei/v2/pkg/utils"
 	"github.com/projectdiscovery/tlsx/pkg/tlsx"
 	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
 	"github.com/projectdiscovery/tlsx/pkg/tlsx/openssl"
@@ -74,19 +80,41 @@ type Request struct {
 	//   - "auto"
 	//	 - "openssl" # reverts to "auto" is openssl is not installed
 	ScanMode string `yaml:"scan_mode,omitempty" json:"scan_mode,omitempty" jsonschema:"title=Scan Mode,description=Scan Mode - auto if not specified.,enum=ctls,enum=ztls,enum=auto"`
+	// description: |
+	//   SessionResumption probes whether the server resumes tls sessions.
+	//
+	//   Additional handshakes are performed with the session ticket or the
+	//   session id issued on the first one, setting session_resumed along with
+	//   the session_resumption_mechanism (ticket or session-id), session_ticket_issued,
+	//   session_ticket_lifetime (in seconds, tls 1.2 tickets only) and session_id_issued.
+	SessionResumption bool `yaml:"session_resumption,omitempty" json:"session_resumption,omitempty" jsonschema:"title=probe tls session resumption,description=Probes whether the server resumes tls sessions"`
+	// description: |
+	//   JA3 is the ja3 fingerprint to use for the client hello of the handshake.
+	//
+	//   Overrides the global ja3 option. The handshake is then made with the spoofed
+	//   client hello instead of the scan mode, and the ja3 and ja3_hash of the client
+	//   hello sent are available in the output.
+	// examples:
+	//   - value: "\"771,4865-4866-4867-49195-49199,0-23-65281-10-11-35-16-5-13-18-51-45-43-27-21,29-23-24,0\""
+	JA3 string `yaml:"ja3,omitempty" json:"ja3,omitempty" jsonschema:"title=ja3 fingerprint of the client hello,description=JA3 fingerprint to use for the client hello of the handshake"`
 
 	// cache any variables that may be needed for operation.
-	dialer  *fastdialer.Dialer
-	tlsx    *tlsx.Service
-	options *protocols.ExecuterOptions
+	dialer      *fastdialer.Dialer
+	tlsx        *tlsx.Service
+	tlsxOptions *clients.Options
+	options     *protocols.ExecuterOptions
+	// ja3Spec is the parsed ja3 fingerprint for the handshake, if any
+	ja3Spec *ja3.Spec
+	// ja3 is the ja3 fingerprint of the client hello sent
+	ja3 string
 }
 
 // CanCluster returns true if the request can be clustered.
 func (request *Request) CanCluster(other *Request) bool {
-	if len(request.CipherSuites) > 0 || request.MinVersion != "" || request.MaxVersion != "" {
+	if len(request.CipherSuites) > 0 || request.MinVersion != "" || request.MaxVersion != "" || request.JA3 != "" || other.JA3 != "" {
 		return false
 	}
-	if request.Address != other.Address || request.ScanMode != other.ScanMode {
+	if request.Address != other.Address || request.ScanMode != other.ScanMode || request.SessionResumption != other.SessionResumption {
 		return false
 	}
 	return true
@@ -130,7 +158,7 @@ func (request *Request) Compile(options *protocols.ExecuterOptions) error {
 		Ciphers:           request.CipherSuites,
 		WildcardCertCheck: true,
 		Retries:           request.options.Options.Retries,
-		Timeout:           request.options.Options.Timeout,
+		Timeout:           int(math.Ceil(options.Options.GetConnectTimeout().Seconds())),
 		Fastdialer:        client,
 		ClientHello:       true,
 		ServerHello:       true,
@@ -141,6 +169,19 @@ func (request *Request) Compile(options *protocols.ExecuterOptions) error {
 		return errorutil.NewWithTag(request.TemplateID, "could not create tlsx service")
 	}
 	request.tlsx = tlsxService
+	request.tlsxOptions = tlsxOptions
+
+	if ja3Value := request.JA3; ja3Value != "" || options.Options.JA3 != "" {
+		if ja3Value == "" {
+			ja3Value = options.Options.JA3
+		}
+		if request.ja3Spec, err = ja3.Parse(ja3Value); err != nil {
+			return errorutil.NewWithTag(request.TemplateID, "could not parse ja3").Wrap(err)
+		}
+		if request.ja3, err = ja3.Fingerprint(request.ja3Spec); err != nil {
+			return errorutil.NewWithTag(request.TemplateID, "could not build ja3 client hello").Wrap(err)
+		}
+	}
 
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
@@ -215,7 +256,17 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		hostIp = host
 	}
 
-	response, err := request.tlsx.Connect(host, hostIp, port)
+	// Check if the request budget of the template for the host is exhausted
+	if !requestOptions.RequestBudget.Take(input.MetaInput.Input) {
+		requestOptions.Progress.IncrementSkippedRequestsBy(1)
+		return nil
+	}
+	var response *clients.Response
+	if request.ja3Spec != nil {
+		response, err = request.connectJA3(host, hostIp, port)
+	} else {
+		response, err = request.tlsx.Connect(host, hostIp, port)
+	}
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
@@ -249,7 +300,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 	if input.MetaInput.CustomIP != "" {
 		data["ip"] = hostIp
 	} else {
-		data["ip"] = request.dialer.GetDialedIP(hostname)
+		data["ip"] = protocolstate.GetDialedIP(request.dialer, hostname)
 	}
 	data["template-path"] = requestOptions.TemplatePath
 	data["template-id"] = requestOptions.TemplateID
@@ -291,6 +342,28 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		}
 		data[tag] = f.Value()
 	}
+	for k, v := range certificateExpiryValues(response.CertificateResponse, time.Now()) {
+		data[k] = v
+	}
+	if request.ja3 != "" {
+		data["ja3"] = request.ja3
+		data["ja3_hash"] = ja3.Hash(request.ja3)
+	}
+	if request.SessionResumption {
+		dial := func() (net.Conn, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), requestOptions.Options.GetConnectTimeout())
+			defer cancel()
+			return request.dialer.Dial(ctx, "tcp", net.JoinHostPort(hostIp, port))
+		}
+		resumption, err := probeSessionResumption(dial, host, requestOptions.Options.GetConnectTimeout())
+		if err != nil {
+			gologger.Verbose().Msgf("[%s] Could not probe session resumption for %s: %s", requestOptions.TemplateID, addressToDial, err)
+		} else {
+			for k, v := range resumption.values() {
+				data[k] = v
+			}
+		}
+	}
 
 	event := eventcreator.CreateEvent(request, data, requestOptions.Options.Debug || requestOptions.Options.DebugResponse)
 	if requestOptions.Options.Debug || requestOptions.Options.DebugResponse || requestOptions.Options.StoreResponse {
@@ -311,15 +384,93 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"type":      "Type is the type of request made",
-	"response":  "JSON SSL protocol handshake details",
-	"not_after": "Timestamp after which the remote cert expires",
-	"host":      "Host is the input to the template",
-	"matched":   "Matched is the input which was matched upon",
+	"type":                         "Type is the type of request made",
+	"response":                     "JSON SSL protocol handshake details",
+	"not_after":                    "Timestamp after which the remote cert expires",
+	"cert_days_until_expiry":       "Number of days until the remote cert expires (negative if expired)",
+	"cert_is_expired":              "Whether the remote cert has expired",
+	"session_resumed":              "Whether the server resumed a previous session (session_resumption)",
+	"session_resumption_mechanism": "Mechanism of the resumed session, ticket or session-id (session_resumption)",
+	"session_ticket_issued":        "Whether the server issued a session ticket (session_resumption)",
+	"session_ticket_lifetime":      "Lifetime hint of the tls 1.2 session ticket in seconds (session_resumption)",
+	"session_id_issued":            "Whether the server issued a tls 1.2 session id (session_resumption)",
+	"ja3":                          "JA3 fingerprint of the tls client hello sent (only if a ja3 is configured)",
+	"ja3_hash":                     "MD5 hash of the JA3 fingerprint of the tls client hello sent",
+	"host":                         "Host is the input to the template",
+	"matched":                      "Matched is the input which was matched upon",
+}
+
+// certificateExpiryValues returns the expiry variables derived from a certificate
+func certificateExpiryValues(cert *clients.CertificateResponse, now time.Time) map[string]interface{} {
+	if cert == nil || cert.NotAfter.IsZero() {
+		return nil
+	}
+	remaining := cert.NotAfter.Sub(now)
+	return map[string]interface{}{
+		"cert_days_until_expiry": int(math.Floor(remaining.Hours() / 24)),
+		"cert_is_expired":        remaining < 0,
+	}
+}
+
+// tlsVersionNames contains the names of the tls versions as reported by tlsx
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "tls10",
+	tls.VersionTLS11: "tls11",
+	tls.VersionTLS12: "tls12",
+	tls.VersionTLS13: "tls13",
+}
+
+// connectJA3 performs a tls handshake with host sending the client hello of the
+// ja3 fingerprint and returns the details of the connection in the tlsx format.
+func (request *Request) connectJA3(host, ip, port string) (*clients.Response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), request.options.Options.GetConnectTimeout())
+	defer cancel()
+	conn, err := request.dialer.Dial(ctx, "tcp", net.JoinHostPort(ip, port))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	serverName := host
+	if request.options.Options.SNI != "" {
+		serverName = request.options.Options.SNI
+	}
+	tlsConn, err := ja3.Client(conn, request.ja3Spec, &tls.Config{ServerName: serverName, InsecureSkipVerify: true, MinVersion: tls.VersionTLS10}, nil)
+	if err != nil {
+		return nil, err
+	}
+	_ = tlsConn.SetDeadline(time.Now().Add(request.options.Options.GetTLSHandshakeTimeout()))
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, errors.Wrap(err, "could not perform ja3 tls handshake")
+	}
+
+	state := tlsConn.ConnectionState()
+	now := time.Now()
+	response := &clients.Response{
+		Timestamp:     &now,
+		Host:          host,
+		IP:            ip,
+		Port:          port,
+		ProbeStatus:   true,
+		Version:       tlsVersionNames[state.Version],
+		Cipher:        tls.CipherSuiteName(state.CipherSuite),
+		TLSConnection: "ja3",
+		ServerName:    serverName,
+		Ja3Hash:       ja3.Hash(request.ja3),
+	}
+	if len(state.PeerCertificates) == 0 {
+		return response, nil
+	}
+	response.CertificateResponse = clients.Convertx509toResponse(request.tlsxOptions, host, state.PeerCertificates[0], false)
+	for _, cert := range state.PeerCertificates[1:] {
+		response.Chain = append(response.Chain, clients.Convertx509toResponse(request.tlsxOptions, host, cert, false))
+	}
+	return response, nil
 }
 
 // getAddress returns the address of the host to make request to
 func getAddress(toTest string) (string, error) {
+	toTest = nucleiutils.NormalizeIPv6Input(toTest)
 	urlx, err := urlutil.Parse(toTest)
 	if err != nil {
 		// use given input instead of url parsing failure
@@ -1,7 +1,12 @@
 package ssl
 
 import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -9,7 +14,9 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v2/pkg/output"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/ja3"
 	"github.com/projectdiscovery/nuclei/v2/pkg/testutils"
+	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
 )
 
 func TestSSLProtocol(t *testing.T) {
@@ -36,7 +43,174 @@ func TestSSLProtocol(t *testing.T) {
 	require.NotEmpty(t, gotEvent, "could not get event items")
 }
 
+func TestSSLProtocolJA3(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-ssl-ja3"
+	ja3Value := "771,4865-4866-4867-49195-49199-49196-49200-52393-52392-49171-49172-156-157-47-53,0-23-65281-10-11-35-16-5-13-18-51-45-43-27-21,29-23-24,0"
+	request := &Request{
+		Address: "{{Hostname}}",
+		JA3:     ja3Value,
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile ssl request")
+	require.False(t, request.CanCluster(&Request{Address: "{{Hostname}}", ScanMode: request.ScanMode}), "could cluster request with ja3")
+
+	var gotEvent output.InternalEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.Listener.Addr().String()), nil, nil, func(event *output.InternalWrappedEvent) {
+		gotEvent = event.InternalEvent
+	})
+	require.Nil(t, err, "could not run ssl request")
+	require.Equal(t, ja3Value, gotEvent["ja3"], "could not get ja3")
+	require.Equal(t, ja3.Hash(ja3Value), gotEvent["ja3_hash"], "could not get ja3 hash")
+	require.Equal(t, "tls13", gotEvent["tls_version"], "could not get tls version")
+	require.Equal(t, "ja3", gotEvent["tls_connection"], "could not get tls connection")
+	require.NotEmpty(t, gotEvent["fingerprint_hash"], "could not get certificate")
+
+	request = &Request{Address: "{{Hostname}}", JA3: "771,,0,29,0"}
+	err = request.Compile(executerOpts)
+	require.NotNil(t, err, "could compile ssl request with invalid ja3")
+}
+
 func TestGetAddress(t *testing.T) {
 	address, _ := getAddress("https://google.com")
 	require.Equal(t, "google.com:443", address, "could not get correct address")
+
+	for input, expected := range map[string]string{
+		"[::1]:8443":                   "[::1]:8443",
+		"https://[::1]":                "[::1]:443",
+		"::1":                          "[::1]:443",
+		"2001:db8::1":                  "[2001:db8::1]:443",
+		"[fe80::1%eth0]:8443":          "[fe80::1%eth0]:8443",
+		"https://[fe80::1%25eth0]:853": "[fe80::1%eth0]:853",
+	} {
+		address, err := getAddress(input)
+		require.Nil(t, err, "could not get address")
+		require.Equal(t, expected, address, "could not get correct ipv6 address for %s", input)
+	}
+}
+
+func TestCertificateExpiryValues(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	values := certificateExpiryValues(&clients.CertificateResponse{NotAfter: now.Add(30*24*time.Hour + time.Hour)}, now)
+	require.Equal(t, 30, values["cert_days_until_expiry"], "could not get days until expiry")
+	require.Equal(t, false, values["cert_is_expired"], "could not get expired status")
+
+	values = certificateExpiryValues(&clients.CertificateResponse{NotAfter: now.Add(-time.Hour)}, now)
+	require.Equal(t, -1, values["cert_days_until_expiry"], "could not get days until expiry of expired cert")
+	require.Equal(t, true, values["cert_is_expired"], "could not get expired status of expired cert")
+
+	require.Nil(t, certificateExpiryValues(nil, now), "could not handle missing certificate")
+}
+
+func TestProbeSessionResumption(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		maxVersion uint16
+		tickets    bool
+	}{
+		{name: "tls12 tickets", maxVersion: tls.VersionTLS12, tickets: true},
+		{name: "tls13 tickets", maxVersion: tls.VersionTLS13, tickets: true},
+		{name: "tls12 no tickets", maxVersion: tls.VersionTLS12},
+		{name: "tls13 no tickets", maxVersion: tls.VersionTLS13},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			ts.TLS = &tls.Config{MaxVersion: test.maxVersion, SessionTicketsDisabled: !test.tickets}
+			ts.StartTLS()
+			defer ts.Close()
+
+			dial := func() (net.Conn, error) {
+				return net.Dial("tcp", ts.Listener.Addr().String())
+			}
+			result, err := probeSessionResumption(dial, "127.0.0.1", 5*time.Second)
+			require.Nil(t, err, "could not probe session resumption")
+			require.Equal(t, test.tickets, result.TicketIssued, "could not get ticket issued")
+			require.Equal(t, test.tickets, result.Resumed, "could not get resumed")
+			require.False(t, result.SessionIDIssued, "could not ignore echoed session id")
+			if test.tickets {
+				require.Equal(t, resumptionTicket, result.Mechanism, "could not get mechanism")
+			} else {
+				require.Empty(t, result.Mechanism, "could not get empty mechanism")
+			}
+			// go servers don't send a ticket lifetime hint
+			require.Zero(t, result.TicketLifetime, "could not get ticket lifetime")
+		})
+	}
+}
+
+func TestReplayClientHello(t *testing.T) {
+	sessionID := make([]byte, 32)
+	for i := range sessionID {
+		sessionID[i] = byte(i)
+	}
+	// record wrapping a server hello with the session id
+	serverHello := append([]byte{handshakeTypeServerHello, 0, 0, byte(2 + 32 + 1 + len(sessionID) + 3)}, 0x03, 0x03)
+	serverHello = append(serverHello, make([]byte, 32)...)
+	serverHello = append(serverHello, byte(len(sessionID)))
+	serverHello = append(serverHello, sessionID...)
+	serverHello = append(serverHello, 0xc0, 0x2f, 0x00)
+	serverHelloRecord := append([]byte{recordTypeHandshake, 0x03, 0x03, 0, byte(len(serverHello))}, serverHello...)
+	changeCipherSpecRecord := []byte{recordTypeChangeCipherSpec, 0x03, 0x03, 0, 1, 1}
+	certificateRecord := []byte{recordTypeHandshake, 0x03, 0x03, 0, 4, 11, 0, 0, 0}
+	ticketRecord := []byte{recordTypeHandshake, 0x03, 0x03, 0, 10, handshakeTypeNewSessionTicket, 0, 0, 6, 0, 0, 0x1c, 0x20, 0, 0}
+
+	serverID, lifetime := parseServerHandshake(append(append(append([]byte{}, serverHelloRecord...), ticketRecord...), changeCipherSpecRecord...))
+	require.Equal(t, sessionID, serverID, "could not get server session id")
+	require.Equal(t, 7200, lifetime, "could not get ticket lifetime")
+
+	for name, test := range map[string]struct {
+		response []byte
+		resumed  bool
+	}{
+		"resumed":     {response: append(append([]byte{}, serverHelloRecord...), changeCipherSpecRecord...), resumed: true},
+		"full":        {response: append(append([]byte{}, serverHelloRecord...), certificateRecord...)},
+		"truncated":   {response: serverHelloRecord[:20]},
+		"unsupported": {response: []byte{21, 0x03, 0x03, 0, 2, 2, 40}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			client, server := net.Pipe()
+			go func() {
+				defer server.Close()
+				_, _ = server.Read(make([]byte, 1024))
+				_, _ = server.Write(test.response)
+			}()
+			dial := func() (net.Conn, error) { return client, nil }
+
+			clientHello := []byte{recordTypeHandshake, 0x03, 0x01, 0, 4, handshakeTypeClientHello, 0, 0, 0}
+			resumed, err := replayClientHello(dial, clientHello, sessionID, 5*time.Second)
+			require.Nil(t, err, "could not replay client hello")
+			require.Equal(t, test.resumed, resumed, "could not get resumption")
+		})
+	}
+}
+
+func TestReplaceClientHelloSessionID(t *testing.T) {
+	record := append([]byte{recordTypeHandshake, 0x03, 0x01, 0, 0, handshakeTypeClientHello, 0, 0, 0, 0x03, 0x03}, make([]byte, 32)...)
+	record = append(record, 4, 1, 2, 3, 4, 0xaa)
+
+	clientHello, clientID := parseClientHello(record)
+	require.Nil(t, clientHello, "could not reject record with invalid length")
+	require.Nil(t, clientID, "could not reject record with invalid length")
+
+	record[4] = byte(len(record) - recordHeaderLength)
+	record[8] = byte(len(record) - recordHeaderLength - 4)
+	clientHello, clientID = parseClientHello(record)
+	require.Equal(t, record, clientHello, "could not get client hello record")
+	require.Equal(t, []byte{1, 2, 3, 4}, clientID, "could not get client session id")
+
+	replaced := replaceClientHelloSessionID(record, []byte{5, 6, 7, 8})
+	_, replacedID := parseClientHello(replaced)
+	require.Equal(t, []byte{5, 6, 7, 8}, replacedID, "could not replace session id")
+	require.Equal(t, []byte{1, 2, 3, 4}, clientID, "could not keep original record")
+	require.Nil(t, replaceClientHelloSessionID(record, []byte{5, 6}), "could not reject session id of different length")
 }
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"bytes"
+	"mime"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/html/charset"
+)
+
+// metaCharsetPrescanSize is the number of bytes of a body searched for a meta charset
+const metaCharsetPrescanSize = 1024
+
+// metaCharsetRegex matches the charset of a <meta charset> or of the content
+// type of a <meta http-equiv="content-type"> declared in a document
+var metaCharsetRegex = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([a-z0-9_:.\-]+)`)
+
+// DetectCharset returns the canonical name of the charset of a body, from its
+// byte order mark, the charset parameter of its content type or the meta
+// charset declared in its first bytes, or an empty string if none is declared
+// or the declared charset is unknown.
+func DetectCharset(contentType string, body []byte) string {
+	switch {
+	case bytes.HasPrefix(body, []byte("\xef\xbb\xbf")):
+		return "utf-8"
+	case bytes.HasPrefix(body, []byte("\xfe\xff")):
+		return "utf-16be"
+	case bytes.HasPrefix(body, []byte("\xff\xfe")):
+		return "utf-16le"
+	}
+	if _, params, err := mime.ParseMediaType(contentType); err == nil {
+		if _, name := charset.Lookup(params["charset"]); name != "" {
+			return name
+		}
+	}
+	prescan := body
+	if len(prescan) > metaCharsetPrescanSize {
+		prescan = prescan[:metaCharsetPrescanSize]
+	}
+	if match := metaCharsetRegex.FindSubmatch(prescan); match != nil {
+		if _, name := charset.Lookup(string(match[1])); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// TranscodeToUTF8 transcodes a body from the charset detected by DetectCharset
+// to utf-8, returning the body along with the name of its charset. Bodies with
+// no declared charset or already in utf-8 are returned as is.
+func TranscodeToUTF8(contentType string, body []byte) ([]byte, string, error) {
+	name := DetectCharset(contentType, body)
+	if name == "" || name == "utf-8" {
+		return body, name, nil
+	}
+	encoding, _ := charset.Lookup(name)
+	if encoding == nil {
+		return body, name, nil
+	}
+	transcoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return body, name, errors.Wrapf(err, "could not transcode %s body", name)
+	}
+	// the decoders of utf-16 keep the byte order mark
+	return bytes.TrimPrefix(transcoded, []byte("\xef\xbb\xbf")), name, nil
+}
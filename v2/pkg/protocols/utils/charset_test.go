@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestTranscodeToUTF8(t *testing.T) {
+	encode := func(t *testing.T, enc encoding.Encoding, text string) []byte {
+		data, err := enc.NewEncoder().Bytes([]byte(text))
+		require.Nil(t, err, "could not encode test body")
+		return data
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		encoding    encoding.Encoding
+		text        string
+		charset     string
+	}{
+		{"shift_jis", "text/html; charset=Shift_JIS", japanese.ShiftJIS, "<p>ログイン</p>", "shift_jis"},
+		{"euc-jp", "text/plain; charset=\"euc-jp\"", japanese.EUCJP, "管理者", "euc-jp"},
+		{"iso-8859-2", "text/html; charset=ISO-8859-2", charmap.ISO8859_2, "<p>Zażółć gęślą jaźń</p>", "iso-8859-2"},
+		{"windows-1251", "text/html;charset=windows-1251", charmap.Windows1251, "<p>Пароль</p>", "windows-1251"},
+		{"euc-kr", "text/html; charset=euc-kr", korean.EUCKR, "<p>비밀번호</p>", "euc-kr"},
+		{"meta charset", "text/html", japanese.ShiftJIS, "<html><head><meta charset=\"shift_jis\"></head><body>ログイン</body></html>", "shift_jis"},
+		{"meta http-equiv", "", charmap.ISO8859_15, "<meta http-equiv=\"Content-Type\" content=\"text/html; charset=iso-8859-15\"><p>€ déjà</p>", "iso-8859-15"},
+		{"utf-16le bom", "text/html", unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), "<p>ログイン</p>", "utf-16le"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := encode(t, test.encoding, test.text)
+			require.NotEqual(t, test.text, string(body), "test body was not encoded")
+
+			transcoded, charset, err := TranscodeToUTF8(test.contentType, body)
+			require.Nil(t, err, "could not transcode body")
+			require.Equal(t, test.charset, charset, "could not detect charset")
+			require.Equal(t, test.text, string(transcoded), "could not transcode body")
+		})
+	}
+
+	t.Run("undeclared", func(t *testing.T) {
+		body := encode(t, japanese.ShiftJIS, "ログイン")
+		transcoded, charset, err := TranscodeToUTF8("text/html", body)
+		require.Nil(t, err, "could not transcode body")
+		require.Empty(t, charset, "detected undeclared charset")
+		require.Equal(t, body, transcoded, "undeclared charset body was modified")
+	})
+	t.Run("utf-8", func(t *testing.T) {
+		body := []byte("<meta charset=utf-8><p>ログイン</p>")
+		transcoded, charset, err := TranscodeToUTF8("text/html; charset=unknown", body)
+		require.Nil(t, err, "could not transcode body")
+		require.Equal(t, "utf-8", charset, "could not detect meta charset")
+		require.Equal(t, body, transcoded, "utf-8 body was modified")
+	})
+	t.Run("meta beyond prescan", func(t *testing.T) {
+		body := append(make([]byte, metaCharsetPrescanSize), []byte("<meta charset=shift_jis>")...)
+		require.Empty(t, DetectCharset("", body), "detected meta charset beyond prescan")
+	})
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"net/http"
 	"os"
 	"strings"
 
@@ -46,3 +47,17 @@ func CalculateContentLength(contentLength, bodyLength int64) int64 {
 	}
 	return bodyLength
 }
+
+// HeadersToMap converts response headers to a map with lowercased header names
+// for use in DSL matching. Values of duplicate headers are joined with ", ".
+func HeadersToMap(headers http.Header) map[string]interface{} {
+	headersMap := make(map[string]interface{}, len(headers))
+	for k, v := range headers {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if existing, ok := headersMap[k]; ok {
+			v = append([]string{existing.(string)}, v...)
+		}
+		headersMap[k] = strings.Join(v, ", ")
+	}
+	return headersMap
+}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"strings"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/utils/vardump"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/network/networkclientpool"
 	protocolutils "github.com/projectdiscovery/nuclei/v2/pkg/protocols/utils"
@@ -51,6 +53,31 @@ type Request struct {
 	// description: |
 	//   Headers contains headers for the request.
 	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"title=headers contains the request headers,description=Headers contains headers for the request"`
+	// description: |
+	//   Subprotocols contains the subprotocols requested during the handshake.
+	//
+	//   The subprotocol selected by the server is available in the subprotocol part.
+	// examples:
+	//   - value: "[]string{\"graphql-transport-ws\", \"graphql-ws\"}"
+	Subprotocols []string `yaml:"subprotocols,omitempty" json:"subprotocols,omitempty" jsonschema:"title=subprotocols requested during the handshake,description=Subprotocols contains the subprotocols requested during the handshake"`
+	// description: |
+	//   ReadFrames is the maximum number of frames read after each input.
+	//
+	//   When no inputs are specified, frames are read after the connection is established.
+	//   Reading stops early when no frame is received within the read timeout
+	//   or the server closes the connection.
+	//
+	//   Default value for read-frames is 1.
+	// examples:
+	//   - value: "5"
+	ReadFrames int `yaml:"read-frames,omitempty" json:"read-frames,omitempty" jsonschema:"title=maximum number of frames to read,description=Maximum number of frames read after each input"`
+	// description: |
+	//   ReadTimeout is the number of seconds to wait for each frame.
+	//
+	//   Default value for read-timeout is the request timeout.
+	// examples:
+	//   - value: "2"
+	ReadTimeout int `yaml:"read-timeout,omitempty" json:"read-timeout,omitempty" jsonschema:"title=seconds to wait for each frame,description=Number of seconds to wait for each frame"`
 
 	// description: |
 	//   Attack is the type of payload combinations to perform.
@@ -64,6 +91,10 @@ type Request struct {
 	//   Payloads support both key-values combinations where a list
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
+	//
+	//   Payloads can also be generated lazily from a map naming a value generator:
+	//   range (start, end, step, format), date (start, end, step, format) or
+	//   charset (charset, min, max), bounded by an optional limit of values.
 	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the webosocket request,description=Payloads contains any payloads for the current request"`
 
 	generator *generators.PayloadGenerator
@@ -198,7 +229,8 @@ func (request *Request) executeRequestWithPayloads(input, hostname string, dynam
 	}
 	websocketDialer := ws.Dialer{
 		Header:    ws.HandshakeHeaderHTTP(header),
-		Timeout:   time.Duration(requestOptions.Options.Timeout) * time.Second,
+		Protocols: request.Subprotocols,
+		Timeout:   requestOptions.Options.GetConnectTimeout(),
 		NetDial:   request.dialer.Dial,
 		TLSConfig: tlsConfig,
 	}
@@ -224,7 +256,12 @@ func (request *Request) executeRequestWithPayloads(input, hostname string, dynam
 	parsedAddress.Path = path.Join(parsedAddress.Path, parsed.Path)
 	addressToDial = parsedAddress.String()
 
-	conn, readBuffer, _, err := websocketDialer.Dial(context.Background(), addressToDial)
+	// Check if the request budget of the template for the host is exhausted
+	if !requestOptions.RequestBudget.Take(input) {
+		requestOptions.Progress.IncrementSkippedRequestsBy(1)
+		return nil
+	}
+	conn, readBuffer, handshake, err := websocketDialer.Dial(context.Background(), addressToDial)
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
@@ -232,12 +269,14 @@ func (request *Request) executeRequestWithPayloads(input, hostname string, dynam
 	}
 	defer conn.Close()
 
-	responseBuilder := &strings.Builder{}
+	// frames sent along with the handshake response are buffered by the dialer
+	frameConn := &frameConnection{Conn: conn, reader: conn}
 	if readBuffer != nil {
-		_, _ = io.Copy(responseBuilder, readBuffer) // Copy initial response
+		frameConn.reader = io.MultiReader(readBuffer, conn)
 	}
 
-	events, requestOutput, err := request.readWriteInputWebsocket(conn, payloadValues, input, responseBuilder)
+	responseBuilder := &strings.Builder{}
+	events, requestOutput, err := request.readWriteInputWebsocket(frameConn, payloadValues, input, responseBuilder)
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
@@ -267,7 +306,8 @@ func (request *Request) executeRequestWithPayloads(input, hostname string, dynam
 	data["response"] = responseBuilder.String()
 	data["host"] = input
 	data["matched"] = addressToDial
-	data["ip"] = request.dialer.GetDialedIP(hostname)
+	data["subprotocol"] = handshake.Protocol
+	data["ip"] = protocolstate.GetDialedIP(request.dialer, hostname)
 
 	event := eventcreator.CreateEventWithAdditionalOptions(request, data, requestOptions.Options.Debug || requestOptions.Options.DebugResponse, func(internalWrappedEvent *output.InternalWrappedEvent) {
 		internalWrappedEvent.OperatorsResult.PayloadValues = payloadValues
@@ -285,8 +325,22 @@ func (request *Request) executeRequestWithPayloads(input, hostname string, dynam
 func (request *Request) readWriteInputWebsocket(conn net.Conn, payloadValues map[string]interface{}, input string, respBuilder *strings.Builder) (events map[string]interface{}, req string, err error) {
 	reqBuilder := &strings.Builder{}
 	inputEvents := make(map[string]interface{})
+	var frames []string
 
 	requestOptions := request.options
+	maxFrames, readTimeout := request.frameReadLimits()
+	if len(request.Inputs) == 0 && request.ReadFrames > 0 {
+		received, err := readFrames(conn, maxFrames, readTimeout)
+		if err != nil {
+			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
+			requestOptions.Progress.IncrementFailedRequestsBy(1)
+			return nil, "", errors.Wrap(err, "could not read response from server")
+		}
+		for _, frame := range received {
+			respBuilder.Write(frame)
+			frames = append(frames, string(frame))
+		}
+	}
 	for _, req := range request.Inputs {
 		reqBuilder.Grow(len(req.Data))
 
@@ -305,21 +359,24 @@ func (request *Request) readWriteInputWebsocket(conn net.Conn, payloadValues map
 			return nil, "", errors.Wrap(err, "could not write request to server")
 		}
 
-		msg, opCode, err := wsutil.ReadServerData(conn)
+		received, err := readFrames(conn, maxFrames, readTimeout)
 		if err != nil {
 			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 			requestOptions.Progress.IncrementFailedRequestsBy(1)
-			return nil, "", errors.Wrap(err, "could not write request to server")
+			return nil, "", errors.Wrap(err, "could not read response from server")
 		}
-		// Only perform matching and writes in case we receive
-		// text or binary opcode from the websocket server.
-		if opCode != ws.OpText && opCode != ws.OpBinary {
+		if len(received) == 0 {
 			continue
 		}
 
-		respBuilder.Write(msg)
+		inputBuilder := &strings.Builder{}
+		for _, frame := range received {
+			respBuilder.Write(frame)
+			inputBuilder.Write(frame)
+			frames = append(frames, string(frame))
+		}
 		if req.Name != "" {
-			bufferStr := string(msg)
+			bufferStr := inputBuilder.String()
 			inputEvents[req.Name] = bufferStr
 
 			// Run any internal extractors for the request here and add found values to map.
@@ -331,9 +388,66 @@ func (request *Request) readWriteInputWebsocket(conn net.Conn, payloadValues map
 			}
 		}
 	}
+	inputEvents["frames"] = frames
 	return inputEvents, reqBuilder.String(), nil
 }
 
+// frameReadLimits returns the maximum number of frames read after each input
+// and the time to wait for each frame.
+func (request *Request) frameReadLimits() (int, time.Duration) {
+	maxFrames := 1
+	if request.ReadFrames > 0 {
+		maxFrames = request.ReadFrames
+	}
+	readTimeout := request.options.Options.GetResponseReadTimeout()
+	if request.ReadTimeout > 0 {
+		readTimeout = time.Duration(request.ReadTimeout) * time.Second
+	}
+	return maxFrames, readTimeout
+}
+
+// readFrames reads up to maxFrames text or binary frames sent by the server,
+// waiting at most timeout for each frame. Reading stops without an error when
+// no frame is received in time or the server closes the connection.
+func readFrames(conn net.Conn, maxFrames int, timeout time.Duration) ([][]byte, error) {
+	defer func() {
+		_ = conn.SetReadDeadline(time.Time{})
+	}()
+
+	var frames [][]byte
+	for len(frames) < maxFrames {
+		if timeout > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(timeout))
+		}
+		msg, opCode, err := wsutil.ReadServerData(conn)
+		if err != nil {
+			var closedErr wsutil.ClosedError
+			if os.IsTimeout(err) || errors.As(err, &closedErr) || errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		// Only perform matching and writes in case we receive
+		// text or binary opcode from the websocket server.
+		if opCode != ws.OpText && opCode != ws.OpBinary {
+			continue
+		}
+		frames = append(frames, msg)
+	}
+	return frames, nil
+}
+
+// frameConnection is a websocket connection reading the frames
+// buffered during the handshake before the ones from the connection.
+type frameConnection struct {
+	net.Conn
+	reader io.Reader
+}
+
+func (c *frameConnection) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
 // getAddress returns the address of the host to make request to
 func getAddress(toTest string) (string, error) {
 	parsed, err := url.Parse(toTest)
@@ -377,12 +491,14 @@ func (request *Request) GetCompiledOperators() []*operators.Operators {
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"type":     "Type is the type of request made",
-	"success":  "Success specifies whether websocket connection was successful",
-	"request":  "Websocket request made to the server",
-	"response": "Websocket response received from the server",
-	"host":     "Host is the input to the template",
-	"matched":  "Matched is the input which was matched upon",
+	"type":        "Type is the type of request made",
+	"success":     "Success specifies whether websocket connection was successful",
+	"request":     "Websocket request made to the server",
+	"response":    "Websocket response received from the server",
+	"host":        "Host is the input to the template",
+	"matched":     "Matched is the input which was matched upon",
+	"frames":      "Frames is the list of text and binary frames received from the server",
+	"subprotocol": "Subprotocol is the subprotocol selected by the server during the handshake",
 }
 
 func (request *Request) MakeResultEventItem(wrapped *output.InternalWrappedEvent) *output.ResultEvent {
@@ -0,0 +1,77 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFrames(t *testing.T) {
+	writeFrames := func(conn net.Conn, frames ...string) {
+		for _, frame := range frames {
+			if err := wsutil.WriteServerMessage(conn, ws.OpText, []byte(frame)); err != nil {
+				return
+			}
+		}
+	}
+
+	t.Run("limit", func(t *testing.T) {
+		client, server := net.Pipe()
+		defer client.Close()
+		defer server.Close()
+		go writeFrames(server, "first", "second", "third")
+
+		frames, err := readFrames(client, 2, time.Second)
+		require.Nil(t, err, "could not read frames")
+		require.Equal(t, [][]byte{[]byte("first"), []byte("second")}, frames, "could not limit frames")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		client, server := net.Pipe()
+		defer client.Close()
+		defer server.Close()
+		go writeFrames(server, "first")
+
+		frames, err := readFrames(client, 5, 200*time.Millisecond)
+		require.Nil(t, err, "could not read frames")
+		require.Equal(t, [][]byte{[]byte("first")}, frames, "could not stop reading on timeout")
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		client, server := net.Pipe()
+		defer client.Close()
+		go func() {
+			writeFrames(server, "first")
+			_ = server.Close()
+		}()
+
+		frames, err := readFrames(client, 5, time.Second)
+		require.Nil(t, err, "could not read frames")
+		require.Len(t, frames, 1, "could not stop reading on close")
+	})
+}
+
+func TestFrameConnection(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	// the first frame is buffered during the handshake
+	buffered := &bytes.Buffer{}
+	require.Nil(t, wsutil.WriteServerMessage(buffered, ws.OpText, []byte("buffered")), "could not write frame")
+	go func() {
+		_ = wsutil.WriteServerMessage(server, ws.OpText, []byte("connection"))
+	}()
+
+	conn := &frameConnection{Conn: client, reader: io.MultiReader(bufio.NewReader(buffered), client)}
+	frames, err := readFrames(conn, 2, time.Second)
+	require.Nil(t, err, "could not read frames")
+	require.Equal(t, [][]byte{[]byte("buffered"), []byte("connection")}, frames, "could not read buffered frames")
+}
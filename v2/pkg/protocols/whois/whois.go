@@ -100,6 +100,12 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 
 	// and replace placeholders
 	query := replacer.Replace(request.Query, variables)
+
+	// Check if the request budget of the template for the host is exhausted
+	if !request.options.RequestBudget.Take(input.MetaInput.Input) {
+		request.options.Progress.IncrementSkippedRequestsBy(1)
+		return nil
+	}
 	// build an rdap request
 	rdapReq := rdap.NewAutoRequest(query)
 	rdapReq.Server = request.parsedServerURL
@@ -0,0 +1,39 @@
+package whois
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/model"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/requestbudget"
+	"github.com/projectdiscovery/nuclei/v2/pkg/testutils"
+)
+
+func TestWhoisExecuteRequestBudget(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-whois-budget"
+	request := &Request{Query: "{{Host}}"}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.RequestBudget = requestbudget.New(templateID, 1)
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile whois request")
+
+	// exhaust the budget of the host so that no whois request is sent
+	require.True(t, executerOpts.RequestBudget.Take("example.com"), "could not take from budget")
+
+	var gotEvent bool
+	err = request.ExecuteWithResults(contextargs.NewWithInput("example.com"), nil, nil, func(event *output.InternalWrappedEvent) {
+		gotEvent = true
+	})
+	require.Nil(t, err, "could not run whois request")
+	require.False(t, gotEvent, "request sent beyond the budget")
+}
@@ -23,7 +23,7 @@ import (
 // request which saves time and network resources during execution.
 //
 // The clusterer goes through all the templates, looking for templates with a single
-// HTTP/DNS/TLS request to an endpoint (multiple requests aren't clustered as of now).
+// HTTP/DNS/TLS/Headless request to an endpoint (multiple requests aren't clustered as of now).
 //
 // All the templates are iterated and any templates with request that is identical
 // to the first individual request is compared for equality.
@@ -36,6 +36,8 @@ import (
 //   - If request headers aren't identical
 //   - Similarly for DNS, only identical DNS requests are clustered to a target.
 //   - Similarly for TLS, only identical TLS requests are clustered to a target.
+//   - For Headless, only requests with identical steps which only load or observe
+//     the page are clustered, sharing a single page.
 //
 // If multiple requests are identified as identical, they are appended to a slice.
 // Finally, the engine creates a single executer with a clusteredexecuter for all templates
@@ -43,11 +45,22 @@ import (
 func Cluster(list map[string]*Template) [][]*Template {
 	final := [][]*Template{}
 
+	// iterate the templates in order so the clusters are the same across executions
+	keys := make([]string, 0, len(list))
+	for key := range list {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	// Each protocol that can be clustered should be handled here.
-	for key, template := range list {
+	for _, key := range keys {
+		template, ok := list[key]
+		if !ok {
+			continue
+		}
 		// We only cluster http and dns requests as of now.
 		// Take care of requests that can't be clustered first.
-		if len(template.RequestsHTTP) == 0 && len(template.RequestsDNS) == 0 && len(template.RequestsSSL) == 0 {
+		if len(template.RequestsHTTP) == 0 && len(template.RequestsDNS) == 0 && len(template.RequestsSSL) == 0 && len(template.RequestsHeadless) == 0 {
 			delete(list, key)
 			final = append(final, []*Template{template})
 			continue
@@ -62,12 +75,18 @@ func Cluster(list map[string]*Template) [][]*Template {
 			templateType = types.HTTPProtocol
 		case len(template.RequestsSSL) == 1:
 			templateType = types.SSLProtocol
+		case len(template.RequestsHeadless) == 1:
+			templateType = types.HeadlessProtocol
 		}
 
 		// Find any/all similar matching request that is identical to
 		// this one and cluster them together for http protocol only.
 		cluster := []*Template{}
-		for otherKey, other := range list {
+		for _, otherKey := range keys {
+			other, ok := list[otherKey]
+			if !ok {
+				continue
+			}
 			switch templateType {
 			case types.DNSProtocol:
 				if len(other.RequestsDNS) == 0 || len(other.RequestsDNS) > 1 {
@@ -90,6 +109,13 @@ func Cluster(list map[string]*Template) [][]*Template {
 					delete(list, otherKey)
 					cluster = append(cluster, other)
 				}
+			case types.HeadlessProtocol:
+				if len(other.RequestsHeadless) == 0 || len(other.RequestsHeadless) > 1 {
+					continue
+				} else if template.RequestsHeadless[0].CanCluster(other.RequestsHeadless[0]) {
+					delete(list, otherKey)
+					cluster = append(cluster, other)
+				}
 			}
 		}
 		if len(cluster) > 0 {
@@ -118,13 +144,18 @@ func ClusterTemplates(templatesList []*Template, options protocols.ExecuterOptio
 		return templatesList, 0
 	}
 
+	finalTemplatesList := make([]*Template, 0, len(templatesList))
 	templatesMap := make(map[string]*Template)
 	for _, v := range templatesList {
+		// headless templates are only clustered if enabled
+		if len(v.RequestsHeadless) > 0 && !options.Options.HeadlessClustering {
+			finalTemplatesList = append(finalTemplatesList, v)
+			continue
+		}
 		templatesMap[v.Path] = v
 	}
 	clusterCount := 0
 
-	finalTemplatesList := make([]*Template, 0, len(templatesList))
 	clusters := Cluster(templatesMap)
 	for _, cluster := range clusters {
 		if len(cluster) > 1 {
@@ -140,14 +171,18 @@ func ClusterTemplates(templatesList []*Template, options protocols.ExecuterOptio
 			for _, req := range cluster[0].RequestsSSL {
 				req.Options().TemplateID = clusterID
 			}
+			for _, req := range cluster[0].RequestsHeadless {
+				req.Options().TemplateID = clusterID
+			}
 			executerOpts.TemplateID = clusterID
 			finalTemplatesList = append(finalTemplatesList, &Template{
-				ID:            clusterID,
-				RequestsDNS:   cluster[0].RequestsDNS,
-				RequestsHTTP:  cluster[0].RequestsHTTP,
-				RequestsSSL:   cluster[0].RequestsSSL,
-				Executer:      NewClusterExecuter(cluster, &executerOpts),
-				TotalRequests: len(cluster[0].RequestsHTTP) + len(cluster[0].RequestsDNS),
+				ID:               clusterID,
+				RequestsDNS:      cluster[0].RequestsDNS,
+				RequestsHTTP:     cluster[0].RequestsHTTP,
+				RequestsSSL:      cluster[0].RequestsSSL,
+				RequestsHeadless: cluster[0].RequestsHeadless,
+				Executer:         NewClusterExecuter(cluster, &executerOpts),
+				TotalRequests:    len(cluster[0].RequestsHTTP) + len(cluster[0].RequestsDNS) + len(cluster[0].RequestsHeadless),
 			})
 			clusterCount += len(cluster)
 		} else {
@@ -188,6 +223,9 @@ func NewClusterExecuter(requests []*Template, options *protocols.ExecuterOptions
 	} else if len(requests[0].RequestsSSL) == 1 {
 		executer.templateType = types.SSLProtocol
 		executer.requests = requests[0].RequestsSSL[0]
+	} else if len(requests[0].RequestsHeadless) == 1 {
+		executer.templateType = types.HeadlessProtocol
+		executer.requests = requests[0].RequestsHeadless[0]
 	}
 	appendOperator := func(req *Template, operator *operators.Operators) {
 		operator.TemplateID = req.ID
@@ -213,6 +251,10 @@ func NewClusterExecuter(requests []*Template, options *protocols.ExecuterOptions
 			if req.RequestsSSL[0].CompiledOperators != nil {
 				appendOperator(req, req.RequestsSSL[0].CompiledOperators)
 			}
+		} else if executer.templateType == types.HeadlessProtocol {
+			if req.RequestsHeadless[0].CompiledOperators != nil {
+				appendOperator(req, req.RequestsHeadless[0].CompiledOperators)
+			}
 		}
 	}
 	return executer
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/dns"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/headless"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/headless/engine"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/http"
 	"github.com/stretchr/testify/require"
 )
@@ -47,6 +49,28 @@ func TestClusterTemplates(t *testing.T) {
 				{RequestsDNS: []*dns.Request{{Name: "{{Hostname}}"}}},
 			}},
 		},
+		{
+			name: "headless-cluster",
+			templates: map[string]*Template{
+				"first.yaml":  {RequestsHeadless: []*headless.Request{{Steps: headlessSteps(engine.ActionNavigate, engine.ActionWaitLoad)}}},
+				"second.yaml": {RequestsHeadless: []*headless.Request{{Steps: headlessSteps(engine.ActionNavigate, engine.ActionWaitLoad)}}},
+			},
+			expected: [][]*Template{{
+				{RequestsHeadless: []*headless.Request{{Steps: headlessSteps(engine.ActionNavigate, engine.ActionWaitLoad)}}},
+				{RequestsHeadless: []*headless.Request{{Steps: headlessSteps(engine.ActionNavigate, engine.ActionWaitLoad)}}},
+			}},
+		},
+		{
+			name: "no-headless-cluster-interaction",
+			templates: map[string]*Template{
+				"first.yaml":  {RequestsHeadless: []*headless.Request{{Steps: headlessSteps(engine.ActionNavigate, engine.ActionClick)}}},
+				"second.yaml": {RequestsHeadless: []*headless.Request{{Steps: headlessSteps(engine.ActionNavigate, engine.ActionClick)}}},
+			},
+			expected: [][]*Template{
+				{{RequestsHeadless: []*headless.Request{{Steps: headlessSteps(engine.ActionNavigate, engine.ActionClick)}}}},
+				{{RequestsHeadless: []*headless.Request{{Steps: headlessSteps(engine.ActionNavigate, engine.ActionClick)}}}},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -56,3 +80,16 @@ func TestClusterTemplates(t *testing.T) {
 		})
 	}
 }
+
+// headlessSteps returns the steps of the given actions, navigating to the base url
+func headlessSteps(actions ...engine.ActionType) []*engine.Action {
+	steps := make([]*engine.Action, 0, len(actions))
+	for _, action := range actions {
+		step := &engine.Action{ActionType: engine.ActionTypeHolder{ActionType: action}}
+		if action == engine.ActionNavigate {
+			step.Data = map[string]string{"url": "{{BaseURL}}"}
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
@@ -105,7 +105,8 @@ func (template *Template) Requests() int {
 		len(template.Workflows) +
 		len(template.RequestsSSL) +
 		len(template.RequestsWebsocket) +
-		len(template.RequestsWHOIS)
+		len(template.RequestsWHOIS) +
+		len(template.RequestsICMP)
 }
 
 // compileProtocolRequests compiles all the protocol requests for the template
@@ -146,6 +147,9 @@ func (template *Template) compileProtocolRequests(options protocols.ExecuterOpti
 	if len(template.RequestsWHOIS) > 0 {
 		requests = append(requests, template.convertRequestToProtocolsRequest(template.RequestsWHOIS)...)
 	}
+	if len(template.RequestsICMP) > 0 {
+		requests = append(requests, template.convertRequestToProtocolsRequest(template.RequestsICMP)...)
+	}
 	template.Executer = executer.NewExecuter(requests, &options)
 	return nil
 }
@@ -228,7 +232,25 @@ func ParseTemplateFromReader(reader io.Reader, preprocessor Preprocessor, option
 	options.TemplateInfo = template.Info
 	options.StopAtFirstMatch = template.StopAtFirstMatch
 
+	maxRequests := template.MaxRequests
+	if maxRequests == 0 && options.Options != nil {
+		maxRequests = options.Options.MaxRequestsPerTemplate
+	}
+	options.RequestBudget = options.RequestBudgets.New(template.ID, maxRequests)
+
+	options.MaxBodySize = template.MaxBodySize
+	if options.MaxBodySize == 0 && options.Options != nil {
+		options.MaxBodySize = options.Options.MaxBodySize
+	}
+
 	if template.Variables.Len() > 0 {
+		if err := template.Variables.CheckCycles(); err != nil {
+			return nil, errors.Wrap(err, "invalid variables")
+		}
+		envAllowed := options.Options != nil && options.Options.EnvironmentVariables
+		if err := template.Variables.ExpandEnv(envAllowed); err != nil {
+			return nil, errors.Wrap(err, "could not expand environment variables (enable them with -env-vars)")
+		}
 		options.Variables = template.Variables
 	}
 
@@ -237,6 +259,19 @@ func ParseTemplateFromReader(reader io.Reader, preprocessor Preprocessor, option
 		return nil, fmt.Errorf("no requests defined for %s", template.ID)
 	}
 
+	if len(template.FlowMatchers) > 0 {
+		compiled := &operators.Operators{
+			Matchers:          template.FlowMatchers,
+			MatchersCondition: template.FlowMatchersCondition,
+			ExcludeMatchers:   options.ExcludeMatchers,
+			TemplateID:        template.ID,
+		}
+		if err := compiled.Compile(); err != nil {
+			return nil, errors.Wrap(err, "could not compile flow matchers")
+		}
+		options.FlowOperators = compiled
+	}
+
 	if err := template.compileProtocolRequests(options); err != nil {
 		return nil, err
 	}
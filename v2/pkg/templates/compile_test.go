@@ -36,7 +36,7 @@ var executerOpts protocols.ExecuterOptions
 func setup() {
 	options := testutils.DefaultOptions
 	testutils.Init(options)
-	progressImpl, _ := progress.NewStatsTicker(0, false, false, false, false, 0)
+	progressImpl, _ := progress.NewStatsTicker(0, false, false, false, false, 0, "")
 
 	executerOpts = protocols.ExecuterOptions{
 		Output:       testutils.NewMockOutputWriter(),
@@ -6,12 +6,14 @@ import (
 
 	validate "github.com/go-playground/validator/v10"
 	"github.com/projectdiscovery/nuclei/v2/pkg/model"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/variables"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/dns"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/file"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/headless"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/http"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/icmp"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/network"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/ssl"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/websocket"
@@ -92,6 +94,9 @@ type Template struct {
 	//   WHOIS contains the WHOIS request to make in the template.
 	RequestsWHOIS []*whois.Request `yaml:"whois,omitempty" json:"whois,omitempty" jsonschema:"title=whois requests to make,description=WHOIS requests to make for the template"`
 	// description: |
+	//   ICMP contains the ICMP echo requests to make in the template.
+	RequestsICMP []*icmp.Request `yaml:"icmp,omitempty" json:"icmp,omitempty" jsonschema:"title=icmp requests to make,description=ICMP echo requests to make for the template"`
+	// description: |
 	//   Workflows is a yaml based workflow declaration code.
 	workflows.Workflow `yaml:",inline,omitempty" jsonschema:"title=workflows to run,description=Workflows to run for the template"`
 	CompiledWorkflow   *workflows.Workflow `yaml:"-" json:"-" jsonschema:"-"`
@@ -102,6 +107,34 @@ type Template struct {
 	// description: |
 	//  Stop execution once first match is found
 	StopAtFirstMatch bool `yaml:"stop-at-first-match,omitempty" json:"stop-at-first-match,omitempty" jsonschema:"title=stop at first match,description=Stop at first match for the template"`
+	// description: |
+	//   MaxRequests is the maximum number of requests the template can send to a single host.
+	//
+	//   Overrides the global max-requests option. Further requests are skipped once the budget is exhausted.
+	MaxRequests int `yaml:"max-requests,omitempty" json:"max-requests,omitempty" jsonschema:"title=maximum requests per host,description=Maximum number of requests the template can send to a single host"`
+	// description: |
+	//   MaxBodySize is the maximum size in bytes of the response bodies kept for matching, after decompression.
+	//
+	//   Overrides the global max-body-size option. Bodies exceeding it are truncated and the truncated
+	//   variable is set to true. -1 disables the limit for templates requiring complete bodies.
+	MaxBodySize int `yaml:"max-body-size,omitempty" json:"max-body-size,omitempty" jsonschema:"title=maximum response body size,description=Maximum size in bytes of the response bodies kept for matching (-1 disables the limit)"`
+	// description: |
+	//   FlowMatchers are matchers evaluated once all the requests of the template have run.
+	//
+	//   Responses of every request are available prefixed by the protocol and the position
+	//   of the request (e.g. http_1_body, dns_2_answer), by the id of the request if any,
+	//   or unprefixed for the last response. Values of named and internal extractors of
+	//   all the requests are available by their name.
+	//
+	//   Flow matchers are not evaluated if stop-at-first-match ended the flow before all the
+	//   requests were run.
+	FlowMatchers []*matchers.Matcher `yaml:"flow-matchers,omitempty" json:"flow-matchers,omitempty" jsonschema:"title=flow matchers,description=Matchers evaluated once all the requests of the template have run"`
+	// description: |
+	//   FlowMatchersCondition is the condition between the flow matchers. Default is OR.
+	// values:
+	//   - "and"
+	//   - "or"
+	FlowMatchersCondition string `yaml:"flow-matchers-condition,omitempty" json:"flow-matchers-condition,omitempty" jsonschema:"title=condition between the flow matchers,description=Conditions between the flow matchers,enum=and,enum=or"`
 
 	// description: |
 	//   Signature is the request signature method
@@ -111,6 +144,15 @@ type Template struct {
 
 	// description: |
 	//   Variables contains any variables for the current request.
+	//
+	//   Environment variables can be referenced in the values as {{env "NAME"}}
+	//   when they are enabled with -env-vars.
+	//
+	//   Variables can reference each other in any order, they are evaluated after
+	//   the variables they reference and cycles are reported when the template is
+	//   loaded. Variables only referencing constant values are evaluated once when
+	//   the template is loaded, the others for every request, so they can reference
+	//   the payloads and the values extracted by previous requests.
 	Variables variables.Variable `yaml:"variables,omitempty" json:"variables,omitempty" jsonschema:"title=variables for the http request,description=Variables contains any variables for the current request"`
 
 	// TotalRequests is the total number of requests for the template.
@@ -135,6 +177,7 @@ var TemplateProtocols = []string{
 	"ssl",
 	"websocket",
 	"whois",
+	"icmp",
 }
 
 // Type returns the type of the template
@@ -158,6 +201,8 @@ func (template *Template) Type() types.ProtocolType {
 		return types.WebsocketProtocol
 	case len(template.RequestsWHOIS) > 0:
 		return types.WHOISProtocol
+	case len(template.RequestsICMP) > 0:
+		return types.ICMPProtocol
 	default:
 		return types.InvalidProtocol
 	}
@@ -34,6 +34,9 @@ var (
 	WEBSOCKETRequestDoc           encoder.Doc
 	WEBSOCKETInputDoc             encoder.Doc
 	WHOISRequestDoc               encoder.Doc
+	ICMPRequestDoc                encoder.Doc
+	MATCHERSMatcherDoc            encoder.Doc
+	MatcherTypeHolderDoc          encoder.Doc
 	HTTPSignatureTypeHolderDoc    encoder.Doc
 	VARIABLESVariableDoc          encoder.Doc
 )
@@ -42,7 +45,7 @@ func init() {
 	TemplateDoc.Type = "Template"
 	TemplateDoc.Comments[encoder.LineComment] = " Template is a YAML input file which defines all the requests and"
 	TemplateDoc.Description = "Template is a YAML input file which defines all the requests and\n other metadata for a template."
-	TemplateDoc.Fields = make([]encoder.Doc, 16)
+	TemplateDoc.Fields = make([]encoder.Doc, 21)
 	TemplateDoc.Fields[0].Name = "id"
 	TemplateDoc.Fields[0].Type = "string"
 	TemplateDoc.Fields[0].Note = ""
@@ -115,29 +118,58 @@ func init() {
 	TemplateDoc.Fields[11].Note = ""
 	TemplateDoc.Fields[11].Description = "WHOIS contains the WHOIS request to make in the template."
 	TemplateDoc.Fields[11].Comments[encoder.LineComment] = "WHOIS contains the WHOIS request to make in the template."
-	TemplateDoc.Fields[12].Name = "self-contained"
-	TemplateDoc.Fields[12].Type = "bool"
+	TemplateDoc.Fields[12].Name = "icmp"
+	TemplateDoc.Fields[12].Type = "[]icmp.Request"
 	TemplateDoc.Fields[12].Note = ""
-	TemplateDoc.Fields[12].Description = "Self Contained marks Requests for the template as self-contained"
-	TemplateDoc.Fields[12].Comments[encoder.LineComment] = "Self Contained marks Requests for the template as self-contained"
-	TemplateDoc.Fields[13].Name = "stop-at-first-match"
+	TemplateDoc.Fields[12].Description = "ICMP contains the ICMP echo requests to make in the template."
+	TemplateDoc.Fields[12].Comments[encoder.LineComment] = "ICMP contains the ICMP echo requests to make in the template."
+	TemplateDoc.Fields[13].Name = "self-contained"
 	TemplateDoc.Fields[13].Type = "bool"
 	TemplateDoc.Fields[13].Note = ""
-	TemplateDoc.Fields[13].Description = "Stop execution once first match is found"
-	TemplateDoc.Fields[13].Comments[encoder.LineComment] = "Stop execution once first match is found"
-	TemplateDoc.Fields[14].Name = "signature"
-	TemplateDoc.Fields[14].Type = "http.SignatureTypeHolder"
+	TemplateDoc.Fields[13].Description = "Self Contained marks Requests for the template as self-contained"
+	TemplateDoc.Fields[13].Comments[encoder.LineComment] = "Self Contained marks Requests for the template as self-contained"
+	TemplateDoc.Fields[14].Name = "stop-at-first-match"
+	TemplateDoc.Fields[14].Type = "bool"
 	TemplateDoc.Fields[14].Note = ""
-	TemplateDoc.Fields[14].Description = "Signature is the request signature method"
-	TemplateDoc.Fields[14].Comments[encoder.LineComment] = "Signature is the request signature method"
-	TemplateDoc.Fields[14].Values = []string{
+	TemplateDoc.Fields[14].Description = "Stop execution once first match is found"
+	TemplateDoc.Fields[14].Comments[encoder.LineComment] = "Stop execution once first match is found"
+	TemplateDoc.Fields[15].Name = "max-requests"
+	TemplateDoc.Fields[15].Type = "int"
+	TemplateDoc.Fields[15].Note = ""
+	TemplateDoc.Fields[15].Description = "MaxRequests is the maximum number of requests the template can send to a single host.\n\nOverrides the global max-requests option. Further requests are skipped once the budget is exhausted."
+	TemplateDoc.Fields[15].Comments[encoder.LineComment] = "MaxRequests is the maximum number of requests the template can send to a single host."
+	TemplateDoc.Fields[16].Name = "max-body-size"
+	TemplateDoc.Fields[16].Type = "int"
+	TemplateDoc.Fields[16].Note = ""
+	TemplateDoc.Fields[16].Description = "MaxBodySize is the maximum size in bytes of the response bodies kept for matching, after decompression.\n\nOverrides the global max-body-size option. Bodies exceeding it are truncated and the truncated\nvariable is set to true. -1 disables the limit for templates requiring complete bodies."
+	TemplateDoc.Fields[16].Comments[encoder.LineComment] = "MaxBodySize is the maximum size in bytes of the response bodies kept for matching, after decompression."
+	TemplateDoc.Fields[17].Name = "flow-matchers"
+	TemplateDoc.Fields[17].Type = "[]matchers.Matcher"
+	TemplateDoc.Fields[17].Note = ""
+	TemplateDoc.Fields[17].Description = "FlowMatchers are matchers evaluated once all the requests of the template have run.\n\nResponses of every request are available prefixed by the protocol and the position\nof the request (e.g. http_1_body, dns_2_answer), by the id of the request if any,\nor unprefixed for the last response. Values of named and internal extractors of\nall the requests are available by their name.\n\nFlow matchers are not evaluated if stop-at-first-match ended the flow before all the\nrequests were run."
+	TemplateDoc.Fields[17].Comments[encoder.LineComment] = "FlowMatchers are matchers evaluated once all the requests of the template have run."
+	TemplateDoc.Fields[18].Name = "flow-matchers-condition"
+	TemplateDoc.Fields[18].Type = "string"
+	TemplateDoc.Fields[18].Note = ""
+	TemplateDoc.Fields[18].Description = "FlowMatchersCondition is the condition between the flow matchers. Default is OR."
+	TemplateDoc.Fields[18].Comments[encoder.LineComment] = "FlowMatchersCondition is the condition between the flow matchers. Default is OR."
+	TemplateDoc.Fields[18].Values = []string{
+		"and",
+		"or",
+	}
+	TemplateDoc.Fields[19].Name = "signature"
+	TemplateDoc.Fields[19].Type = "http.SignatureTypeHolder"
+	TemplateDoc.Fields[19].Note = ""
+	TemplateDoc.Fields[19].Description = "Signature is the request signature method"
+	TemplateDoc.Fields[19].Comments[encoder.LineComment] = "Signature is the request signature method"
+	TemplateDoc.Fields[19].Values = []string{
 		"AWS",
 	}
-	TemplateDoc.Fields[15].Name = "variables"
-	TemplateDoc.Fields[15].Type = "variables.Variable"
-	TemplateDoc.Fields[15].Note = ""
-	TemplateDoc.Fields[15].Description = "Variables contains any variables for the current request."
-	TemplateDoc.Fields[15].Comments[encoder.LineComment] = "Variables contains any variables for the current request."
+	TemplateDoc.Fields[20].Name = "variables"
+	TemplateDoc.Fields[20].Type = "variables.Variable"
+	TemplateDoc.Fields[20].Note = ""
+	TemplateDoc.Fields[20].Description = "Variables contains any variables for the current request.\n\nEnvironment variables can be referenced in the values as {{env \"NAME\"}}\nwhen they are enabled with -env-vars.\n\nVariables can reference each other in any order, they are evaluated after\nthe variables they reference and cycles are reported when the template is\nloaded. Variables only referencing constant values are evaluated once when\nthe template is loaded, the others for every request, so they can reference\nthe payloads and the values extracted by previous requests."
+	TemplateDoc.Fields[20].Comments[encoder.LineComment] = "Variables contains any variables for the current request."
 
 	MODELInfoDoc.Type = "model.Info"
 	MODELInfoDoc.Comments[encoder.LineComment] = " Info contains metadata information about a template"
@@ -393,6 +425,22 @@ func init() {
 			Key:   "body",
 			Value: "HTTP response body received from server (default)",
 		},
+		{
+			Key:   "raw_body",
+			Value: "HTTP response body before decompression (only if compressed)",
+		},
+		{
+			Key:   "raw_response",
+			Value: "Exact bytes received for the request (only with raw-bytes)",
+		},
+		{
+			Key:   "charset",
+			Value: "Charset detected for the HTTP response body (only with transcode-response)",
+		},
+		{
+			Key:   "charset_body",
+			Value: "HTTP response body in its original charset (only if transcoded)",
+		},
 		{
 			Key:   "content_length",
 			Value: "HTTP Response content length",
@@ -417,8 +465,64 @@ func init() {
 			Key:   "headers_from_response",
 			Value: "HTTP response headers in name:value format",
 		},
+		{
+			Key:   "headers",
+			Value: "HTTP response headers as a case-insensitive map (e.g. headers[\"x-powered-by\"])",
+		},
+		{
+			Key:   "ja3",
+			Value: "JA3 fingerprint of the tls client hello sent (only if a ja3 is configured)",
+		},
+		{
+			Key:   "ja3_hash",
+			Value: "MD5 hash of the JA3 fingerprint of the tls client hello sent",
+		},
+		{
+			Key:   "http_version",
+			Value: "HTTP version negotiated for the response (e.g. HTTP/2.0)",
+		},
+		{
+			Key:   "user_agent",
+			Value: "User-Agent sent with the request",
+		},
+		{
+			Key:   "truncated",
+			Value: "True if the response body was truncated to the maximum body size",
+		},
+		{
+			Key:   "rate_limit_delay",
+			Value: "Delay in seconds advertised by the Retry-After or rate limit reset headers of the response (0 if none)",
+		},
+		{
+			Key:   "h2_frames",
+			Value: "HTTP/2 frames sent and received, one per line (only with http2-frames)",
+		},
+		{
+			Key:   "h2_pseudo_headers",
+			Value: "HTTP/2 pseudo-headers of the response in name: value format (e.g. :status: 200)",
+		},
+		{
+			Key:   "h2_request_pseudo_headers",
+			Value: "HTTP/2 pseudo-headers of the request in name: value format",
+		},
+		{
+			Key:   "h2_rst_stream",
+			Value: "Error codes of the HTTP/2 RST_STREAM frames received (e.g. REFUSED_STREAM)",
+		},
+		{
+			Key:   "h2_goaway",
+			Value: "Error codes of the HTTP/2 GOAWAY frames received (e.g. ENHANCE_YOUR_CALM)",
+		},
+		{
+			Key:   "h2_goaway_debug",
+			Value: "Debug data of the HTTP/2 GOAWAY frames received",
+		},
+		{
+			Key:   "h2_stream_error",
+			Value: "HTTP/2 stream or GOAWAY error the request failed with, if any",
+		},
 	}
-	HTTPRequestDoc.Fields = make([]encoder.Doc, 30)
+	HTTPRequestDoc.Fields = make([]encoder.Doc, 38)
 	HTTPRequestDoc.Fields[0].Name = "path"
 	HTTPRequestDoc.Fields[0].Type = "[]string"
 	HTTPRequestDoc.Fields[0].Note = ""
@@ -443,160 +547,211 @@ func init() {
 	HTTPRequestDoc.Fields[3].Note = ""
 	HTTPRequestDoc.Fields[3].Description = "Name is the optional name of the request.\n\nIf a name is specified, all the named request in a template can be matched upon\nin a combined manner allowing multi-request based matchers."
 	HTTPRequestDoc.Fields[3].Comments[encoder.LineComment] = "Name is the optional name of the request."
-	HTTPRequestDoc.Fields[4].Name = "attack"
-	HTTPRequestDoc.Fields[4].Type = "generators.AttackTypeHolder"
+	HTTPRequestDoc.Fields[4].Name = "depends-on"
+	HTTPRequestDoc.Fields[4].Type = "[]string"
 	HTTPRequestDoc.Fields[4].Note = ""
-	HTTPRequestDoc.Fields[4].Description = "Attack is the type of payload combinations to perform.\n\nbatteringram is inserts the same payload into all defined payload positions at once, pitchfork combines multiple payload sets and clusterbomb generates\npermutations and combinations for all payloads."
-	HTTPRequestDoc.Fields[4].Comments[encoder.LineComment] = "Attack is the type of payload combinations to perform."
-	HTTPRequestDoc.Fields[4].Values = []string{
+	HTTPRequestDoc.Fields[4].Description = "DependsOn contains the IDs of the requests of the template this request depends on.\n\nOnce a request of the template declares dependencies, every request runs as soon as the\nrequests it depends on have completed, concurrently with the independent ones. The values\nextracted by the dependencies are available to the request."
+	HTTPRequestDoc.Fields[4].Comments[encoder.LineComment] = "DependsOn contains the IDs of the requests of the template this request depends on."
+
+	HTTPRequestDoc.Fields[4].AddExample("", []string{"login"})
+	HTTPRequestDoc.Fields[5].Name = "attack"
+	HTTPRequestDoc.Fields[5].Type = "generators.AttackTypeHolder"
+	HTTPRequestDoc.Fields[5].Note = ""
+	HTTPRequestDoc.Fields[5].Description = "Attack is the type of payload combinations to perform.\n\nbatteringram is inserts the same payload into all defined payload positions at once, pitchfork combines multiple payload sets and clusterbomb generates\npermutations and combinations for all payloads."
+	HTTPRequestDoc.Fields[5].Comments[encoder.LineComment] = "Attack is the type of payload combinations to perform."
+	HTTPRequestDoc.Fields[5].Values = []string{
 		"batteringram",
 		"pitchfork",
 		"clusterbomb",
 	}
-	HTTPRequestDoc.Fields[5].Name = "method"
-	HTTPRequestDoc.Fields[5].Type = "HTTPMethodTypeHolder"
-	HTTPRequestDoc.Fields[5].Note = ""
-	HTTPRequestDoc.Fields[5].Description = "Method is the HTTP Request Method."
-	HTTPRequestDoc.Fields[5].Comments[encoder.LineComment] = "Method is the HTTP Request Method."
-	HTTPRequestDoc.Fields[6].Name = "body"
-	HTTPRequestDoc.Fields[6].Type = "string"
+	HTTPRequestDoc.Fields[6].Name = "method"
+	HTTPRequestDoc.Fields[6].Type = "HTTPMethodTypeHolder"
 	HTTPRequestDoc.Fields[6].Note = ""
-	HTTPRequestDoc.Fields[6].Description = "Body is an optional parameter which contains HTTP Request body."
-	HTTPRequestDoc.Fields[6].Comments[encoder.LineComment] = "Body is an optional parameter which contains HTTP Request body."
-
-	HTTPRequestDoc.Fields[6].AddExample("Same Body for a Login POST request", "username=test&password=test")
-	HTTPRequestDoc.Fields[7].Name = "payloads"
-	HTTPRequestDoc.Fields[7].Type = "map[string]interface{}"
+	HTTPRequestDoc.Fields[6].Description = "Method is the HTTP Request Method."
+	HTTPRequestDoc.Fields[6].Comments[encoder.LineComment] = "Method is the HTTP Request Method."
+	HTTPRequestDoc.Fields[7].Name = "body"
+	HTTPRequestDoc.Fields[7].Type = "string"
 	HTTPRequestDoc.Fields[7].Note = ""
-	HTTPRequestDoc.Fields[7].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time."
-	HTTPRequestDoc.Fields[7].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
-	HTTPRequestDoc.Fields[8].Name = "headers"
-	HTTPRequestDoc.Fields[8].Type = "map[string]string"
-	HTTPRequestDoc.Fields[8].Note = ""
-	HTTPRequestDoc.Fields[8].Description = "Headers contains HTTP Headers to send with the request."
-	HTTPRequestDoc.Fields[8].Comments[encoder.LineComment] = "Headers contains HTTP Headers to send with the request."
+	HTTPRequestDoc.Fields[7].Description = "Body is an optional parameter which contains HTTP Request body."
+	HTTPRequestDoc.Fields[7].Comments[encoder.LineComment] = "Body is an optional parameter which contains HTTP Request body."
 
-	HTTPRequestDoc.Fields[8].AddExample("", map[string]string{"Content-Type": "application/x-www-form-urlencoded", "Content-Length": "1", "Any-Header": "Any-Value"})
-	HTTPRequestDoc.Fields[9].Name = "race_count"
-	HTTPRequestDoc.Fields[9].Type = "int"
+	HTTPRequestDoc.Fields[7].AddExample("Same Body for a Login POST request", "username=test&password=test")
+	HTTPRequestDoc.Fields[8].Name = "payloads"
+	HTTPRequestDoc.Fields[8].Type = "map[string]interface{}"
+	HTTPRequestDoc.Fields[8].Note = ""
+	HTTPRequestDoc.Fields[8].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time.\n\nPayloads can also be generated lazily from a map naming a value generator:\nrange (start, end, step, format), date (start, end, step, format) or\ncharset (charset, min, max), bounded by an optional limit of values."
+	HTTPRequestDoc.Fields[8].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
+	HTTPRequestDoc.Fields[9].Name = "headers"
+	HTTPRequestDoc.Fields[9].Type = "map[string]string"
 	HTTPRequestDoc.Fields[9].Note = ""
-	HTTPRequestDoc.Fields[9].Description = "RaceCount is the number of times to send a request in Race Condition Attack."
-	HTTPRequestDoc.Fields[9].Comments[encoder.LineComment] = "RaceCount is the number of times to send a request in Race Condition Attack."
+	HTTPRequestDoc.Fields[9].Description = "Headers contains HTTP Headers to send with the request."
+	HTTPRequestDoc.Fields[9].Comments[encoder.LineComment] = "Headers contains HTTP Headers to send with the request."
 
-	HTTPRequestDoc.Fields[9].AddExample("Send a request 5 times", 5)
-	HTTPRequestDoc.Fields[10].Name = "max-redirects"
-	HTTPRequestDoc.Fields[10].Type = "int"
+	HTTPRequestDoc.Fields[9].AddExample("", map[string]string{"Content-Type": "application/x-www-form-urlencoded", "Content-Length": "1", "Any-Header": "Any-Value"})
+	HTTPRequestDoc.Fields[10].Name = "computed-headers"
+	HTTPRequestDoc.Fields[10].Type = "map[string]string"
 	HTTPRequestDoc.Fields[10].Note = ""
-	HTTPRequestDoc.Fields[10].Description = "MaxRedirects is the maximum number of redirects that should be followed."
-	HTTPRequestDoc.Fields[10].Comments[encoder.LineComment] = "MaxRedirects is the maximum number of redirects that should be followed."
+	HTTPRequestDoc.Fields[10].Description = "ComputedHeaders contains HTTP Headers computed with DSL expressions right before the request is sent.\n\nBesides the template variables and extracted values, the expressions have access to the fields\nof the outgoing request: `request_method`, `request_url`, `request_host`, `request_path`,\n`request_query`, `request_body`, `request_headers` (case-insensitive map), `request_timestamp`\n(unix seconds), `request_date` (http format) and `request_amz_date` (e.g. 20060102T150405Z),\nwhich allows request signing schemes. The timestamp is the same for all the computed headers,\nwhich don't see each other and override the headers of the request."
+	HTTPRequestDoc.Fields[10].Comments[encoder.LineComment] = "ComputedHeaders contains HTTP Headers computed with DSL expressions right before the request is sent."
 
-	HTTPRequestDoc.Fields[10].AddExample("Follow up to 5 redirects", 5)
-	HTTPRequestDoc.Fields[11].Name = "pipeline-concurrent-connections"
+	HTTPRequestDoc.Fields[10].AddExample("", map[string]string{"X-Timestamp": "{{request_timestamp}}", "X-Signature": "{{hmac('sha256', request_method + request_path + request_timestamp, secret)}}"})
+	HTTPRequestDoc.Fields[11].Name = "race_count"
 	HTTPRequestDoc.Fields[11].Type = "int"
 	HTTPRequestDoc.Fields[11].Note = ""
-	HTTPRequestDoc.Fields[11].Description = "PipelineConcurrentConnections is number of connections to create during pipelining."
-	HTTPRequestDoc.Fields[11].Comments[encoder.LineComment] = "PipelineConcurrentConnections is number of connections to create during pipelining."
+	HTTPRequestDoc.Fields[11].Description = "RaceCount is the number of times to send a request in Race Condition Attack."
+	HTTPRequestDoc.Fields[11].Comments[encoder.LineComment] = "RaceCount is the number of times to send a request in Race Condition Attack."
 
-	HTTPRequestDoc.Fields[11].AddExample("Create 40 concurrent connections", 40)
-	HTTPRequestDoc.Fields[12].Name = "pipeline-requests-per-connection"
+	HTTPRequestDoc.Fields[11].AddExample("Send a request 5 times", 5)
+	HTTPRequestDoc.Fields[12].Name = "max-redirects"
 	HTTPRequestDoc.Fields[12].Type = "int"
 	HTTPRequestDoc.Fields[12].Note = ""
-	HTTPRequestDoc.Fields[12].Description = "PipelineRequestsPerConnection is number of requests to send per connection when pipelining."
-	HTTPRequestDoc.Fields[12].Comments[encoder.LineComment] = "PipelineRequestsPerConnection is number of requests to send per connection when pipelining."
+	HTTPRequestDoc.Fields[12].Description = "MaxRedirects is the maximum number of redirects that should be followed."
+	HTTPRequestDoc.Fields[12].Comments[encoder.LineComment] = "MaxRedirects is the maximum number of redirects that should be followed."
 
-	HTTPRequestDoc.Fields[12].AddExample("Send 100 requests per pipeline connection", 100)
-	HTTPRequestDoc.Fields[13].Name = "threads"
+	HTTPRequestDoc.Fields[12].AddExample("Follow up to 5 redirects", 5)
+	HTTPRequestDoc.Fields[13].Name = "pipeline-concurrent-connections"
 	HTTPRequestDoc.Fields[13].Type = "int"
 	HTTPRequestDoc.Fields[13].Note = ""
-	HTTPRequestDoc.Fields[13].Description = "Threads specifies number of threads to use sending requests. This enables Connection Pooling.\n\nConnection: Close attribute must not be used in request while using threads flag, otherwise\npooling will fail and engine will continue to close connections after requests."
-	HTTPRequestDoc.Fields[13].Comments[encoder.LineComment] = "Threads specifies number of threads to use sending requests. This enables Connection Pooling."
+	HTTPRequestDoc.Fields[13].Description = "PipelineConcurrentConnections is number of connections to create during pipelining."
+	HTTPRequestDoc.Fields[13].Comments[encoder.LineComment] = "PipelineConcurrentConnections is number of connections to create during pipelining."
 
-	HTTPRequestDoc.Fields[13].AddExample("Send requests using 10 concurrent threads", 10)
-	HTTPRequestDoc.Fields[14].Name = "max-size"
+	HTTPRequestDoc.Fields[13].AddExample("Create 40 concurrent connections", 40)
+	HTTPRequestDoc.Fields[14].Name = "pipeline-requests-per-connection"
 	HTTPRequestDoc.Fields[14].Type = "int"
 	HTTPRequestDoc.Fields[14].Note = ""
-	HTTPRequestDoc.Fields[14].Description = "MaxSize is the maximum size of http response body to read in bytes."
-	HTTPRequestDoc.Fields[14].Comments[encoder.LineComment] = "MaxSize is the maximum size of http response body to read in bytes."
+	HTTPRequestDoc.Fields[14].Description = "PipelineRequestsPerConnection is number of requests to send per connection when pipelining."
+	HTTPRequestDoc.Fields[14].Comments[encoder.LineComment] = "PipelineRequestsPerConnection is number of requests to send per connection when pipelining."
 
-	HTTPRequestDoc.Fields[14].AddExample("Read max 2048 bytes of the response", 2048)
-	HTTPRequestDoc.Fields[15].Name = "fuzzing"
-	HTTPRequestDoc.Fields[15].Type = "[]fuzz.Rule"
+	HTTPRequestDoc.Fields[14].AddExample("Send 100 requests per pipeline connection", 100)
+	HTTPRequestDoc.Fields[15].Name = "threads"
+	HTTPRequestDoc.Fields[15].Type = "int"
 	HTTPRequestDoc.Fields[15].Note = ""
-	HTTPRequestDoc.Fields[15].Description = "Fuzzing describes schema to fuzz http requests"
-	HTTPRequestDoc.Fields[15].Comments[encoder.LineComment] = " Fuzzing describes schema to fuzz http requests"
-	HTTPRequestDoc.Fields[16].Name = "signature"
-	HTTPRequestDoc.Fields[16].Type = "SignatureTypeHolder"
+	HTTPRequestDoc.Fields[15].Description = "Threads specifies number of threads to use sending requests. This enables Connection Pooling.\n\nConnection: Close attribute must not be used in request while using threads flag, otherwise\npooling will fail and engine will continue to close connections after requests."
+	HTTPRequestDoc.Fields[15].Comments[encoder.LineComment] = "Threads specifies number of threads to use sending requests. This enables Connection Pooling."
+
+	HTTPRequestDoc.Fields[15].AddExample("Send requests using 10 concurrent threads", 10)
+	HTTPRequestDoc.Fields[16].Name = "max-size"
+	HTTPRequestDoc.Fields[16].Type = "int"
 	HTTPRequestDoc.Fields[16].Note = ""
-	HTTPRequestDoc.Fields[16].Description = "Signature is the request signature method"
-	HTTPRequestDoc.Fields[16].Comments[encoder.LineComment] = "Signature is the request signature method"
-	HTTPRequestDoc.Fields[16].Values = []string{
-		"AWS",
-	}
-	HTTPRequestDoc.Fields[17].Name = "cookie-reuse"
-	HTTPRequestDoc.Fields[17].Type = "bool"
+	HTTPRequestDoc.Fields[16].Description = "MaxSize is the maximum size of http response body to read in bytes."
+	HTTPRequestDoc.Fields[16].Comments[encoder.LineComment] = "MaxSize is the maximum size of http response body to read in bytes."
+
+	HTTPRequestDoc.Fields[16].AddExample("Read max 2048 bytes of the response", 2048)
+	HTTPRequestDoc.Fields[17].Name = "fuzzing"
+	HTTPRequestDoc.Fields[17].Type = "[]fuzz.Rule"
 	HTTPRequestDoc.Fields[17].Note = ""
-	HTTPRequestDoc.Fields[17].Description = "CookieReuse is an optional setting that enables cookie reuse for\nall requests defined in raw section."
-	HTTPRequestDoc.Fields[17].Comments[encoder.LineComment] = "CookieReuse is an optional setting that enables cookie reuse for"
-	HTTPRequestDoc.Fields[18].Name = "read-all"
-	HTTPRequestDoc.Fields[18].Type = "bool"
+	HTTPRequestDoc.Fields[17].Description = "Fuzzing describes schema to fuzz http requests"
+	HTTPRequestDoc.Fields[17].Comments[encoder.LineComment] = " Fuzzing describes schema to fuzz http requests"
+	HTTPRequestDoc.Fields[18].Name = "signature"
+	HTTPRequestDoc.Fields[18].Type = "SignatureTypeHolder"
 	HTTPRequestDoc.Fields[18].Note = ""
-	HTTPRequestDoc.Fields[18].Description = "Enables force reading of the entire raw unsafe request body ignoring\nany specified content length headers."
-	HTTPRequestDoc.Fields[18].Comments[encoder.LineComment] = "Enables force reading of the entire raw unsafe request body ignoring"
-	HTTPRequestDoc.Fields[19].Name = "redirects"
+	HTTPRequestDoc.Fields[18].Description = "Signature is the request signature method"
+	HTTPRequestDoc.Fields[18].Comments[encoder.LineComment] = "Signature is the request signature method"
+	HTTPRequestDoc.Fields[18].Values = []string{
+		"AWS",
+	}
+	HTTPRequestDoc.Fields[19].Name = "cookie-reuse"
 	HTTPRequestDoc.Fields[19].Type = "bool"
 	HTTPRequestDoc.Fields[19].Note = ""
-	HTTPRequestDoc.Fields[19].Description = "Redirects specifies whether redirects should be followed by the HTTP Client.\n\nThis can be used in conjunction with `max-redirects` to control the HTTP request redirects."
-	HTTPRequestDoc.Fields[19].Comments[encoder.LineComment] = "Redirects specifies whether redirects should be followed by the HTTP Client."
-	HTTPRequestDoc.Fields[20].Name = "host-redirects"
+	HTTPRequestDoc.Fields[19].Description = "CookieReuse is an optional setting that enables cookie reuse for\nall requests defined in raw section."
+	HTTPRequestDoc.Fields[19].Comments[encoder.LineComment] = "CookieReuse is an optional setting that enables cookie reuse for"
+	HTTPRequestDoc.Fields[20].Name = "read-all"
 	HTTPRequestDoc.Fields[20].Type = "bool"
 	HTTPRequestDoc.Fields[20].Note = ""
-	HTTPRequestDoc.Fields[20].Description = "Redirects specifies whether only redirects to the same host should be followed by the HTTP Client.\n\nThis can be used in conjunction with `max-redirects` to control the HTTP request redirects."
-	HTTPRequestDoc.Fields[20].Comments[encoder.LineComment] = "Redirects specifies whether only redirects to the same host should be followed by the HTTP Client."
-	HTTPRequestDoc.Fields[21].Name = "pipeline"
+	HTTPRequestDoc.Fields[20].Description = "Enables force reading of the entire raw unsafe request body ignoring\nany specified content length headers."
+	HTTPRequestDoc.Fields[20].Comments[encoder.LineComment] = "Enables force reading of the entire raw unsafe request body ignoring"
+	HTTPRequestDoc.Fields[21].Name = "redirects"
 	HTTPRequestDoc.Fields[21].Type = "bool"
 	HTTPRequestDoc.Fields[21].Note = ""
-	HTTPRequestDoc.Fields[21].Description = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining\n\nAll requests must be idempotent (GET/POST). This can be used for race conditions/billions requests."
-	HTTPRequestDoc.Fields[21].Comments[encoder.LineComment] = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining"
-	HTTPRequestDoc.Fields[22].Name = "unsafe"
+	HTTPRequestDoc.Fields[21].Description = "Redirects specifies whether redirects should be followed by the HTTP Client.\n\nThis can be used in conjunction with `max-redirects` to control the HTTP request redirects."
+	HTTPRequestDoc.Fields[21].Comments[encoder.LineComment] = "Redirects specifies whether redirects should be followed by the HTTP Client."
+	HTTPRequestDoc.Fields[22].Name = "host-redirects"
 	HTTPRequestDoc.Fields[22].Type = "bool"
 	HTTPRequestDoc.Fields[22].Note = ""
-	HTTPRequestDoc.Fields[22].Description = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests.\n\nThis uses the [rawhttp](https://github.com/projectdiscovery/rawhttp) engine to achieve complete\ncontrol over the request, with no normalization performed by the client."
-	HTTPRequestDoc.Fields[22].Comments[encoder.LineComment] = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests."
-	HTTPRequestDoc.Fields[23].Name = "race"
+	HTTPRequestDoc.Fields[22].Description = "Redirects specifies whether only redirects to the same host should be followed by the HTTP Client.\n\nThis can be used in conjunction with `max-redirects` to control the HTTP request redirects."
+	HTTPRequestDoc.Fields[22].Comments[encoder.LineComment] = "Redirects specifies whether only redirects to the same host should be followed by the HTTP Client."
+	HTTPRequestDoc.Fields[23].Name = "pipeline"
 	HTTPRequestDoc.Fields[23].Type = "bool"
 	HTTPRequestDoc.Fields[23].Note = ""
-	HTTPRequestDoc.Fields[23].Description = "Race determines if all the request have to be attempted at the same time (Race Condition)\n\nThe actual number of requests that will be sent is determined by the `race_count`  field."
-	HTTPRequestDoc.Fields[23].Comments[encoder.LineComment] = "Race determines if all the request have to be attempted at the same time (Race Condition)"
-	HTTPRequestDoc.Fields[24].Name = "req-condition"
+	HTTPRequestDoc.Fields[23].Description = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining\n\nAll requests must be idempotent (GET/POST). This can be used for race conditions/billions requests."
+	HTTPRequestDoc.Fields[23].Comments[encoder.LineComment] = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining"
+	HTTPRequestDoc.Fields[24].Name = "unsafe"
 	HTTPRequestDoc.Fields[24].Type = "bool"
 	HTTPRequestDoc.Fields[24].Note = ""
-	HTTPRequestDoc.Fields[24].Description = "ReqCondition automatically assigns numbers to requests and preserves their history.\n\nThis allows matching on them later for multi-request conditions."
-	HTTPRequestDoc.Fields[24].Comments[encoder.LineComment] = "ReqCondition automatically assigns numbers to requests and preserves their history."
-	HTTPRequestDoc.Fields[25].Name = "stop-at-first-match"
+	HTTPRequestDoc.Fields[24].Description = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests.\n\nThis uses the [rawhttp](https://github.com/projectdiscovery/rawhttp) engine to achieve complete\ncontrol over the request, with no normalization performed by the client."
+	HTTPRequestDoc.Fields[24].Comments[encoder.LineComment] = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests."
+	HTTPRequestDoc.Fields[25].Name = "raw-bytes"
 	HTTPRequestDoc.Fields[25].Type = "bool"
 	HTTPRequestDoc.Fields[25].Note = ""
-	HTTPRequestDoc.Fields[25].Description = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
-	HTTPRequestDoc.Fields[25].Comments[encoder.LineComment] = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
-	HTTPRequestDoc.Fields[26].Name = "skip-variables-check"
+	HTTPRequestDoc.Fields[25].Description = "RawBytes sends the unsafe raw requests byte for byte over plaintext and tls\nconnections, for request smuggling and header injection tests.\n\nLine endings are not converted to CRLF, the path of the input is not merged\nwith the request path and the custom headers are not inserted, so that malformed\ncontent-length and transfer-encoding headers or bare CRs are sent as written.\nThe exact bytes received are available as `raw_response`, including the data\nfollowing the response until the connection is closed with `read-all`.\nRedirects are not followed."
+	HTTPRequestDoc.Fields[25].Comments[encoder.LineComment] = "RawBytes sends the unsafe raw requests byte for byte over plaintext and tls"
+	HTTPRequestDoc.Fields[26].Name = "race"
 	HTTPRequestDoc.Fields[26].Type = "bool"
 	HTTPRequestDoc.Fields[26].Note = ""
-	HTTPRequestDoc.Fields[26].Description = "SkipVariablesCheck skips the check for unresolved variables in request"
-	HTTPRequestDoc.Fields[26].Comments[encoder.LineComment] = "SkipVariablesCheck skips the check for unresolved variables in request"
-	HTTPRequestDoc.Fields[27].Name = "iterate-all"
+	HTTPRequestDoc.Fields[26].Description = "Race determines if all the request have to be attempted at the same time (Race Condition)\n\nThe actual number of requests that will be sent is determined by the `race_count`  field."
+	HTTPRequestDoc.Fields[26].Comments[encoder.LineComment] = "Race determines if all the request have to be attempted at the same time (Race Condition)"
+	HTTPRequestDoc.Fields[27].Name = "req-condition"
 	HTTPRequestDoc.Fields[27].Type = "bool"
 	HTTPRequestDoc.Fields[27].Note = ""
-	HTTPRequestDoc.Fields[27].Description = "IterateAll iterates all the values extracted from internal extractors"
-	HTTPRequestDoc.Fields[27].Comments[encoder.LineComment] = "IterateAll iterates all the values extracted from internal extractors"
-	HTTPRequestDoc.Fields[28].Name = "digest-username"
-	HTTPRequestDoc.Fields[28].Type = "string"
+	HTTPRequestDoc.Fields[27].Description = "ReqCondition automatically assigns numbers to requests and preserves their history.\n\nThis allows matching on them later for multi-request conditions."
+	HTTPRequestDoc.Fields[27].Comments[encoder.LineComment] = "ReqCondition automatically assigns numbers to requests and preserves their history."
+	HTTPRequestDoc.Fields[28].Name = "stop-at-first-match"
+	HTTPRequestDoc.Fields[28].Type = "bool"
 	HTTPRequestDoc.Fields[28].Note = ""
-	HTTPRequestDoc.Fields[28].Description = "DigestAuthUsername specifies the username for digest authentication"
-	HTTPRequestDoc.Fields[28].Comments[encoder.LineComment] = "DigestAuthUsername specifies the username for digest authentication"
-	HTTPRequestDoc.Fields[29].Name = "digest-password"
-	HTTPRequestDoc.Fields[29].Type = "string"
+	HTTPRequestDoc.Fields[28].Description = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
+	HTTPRequestDoc.Fields[28].Comments[encoder.LineComment] = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
+	HTTPRequestDoc.Fields[29].Name = "skip-variables-check"
+	HTTPRequestDoc.Fields[29].Type = "bool"
 	HTTPRequestDoc.Fields[29].Note = ""
-	HTTPRequestDoc.Fields[29].Description = "DigestAuthPassword specifies the password for digest authentication"
-	HTTPRequestDoc.Fields[29].Comments[encoder.LineComment] = "DigestAuthPassword specifies the password for digest authentication"
+	HTTPRequestDoc.Fields[29].Description = "SkipVariablesCheck skips the check for unresolved variables in request"
+	HTTPRequestDoc.Fields[29].Comments[encoder.LineComment] = "SkipVariablesCheck skips the check for unresolved variables in request"
+	HTTPRequestDoc.Fields[30].Name = "iterate-all"
+	HTTPRequestDoc.Fields[30].Type = "bool"
+	HTTPRequestDoc.Fields[30].Note = ""
+	HTTPRequestDoc.Fields[30].Description = "IterateAll iterates all the values extracted from internal extractors"
+	HTTPRequestDoc.Fields[30].Comments[encoder.LineComment] = "IterateAll iterates all the values extracted from internal extractors"
+	HTTPRequestDoc.Fields[31].Name = "digest-username"
+	HTTPRequestDoc.Fields[31].Type = "string"
+	HTTPRequestDoc.Fields[31].Note = ""
+	HTTPRequestDoc.Fields[31].Description = "DigestAuthUsername specifies the username for digest authentication"
+	HTTPRequestDoc.Fields[31].Comments[encoder.LineComment] = "DigestAuthUsername specifies the username for digest authentication"
+	HTTPRequestDoc.Fields[32].Name = "digest-password"
+	HTTPRequestDoc.Fields[32].Type = "string"
+	HTTPRequestDoc.Fields[32].Note = ""
+	HTTPRequestDoc.Fields[32].Description = "DigestAuthPassword specifies the password for digest authentication"
+	HTTPRequestDoc.Fields[32].Comments[encoder.LineComment] = "DigestAuthPassword specifies the password for digest authentication"
+	HTTPRequestDoc.Fields[33].Name = "ja3"
+	HTTPRequestDoc.Fields[33].Type = "string"
+	HTTPRequestDoc.Fields[33].Note = ""
+	HTTPRequestDoc.Fields[33].Description = "JA3 is the ja3 fingerprint to use for the tls client hello of the requests.\n\nCiphers, extensions and curves are sent in the order of the fingerprint. Overrides\nthe global ja3 option. Unsafe raw requests and requests made through http proxies\nuse the default client hello."
+	HTTPRequestDoc.Fields[33].Comments[encoder.LineComment] = "JA3 is the ja3 fingerprint to use for the tls client hello of the requests."
+
+	HTTPRequestDoc.Fields[33].AddExample("", "771,4865-4866-4867-49195-49199,0-23-65281-10-11-35-16-5-13-51-45-43,29-23-24,0")
+	HTTPRequestDoc.Fields[34].Name = "http-version"
+	HTTPRequestDoc.Fields[34].Type = "string"
+	HTTPRequestDoc.Fields[34].Note = ""
+	HTTPRequestDoc.Fields[34].Description = "HTTPVersion forces the http version used for the requests - negotiated if not specified.\n\nWith http2, https urls negotiate h2 with alpn and http urls use cleartext h2c with\nprior knowledge. http3 requests are sent over QUIC and require https urls.\nThe negotiated version is available as the `http_version` variable. The global ja3\noption only applies to http1 requests."
+	HTTPRequestDoc.Fields[34].Comments[encoder.LineComment] = "HTTPVersion forces the http version used for the requests - negotiated if not specified."
+	HTTPRequestDoc.Fields[34].Values = []string{
+		"http1",
+		"http2",
+		"http3",
+	}
+	HTTPRequestDoc.Fields[35].Name = "http-version-fallback"
+	HTTPRequestDoc.Fields[35].Type = "bool"
+	HTTPRequestDoc.Fields[35].Note = ""
+	HTTPRequestDoc.Fields[35].Description = "HTTPVersionFallback retries the requests with the negotiated http version\nif the server doesn't support the forced http version."
+	HTTPRequestDoc.Fields[35].Comments[encoder.LineComment] = "HTTPVersionFallback retries the requests with the negotiated http version"
+	HTTPRequestDoc.Fields[36].Name = "http2-frames"
+	HTTPRequestDoc.Fields[36].Type = "bool"
+	HTTPRequestDoc.Fields[36].Note = ""
+	HTTPRequestDoc.Fields[36].Description = "HTTP2Frames records the frames of the http2 connection of the requests to match\non stream-level details, such as the behaviour of servers vulnerable to http2 DoS patterns.\n\nThe requests are sent on a dedicated connection so that all its frames belong to\nthem. The frames are available as `h2_frames`, the pseudo-headers as `h2_pseudo_headers`\nand `h2_request_pseudo_headers`, and the error codes of the RST_STREAM and GOAWAY\nframes received as `h2_rst_stream` and `h2_goaway`. Requests failing with a stream\nreset or a GOAWAY are still matched, with the error as `h2_stream_error`.\nRequires the http2 http-version."
+	HTTPRequestDoc.Fields[36].Comments[encoder.LineComment] = "HTTP2Frames records the frames of the http2 connection of the requests to match"
+	HTTPRequestDoc.Fields[37].Name = "respect-rate-headers"
+	HTTPRequestDoc.Fields[37].Type = "bool"
+	HTTPRequestDoc.Fields[37].Note = ""
+	HTTPRequestDoc.Fields[37].Description = "RespectRateHeaders delays the next requests to a host by the delay advertised by the\nrate limit headers of its responses.\n\nRetry-After (in seconds or as an http date) takes precedence over the X-RateLimit-Reset\nand RateLimit-Reset headers, which are only honored once the remaining requests are exhausted.\nThe delay is capped by the retry-backoff-max option and available as the `rate_limit_delay` variable."
+	HTTPRequestDoc.Fields[37].Comments[encoder.LineComment] = "RespectRateHeaders delays the next requests to a host by the delay advertised by the"
 
 	GENERATORSAttackTypeHolderDoc.Type = "generators.AttackTypeHolder"
 	GENERATORSAttackTypeHolderDoc.Comments[encoder.LineComment] = " AttackTypeHolder is used to hold internal type of the protocol"
@@ -937,6 +1092,10 @@ func init() {
 			Key:   "raw,body,all,data",
 			Value: "Raw contains the raw file contents",
 		},
+		{
+			Key:   "truncated",
+			Value: "True if the file was larger than the maximum size processed",
+		},
 	}
 	FILERequestDoc.Fields = make([]encoder.Doc, 5)
 	FILERequestDoc.Fields[0].Name = "extensions"
@@ -1023,8 +1182,20 @@ func init() {
 			Key:   "raw",
 			Value: "Full Network protocol data",
 		},
+		{
+			Key:   "ja3",
+			Value: "JA3 fingerprint of the tls client hello sent (only if a ja3 is configured)",
+		},
+		{
+			Key:   "ja3_hash",
+			Value: "MD5 hash of the JA3 fingerprint of the tls client hello sent",
+		},
+		{
+			Key:   "truncated",
+			Value: "True if the response was truncated to the maximum body size",
+		},
 	}
-	NETWORKRequestDoc.Fields = make([]encoder.Doc, 7)
+	NETWORKRequestDoc.Fields = make([]encoder.Doc, 8)
 	NETWORKRequestDoc.Fields[0].Name = "id"
 	NETWORKRequestDoc.Fields[0].Type = "string"
 	NETWORKRequestDoc.Fields[0].Note = ""
@@ -1033,7 +1204,7 @@ func init() {
 	NETWORKRequestDoc.Fields[1].Name = "host"
 	NETWORKRequestDoc.Fields[1].Type = "[]string"
 	NETWORKRequestDoc.Fields[1].Note = ""
-	NETWORKRequestDoc.Fields[1].Description = "Host to send network requests to.\n\nUsually it's set to `{{Hostname}}`. If you want to enable TLS for\nTCP Connection, you can use `tls://{{Hostname}}`."
+	NETWORKRequestDoc.Fields[1].Description = "Host to send network requests to.\n\nUsually it's set to `{{Hostname}}`. If you want to enable TLS for\nTCP Connection, you can use `tls://{{Hostname}}`.\n\nUnix domain sockets can be used with `unix://` followed by the path\nof the socket, e.g. `unix:///var/run/docker.sock`. For inputs in the\n`unix:///path/to/socket` format, `unix://{{Hostname}}` connects to the input socket."
 	NETWORKRequestDoc.Fields[1].Comments[encoder.LineComment] = "Host to send network requests to."
 
 	NETWORKRequestDoc.Fields[1].AddExample("", []string{"{{Hostname}}"})
@@ -1045,7 +1216,7 @@ func init() {
 	NETWORKRequestDoc.Fields[3].Name = "payloads"
 	NETWORKRequestDoc.Fields[3].Type = "map[string]interface{}"
 	NETWORKRequestDoc.Fields[3].Note = ""
-	NETWORKRequestDoc.Fields[3].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time."
+	NETWORKRequestDoc.Fields[3].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time.\n\nPayloads can also be generated lazily from a map naming a value generator:\nrange (start, end, step, format), date (start, end, step, format) or\ncharset (charset, min, max), bounded by an optional limit of values."
 	NETWORKRequestDoc.Fields[3].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
 	NETWORKRequestDoc.Fields[4].Name = "inputs"
 	NETWORKRequestDoc.Fields[4].Type = "[]network.Input"
@@ -1066,6 +1237,13 @@ func init() {
 	NETWORKRequestDoc.Fields[6].Comments[encoder.LineComment] = "ReadAll determines if the data stream should be read till the end regardless of the size"
 
 	NETWORKRequestDoc.Fields[6].AddExample("", false)
+	NETWORKRequestDoc.Fields[7].Name = "ja3"
+	NETWORKRequestDoc.Fields[7].Type = "string"
+	NETWORKRequestDoc.Fields[7].Note = ""
+	NETWORKRequestDoc.Fields[7].Description = "JA3 is the ja3 fingerprint to use for the client hello of `tls://` connections.\n\nOverrides the global ja3 option."
+	NETWORKRequestDoc.Fields[7].Comments[encoder.LineComment] = "JA3 is the ja3 fingerprint to use for the client hello of `tls://` connections."
+
+	NETWORKRequestDoc.Fields[7].AddExample("", "771,4865-4866-4867-49195-49199,0-23-65281-10-11-35-16-5-13-51-45-43,29-23-24,0")
 
 	NETWORKInputDoc.Type = "network.Input"
 	NETWORKInputDoc.Comments[encoder.LineComment] = ""
@@ -1172,8 +1350,52 @@ func init() {
 			Key:   "resp,body,data",
 			Value: "Headless response received from client (default)",
 		},
+		{
+			Key:   "outgoing_requests",
+			Value: "Method, URL and body of the fetch/XHR requests made by the page",
+		},
+		{
+			Key:   "referrers",
+			Value: "URL and Referer header of the requests made by the page with a referrer",
+		},
+		{
+			Key:   "headers",
+			Value: "Response headers of the last loaded document as a case-insensitive map (e.g. headers[\"x-powered-by\"])",
+		},
+		{
+			Key:   "resources",
+			Value: "Number of resources loaded by the page, also available by lowercase resource type (e.g. resources_script)",
+		},
+		{
+			Key:   "third_party_resources",
+			Value: "Number of resources loaded from third-party domains, also available by resource type (e.g. third_party_resources_script)",
+		},
+		{
+			Key:   "third_party_domains",
+			Value: "Number of distinct third-party domains resources were loaded from",
+		},
+		{
+			Key:   "set_cookies",
+			Value: "JSON array of the cookies set by the Set-Cookie headers of every response with their url, parsed attributes and cleared and third_party flags",
+		},
+		{
+			Key:   "websocket_frames",
+			Value: "JSON array of the websocket frames recorded by the websocket actions with their url, direction, data and original data if modified",
+		},
+		{
+			Key:   "user_agent",
+			Value: "User-Agent set for the page (only if custom or rotated)",
+		},
+		{
+			Key:   "truncated",
+			Value: "True if the page html or a response body of the history was truncated to the maximum body size",
+		},
+		{
+			Key:   "final_url",
+			Value: "URL of the page once the actions were executed, after server and client-side redirects",
+		},
 	}
-	HEADLESSRequestDoc.Fields = make([]encoder.Doc, 7)
+	HEADLESSRequestDoc.Fields = make([]encoder.Doc, 8)
 	HEADLESSRequestDoc.Fields[0].Name = "id"
 	HEADLESSRequestDoc.Fields[0].Type = "string"
 	HEADLESSRequestDoc.Fields[0].Note = ""
@@ -1187,28 +1409,35 @@ func init() {
 	HEADLESSRequestDoc.Fields[2].Name = "payloads"
 	HEADLESSRequestDoc.Fields[2].Type = "map[string]interface{}"
 	HEADLESSRequestDoc.Fields[2].Note = ""
-	HEADLESSRequestDoc.Fields[2].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time."
+	HEADLESSRequestDoc.Fields[2].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time.\n\nPayloads can also be generated lazily from a map naming a value generator:\nrange (start, end, step, format), date (start, end, step, format) or\ncharset (charset, min, max), bounded by an optional limit of values."
 	HEADLESSRequestDoc.Fields[2].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
-	HEADLESSRequestDoc.Fields[3].Name = "steps"
-	HEADLESSRequestDoc.Fields[3].Type = "[]engine.Action"
+	HEADLESSRequestDoc.Fields[3].Name = "max-combinations"
+	HEADLESSRequestDoc.Fields[3].Type = "int"
 	HEADLESSRequestDoc.Fields[3].Note = ""
-	HEADLESSRequestDoc.Fields[3].Description = "Steps is the list of actions to run for headless request"
-	HEADLESSRequestDoc.Fields[3].Comments[encoder.LineComment] = "Steps is the list of actions to run for headless request"
-	HEADLESSRequestDoc.Fields[4].Name = "user_agent"
-	HEADLESSRequestDoc.Fields[4].Type = "userAgent.UserAgentHolder"
+	HEADLESSRequestDoc.Fields[3].Description = "MaxCombinations is the maximum number of payload combinations the steps are run with\nfor an input, the remaining combinations being skipped. Default is 0 (no limit).\n\nThe steps are run once per combination, each one reported with its payload values."
+	HEADLESSRequestDoc.Fields[3].Comments[encoder.LineComment] = "MaxCombinations is the maximum number of payload combinations the steps are run with"
+
+	HEADLESSRequestDoc.Fields[3].AddExample("Try at most 50 payload combinations", 50)
+	HEADLESSRequestDoc.Fields[4].Name = "steps"
+	HEADLESSRequestDoc.Fields[4].Type = "[]engine.Action"
 	HEADLESSRequestDoc.Fields[4].Note = ""
-	HEADLESSRequestDoc.Fields[4].Description = "descriptions: |\n 	 User-Agent is the type of user-agent to use for the request."
-	HEADLESSRequestDoc.Fields[4].Comments[encoder.LineComment] = " descriptions: |"
-	HEADLESSRequestDoc.Fields[5].Name = "custom_user_agent"
-	HEADLESSRequestDoc.Fields[5].Type = "string"
+	HEADLESSRequestDoc.Fields[4].Description = "Steps is the list of actions to run for headless request"
+	HEADLESSRequestDoc.Fields[4].Comments[encoder.LineComment] = "Steps is the list of actions to run for headless request"
+	HEADLESSRequestDoc.Fields[5].Name = "user_agent"
+	HEADLESSRequestDoc.Fields[5].Type = "userAgent.UserAgentHolder"
 	HEADLESSRequestDoc.Fields[5].Note = ""
-	HEADLESSRequestDoc.Fields[5].Description = "description: |\n 	 If UserAgent is set to custom, customUserAgent is the custom user-agent to use for the request."
-	HEADLESSRequestDoc.Fields[5].Comments[encoder.LineComment] = " description: |"
-	HEADLESSRequestDoc.Fields[6].Name = "stop-at-first-match"
-	HEADLESSRequestDoc.Fields[6].Type = "bool"
+	HEADLESSRequestDoc.Fields[5].Description = "descriptions: |\n 	 User-Agent is the type of user-agent to use for the request."
+	HEADLESSRequestDoc.Fields[5].Comments[encoder.LineComment] = " descriptions: |"
+	HEADLESSRequestDoc.Fields[6].Name = "custom_user_agent"
+	HEADLESSRequestDoc.Fields[6].Type = "string"
 	HEADLESSRequestDoc.Fields[6].Note = ""
-	HEADLESSRequestDoc.Fields[6].Description = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
-	HEADLESSRequestDoc.Fields[6].Comments[encoder.LineComment] = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
+	HEADLESSRequestDoc.Fields[6].Description = "description: |\n 	 If UserAgent is set to custom, customUserAgent is the custom user-agent to use for the request."
+	HEADLESSRequestDoc.Fields[6].Comments[encoder.LineComment] = " description: |"
+	HEADLESSRequestDoc.Fields[7].Name = "stop-at-first-match"
+	HEADLESSRequestDoc.Fields[7].Type = "bool"
+	HEADLESSRequestDoc.Fields[7].Note = ""
+	HEADLESSRequestDoc.Fields[7].Description = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
+	HEADLESSRequestDoc.Fields[7].Comments[encoder.LineComment] = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
 
 	ENGINEActionDoc.Type = "engine.Action"
 	ENGINEActionDoc.Comments[encoder.LineComment] = " Action is an action taken by the browser to reach a navigation"
@@ -1279,6 +1508,49 @@ func init() {
 		"debug",
 		"sleep",
 		"waitvisible",
+		"elementinfo",
+		"screenshotdiff",
+		"links",
+		"forms",
+		"exportsession",
+		"importsession",
+		"setreferrer",
+		"waitresponse",
+		"paste",
+		"jsendpoints",
+		"screenshotelement",
+		"setcookie",
+		"mockresponse",
+		"serviceworkers",
+		"unload",
+		"computedstyle",
+		"evalonnewdocument",
+		"mixedcontent",
+		"domhash",
+		"setorigin",
+		"switchtab",
+		"openredirect",
+		"fillform",
+		"redirects",
+		"axtree",
+		"domxss",
+		"waitinteraction",
+		"cspnonce",
+		"webvitals",
+		"metadata",
+		"clickjacking",
+		"eventhandlers",
+		"wasm",
+		"trackers",
+		"storage",
+		"websocket",
+		"corsprobe",
+		"prototypepollution",
+		"permissionspolicy",
+		"sourcemaps",
+		"prefilledfields",
+		"insecureforms",
+		"domclobbering",
 	}
 
 	USERAGENTUserAgentHolderDoc.Type = "userAgent.UserAgentHolder"
@@ -1325,6 +1597,42 @@ func init() {
 			Key:   "not_after",
 			Value: "Timestamp after which the remote cert expires",
 		},
+		{
+			Key:   "cert_days_until_expiry",
+			Value: "Number of days until the remote cert expires (negative if expired)",
+		},
+		{
+			Key:   "cert_is_expired",
+			Value: "Whether the remote cert has expired",
+		},
+		{
+			Key:   "session_resumed",
+			Value: "Whether the server resumed a previous session (session_resumption)",
+		},
+		{
+			Key:   "session_resumption_mechanism",
+			Value: "Mechanism of the resumed session, ticket or session-id (session_resumption)",
+		},
+		{
+			Key:   "session_ticket_issued",
+			Value: "Whether the server issued a session ticket (session_resumption)",
+		},
+		{
+			Key:   "session_ticket_lifetime",
+			Value: "Lifetime hint of the tls 1.2 session ticket in seconds (session_resumption)",
+		},
+		{
+			Key:   "session_id_issued",
+			Value: "Whether the server issued a tls 1.2 session id (session_resumption)",
+		},
+		{
+			Key:   "ja3",
+			Value: "JA3 fingerprint of the tls client hello sent (only if a ja3 is configured)",
+		},
+		{
+			Key:   "ja3_hash",
+			Value: "MD5 hash of the JA3 fingerprint of the tls client hello sent",
+		},
 		{
 			Key:   "host",
 			Value: "Host is the input to the template",
@@ -1334,7 +1642,7 @@ func init() {
 			Value: "Matched is the input which was matched upon",
 		},
 	}
-	SSLRequestDoc.Fields = make([]encoder.Doc, 5)
+	SSLRequestDoc.Fields = make([]encoder.Doc, 7)
 	SSLRequestDoc.Fields[0].Name = "address"
 	SSLRequestDoc.Fields[0].Type = "string"
 	SSLRequestDoc.Fields[0].Note = ""
@@ -1374,6 +1682,18 @@ func init() {
 	SSLRequestDoc.Fields[4].Note = ""
 	SSLRequestDoc.Fields[4].Description = "description: |\n   Tls Scan Mode - auto if not specified\n values:\n   - \"ctls\"\n   - \"ztls\"\n   - \"auto\"\n	 - \"openssl\" # reverts to \"auto\" is openssl is not installed"
 	SSLRequestDoc.Fields[4].Comments[encoder.LineComment] = " description: |"
+	SSLRequestDoc.Fields[5].Name = "session_resumption"
+	SSLRequestDoc.Fields[5].Type = "bool"
+	SSLRequestDoc.Fields[5].Note = ""
+	SSLRequestDoc.Fields[5].Description = "SessionResumption probes whether the server resumes tls sessions.\n\nAdditional handshakes are performed with the session ticket or the\nsession id issued on the first one, setting session_resumed along with\nthe session_resumption_mechanism (ticket or session-id), session_ticket_issued,\nsession_ticket_lifetime (in seconds, tls 1.2 tickets only) and session_id_issued."
+	SSLRequestDoc.Fields[5].Comments[encoder.LineComment] = "SessionResumption probes whether the server resumes tls sessions."
+	SSLRequestDoc.Fields[6].Name = "ja3"
+	SSLRequestDoc.Fields[6].Type = "string"
+	SSLRequestDoc.Fields[6].Note = ""
+	SSLRequestDoc.Fields[6].Description = "JA3 is the ja3 fingerprint to use for the client hello of the handshake.\n\nOverrides the global ja3 option. The handshake is then made with the spoofed\nclient hello instead of the scan mode, and the ja3 and ja3_hash of the client\nhello sent are available in the output."
+	SSLRequestDoc.Fields[6].Comments[encoder.LineComment] = "JA3 is the ja3 fingerprint to use for the client hello of the handshake."
+
+	SSLRequestDoc.Fields[6].AddExample("", "771,4865-4866-4867-49195-49199,0-23-65281-10-11-35-16-5-13-18-51-45-43-27-21,29-23-24,0")
 
 	WEBSOCKETRequestDoc.Type = "websocket.Request"
 	WEBSOCKETRequestDoc.Comments[encoder.LineComment] = " Request is a request for the Websocket protocol"
@@ -1409,8 +1729,16 @@ func init() {
 			Key:   "matched",
 			Value: "Matched is the input which was matched upon",
 		},
+		{
+			Key:   "frames",
+			Value: "Frames is the list of text and binary frames received from the server",
+		},
+		{
+			Key:   "subprotocol",
+			Value: "Subprotocol is the subprotocol selected by the server during the handshake",
+		},
 	}
-	WEBSOCKETRequestDoc.Fields = make([]encoder.Doc, 5)
+	WEBSOCKETRequestDoc.Fields = make([]encoder.Doc, 8)
 	WEBSOCKETRequestDoc.Fields[0].Name = "address"
 	WEBSOCKETRequestDoc.Fields[0].Type = "string"
 	WEBSOCKETRequestDoc.Fields[0].Note = ""
@@ -1426,16 +1754,37 @@ func init() {
 	WEBSOCKETRequestDoc.Fields[2].Note = ""
 	WEBSOCKETRequestDoc.Fields[2].Description = "Headers contains headers for the request."
 	WEBSOCKETRequestDoc.Fields[2].Comments[encoder.LineComment] = "Headers contains headers for the request."
-	WEBSOCKETRequestDoc.Fields[3].Name = "attack"
-	WEBSOCKETRequestDoc.Fields[3].Type = "generators.AttackTypeHolder"
+	WEBSOCKETRequestDoc.Fields[3].Name = "subprotocols"
+	WEBSOCKETRequestDoc.Fields[3].Type = "[]string"
 	WEBSOCKETRequestDoc.Fields[3].Note = ""
-	WEBSOCKETRequestDoc.Fields[3].Description = "Attack is the type of payload combinations to perform.\n\nSniper is each payload once, pitchfork combines multiple payload sets and clusterbomb generates\npermutations and combinations for all payloads."
-	WEBSOCKETRequestDoc.Fields[3].Comments[encoder.LineComment] = "Attack is the type of payload combinations to perform."
-	WEBSOCKETRequestDoc.Fields[4].Name = "payloads"
-	WEBSOCKETRequestDoc.Fields[4].Type = "map[string]interface{}"
+	WEBSOCKETRequestDoc.Fields[3].Description = "Subprotocols contains the subprotocols requested during the handshake.\n\nThe subprotocol selected by the server is available in the subprotocol part."
+	WEBSOCKETRequestDoc.Fields[3].Comments[encoder.LineComment] = "Subprotocols contains the subprotocols requested during the handshake."
+
+	WEBSOCKETRequestDoc.Fields[3].AddExample("", []string{"graphql-transport-ws", "graphql-ws"})
+	WEBSOCKETRequestDoc.Fields[4].Name = "read-frames"
+	WEBSOCKETRequestDoc.Fields[4].Type = "int"
 	WEBSOCKETRequestDoc.Fields[4].Note = ""
-	WEBSOCKETRequestDoc.Fields[4].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time."
-	WEBSOCKETRequestDoc.Fields[4].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
+	WEBSOCKETRequestDoc.Fields[4].Description = "ReadFrames is the maximum number of frames read after each input.\n\nWhen no inputs are specified, frames are read after the connection is established.\nReading stops early when no frame is received within the read timeout\nor the server closes the connection.\n\nDefault value for read-frames is 1."
+	WEBSOCKETRequestDoc.Fields[4].Comments[encoder.LineComment] = "ReadFrames is the maximum number of frames read after each input."
+
+	WEBSOCKETRequestDoc.Fields[4].AddExample("", 5)
+	WEBSOCKETRequestDoc.Fields[5].Name = "read-timeout"
+	WEBSOCKETRequestDoc.Fields[5].Type = "int"
+	WEBSOCKETRequestDoc.Fields[5].Note = ""
+	WEBSOCKETRequestDoc.Fields[5].Description = "ReadTimeout is the number of seconds to wait for each frame.\n\nDefault value for read-timeout is the request timeout."
+	WEBSOCKETRequestDoc.Fields[5].Comments[encoder.LineComment] = "ReadTimeout is the number of seconds to wait for each frame."
+
+	WEBSOCKETRequestDoc.Fields[5].AddExample("", 2)
+	WEBSOCKETRequestDoc.Fields[6].Name = "attack"
+	WEBSOCKETRequestDoc.Fields[6].Type = "generators.AttackTypeHolder"
+	WEBSOCKETRequestDoc.Fields[6].Note = ""
+	WEBSOCKETRequestDoc.Fields[6].Description = "Attack is the type of payload combinations to perform.\n\nSniper is each payload once, pitchfork combines multiple payload sets and clusterbomb generates\npermutations and combinations for all payloads."
+	WEBSOCKETRequestDoc.Fields[6].Comments[encoder.LineComment] = "Attack is the type of payload combinations to perform."
+	WEBSOCKETRequestDoc.Fields[7].Name = "payloads"
+	WEBSOCKETRequestDoc.Fields[7].Type = "map[string]interface{}"
+	WEBSOCKETRequestDoc.Fields[7].Note = ""
+	WEBSOCKETRequestDoc.Fields[7].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time.\n\nPayloads can also be generated lazily from a map naming a value generator:\nrange (start, end, step, format), date (start, end, step, format) or\ncharset (charset, min, max), bounded by an optional limit of values."
+	WEBSOCKETRequestDoc.Fields[7].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
 
 	WEBSOCKETInputDoc.Type = "websocket.Input"
 	WEBSOCKETInputDoc.Comments[encoder.LineComment] = ""
@@ -1485,6 +1834,301 @@ func init() {
 	WHOISRequestDoc.Fields[1].Description = "description: |\n 	 Optional WHOIS server URL.\n\n 	 If present, specifies the WHOIS server to execute the Request on.\n   Otherwise, nil enables bootstrapping"
 	WHOISRequestDoc.Fields[1].Comments[encoder.LineComment] = " description: |"
 
+	ICMPRequestDoc.Type = "icmp.Request"
+	ICMPRequestDoc.Comments[encoder.LineComment] = " Request is a request for the ICMP protocol"
+	ICMPRequestDoc.Description = "Request is a request for the ICMP protocol"
+	ICMPRequestDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "Template",
+			FieldName: "icmp",
+		},
+	}
+	ICMPRequestDoc.PartDefinitions = []encoder.KeyValue{
+		{
+			Key:   "type",
+			Value: "Type is the type of request made",
+		},
+		{
+			Key:   "host",
+			Value: "Host is the input to the template",
+		},
+		{
+			Key:   "matched",
+			Value: "Matched is the host which was pinged",
+		},
+		{
+			Key:   "ip",
+			Value: "IP is the address the echo requests were sent to",
+		},
+		{
+			Key:   "reachable",
+			Value: "Reachable is true if any echo reply was received",
+		},
+		{
+			Key:   "rtt",
+			Value: "RTT is the average round trip time in milliseconds",
+		},
+		{
+			Key:   "min_rtt",
+			Value: "Min RTT is the minimum round trip time in milliseconds",
+		},
+		{
+			Key:   "max_rtt",
+			Value: "Max RTT is the maximum round trip time in milliseconds",
+		},
+		{
+			Key:   "ttl",
+			Value: "TTL is the ttl (or hop limit) of the echo reply",
+		},
+		{
+			Key:   "sent",
+			Value: "Sent is the number of echo requests sent",
+		},
+		{
+			Key:   "received",
+			Value: "Received is the number of echo replies received",
+		},
+		{
+			Key:   "packet_loss",
+			Value: "Packet Loss is the percentage of echo requests without reply",
+		},
+		{
+			Key:   "privileged",
+			Value: "Privileged is true if raw sockets were used for the requests",
+		},
+		{
+			Key:   "response",
+			Value: "Ping style summary of the echo replies",
+		},
+	}
+	ICMPRequestDoc.Fields = make([]encoder.Doc, 2)
+	ICMPRequestDoc.Fields[0].Name = "host"
+	ICMPRequestDoc.Fields[0].Type = "string"
+	ICMPRequestDoc.Fields[0].Note = ""
+	ICMPRequestDoc.Fields[0].Description = "Host contains the host to send echo requests to.\n\nDefaults to {{Hostname}} if not specified."
+	ICMPRequestDoc.Fields[0].Comments[encoder.LineComment] = "Host contains the host to send echo requests to."
+
+	ICMPRequestDoc.Fields[0].AddExample("", "{{Hostname}}")
+	ICMPRequestDoc.Fields[1].Name = "count"
+	ICMPRequestDoc.Fields[1].Type = "int"
+	ICMPRequestDoc.Fields[1].Note = ""
+	ICMPRequestDoc.Fields[1].Description = "Count is the number of echo requests to send.\n\nDefaults to 1 if not specified."
+	ICMPRequestDoc.Fields[1].Comments[encoder.LineComment] = "Count is the number of echo requests to send."
+
+	ICMPRequestDoc.Fields[1].AddExample("", 3)
+
+	MATCHERSMatcherDoc.Type = "matchers.Matcher"
+	MATCHERSMatcherDoc.Comments[encoder.LineComment] = " Matcher is used to match a part in the output from a protocol."
+	MATCHERSMatcherDoc.Description = "Matcher is used to match a part in the output from a protocol."
+	MATCHERSMatcherDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "Template",
+			FieldName: "flow-matchers",
+		},
+	}
+	MATCHERSMatcherDoc.Fields = make([]encoder.Doc, 23)
+	MATCHERSMatcherDoc.Fields[0].Name = "type"
+	MATCHERSMatcherDoc.Fields[0].Type = "MatcherTypeHolder"
+	MATCHERSMatcherDoc.Fields[0].Note = ""
+	MATCHERSMatcherDoc.Fields[0].Description = "Type is the type of the matcher."
+	MATCHERSMatcherDoc.Fields[0].Comments[encoder.LineComment] = "Type is the type of the matcher."
+	MATCHERSMatcherDoc.Fields[1].Name = "condition"
+	MATCHERSMatcherDoc.Fields[1].Type = "string"
+	MATCHERSMatcherDoc.Fields[1].Note = ""
+	MATCHERSMatcherDoc.Fields[1].Description = "Condition is the optional condition between two matcher variables. By default,\nthe condition is assumed to be OR."
+	MATCHERSMatcherDoc.Fields[1].Comments[encoder.LineComment] = "Condition is the optional condition between two matcher variables. By default,"
+	MATCHERSMatcherDoc.Fields[1].Values = []string{
+		"and",
+		"or",
+	}
+	MATCHERSMatcherDoc.Fields[2].Name = "part"
+	MATCHERSMatcherDoc.Fields[2].Type = "string"
+	MATCHERSMatcherDoc.Fields[2].Note = ""
+	MATCHERSMatcherDoc.Fields[2].Description = "Part is the part of the request response to match data from.\n\nEach protocol exposes a lot of different parts which are well\ndocumented in docs for each request type."
+	MATCHERSMatcherDoc.Fields[2].Comments[encoder.LineComment] = "Part is the part of the request response to match data from."
+
+	MATCHERSMatcherDoc.Fields[2].AddExample("", "body")
+
+	MATCHERSMatcherDoc.Fields[2].AddExample("", "raw")
+	MATCHERSMatcherDoc.Fields[3].Name = "negative"
+	MATCHERSMatcherDoc.Fields[3].Type = "bool"
+	MATCHERSMatcherDoc.Fields[3].Note = ""
+	MATCHERSMatcherDoc.Fields[3].Description = "Negative specifies if the match should be reversed\nIt will only match if the condition is not true."
+	MATCHERSMatcherDoc.Fields[3].Comments[encoder.LineComment] = "Negative specifies if the match should be reversed"
+	MATCHERSMatcherDoc.Fields[4].Name = "name"
+	MATCHERSMatcherDoc.Fields[4].Type = "string"
+	MATCHERSMatcherDoc.Fields[4].Note = ""
+	MATCHERSMatcherDoc.Fields[4].Description = "Name of the matcher. Name should be lowercase and must not contain\nspaces or underscores (_)."
+	MATCHERSMatcherDoc.Fields[4].Comments[encoder.LineComment] = "Name of the matcher. Name should be lowercase and must not contain"
+
+	MATCHERSMatcherDoc.Fields[4].AddExample("", "cookie-matcher")
+	MATCHERSMatcherDoc.Fields[5].Name = "status"
+	MATCHERSMatcherDoc.Fields[5].Type = "[]int"
+	MATCHERSMatcherDoc.Fields[5].Note = ""
+	MATCHERSMatcherDoc.Fields[5].Description = "Status are the acceptable status codes for the response."
+	MATCHERSMatcherDoc.Fields[5].Comments[encoder.LineComment] = "Status are the acceptable status codes for the response."
+
+	MATCHERSMatcherDoc.Fields[5].AddExample("", []int{200, 302})
+	MATCHERSMatcherDoc.Fields[6].Name = "size"
+	MATCHERSMatcherDoc.Fields[6].Type = "[]int"
+	MATCHERSMatcherDoc.Fields[6].Note = ""
+	MATCHERSMatcherDoc.Fields[6].Description = "Size is the acceptable size for the response"
+	MATCHERSMatcherDoc.Fields[6].Comments[encoder.LineComment] = "Size is the acceptable size for the response"
+
+	MATCHERSMatcherDoc.Fields[6].AddExample("", []int{3029, 2042})
+	MATCHERSMatcherDoc.Fields[7].Name = "words"
+	MATCHERSMatcherDoc.Fields[7].Type = "[]string"
+	MATCHERSMatcherDoc.Fields[7].Note = ""
+	MATCHERSMatcherDoc.Fields[7].Description = "Words contains word patterns required to be present in the response part."
+	MATCHERSMatcherDoc.Fields[7].Comments[encoder.LineComment] = "Words contains word patterns required to be present in the response part."
+
+	MATCHERSMatcherDoc.Fields[7].AddExample("Match for Outlook mail protection domain", []string{"mail.protection.outlook.com"})
+
+	MATCHERSMatcherDoc.Fields[7].AddExample("Match for application/json in response headers", []string{"application/json"})
+	MATCHERSMatcherDoc.Fields[8].Name = "regex"
+	MATCHERSMatcherDoc.Fields[8].Type = "[]string"
+	MATCHERSMatcherDoc.Fields[8].Note = ""
+	MATCHERSMatcherDoc.Fields[8].Description = "Regex contains Regular Expression patterns required to be present in the response part."
+	MATCHERSMatcherDoc.Fields[8].Comments[encoder.LineComment] = "Regex contains Regular Expression patterns required to be present in the response part."
+
+	MATCHERSMatcherDoc.Fields[8].AddExample("Match for Linkerd Service via Regex", []string{`(?mi)^Via\\s*?:.*?linkerd.*$`})
+
+	MATCHERSMatcherDoc.Fields[8].AddExample("Match for Open Redirect via Location header", []string{`(?m)^(?:Location\\s*?:\\s*?)(?:https?://|//)?(?:[a-zA-Z0-9\\-_\\.@]*)example\\.com.*$`})
+	MATCHERSMatcherDoc.Fields[9].Name = "binary"
+	MATCHERSMatcherDoc.Fields[9].Type = "[]string"
+	MATCHERSMatcherDoc.Fields[9].Note = ""
+	MATCHERSMatcherDoc.Fields[9].Description = "Binary are the binary patterns required to be present in the response part."
+	MATCHERSMatcherDoc.Fields[9].Comments[encoder.LineComment] = "Binary are the binary patterns required to be present in the response part."
+
+	MATCHERSMatcherDoc.Fields[9].AddExample("Match for Springboot Heapdump Actuator \"JAVA PROFILE\", \"HPROF\", \"Gunzip magic byte\"", []string{"4a4156412050524f46494c45", "4850524f46", "1f8b080000000000"})
+
+	MATCHERSMatcherDoc.Fields[9].AddExample("Match for 7zip files", []string{"377ABCAF271C"})
+	MATCHERSMatcherDoc.Fields[10].Name = "dsl"
+	MATCHERSMatcherDoc.Fields[10].Type = "[]string"
+	MATCHERSMatcherDoc.Fields[10].Note = ""
+	MATCHERSMatcherDoc.Fields[10].Description = "DSL are the dsl expressions that will be evaluated as part of nuclei matching rules.\nA list of these helper functions are available [here](https://nuclei.projectdiscovery.io/templating-guide/helper-functions/)."
+	MATCHERSMatcherDoc.Fields[10].Comments[encoder.LineComment] = "DSL are the dsl expressions that will be evaluated as part of nuclei matching rules."
+
+	MATCHERSMatcherDoc.Fields[10].AddExample("DSL Matcher for package.json file", []string{"contains(body, 'packages') && contains(tolower(all_headers), 'application/octet-stream') && status_code == 200"})
+
+	MATCHERSMatcherDoc.Fields[10].AddExample("DSL Matcher for missing strict transport security header", []string{"!contains(tolower(all_headers), ''strict-transport-security'')"})
+	MATCHERSMatcherDoc.Fields[11].Name = "schema"
+	MATCHERSMatcherDoc.Fields[11].Type = "string"
+	MATCHERSMatcherDoc.Fields[11].Note = ""
+	MATCHERSMatcherDoc.Fields[11].Description = "Schema is the JSON Schema the response part is validated against.\n\nThe part matches if it is valid json conforming to the schema, or if\nit doesn't conform when invalid is set. References to other schemas\nmust be defined in the schema itself."
+	MATCHERSMatcherDoc.Fields[11].Comments[encoder.LineComment] = "Schema is the JSON Schema the response part is validated against."
+
+	MATCHERSMatcherDoc.Fields[11].AddExample("Match for a user object with a numeric id", "{\"type\": \"object\", \"required\": [\"id\"], \"properties\": {\"id\": {\"type\": \"integer\"}}}")
+	MATCHERSMatcherDoc.Fields[12].Name = "invalid"
+	MATCHERSMatcherDoc.Fields[12].Type = "bool"
+	MATCHERSMatcherDoc.Fields[12].Note = ""
+	MATCHERSMatcherDoc.Fields[12].Description = "Invalid matches the response part if it doesn't conform to the schema.\n\nThe validation errors are reported with the result."
+	MATCHERSMatcherDoc.Fields[12].Comments[encoder.LineComment] = "Invalid matches the response part if it doesn't conform to the schema."
+	MATCHERSMatcherDoc.Fields[13].Name = "baseline"
+	MATCHERSMatcherDoc.Fields[13].Type = "string"
+	MATCHERSMatcherDoc.Fields[13].Note = ""
+	MATCHERSMatcherDoc.Fields[13].Description = "Baseline is the part the response part is compared against by differential matchers.\n\nIt usually is a part of the response of a previous request, referenced\neither by its number with req-condition (e.g. body_1) or by its id (e.g. baseline_body)."
+	MATCHERSMatcherDoc.Fields[13].Comments[encoder.LineComment] = "Baseline is the part the response part is compared against by differential matchers."
+
+	MATCHERSMatcherDoc.Fields[13].AddExample("Compare against the body of the first request", "body_1")
+	MATCHERSMatcherDoc.Fields[14].Name = "max-similarity"
+	MATCHERSMatcherDoc.Fields[14].Type = "float64"
+	MATCHERSMatcherDoc.Fields[14].Note = ""
+	MATCHERSMatcherDoc.Fields[14].Description = "MaxSimilarity matches if the similarity between the part and the baseline is below it.\n\nThe similarity goes from 0 for entirely different contents to 1 for identical ones,\nand is computed as the proportion of words the part and the baseline have in common."
+	MATCHERSMatcherDoc.Fields[14].Comments[encoder.LineComment] = "MaxSimilarity matches if the similarity between the part and the baseline is below it."
+
+	MATCHERSMatcherDoc.Fields[14].AddExample("Match if less than 90% of the words are in common", 0.9)
+	MATCHERSMatcherDoc.Fields[15].Name = "min-length-delta"
+	MATCHERSMatcherDoc.Fields[15].Type = "int"
+	MATCHERSMatcherDoc.Fields[15].Note = ""
+	MATCHERSMatcherDoc.Fields[15].Description = "MinLengthDelta matches if the length of the part differs from the\nlength of the baseline by at least the specified number of bytes."
+	MATCHERSMatcherDoc.Fields[15].Comments[encoder.LineComment] = "MinLengthDelta matches if the length of the part differs from the"
+
+	MATCHERSMatcherDoc.Fields[15].AddExample("Match if the lengths differ by at least 100 bytes", 100)
+	MATCHERSMatcherDoc.Fields[16].Name = "fingerprints"
+	MATCHERSMatcherDoc.Fields[16].Type = "[]string"
+	MATCHERSMatcherDoc.Fields[16].Note = ""
+	MATCHERSMatcherDoc.Fields[16].Description = "Fingerprints are the structural fingerprints of html documents the part is compared against by structure matchers.\n\nA fingerprint only depends on the tag tree of a document, ignoring its text and\nattributes, and is obtained by running a structure extractor on the document."
+	MATCHERSMatcherDoc.Fields[16].Comments[encoder.LineComment] = "Fingerprints are the structural fingerprints of html documents the part is compared against by structure matchers."
+
+	MATCHERSMatcherDoc.Fields[16].AddExample("Compare against the fingerprint of a login page", []string{"1a7b221c03a9054d021b0c6d07be282f062b02e708a52d671db50aea0e72017903a20e174df51d2a0477035a1814065c0ac814a41b12097a04150282123404f303d52a85233b31f711702d06085d1ae018b40250102c1f1b0aad491721060aa629b00bea05b6039b1a8731ad29103d181b1105f0000313211e941d9f16b51a12"})
+	MATCHERSMatcherDoc.Fields[17].Name = "min-similarity"
+	MATCHERSMatcherDoc.Fields[17].Type = "float64"
+	MATCHERSMatcherDoc.Fields[17].Note = ""
+	MATCHERSMatcherDoc.Fields[17].Description = "MinSimilarity matches if the structural similarity between the part and the fingerprints is at least it.\n\nThe similarity goes from 0 for unrelated structures to 1 for identical ones. Default is 0.8."
+	MATCHERSMatcherDoc.Fields[17].Comments[encoder.LineComment] = "MinSimilarity matches if the structural similarity between the part and the fingerprints is at least it."
+
+	MATCHERSMatcherDoc.Fields[17].AddExample("Match if the structures are at least 90% similar", 0.9)
+	MATCHERSMatcherDoc.Fields[18].Name = "encoding"
+	MATCHERSMatcherDoc.Fields[18].Type = "string"
+	MATCHERSMatcherDoc.Fields[18].Note = ""
+	MATCHERSMatcherDoc.Fields[18].Description = "Encoding specifies the encoding for the words field if any."
+	MATCHERSMatcherDoc.Fields[18].Comments[encoder.LineComment] = "Encoding specifies the encoding for the words field if any."
+	MATCHERSMatcherDoc.Fields[18].Values = []string{
+		"hex",
+	}
+	MATCHERSMatcherDoc.Fields[19].Name = "case-insensitive"
+	MATCHERSMatcherDoc.Fields[19].Type = "bool"
+	MATCHERSMatcherDoc.Fields[19].Note = ""
+	MATCHERSMatcherDoc.Fields[19].Description = "CaseInsensitive enables case-insensitive matches. Default is false."
+	MATCHERSMatcherDoc.Fields[19].Comments[encoder.LineComment] = "CaseInsensitive enables case-insensitive matches. Default is false."
+	MATCHERSMatcherDoc.Fields[19].Values = []string{
+		"false",
+		"true",
+	}
+	MATCHERSMatcherDoc.Fields[20].Name = "match-all"
+	MATCHERSMatcherDoc.Fields[20].Type = "bool"
+	MATCHERSMatcherDoc.Fields[20].Note = ""
+	MATCHERSMatcherDoc.Fields[20].Description = "MatchAll enables matching for all matcher values. Default is false."
+	MATCHERSMatcherDoc.Fields[20].Comments[encoder.LineComment] = "MatchAll enables matching for all matcher values. Default is false."
+	MATCHERSMatcherDoc.Fields[20].Values = []string{
+		"false",
+		"true",
+	}
+	MATCHERSMatcherDoc.Fields[21].Name = "min-matches"
+	MATCHERSMatcherDoc.Fields[21].Type = "int"
+	MATCHERSMatcherDoc.Fields[21].Note = ""
+	MATCHERSMatcherDoc.Fields[21].Description = "MinMatches is the minimum number of words or regexes which must match\nfor the matcher to match, ignoring condition."
+	MATCHERSMatcherDoc.Fields[21].Comments[encoder.LineComment] = "MinMatches is the minimum number of words or regexes which must match"
+
+	MATCHERSMatcherDoc.Fields[21].AddExample("Match if at least two of the words are present", 2)
+	MATCHERSMatcherDoc.Fields[22].Name = "auto-decode"
+	MATCHERSMatcherDoc.Fields[22].Type = "bool"
+	MATCHERSMatcherDoc.Fields[22].Note = ""
+	MATCHERSMatcherDoc.Fields[22].Description = "AutoDecode additionally matches the words or regexes against the decoded\ncontent of the base64 and hex encoded substrings of the part.\n\nOnly substrings decoding to printable text are considered."
+	MATCHERSMatcherDoc.Fields[22].Comments[encoder.LineComment] = "AutoDecode additionally matches the words or regexes against the decoded"
+	MATCHERSMatcherDoc.Fields[22].Values = []string{
+		"false",
+		"true",
+	}
+
+	MatcherTypeHolderDoc.Type = "MatcherTypeHolder"
+	MatcherTypeHolderDoc.Comments[encoder.LineComment] = " MatcherTypeHolder is used to hold internal type of the matcher"
+	MatcherTypeHolderDoc.Description = "MatcherTypeHolder is used to hold internal type of the matcher"
+	MatcherTypeHolderDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "matchers.Matcher",
+			FieldName: "type",
+		},
+	}
+	MatcherTypeHolderDoc.Fields = make([]encoder.Doc, 1)
+	MatcherTypeHolderDoc.Fields[0].Name = ""
+	MatcherTypeHolderDoc.Fields[0].Type = "MatcherType"
+	MatcherTypeHolderDoc.Fields[0].Note = ""
+	MatcherTypeHolderDoc.Fields[0].Description = ""
+	MatcherTypeHolderDoc.Fields[0].Comments[encoder.LineComment] = ""
+	MatcherTypeHolderDoc.Fields[0].EnumFields = []string{
+		"word",
+		"regex",
+		"binary",
+		"status",
+		"size",
+		"dsl",
+		"jsonschema",
+		"differential",
+		"structure",
+	}
+
 	HTTPSignatureTypeHolderDoc.Type = "http.SignatureTypeHolder"
 	HTTPSignatureTypeHolderDoc.Comments[encoder.LineComment] = " SignatureTypeHolder is used to hold internal type of the signature"
 	HTTPSignatureTypeHolderDoc.Description = "SignatureTypeHolder is used to hold internal type of the signature"
@@ -1539,6 +2183,9 @@ func GetTemplateDoc() *encoder.FileDoc {
 			&WEBSOCKETRequestDoc,
 			&WEBSOCKETInputDoc,
 			&WHOISRequestDoc,
+			&ICMPRequestDoc,
+			&MATCHERSMatcherDoc,
+			&MatcherTypeHolderDoc,
 			&HTTPSignatureTypeHolderDoc,
 			&VARIABLESVariableDoc,
 		},
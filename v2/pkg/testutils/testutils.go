@@ -77,7 +77,7 @@ type TemplateInfo struct {
 
 // NewMockExecuterOptions creates a new mock executeroptions struct
 func NewMockExecuterOptions(options *types.Options, info *TemplateInfo) *protocols.ExecuterOptions {
-	progressImpl, _ := progress.NewStatsTicker(0, false, false, false, false, 0)
+	progressImpl, _ := progress.NewStatsTicker(0, false, false, false, false, 0, "")
 	executerOpts := &protocols.ExecuterOptions{
 		TemplateID:   info.ID,
 		TemplateInfo: info.Info,
@@ -169,3 +169,7 @@ func (m *MockProgressClient) IncrementErrorsBy(count int64) {}
 // IncrementFailedRequestsBy increments the number of requests counter by count
 // along with errors.
 func (m *MockProgressClient) IncrementFailedRequestsBy(count int64) {}
+
+// IncrementSkippedRequestsBy increments the number of requests counter by count
+// along with skipped requests.
+func (m *MockProgressClient) IncrementSkippedRequestsBy(count int64) {}
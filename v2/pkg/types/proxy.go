@@ -1,5 +1,7 @@
 package types
 
+import "github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/proxypool"
+
 const (
 	HTTP_PROXY_ENV = "HTTP_PROXY"
 )
@@ -9,4 +11,6 @@ var (
 	ProxyURL string
 	// ProxySocksURL is the URL for the proxy socks server
 	ProxySocksURL string
+	// ProxyPool distributes requests across the proxy servers if a pool is used
+	ProxyPool *proxypool.Pool
 )
@@ -24,12 +24,18 @@ type ResumeCfg struct {
 	sync.RWMutex
 	ResumeFrom map[string]*ResumeInfo `json:"resumeFrom"`
 	Current    map[string]*ResumeInfo `json:"-"`
+	// Findings contains the keys of the findings emitted by the scan
+	Findings map[string]struct{} `json:"findings,omitempty"`
+	// emitted contains the keys of the findings emitted before resuming
+	emitted map[string]struct{}
 }
 
 type ResumeInfo struct {
 	sync.RWMutex
 	Completed bool                `json:"completed"`
 	InFlight  map[uint32]struct{} `json:"inFlight"`
+	// Scanned is the number of targets dispatched for the template
+	Scanned   uint32              `json:"scanned,omitempty"`
 	SkipUnder uint32              `json:"-"`
 	Repeat    map[uint32]struct{} `json:"-"`
 	DoAbove   uint32              `json:"-"`
@@ -52,6 +58,7 @@ func (resumeInfo *ResumeInfo) Clone() *ResumeInfo {
 	return &ResumeInfo{
 		Completed: resumeInfo.Completed,
 		InFlight:  inFlight,
+		Scanned:   resumeInfo.Scanned,
 		SkipUnder: resumeInfo.SkipUnder,
 		Repeat:    repeat,
 		DoAbove:   resumeInfo.DoAbove,
@@ -63,6 +70,7 @@ func NewResumeCfg() *ResumeCfg {
 	return &ResumeCfg{
 		ResumeFrom: make(map[string]*ResumeInfo),
 		Current:    make(map[string]*ResumeInfo),
+		Findings:   make(map[string]struct{}),
 	}
 }
 
@@ -80,22 +88,56 @@ func (resumeCfg *ResumeCfg) Clone() *ResumeCfg {
 		current[id] = resumeInfo.Clone()
 	}
 
+	findings := make(map[string]struct{}, len(resumeCfg.Findings))
+	for key := range resumeCfg.Findings {
+		findings[key] = struct{}{}
+	}
+
 	return &ResumeCfg{
 		ResumeFrom: resumeFrom,
 		Current:    current,
+		Findings:   findings,
 	}
 }
 
-// Clone the resume structure
+// AddFinding records the key of a finding emitted by the scan
+func (resumeCfg *ResumeCfg) AddFinding(key string) {
+	resumeCfg.Lock()
+	defer resumeCfg.Unlock()
+
+	if resumeCfg.Findings == nil {
+		resumeCfg.Findings = make(map[string]struct{})
+	}
+	resumeCfg.Findings[key] = struct{}{}
+}
+
+// Emitted returns true if a finding was already emitted before resuming the scan
+func (resumeCfg *ResumeCfg) Emitted(key string) bool {
+	resumeCfg.RLock()
+	defer resumeCfg.RUnlock()
+
+	_, ok := resumeCfg.emitted[key]
+	return ok
+}
+
+// Compile the resume structure
 func (resumeCfg *ResumeCfg) Compile() {
 	resumeCfg.Lock()
 	defer resumeCfg.Unlock()
 
+	resumeCfg.emitted = make(map[string]struct{}, len(resumeCfg.Findings))
+	for key := range resumeCfg.Findings {
+		resumeCfg.emitted[key] = struct{}{}
+	}
 	for _, resumeInfo := range resumeCfg.ResumeFrom {
 		if resumeInfo.Completed && len(resumeInfo.InFlight) > 0 {
 			resumeInfo.InFlight = make(map[uint32]struct{})
 		}
-		min := uint32(math.MaxUint32)
+		// without targets in flight all the dispatched targets were processed
+		min := resumeInfo.Scanned
+		if len(resumeInfo.InFlight) > 0 {
+			min = math.MaxUint32
+		}
 		max := uint32(0)
 		for index := range resumeInfo.InFlight {
 			if index < min {
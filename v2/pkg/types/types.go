@@ -33,6 +33,10 @@ type Options struct {
 	ExcludeMatchers goflags.StringSlice
 	// CustomHeaders is the list of custom global headers to send with each request.
 	CustomHeaders goflags.StringSlice
+	// UserAgents is the list of user agents to pick a random one from for each request.
+	UserAgents goflags.StringSlice
+	// RotateUserAgent rotates the user agent for each headless page.
+	RotateUserAgent bool
 	// Vars is the list of custom global vars
 	Vars goflags.RuntimeMap
 	// Severities filters templates based on their severity and only run the matching ones.
@@ -65,8 +69,16 @@ type Options struct {
 	Targets goflags.StringSlice
 	// TargetsFilePath specifies the targets from a file to scan using templates.
 	TargetsFilePath string
+	// OpenAPISpecFile is an OpenAPI specification file to generate targets from
+	OpenAPISpecFile string
 	// Resume the scan from the state stored in the resume config file
 	Resume string
+	// ResumeInterval is the interval between two checkpoints of the scan progression to the resume file
+	ResumeInterval time.Duration
+	// Incremental is the endpoint inventory of the previous crawl, the scan skipping the endpoints unchanged since
+	Incremental string
+	// IncrementalFields are the fields of the endpoints compared to detect the changed endpoints (status,hash,headers)
+	IncrementalFields goflags.StringSlice
 	// Output is the file to write found results to.
 	Output string
 	// ProxyInternal requests
@@ -75,6 +87,10 @@ type Options struct {
 	ListDslSignatures bool
 	// List of HTTP(s)/SOCKS5 proxy to use (comma separated or file input)
 	Proxy goflags.StringSlice
+	// ProxyPool distributes requests across all the healthy proxies instead of using a single one
+	ProxyPool bool
+	// ProxyCheckInterval is the interval between two health checks of the proxies of the pool
+	ProxyCheckInterval time.Duration
 	// TemplatesDirectory is the directory to use for storing templates
 	NewTemplatesDirectory string
 	// TraceLogFile specifies a file to write with the trace of all requests
@@ -137,12 +153,26 @@ type Options struct {
 	StatsInterval int
 	// MetricsPort is the port to show metrics on
 	MetricsPort int
+	// PrometheusAddress is the address to expose prometheus metrics on
+	PrometheusAddress string
 	// MaxHostError is the maximum number of errors allowed for a host
 	MaxHostError int
 	// TrackError contains additional error messages that count towards the maximum number of errors allowed for a host
 	TrackError goflags.StringSlice
 	// NoHostErrors disables host skipping after maximum number of errors
 	NoHostErrors bool
+	// MaxRequestsPerTemplate is the maximum number of requests a template can send to a single host
+	MaxRequestsPerTemplate int
+	// RetryBackoff is the maximum number of retries with exponential backoff for throttled http requests
+	RetryBackoff int
+	// RetryBackoffMax is the maximum time to wait before retrying a throttled http request
+	RetryBackoffMax time.Duration
+	// RespectRateHeaders delays the requests to a host as advertised by its rate limit response headers
+	RespectRateHeaders bool
+	// BreakerThreshold is the number of consecutive http failures after which a host is temporarily skipped
+	BreakerThreshold int
+	// BreakerCooldown is the time a host is skipped for once the breaker threshold is reached
+	BreakerCooldown time.Duration
 	// BulkSize is the of targets analyzed in parallel for each template
 	BulkSize int
 	// TemplateThreads is the number of templates executed in parallel
@@ -151,8 +181,16 @@ type Options struct {
 	HeadlessBulkSize int
 	// HeadlessTemplateThreads is the number of headless templates executed in parallel
 	HeadlessTemplateThreads int
+	// ProtocolConcurrency is the maximum number of templates of a protocol executed in parallel (protocol=limit)
+	ProtocolConcurrency goflags.StringSlice
 	// Timeout is the seconds to wait for a response from the server.
 	Timeout int
+	// DialTimeout is the time to wait for connections to be established (0 = Timeout)
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout is the time to wait for tls handshakes to complete (0 = Timeout)
+	TLSHandshakeTimeout time.Duration
+	// ResponseReadTimeout is the time to wait for the response after sending a request (0 = Timeout)
+	ResponseReadTimeout time.Duration
 	// Retries is the number of times to retry the request
 	Retries int
 	// Rate-Limit is the maximum number of requests per specified target
@@ -161,6 +199,18 @@ type Options struct {
 	RateLimitMinute int
 	// PageTimeout is the maximum time to wait for a page in seconds
 	PageTimeout int
+	// HeadlessMaxPages is the maximum number of pages open concurrently in the browser
+	HeadlessMaxPages int
+	// HeadlessRecyclePages is the number of pages after which the browser is relaunched
+	HeadlessRecyclePages int
+	// HeadlessRequestBodySize is the maximum size of request bodies recorded in headless page history
+	HeadlessRequestBodySize int
+	// HeadlessRecord is the directory to record headless sessions to
+	HeadlessRecord string
+	// HeadlessReplay is the directory to replay recorded headless sessions from
+	HeadlessReplay string
+	// HeadlessClustering clusters the headless templates with identical observation-only steps to share their pages
+	HeadlessClustering bool
 	// InteractionsCacheSize is the number of interaction-url->req to keep in cache at a time.
 	InteractionsCacheSize int
 	// InteractionsPollDuration is the number of seconds to wait before each interaction poll
@@ -171,6 +221,8 @@ type Options struct {
 	// InteractionsCoolDownPeriod is additional seconds to wait for interactions after closing
 	// of the poller.
 	InteractionsCoolDownPeriod int
+	// InteractionsOutput adds all the interactions correlated with a matched request to the json output
+	InteractionsOutput bool
 	// MaxRedirects is the maximum numbers of redirects to be followed.
 	MaxRedirects int
 	// FollowRedirects enables following redirects for http request module
@@ -201,6 +253,8 @@ type Options struct {
 	ShowActions bool
 	// Metrics enables display of metrics via an http endpoint
 	Metrics bool
+	// Prometheus enables exposing prometheus metrics via an http endpoint
+	Prometheus bool
 	// Debug mode allows debugging request/responses for the engine
 	Debug bool
 	// DebugRequests mode allows debugging request for the engine
@@ -217,6 +271,9 @@ type Options struct {
 	Silent bool
 	// Validate validates the templates passed to nuclei.
 	Validate bool
+	// ResponseFixture is a file with a raw http response the http templates
+	// are run against instead of the targets.
+	ResponseFixture string
 	// NoStrictSyntax disables strict syntax check on nuclei templates (allows custom key-value pairs).
 	NoStrictSyntax bool
 	// Verbose flag indicates whether to show verbose output or not
@@ -268,6 +325,34 @@ type Options struct {
 	EnvironmentVariables bool
 	// MatcherStatus displays optional status for the failed matches as well
 	MatcherStatus bool
+	// DedupeFindings suppresses repeated identical findings of a run and reports their count
+	DedupeFindings bool
+	// DedupeIncludeHost includes the host in the key used to dedupe identical findings
+	DedupeIncludeHost bool
+	// DedupeFullOutput is the file to write all findings to when deduplication is enabled
+	DedupeFullOutput string
+	// DiffResults are the previous and current result files to output the added and removed findings of
+	DiffResults goflags.StringSlice
+	// OutputTemplate is the go template used to format each finding on a single line
+	OutputTemplate string
+	// WebhookURL is the url of the webhook the findings are posted to as they are found
+	WebhookURL string
+	// WebhookHeaders are the headers sent with the webhook requests in header:value format
+	WebhookHeaders goflags.StringSlice
+	// WebhookTemplate is the go template used to format the findings posted to the webhook
+	WebhookTemplate string
+	// SignKey is the file of the hmac secret or ed25519 key used to sign and verify the output file
+	SignKey string
+	// VerifyOutput is the output file to verify against its detached signature
+	VerifyOutput string
+	// Inventory is the file to write the inventory of the assets detected per host to
+	Inventory string
+	// InventoryCategories are the categories of the findings aggregated in the inventory
+	InventoryCategories goflags.StringSlice
+	// OutputBatchSize is the maximum number of findings sent to the webhook per flush interval, 0 to disable throttling
+	OutputBatchSize int
+	// OutputFlushInterval is the interval the throttled findings are sent to the webhook at, 1s if 0
+	OutputFlushInterval time.Duration
 	// ClientCertFile client certificate file (PEM-encoded) used for authenticating against scanned hosts
 	ClientCertFile string
 	// ClientKeyFile client key file (PEM-encoded) used for authenticating against scanned hosts
@@ -290,6 +375,8 @@ type Options struct {
 	DisableRedirects bool
 	// SNI custom hostname
 	SNI string
+	// JA3 is the ja3 fingerprint used for the tls client hello of http, network and ssl requests
+	JA3 string
 	// Interface to use for network scan
 	Interface string
 	// SourceIP sets custom source IP address for network requests
@@ -300,6 +387,10 @@ type Options struct {
 	ResponseReadSize int
 	// ResponseSaveSize is the maximum size of response to save
 	ResponseSaveSize int
+	// MaxBodySize is the maximum size of response bodies kept for matching after decompression (0 = unlimited)
+	MaxBodySize int
+	// TranscodeResponse transcodes the response bodies in legacy charsets to utf-8 before matching
+	TranscodeResponse bool
 	// Health Check
 	HealthCheck bool
 	// Time to wait between each input read operation before closing the stream
@@ -356,6 +447,11 @@ type Options struct {
 	AzureServiceURL string
 	// Scan Strategy (auto,hosts-spray,templates-spray)
 	ScanStrategy string
+	// Seed makes the scan order reproducible (0 = disabled). It shuffles the execution order of the
+	// templates and the iteration order of the targets with the seed, and seeds the rand_char, rand_base,
+	// rand_int and rand_text_* dsl helpers. Payloads are always iterated in their defined order, and the
+	// order of the requests is only the same across runs without concurrency (-c 1 -bs 1).
+	Seed int
 	// Fuzzing Type overrides template level fuzzing-type configuration
 	FuzzingType string
 	// Fuzzing Mode overrides template level fuzzing-mode configuration
@@ -377,6 +473,38 @@ func (options *Options) ShouldFollowHTTPRedirects() bool {
 	return options.FollowRedirects || options.FollowHostRedirects
 }
 
+// GetDialTimeout returns the time to wait for connections to be established
+func (options *Options) GetDialTimeout() time.Duration {
+	return options.timeoutOrDefault(options.DialTimeout)
+}
+
+// GetTLSHandshakeTimeout returns the time to wait for tls handshakes to complete
+func (options *Options) GetTLSHandshakeTimeout() time.Duration {
+	return options.timeoutOrDefault(options.TLSHandshakeTimeout)
+}
+
+// GetResponseReadTimeout returns the time to wait for the response after sending a request
+func (options *Options) GetResponseReadTimeout() time.Duration {
+	return options.timeoutOrDefault(options.ResponseReadTimeout)
+}
+
+// GetConnectTimeout returns the time to wait for connections to be established
+// including their tls handshake, the request timeout if neither is set.
+func (options *Options) GetConnectTimeout() time.Duration {
+	if options.DialTimeout <= 0 && options.TLSHandshakeTimeout <= 0 {
+		return time.Duration(options.Timeout) * time.Second
+	}
+	return options.GetDialTimeout() + options.GetTLSHandshakeTimeout()
+}
+
+// timeoutOrDefault returns the timeout if set or the request timeout otherwise
+func (options *Options) timeoutOrDefault(timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	return time.Duration(options.Timeout) * time.Second
+}
+
 // DefaultOptions returns default options for nuclei
 func DefaultOptions() *Options {
 	return &Options{
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// SplitHostPort splits an address into its host and port, the port being
+// empty if the address has none. Unlike net.SplitHostPort, addresses without
+// port are accepted, the brackets of ipv6 hosts are always removed and the
+// url escaped zones of scoped ipv6 hosts are unescaped.
+func SplitHostPort(address string) (host, port string) {
+	var err error
+	if host, port, err = net.SplitHostPort(address); err != nil {
+		host, port = address, ""
+		if strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]") {
+			host = address[1 : len(address)-1]
+		}
+	}
+	return unescapeZone(host), port
+}
+
+// JoinHostPort joins a host, which may already be bracketed, and a port.
+// Without port the host is returned, bracketed if it's an ipv6 address.
+func JoinHostPort(host, port string) string {
+	host = unescapeZone(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+	if port == "" {
+		return BracketIPv6(host)
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// BracketIPv6 brackets a bare ipv6 address so it can be used as the host
+// of an url or address. Other values are returned unchanged.
+func BracketIPv6(value string) string {
+	if IsIPv6(value) {
+		return "[" + value + "]"
+	}
+	return value
+}
+
+// NormalizeIPv6Input brackets a bare ipv6 address and escapes the zone of scoped
+// ipv6 hosts, e.g. fe80::1%eth0 or https://[fe80::1%eth0]:8443/, as required
+// to parse the input as an url. Other inputs are returned unchanged.
+func NormalizeIPv6Input(input string) string {
+	input = BracketIPv6(input)
+
+	var scheme string
+	if i := strings.Index(input, "://"); i != -1 {
+		scheme, input = input[:i+3], input[i+3:]
+	}
+	end := strings.Index(input, "]")
+	if !strings.HasPrefix(input, "[") || end == -1 {
+		return scheme + input
+	}
+	host := input[1:end]
+	if i := strings.Index(host, "%"); i != -1 && !strings.HasPrefix(host[i:], "%25") {
+		host = host[:i] + "%25" + host[i+1:]
+	}
+	return scheme + "[" + host + input[end:]
+}
+
+// unescapeZone unescapes the url escaped zone of a scoped ipv6 address
+func unescapeZone(host string) string {
+	if unescaped := strings.Replace(host, "%25", "%", 1); unescaped != host && IsIPv6(unescaped) {
+		return unescaped
+	}
+	return host
+}
+
+// IsIPv6 returns true if the value is an unbracketed ipv6 address, with an optional zone
+func IsIPv6(value string) bool {
+	addr, err := netip.ParseAddr(value)
+	return err == nil && addr.Is6()
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitHostPort(t *testing.T) {
+	tests := []struct {
+		address, host, port string
+	}{
+		{"example.com:443", "example.com", "443"},
+		{"example.com", "example.com", ""},
+		{"127.0.0.1:80", "127.0.0.1", "80"},
+		{"[::1]:8443", "::1", "8443"},
+		{"[::1]", "::1", ""},
+		{"::1", "::1", ""},
+		{"2001:db8::1", "2001:db8::1", ""},
+		{"[fe80::1%eth0]:8443", "fe80::1%eth0", "8443"},
+		{"[fe80::1%25eth0]:8443", "fe80::1%eth0", "8443"},
+		{"[fe80::1%25eth0]", "fe80::1%eth0", ""},
+	}
+	for _, test := range tests {
+		host, port := SplitHostPort(test.address)
+		require.Equal(t, test.host, host, "wrong host for %s", test.address)
+		require.Equal(t, test.port, port, "wrong port for %s", test.address)
+	}
+}
+
+func TestJoinHostPort(t *testing.T) {
+	require.Equal(t, "example.com:443", JoinHostPort("example.com", "443"))
+	require.Equal(t, "example.com", JoinHostPort("example.com", ""))
+	require.Equal(t, "[::1]:8443", JoinHostPort("::1", "8443"))
+	require.Equal(t, "[::1]:8443", JoinHostPort("[::1]", "8443"), "bracketed host bracketed again")
+	require.Equal(t, "[::1]", JoinHostPort("::1", ""), "ipv6 host not bracketed without port")
+	require.Equal(t, "[fe80::1%eth0]:80", JoinHostPort("[fe80::1%25eth0]", "80"), "zone not unescaped")
+}
+
+func TestNormalizeIPv6Input(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{"example.com", "example.com"},
+		{"https://example.com:8443/path", "https://example.com:8443/path"},
+		{"127.0.0.1", "127.0.0.1"},
+		{"::1", "[::1]"},
+		{"2001:db8::1", "[2001:db8::1]"},
+		{"::ffff:192.0.2.1", "[::ffff:192.0.2.1]"},
+		{"[::1]:8443", "[::1]:8443"},
+		{"https://[::1]:8443/path", "https://[::1]:8443/path"},
+		{"fe80::1%eth0", "[fe80::1%25eth0]"},
+		{"[fe80::1%eth0]:8443", "[fe80::1%25eth0]:8443"},
+		{"https://[fe80::1%eth0]:8443/", "https://[fe80::1%25eth0]:8443/"},
+		{"https://[fe80::1%25eth0]:8443/", "https://[fe80::1%25eth0]:8443/"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, NormalizeIPv6Input(test.input), "wrong normalized input for %s", test.input)
+	}
+}